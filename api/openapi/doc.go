@@ -0,0 +1,10 @@
+// Package openapi holds the OpenAPI 3 document of the task queue's HTTP
+// API.
+//
+// The document is maintained by hand in openapi.json next to this file and
+// embedded in the binary. The API serves it at GET /v1/openapi.json and
+// renders it at /v1/docs when docs are enabled. A test of the api package
+// compares Operations with the routes the handler registers, so an
+// endpoint added without documenting it, or documented without a handler,
+// fails the build.
+package openapi
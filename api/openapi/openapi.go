@@ -0,0 +1,49 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"task-queue/pkg/errors"
+)
+
+// spec is the OpenAPI document
+//
+//go:embed openapi.json
+var spec []byte
+
+// methods are the operations of an OpenAPI path item
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Document returns the OpenAPI document as JSON
+func Document() []byte {
+	return spec
+}
+
+// Operations returns the documented operations as ServeMux patterns, such
+// as "GET /v1/jobs/{id}", sorted
+func Operations() ([]string, error) {
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, errors.Wrap(err, "invalid OpenAPI document").
+			WithCode(errors.CodeSerialization).
+			WithOp("openapi.Operations")
+	}
+
+	var operations []string
+	for path, item := range doc.Paths {
+		for _, method := range methods {
+			if _, ok := item[method]; ok {
+				operations = append(operations, strings.ToUpper(method)+" "+path)
+			}
+		}
+	}
+
+	sort.Strings(operations)
+	return operations, nil
+}
@@ -0,0 +1,1073 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: taskqueue/v1/jobs.proto
+
+package taskqueuev1
+
+import (
+	status "google.golang.org/genproto/googleapis/rpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// JobStatus is the state of a job
+type JobStatus int32
+
+const (
+	JobStatus_JOB_STATUS_UNSPECIFIED JobStatus = 0
+	JobStatus_JOB_STATUS_PENDING     JobStatus = 1
+	JobStatus_JOB_STATUS_RUNNING     JobStatus = 2
+	JobStatus_JOB_STATUS_COMPLETED   JobStatus = 3
+	JobStatus_JOB_STATUS_FAILED      JobStatus = 4
+	JobStatus_JOB_STATUS_RETRYING    JobStatus = 5
+	JobStatus_JOB_STATUS_DEAD        JobStatus = 6
+	JobStatus_JOB_STATUS_CANCELLED   JobStatus = 7
+)
+
+// Enum value maps for JobStatus.
+var (
+	JobStatus_name = map[int32]string{
+		0: "JOB_STATUS_UNSPECIFIED",
+		1: "JOB_STATUS_PENDING",
+		2: "JOB_STATUS_RUNNING",
+		3: "JOB_STATUS_COMPLETED",
+		4: "JOB_STATUS_FAILED",
+		5: "JOB_STATUS_RETRYING",
+		6: "JOB_STATUS_DEAD",
+		7: "JOB_STATUS_CANCELLED",
+	}
+	JobStatus_value = map[string]int32{
+		"JOB_STATUS_UNSPECIFIED": 0,
+		"JOB_STATUS_PENDING":     1,
+		"JOB_STATUS_RUNNING":     2,
+		"JOB_STATUS_COMPLETED":   3,
+		"JOB_STATUS_FAILED":      4,
+		"JOB_STATUS_RETRYING":    5,
+		"JOB_STATUS_DEAD":        6,
+		"JOB_STATUS_CANCELLED":   7,
+	}
+)
+
+func (x JobStatus) Enum() *JobStatus {
+	p := new(JobStatus)
+	*p = x
+	return p
+}
+
+func (x JobStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JobStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_taskqueue_v1_jobs_proto_enumTypes[0].Descriptor()
+}
+
+func (JobStatus) Type() protoreflect.EnumType {
+	return &file_taskqueue_v1_jobs_proto_enumTypes[0]
+}
+
+func (x JobStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JobStatus.Descriptor instead.
+func (JobStatus) EnumDescriptor() ([]byte, []int) {
+	return file_taskqueue_v1_jobs_proto_rawDescGZIP(), []int{0}
+}
+
+// JobPriority is the priority of a job. Unspecified submits at low
+// priority, like omitting the priority over HTTP.
+type JobPriority int32
+
+const (
+	JobPriority_JOB_PRIORITY_UNSPECIFIED JobPriority = 0
+	JobPriority_JOB_PRIORITY_LOW         JobPriority = 1
+	JobPriority_JOB_PRIORITY_NORMAL      JobPriority = 2
+	JobPriority_JOB_PRIORITY_HIGH        JobPriority = 3
+	JobPriority_JOB_PRIORITY_CRITICAL    JobPriority = 4
+)
+
+// Enum value maps for JobPriority.
+var (
+	JobPriority_name = map[int32]string{
+		0: "JOB_PRIORITY_UNSPECIFIED",
+		1: "JOB_PRIORITY_LOW",
+		2: "JOB_PRIORITY_NORMAL",
+		3: "JOB_PRIORITY_HIGH",
+		4: "JOB_PRIORITY_CRITICAL",
+	}
+	JobPriority_value = map[string]int32{
+		"JOB_PRIORITY_UNSPECIFIED": 0,
+		"JOB_PRIORITY_LOW":         1,
+		"JOB_PRIORITY_NORMAL":      2,
+		"JOB_PRIORITY_HIGH":        3,
+		"JOB_PRIORITY_CRITICAL":    4,
+	}
+)
+
+func (x JobPriority) Enum() *JobPriority {
+	p := new(JobPriority)
+	*p = x
+	return p
+}
+
+func (x JobPriority) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JobPriority) Descriptor() protoreflect.EnumDescriptor {
+	return file_taskqueue_v1_jobs_proto_enumTypes[1].Descriptor()
+}
+
+func (JobPriority) Type() protoreflect.EnumType {
+	return &file_taskqueue_v1_jobs_proto_enumTypes[1]
+}
+
+func (x JobPriority) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JobPriority.Descriptor instead.
+func (JobPriority) EnumDescriptor() ([]byte, []int) {
+	return file_taskqueue_v1_jobs_proto_rawDescGZIP(), []int{1}
+}
+
+// Job is a stored job
+type Job struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type  string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// payload is the job's JSON payload
+	Payload     []byte                 `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	Status      JobStatus              `protobuf:"varint,4,opt,name=status,proto3,enum=taskqueue.v1.JobStatus" json:"status,omitempty"`
+	Priority    JobPriority            `protobuf:"varint,5,opt,name=priority,proto3,enum=taskqueue.v1.JobPriority" json:"priority,omitempty"`
+	MaxRetries  int32                  `protobuf:"varint,6,opt,name=max_retries,json=maxRetries,proto3" json:"max_retries,omitempty"`
+	RetryCount  int32                  `protobuf:"varint,7,opt,name=retry_count,json=retryCount,proto3" json:"retry_count,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt   *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ScheduledAt *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=scheduled_at,json=scheduledAt,proto3" json:"scheduled_at,omitempty"`
+	StartedAt   *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	CompletedAt *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	Error       string                 `protobuf:"bytes,13,opt,name=error,proto3" json:"error,omitempty"`
+	// result is the JSON result of a completed job
+	Result        []byte           `protobuf:"bytes,14,opt,name=result,proto3" json:"result,omitempty"`
+	WorkerId      string           `protobuf:"bytes,15,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Metadata      *structpb.Struct `protobuf:"bytes,16,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Job) Reset() {
+	*x = Job{}
+	mi := &file_taskqueue_v1_jobs_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Job) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Job) ProtoMessage() {}
+
+func (x *Job) ProtoReflect() protoreflect.Message {
+	mi := &file_taskqueue_v1_jobs_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Job.ProtoReflect.Descriptor instead.
+func (*Job) Descriptor() ([]byte, []int) {
+	return file_taskqueue_v1_jobs_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Job) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Job) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Job) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Job) GetStatus() JobStatus {
+	if x != nil {
+		return x.Status
+	}
+	return JobStatus_JOB_STATUS_UNSPECIFIED
+}
+
+func (x *Job) GetPriority() JobPriority {
+	if x != nil {
+		return x.Priority
+	}
+	return JobPriority_JOB_PRIORITY_UNSPECIFIED
+}
+
+func (x *Job) GetMaxRetries() int32 {
+	if x != nil {
+		return x.MaxRetries
+	}
+	return 0
+}
+
+func (x *Job) GetRetryCount() int32 {
+	if x != nil {
+		return x.RetryCount
+	}
+	return 0
+}
+
+func (x *Job) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Job) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Job) GetScheduledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledAt
+	}
+	return nil
+}
+
+func (x *Job) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *Job) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *Job) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Job) GetResult() []byte {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *Job) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *Job) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// SubmitJobRequest describes a job to submit
+type SubmitJobRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// payload is the job's JSON payload
+	Payload  []byte      `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	Priority JobPriority `protobuf:"varint,3,opt,name=priority,proto3,enum=taskqueue.v1.JobPriority" json:"priority,omitempty"`
+	// max_retries defaults to the server's default when unset
+	MaxRetries    *int32                 `protobuf:"varint,4,opt,name=max_retries,json=maxRetries,proto3,oneof" json:"max_retries,omitempty"`
+	ScheduledAt   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=scheduled_at,json=scheduledAt,proto3" json:"scheduled_at,omitempty"`
+	Metadata      *structpb.Struct       `protobuf:"bytes,6,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitJobRequest) Reset() {
+	*x = SubmitJobRequest{}
+	mi := &file_taskqueue_v1_jobs_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitJobRequest) ProtoMessage() {}
+
+func (x *SubmitJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taskqueue_v1_jobs_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitJobRequest.ProtoReflect.Descriptor instead.
+func (*SubmitJobRequest) Descriptor() ([]byte, []int) {
+	return file_taskqueue_v1_jobs_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SubmitJobRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SubmitJobRequest) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *SubmitJobRequest) GetPriority() JobPriority {
+	if x != nil {
+		return x.Priority
+	}
+	return JobPriority_JOB_PRIORITY_UNSPECIFIED
+}
+
+func (x *SubmitJobRequest) GetMaxRetries() int32 {
+	if x != nil && x.MaxRetries != nil {
+		return *x.MaxRetries
+	}
+	return 0
+}
+
+func (x *SubmitJobRequest) GetScheduledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledAt
+	}
+	return nil
+}
+
+func (x *SubmitJobRequest) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// SubmitJobsRequest submits several jobs
+type SubmitJobsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Jobs          []*SubmitJobRequest    `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitJobsRequest) Reset() {
+	*x = SubmitJobsRequest{}
+	mi := &file_taskqueue_v1_jobs_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitJobsRequest) ProtoMessage() {}
+
+func (x *SubmitJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taskqueue_v1_jobs_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitJobsRequest.ProtoReflect.Descriptor instead.
+func (*SubmitJobsRequest) Descriptor() ([]byte, []int) {
+	return file_taskqueue_v1_jobs_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SubmitJobsRequest) GetJobs() []*SubmitJobRequest {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+// SubmitJobResult is the outcome of one job of a SubmitJobsRequest: the
+// submitted job or the error that rejected it
+type SubmitJobResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Job           *Job                   `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	Error         *status.Status         `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitJobResult) Reset() {
+	*x = SubmitJobResult{}
+	mi := &file_taskqueue_v1_jobs_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitJobResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitJobResult) ProtoMessage() {}
+
+func (x *SubmitJobResult) ProtoReflect() protoreflect.Message {
+	mi := &file_taskqueue_v1_jobs_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitJobResult.ProtoReflect.Descriptor instead.
+func (*SubmitJobResult) Descriptor() ([]byte, []int) {
+	return file_taskqueue_v1_jobs_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SubmitJobResult) GetJob() *Job {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+func (x *SubmitJobResult) GetError() *status.Status {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// SubmitJobsResponse holds one result per submitted job, in request order
+type SubmitJobsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*SubmitJobResult     `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitJobsResponse) Reset() {
+	*x = SubmitJobsResponse{}
+	mi := &file_taskqueue_v1_jobs_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitJobsResponse) ProtoMessage() {}
+
+func (x *SubmitJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taskqueue_v1_jobs_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitJobsResponse.ProtoReflect.Descriptor instead.
+func (*SubmitJobsResponse) Descriptor() ([]byte, []int) {
+	return file_taskqueue_v1_jobs_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SubmitJobsResponse) GetResults() []*SubmitJobResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// GetJobRequest names the job to return
+type GetJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobRequest) Reset() {
+	*x = GetJobRequest{}
+	mi := &file_taskqueue_v1_jobs_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobRequest) ProtoMessage() {}
+
+func (x *GetJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taskqueue_v1_jobs_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobRequest.ProtoReflect.Descriptor instead.
+func (*GetJobRequest) Descriptor() ([]byte, []int) {
+	return file_taskqueue_v1_jobs_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetJobRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// ListJobsRequest filters and pages a job listing. Empty fields match every
+// job.
+type ListJobsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Statuses      []JobStatus            `protobuf:"varint,1,rep,packed,name=statuses,proto3,enum=taskqueue.v1.JobStatus" json:"statuses,omitempty"`
+	Types         []string               `protobuf:"bytes,2,rep,name=types,proto3" json:"types,omitempty"`
+	Priorities    []JobPriority          `protobuf:"varint,3,rep,packed,name=priorities,proto3,enum=taskqueue.v1.JobPriority" json:"priorities,omitempty"`
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	// tags must all appear in the job's "tags" metadata list
+	Tags []string `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	// metadata maps metadata keys to the values they must hold
+	Metadata map[string]string `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// query matches a case-insensitive substring of the job's ID, type, or
+	// error
+	Query string `protobuf:"bytes,8,opt,name=query,proto3" json:"query,omitempty"`
+	// sort is created_at or updated_at, prefixed with - for newest first. It
+	// defaults to -created_at.
+	Sort string `protobuf:"bytes,9,opt,name=sort,proto3" json:"sort,omitempty"`
+	// limit defaults to 50 and is capped at 200
+	Limit int32 `protobuf:"varint,10,opt,name=limit,proto3" json:"limit,omitempty"`
+	// cursor is the next_cursor of the previous page
+	Cursor        string `protobuf:"bytes,11,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsRequest) Reset() {
+	*x = ListJobsRequest{}
+	mi := &file_taskqueue_v1_jobs_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsRequest) ProtoMessage() {}
+
+func (x *ListJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taskqueue_v1_jobs_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsRequest.ProtoReflect.Descriptor instead.
+func (*ListJobsRequest) Descriptor() ([]byte, []int) {
+	return file_taskqueue_v1_jobs_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListJobsRequest) GetStatuses() []JobStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *ListJobsRequest) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *ListJobsRequest) GetPriorities() []JobPriority {
+	if x != nil {
+		return x.Priorities
+	}
+	return nil
+}
+
+func (x *ListJobsRequest) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *ListJobsRequest) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *ListJobsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ListJobsRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *ListJobsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListJobsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+// ListJobsResponse is a page of jobs
+type ListJobsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Items []*Job                 `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	// next_cursor is empty on the last page
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	TotalEstimate int64  `protobuf:"varint,3,opt,name=total_estimate,json=totalEstimate,proto3" json:"total_estimate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsResponse) Reset() {
+	*x = ListJobsResponse{}
+	mi := &file_taskqueue_v1_jobs_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsResponse) ProtoMessage() {}
+
+func (x *ListJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taskqueue_v1_jobs_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsResponse.ProtoReflect.Descriptor instead.
+func (*ListJobsResponse) Descriptor() ([]byte, []int) {
+	return file_taskqueue_v1_jobs_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListJobsResponse) GetItems() []*Job {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ListJobsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *ListJobsResponse) GetTotalEstimate() int64 {
+	if x != nil {
+		return x.TotalEstimate
+	}
+	return 0
+}
+
+// CancelJobRequest names the job to cancel
+type CancelJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelJobRequest) Reset() {
+	*x = CancelJobRequest{}
+	mi := &file_taskqueue_v1_jobs_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelJobRequest) ProtoMessage() {}
+
+func (x *CancelJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taskqueue_v1_jobs_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelJobRequest.ProtoReflect.Descriptor instead.
+func (*CancelJobRequest) Descriptor() ([]byte, []int) {
+	return file_taskqueue_v1_jobs_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CancelJobRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// WatchJobRequest names the job to watch
+type WatchJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchJobRequest) Reset() {
+	*x = WatchJobRequest{}
+	mi := &file_taskqueue_v1_jobs_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchJobRequest) ProtoMessage() {}
+
+func (x *WatchJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taskqueue_v1_jobs_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchJobRequest.ProtoReflect.Descriptor instead.
+func (*WatchJobRequest) Descriptor() ([]byte, []int) {
+	return file_taskqueue_v1_jobs_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WatchJobRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+var File_taskqueue_v1_jobs_proto protoreflect.FileDescriptor
+
+const file_taskqueue_v1_jobs_proto_rawDesc = "" +
+	"\n" +
+	"\x17taskqueue/v1/jobs.proto\x12\ftaskqueue.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x17google/rpc/status.proto\"\x9c\x05\n" +
+	"\x03Job\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x18\n" +
+	"\apayload\x18\x03 \x01(\fR\apayload\x12/\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x17.taskqueue.v1.JobStatusR\x06status\x125\n" +
+	"\bpriority\x18\x05 \x01(\x0e2\x19.taskqueue.v1.JobPriorityR\bpriority\x12\x1f\n" +
+	"\vmax_retries\x18\x06 \x01(\x05R\n" +
+	"maxRetries\x12\x1f\n" +
+	"\vretry_count\x18\a \x01(\x05R\n" +
+	"retryCount\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12=\n" +
+	"\fscheduled_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\vscheduledAt\x129\n" +
+	"\n" +
+	"started_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
+	"\fcompleted_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12\x14\n" +
+	"\x05error\x18\r \x01(\tR\x05error\x12\x16\n" +
+	"\x06result\x18\x0e \x01(\fR\x06result\x12\x1b\n" +
+	"\tworker_id\x18\x0f \x01(\tR\bworkerId\x123\n" +
+	"\bmetadata\x18\x10 \x01(\v2\x17.google.protobuf.StructR\bmetadata\"\xa1\x02\n" +
+	"\x10SubmitJobRequest\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x18\n" +
+	"\apayload\x18\x02 \x01(\fR\apayload\x125\n" +
+	"\bpriority\x18\x03 \x01(\x0e2\x19.taskqueue.v1.JobPriorityR\bpriority\x12$\n" +
+	"\vmax_retries\x18\x04 \x01(\x05H\x00R\n" +
+	"maxRetries\x88\x01\x01\x12=\n" +
+	"\fscheduled_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vscheduledAt\x123\n" +
+	"\bmetadata\x18\x06 \x01(\v2\x17.google.protobuf.StructR\bmetadataB\x0e\n" +
+	"\f_max_retries\"G\n" +
+	"\x11SubmitJobsRequest\x122\n" +
+	"\x04jobs\x18\x01 \x03(\v2\x1e.taskqueue.v1.SubmitJobRequestR\x04jobs\"`\n" +
+	"\x0fSubmitJobResult\x12#\n" +
+	"\x03job\x18\x01 \x01(\v2\x11.taskqueue.v1.JobR\x03job\x12(\n" +
+	"\x05error\x18\x02 \x01(\v2\x12.google.rpc.StatusR\x05error\"M\n" +
+	"\x12SubmitJobsResponse\x127\n" +
+	"\aresults\x18\x01 \x03(\v2\x1d.taskqueue.v1.SubmitJobResultR\aresults\"\x1f\n" +
+	"\rGetJobRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x8d\x04\n" +
+	"\x0fListJobsRequest\x123\n" +
+	"\bstatuses\x18\x01 \x03(\x0e2\x17.taskqueue.v1.JobStatusR\bstatuses\x12\x14\n" +
+	"\x05types\x18\x02 \x03(\tR\x05types\x129\n" +
+	"\n" +
+	"priorities\x18\x03 \x03(\x0e2\x19.taskqueue.v1.JobPriorityR\n" +
+	"priorities\x12?\n" +
+	"\rcreated_after\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12\x12\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\x12G\n" +
+	"\bmetadata\x18\a \x03(\v2+.taskqueue.v1.ListJobsRequest.MetadataEntryR\bmetadata\x12\x14\n" +
+	"\x05query\x18\b \x01(\tR\x05query\x12\x12\n" +
+	"\x04sort\x18\t \x01(\tR\x04sort\x12\x14\n" +
+	"\x05limit\x18\n" +
+	" \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\v \x01(\tR\x06cursor\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x83\x01\n" +
+	"\x10ListJobsResponse\x12'\n" +
+	"\x05items\x18\x01 \x03(\v2\x11.taskqueue.v1.JobR\x05items\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\x12%\n" +
+	"\x0etotal_estimate\x18\x03 \x01(\x03R\rtotalEstimate\"\"\n" +
+	"\x10CancelJobRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"!\n" +
+	"\x0fWatchJobRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id*\xd0\x01\n" +
+	"\tJobStatus\x12\x1a\n" +
+	"\x16JOB_STATUS_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12JOB_STATUS_PENDING\x10\x01\x12\x16\n" +
+	"\x12JOB_STATUS_RUNNING\x10\x02\x12\x18\n" +
+	"\x14JOB_STATUS_COMPLETED\x10\x03\x12\x15\n" +
+	"\x11JOB_STATUS_FAILED\x10\x04\x12\x17\n" +
+	"\x13JOB_STATUS_RETRYING\x10\x05\x12\x13\n" +
+	"\x0fJOB_STATUS_DEAD\x10\x06\x12\x18\n" +
+	"\x14JOB_STATUS_CANCELLED\x10\a*\x8c\x01\n" +
+	"\vJobPriority\x12\x1c\n" +
+	"\x18JOB_PRIORITY_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10JOB_PRIORITY_LOW\x10\x01\x12\x17\n" +
+	"\x13JOB_PRIORITY_NORMAL\x10\x02\x12\x15\n" +
+	"\x11JOB_PRIORITY_HIGH\x10\x03\x12\x19\n" +
+	"\x15JOB_PRIORITY_CRITICAL\x10\x042\xa2\x03\n" +
+	"\n" +
+	"JobService\x12>\n" +
+	"\tSubmitJob\x12\x1e.taskqueue.v1.SubmitJobRequest\x1a\x11.taskqueue.v1.Job\x12O\n" +
+	"\n" +
+	"SubmitJobs\x12\x1f.taskqueue.v1.SubmitJobsRequest\x1a .taskqueue.v1.SubmitJobsResponse\x128\n" +
+	"\x06GetJob\x12\x1b.taskqueue.v1.GetJobRequest\x1a\x11.taskqueue.v1.Job\x12I\n" +
+	"\bListJobs\x12\x1d.taskqueue.v1.ListJobsRequest\x1a\x1e.taskqueue.v1.ListJobsResponse\x12>\n" +
+	"\tCancelJob\x12\x1e.taskqueue.v1.CancelJobRequest\x1a\x11.taskqueue.v1.Job\x12>\n" +
+	"\bWatchJob\x12\x1d.taskqueue.v1.WatchJobRequest\x1a\x11.taskqueue.v1.Job0\x01B/Z-task-queue/api/proto/taskqueue/v1;taskqueuev1b\x06proto3"
+
+var (
+	file_taskqueue_v1_jobs_proto_rawDescOnce sync.Once
+	file_taskqueue_v1_jobs_proto_rawDescData []byte
+)
+
+func file_taskqueue_v1_jobs_proto_rawDescGZIP() []byte {
+	file_taskqueue_v1_jobs_proto_rawDescOnce.Do(func() {
+		file_taskqueue_v1_jobs_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_taskqueue_v1_jobs_proto_rawDesc), len(file_taskqueue_v1_jobs_proto_rawDesc)))
+	})
+	return file_taskqueue_v1_jobs_proto_rawDescData
+}
+
+var file_taskqueue_v1_jobs_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_taskqueue_v1_jobs_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_taskqueue_v1_jobs_proto_goTypes = []any{
+	(JobStatus)(0),                // 0: taskqueue.v1.JobStatus
+	(JobPriority)(0),              // 1: taskqueue.v1.JobPriority
+	(*Job)(nil),                   // 2: taskqueue.v1.Job
+	(*SubmitJobRequest)(nil),      // 3: taskqueue.v1.SubmitJobRequest
+	(*SubmitJobsRequest)(nil),     // 4: taskqueue.v1.SubmitJobsRequest
+	(*SubmitJobResult)(nil),       // 5: taskqueue.v1.SubmitJobResult
+	(*SubmitJobsResponse)(nil),    // 6: taskqueue.v1.SubmitJobsResponse
+	(*GetJobRequest)(nil),         // 7: taskqueue.v1.GetJobRequest
+	(*ListJobsRequest)(nil),       // 8: taskqueue.v1.ListJobsRequest
+	(*ListJobsResponse)(nil),      // 9: taskqueue.v1.ListJobsResponse
+	(*CancelJobRequest)(nil),      // 10: taskqueue.v1.CancelJobRequest
+	(*WatchJobRequest)(nil),       // 11: taskqueue.v1.WatchJobRequest
+	nil,                           // 12: taskqueue.v1.ListJobsRequest.MetadataEntry
+	(*timestamppb.Timestamp)(nil), // 13: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),       // 14: google.protobuf.Struct
+	(*status.Status)(nil),         // 15: google.rpc.Status
+}
+var file_taskqueue_v1_jobs_proto_depIdxs = []int32{
+	0,  // 0: taskqueue.v1.Job.status:type_name -> taskqueue.v1.JobStatus
+	1,  // 1: taskqueue.v1.Job.priority:type_name -> taskqueue.v1.JobPriority
+	13, // 2: taskqueue.v1.Job.created_at:type_name -> google.protobuf.Timestamp
+	13, // 3: taskqueue.v1.Job.updated_at:type_name -> google.protobuf.Timestamp
+	13, // 4: taskqueue.v1.Job.scheduled_at:type_name -> google.protobuf.Timestamp
+	13, // 5: taskqueue.v1.Job.started_at:type_name -> google.protobuf.Timestamp
+	13, // 6: taskqueue.v1.Job.completed_at:type_name -> google.protobuf.Timestamp
+	14, // 7: taskqueue.v1.Job.metadata:type_name -> google.protobuf.Struct
+	1,  // 8: taskqueue.v1.SubmitJobRequest.priority:type_name -> taskqueue.v1.JobPriority
+	13, // 9: taskqueue.v1.SubmitJobRequest.scheduled_at:type_name -> google.protobuf.Timestamp
+	14, // 10: taskqueue.v1.SubmitJobRequest.metadata:type_name -> google.protobuf.Struct
+	3,  // 11: taskqueue.v1.SubmitJobsRequest.jobs:type_name -> taskqueue.v1.SubmitJobRequest
+	2,  // 12: taskqueue.v1.SubmitJobResult.job:type_name -> taskqueue.v1.Job
+	15, // 13: taskqueue.v1.SubmitJobResult.error:type_name -> google.rpc.Status
+	5,  // 14: taskqueue.v1.SubmitJobsResponse.results:type_name -> taskqueue.v1.SubmitJobResult
+	0,  // 15: taskqueue.v1.ListJobsRequest.statuses:type_name -> taskqueue.v1.JobStatus
+	1,  // 16: taskqueue.v1.ListJobsRequest.priorities:type_name -> taskqueue.v1.JobPriority
+	13, // 17: taskqueue.v1.ListJobsRequest.created_after:type_name -> google.protobuf.Timestamp
+	13, // 18: taskqueue.v1.ListJobsRequest.created_before:type_name -> google.protobuf.Timestamp
+	12, // 19: taskqueue.v1.ListJobsRequest.metadata:type_name -> taskqueue.v1.ListJobsRequest.MetadataEntry
+	2,  // 20: taskqueue.v1.ListJobsResponse.items:type_name -> taskqueue.v1.Job
+	3,  // 21: taskqueue.v1.JobService.SubmitJob:input_type -> taskqueue.v1.SubmitJobRequest
+	4,  // 22: taskqueue.v1.JobService.SubmitJobs:input_type -> taskqueue.v1.SubmitJobsRequest
+	7,  // 23: taskqueue.v1.JobService.GetJob:input_type -> taskqueue.v1.GetJobRequest
+	8,  // 24: taskqueue.v1.JobService.ListJobs:input_type -> taskqueue.v1.ListJobsRequest
+	10, // 25: taskqueue.v1.JobService.CancelJob:input_type -> taskqueue.v1.CancelJobRequest
+	11, // 26: taskqueue.v1.JobService.WatchJob:input_type -> taskqueue.v1.WatchJobRequest
+	2,  // 27: taskqueue.v1.JobService.SubmitJob:output_type -> taskqueue.v1.Job
+	6,  // 28: taskqueue.v1.JobService.SubmitJobs:output_type -> taskqueue.v1.SubmitJobsResponse
+	2,  // 29: taskqueue.v1.JobService.GetJob:output_type -> taskqueue.v1.Job
+	9,  // 30: taskqueue.v1.JobService.ListJobs:output_type -> taskqueue.v1.ListJobsResponse
+	2,  // 31: taskqueue.v1.JobService.CancelJob:output_type -> taskqueue.v1.Job
+	2,  // 32: taskqueue.v1.JobService.WatchJob:output_type -> taskqueue.v1.Job
+	27, // [27:33] is the sub-list for method output_type
+	21, // [21:27] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
+}
+
+func init() { file_taskqueue_v1_jobs_proto_init() }
+func file_taskqueue_v1_jobs_proto_init() {
+	if File_taskqueue_v1_jobs_proto != nil {
+		return
+	}
+	file_taskqueue_v1_jobs_proto_msgTypes[1].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_taskqueue_v1_jobs_proto_rawDesc), len(file_taskqueue_v1_jobs_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_taskqueue_v1_jobs_proto_goTypes,
+		DependencyIndexes: file_taskqueue_v1_jobs_proto_depIdxs,
+		EnumInfos:         file_taskqueue_v1_jobs_proto_enumTypes,
+		MessageInfos:      file_taskqueue_v1_jobs_proto_msgTypes,
+	}.Build()
+	File_taskqueue_v1_jobs_proto = out.File
+	file_taskqueue_v1_jobs_proto_goTypes = nil
+	file_taskqueue_v1_jobs_proto_depIdxs = nil
+}
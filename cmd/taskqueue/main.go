@@ -0,0 +1,19 @@
+// Command taskqueue operates the task queue through its HTTP API. See
+// package cli for its commands and exit statuses.
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"task-queue/internal/cli"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := cli.Execute(ctx, cli.NewCommand(cli.Connect), os.Args[1:])
+	stop()
+	os.Exit(code)
+}
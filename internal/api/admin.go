@@ -0,0 +1,261 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/internal/service"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+	"task-queue/pkg/validation"
+)
+
+// queuesResponse is the body of GET /v1/admin/queues
+type queuesResponse struct {
+	Queues []*queue.QueueStats `json:"queues"`
+}
+
+// deadLetterResponse is the body of GET /v1/admin/queues/{name}/dead-letter.
+// NextOffset is null on the last page.
+type deadLetterResponse struct {
+	Queue      string        `json:"queue"`
+	Items      []*models.Job `json:"items"`
+	Total      int64         `json:"total"`
+	NextOffset *int          `json:"next_offset"`
+}
+
+// countResponse is the body of the bulk admin actions, counting the jobs
+// they affected
+type countResponse struct {
+	Queue string `json:"queue"`
+	Count int64  `json:"count"`
+}
+
+// routeAdmin registers the admin endpoints, each requiring ScopeAdmin
+func (h *Handler) routeAdmin() {
+	routes := map[string]http.HandlerFunc{
+		"GET /v1/admin/queues":                                  h.listQueues,
+		"GET /v1/admin/queues/{name}/dead-letter":               h.listDeadLetters,
+		"POST /v1/admin/queues/{name}/dead-letter/{id}/requeue": h.requeueDeadLetter,
+		"POST /v1/admin/queues/{name}/dead-letter/requeue-all":  h.requeueDeadLetters,
+		"DELETE /v1/admin/queues/{name}/dead-letter":            h.purgeDeadLetters,
+		"POST /v1/admin/queues/{name}/pause":                    h.pauseQueue,
+		"POST /v1/admin/queues/{name}/resume":                   h.resumeQueue,
+		"POST /v1/admin/queues/{name}/drain":                    h.drainQueue,
+	}
+
+	for pattern, handler := range routes {
+		h.handle(pattern, h.requireScope(ScopeAdmin, handler))
+	}
+}
+
+// admin returns the operator controls of the {name} queue
+func (h *Handler) admin(r *http.Request) (string, queue.Admin, error) {
+	name := r.PathValue("name")
+	admin, err := h.queues.Admin(name)
+	return name, admin, err
+}
+
+// auditAdmin records an admin action with the caller's identity
+func (h *Handler) auditAdmin(r *http.Request, event, name string, extra ...any) {
+	actor := ""
+	if principal, ok := PrincipalFromContext(r.Context()); ok {
+		actor = principal.Name
+	}
+
+	h.audit.AdminEvent(r.Context(), event, actor, append([]any{"queue", name}, extra...)...)
+}
+
+// listQueues handles GET /v1/admin/queues: the statistics of every queue
+func (h *Handler) listQueues(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.queues.Stats(r.Context())
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, queuesResponse{Queues: stats})
+}
+
+// listDeadLetters handles GET /v1/admin/queues/{name}/dead-letter: a page
+// of the queue's dead-lettered jobs, oldest first, selected by the offset
+// and limit parameters
+func (h *Handler) listDeadLetters(w http.ResponseWriter, r *http.Request) {
+	name, admin, err := h.admin(r)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	offset, limit, err := parsePage(r.URL.Query())
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	jobs, total, err := admin.DeadLetters(r.Context(), offset, limit)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	resp := deadLetterResponse{Queue: name, Items: jobs, Total: total}
+	if next := offset + len(jobs); len(jobs) > 0 && int64(next) < total {
+		resp.NextOffset = &next
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// parsePage decodes the offset and limit parameters of a dead letter
+// listing. The limit defaults to service.DefaultListLimit and is capped at
+// service.MaxListLimit.
+func parsePage(query url.Values) (int, int, error) {
+	offset, limit := 0, service.DefaultListLimit
+	err := validation.ValidateAll(
+		validation.NewField("offset", query.Get("offset"), parseOffset(&offset)),
+		validation.NewField("limit", query.Get("limit"), parseLimit(&limit)),
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return offset, min(limit, service.MaxListLimit), nil
+}
+
+// parseOffset returns a validator that parses a non-negative offset into
+// dst. An empty value leaves dst unchanged.
+func parseOffset(dst *int) validation.Validator {
+	return validation.ValidatorFunc(func(value any) error {
+		s := value.(string)
+		if s == "" {
+			return nil
+		}
+
+		offset, err := strconv.Atoi(s)
+		if err != nil || offset < 0 {
+			return fmt.Errorf("must be a non-negative integer")
+		}
+
+		*dst = offset
+		return nil
+	})
+}
+
+// requeueDeadLetter handles POST
+// /v1/admin/queues/{name}/dead-letter/{id}/requeue: the job returns to the
+// queue with its retry count and error cleared
+func (h *Handler) requeueDeadLetter(w http.ResponseWriter, r *http.Request) {
+	name, admin, err := h.admin(r)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	id, err := jobID(r)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	job, err := admin.RequeueDeadLetter(r.Context(), id)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	h.auditAdmin(r, logger.AuditDeadLetterRequeued, name, "job_id", id)
+	writeJSON(w, http.StatusOK, job)
+}
+
+// requeueDeadLetters handles POST
+// /v1/admin/queues/{name}/dead-letter/requeue-all
+func (h *Handler) requeueDeadLetters(w http.ResponseWriter, r *http.Request) {
+	h.countAction(w, r, logger.AuditDeadLetterRequeuedAll, queue.Admin.RequeueDeadLetters)
+}
+
+// purgeDeadLetters handles DELETE /v1/admin/queues/{name}/dead-letter
+func (h *Handler) purgeDeadLetters(w http.ResponseWriter, r *http.Request) {
+	h.countAction(w, r, logger.AuditDeadLetterPurged, queue.Admin.PurgeDeadLetters)
+}
+
+// drainQueue handles POST /v1/admin/queues/{name}/drain: every ready and
+// delayed job is deleted, leaving in-flight and dead-lettered jobs
+func (h *Handler) drainQueue(w http.ResponseWriter, r *http.Request) {
+	h.countAction(w, r, logger.AuditQueueDrained, queue.Admin.Drain)
+}
+
+// countAction runs a bulk admin action on the {name} queue, audits it as
+// event, and responds with the number of jobs it affected. A partial
+// failure is audited with the count reached before it.
+func (h *Handler) countAction(w http.ResponseWriter, r *http.Request, event string,
+	action func(queue.Admin, context.Context) (int64, error)) {
+	name, admin, err := h.admin(r)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	count, err := action(admin, r.Context())
+	if err != nil {
+		if count > 0 {
+			h.auditAdmin(r, event, name, "count", count, "error", err.Error())
+		}
+
+		h.fail(w, r, err)
+		return
+	}
+
+	h.auditAdmin(r, event, name, "count", count)
+	writeJSON(w, http.StatusOK, countResponse{Queue: name, Count: count})
+}
+
+// pauseQueue handles POST /v1/admin/queues/{name}/pause, responding with
+// the queue's statistics
+func (h *Handler) pauseQueue(w http.ResponseWriter, r *http.Request) {
+	h.toggleAction(w, r, logger.AuditQueuePaused, queue.Admin.Pause)
+}
+
+// resumeQueue handles POST /v1/admin/queues/{name}/resume
+func (h *Handler) resumeQueue(w http.ResponseWriter, r *http.Request) {
+	h.toggleAction(w, r, logger.AuditQueueResumed, queue.Admin.Resume)
+}
+
+// toggleAction runs a pause or resume on the {name} queue, audits it as
+// event, and responds with the queue's statistics
+func (h *Handler) toggleAction(w http.ResponseWriter, r *http.Request, event string,
+	action func(queue.Admin, context.Context) error) {
+	name, admin, err := h.admin(r)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	if err := action(admin, r.Context()); err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	h.auditAdmin(r, event, name)
+
+	q, err := h.queues.Get(name)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	stats, err := q.Stats(r.Context())
+	if err != nil {
+		h.fail(w, r, errors.Wrapf(err, "failed to get stats of queue %s", name).
+			WithOp("api.toggleAction"))
+		return
+	}
+
+	stats.Name = name
+	writeJSON(w, http.StatusOK, stats)
+}
@@ -0,0 +1,350 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Keys accepted by the admin test API
+const (
+	adminKey  = "admin-key"
+	readerKey = "reader-key"
+)
+
+// adminEvent is an admin action recorded by recordingAudit
+type adminEvent struct {
+	event string
+	actor string
+	extra map[string]any
+}
+
+// recordingAudit is an AuditLogger keeping the admin events it receives
+type recordingAudit struct {
+	mu     sync.Mutex
+	events []adminEvent
+}
+
+func (a *recordingAudit) JobEvent(context.Context, string, *models.Job, ...any) {}
+
+func (a *recordingAudit) AdminEvent(_ context.Context, event, actor string, extra ...any) {
+	fields := make(map[string]any)
+	for i := 0; i+1 < len(extra); i += 2 {
+		fields[extra[i].(string)] = extra[i+1]
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.events = append(a.events, adminEvent{event: event, actor: actor, extra: fields})
+}
+
+func (a *recordingAudit) Sync() error {
+	return nil
+}
+
+// adminAPI is a test API serving the admin endpoints over one MemoryQueue
+type adminAPI struct {
+	*testAPI
+	queue *queue.MemoryQueue
+	audit *recordingAudit
+}
+
+func newAdminAPI() *adminAPI {
+	a := &adminAPI{
+		queue: queue.NewMemoryQueue(queue.Config{Name: "default"}),
+		audit: &recordingAudit{},
+	}
+
+	manager := queue.NewQueueManager()
+	manager.Register("default", a.queue)
+	a.testAPI = newTestAPI(
+		WithQueueManager(manager),
+		WithAudit(a.audit),
+		WithAuth(StaticKeys{
+			adminKey:  {Name: "ops", Scopes: []string{ScopeAdmin}},
+			readerKey: {Name: "dashboard", Scopes: []string{"jobs:read"}},
+		}),
+	)
+
+	return a
+}
+
+// call sends a request authenticated with key, or without credentials
+// when key is empty
+func (a *adminAPI) call(method, path, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(""))
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	return rec
+}
+
+// deadLetter enqueues a job and fails it until it is dead-lettered
+func (a *adminAPI) deadLetter(t *testing.T) *models.Job {
+	t.Helper()
+
+	ctx := context.Background()
+	job := models.NewJob("email_send", json.RawMessage(`{}`), models.JobPriorityNormal)
+	job.MaxRetries = 1
+	require.NoError(t, a.queue.Enqueue(ctx, job))
+
+	dequeued, err := a.queue.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, dequeued)
+	require.NoError(t, a.queue.Nack(ctx, dequeued.ID, "smtp down"))
+	return job
+}
+
+// lastEvent returns the last admin event recorded
+func (a *adminAPI) lastEvent(t *testing.T) adminEvent {
+	t.Helper()
+
+	a.audit.mu.Lock()
+	defer a.audit.mu.Unlock()
+	require.NotEmpty(t, a.audit.events)
+	return a.audit.events[len(a.audit.events)-1]
+}
+
+func TestAdmin_AuthDenied(t *testing.T) {
+	a := newAdminAPI()
+
+	routes := []struct{ method, path string }{
+		{http.MethodGet, "/v1/admin/queues"},
+		{http.MethodGet, "/v1/admin/queues/default/dead-letter"},
+		{http.MethodPost, "/v1/admin/queues/default/dead-letter/" + uuid.NewString() + "/requeue"},
+		{http.MethodPost, "/v1/admin/queues/default/dead-letter/requeue-all"},
+		{http.MethodDelete, "/v1/admin/queues/default/dead-letter"},
+		{http.MethodPost, "/v1/admin/queues/default/pause"},
+		{http.MethodPost, "/v1/admin/queues/default/resume"},
+		{http.MethodPost, "/v1/admin/queues/default/drain"},
+	}
+
+	for _, route := range routes {
+		t.Run(route.method+" "+route.path, func(t *testing.T) {
+			rec := a.call(route.method, route.path, "")
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+			assert.Equal(t, string(errors.CodeAuthentication), decodeProblem(t, rec)["code"])
+
+			rec = a.call(route.method, route.path, "wrong-key")
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+			rec = a.call(route.method, route.path, readerKey)
+			assert.Equal(t, http.StatusForbidden, rec.Code)
+			assert.Equal(t, string(errors.CodePermission), decodeProblem(t, rec)["code"])
+		})
+	}
+
+	assert.Empty(t, a.audit.events, "denied requests were audited as actions")
+
+	paused, err := a.queue.Stats(context.Background())
+	require.NoError(t, err)
+	assert.False(t, paused.Paused, "denied pause took effect")
+}
+
+func TestAdmin_NoAuthenticator(t *testing.T) {
+	manager := queue.NewQueueManager()
+	manager.Register("default", queue.NewMemoryQueue(queue.Config{}))
+	a := newTestAPI(WithQueueManager(manager))
+
+	rec := a.do(http.MethodGet, "/v1/admin/queues", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestAdmin_ListQueues(t *testing.T) {
+	a := newAdminAPI()
+	a.deadLetter(t)
+	require.NoError(t, a.queue.Enqueue(context.Background(),
+		models.NewJob("email_send", json.RawMessage(`{}`), models.JobPriorityHigh)))
+
+	rec := a.call(http.MethodGet, "/v1/admin/queues", adminKey)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var body queuesResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	require.Len(t, body.Queues, 1)
+	assert.Equal(t, "default", body.Queues[0].Name)
+	assert.EqualValues(t, 1, body.Queues[0].Size)
+	assert.EqualValues(t, 1, body.Queues[0].DeadLetter)
+}
+
+func TestAdmin_ListDeadLetters(t *testing.T) {
+	a := newAdminAPI()
+
+	var want []uuid.UUID
+	for range 5 {
+		want = append(want, a.deadLetter(t).ID)
+	}
+
+	var got []uuid.UUID
+	path := "/v1/admin/queues/default/dead-letter?limit=2"
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 3)
+
+		rec := a.call(http.MethodGet, path, adminKey)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var body deadLetterResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+		assert.EqualValues(t, 5, body.Total)
+		for _, job := range body.Items {
+			assert.Equal(t, models.JobStatusDead, job.Status)
+			got = append(got, job.ID)
+		}
+
+		if body.NextOffset == nil {
+			break
+		}
+
+		path = "/v1/admin/queues/default/dead-letter?limit=2&offset=" + strconv.Itoa(*body.NextOffset)
+	}
+
+	assert.Equal(t, want, got)
+
+	rec := a.call(http.MethodGet, "/v1/admin/queues/default/dead-letter?offset=-1&limit=x", adminKey)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = a.call(http.MethodGet, "/v1/admin/queues/missing/dead-letter", adminKey)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, string(errors.CodeNotFound), decodeProblem(t, rec)["code"])
+}
+
+func TestAdmin_RequeueDeadLetter(t *testing.T) {
+	a := newAdminAPI()
+	job := a.deadLetter(t)
+	path := "/v1/admin/queues/default/dead-letter/" + job.ID.String() + "/requeue"
+
+	rec := a.call(http.MethodPost, path, adminKey)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var requeued models.Job
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&requeued))
+	assert.Equal(t, models.JobStatusPending, requeued.Status)
+	assert.Zero(t, requeued.RetryCount)
+	assert.Nil(t, requeued.Error)
+
+	dequeued, err := a.queue.Dequeue(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, dequeued)
+	assert.Equal(t, job.ID, dequeued.ID)
+
+	event := a.lastEvent(t)
+	assert.Equal(t, logger.AuditDeadLetterRequeued, event.event)
+	assert.Equal(t, "ops", event.actor)
+	assert.Equal(t, "default", event.extra["queue"])
+	assert.Equal(t, job.ID, event.extra["job_id"])
+
+	rec = a.call(http.MethodPost, path, adminKey)
+	assert.Equal(t, http.StatusNotFound, rec.Code, "job is no longer dead-lettered")
+
+	rec = a.call(http.MethodPost, "/v1/admin/queues/default/dead-letter/not-a-uuid/requeue", adminKey)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestAdmin_RequeueAllDeadLetters(t *testing.T) {
+	a := newAdminAPI()
+	a.deadLetter(t)
+	a.deadLetter(t)
+
+	rec := a.call(http.MethodPost, "/v1/admin/queues/default/dead-letter/requeue-all", adminKey)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.JSONEq(t, `{"queue":"default","count":2}`, rec.Body.String())
+
+	stats, err := a.queue.Stats(context.Background())
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, stats.Size)
+	assert.Zero(t, stats.DeadLetter)
+
+	event := a.lastEvent(t)
+	assert.Equal(t, logger.AuditDeadLetterRequeuedAll, event.event)
+	assert.Equal(t, "ops", event.actor)
+	assert.EqualValues(t, 2, event.extra["count"])
+}
+
+func TestAdmin_PurgeDeadLetters(t *testing.T) {
+	a := newAdminAPI()
+	a.deadLetter(t)
+
+	rec := a.call(http.MethodDelete, "/v1/admin/queues/default/dead-letter", adminKey)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.JSONEq(t, `{"queue":"default","count":1}`, rec.Body.String())
+
+	_, total, err := a.queue.DeadLetters(context.Background(), 0, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Equal(t, logger.AuditDeadLetterPurged, a.lastEvent(t).event)
+}
+
+func TestAdmin_PauseResume(t *testing.T) {
+	a := newAdminAPI()
+	ctx := context.Background()
+	require.NoError(t, a.queue.Enqueue(ctx, models.NewJob("email_send", json.RawMessage(`{}`),
+		models.JobPriorityNormal)))
+
+	rec := a.call(http.MethodPost, "/v1/admin/queues/default/pause", adminKey)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var stats queue.QueueStats
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&stats))
+	assert.True(t, stats.Paused)
+	assert.Equal(t, logger.AuditQueuePaused, a.lastEvent(t).event)
+
+	job, err := a.queue.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, job, "paused queue delivered a job")
+
+	rec = a.call(http.MethodPost, "/v1/admin/queues/default/resume", adminKey)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&stats))
+	assert.False(t, stats.Paused)
+	assert.Equal(t, logger.AuditQueueResumed, a.lastEvent(t).event)
+
+	job, err = a.queue.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.NotNil(t, job)
+}
+
+func TestAdmin_Drain(t *testing.T) {
+	a := newAdminAPI()
+	ctx := context.Background()
+	dead := a.deadLetter(t)
+	for range 3 {
+		require.NoError(t, a.queue.Enqueue(ctx, models.NewJob("email_send", json.RawMessage(`{}`),
+			models.JobPriorityNormal)))
+	}
+
+	rec := a.call(http.MethodPost, "/v1/admin/queues/default/drain", adminKey)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.JSONEq(t, `{"queue":"default","count":3}`, rec.Body.String())
+
+	stats, err := a.queue.Stats(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, stats.Size)
+	assert.EqualValues(t, 1, stats.DeadLetter, "drain removed a dead letter")
+
+	event := a.lastEvent(t)
+	assert.Equal(t, logger.AuditQueueDrained, event.event)
+	assert.Equal(t, "ops", event.actor)
+
+	jobs, _, err := a.queue.DeadLetters(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, dead.ID, jobs[0].ID)
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/internal/storage"
+	"task-queue/pkg/errors"
+)
+
+// DefaultKeyCacheTTL is how long StoreKeys trusts a looked-up key
+const DefaultKeyCacheTTL = time.Minute
+
+// maxCachedKeys bounds the StoreKeys cache, so a flood of random keys
+// cannot grow it without limit
+const maxCachedKeys = 10000
+
+// cachedKey is a StoreKeys lookup: the key's principal, or nil for a key
+// that is not stored
+type cachedKey struct {
+	principal *Principal
+	expires   time.Time
+}
+
+// StoreKeys authenticates an API key by looking up its hash in a key
+// store. Lookups, including misses, are cached for a TTL, so a revoked key
+// may keep working until its entry expires.
+type StoreKeys struct {
+	store storage.APIKeyStore
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedKey
+}
+
+var _ Authenticator = (*StoreKeys)(nil)
+
+// NewStoreKeys creates an authenticator over store caching lookups for
+// ttl, DefaultKeyCacheTTL when ttl is zero
+func NewStoreKeys(store storage.APIKeyStore, ttl time.Duration) *StoreKeys {
+	if ttl == 0 {
+		ttl = DefaultKeyCacheTTL
+	}
+
+	return &StoreKeys{
+		store: store,
+		ttl:   ttl,
+		now:   time.Now,
+		cache: make(map[string]cachedKey),
+	}
+}
+
+// Authenticate returns the principal of the stored key matching the
+// request's API key. A store failure is returned as is, so it surfaces as
+// a server error rather than a rejected key.
+func (k *StoreKeys) Authenticate(r *http.Request) (*Principal, error) {
+	token, err := credential(r)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := models.HashAPIKey(token)
+	now := k.now()
+	if cached, ok := k.cached(hash, now); ok {
+		if cached == nil {
+			return nil, invalidKey("api.StoreKeys.Authenticate")
+		}
+
+		return cached, nil
+	}
+
+	key, err := k.store.FindAPIKey(r.Context(), hash)
+	switch {
+	case errors.HasCode(err, errors.CodeNotFound):
+		k.remember(hash, nil, now.Add(k.ttl))
+		return nil, invalidKey("api.StoreKeys.Authenticate")
+
+	case err != nil:
+		return nil, errors.Wrap(err, "failed to look up API key").
+			WithOp("api.StoreKeys.Authenticate")
+	}
+
+	if !key.Active(now) {
+		k.remember(hash, nil, now.Add(k.ttl))
+		return nil, errors.New("expired API key").
+			WithCode(errors.CodeAuthentication).
+			WithOp("api.StoreKeys.Authenticate")
+	}
+
+	expires := now.Add(k.ttl)
+	if key.ExpiresAt != nil && key.ExpiresAt.Before(expires) {
+		expires = *key.ExpiresAt
+	}
+
+	principal := &Principal{Name: key.Name, Scopes: key.Scopes}
+	k.remember(hash, principal, expires)
+	return principal, nil
+}
+
+// cached returns the unexpired cache entry of hash
+func (k *StoreKeys) cached(hash string, now time.Time) (*Principal, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	entry, ok := k.cache[hash]
+	if !ok || !now.Before(entry.expires) {
+		return nil, false
+	}
+
+	return entry.principal, true
+}
+
+// remember caches the lookup of hash until expires. A full cache is
+// cleared rather than evicted entry by entry.
+func (k *StoreKeys) remember(hash string, principal *Principal, expires time.Time) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if len(k.cache) >= maxCachedKeys {
+		clear(k.cache)
+	}
+
+	k.cache[hash] = cachedKey{principal: principal, expires: expires}
+}
+
+// NewAuthenticator builds the authenticator of cfg: its configured keys,
+// then the keys of store when cfg.Database is set
+func NewAuthenticator(cfg config.AuthConfig, store storage.APIKeyStore) (Authenticator, error) {
+	keys := make(HashedKeys, len(cfg.Keys))
+	for _, key := range cfg.Keys {
+		keys[strings.ToLower(key.Hash)] = Principal{Name: key.Name, Scopes: key.Scopes}
+	}
+
+	auth := Authenticators{keys}
+	if cfg.Database {
+		if store == nil {
+			return nil, errors.New("auth.database requires an API key store").
+				WithCode(errors.CodeConfiguration).
+				WithOp("api.NewAuthenticator")
+		}
+
+		auth = append(auth, NewStoreKeys(store, cfg.CacheTTL))
+	}
+
+	return auth, nil
+}
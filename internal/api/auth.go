@@ -0,0 +1,230 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"slices"
+	"strings"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+)
+
+// Scopes granted to API keys. ScopeAdmin implies every other scope.
+const (
+	// ScopeJobsRead grants reading, listing, and watching jobs
+	ScopeJobsRead = "jobs:read"
+
+	// ScopeJobsWrite grants submitting and cancelling jobs
+	ScopeJobsWrite = "jobs:write"
+
+	// ScopeAdmin grants access to the /v1/admin endpoints
+	ScopeAdmin = "admin"
+)
+
+// APIKeyHeader carries an API key, as an alternative to an
+// Authorization: Bearer header
+const APIKeyHeader = "X-API-Key"
+
+// Principal is an authenticated API caller
+type Principal struct {
+	Name   string
+	Scopes []string
+}
+
+// HasScope reports whether p was granted scope, directly or through
+// ScopeAdmin
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope) || slices.Contains(p.Scopes, ScopeAdmin)
+}
+
+// Authenticator identifies the caller of a request. A request without valid
+// credentials fails with a CodeAuthentication error.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// credential returns the API key of r, from X-API-Key or else an
+// Authorization: Bearer header
+func credential(r *http.Request) (string, error) {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return key, nil
+	}
+
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && token != "" {
+		return token, nil
+	}
+
+	return "", errors.New("missing API key").
+		WithCode(errors.CodeAuthentication).
+		WithOp("api.credential")
+}
+
+// invalidKey is the error of a key that identifies no principal
+func invalidKey(op string) error {
+	return errors.New("invalid API key").
+		WithCode(errors.CodeAuthentication).
+		WithOp(op)
+}
+
+// StaticKeys authenticates an API key against a fixed set of keys, each
+// mapped to the principal it identifies
+type StaticKeys map[string]Principal
+
+var _ Authenticator = StaticKeys(nil)
+
+// Authenticate returns the principal of the request's API key. Every key
+// is compared in constant time, so timing does not reveal which prefix
+// matched.
+func (k StaticKeys) Authenticate(r *http.Request) (*Principal, error) {
+	token, err := credential(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *Principal
+	for key, principal := range k {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(token)) == 1 {
+			found = &principal
+		}
+	}
+
+	if found == nil {
+		return nil, invalidKey("api.StaticKeys.Authenticate")
+	}
+
+	return found, nil
+}
+
+// HashedKeys authenticates an API key by its hex SHA-256, as produced by
+// models.HashAPIKey, so the keys themselves need not be kept in
+// configuration. Each hash is mapped to the principal it identifies.
+type HashedKeys map[string]Principal
+
+var _ Authenticator = HashedKeys(nil)
+
+// Authenticate returns the principal whose hash matches the request's API
+// key. Every hash is compared in constant time.
+func (k HashedKeys) Authenticate(r *http.Request) (*Principal, error) {
+	token, err := credential(r)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := []byte(models.HashAPIKey(token))
+	var found *Principal
+	for keyHash, principal := range k {
+		if subtle.ConstantTimeCompare([]byte(strings.ToLower(keyHash)), hash) == 1 {
+			found = &principal
+		}
+	}
+
+	if found == nil {
+		return nil, invalidKey("api.HashedKeys.Authenticate")
+	}
+
+	return found, nil
+}
+
+// Authenticators tries each authenticator in turn, returning the first
+// principal found. An error other than CodeAuthentication, such as the key
+// store being down, stops the search.
+type Authenticators []Authenticator
+
+var _ Authenticator = Authenticators(nil)
+
+// Authenticate returns the first principal found for r
+func (a Authenticators) Authenticate(r *http.Request) (*Principal, error) {
+	err := invalidKey("api.Authenticators.Authenticate")
+	for _, auth := range a {
+		principal, authErr := auth.Authenticate(r)
+		if authErr == nil {
+			return principal, nil
+		}
+
+		err = authErr
+		if !errors.HasCode(err, errors.CodeAuthentication) {
+			return nil, err
+		}
+	}
+
+	return nil, err
+}
+
+// authResult is the outcome of authenticating a request
+type authResult struct {
+	principal *Principal
+	err       error
+}
+
+// authKey is the context key of a request's authResult
+type authKey struct{}
+
+// authenticate authenticates r once with the WithAuth authenticator,
+// returning r with the outcome in its context. The principal's name is also
+// set as the logger's user ID, so logs and job events name the caller.
+func (h *Handler) authenticate(r *http.Request) *http.Request {
+	principal, err := h.auth.Authenticate(r)
+	ctx := context.WithValue(r.Context(), authKey{}, authResult{principal: principal, err: err})
+	if err == nil {
+		ctx = context.WithValue(ctx, logger.UserIDKey, principal.Name)
+	}
+
+	return r.WithContext(ctx)
+}
+
+// PrincipalFromContext returns the principal authenticated for the request
+// carrying ctx
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	result, ok := ctx.Value(authKey{}).(authResult)
+	if !ok || result.err != nil || result.principal == nil {
+		return nil, false
+	}
+
+	return result.principal, true
+}
+
+// requireScope runs next only for callers granted scope. Without an
+// Authenticator the job scopes are open to everyone and ScopeAdmin is
+// refused to everyone.
+func (h *Handler) requireScope(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.auth == nil {
+			if scope != ScopeAdmin {
+				next(w, r)
+				return
+			}
+
+			h.fail(w, r, errors.New("authentication is not configured").
+				WithCode(errors.CodeAuthentication).
+				WithOp("api.requireScope"))
+			return
+		}
+
+		if _, ok := r.Context().Value(authKey{}).(authResult); !ok {
+			r = h.authenticate(r)
+		}
+
+		result := r.Context().Value(authKey{}).(authResult)
+		if result.err != nil {
+			if errors.HasCode(result.err, errors.CodeAuthentication) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="task-queue"`)
+			}
+
+			h.fail(w, r, result.err)
+			return
+		}
+
+		if !result.principal.HasScope(scope) {
+			h.fail(w, r, errors.Newf("%s lacks the %s scope", result.principal.Name, scope).
+				WithCode(errors.CodePermission).
+				WithMetadata("scope", scope).
+				WithOp("api.requireScope"))
+			return
+		}
+
+		next(w, r)
+	}
+}
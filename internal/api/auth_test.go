@@ -0,0 +1,276 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/health"
+	"task-queue/internal/models"
+	"task-queue/internal/storage"
+	"task-queue/pkg/errors"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Keys configured by hash for the auth tests
+const (
+	writerKey = "tq_writer"
+	viewerKey = "tq_viewer"
+)
+
+// newAuthAPI is a test API authenticating the writer and viewer keys by
+// hash
+func newAuthAPI(t *testing.T) *testAPI {
+	t.Helper()
+
+	auth, err := NewAuthenticator(config.AuthConfig{
+		Enabled: true,
+		Keys: []config.APIKeyConfig{
+			{Name: "producer", Hash: models.HashAPIKey(writerKey), Scopes: []string{ScopeJobsWrite, ScopeJobsRead}},
+			{Name: "dashboard", Hash: strings.ToUpper(models.HashAPIKey(viewerKey)), Scopes: []string{ScopeJobsRead}},
+		},
+	}, nil)
+	require.NoError(t, err)
+	return newTestAPI(WithAuth(auth))
+}
+
+// send sends a request with headers as name/value pairs
+func (a *testAPI) send(method, path, body string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	return rec
+}
+
+const submitBody = `{"type":"email_send","payload":{"to":"a@example.com"}}`
+
+func TestAuth_MissingKey(t *testing.T) {
+	a := newAuthAPI(t)
+
+	rec := a.send(http.MethodPost, "/v1/jobs", submitBody)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+	assert.Equal(t, string(errors.CodeAuthentication), decodeProblem(t, rec)["code"])
+}
+
+func TestAuth_WrongKey(t *testing.T) {
+	a := newAuthAPI(t)
+
+	for _, header := range []string{APIKeyHeader, "Authorization"} {
+		value := "tq_unknown"
+		if header == "Authorization" {
+			value = "Bearer " + value
+		}
+
+		rec := a.send(http.MethodGet, "/v1/jobs", "", header, value)
+		require.Equal(t, http.StatusUnauthorized, rec.Code, header)
+		assert.Equal(t, string(errors.CodeAuthentication), decodeProblem(t, rec)["code"])
+	}
+}
+
+func TestAuth_InsufficientScope(t *testing.T) {
+	a := newAuthAPI(t)
+
+	rec := a.send(http.MethodPost, "/v1/jobs", submitBody, APIKeyHeader, viewerKey)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	problem := decodeProblem(t, rec)
+	assert.Equal(t, string(errors.CodePermission), problem["code"])
+	assert.Contains(t, problem["detail"], ScopeJobsWrite)
+}
+
+func TestAuth_ScopedKeyAllowed(t *testing.T) {
+	a := newAuthAPI(t)
+
+	rec := a.send(http.MethodPost, "/v1/jobs", submitBody, "Authorization", "Bearer "+writerKey)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec = a.send(http.MethodGet, "/v1/jobs", "", APIKeyHeader, viewerKey)
+	assert.Equal(t, http.StatusOK, rec.Code, "hashes match in any case")
+
+	rec = a.send(http.MethodGet, "/v1/admin/queues", "", APIKeyHeader, writerKey)
+	assert.Equal(t, http.StatusNotFound, rec.Code, "admin routes exist only with a queue manager")
+}
+
+func TestAuth_PrincipalNamesEvents(t *testing.T) {
+	a := newAuthAPI(t)
+
+	rec := a.send(http.MethodPost, "/v1/jobs", submitBody, APIKeyHeader, writerKey)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	jobs, err := a.store.List(context.Background(), &models.JobFilter{})
+	require.NoError(t, err)
+	require.Len(t, jobs.Items, 1)
+
+	events := a.events.Events(jobs.Items[0].ID)
+	require.Len(t, events, 1)
+	require.NotNil(t, events[0].CreatedBy)
+	assert.Equal(t, "producer", *events[0].CreatedBy)
+}
+
+func TestAuth_ProbesAreOpen(t *testing.T) {
+	auth, err := NewAuthenticator(config.AuthConfig{Enabled: true}, nil)
+	require.NoError(t, err)
+
+	checker := health.New()
+	checker.Register("redis", func(context.Context) error {
+		return errors.New("connection refused").WithCode(errors.CodeNetwork)
+	})
+	a := newTestAPI(WithAuth(auth), WithHealth(checker))
+
+	assert.Equal(t, http.StatusOK, a.send(http.MethodGet, "/healthz", "").Code)
+
+	rec := a.send(http.MethodGet, "/readyz", "")
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"failing":["redis"]`)
+
+	assert.Equal(t, http.StatusUnauthorized, a.send(http.MethodGet, "/v1/jobs", "").Code)
+}
+
+func TestAuth_AdminImpliesJobScopes(t *testing.T) {
+	principal := &Principal{Name: "ops", Scopes: []string{ScopeAdmin}}
+	assert.True(t, principal.HasScope(ScopeJobsWrite))
+	assert.True(t, principal.HasScope(ScopeJobsRead))
+}
+
+// countingStore counts key lookups and can be made to fail
+type countingStore struct {
+	*storage.MemoryAPIKeyStore
+	lookups atomic.Int32
+	fail    atomic.Bool
+}
+
+func (s *countingStore) FindAPIKey(ctx context.Context, hash string) (*models.APIKey, error) {
+	s.lookups.Add(1)
+	if s.fail.Load() {
+		return nil, errors.New("connection refused").WithCode(errors.CodeDatabase)
+	}
+
+	return s.MemoryAPIKeyStore.FindAPIKey(ctx, hash)
+}
+
+// keyRequest is a request carrying key in X-API-Key
+func keyRequest(key string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/v1/jobs", nil)
+	req.Header.Set(APIKeyHeader, key)
+	return req
+}
+
+func TestStoreKeys(t *testing.T) {
+	ctx := context.Background()
+	store := &countingStore{MemoryAPIKeyStore: storage.NewMemoryAPIKeyStore()}
+	secret, key, err := storage.IssueAPIKey(ctx, store, "ci", []string{ScopeJobsWrite})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, "tq_"))
+	assert.Equal(t, models.HashAPIKey(secret), key.KeyHash)
+
+	now := time.Now()
+	auth := NewStoreKeys(store, time.Minute)
+	auth.now = func() time.Time { return now }
+
+	t.Run("lookups are cached", func(t *testing.T) {
+		for range 3 {
+			principal, err := auth.Authenticate(keyRequest(secret))
+			require.NoError(t, err)
+			assert.Equal(t, "ci", principal.Name)
+			assert.Equal(t, []string{ScopeJobsWrite}, principal.Scopes)
+		}
+
+		assert.EqualValues(t, 1, store.lookups.Load())
+	})
+
+	t.Run("misses are cached", func(t *testing.T) {
+		for range 2 {
+			_, err := auth.Authenticate(keyRequest("tq_unknown"))
+			assert.True(t, errors.HasCode(err, errors.CodeAuthentication))
+		}
+
+		assert.EqualValues(t, 2, store.lookups.Load())
+	})
+
+	t.Run("store failures are not authentication failures", func(t *testing.T) {
+		store.fail.Store(true)
+		defer store.fail.Store(false)
+
+		_, err := auth.Authenticate(keyRequest("tq_other"))
+		require.Error(t, err)
+		assert.True(t, errors.HasCode(err, errors.CodeDatabase))
+	})
+
+	t.Run("rotated keys expire after the grace period", func(t *testing.T) {
+		newSecret, newKey, err := storage.RotateAPIKey(ctx, store, key.ID, time.Hour)
+		require.NoError(t, err)
+		assert.NotEqual(t, secret, newSecret)
+		assert.Equal(t, key.Name, newKey.Name)
+
+		now = now.Add(2 * time.Minute)
+		_, err = auth.Authenticate(keyRequest(secret))
+		assert.NoError(t, err, "the old key works during the grace period")
+
+		now = now.Add(2 * time.Hour)
+		_, err = auth.Authenticate(keyRequest(secret))
+		assert.True(t, errors.HasCode(err, errors.CodeAuthentication))
+
+		principal, err := auth.Authenticate(keyRequest(newSecret))
+		require.NoError(t, err)
+		assert.Equal(t, "ci", principal.Name)
+	})
+
+	t.Run("revoked keys stop working once the cache expires", func(t *testing.T) {
+		tempSecret, temp, err := storage.IssueAPIKey(ctx, store, "temp", []string{ScopeJobsRead})
+		require.NoError(t, err)
+		_, err = auth.Authenticate(keyRequest(tempSecret))
+		require.NoError(t, err)
+
+		require.NoError(t, storage.RevokeAPIKey(ctx, store, temp.ID))
+		_, err = auth.Authenticate(keyRequest(tempSecret))
+		assert.NoError(t, err, "the cached lookup is still trusted")
+
+		now = now.Add(2 * time.Minute)
+		_, err = auth.Authenticate(keyRequest(tempSecret))
+		assert.True(t, errors.HasCode(err, errors.CodeAuthentication))
+
+		err = storage.RevokeAPIKey(ctx, store, uuid.New())
+		assert.True(t, errors.HasCode(err, errors.CodeNotFound))
+	})
+}
+
+func TestNewAuthenticator_DatabaseRequiresStore(t *testing.T) {
+	_, err := NewAuthenticator(config.AuthConfig{Enabled: true, Database: true}, nil)
+	assert.True(t, errors.HasCode(err, errors.CodeConfiguration))
+}
+
+func TestNewAuthenticator_FallsThroughToStore(t *testing.T) {
+	store := storage.NewMemoryAPIKeyStore()
+	secret, _, err := storage.IssueAPIKey(context.Background(), store, "ci", []string{ScopeJobsRead})
+	require.NoError(t, err)
+
+	auth, err := NewAuthenticator(config.AuthConfig{
+		Enabled:  true,
+		Database: true,
+		Keys: []config.APIKeyConfig{
+			{Name: "producer", Hash: models.HashAPIKey(writerKey), Scopes: []string{ScopeJobsWrite}},
+		},
+	}, store)
+	require.NoError(t, err)
+
+	principal, err := auth.Authenticate(keyRequest(secret))
+	require.NoError(t, err)
+	assert.Equal(t, "ci", principal.Name)
+
+	principal, err = auth.Authenticate(keyRequest(writerKey))
+	require.NoError(t, err)
+	assert.Equal(t, "producer", principal.Name)
+}
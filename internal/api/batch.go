@@ -0,0 +1,121 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/internal/service"
+	"task-queue/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// Default limits of POST /v1/jobs/batch
+const (
+	DefaultMaxBatchItems = 1000
+	DefaultMaxBatchBytes = 32 << 20
+)
+
+// Statuses of the items of a batch response
+const (
+	itemAccepted = "accepted"
+	itemRejected = "rejected"
+)
+
+// WithBatchLimits caps the number of jobs and the body size of bulk
+// submissions at the limits of cfg. Zero limits keep the defaults.
+func WithBatchLimits(cfg config.BatchConfig) Option {
+	return func(h *Handler) {
+		if cfg.MaxItems > 0 {
+			h.maxBatchItems = cfg.MaxItems
+		}
+
+		if cfg.MaxBodyBytes > 0 {
+			h.maxBatchBytes = cfg.MaxBodyBytes
+		}
+	}
+}
+
+// batchRequest is the body of POST /v1/jobs/batch
+type batchRequest struct {
+	Jobs []*models.JobRequest `json:"jobs"`
+}
+
+// batchItem is the outcome of one job of a batch: its ID when accepted,
+// its failures when rejected
+type batchItem struct {
+	Index  int                 `json:"index"`
+	ID     *uuid.UUID          `json:"id,omitempty"`
+	Status string              `json:"status"`
+	Errors []errors.FieldError `json:"errors,omitempty"`
+}
+
+// batchResponse is the body of a POST /v1/jobs/batch response
+type batchResponse struct {
+	Accepted int         `json:"accepted"`
+	Rejected int         `json:"rejected"`
+	Results  []batchItem `json:"results"`
+}
+
+// submitBatch handles POST /v1/jobs/batch: every valid job is stored and
+// enqueued, and each job's outcome is reported in a 207 response. With
+// ?atomic=true a single invalid job rejects the whole batch.
+func (h *Handler) submitBatch(w http.ResponseWriter, r *http.Request) {
+	atomic := false
+	if value := r.URL.Query().Get("atomic"); value != "" {
+		var err error
+		if atomic, err = strconv.ParseBool(value); err != nil {
+			h.fail(w, r, errors.Validation("invalid atomic flag %q", value).
+				WithOp("api.submitBatch"))
+			return
+		}
+	}
+
+	var req batchRequest
+	if err := decodeJSON(w, r, h.maxBatchBytes, &req); err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	results, err := h.jobs.SubmitBatch(r.Context(), req.Jobs,
+		service.BatchLimit(h.maxBatchItems), service.Atomic(atomic))
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	resp := batchResponse{Results: make([]batchItem, len(results))}
+	for i, result := range results {
+		item := batchItem{Index: i, Status: itemAccepted}
+		if result.Err != nil {
+			item.Status = itemRejected
+			item.Errors = itemErrors(result.Err)
+			resp.Rejected++
+		} else {
+			item.ID = &result.Job.ID
+			resp.Accepted++
+		}
+
+		resp.Results[i] = item
+	}
+
+	writeJSON(w, http.StatusMultiStatus, resp)
+}
+
+// itemErrors renders the rejection of one batch job like the "errors" of a
+// problem response: its field failures, or a single failure without a
+// field for other errors
+func itemErrors(err error) []errors.FieldError {
+	problem := errors.ProblemDetails(err, "")
+	if fields, ok := problem.Extensions["errors"].([]errors.FieldError); ok && len(fields) > 0 {
+		return fields
+	}
+
+	return []errors.FieldError{{
+		Message: problem.Detail,
+		Tag:     strings.ToLower(string(errors.GetCode(err))),
+	}}
+}
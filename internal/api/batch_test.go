@@ -0,0 +1,188 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/internal/service"
+	"task-queue/internal/storage"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mixedBatch holds a valid job, one with an invalid type and priority, and
+// another valid job
+const mixedBatch = `{"jobs":[
+	{"type":"email_send","payload":{"to":"a@example.com"}},
+	{"type":"bad type!","payload":{},"priority":99},
+	{"type":"report_build","payload":{"id":7},"priority":2}
+]}`
+
+// decodeBatch decodes a 207 batch response
+func decodeBatch(t *testing.T, body string, code int) batchResponse {
+	t.Helper()
+
+	require.Equal(t, http.StatusMultiStatus, code, body)
+
+	var resp batchResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+	return resp
+}
+
+// storedJobs returns every job in the store of a
+func (a *testAPI) storedJobs(t *testing.T) []*models.Job {
+	t.Helper()
+
+	page, err := a.store.List(context.Background(), &models.JobFilter{})
+	require.NoError(t, err)
+	return page.Items
+}
+
+func TestSubmitBatch_MixedResults(t *testing.T) {
+	a := newTestAPI()
+
+	rec := a.do(http.MethodPost, "/v1/jobs/batch", mixedBatch)
+	resp := decodeBatch(t, rec.Body.String(), rec.Code)
+	assert.Equal(t, 2, resp.Accepted)
+	assert.Equal(t, 1, resp.Rejected)
+	require.Len(t, resp.Results, 3)
+
+	for _, i := range []int{0, 2} {
+		item := resp.Results[i]
+		assert.Equal(t, i, item.Index)
+		assert.Equal(t, itemAccepted, item.Status)
+		require.NotNil(t, item.ID)
+		assert.Empty(t, item.Errors)
+
+		job, err := a.store.Get(context.Background(), *item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.JobStatusPending, job.Status)
+		assert.Len(t, a.events.Events(job.ID), 1)
+	}
+
+	rejected := resp.Results[1]
+	assert.Equal(t, itemRejected, rejected.Status)
+	assert.Nil(t, rejected.ID)
+
+	fields := make([]string, len(rejected.Errors))
+	for i, failure := range rejected.Errors {
+		fields[i] = failure.Field
+	}
+
+	assert.ElementsMatch(t, []string{"type", "priority"}, fields)
+
+	size, err := a.queue.Size(context.Background())
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, size)
+}
+
+func TestSubmitBatch_AtomicRejectsAll(t *testing.T) {
+	a := newTestAPI()
+
+	rec := a.do(http.MethodPost, "/v1/jobs/batch?atomic=true", mixedBatch)
+	resp := decodeBatch(t, rec.Body.String(), rec.Code)
+	assert.Equal(t, 0, resp.Accepted)
+	assert.Equal(t, 3, resp.Rejected)
+
+	for _, item := range resp.Results {
+		assert.Equal(t, itemRejected, item.Status)
+		assert.Nil(t, item.ID)
+		assert.NotEmpty(t, item.Errors)
+	}
+
+	assert.Equal(t, "validation", resp.Results[0].Errors[0].Tag)
+	assert.Contains(t, resp.Results[0].Errors[0].Message, "atomic batch")
+	assert.Empty(t, a.storedJobs(t), "no job of a rejected atomic batch is stored")
+
+	size, err := a.queue.Size(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, size)
+}
+
+func TestSubmitBatch_AtomicAcceptsValidBatch(t *testing.T) {
+	a := newTestAPI()
+
+	rec := a.do(http.MethodPost, "/v1/jobs/batch?atomic=1",
+		`{"jobs":[{"type":"email_send","payload":{}},{"type":"email_send","payload":{}}]}`)
+	resp := decodeBatch(t, rec.Body.String(), rec.Code)
+	assert.Equal(t, 2, resp.Accepted)
+	assert.Len(t, a.storedJobs(t), 2)
+
+	rec = a.do(http.MethodPost, "/v1/jobs/batch?atomic=maybe", `{"jobs":[]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+// failingQueue is a memory queue whose batches fail after enqueueing their
+// first job
+type failingQueue struct {
+	*queue.MemoryQueue
+}
+
+func (q failingQueue) EnqueueBatch(ctx context.Context, jobs []*models.Job) error {
+	if err := q.Enqueue(ctx, jobs[0]); err != nil {
+		return err
+	}
+
+	return errors.New("connection reset").WithCode(errors.CodeNetwork)
+}
+
+func TestSubmitBatch_EnqueueFailureAbandonsBatch(t *testing.T) {
+	store := storage.NewMemoryJobStore()
+	q := failingQueue{queue.NewMemoryQueue(queue.Config{})}
+	log := logger.NewNop()
+	h := New(service.New(store, storage.NewMemoryEventStore(), q, log), log)
+	a := &testAPI{Handler: h, store: store}
+
+	rec := a.do(http.MethodPost, "/v1/jobs/batch",
+		`{"jobs":[{"type":"email_send","payload":{}},{"type":"email_send","payload":{}}]}`)
+	require.Equal(t, http.StatusInternalServerError, rec.Code, rec.Body.String())
+
+	jobs := a.storedJobs(t)
+	require.Len(t, jobs, 2)
+	for _, job := range jobs {
+		assert.Equal(t, models.JobStatusFailed, job.Status)
+	}
+
+	size, err := q.Size(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, size, "the partly enqueued batch is taken back out")
+}
+
+func TestSubmitBatch_Limits(t *testing.T) {
+	a := newTestAPI(WithBatchLimits(config.BatchConfig{MaxItems: 2, MaxBodyBytes: 256}))
+
+	rec := a.do(http.MethodPost, "/v1/jobs/batch", `{"jobs":[]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "an empty batch is rejected")
+
+	items := strings.Repeat(`{"type":"email_send","payload":{}},`, 3)
+	rec = a.do(http.MethodPost, "/v1/jobs/batch", `{"jobs":[`+strings.TrimSuffix(items, ",")+`]}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.EqualValues(t, 2, decodeProblem(t, rec)["max_jobs"])
+
+	payload := fmt.Sprintf(`{"text":%q}`, strings.Repeat("x", 300))
+	rec = a.do(http.MethodPost, "/v1/jobs/batch",
+		`{"jobs":[{"type":"email_send","payload":`+payload+`}]}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Empty(t, a.storedJobs(t))
+}
+
+func TestSubmitBatch_RequiresWriteScope(t *testing.T) {
+	a := newAuthAPI(t)
+
+	body := `{"jobs":[` + submitBody + `]}`
+	rec := a.send(http.MethodPost, "/v1/jobs/batch", body, APIKeyHeader, viewerKey)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = a.send(http.MethodPost, "/v1/jobs/batch", body, APIKeyHeader, writerKey)
+	assert.Equal(t, http.StatusMultiStatus, rec.Code)
+}
@@ -0,0 +1,115 @@
+// Package api serves the task queue's HTTP API for producers.
+//
+// The endpoints are:
+//
+//	POST   /v1/jobs              submit a models.JobRequest, 201 with the job
+//	POST   /v1/jobs/batch        submit {"jobs": [...]}, 207 with each job's outcome
+//	GET    /v1/jobs              list jobs matching a filter, a page at a time
+//	GET    /v1/jobs/{id}         the job and its status
+//	DELETE /v1/jobs/{id}         cancel a job that has not started
+//	POST   /v1/jobs/{id}/replay  run a finished job again as a new job, 201 with it
+//	GET    /v1/jobs/{id}/events  the job's audit trail, oldest first
+//
+// The listing takes repeatable or comma-separated status, type, priority,
+// tag, and metadata (key:value) parameters, created_after and
+// created_before timestamps, a q text search, sort (created_at or
+// updated_at, prefixed with - for newest first), limit, and the cursor
+// returned as next_cursor by the previous page. Limits above 200 are
+// capped, and listed priorities are names such as "high".
+//
+// A replay copies the finished job's type, payload, priority, and metadata
+// into a new job whose replay_of metadata names the original. An optional
+// body overrides the priority and scheduled_at and adds metadata. Replaying
+// a job that is pending, retrying, or running is a 409 conflict.
+//
+// A batch reports each job by index as accepted, with its id, or rejected,
+// with its errors; the invalid jobs do not stop the others. With
+// ?atomic=true one invalid job rejects them all. The accepted jobs are
+// stored in one transaction. WithBatchLimits sets the most jobs and bytes
+// a batch may hold, 1000 jobs and 32 MiB by default.
+//
+// With WithEventBus, job events are also streamed as server-sent events:
+//
+//	GET    /v1/jobs/{id}/watch   the job's state, then its changes until it finishes
+//	GET    /v1/events            every job event, filtered by type and status
+//
+// Each frame is named by its event type, such as job.completed, and
+// carries the eventbus.Event as JSON. Idle streams send a heartbeat
+// comment every 15 seconds. A client that falls too far behind is
+// disconnected, and every stream ends when a Server shuts down.
+//
+// With WithDocs, GET /v1/openapi.json serves the OpenAPI document of
+// package openapi and GET /v1/docs renders it, both without
+// authentication. A test keeps the document in step with Routes.
+//
+// With WithHealth, GET /healthz and GET /readyz answer the liveness and
+// readiness probes of a health.Checker, without authentication or rate
+// limits.
+//
+// With WithTracing every other request is a server span named after its
+// route, continuing the trace of the caller's W3C traceparent header, and
+// its log entries carry the trace ID.
+//
+// With WithAuth every request is authenticated by an API key sent as
+// X-API-Key or Authorization: Bearer. Reading and watching jobs then
+// requires ScopeJobsRead, submitting and cancelling them ScopeJobsWrite,
+// and ScopeAdmin grants both. NewAuthenticator builds the authenticator of
+// the auth config section: keys configured by their SHA-256 hash, then keys
+// looked up in a storage.APIKeyStore with StoreKeys, which caches lookups.
+// The caller's name becomes the user_id of request logs and the created_by
+// of the job events it causes.
+//
+// With WithQueueManager, callers authenticated with ScopeAdmin also reach
+// the admin endpoints:
+//
+//	GET    /v1/admin/queues                                      statistics of every queue
+//	GET    /v1/admin/queues/{name}/dead-letter                   dead-lettered jobs, by offset and limit
+//	POST   /v1/admin/queues/{name}/dead-letter/{id}/requeue      requeue one dead-lettered job
+//	POST   /v1/admin/queues/{name}/dead-letter/requeue-all       requeue every dead-lettered job
+//	DELETE /v1/admin/queues/{name}/dead-letter                   purge the dead letter queue
+//	POST   /v1/admin/queues/{name}/pause                         stop delivering jobs
+//	POST   /v1/admin/queues/{name}/resume                        deliver jobs again
+//	POST   /v1/admin/queues/{name}/drain                         delete every waiting job
+//
+// Every admin action is recorded on the WithAudit logger with the caller's
+// name. Missing or unknown credentials are 401 and a caller without the
+// admin scope is 403.
+//
+// WithRateLimit gives each client a token bucket, keyed by its API key when
+// it authenticates and by its IP address otherwise, with X-Forwarded-For
+// honored only from trusted proxies. Every limited response carries
+// X-RateLimit-Remaining, and a client out of tokens gets a 429 with
+// Retry-After. Configured routes, such as POST /v1/jobs, have limits and
+// buckets of their own.
+//
+// Errors are RFC 7807 problem details written by errors.WriteProblem:
+// invalid requests are 400 with the field failures under "errors", unknown
+// jobs are 404, and cancelling a running or finished job is 409. A
+// panicking endpoint is answered with a 500 problem.
+//
+// A Server runs the API with the configured timeouts and TLS until SIGTERM
+// or SIGINT. On shutdown it fails /readyz, keeps serving for the drain
+// delay so load balancers can notice, ends event streams, waits up to
+// ShutdownTimeout for in-flight requests, then runs its OnShutdown steps in
+// order:
+//
+//	auth, err := api.NewAuthenticator(cfg.Auth, storage.NewAPIKeyRepository(db, log))
+//	svc := service.New(jobStore, eventStore, q, log,
+//	    service.WithValidation(validation.WithSchemaLookup(schemas)))
+//	h := api.New(svc, log,
+//	    api.WithQueueManager(queues),
+//	    api.WithAuth(auth),
+//	    api.WithAudit(audit),
+//	    api.WithEventBus(bus),
+//	    api.WithHealth(checker),
+//	    api.WithBatchLimits(cfg.Server.Batch),
+//	    api.WithRateLimit(limiter, cfg.Server.RateLimit))
+//	srv := api.NewServer(cfg.Server, h, log,
+//	    api.WithReadiness(checker),
+//	    api.OnShutdown("worker pool", stopPool),
+//	    api.OnShutdown("queue", func(context.Context) error { return q.Close() }),
+//	    api.OnShutdown("database", func(context.Context) error { return db.Close() }))
+//	if err := srv.Run(ctx); err != nil {
+//	    return err
+//	}
+package api
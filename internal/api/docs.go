@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+
+	"task-queue/api/openapi"
+)
+
+// redocPage renders the OpenAPI document with Redoc
+const redocPage = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>Task Queue API</title>
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+</head>
+<body>
+  <redoc spec-url="/v1/openapi.json"></redoc>
+  <script src="https://cdn.redoc.ly/redoc/latest/bundles/redoc.standalone.js"></script>
+</body>
+</html>
+`
+
+// routeDocs registers the OpenAPI document and its page
+func (h *Handler) routeDocs() {
+	h.handle("GET /v1/openapi.json", h.openAPI)
+	h.handle("GET /v1/docs", h.docsPage)
+}
+
+// openAPI handles GET /v1/openapi.json
+func (h *Handler) openAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openapi.Document())
+}
+
+// docsPage handles GET /v1/docs
+func (h *Handler) docsPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(redocPage))
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"task-queue/api/openapi"
+	"task-queue/internal/eventbus"
+	"task-queue/internal/queue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPI_MatchesRoutes(t *testing.T) {
+	bus := eventbus.NewMemoryBus()
+	t.Cleanup(func() { _ = bus.Close() })
+
+	a := newTestAPI(WithQueueManager(queue.NewQueueManager()), WithEventBus(bus), WithDocs())
+
+	documented, err := openapi.Operations()
+	require.NoError(t, err)
+
+	routes := a.Routes()
+	for _, route := range routes {
+		assert.Contains(t, documented, route, "route %s is not in the OpenAPI document", route)
+	}
+
+	for _, operation := range documented {
+		assert.Contains(t, routes, operation, "documented operation %s has no handler", operation)
+	}
+}
+
+func TestOpenAPI_Served(t *testing.T) {
+	a := newTestAPI(WithDocs())
+
+	rec := a.do(http.MethodGet, "/v1/openapi.json", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var doc map[string]any
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&doc))
+	assert.Equal(t, "3.0.3", doc["openapi"])
+
+	rec = a.do(http.MethodGet, "/v1/docs", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `spec-url="/v1/openapi.json"`)
+}
+
+func TestOpenAPI_DisabledByDefault(t *testing.T) {
+	a := newTestAPI()
+
+	assert.Equal(t, http.StatusNotFound, a.do(http.MethodGet, "/v1/openapi.json", "").Code)
+	assert.Equal(t, http.StatusNotFound, a.do(http.MethodGet, "/v1/docs", "").Code)
+}
@@ -0,0 +1,283 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"time"
+
+	"task-queue/internal/eventbus"
+	"task-queue/internal/health"
+	"task-queue/internal/queue"
+	"task-queue/internal/service"
+	"task-queue/internal/tracing"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+	"task-queue/pkg/validation"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultMaxBodyBytes caps request bodies: the largest default payload plus
+// room for the rest of the job request
+const defaultMaxBodyBytes = validation.DefaultMaxPayloadBytes + 64<<10
+
+// Option configures a Handler
+type Option func(*Handler)
+
+// WithMaxBodyBytes caps the size of request bodies. Raise it along with
+// validation.WithMaxPayloadBytes.
+func WithMaxBodyBytes(n int64) Option {
+	return func(h *Handler) {
+		h.maxBodyBytes = n
+	}
+}
+
+// WithAuth authenticates every request with auth. The job endpoints then
+// require ScopeJobsRead or ScopeJobsWrite; without it they are open. The
+// admin endpoints always require ScopeAdmin.
+func WithAuth(auth Authenticator) Option {
+	return func(h *Handler) {
+		h.auth = auth
+	}
+}
+
+// WithQueueManager serves the /v1/admin endpoints over the queues of m.
+// They require a caller with ScopeAdmin.
+func WithQueueManager(m *queue.QueueManager) Option {
+	return func(h *Handler) {
+		h.queues = m
+	}
+}
+
+// WithAudit records admin actions, with the caller's identity, on audit
+func WithAudit(audit logger.AuditLogger) Option {
+	return func(h *Handler) {
+		h.audit = audit
+	}
+}
+
+// WithEventBus serves the /v1/jobs/{id}/watch and /v1/events streams of
+// the events published on bus
+func WithEventBus(bus eventbus.Bus) Option {
+	return func(h *Handler) {
+		h.events = bus
+	}
+}
+
+// WithHeartbeatInterval sets how often an idle event stream sends a
+// heartbeat comment. It defaults to DefaultHeartbeatInterval.
+func WithHeartbeatInterval(d time.Duration) Option {
+	return func(h *Handler) {
+		h.heartbeat = d
+	}
+}
+
+// WithHealth serves the liveness and readiness probes of checker on
+// /healthz and /readyz. Probes are neither authenticated nor rate limited.
+func WithHealth(checker *health.Checker) Option {
+	return func(h *Handler) {
+		h.health = checker
+	}
+}
+
+// WithDocs serves the OpenAPI document at /v1/openapi.json and a page
+// rendering it at /v1/docs, both without authentication
+func WithDocs() Option {
+	return func(h *Handler) {
+		h.docs = true
+	}
+}
+
+// WithTracing traces every request other than a health probe on tp as a
+// server span named after its route, continuing the trace of the caller's
+// traceparent header. The jobs a request submits join its trace.
+func WithTracing(tp trace.TracerProvider) Option {
+	return func(h *Handler) {
+		h.tracer = tp
+	}
+}
+
+// Handler serves the job API: submitting and listing jobs, reading their
+// status and audit trail, and cancelling them. With a queue manager it also
+// serves the admin API, and with an event bus the event streams.
+type Handler struct {
+	jobs          *service.JobService
+	queues        *queue.QueueManager
+	events        eventbus.Bus
+	health        *health.Checker
+	auth          Authenticator
+	limits        *rateLimits
+	audit         logger.AuditLogger
+	logger        logger.Logger
+	maxBodyBytes  int64
+	heartbeat     time.Duration
+	maxBatchItems int
+	maxBatchBytes int64
+	docs          bool
+	tracer        trace.TracerProvider
+	traced        http.Handler
+	mux           *http.ServeMux
+	routes        []string
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+// New creates the job API over jobs
+func New(jobs *service.JobService, log logger.Logger, opts ...Option) *Handler {
+	h := &Handler{
+		jobs:          jobs,
+		audit:         logger.NopAudit(),
+		logger:        log.Named("api"),
+		maxBodyBytes:  defaultMaxBodyBytes,
+		heartbeat:     DefaultHeartbeatInterval,
+		maxBatchItems: DefaultMaxBatchItems,
+		maxBatchBytes: DefaultMaxBatchBytes,
+		mux:           http.NewServeMux(),
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	h.handle("POST /v1/jobs", h.requireScope(ScopeJobsWrite, h.submitJob))
+	h.handle("POST /v1/jobs/batch", h.requireScope(ScopeJobsWrite, h.submitBatch))
+	h.handle("GET /v1/jobs", h.requireScope(ScopeJobsRead, h.listJobs))
+	h.handle("GET /v1/jobs/{id}", h.requireScope(ScopeJobsRead, h.getJob))
+	h.handle("DELETE /v1/jobs/{id}", h.requireScope(ScopeJobsWrite, h.cancelJob))
+	h.handle("POST /v1/jobs/{id}/replay", h.requireScope(ScopeJobsWrite, h.replayJob))
+	h.handle("GET /v1/jobs/{id}/events", h.requireScope(ScopeJobsRead, h.listEvents))
+	if h.queues != nil {
+		h.routeAdmin()
+	}
+
+	if h.events != nil {
+		h.routeStreams()
+	}
+
+	if h.docs {
+		h.routeDocs()
+	}
+
+	if h.tracer != nil {
+		h.traced = otelhttp.NewHandler(http.HandlerFunc(h.serve), "api",
+			otelhttp.WithTracerProvider(h.tracer),
+			otelhttp.WithPropagators(tracing.Propagator),
+			otelhttp.WithSpanNameFormatter(h.spanName),
+			otelhttp.WithFilter(func(r *http.Request) bool { return !isProbe(r) }))
+	}
+
+	return h
+}
+
+// ServeHTTP answers health probes directly; other requests are
+// authenticated and routed to their endpoint once they pass the rate limit
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.traced != nil {
+		h.traced.ServeHTTP(w, r)
+		return
+	}
+
+	h.serve(w, r)
+}
+
+// serve answers r, within its span when requests are traced. A panicking
+// endpoint is answered with a 500 problem rather than a dropped connection.
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request) {
+	r = r.WithContext(tracing.LogContext(r.Context()))
+	defer h.recoverPanic(w, r)
+
+	if h.health != nil && isProbe(r) {
+		h.health.Handler().ServeHTTP(w, r)
+		return
+	}
+
+	if h.auth != nil {
+		r = h.authenticate(r)
+	}
+
+	if h.limits != nil && !h.allowRequest(w, r) {
+		return
+	}
+
+	h.mux.ServeHTTP(w, r)
+}
+
+// recoverPanic answers r with the panic of its endpoint as a CodeInternal
+// problem. It must be deferred directly. http.ErrAbortHandler is passed on
+// so the server aborts the response as it asked.
+func (h *Handler) recoverPanic(w http.ResponseWriter, r *http.Request) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	if recovered == http.ErrAbortHandler {
+		panic(recovered)
+	}
+
+	h.fail(w, r, errors.FromPanic(recovered).WithOp("api.serve"))
+}
+
+// spanName names the span of r after the route it matches, such as
+// "GET /v1/jobs/{id}", or after its method when it matches none
+func (h *Handler) spanName(_ string, r *http.Request) string {
+	if _, pattern := h.mux.Handler(r); pattern != "" {
+		return pattern
+	}
+
+	return r.Method
+}
+
+// handle routes requests matching pattern to handler
+func (h *Handler) handle(pattern string, handler http.HandlerFunc) {
+	h.mux.HandleFunc(pattern, handler)
+	h.routes = append(h.routes, pattern)
+}
+
+// Routes returns the patterns of the registered endpoints, such as
+// "GET /v1/jobs/{id}", sorted
+func (h *Handler) Routes() []string {
+	routes := slices.Clone(h.routes)
+	slices.Sort(routes)
+	return routes
+}
+
+// isProbe reports whether r is a liveness or readiness probe
+func isProbe(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	return r.URL.Path == "/healthz" || r.URL.Path == "/readyz"
+}
+
+// jobID parses the {id} path segment of r
+func jobID(r *http.Request) (uuid.UUID, error) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		return uuid.Nil, errors.Validation("invalid job ID %q", r.PathValue("id")).
+			WithOp("api.jobID")
+	}
+
+	return id, nil
+}
+
+// writeJSON writes v as a JSON response with status
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// fail writes err as a problem response, logging server errors
+func (h *Handler) fail(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.GetHTTPStatus(err) >= http.StatusInternalServerError {
+		h.logger.WithContext(r.Context()).Error("request failed",
+			"method", r.Method, "path", r.URL.Path, "error", err)
+	}
+
+	errors.WriteProblem(w, err, r)
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// eventsResponse is the body of GET /v1/jobs/{id}/events
+type eventsResponse struct {
+	JobID  uuid.UUID         `json:"job_id"`
+	Events []models.JobEvent `json:"events"`
+}
+
+// decodeJSON decodes the body of r into v, reading at most limit bytes
+func decodeJSON(w http.ResponseWriter, r *http.Request, limit int64, v any) error {
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, limit)).Decode(v)
+
+	var tooLarge *http.MaxBytesError
+	switch {
+	case err == nil:
+		return nil
+
+	case stderrors.As(err, &tooLarge):
+		return errors.Validation("request body exceeds %d bytes", limit).
+			WithStatusCode(http.StatusRequestEntityTooLarge).
+			WithOp("api.decodeJSON")
+	}
+
+	return errors.Wrap(err, "invalid request body").
+		WithCode(errors.CodeValidation).
+		WithOp("api.decodeJSON")
+}
+
+// submitJob handles POST /v1/jobs: it validates the job request, stores the
+// job, and enqueues it
+func (h *Handler) submitJob(w http.ResponseWriter, r *http.Request) {
+	var req models.JobRequest
+	if err := decodeJSON(w, r, h.maxBodyBytes, &req); err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	job, err := h.jobs.Submit(r.Context(), &req)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/v1/jobs/"+job.ID.String())
+	writeJSON(w, http.StatusCreated, job)
+}
+
+// getJob handles GET /v1/jobs/{id}
+func (h *Handler) getJob(w http.ResponseWriter, r *http.Request) {
+	id, err := jobID(r)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	job, err := h.jobs.Get(r.Context(), id)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
+
+// cancelJob handles DELETE /v1/jobs/{id}: a job that has not started is
+// removed from the queue and marked cancelled. Running and finished jobs
+// cannot be cancelled.
+func (h *Handler) cancelJob(w http.ResponseWriter, r *http.Request) {
+	id, err := jobID(r)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	job, err := h.jobs.Cancel(r.Context(), id)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
+
+// replayJob handles POST /v1/jobs/{id}/replay: a finished job is submitted
+// again as a new job, with the priority, scheduled_at, and metadata
+// additions of an optional models.ReplayOverrides body
+func (h *Handler) replayJob(w http.ResponseWriter, r *http.Request) {
+	id, err := jobID(r)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	var overrides models.ReplayOverrides
+	if r.ContentLength != 0 {
+		if err := decodeJSON(w, r, h.maxBodyBytes, &overrides); err != nil {
+			h.fail(w, r, err)
+			return
+		}
+	}
+
+	job, err := h.jobs.ReplayJob(r.Context(), id, overrides)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/v1/jobs/"+job.ID.String())
+	writeJSON(w, http.StatusCreated, job)
+}
+
+// listEvents handles GET /v1/jobs/{id}/events, returning the job's audit
+// trail oldest first
+func (h *Handler) listEvents(w http.ResponseWriter, r *http.Request) {
+	id, err := jobID(r)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	events, err := h.jobs.Events(r.Context(), id)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, eventsResponse{JobID: id, Events: events})
+}
@@ -0,0 +1,302 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/internal/service"
+	"task-queue/internal/storage"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAPI is a Handler over in-memory stores and queue
+type testAPI struct {
+	*Handler
+	store  *storage.MemoryJobStore
+	events *storage.MemoryEventStore
+	queue  *queue.MemoryQueue
+}
+
+func newTestAPI(opts ...Option) *testAPI {
+	a := &testAPI{
+		store:  storage.NewMemoryJobStore(),
+		events: storage.NewMemoryEventStore(),
+		queue:  queue.NewMemoryQueue(queue.Config{}),
+	}
+
+	log := logger.NewNop()
+	a.Handler = New(service.New(a.store, a.events, a.queue, log), log, opts...)
+	return a
+}
+
+// do sends a request to the API and returns the recorded response
+func (a *testAPI) do(method, path, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return rec
+}
+
+// submit creates a job through the API
+func (a *testAPI) submit(t *testing.T) *models.Job {
+	t.Helper()
+
+	rec := a.do(http.MethodPost, "/v1/jobs", `{"type":"email_send","payload":{"to":"a@example.com"}}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var job models.Job
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&job))
+	return &job
+}
+
+// decodeProblem decodes a problem details response
+func decodeProblem(t *testing.T, rec *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+
+	assert.Equal(t, errors.ProblemContentType, rec.Header().Get("Content-Type"))
+
+	var problem map[string]any
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+	return problem
+}
+
+func TestSubmitJob(t *testing.T) {
+	a := newTestAPI()
+
+	rec := a.do(http.MethodPost, "/v1/jobs",
+		`{"type":"email_send","payload":{"to":"a@example.com"},"priority":2,"max_retries":5,"metadata":{"tenant":"acme"}}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var job models.Job
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&job))
+	assert.Equal(t, "/v1/jobs/"+job.ID.String(), rec.Header().Get("Location"))
+	assert.Equal(t, models.JobStatusPending, job.Status)
+	assert.Equal(t, models.JobPriorityHigh, job.Priority)
+	assert.Equal(t, 5, job.MaxRetries)
+
+	stored, err := a.store.Get(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", stored.Metadata["tenant"])
+
+	queued, err := a.queue.Dequeue(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, queued)
+	assert.Equal(t, job.ID, queued.ID)
+
+	events := a.events.Events(job.ID)
+	require.Len(t, events, 1)
+	assert.Equal(t, logger.AuditJobCreated, events[0].EventType)
+
+	rec = a.do(http.MethodPost, "/v1/jobs", `{"type":"email_send","payload":{},"priority":"critical"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&job))
+	assert.Equal(t, models.JobPriorityCritical, job.Priority, "priorities are accepted by name")
+}
+
+func TestSubmitJob_Invalid(t *testing.T) {
+	a := newTestAPI()
+
+	rec := a.do(http.MethodPost, "/v1/jobs", `{"type":"","payload":{},"max_retries":50}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	problem := decodeProblem(t, rec)
+	assert.Equal(t, string(errors.CodeValidation), problem["code"])
+
+	var fields []string
+	for _, failure := range problem["errors"].([]any) {
+		fields = append(fields, failure.(map[string]any)["field"].(string))
+	}
+	assert.ElementsMatch(t, []string{"type", "max_retries"}, fields)
+
+	rec = a.do(http.MethodPost, "/v1/jobs", `{"type":`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	size, err := a.queue.Size(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, size, "invalid jobs were enqueued")
+}
+
+func TestSubmitJob_BodyTooLarge(t *testing.T) {
+	a := newTestAPI(WithMaxBodyBytes(64))
+
+	rec := a.do(http.MethodPost, "/v1/jobs",
+		`{"type":"email_send","payload":{"body":"`+strings.Repeat("x", 128)+`"}}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func TestGetJob(t *testing.T) {
+	a := newTestAPI()
+	job := a.submit(t)
+
+	rec := a.do(http.MethodGet, "/v1/jobs/"+job.ID.String(), "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var got models.Job
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	assert.Equal(t, job.ID, got.ID)
+	assert.Equal(t, "email_send", got.Type)
+
+	rec = a.do(http.MethodGet, "/v1/jobs/6f1c2c8e-0d5b-4a44-9a0e-4c1f0f3b2a11", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, string(errors.CodeNotFound), decodeProblem(t, rec)["code"])
+
+	rec = a.do(http.MethodGet, "/v1/jobs/not-a-uuid", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestCancelJob(t *testing.T) {
+	a := newTestAPI()
+	job := a.submit(t)
+
+	rec := a.do(http.MethodDelete, "/v1/jobs/"+job.ID.String(), "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var cancelled models.Job
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&cancelled))
+	assert.Equal(t, models.JobStatusCancelled, cancelled.Status)
+	assert.NotNil(t, cancelled.CompletedAt)
+
+	stored, err := a.store.Get(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusCancelled, stored.Status)
+
+	queued, err := a.queue.Dequeue(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, queued, "cancelled job is still queued")
+
+	rec = a.do(http.MethodDelete, "/v1/jobs/"+job.ID.String(), "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestCancelJob_Running(t *testing.T) {
+	a := newTestAPI()
+	job := a.submit(t)
+
+	job.Status = models.JobStatusRunning
+	require.NoError(t, a.store.UpdateStatus(context.Background(), job))
+
+	rec := a.do(http.MethodDelete, "/v1/jobs/"+job.ID.String(), "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, string(errors.CodeConflict), decodeProblem(t, rec)["code"])
+
+	stored, err := a.store.Get(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusRunning, stored.Status)
+}
+
+func TestReplayJob(t *testing.T) {
+	a := newTestAPI()
+	job := a.submit(t)
+	job.Status = models.JobStatusDead
+	require.NoError(t, a.store.UpdateStatus(context.Background(), job))
+
+	rec := a.do(http.MethodPost, "/v1/jobs/"+job.ID.String()+"/replay",
+		`{"priority":"high","metadata":{"ticket":"OPS-12"}}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var replay models.Job
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&replay))
+	assert.NotEqual(t, job.ID, replay.ID)
+	assert.Equal(t, "/v1/jobs/"+replay.ID.String(), rec.Header().Get("Location"))
+	assert.Equal(t, job.Type, replay.Type)
+	assert.JSONEq(t, string(job.Payload), string(replay.Payload))
+	assert.Equal(t, models.JobPriorityHigh, replay.Priority)
+	assert.Equal(t, models.JobStatusPending, replay.Status)
+	assert.Equal(t, job.ID.String(), replay.Metadata[models.ReplayMetadataKey])
+	assert.Equal(t, "OPS-12", replay.Metadata["ticket"])
+
+	queued, err := a.queue.Dequeue(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, queued)
+	assert.Equal(t, replay.ID, queued.ID)
+
+	events, err := a.events.ListEvents(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, logger.AuditJobReplayed, events[len(events)-1].EventType)
+	assert.Equal(t, replay.ID.String(), events[len(events)-1].EventData["replay_id"])
+
+	events, err = a.events.ListEvents(context.Background(), replay.ID)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, logger.AuditJobCreated, events[0].EventType)
+	assert.Equal(t, job.ID.String(), events[0].EventData[models.ReplayMetadataKey])
+}
+
+func TestReplayJob_WithoutBody(t *testing.T) {
+	a := newTestAPI()
+	job := a.submit(t)
+	job.Status = models.JobStatusCompleted
+	require.NoError(t, a.store.UpdateStatus(context.Background(), job))
+
+	rec := a.do(http.MethodPost, "/v1/jobs/"+job.ID.String()+"/replay", "")
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var replay models.Job
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&replay))
+	assert.Equal(t, job.Priority, replay.Priority)
+	assert.Equal(t, job.MaxRetries, replay.MaxRetries)
+}
+
+func TestReplayJob_NotFinished(t *testing.T) {
+	a := newTestAPI()
+	job := a.submit(t)
+
+	rec := a.do(http.MethodPost, "/v1/jobs/"+job.ID.String()+"/replay", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, string(errors.CodeConflict), decodeProblem(t, rec)["code"])
+
+	rec = a.do(http.MethodPost, "/v1/jobs/6f1c2c8e-0d5b-4a44-9a0e-4c1f0f3b2a11/replay", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestListEvents(t *testing.T) {
+	a := newTestAPI()
+	job := a.submit(t)
+	require.Equal(t, http.StatusOK, a.do(http.MethodDelete, "/v1/jobs/"+job.ID.String(), "").Code)
+
+	rec := a.do(http.MethodGet, "/v1/jobs/"+job.ID.String()+"/events", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var body eventsResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.Equal(t, job.ID, body.JobID)
+	require.Len(t, body.Events, 2)
+	assert.Equal(t, logger.AuditJobCreated, body.Events[0].EventType)
+	assert.Equal(t, logger.AuditJobCancelled, body.Events[1].EventType)
+	assert.Equal(t, string(models.JobStatusPending), body.Events[1].EventData["previous_status"])
+
+	rec = a.do(http.MethodGet, "/v1/jobs/6f1c2c8e-0d5b-4a44-9a0e-4c1f0f3b2a11/events", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+// panickingStore is a job store whose Get panics
+type panickingStore struct {
+	*storage.MemoryJobStore
+}
+
+func (s panickingStore) Get(context.Context, uuid.UUID) (*models.Job, error) {
+	panic("store corrupted")
+}
+
+func TestHandler_RecoversPanics(t *testing.T) {
+	log := logger.NewNop()
+	store := panickingStore{MemoryJobStore: storage.NewMemoryJobStore()}
+	h := New(service.New(store, storage.NewMemoryEventStore(), queue.NewMemoryQueue(queue.Config{}), log), log)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/jobs/"+uuid.NewString(), nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	problem := decodeProblem(t, rec)
+	assert.Equal(t, string(errors.CodeInternal), problem["code"])
+}
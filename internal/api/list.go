@@ -0,0 +1,195 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/internal/service"
+	"task-queue/pkg/validation"
+)
+
+// maxSearchLength caps the q parameter
+const maxSearchLength = 200
+
+// jobView is the wire shape of a listed job, with the priority by name
+type jobView struct {
+	*models.Job
+	Priority string `json:"priority"`
+}
+
+// listResponse is the body of GET /v1/jobs. NextCursor is null on the last
+// page.
+type listResponse struct {
+	Items         []jobView `json:"items"`
+	NextCursor    *string   `json:"next_cursor"`
+	TotalEstimate int64     `json:"total_estimate"`
+}
+
+// listJobs handles GET /v1/jobs: the jobs matching the query parameters, one
+// page at a time
+func (h *Handler) listJobs(w http.ResponseWriter, r *http.Request) {
+	query, err := parseListQuery(r.URL.Query())
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	page, err := h.jobs.List(r.Context(), query)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	resp := listResponse{
+		Items:         make([]jobView, len(page.Items)),
+		TotalEstimate: page.TotalEstimate,
+	}
+
+	for i, job := range page.Items {
+		resp.Items[i] = jobView{Job: job, Priority: job.Priority.Name()}
+	}
+
+	if page.NextCursor != "" {
+		resp.NextCursor = &page.NextCursor
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// parseListQuery decodes the listing parameters into a service query.
+// Every parameter is checked and all failures are reported together, each
+// under its parameter name.
+func parseListQuery(query url.Values) (service.ListQuery, error) {
+	var filter models.JobFilter
+	statuses := listValues(query, "status")
+	types := listValues(query, "type")
+	priorities := listValues(query, "priority")
+	tags := listValues(query, "tag")
+	metadata := listValues(query, "metadata")
+
+	sort := query.Get("sort")
+	if sort == "" {
+		sort = service.DefaultSort
+	}
+
+	err := validation.ValidateAll(
+		validation.NewField("status", statuses, validation.Each(validation.JobStatus())),
+		validation.NewField("type", types, validation.Each(validation.JobType())),
+		validation.NewField("priority", priorities, validation.Each(validation.JobPriority())),
+		validation.NewField("created_after", query.Get("created_after"), parseTime(&filter.CreatedAfter)),
+		validation.NewField("created_before", query.Get("created_before"), parseTime(&filter.CreatedBefore)),
+		validation.NewField("tag", tags, validation.Each(validation.Length(1, 64))),
+		validation.NewField("metadata", metadata, validation.Each(parseMetadata(&filter.Metadata))),
+		validation.NewField("q", query.Get("q"), validation.Length(0, maxSearchLength)),
+		validation.NewField("sort", sort, validation.OneOfStrings(service.SortNames...)),
+		validation.NewField("limit", query.Get("limit"), parseLimit(&filter.Limit)),
+		validation.NewField("cursor", query.Get("cursor"), checkCursor(sort)),
+	)
+	if err != nil {
+		return service.ListQuery{}, err
+	}
+
+	for _, status := range statuses {
+		filter.Statuses = append(filter.Statuses, models.JobStatus(status))
+	}
+
+	for _, name := range priorities {
+		priority, _ := models.ParseJobPriority(name)
+		filter.Priorities = append(filter.Priorities, priority)
+	}
+
+	filter.Types = types
+	filter.Tags = tags
+	filter.Search = query.Get("q")
+	return service.ListQuery{Filter: filter, Sort: sort, Cursor: query.Get("cursor")}, nil
+}
+
+// listValues returns the values of a repeatable parameter, each of which
+// may also be a comma-separated list
+func listValues(query url.Values, name string) []string {
+	var values []string
+	for _, value := range query[name] {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+
+	return values
+}
+
+// parseTime returns a validator that parses an RFC 3339 timestamp into dst.
+// An empty value leaves dst unset.
+func parseTime(dst **time.Time) validation.Validator {
+	return validation.ValidatorFunc(func(value any) error {
+		s := value.(string)
+		if s == "" {
+			return nil
+		}
+
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return fmt.Errorf("must be an RFC 3339 timestamp")
+		}
+
+		*dst = &t
+		return nil
+	})
+}
+
+// parseMetadata returns a validator that parses a key:value metadata match
+// into dst
+func parseMetadata(dst *map[string]string) validation.Validator {
+	return validation.ValidatorFunc(func(value any) error {
+		key, v, ok := strings.Cut(value.(string), ":")
+		if !ok || key == "" {
+			return fmt.Errorf("must be a key:value pair")
+		}
+
+		if *dst == nil {
+			*dst = make(map[string]string)
+		}
+
+		(*dst)[key] = v
+		return nil
+	})
+}
+
+// parseLimit returns a validator that parses a positive page size into
+// dst. An empty value leaves dst unchanged.
+func parseLimit(dst *int) validation.Validator {
+	return validation.ValidatorFunc(func(value any) error {
+		s := value.(string)
+		if s == "" {
+			return nil
+		}
+
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit < 1 {
+			return fmt.Errorf("must be a positive integer")
+		}
+
+		*dst = limit
+		return nil
+	})
+}
+
+// checkCursor returns a validator that checks a cursor was issued for sort,
+// so its failure is reported with the other parameters. An empty value
+// starts from the first page.
+func checkCursor(sort string) validation.Validator {
+	return validation.ValidatorFunc(func(value any) error {
+		if value.(string) == "" {
+			return nil
+		}
+
+		_, err := service.DecodeCursor(sort, value.(string))
+		return err
+	})
+}
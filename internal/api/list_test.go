@@ -0,0 +1,192 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listPage is the decoded body of a listing
+type listPage struct {
+	Items []struct {
+		ID       uuid.UUID        `json:"id"`
+		Type     string           `json:"type"`
+		Status   models.JobStatus `json:"status"`
+		Priority string           `json:"priority"`
+	} `json:"items"`
+	NextCursor    *string `json:"next_cursor"`
+	TotalEstimate int64   `json:"total_estimate"`
+}
+
+// seed stores a job created at created, modified by opts
+func (a *testAPI) seed(t *testing.T, created time.Time, opts ...func(*models.Job)) *models.Job {
+	t.Helper()
+
+	job := models.NewJob("send_email", json.RawMessage(`{}`), models.JobPriorityNormal)
+	job.CreatedAt = created
+	job.UpdatedAt = created
+	for _, opt := range opts {
+		opt(job)
+	}
+
+	require.NoError(t, a.store.Create(context.Background(), job))
+	return job
+}
+
+// list requests a page of jobs matching query
+func (a *testAPI) list(t *testing.T, query url.Values) listPage {
+	t.Helper()
+
+	rec := a.do(http.MethodGet, "/v1/jobs?"+query.Encode(), "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var page listPage
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&page))
+	return page
+}
+
+func TestListJobs_CombinedFilters(t *testing.T) {
+	a := newTestAPI()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	failed := func(job *models.Job) {
+		reason := "smtp: connection timeout"
+		job.Status = models.JobStatusFailed
+		job.Error = &reason
+	}
+	tagged := func(job *models.Job) {
+		job.Metadata["tenant"] = "acme"
+		job.Metadata["tags"] = []any{"billing", "urgent"}
+	}
+	high := func(job *models.Job) { job.Priority = models.JobPriorityHigh }
+
+	want := a.seed(t, base.Add(2*time.Hour), failed, tagged, high)
+	a.seed(t, base.Add(3*time.Hour), failed, tagged)      // wrong priority
+	a.seed(t, base.Add(-time.Hour), failed, tagged, high) // too old
+	a.seed(t, base.Add(4*time.Hour), tagged, high)        // not failed
+	a.seed(t, base.Add(5*time.Hour), failed, high)        // no tags or tenant
+	a.seed(t, base.Add(6*time.Hour), failed, tagged, high, func(job *models.Job) {
+		job.Type = "send_sms"
+	})
+
+	page := a.list(t, url.Values{
+		"status":        {"failed,dead"},
+		"type":          {"send_email"},
+		"priority":      {"high", "critical"},
+		"created_after": {base.Format(time.RFC3339)},
+		"tag":           {"urgent"},
+		"metadata":      {"tenant:acme"},
+		"q":             {"TIMEOUT"},
+	})
+
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, want.ID, page.Items[0].ID)
+	assert.Equal(t, "high", page.Items[0].Priority)
+	assert.EqualValues(t, 1, page.TotalEstimate)
+	assert.Nil(t, page.NextCursor)
+}
+
+func TestListJobs_CursorIteration(t *testing.T) {
+	a := newTestAPI()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var want []uuid.UUID
+	for i := range 7 {
+		job := a.seed(t, base.Add(time.Duration(i)*time.Minute))
+		want = append([]uuid.UUID{job.ID}, want...)
+	}
+
+	// Jobs created in the same instant are listed by descending ID
+	tied := []uuid.UUID{a.seed(t, base.Add(-time.Minute)).ID, a.seed(t, base.Add(-time.Minute)).ID}
+	if bytes.Compare(tied[0][:], tied[1][:]) < 0 {
+		tied[0], tied[1] = tied[1], tied[0]
+	}
+	want = append(want, tied...)
+
+	var (
+		got   []uuid.UUID
+		pages int
+	)
+	query := url.Values{"limit": {"3"}}
+	for {
+		page := a.list(t, query)
+		pages++
+		assert.EqualValues(t, 9, page.TotalEstimate)
+
+		for _, item := range page.Items {
+			got = append(got, item.ID)
+		}
+
+		if page.NextCursor == nil {
+			break
+		}
+
+		query.Set("cursor", *page.NextCursor)
+	}
+
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, want, got)
+
+	query = url.Values{"limit": {"3"}, "sort": {"created_at"}}
+	first := a.list(t, query)
+	require.NotNil(t, first.NextCursor)
+
+	query.Set("sort", "-updated_at")
+	query.Set("cursor", *first.NextCursor)
+	rec := a.do(http.MethodGet, "/v1/jobs?"+query.Encode(), "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "cursor reused with another sort")
+}
+
+func TestListJobs_LimitCapped(t *testing.T) {
+	a := newTestAPI()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := range service.MaxListLimit + 10 {
+		a.seed(t, base.Add(time.Duration(i)*time.Second))
+	}
+
+	page := a.list(t, url.Values{"limit": {"1000"}})
+	assert.Len(t, page.Items, service.MaxListLimit)
+	assert.NotNil(t, page.NextCursor)
+
+	page = a.list(t, url.Values{})
+	assert.Len(t, page.Items, service.DefaultListLimit)
+}
+
+func TestListJobs_InvalidParams(t *testing.T) {
+	a := newTestAPI()
+
+	query := url.Values{
+		"status":        {"failed,paused"},
+		"priority":      {"urgent"},
+		"created_after": {"yesterday"},
+		"metadata":      {"tenant"},
+		"sort":          {"priority"},
+		"limit":         {"0"},
+		"cursor":        {"not-a-cursor"},
+	}
+	rec := a.do(http.MethodGet, "/v1/jobs?"+query.Encode(), "")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	problem := decodeProblem(t, rec)
+
+	var fields []string
+	for _, failure := range problem["errors"].([]any) {
+		fields = append(fields, failure.(map[string]any)["field"].(string))
+	}
+
+	assert.ElementsMatch(t, []string{
+		"status[1]", "priority[0]", "created_after", "metadata[0]", "sort", "limit", "cursor",
+	}, fields, fmt.Sprint(problem["errors"]))
+}
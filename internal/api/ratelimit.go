@@ -0,0 +1,144 @@
+package api
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/ratelimit"
+	"task-queue/pkg/errors"
+)
+
+// rateLimits are the limits applied by a Handler's rate limiter
+type rateLimits struct {
+	limiter ratelimit.Limiter
+	rule    ratelimit.Rule
+	routes  map[string]ratelimit.Rule
+	proxies []netip.Prefix
+}
+
+// WithRateLimit limits each client to the rates of cfg, keeping its token
+// buckets in limiter. Clients are identified by API key when they
+// authenticate with the WithAuth authenticator, and by IP address
+// otherwise. Invalid trusted proxies are logged and skipped, leaving the
+// valid ones trusted; config.Validate reports them.
+func WithRateLimit(limiter ratelimit.Limiter, cfg config.RateLimitConfig) Option {
+	return func(h *Handler) {
+		limits := &rateLimits{
+			limiter: limiter,
+			rule:    ratelimit.Rule{Rate: cfg.RequestsPerSecond, Burst: cfg.Burst},
+			routes:  make(map[string]ratelimit.Rule, len(cfg.Routes)),
+		}
+
+		for _, route := range cfg.Routes {
+			pattern := strings.ToUpper(route.Method) + " " + route.Path
+			limits.routes[pattern] = ratelimit.Rule{Rate: route.RequestsPerSecond, Burst: route.Burst}
+		}
+
+		proxies, err := cfg.TrustedPrefixes()
+		if err != nil {
+			h.logger.Warn("ignoring invalid trusted proxies", "error", err)
+		}
+
+		limits.proxies = proxies
+		h.limits = limits
+	}
+}
+
+// allowRequest takes a token for r from its client's bucket, setting
+// X-RateLimit-Remaining. A denied request is answered with a 429 and
+// reported false. The limiter failing lets the request through.
+func (h *Handler) allowRequest(w http.ResponseWriter, r *http.Request) bool {
+	key := h.clientKey(r)
+	rule := h.limits.rule
+
+	// An overridden route has a bucket of its own, so it neither spends nor
+	// is starved by the client's other requests
+	_, pattern := h.mux.Handler(r)
+	if routeRule, ok := h.limits.routes[pattern]; ok {
+		key += "|" + pattern
+		rule = routeRule
+	}
+
+	result, err := h.limits.limiter.Allow(r.Context(), key, rule)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("rate limiter failed", "error", err)
+		return true
+	}
+
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	if result.Allowed {
+		return true
+	}
+
+	retryAfter := max(1, int(math.Ceil(result.RetryAfter.Seconds())))
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	h.fail(w, r, errors.RateLimited("rate limit exceeded, retry in %s",
+		time.Duration(retryAfter)*time.Second).
+		WithMetadata("retry_after", retryAfter).
+		WithOp("api.rateLimit"))
+	return false
+}
+
+// clientKey identifies the client of r: its principal when r was
+// authenticated, else its IP address
+func (h *Handler) clientKey(r *http.Request) string {
+	if principal, ok := PrincipalFromContext(r.Context()); ok {
+		return "key:" + principal.Name
+	}
+
+	return "ip:" + clientIP(r, h.limits.proxies)
+}
+
+// clientIP returns the address r came from. When the peer is a trusted
+// proxy, X-Forwarded-For is read from the right, skipping trusted proxies,
+// so a client cannot choose its address by sending the header itself.
+func clientIP(r *http.Request, trusted []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return host
+	}
+
+	var forwarded []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		forwarded = append(forwarded, strings.Split(header, ",")...)
+	}
+
+	for _, hop := range slices.Backward(forwarded) {
+		if !isTrusted(addr, trusted) {
+			break
+		}
+
+		next, err := netip.ParseAddr(strings.TrimSpace(hop))
+		if err != nil {
+			break
+		}
+
+		addr = next
+	}
+
+	return addr.Unmap().String()
+}
+
+// isTrusted reports whether addr is in one of the trusted prefixes
+func isTrusted(addr netip.Addr, trusted []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"task-queue/internal/config"
+	"task-queue/internal/ratelimit"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRateLimit allows two requests per client, refilling one a second,
+// and one job submission
+var testRateLimit = config.RateLimitConfig{
+	Enabled:           true,
+	RequestsPerSecond: 1,
+	Burst:             2,
+	TrustedProxies:    []string{"10.0.0.0/8"},
+	Routes: []config.RouteRateLimitConfig{
+		{Method: "post", Path: "/v1/jobs", RequestsPerSecond: 1, Burst: 1},
+	},
+}
+
+// limitedAPI is a test API limited by testRateLimit
+func limitedAPI(limiter ratelimit.Limiter, opts ...Option) *testAPI {
+	return newTestAPI(append(opts, WithRateLimit(limiter, testRateLimit))...)
+}
+
+// get sends a GET to path from remote, with extra headers as name/value
+// pairs
+func (a *testAPI) get(path, remote string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remote
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Add(headers[i], headers[i+1])
+	}
+
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimit_BurstExhaustion(t *testing.T) {
+	a := limitedAPI(ratelimit.NewMemoryLimiter())
+
+	first := a.get("/v1/jobs", "192.0.2.1:5000")
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "1", first.Header().Get("X-RateLimit-Remaining"))
+
+	second := a.get("/v1/jobs", "192.0.2.1:5000")
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, "0", second.Header().Get("X-RateLimit-Remaining"))
+
+	denied := a.get("/v1/jobs", "192.0.2.1:5000")
+	require.Equal(t, http.StatusTooManyRequests, denied.Code)
+	assert.Equal(t, "1", denied.Header().Get("Retry-After"))
+	assert.Equal(t, "0", denied.Header().Get("X-RateLimit-Remaining"))
+
+	problem := decodeProblem(t, denied)
+	assert.Equal(t, string(errors.CodeRateLimit), problem["code"])
+	assert.EqualValues(t, http.StatusTooManyRequests, problem["status"])
+}
+
+func TestRateLimit_PerKeyIsolation(t *testing.T) {
+	readers := []string{ScopeJobsRead}
+	a := limitedAPI(ratelimit.NewMemoryLimiter(), WithAuth(StaticKeys{
+		"key-a": {Name: "a", Scopes: readers},
+		"key-b": {Name: "b", Scopes: readers},
+	}))
+
+	for range 2 {
+		require.Equal(t, http.StatusOK, a.get("/v1/jobs", "192.0.2.1:5000", "Authorization", "Bearer key-a").Code)
+	}
+
+	assert.Equal(t, http.StatusTooManyRequests,
+		a.get("/v1/jobs", "192.0.2.2:5000", "Authorization", "Bearer key-a").Code,
+		"an API key is limited wherever it calls from")
+	assert.Equal(t, http.StatusOK,
+		a.get("/v1/jobs", "192.0.2.1:5000", "Authorization", "Bearer key-b").Code)
+	assert.Equal(t, http.StatusUnauthorized, a.get("/v1/jobs", "192.0.2.1:5000").Code,
+		"anonymous callers are limited by address")
+	assert.Equal(t, http.StatusUnauthorized,
+		a.get("/v1/jobs", "192.0.2.1:5000", "Authorization", "Bearer unknown").Code,
+		"invalid keys fall back to the address")
+	assert.Equal(t, http.StatusTooManyRequests, a.get("/v1/jobs", "192.0.2.1:5000").Code)
+}
+
+func TestRateLimit_RouteOverride(t *testing.T) {
+	a := limitedAPI(ratelimit.NewMemoryLimiter())
+	submit := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v1/jobs",
+			strings.NewReader(`{"type":"email_send","payload":{}}`))
+		rec := httptest.NewRecorder()
+		a.ServeHTTP(rec, req)
+		return rec
+	}
+
+	created := submit()
+	require.Equal(t, http.StatusCreated, created.Code, created.Body.String())
+	assert.Equal(t, "0", created.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, http.StatusTooManyRequests, submit().Code)
+
+	assert.Equal(t, http.StatusOK, a.get("/v1/jobs", httptest.DefaultRemoteAddr).Code,
+		"other routes keep their own bucket")
+}
+
+func TestClientIP(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	tests := []struct {
+		name      string
+		remote    string
+		forwarded []string
+		want      string
+	}{
+		{name: "direct", remote: "192.0.2.1:5000", want: "192.0.2.1"},
+		{name: "untrusted peer", remote: "192.0.2.1:5000", forwarded: []string{"198.51.100.7"}, want: "192.0.2.1"},
+		{name: "trusted proxy", remote: "10.0.0.1:5000", forwarded: []string{"198.51.100.7"}, want: "198.51.100.7"},
+		{name: "spoofed prefix", remote: "10.0.0.1:5000", forwarded: []string{"203.0.113.9, 198.51.100.7"}, want: "198.51.100.7"},
+		{name: "proxy chain", remote: "10.0.0.1:5000", forwarded: []string{"198.51.100.7, 10.0.0.2", "10.0.0.3"}, want: "198.51.100.7"},
+		{name: "garbage hop", remote: "10.0.0.1:5000", forwarded: []string{"198.51.100.7, nonsense"}, want: "10.0.0.1"},
+		{name: "ipv6", remote: "[2001:db8::1]:5000", want: "2001:db8::1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			for _, header := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", header)
+			}
+
+			assert.Equal(t, tt.want, clientIP(req, trusted))
+		})
+	}
+}
+
+func TestRateLimit_InvalidProxyKeepsValidOnes(t *testing.T) {
+	cfg := testRateLimit
+	cfg.TrustedProxies = []string{"nonsense", "10.0.0.0/8"}
+	a := newTestAPI(WithRateLimit(ratelimit.NewMemoryLimiter(), cfg))
+
+	for _, client := range []string{"198.51.100.7", "198.51.100.8"} {
+		for range 2 {
+			assert.Equal(t, http.StatusOK, a.get("/v1/jobs", "10.0.0.1:5000", "X-Forwarded-For", client).Code,
+				"clients behind the trusted proxy have their own buckets")
+		}
+	}
+}
+
+func TestRateLimit_RedisBacked(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	newReplica := func() *testAPI {
+		limiter, err := ratelimit.NewRedisLimiter(client, "test:ratelimit", logger.NewNop())
+		require.NoError(t, err)
+		return limitedAPI(limiter)
+	}
+
+	first, second := newReplica(), newReplica()
+	assert.Equal(t, http.StatusOK, first.get("/v1/jobs", "192.0.2.1:5000").Code)
+	assert.Equal(t, http.StatusOK, second.get("/v1/jobs", "192.0.2.1:5000").Code)
+
+	denied := first.get("/v1/jobs", "192.0.2.1:5000")
+	assert.Equal(t, http.StatusTooManyRequests, denied.Code, "the replicas share the client's bucket")
+	assert.Equal(t, "1", denied.Header().Get("Retry-After"))
+	assert.True(t, server.Exists("test:ratelimit:ip:192.0.2.1"))
+}
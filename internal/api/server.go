@@ -0,0 +1,307 @@
+package api
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/health"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+)
+
+// ServerOption configures a Server
+type ServerOption func(*Server)
+
+// WithReadiness fails the probes of checker as soon as shutdown begins, so
+// load balancers stop sending requests before the server stops taking them
+func WithReadiness(checker *health.Checker) ServerOption {
+	return func(s *Server) {
+		s.health = checker
+	}
+}
+
+// WithDrainDelay sets how long the server keeps serving after its
+// readiness fails, for load balancers to notice. It defaults to
+// cfg.DrainDelay.
+func WithDrainDelay(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.drainDelay = d
+	}
+}
+
+// WithSignals stops Run when signals delivers instead of on SIGTERM or
+// SIGINT. A nil channel leaves only the context to stop it.
+func WithSignals(signals <-chan os.Signal) ServerOption {
+	return func(s *Server) {
+		s.signals = signals
+		s.customSignals = true
+	}
+}
+
+// OnShutdown adds a step run once the HTTP server has stopped, such as
+// draining the worker pool or closing the queue and database. Steps run in
+// the order they were added, each bounded by cfg.ShutdownTimeout, and a
+// failed step does not stop the ones after it.
+func OnShutdown(name string, fn func(ctx context.Context) error) ServerOption {
+	return func(s *Server) {
+		s.steps = append(s.steps, shutdownStep{name: name, fn: fn})
+	}
+}
+
+// shutdownStep is a step of a Server's shutdown
+type shutdownStep struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Server runs the HTTP API and its orderly shutdown: on a signal or a
+// canceled context it fails readiness, waits out the drain delay, stops
+// taking requests, ends event streams, waits up to cfg.ShutdownTimeout for
+// in-flight requests, and runs the OnShutdown steps
+type Server struct {
+	cfg           config.ServerConfig
+	handler       http.Handler
+	health        *health.Checker
+	drainDelay    time.Duration
+	signals       <-chan os.Signal
+	customSignals bool
+	steps         []shutdownStep
+	logger        logger.Logger
+
+	srv     *http.Server
+	ln      net.Listener
+	done    chan error
+	closing chan struct{}
+
+	mu    sync.Mutex
+	fresh map[net.Conn]struct{}
+}
+
+// closingKey carries, in a request's context, the channel a Server closes
+// when it begins shutting down
+type closingKey struct{}
+
+// serverClosing returns the channel closed when the server answering the
+// request of ctx begins shutting down, or nil outside a Server
+func serverClosing(ctx context.Context) <-chan struct{} {
+	closing, _ := ctx.Value(closingKey{}).(chan struct{})
+	return closing
+}
+
+// NewServer creates a server answering requests to h as configured by cfg
+func NewServer(cfg config.ServerConfig, h http.Handler, log logger.Logger,
+	opts ...ServerOption) *Server {
+	s := &Server{
+		cfg:        cfg,
+		handler:    h,
+		drainDelay: cfg.DrainDelay,
+		logger:     log.Named("server"),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Start listens on cfg.Host and cfg.Port and serves requests in the
+// background, bounded by cfg.ReadTimeout and cfg.WriteTimeout and over TLS
+// when cfg.TLSEnabled is set
+func (s *Server) Start() error {
+	addr := net.JoinHostPort(s.cfg.Host, fmt.Sprint(s.cfg.Port))
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on %s", addr).
+			WithCode(errors.CodeConfiguration).
+			WithOp("api.Server.Start")
+	}
+
+	s.serve(ln)
+	return nil
+}
+
+// serve answers requests on ln in the background
+func (s *Server) serve(ln net.Listener) {
+	s.ln = ln
+	s.closing = make(chan struct{})
+	s.fresh = make(map[net.Conn]struct{})
+	s.srv = &http.Server{
+		Handler:           s.handler,
+		ReadTimeout:       s.cfg.ReadTimeout,
+		ReadHeaderTimeout: s.cfg.ReadTimeout,
+		WriteTimeout:      s.cfg.WriteTimeout,
+		ConnState:         s.trackConn,
+		BaseContext: func(net.Listener) context.Context {
+			return context.WithValue(context.Background(), closingKey{}, s.closing)
+		},
+	}
+	s.srv.RegisterOnShutdown(s.beginShutdown)
+
+	s.done = make(chan error, 1)
+	go func() {
+		if s.cfg.TLSEnabled {
+			s.done <- s.srv.ServeTLS(ln, s.cfg.TLSCertFile, s.cfg.TLSKeyFile)
+			return
+		}
+
+		s.done <- s.srv.Serve(ln)
+	}()
+}
+
+// trackConn records the connections that have not sent a request yet
+func (s *Server) trackConn(conn net.Conn, state http.ConnState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state == http.StateNew {
+		s.fresh[conn] = struct{}{}
+		return
+	}
+
+	delete(s.fresh, conn)
+}
+
+// beginShutdown ends the event streams, which otherwise last as long as
+// their client listens, and closes the connections that never sent a
+// request, which Shutdown would count as active for seconds
+func (s *Server) beginShutdown() {
+	close(s.closing)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for conn := range s.fresh {
+		_ = conn.Close()
+	}
+}
+
+// Addr returns the address the server listens on once started
+func (s *Server) Addr() net.Addr {
+	if s.ln == nil {
+		return nil
+	}
+
+	return s.ln.Addr()
+}
+
+// Run starts the server unless Start already did, and blocks until SIGTERM
+// or SIGINT arrives, ctx is canceled, or the server fails. It then shuts
+// down, returning every failure of the shutdown joined into one error.
+func (s *Server) Run(ctx context.Context) error {
+	if s.srv == nil {
+		if err := s.Start(); err != nil {
+			return err
+		}
+	}
+
+	signals := s.signals
+	if !s.customSignals {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, syscall.SIGTERM, syscall.SIGINT)
+		defer signal.Stop(ch)
+		signals = ch
+	}
+
+	select {
+	case err := <-s.done:
+		return s.shutdown(ctx, errors.Wrap(err, "api server failed").
+			WithCode(errors.CodeNetwork).
+			WithOp("api.Server.Run"))
+
+	case sig := <-signals:
+		s.logger.Info("shutting down", "signal", sig.String())
+
+	case <-ctx.Done():
+		s.logger.Info("shutting down", "reason", context.Cause(ctx).Error())
+	}
+
+	return s.shutdown(ctx, nil)
+}
+
+// shutdown fails readiness, drains the HTTP server unless it already
+// failed with cause, and runs the shutdown steps
+func (s *Server) shutdown(ctx context.Context, cause error) error {
+	ctx = context.WithoutCancel(ctx)
+	errs := []error{cause}
+	if s.health != nil {
+		s.health.Shutdown()
+	}
+
+	if cause == nil {
+		errs = append(errs, s.stopHTTP(ctx))
+	}
+
+	for _, step := range s.steps {
+		stepCtx, cancel := context.WithTimeout(ctx, s.cfg.ShutdownTimeout)
+		if err := step.fn(stepCtx); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to shut down %s", step.name).
+				WithOp("api.Server.Run"))
+		}
+
+		cancel()
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	s.logger.Info("shut down")
+	return nil
+}
+
+// stopHTTP waits out the drain delay, then stops taking requests and waits
+// up to cfg.ShutdownTimeout for in-flight ones, cutting off the rest
+func (s *Server) stopHTTP(ctx context.Context) error {
+	if s.drainDelay > 0 {
+		time.Sleep(s.drainDelay)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, s.cfg.ShutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	if err := s.srv.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, errors.Wrap(err, "in-flight requests did not finish in time").
+			WithCode(errors.CodeTimeout).
+			WithOp("api.Server.Run"))
+		_ = s.srv.Close()
+	}
+
+	if err := <-s.done; err != nil && !stderrors.Is(err, http.ErrServerClosed) {
+		errs = append(errs, errors.Wrap(err, "api server failed").
+			WithCode(errors.CodeNetwork).
+			WithOp("api.Server.Run"))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Serve answers requests to h on cfg.Host and cfg.Port until ctx is
+// canceled, then waits up to cfg.ShutdownTimeout for in-flight requests.
+// Unlike Server.Run it ignores signals and has no drain delay.
+func Serve(ctx context.Context, cfg config.ServerConfig, h http.Handler) error {
+	cfg.DrainDelay = 0
+	return NewServer(cfg, h, logger.NewNop(), WithSignals(nil)).Run(ctx)
+}
+
+// serve answers requests to h on ln until ctx is canceled
+func serve(ctx context.Context, ln net.Listener, cfg config.ServerConfig, h http.Handler) error {
+	cfg.DrainDelay = 0
+	s := NewServer(cfg, h, logger.NewNop(), WithSignals(nil))
+	s.serve(ln)
+	return s.Run(ctx)
+}
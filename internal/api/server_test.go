@@ -0,0 +1,261 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/eventbus"
+	"task-queue/internal/health"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServe_ShutsDownOnCancel(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	a := newTestAPI()
+	job := a.submit(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- serve(ctx, ln, config.ServerConfig{
+			ReadTimeout:     time.Second,
+			WriteTimeout:    time.Second,
+			ShutdownTimeout: time.Second,
+		}, a)
+	}()
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/v1/jobs/" + job.ID.String())
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+
+	case <-time.After(2 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+}
+
+// lifecycle is a Server over the test API on a loopback port, stopped by a
+// fake signal
+type lifecycle struct {
+	*Server
+	checker *health.Checker
+	signals chan os.Signal
+}
+
+// stepLog records the shutdown steps that ran, in order
+type stepLog struct {
+	mu    sync.Mutex
+	names []string
+}
+
+// step is a shutdown step named name recording itself and failing with err
+func (l *stepLog) step(name string, err error) ServerOption {
+	return OnShutdown(name, func(context.Context) error {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.names = append(l.names, name)
+		return err
+	})
+}
+
+func newLifecycle(t *testing.T, cfg config.ServerConfig, h http.Handler, opts ...ServerOption) *lifecycle {
+	t.Helper()
+
+	l := &lifecycle{checker: health.New(), signals: make(chan os.Signal, 1)}
+	if h == nil {
+		h = newTestAPI(WithHealth(l.checker))
+	}
+
+	cfg.Host = "127.0.0.1"
+	opts = append([]ServerOption{WithReadiness(l.checker), WithSignals(l.signals)}, opts...)
+	l.Server = NewServer(cfg, h, logger.NewNop(), opts...)
+	require.NoError(t, l.Start())
+	return l
+}
+
+// url returns the URL of path on the server
+func (l *lifecycle) url(path string) string {
+	return "http://" + l.Addr().String() + path
+}
+
+// run runs the server in the background, returning the result of Run
+func (l *lifecycle) run() <-chan error {
+	done := make(chan error, 1)
+	go func() { done <- l.Run(context.Background()) }()
+	return done
+}
+
+// wait returns the result of a Run, failing t when it does not finish
+func wait(t *testing.T, done <-chan error) error {
+	t.Helper()
+
+	select {
+	case err := <-done:
+		return err
+
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+		return nil
+	}
+}
+
+func TestServer_ShutdownOrder(t *testing.T) {
+	var steps stepLog
+	l := newLifecycle(t, config.ServerConfig{ShutdownTimeout: time.Second},
+		nil, WithDrainDelay(200*time.Millisecond),
+		steps.step("worker pool", nil), steps.step("queue", nil), steps.step("database", nil))
+
+	resp, err := http.Get(l.url("/readyz"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	done := l.run()
+	l.signals <- syscall.SIGTERM
+
+	// Readiness fails during the drain delay while requests are still served
+	require.Eventually(t, l.checker.ShuttingDown, time.Second, time.Millisecond)
+	resp, err = http.Get(l.url("/readyz"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	resp, err = http.Get(l.url("/v1/jobs"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.NoError(t, wait(t, done))
+	assert.Equal(t, []string{"worker pool", "queue", "database"}, steps.names)
+
+	_, err = net.Dial("tcp", l.Addr().String())
+	assert.Error(t, err, "the server no longer listens")
+}
+
+func TestServer_InFlightRequests(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		if r.URL.Path == "/stuck" {
+			<-r.Context().Done()
+			return
+		}
+
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	t.Run("finish within the timeout", func(t *testing.T) {
+		l := newLifecycle(t, config.ServerConfig{ShutdownTimeout: 2 * time.Second}, slow)
+		result := make(chan int, 1)
+		go func() {
+			resp, err := http.Get(l.url("/slow"))
+			if err != nil {
+				result <- 0
+				return
+			}
+
+			resp.Body.Close()
+			result <- resp.StatusCode
+		}()
+
+		<-started
+		done := l.run()
+		l.signals <- syscall.SIGINT
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+
+		assert.Equal(t, http.StatusNoContent, <-result)
+		assert.NoError(t, wait(t, done))
+	})
+
+	t.Run("cut off after the timeout", func(t *testing.T) {
+		var steps stepLog
+		l := newLifecycle(t, config.ServerConfig{ShutdownTimeout: 100 * time.Millisecond}, slow,
+			steps.step("queue", nil))
+		failed := make(chan error, 1)
+		go func() {
+			resp, err := http.Get(l.url("/stuck"))
+			if err == nil {
+				resp.Body.Close()
+			}
+
+			failed <- err
+		}()
+
+		<-started
+		done := l.run()
+		l.signals <- syscall.SIGTERM
+
+		err := wait(t, done)
+		require.Error(t, err)
+		assert.True(t, errors.HasCode(err, errors.CodeTimeout))
+		assert.Error(t, <-failed, "the stuck request is cut off")
+		assert.Equal(t, []string{"queue"}, steps.names, "shutdown continues after the timeout")
+	})
+}
+
+func TestServer_EndsEventStreams(t *testing.T) {
+	bus := eventbus.NewMemoryBus()
+	t.Cleanup(func() { _ = bus.Close() })
+
+	l := newLifecycle(t, config.ServerConfig{ShutdownTimeout: 5 * time.Second},
+		newTestAPI(WithEventBus(bus)))
+
+	resp, err := http.Get(l.url("/v1/events"))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Eventually(t, func() bool { return bus.Subscribers() == 1 }, time.Second, time.Millisecond)
+
+	start := time.Now()
+	done := l.run()
+	l.signals <- syscall.SIGTERM
+
+	require.NoError(t, wait(t, done))
+	assert.Less(t, time.Since(start), time.Second, "the open stream does not hold up shutdown")
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, ": shutting down\n\n", string(body))
+}
+
+func TestServer_JoinsShutdownFailures(t *testing.T) {
+	var steps stepLog
+	l := newLifecycle(t, config.ServerConfig{ShutdownTimeout: time.Second}, nil,
+		steps.step("worker pool", errors.New("handlers still running")),
+		steps.step("queue", nil),
+		steps.step("database", errors.New("connection busy")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- l.Run(ctx) }()
+	cancel()
+
+	err := wait(t, done)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to shut down worker pool")
+	assert.Contains(t, err.Error(), "failed to shut down database")
+	assert.Equal(t, []string{"worker pool", "queue", "database"}, steps.names)
+}
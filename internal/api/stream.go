@@ -0,0 +1,173 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"task-queue/internal/eventbus"
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/validation"
+)
+
+// DefaultHeartbeatInterval is how often an idle event stream sends a
+// comment, keeping proxies from closing it
+const DefaultHeartbeatInterval = 15 * time.Second
+
+// eventSnapshot names the first frame of a watch, reporting the job's state
+// when the watch began
+const eventSnapshot = "job.snapshot"
+
+// routeStreams registers the event stream endpoints
+func (h *Handler) routeStreams() {
+	h.handle("GET /v1/jobs/{id}/watch", h.requireScope(ScopeJobsRead, h.watchJob))
+	h.handle("GET /v1/events", h.requireScope(ScopeJobsRead, h.streamEvents))
+}
+
+// watchJob handles GET /v1/jobs/{id}/watch: a snapshot of the job followed
+// by its status changes, until it reaches a terminal status
+func (h *Handler) watchJob(w http.ResponseWriter, r *http.Request) {
+	id, err := jobID(r)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	// Subscribing before reading the job means no change between the two
+	// is missed
+	sub := h.events.Subscribe(eventbus.Filter{JobID: id})
+	defer sub.Close()
+
+	job, err := h.jobs.Get(r.Context(), id)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	stream := h.openStream(w, r)
+	if stream.send(eventbus.NewEvent(eventSnapshot, job)) != nil || job.Status.Terminal() {
+		return
+	}
+
+	h.relay(stream, sub, func(event eventbus.Event) bool {
+		return event.Status.Terminal()
+	})
+}
+
+// streamEvents handles GET /v1/events: every job event matching the
+// repeatable or comma-separated type and status parameters
+func (h *Handler) streamEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	types := listValues(query, "type")
+	statuses := listValues(query, "status")
+
+	err := validation.ValidateAll(
+		validation.NewField("type", types, validation.Each(validation.JobType())),
+		validation.NewField("status", statuses, validation.Each(validation.JobStatus())),
+	)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	filter := eventbus.Filter{JobTypes: types}
+	for _, status := range statuses {
+		filter.Statuses = append(filter.Statuses, models.JobStatus(status))
+	}
+
+	sub := h.events.Subscribe(filter)
+	defer sub.Close()
+
+	h.relay(h.openStream(w, r), sub, func(eventbus.Event) bool {
+		return false
+	})
+}
+
+// relay writes the events of sub to stream until the client goes away,
+// the server shuts down, the subscription ends, or last reports that an
+// event ends the stream
+func (h *Handler) relay(stream *eventStream, sub *eventbus.Subscription, last func(eventbus.Event) bool) {
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-stream.ctx.Done():
+			return
+
+		case <-stream.closing:
+			_ = stream.comment("shutting down")
+			return
+
+		case <-heartbeat.C:
+			if stream.comment("heartbeat") != nil {
+				return
+			}
+
+		case event, ok := <-sub.Events():
+			if !ok {
+				if sub.Dropped() {
+					h.logger.WithContext(stream.ctx).Warn("dropped slow event stream client")
+					_ = stream.comment("dropped")
+				}
+
+				return
+			}
+
+			if stream.send(event) != nil || last(event) {
+				return
+			}
+		}
+	}
+}
+
+// eventStream writes server-sent events to one client
+type eventStream struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	ctx     context.Context
+	closing <-chan struct{}
+}
+
+// openStream starts a server-sent event response on w. The server's write
+// timeout is lifted, since a stream stays open as long as the client
+// listens.
+func (h *Handler) openStream(w http.ResponseWriter, r *http.Request) *eventStream {
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	_ = rc.Flush()
+
+	return &eventStream{w: w, rc: rc, ctx: r.Context(), closing: serverClosing(r.Context())}
+}
+
+// send writes event as a frame named by its type
+func (s *eventStream) send(event eventbus.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode job event").
+			WithCode(errors.CodeSerialization)
+	}
+
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+		return err
+	}
+
+	return s.rc.Flush()
+}
+
+// comment writes an SSE comment, ignored by clients
+func (s *eventStream) comment(text string) error {
+	if _, err := fmt.Fprintf(s.w, ": %s\n\n", text); err != nil {
+		return err
+	}
+
+	return s.rc.Flush()
+}
@@ -0,0 +1,242 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"task-queue/internal/eventbus"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/internal/service"
+	"task-queue/internal/storage"
+	"task-queue/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamAPI is a test API served over HTTP with an in-process event bus
+type streamAPI struct {
+	*testAPI
+	bus    *eventbus.MemoryBus
+	server *httptest.Server
+}
+
+func newStreamAPI(t *testing.T, opts ...Option) *streamAPI {
+	t.Helper()
+
+	a := &streamAPI{
+		testAPI: &testAPI{
+			store:  storage.NewMemoryJobStore(),
+			events: storage.NewMemoryEventStore(),
+			queue:  queue.NewMemoryQueue(queue.Config{}),
+		},
+		bus: eventbus.NewMemoryBus(),
+	}
+
+	log := logger.NewNop()
+	svc := service.New(a.store, a.events, a.queue, log, service.WithPublisher(a.bus))
+	a.Handler = New(svc, log, append([]Option{WithEventBus(a.bus)}, opts...)...)
+	a.server = httptest.NewServer(a.Handler)
+	t.Cleanup(func() {
+		a.server.Close()
+		_ = a.bus.Close()
+	})
+
+	return a
+}
+
+// sseFrame is one event of a server-sent event stream
+type sseFrame struct {
+	event string
+	data  string
+}
+
+// sseReader reads a server-sent event stream line by line
+type sseReader struct {
+	body   io.ReadCloser
+	reader *bufio.Reader
+}
+
+// open starts a stream at path, failing t unless it is accepted
+func (a *streamAPI) open(t *testing.T, ctx context.Context, path string) *sseReader {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.server.URL+path, nil)
+	require.NoError(t, err)
+
+	resp, err := a.server.Client().Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	return &sseReader{body: resp.Body, reader: bufio.NewReader(resp.Body)}
+}
+
+// next returns the next frame, skipping comments. It returns io.EOF once the
+// server ends the stream.
+func (r *sseReader) next() (sseFrame, error) {
+	var frame sseFrame
+	for {
+		line, err := r.reader.ReadString('\n')
+		if err != nil {
+			return frame, err
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "" && frame.event != "":
+			return frame, nil
+
+		case strings.HasPrefix(line, "event: "):
+			frame.event = strings.TrimPrefix(line, "event: ")
+
+		case strings.HasPrefix(line, "data: "):
+			frame.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+// nextEvent returns the next frame decoded as a job event
+func (r *sseReader) nextEvent(t *testing.T) eventbus.Event {
+	t.Helper()
+
+	frame, err := r.next()
+	require.NoError(t, err)
+
+	var event eventbus.Event
+	require.NoError(t, json.Unmarshal([]byte(frame.data), &event))
+	assert.Equal(t, frame.event, event.Type)
+	return event
+}
+
+func TestWatchJob_StreamsUntilTerminal(t *testing.T) {
+	a := newStreamAPI(t)
+	job := a.submit(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream := a.open(t, ctx, "/v1/jobs/"+job.ID.String()+"/watch")
+	snapshot := stream.nextEvent(t)
+	assert.Equal(t, eventSnapshot, snapshot.Type)
+	assert.Equal(t, job.ID, snapshot.JobID)
+	assert.Equal(t, models.JobStatusPending, snapshot.Status)
+
+	other := a.submit(t)
+	job.Status = models.JobStatusRunning
+	require.NoError(t, a.bus.Publish(ctx, eventbus.NewEvent(logger.AuditJobStarted, job)))
+	job.Status = models.JobStatusCompleted
+	require.NoError(t, a.bus.Publish(ctx, eventbus.NewEvent(logger.AuditJobCompleted, job)))
+
+	started := stream.nextEvent(t)
+	assert.Equal(t, logger.AuditJobStarted, started.Type)
+	assert.Equal(t, job.ID, started.JobID, "events of %s are not watched", other.ID)
+
+	completed := stream.nextEvent(t)
+	assert.Equal(t, models.JobStatusCompleted, completed.Status)
+
+	_, err := stream.next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestWatchJob_TerminalJobEndsAfterSnapshot(t *testing.T) {
+	a := newStreamAPI(t)
+	job := a.submit(t)
+	rec := a.do(http.MethodDelete, "/v1/jobs/"+job.ID.String(), "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream := a.open(t, ctx, "/v1/jobs/"+job.ID.String()+"/watch")
+	assert.Equal(t, models.JobStatusCancelled, stream.nextEvent(t).Status)
+
+	_, err := stream.next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestWatchJob_NotFound(t *testing.T) {
+	a := newStreamAPI(t)
+
+	missing := models.NewJob("email_send", nil, models.JobPriorityNormal)
+	rec := a.do(http.MethodGet, "/v1/jobs/"+missing.ID.String()+"/watch", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestStreamEvents_Filters(t *testing.T) {
+	a := newStreamAPI(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream := a.open(t, ctx, "/v1/events?type=email_send&status=dead,completed")
+
+	skipped := models.NewJob("report_build", nil, models.JobPriorityNormal)
+	skipped.Status = models.JobStatusDead
+	require.NoError(t, a.bus.Publish(ctx, eventbus.NewEvent(logger.AuditJobDeadLettered, skipped)))
+
+	// Created jobs are pending, which the status filter excludes
+	a.submit(t)
+
+	dead := models.NewJob("email_send", nil, models.JobPriorityNormal)
+	dead.Status = models.JobStatusDead
+	require.NoError(t, a.bus.Publish(ctx, eventbus.NewEvent(logger.AuditJobDeadLettered, dead)))
+
+	event := stream.nextEvent(t)
+	assert.Equal(t, dead.ID, event.JobID)
+	assert.Equal(t, logger.AuditJobDeadLettered, event.Type)
+}
+
+func TestStreamEvents_InvalidFilter(t *testing.T) {
+	a := newStreamAPI(t)
+
+	rec := a.do(http.MethodGet, "/v1/events?status=sleeping", "")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	failures := decodeProblem(t, rec)["errors"].([]any)
+	require.Len(t, failures, 1)
+	assert.Equal(t, "status[0]", failures[0].(map[string]any)["field"])
+}
+
+func TestStreamEvents_Heartbeat(t *testing.T) {
+	a := newStreamAPI(t, WithHeartbeatInterval(10*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream := a.open(t, ctx, "/v1/events")
+	line, err := stream.reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, ": heartbeat\n", line)
+}
+
+func TestStreamEvents_ClientDisconnect(t *testing.T) {
+	a := newStreamAPI(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := a.open(t, ctx, "/v1/events")
+	cancel()
+	_ = stream.body.Close()
+
+	// The handler unsubscribes once it notices the client is gone, so the
+	// bus is left with no subscribers
+	assert.Eventually(t, func() bool {
+		return a.bus.Subscribers() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestStreams_NotRoutedWithoutBus(t *testing.T) {
+	a := newTestAPI()
+
+	rec := a.do(http.MethodGet, "/v1/events", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
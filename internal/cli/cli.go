@@ -0,0 +1,231 @@
+package cli
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/eventbus"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/pkg/client"
+	"task-queue/pkg/errors"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+// Exit statuses by failure category
+const (
+	ExitOK          = 0
+	ExitFailure     = 1
+	ExitUsage       = 2
+	ExitNotFound    = 3
+	ExitDenied      = 4
+	ExitConflict    = 5
+	ExitUnavailable = 6
+)
+
+// API is the part of the task queue API the commands call. *client.Client
+// implements it.
+type API interface {
+	Submit(ctx context.Context, req *models.JobRequest) (*models.Job, error)
+	Get(ctx context.Context, id uuid.UUID) (*models.Job, error)
+	List(ctx context.Context, opts client.ListOptions) (*client.JobPage, error)
+	Replay(ctx context.Context, id uuid.UUID, overrides models.ReplayOverrides) (*models.Job, error)
+	Watch(ctx context.Context, id uuid.UUID, fn func(eventbus.Event) error) error
+	Queues(ctx context.Context) ([]*queue.QueueStats, error)
+	RequeueDeadLetter(ctx context.Context, name string, id uuid.UUID) (*models.Job, error)
+	RequeueDeadLetters(ctx context.Context, name string) (int64, error)
+	Pause(ctx context.Context, name string) (*queue.QueueStats, error)
+	Resume(ctx context.Context, name string) (*queue.QueueStats, error)
+	Drain(ctx context.Context, name string) (int64, error)
+}
+
+var _ API = (*client.Client)(nil)
+
+// Connector builds the API of the client configuration
+type Connector func(cfg config.ClientConfig) (API, error)
+
+// Connect is the Connector calling the HTTP API with pkg/client
+func Connect(cfg config.ClientConfig) (API, error) {
+	c, err := client.New(cfg.URL, client.WithAPIKey(cfg.APIKey), client.WithTimeout(cfg.Timeout))
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// app is the state shared by the commands of one invocation
+type app struct {
+	connect    Connector
+	configPath string
+	output     string
+	now        func() time.Time
+	api        API
+}
+
+// NewCommand builds the taskqueue command tree, calling the API built by
+// connect
+func NewCommand(connect Connector) *cobra.Command {
+	a := &app{connect: connect, now: time.Now}
+
+	root := &cobra.Command{
+		Use:               "taskqueue",
+		Short:             "Operate the task queue",
+		Args:              noArgs,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+		PersistentPreRunE: a.setup,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	root.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return errors.Wrap(err, "invalid flags").WithCode(errors.CodeValidation)
+	})
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&a.configPath, "config", "", "configuration file")
+	flags.StringVarP(&a.output, "output", "o", formatTable, "output format (table, json)")
+	flags.String("client.url", "", "task queue API URL")
+	flags.String("client.api_key", "", "API key")
+	flags.Duration("client.timeout", 0, "timeout of each API call")
+
+	root.AddCommand(
+		a.submitCommand(),
+		a.getCommand(),
+		a.listCommand(),
+		a.watchCommand(),
+		a.replayCommand(),
+		a.statsCommand(),
+		a.requeueCommand(),
+		a.pauseCommand(),
+		a.resumeCommand(),
+		a.drainCommand(),
+	)
+
+	return root
+}
+
+// setup checks the output format, loads the configuration, and connects to
+// the API before any command runs
+func (a *app) setup(cmd *cobra.Command, _ []string) error {
+	if a.output != formatTable && a.output != formatJSON {
+		return errors.Validation("unknown output format %q, want table or json", a.output)
+	}
+
+	cfg, err := config.LoadOrDefault(a.configPath, config.WithFlags(cmd.Flags()))
+	if err != nil {
+		return err
+	}
+
+	a.api, err = a.connect(cfg.Client)
+	return err
+}
+
+// Execute runs cmd with args, printing any error, and returns the exit
+// status
+func Execute(ctx context.Context, cmd *cobra.Command, args []string) int {
+	cmd.SetArgs(args)
+	err := cmd.ExecuteContext(ctx)
+	if err == nil {
+		return ExitOK
+	}
+
+	code := ExitCode(err)
+	fmt.Fprintln(cmd.ErrOrStderr(), "Error:", describe(err))
+	if code == ExitUsage {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Run '%s --help' for usage.\n", cmd.Name())
+	}
+
+	return code
+}
+
+// ExitCode returns the exit status reporting err
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	switch errors.GetCode(err) {
+	case errors.CodeValidation:
+		return ExitUsage
+
+	case errors.CodeNotFound:
+		return ExitNotFound
+
+	case errors.CodeAuthentication, errors.CodePermission:
+		return ExitDenied
+
+	case errors.CodeConflict, errors.CodeAlreadyExists:
+		return ExitConflict
+
+	case errors.CodeNetwork, errors.CodeTimeout, errors.CodeRateLimit:
+		return ExitUnavailable
+	}
+
+	return ExitFailure
+}
+
+// describe renders err for the terminal: its message without operation
+// labels, followed by its field failures
+func describe(err error) string {
+	var e *errors.Error
+	if !stderrors.As(err, &e) {
+		return err.Error()
+	}
+
+	var b strings.Builder
+	b.WriteString(e.Message)
+	if e.Cause != nil {
+		b.WriteString(": " + e.Cause.Error())
+	}
+
+	fields, _ := e.Metadata[errors.FieldsKey].([]errors.FieldError)
+	for _, field := range fields {
+		fmt.Fprintf(&b, "\n  %s: %s", field.Field, field.Message)
+	}
+
+	return b.String()
+}
+
+// noArgs rejects positional arguments, naming an unknown command
+func noArgs(_ *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return errors.Validation("unknown command %q", args[0])
+	}
+
+	return nil
+}
+
+// exactArgs requires one positional argument per name
+func exactArgs(names ...string) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		switch {
+		case len(args) == len(names):
+			return nil
+
+		case len(names) == 0:
+			return errors.Validation("%s takes no arguments, got %q", cmd.Name(), args[0])
+		}
+
+		return errors.Validation("%s takes %d argument(s): <%s>, got %d",
+			cmd.Name(), len(names), strings.Join(names, "> <"), len(args))
+	}
+}
+
+// parseID parses a job ID argument
+func parseID(arg string) (uuid.UUID, error) {
+	id, err := uuid.Parse(arg)
+	if err != nil {
+		return uuid.Nil, errors.Validation("invalid job ID %q", arg)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,396 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/eventbus"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/pkg/client"
+	"task-queue/pkg/errors"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubAPI records the calls of a command and answers them with canned
+// results, or err when it is set
+type stubAPI struct {
+	err      error
+	job      *models.Job
+	page     *client.JobPage
+	events   []eventbus.Event
+	stats    []*queue.QueueStats
+	count    int64
+	requests []*models.JobRequest
+	lists    []client.ListOptions
+	replays  []models.ReplayOverrides
+	calls    []string
+}
+
+// call records a call named by args and returns the stubbed error
+func (s *stubAPI) call(args ...string) error {
+	s.calls = append(s.calls, strings.Join(args, " "))
+	return s.err
+}
+
+func (s *stubAPI) Submit(_ context.Context, req *models.JobRequest) (*models.Job, error) {
+	s.requests = append(s.requests, req)
+	return s.job, s.call("submit")
+}
+
+func (s *stubAPI) Get(_ context.Context, id uuid.UUID) (*models.Job, error) {
+	return s.job, s.call("get", id.String())
+}
+
+func (s *stubAPI) List(_ context.Context, opts client.ListOptions) (*client.JobPage, error) {
+	s.lists = append(s.lists, opts)
+	return s.page, s.call("list")
+}
+
+func (s *stubAPI) Replay(_ context.Context, id uuid.UUID, overrides models.ReplayOverrides) (*models.Job, error) {
+	s.replays = append(s.replays, overrides)
+	return s.job, s.call("replay", id.String())
+}
+
+func (s *stubAPI) Watch(_ context.Context, id uuid.UUID, fn func(eventbus.Event) error) error {
+	if err := s.call("watch", id.String()); err != nil {
+		return err
+	}
+
+	for _, event := range s.events {
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *stubAPI) Queues(context.Context) ([]*queue.QueueStats, error) {
+	return s.stats, s.call("queues")
+}
+
+func (s *stubAPI) RequeueDeadLetter(_ context.Context, name string, id uuid.UUID) (*models.Job, error) {
+	return s.job, s.call("requeue", name, id.String())
+}
+
+func (s *stubAPI) RequeueDeadLetters(_ context.Context, name string) (int64, error) {
+	return s.count, s.call("requeue-all", name)
+}
+
+func (s *stubAPI) Pause(_ context.Context, name string) (*queue.QueueStats, error) {
+	return s.stats[0], s.call("pause", name)
+}
+
+func (s *stubAPI) Resume(_ context.Context, name string) (*queue.QueueStats, error) {
+	return s.stats[0], s.call("resume", name)
+}
+
+func (s *stubAPI) Drain(_ context.Context, name string) (int64, error) {
+	return s.count, s.call("drain", name)
+}
+
+// result is the outcome of one invocation
+type result struct {
+	code   int
+	stdout string
+	stderr string
+	config config.ClientConfig
+}
+
+// run executes the command tree over api with args
+func run(t *testing.T, api *stubAPI, args ...string) result {
+	t.Helper()
+
+	var res result
+	cmd := NewCommand(func(cfg config.ClientConfig) (API, error) {
+		res.config = cfg
+		return api, nil
+	})
+
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetIn(strings.NewReader(`{"from":"stdin"}`))
+
+	res.code = Execute(context.Background(), cmd, args)
+	res.stdout, res.stderr = stdout.String(), stderr.String()
+	return res
+}
+
+func testJob() *models.Job {
+	job := models.NewJob("send_email", json.RawMessage(`{"to":"a@example.com"}`), models.JobPriorityHigh)
+	job.CreatedAt = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return job
+}
+
+func TestArgumentValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "unknown command", args: []string{"frobnicate"}, want: `unknown command "frobnicate"`},
+		{name: "unknown flag", args: []string{"list", "--nope"}, want: "unknown flag: --nope"},
+		{name: "missing id", args: []string{"get"}, want: "get takes 1 argument(s): <id>, got 0"},
+		{name: "invalid id", args: []string{"get", "42"}, want: `invalid job ID "42"`},
+		{name: "extra argument", args: []string{"stats", "default"}, want: "stats takes no arguments"},
+		{name: "missing payload", args: []string{"submit", "--type", "send_email"}, want: "--type and --payload"},
+		{name: "invalid payload", args: []string{"submit", "--type", "t", "--payload", "{"}, want: "not valid JSON"},
+		{name: "unknown priority", args: []string{"submit", "--type", "t", "--payload", "{}", "--priority", "urgent"}, want: `unknown priority "urgent"`},
+		{name: "missing payload file", args: []string{"submit", "--type", "t", "--payload", "@/nonexistent.json"}, want: "failed to read payload"},
+		{name: "invalid since", args: []string{"list", "--since", "soon"}, want: "invalid flags"},
+		{name: "requeue id and all", args: []string{"requeue-dlq", uuid.NewString(), "--all"}, want: "not both"},
+		{name: "requeue nothing", args: []string{"requeue-dlq"}, want: "<id>"},
+		{name: "output format", args: []string{"stats", "-o", "yaml"}, want: `unknown output format "yaml"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &stubAPI{}
+			res := run(t, api, tt.args...)
+
+			assert.Equal(t, ExitUsage, res.code)
+			assert.Contains(t, res.stderr, tt.want)
+			assert.Contains(t, res.stderr, "--help")
+			assert.Empty(t, api.calls, "invalid invocations never call the API")
+		})
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "job.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"to":"file@example.com"}`), 0o600))
+
+	api := &stubAPI{job: testJob()}
+	res := run(t, api, "submit", "--type", "send_email", "--payload", "@"+path,
+		"--priority", "high", "--max-retries", "5")
+	require.Equal(t, ExitOK, res.code, res.stderr)
+
+	require.Len(t, api.requests, 1)
+	req := api.requests[0]
+	assert.Equal(t, "send_email", req.Type)
+	assert.JSONEq(t, `{"to":"file@example.com"}`, string(req.Payload))
+	assert.Equal(t, models.JobPriorityHigh, req.Priority)
+	require.NotNil(t, req.MaxRetries)
+	assert.Equal(t, 5, *req.MaxRetries)
+	assert.Nil(t, req.ScheduledAt)
+	assert.Contains(t, res.stdout, api.job.ID.String())
+	assert.Contains(t, res.stdout, "Priority:   high")
+
+	res = run(t, api, "submit", "--type", "send_email", "--payload", "@-", "--priority", "3")
+	require.Equal(t, ExitOK, res.code, res.stderr)
+	assert.JSONEq(t, `{"from":"stdin"}`, string(api.requests[1].Payload))
+	assert.Equal(t, models.JobPriorityCritical, api.requests[1].Priority)
+	assert.Nil(t, api.requests[1].MaxRetries, "unset flags leave the server default")
+}
+
+func TestReplay(t *testing.T) {
+	api := &stubAPI{job: testJob()}
+	id := uuid.New()
+
+	res := run(t, api, "replay", id.String())
+	require.Equal(t, ExitOK, res.code, res.stderr)
+	assert.Equal(t, []string{"replay " + id.String()}, api.calls)
+	assert.Nil(t, api.replays[0].Priority, "an unset priority keeps the original's")
+	assert.Contains(t, res.stdout, api.job.ID.String())
+
+	res = run(t, api, "replay", id.String(), "--priority", "low", "--delay", "1m")
+	require.Equal(t, ExitOK, res.code, res.stderr)
+	require.NotNil(t, api.replays[1].Priority)
+	assert.Equal(t, models.JobPriorityLow, *api.replays[1].Priority)
+	assert.NotNil(t, api.replays[1].ScheduledAt)
+
+	res = run(t, api, "replay", id.String(), "--priority", "urgent")
+	assert.Equal(t, ExitUsage, res.code)
+}
+
+func TestOutputFormats(t *testing.T) {
+	job := testJob()
+	api := &stubAPI{
+		job:  job,
+		page: &client.JobPage{Items: []*models.Job{job}, NextCursor: "abc"},
+		stats: []*queue.QueueStats{
+			{Name: "default", Size: 12, Processing: 3, DeadLetter: 1, OldestAge: 90 * time.Second},
+		},
+	}
+
+	t.Run("table", func(t *testing.T) {
+		res := run(t, api, "list")
+		require.Equal(t, ExitOK, res.code, res.stderr)
+		assert.Equal(t, "ID                                    TYPE        STATUS   PRIORITY  RETRIES  CREATED\n"+
+			job.ID.String()+"  send_email  pending  high      0/3      2026-01-02T03:04:05Z\n", res.stdout)
+		assert.Equal(t, "more jobs: --cursor abc\n", res.stderr)
+
+		res = run(t, api, "stats")
+		require.Equal(t, ExitOK, res.code, res.stderr)
+		lines := strings.Split(strings.TrimSpace(res.stdout), "\n")
+		require.Len(t, lines, 2)
+		assert.Equal(t, []string{"QUEUE", "SIZE", "PROCESSING", "DELAYED", "DEAD", "OLDEST", "PAUSED"}, strings.Fields(lines[0]))
+		assert.Equal(t, []string{"default", "12", "3", "0", "1", "1m30s", "false"}, strings.Fields(lines[1]))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		res := run(t, api, "get", job.ID.String(), "-o", "json")
+		require.Equal(t, ExitOK, res.code, res.stderr)
+
+		var got models.Job
+		require.NoError(t, json.Unmarshal([]byte(res.stdout), &got))
+		assert.Equal(t, job.ID, got.ID)
+
+		res = run(t, api, "list", "--output", "json")
+		require.Equal(t, ExitOK, res.code, res.stderr)
+		assert.Empty(t, res.stderr, "the cursor is part of the JSON page")
+
+		var page client.JobPage
+		require.NoError(t, json.Unmarshal([]byte(res.stdout), &page))
+		assert.Equal(t, "abc", page.NextCursor)
+
+		api.count = 4
+		res = run(t, api, "drain", "default", "-o", "json")
+		require.Equal(t, ExitOK, res.code, res.stderr)
+		assert.JSONEq(t, `{"queue":"default","count":4}`, res.stdout)
+	})
+}
+
+func TestList_Filters(t *testing.T) {
+	api := &stubAPI{page: &client.JobPage{}}
+	before := time.Now()
+
+	res := run(t, api, "list", "--status", "failed,dead", "--type", "send_email", "--since", "1h", "--limit", "20")
+	require.Equal(t, ExitOK, res.code, res.stderr)
+
+	require.Len(t, api.lists, 1)
+	opts := api.lists[0]
+	assert.Equal(t, []models.JobStatus{models.JobStatusFailed, models.JobStatusDead}, opts.Statuses)
+	assert.Equal(t, []string{"send_email"}, opts.Types)
+	assert.Equal(t, 20, opts.Limit)
+	require.NotNil(t, opts.CreatedAfter)
+	assert.WithinDuration(t, before.Add(-time.Hour), *opts.CreatedAfter, time.Minute)
+}
+
+func TestWatch(t *testing.T) {
+	job := testJob()
+	api := &stubAPI{}
+	for _, status := range []models.JobStatus{models.JobStatusPending, models.JobStatusRunning, models.JobStatusFailed} {
+		job.Status = status
+		event := eventbus.NewEvent("job."+string(status), job)
+		event.Time = job.CreatedAt
+		api.events = append(api.events, event)
+	}
+
+	api.events[2].Error = "smtp timeout"
+
+	res := run(t, api, "watch", job.ID.String())
+	require.Equal(t, ExitOK, res.code, res.stderr)
+
+	lines := strings.Split(strings.TrimSpace(res.stdout), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[1], "job.running")
+	assert.Contains(t, lines[2], `error="smtp timeout"`)
+
+	res = run(t, api, "watch", job.ID.String(), "-o", "json")
+	require.Equal(t, ExitOK, res.code, res.stderr)
+	lines = strings.Split(strings.TrimSpace(res.stdout), "\n")
+	require.Len(t, lines, 3, "one JSON event per line")
+
+	var event eventbus.Event
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &event))
+	assert.Equal(t, models.JobStatusFailed, event.Status)
+}
+
+func TestQueueCommands(t *testing.T) {
+	id := uuid.New()
+	api := &stubAPI{
+		job:   testJob(),
+		count: 7,
+		stats: []*queue.QueueStats{{Name: "emails", Paused: true}},
+	}
+
+	for _, args := range [][]string{
+		{"requeue-dlq", "--queue", "emails", "--all"},
+		{"requeue-dlq", id.String()},
+		{"pause", "emails"},
+		{"resume", "emails"},
+		{"drain", "emails"},
+		{"stats"},
+	} {
+		res := run(t, api, args...)
+		require.Equal(t, ExitOK, res.code, "%v: %s", args, res.stderr)
+	}
+
+	assert.Equal(t, []string{
+		"requeue-all emails",
+		"requeue default " + id.String(),
+		"pause emails",
+		"resume emails",
+		"drain emails",
+		"queues",
+	}, api.calls)
+
+	res := run(t, api, "requeue-dlq", "--queue", "emails", "--all")
+	assert.Equal(t, "Requeued 7 job(s) of queue emails\n", res.stdout)
+}
+
+func TestErrorExitCodes(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{err: errors.Validation("type is required"), want: ExitUsage},
+		{err: errors.NotFound("job not found"), want: ExitNotFound},
+		{err: errors.Unauthenticated("missing API key"), want: ExitDenied},
+		{err: errors.PermissionDenied("requires admin"), want: ExitDenied},
+		{err: errors.Conflict("job is running"), want: ExitConflict},
+		{err: errors.RateLimited("slow down"), want: ExitUnavailable},
+		{err: errors.New("refused").WithCode(errors.CodeNetwork), want: ExitUnavailable},
+		{err: errors.Timeout("deadline exceeded"), want: ExitUnavailable},
+		{err: errors.Internal("boom"), want: ExitFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(errors.GetCode(tt.err)), func(t *testing.T) {
+			res := run(t, &stubAPI{err: tt.err}, "get", uuid.NewString())
+			assert.Equal(t, tt.want, res.code)
+			assert.Empty(t, res.stdout)
+			assert.Contains(t, res.stderr, "Error: ")
+		})
+	}
+
+	t.Run("field failures are listed", func(t *testing.T) {
+		err := errors.Validation("invalid job request").WithMetadata(errors.FieldsKey, []errors.FieldError{
+			{Field: "type", Message: "type is required"},
+		})
+
+		res := run(t, &stubAPI{err: err}, "submit", "--type", "x", "--payload", "{}")
+		assert.Equal(t, ExitUsage, res.code)
+		assert.Contains(t, res.stderr, "invalid job request\n  type: type is required")
+	})
+}
+
+func TestClientConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("client:\n  url: http://file:8080\n  api_key: from-file\n"), 0o600))
+	t.Setenv("TQ_CLIENT_API_KEY", "from-env")
+
+	res := run(t, &stubAPI{stats: []*queue.QueueStats{}}, "stats", "--config", path, "--client.timeout", "5s")
+	require.Equal(t, ExitOK, res.code, res.stderr)
+	assert.Equal(t, "http://file:8080", res.config.URL)
+	assert.Equal(t, "from-env", res.config.APIKey, "the environment overrides the file")
+	assert.Equal(t, 5*time.Second, res.config.Timeout)
+
+	res = run(t, &stubAPI{stats: []*queue.QueueStats{}}, "stats", "--config", path, "--client.url", "http://flag:9090")
+	require.Equal(t, ExitOK, res.code, res.stderr)
+	assert.Equal(t, "http://flag:9090", res.config.URL, "flags override every other source")
+	assert.Equal(t, config.Default().Client.Timeout, res.config.Timeout)
+}
@@ -0,0 +1,33 @@
+// Package cli implements taskqueue, the command-line tool for operating the
+// task queue through its HTTP API.
+//
+// Commands:
+//
+//	taskqueue submit --type send_email --payload @job.json --priority high
+//	taskqueue get <id>
+//	taskqueue list --status failed --since 1h
+//	taskqueue watch <id>
+//	taskqueue replay <id> --priority high
+//	taskqueue stats
+//	taskqueue requeue-dlq --queue default --all
+//	taskqueue pause|resume|drain <queue>
+//
+// The API address, key, and timeout are the client section of the
+// configuration, read from --config, TQ_CLIENT_* variables, and the
+// --client.url, --client.api_key, and --client.timeout flags, in
+// increasing precedence. Results are printed as tables, or as JSON with
+// -o json.
+//
+// The exit status tells failures apart without parsing output:
+//
+//	0  success
+//	1  unexpected failure
+//	2  invalid usage or rejected input
+//	3  not found
+//	4  authentication or permission failure
+//	5  conflict with the job's or queue's state
+//	6  server unavailable, timed out, or rate limited
+//
+// The commands call the API through the API interface, so tests substitute
+// a stub for the HTTP client.
+package cli
@@ -0,0 +1,270 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"task-queue/internal/eventbus"
+	"task-queue/internal/models"
+	"task-queue/pkg/client"
+	"task-queue/pkg/errors"
+
+	"github.com/spf13/cobra"
+)
+
+// submitCommand builds `taskqueue submit`
+func (a *app) submitCommand() *cobra.Command {
+	var (
+		jobType, payload, priority string
+		maxRetries                 int
+		delay                      time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "submit --type TYPE --payload JSON|@FILE",
+		Short: "Submit a job",
+		Args:  exactArgs(),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			req, err := jobRequest(cmd, jobType, payload, priority)
+			if err != nil {
+				return err
+			}
+
+			if cmd.Flags().Changed("max-retries") {
+				req.MaxRetries = &maxRetries
+			}
+
+			if delay > 0 {
+				scheduledAt := a.now().Add(delay)
+				req.ScheduledAt = &scheduledAt
+			}
+
+			job, err := a.api.Submit(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+
+			return a.print(cmd.OutOrStdout(), job, jobDetail(job))
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&jobType, "type", "", "job type (required)")
+	flags.StringVar(&payload, "payload", "", "JSON payload, or @FILE to read it from FILE, @- from stdin (required)")
+	flags.StringVar(&priority, "priority", "normal", "priority: low, normal, high, critical, or a level")
+	flags.IntVar(&maxRetries, "max-retries", 0, "retries before the job is dead-lettered")
+	flags.DurationVar(&delay, "delay", 0, "run the job after this delay")
+	return cmd
+}
+
+// jobRequest builds a job request from the submit flags
+func jobRequest(cmd *cobra.Command, jobType, payload, priority string) (*models.JobRequest, error) {
+	if jobType == "" || payload == "" {
+		return nil, errors.Validation("submit requires --type and --payload")
+	}
+
+	level, err := parsePriority(priority)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := readPayload(cmd.InOrStdin(), payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.JobRequest{Type: jobType, Payload: data, Priority: level}, nil
+}
+
+// parsePriority parses a priority given by name or level
+func parsePriority(priority string) (models.JobPriority, error) {
+	if level, ok := models.ParseJobPriority(priority); ok {
+		return level, nil
+	}
+
+	n, err := strconv.Atoi(priority)
+	if err != nil {
+		return 0, errors.Validation("unknown priority %q", priority)
+	}
+
+	return models.JobPriority(n), nil
+}
+
+// readPayload returns the JSON payload given as a literal, @FILE, or @-
+// for stdin
+func readPayload(stdin io.Reader, arg string) (json.RawMessage, error) {
+	data := []byte(arg)
+	if path, ok := strings.CutPrefix(arg, "@"); ok {
+		var err error
+		if path == "-" {
+			data, err = io.ReadAll(stdin)
+		} else {
+			data, err = os.ReadFile(path)
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read payload from %s", path).
+				WithCode(errors.CodeValidation)
+		}
+	}
+
+	if !json.Valid(data) {
+		return nil, errors.Validation("payload is not valid JSON")
+	}
+
+	return json.RawMessage(data), nil
+}
+
+// getCommand builds `taskqueue get`
+func (a *app) getCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get ID",
+		Short: "Show a job",
+		Args:  exactArgs("id"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+
+			job, err := a.api.Get(cmd.Context(), id)
+			if err != nil {
+				return err
+			}
+
+			return a.print(cmd.OutOrStdout(), job, jobDetail(job))
+		},
+	}
+}
+
+// replayCommand builds `taskqueue replay`
+func (a *app) replayCommand() *cobra.Command {
+	var (
+		priority string
+		delay    time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "replay ID",
+		Short: "Run a finished job again as a new job",
+		Args:  exactArgs("id"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+
+			var overrides models.ReplayOverrides
+			if priority != "" {
+				level, err := parsePriority(priority)
+				if err != nil {
+					return err
+				}
+
+				overrides.Priority = &level
+			}
+
+			if delay > 0 {
+				scheduledAt := a.now().Add(delay)
+				overrides.ScheduledAt = &scheduledAt
+			}
+
+			job, err := a.api.Replay(cmd.Context(), id, overrides)
+			if err != nil {
+				return err
+			}
+
+			return a.print(cmd.OutOrStdout(), job, jobDetail(job))
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&priority, "priority", "", "priority of the new job, the original's by default")
+	flags.DurationVar(&delay, "delay", 0, "run the new job after this delay")
+	return cmd
+}
+
+// listCommand builds `taskqueue list`
+func (a *app) listCommand() *cobra.Command {
+	var (
+		statuses []string
+		opts     client.ListOptions
+		since    time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List jobs, newest first",
+		Args:  exactArgs(),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if since < 0 {
+				return errors.Validation("--since must be positive")
+			}
+
+			for _, status := range statuses {
+				opts.Statuses = append(opts.Statuses, models.JobStatus(status))
+			}
+
+			if since > 0 {
+				after := a.now().Add(-since)
+				opts.CreatedAfter = &after
+			}
+
+			page, err := a.api.List(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+
+			if err := a.print(cmd.OutOrStdout(), page, jobTable(page.Items)); err != nil {
+				return err
+			}
+
+			if a.output == formatTable && page.NextCursor != "" {
+				cmd.PrintErrf("more jobs: --cursor %s\n", page.NextCursor)
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVar(&statuses, "status", nil, "only jobs with these statuses")
+	flags.StringSliceVar(&opts.Types, "type", nil, "only jobs of these types")
+	flags.StringSliceVar(&opts.Priorities, "priority", nil, "only jobs with these priorities")
+	flags.DurationVar(&since, "since", 0, "only jobs created within this duration, e.g. 1h")
+	flags.StringVar(&opts.Search, "search", "", "only jobs whose ID, type, or error contains this text")
+	flags.StringVar(&opts.Sort, "sort", "", "sort order: -created_at, created_at, -updated_at, updated_at")
+	flags.IntVar(&opts.Limit, "limit", 0, "jobs per page")
+	flags.StringVar(&opts.Cursor, "cursor", "", "continue a previous listing")
+	return cmd
+}
+
+// watchCommand builds `taskqueue watch`
+func (a *app) watchCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "watch ID",
+		Short: "Stream a job's status changes until it finishes",
+		Args:  exactArgs("id"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+
+			err = a.api.Watch(cmd.Context(), id, func(event eventbus.Event) error {
+				return a.eventLine(cmd.OutOrStdout(), event)
+			})
+
+			// Interrupting a watch is the usual way to stop it
+			if cmd.Context().Err() != nil {
+				return nil
+			}
+
+			return err
+		},
+	}
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"task-queue/internal/eventbus"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/pkg/errors"
+)
+
+// Output formats
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+// print writes v to w as indented JSON in the JSON format, and through
+// table otherwise
+func (a *app) print(w io.Writer, v any, table func(tw *tabwriter.Writer)) error {
+	if a.output == formatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return writeError(encoder.Encode(v))
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	table(tw)
+	return writeError(tw.Flush())
+}
+
+// writeError wraps a failure to write output
+func writeError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return errors.Wrap(err, "failed to write output").WithOp("cli.print")
+}
+
+// jobTable writes jobs as rows
+func jobTable(jobs []*models.Job) func(tw *tabwriter.Writer) {
+	return func(tw *tabwriter.Writer) {
+		fmt.Fprintln(tw, "ID\tTYPE\tSTATUS\tPRIORITY\tRETRIES\tCREATED")
+		for _, job := range jobs {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d/%d\t%s\n", job.ID, job.Type, job.Status,
+				job.Priority.Name(), job.RetryCount, job.MaxRetries, timestamp(&job.CreatedAt))
+		}
+	}
+}
+
+// jobDetail writes one job as a field per line
+func jobDetail(job *models.Job) func(tw *tabwriter.Writer) {
+	return func(tw *tabwriter.Writer) {
+		fmt.Fprintf(tw, "ID:\t%s\n", job.ID)
+		fmt.Fprintf(tw, "Type:\t%s\n", job.Type)
+		fmt.Fprintf(tw, "Status:\t%s\n", job.Status)
+		fmt.Fprintf(tw, "Priority:\t%s\n", job.Priority.Name())
+		fmt.Fprintf(tw, "Retries:\t%d/%d\n", job.RetryCount, job.MaxRetries)
+		fmt.Fprintf(tw, "Created:\t%s\n", timestamp(&job.CreatedAt))
+		fmt.Fprintf(tw, "Scheduled:\t%s\n", timestamp(job.ScheduledAt))
+		fmt.Fprintf(tw, "Started:\t%s\n", timestamp(job.StartedAt))
+		fmt.Fprintf(tw, "Completed:\t%s\n", timestamp(job.CompletedAt))
+		if job.Error != nil {
+			fmt.Fprintf(tw, "Error:\t%s\n", *job.Error)
+		}
+
+		fmt.Fprintf(tw, "Payload:\t%s\n", job.Payload)
+	}
+}
+
+// statsTable writes queue statistics as rows
+func statsTable(stats []*queue.QueueStats) func(tw *tabwriter.Writer) {
+	return func(tw *tabwriter.Writer) {
+		fmt.Fprintln(tw, "QUEUE\tSIZE\tPROCESSING\tDELAYED\tDEAD\tOLDEST\tPAUSED")
+		for _, s := range stats {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%s\t%s\n", s.Name, s.Size, s.Processing,
+				s.Delayed, s.DeadLetter, s.OldestAge.Round(time.Second), strconv.FormatBool(s.Paused))
+		}
+	}
+}
+
+// countTable writes the result of a bulk queue action
+func countTable(verb, name string, count int64) func(tw *tabwriter.Writer) {
+	return func(tw *tabwriter.Writer) {
+		fmt.Fprintf(tw, "%s %d job(s) of queue %s\n", verb, count, name)
+	}
+}
+
+// eventLine writes a watched event as one line: a JSON object in the JSON
+// format, so the stream can be piped line by line
+func (a *app) eventLine(w io.Writer, event eventbus.Event) error {
+	if a.output == formatJSON {
+		return writeError(json.NewEncoder(w).Encode(event))
+	}
+
+	line := fmt.Sprintf("%s  %-22s %-10s retries=%d", timestamp(&event.Time), event.Type,
+		event.Status, event.RetryCount)
+	if event.Error != "" {
+		line += "  error=" + strconv.Quote(event.Error)
+	}
+
+	_, err := fmt.Fprintln(w, line)
+	return writeError(err)
+}
+
+// timestamp formats t for tables, "-" when unset
+func timestamp(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
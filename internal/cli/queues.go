@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"context"
+
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/pkg/errors"
+
+	"github.com/spf13/cobra"
+)
+
+// countResult is the JSON output of a bulk queue action
+type countResult struct {
+	Queue string `json:"queue"`
+	Count int64  `json:"count"`
+}
+
+// statsCommand builds `taskqueue stats`
+func (a *app) statsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stats",
+		Short: "Show the statistics of every queue",
+		Args:  exactArgs(),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			stats, err := a.api.Queues(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			return a.print(cmd.OutOrStdout(), stats, statsTable(stats))
+		},
+	}
+}
+
+// requeueCommand builds `taskqueue requeue-dlq`
+func (a *app) requeueCommand() *cobra.Command {
+	var (
+		name string
+		all  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "requeue-dlq [ID] --queue NAME [--all]",
+		Short: "Return dead-lettered jobs to their queue",
+		Args: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case all && len(args) > 0:
+				return errors.Validation("requeue-dlq takes a job ID or --all, not both")
+
+			case all:
+				return nil
+			}
+
+			return exactArgs("id")(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all {
+				count, err := a.api.RequeueDeadLetters(cmd.Context(), name)
+				if err != nil {
+					return err
+				}
+
+				return a.print(cmd.OutOrStdout(), countResult{Queue: name, Count: count},
+					countTable("Requeued", name, count))
+			}
+
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+
+			job, err := a.api.RequeueDeadLetter(cmd.Context(), name, id)
+			if err != nil {
+				return err
+			}
+
+			return a.print(cmd.OutOrStdout(), job, jobTable([]*models.Job{job}))
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "queue", "default", "dead letter queue to requeue from")
+	cmd.Flags().BoolVar(&all, "all", false, "requeue every dead-lettered job")
+	return cmd
+}
+
+// pauseCommand builds `taskqueue pause`
+func (a *app) pauseCommand() *cobra.Command {
+	return a.toggleCommand("pause", "Stop workers taking jobs from a queue", API.Pause)
+}
+
+// resumeCommand builds `taskqueue resume`
+func (a *app) resumeCommand() *cobra.Command {
+	return a.toggleCommand("resume", "Let workers take jobs from a paused queue", API.Resume)
+}
+
+// toggleCommand builds a command pausing or resuming the queue it is
+// given, printing the queue's statistics
+func (a *app) toggleCommand(use, short string,
+	action func(API, context.Context, string) (*queue.QueueStats, error)) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " QUEUE",
+		Short: short,
+		Args:  exactArgs("queue"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stats, err := action(a.api, cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			return a.print(cmd.OutOrStdout(), stats, statsTable([]*queue.QueueStats{stats}))
+		},
+	}
+}
+
+// drainCommand builds `taskqueue drain`
+func (a *app) drainCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "drain QUEUE",
+		Short: "Delete the ready and delayed jobs of a queue",
+		Args:  exactArgs("queue"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			count, err := a.api.Drain(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			return a.print(cmd.OutOrStdout(), countResult{Queue: args[0], Count: count},
+				countTable("Drained", args[0], count))
+		},
+	}
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthConfig_Validate(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+
+	tests := []struct {
+		name    string
+		cfg     AuthConfig
+		wantErr string
+	}{
+		{name: "disabled", cfg: AuthConfig{}},
+		{name: "database only", cfg: AuthConfig{Enabled: true, Database: true}},
+		{
+			name: "configured key",
+			cfg:  AuthConfig{Enabled: true, Keys: []APIKeyConfig{{Name: "ops", Hash: hash, Scopes: []string{"admin"}}}},
+		},
+		{name: "no keys", cfg: AuthConfig{Enabled: true}, wantErr: "keys or database must be set"},
+		{
+			name:    "negative cache ttl",
+			cfg:     AuthConfig{Enabled: true, Database: true, CacheTTL: -1},
+			wantErr: "cache_ttl",
+		},
+		{
+			name: "duplicate names",
+			cfg: AuthConfig{Enabled: true, Keys: []APIKeyConfig{
+				{Name: "ops", Hash: hash, Scopes: []string{"admin"}},
+				{Name: "ops", Hash: hash, Scopes: []string{"admin"}},
+			}},
+			wantErr: "key 1 must have a unique name",
+		},
+		{
+			name:    "plaintext key",
+			cfg:     AuthConfig{Enabled: true, Keys: []APIKeyConfig{{Name: "ops", Hash: "tq_secret", Scopes: []string{"admin"}}}},
+			wantErr: "hash must be a hex SHA-256",
+		},
+		{
+			name:    "no scopes",
+			cfg:     AuthConfig{Enabled: true, Keys: []APIKeyConfig{{Name: "ops", Hash: hash}}},
+			wantErr: "at least one scope",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
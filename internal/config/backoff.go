@@ -0,0 +1,16 @@
+package config
+
+import (
+	"task-queue/pkg/retry"
+)
+
+// Build creates the configured backoff strategy. An empty strategy name
+// selects exponential backoff.
+func (c BackoffConfig) Build() (retry.BackoffStrategy, error) {
+	name := c.Strategy
+	if name == "" {
+		name = retry.StrategyExponential
+	}
+
+	return retry.StrategyFromConfig(name, c.Params)
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"task-queue/pkg/errors"
+	"task-queue/pkg/retry"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_QueueBackoff(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		cfg, err := Load(writeConfigFile(t, "config.yaml", "server:\n  port: 9001\n"))
+		require.NoError(t, err)
+
+		strategy, err := cfg.Queue.Backoff.Build()
+		require.NoError(t, err)
+		assert.IsType(t, &retry.ExponentialBackoff{}, strategy)
+	})
+
+	t.Run("named strategy with params", func(t *testing.T) {
+		cfg, err := Load(writeConfigFile(t, "config.yaml", `
+queue:
+  backoff:
+    strategy: polynomial
+    params:
+      base: 50ms
+      exponent: 3
+      max: 10s
+`))
+		require.NoError(t, err)
+		assert.Equal(t, "polynomial", cfg.Queue.Backoff.Strategy)
+
+		strategy, err := cfg.Queue.Backoff.Build()
+		require.NoError(t, err)
+		assert.Equal(t, retry.NewPolynomialBackoff(50*time.Millisecond, 3, 10*time.Second), strategy)
+	})
+}
+
+func TestConfig_ValidateQueueBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		backoff BackoffConfig
+		wantErr string
+	}{
+		{name: "empty strategy is exponential", backoff: BackoffConfig{}},
+		{name: "fibonacci", backoff: BackoffConfig{Strategy: "fibonacci",
+			Params: map[string]any{"base": "1s", "max": "1m"}}},
+		{name: "unknown strategy", backoff: BackoffConfig{Strategy: "golden"},
+			wantErr: `unknown backoff strategy "golden"`},
+		{name: "misspelled param", backoff: BackoffConfig{Strategy: "fixed",
+			Params: map[string]any{"dealy": "1s"}}, wantErr: "unknown parameters dealy"},
+		{name: "bad duration", backoff: BackoffConfig{Strategy: "linear",
+			Params: map[string]any{"increment": "soon"}}, wantErr: "increment must be a duration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Queue: QueueConfig{Backoff: tt.backoff}}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid queue backoff configuration")
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.True(t, errors.HasCode(err, errors.CodeConfiguration))
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"net/url"
+
+	"task-queue/internal/queue"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+)
+
+// Validate checks that the fields required by the selected broker type are
+// set and that no other backend is configured
+func (c BrokerConfig) Validate() error {
+	configured := c.configuredBackends()
+	for _, backend := range configured {
+		if backend != c.Type {
+			return errors.Newf("broker type is %q but broker.%s is configured",
+				c.Type, backend).
+				WithCode(errors.CodeConfiguration)
+		}
+	}
+
+	switch c.Type {
+	case "", BrokerRedis:
+		return nil
+
+	case BrokerRabbitMQ:
+		return c.RabbitMQ.Validate()
+
+	case BrokerSQS:
+		return c.SQS.Validate()
+
+	case BrokerNATS:
+		return c.NATS.Validate()
+
+	default:
+		return errors.Newf("unsupported broker type %q", c.Type).
+			WithCode(errors.CodeConfiguration).
+			WithMetadata("supported", []string{
+				BrokerRedis, BrokerRabbitMQ, BrokerSQS, BrokerNATS,
+			})
+	}
+}
+
+// configuredBackends returns the backends whose identifying fields are set
+func (c BrokerConfig) configuredBackends() []string {
+	var backends []string
+	if c.RabbitMQ.URL != "" {
+		backends = append(backends, BrokerRabbitMQ)
+	}
+
+	if c.SQS.Region != "" || len(c.SQS.QueueURLs) > 0 {
+		backends = append(backends, BrokerSQS)
+	}
+
+	if len(c.NATS.Servers) > 0 {
+		backends = append(backends, BrokerNATS)
+	}
+
+	return backends
+}
+
+// Validate checks the RabbitMQ broker settings
+func (c RabbitMQConfig) Validate() error {
+	if c.URL == "" {
+		return errors.New("broker.rabbitmq.url is required").
+			WithCode(errors.CodeConfiguration)
+	}
+
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "amqp" && u.Scheme != "amqps") || u.Host == "" {
+		return errors.New("broker.rabbitmq.url must be an amqp:// or amqps:// URL").
+			WithCode(errors.CodeConfiguration)
+	}
+
+	if c.Exchange == "" {
+		return errors.New("broker.rabbitmq.exchange is required").
+			WithCode(errors.CodeConfiguration)
+	}
+
+	if c.Prefetch < 0 {
+		return errors.New("broker.rabbitmq.prefetch must not be negative").
+			WithCode(errors.CodeConfiguration)
+	}
+
+	if err := c.TLS.Validate(); err != nil {
+		return errors.Wrap(err, "invalid rabbitmq tls configuration")
+	}
+
+	return nil
+}
+
+// Validate checks the SQS broker settings
+func (c SQSConfig) Validate() error {
+	if c.Region == "" {
+		return errors.New("broker.sqs.region is required").
+			WithCode(errors.CodeConfiguration)
+	}
+
+	if len(c.QueueURLs) == 0 {
+		return errors.New("broker.sqs.queue_urls must map at least one queue").
+			WithCode(errors.CodeConfiguration)
+	}
+
+	for name, queueURL := range c.QueueURLs {
+		if u, err := url.Parse(queueURL); err != nil || u.Host == "" {
+			return errors.Newf("broker.sqs.queue_urls.%s is not a valid URL", name).
+				WithCode(errors.CodeConfiguration)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks the NATS broker settings
+func (c NATSConfig) Validate() error {
+	if len(c.Servers) == 0 {
+		return errors.New("broker.nats.servers requires at least one server").
+			WithCode(errors.CodeConfiguration)
+	}
+
+	if c.Stream == "" {
+		return errors.New("broker.nats.stream is required").
+			WithCode(errors.CodeConfiguration)
+	}
+
+	return nil
+}
+
+// QueueConfig converts the queue and worker sections into queue options
+func (c *Config) QueueConfig() queue.Config {
+	cfg := queue.DefaultConfig()
+	cfg.MaxSize = int64(c.Queue.MaxQueueSize)
+	cfg.VisibilityTimeout = c.Queue.VisibilityTimeout
+	cfg.RetentionPeriod = c.Queue.RetentionPeriod
+	cfg.MaxRetries = c.Queue.DeadLetterMaxRetries
+	cfg.PollInterval = c.Queue.PollInterval
+	cfg.BatchSize = c.Worker.BatchSize
+
+	return cfg
+}
+
+// BuildQueue constructs the queue backend selected by the broker section
+func (c *Config) BuildQueue(log logger.Logger) (queue.Queue, error) {
+	if err := c.Broker.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch c.Broker.Type {
+	case "", BrokerRedis:
+		client, err := c.Redis.NewClient()
+		if err != nil {
+			return nil, err
+		}
+
+		return queue.NewRedisQueue(client, c.QueueConfig(), log)
+
+	default:
+		return nil, errors.Newf("broker type %q is not available in this build",
+			c.Broker.Type).
+			WithCode(errors.CodeConfiguration).
+			WithMetadata("broker", c.Broker.Type)
+	}
+}
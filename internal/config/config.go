@@ -2,71 +2,346 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"task-queue/pkg/errors"
+	"task-queue/pkg/retry"
 
 	"github.com/spf13/viper"
 )
 
-// Load loads configuration from file and environment
-func Load(configPath string) (*Config, error) {
-	viper.SetConfigFile(configPath)
-	viper.SetConfigType("yaml")
+// Load loads configuration from file and environment. The file format is
+// detected from the extension (.yaml, .yml, .json, .toml), defaulting to YAML
+// for extensionless paths. Each call uses its own viper instance, so
+// concurrent loads never observe each other's state.
+func Load(configPath string, opts ...Option) (*Config, error) {
+	format, err := formatFromPath(configPath)
+	if err != nil {
+		return nil, err
+	}
 
-	setDefaults()
+	return load(configPath, format, opts...)
+}
 
-	viper.AutomaticEnv()
-	viper.SetEnvPrefix("TQ")
-	if err := viper.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("failed to read config: %w", err)
+// LoadWithFormat loads configuration like Load but uses the given format
+// instead of inspecting the file extension. It is meant for extensionless
+// paths such as /dev/stdin or temporary files.
+func LoadWithFormat(configPath, format string, opts ...Option) (*Config, error) {
+	format, err := normalizeFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	return load(configPath, format, opts...)
+}
+
+// MustLoad loads configuration like Load and panics if it fails. It is meant
+// for main functions that would exit on a config error anyway.
+func MustLoad(configPath string, opts ...Option) *Config {
+	cfg, err := Load(configPath, opts...)
+	if err != nil {
+		panic(fmt.Sprintf("config: failed to load %s: %v", configPath, err))
+	}
+
+	return cfg
+}
+
+// LoadOrDefault loads configuration like Load, but falls back to the defaults
+// when configPath does not exist. Environment overrides and validation still
+// apply in that case.
+func LoadOrDefault(configPath string, opts ...Option) (*Config, error) {
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return decode("yaml", func(*viper.Viper) error {
+			return nil
+		}, opts...)
+	}
+
+	return Load(configPath, opts...)
+}
+
+// Default returns a configuration built purely from the defaults, ignoring
+// config files and the environment. It is useful for tests and local
+// development.
+func Default() *Config {
+	v := viper.New()
+	setDefaults(v)
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg, viper.DecodeHook(decodeHook())); err != nil {
+		panic(fmt.Sprintf("config: invalid defaults: %v", err))
+	}
+
+	return &cfg
+}
+
+// load reads configPath as format and decodes it on top of the defaults
+func load(configPath, format string, opts ...Option) (*Config, error) {
+	return decode(format, func(v *viper.Viper) error {
+		v.SetConfigFile(configPath)
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("failed to read config: %w", err)
+		}
+
+		return nil
+	}, opts...)
+}
+
+// decode prepares a viper instance with defaults and env bindings, lets read
+// supply the configuration source, and decodes and validates the result
+func decode(format string, read func(v *viper.Viper) error,
+	opts ...Option) (*Config, error) {
+	options := loadOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	v := options.viper
+	if v == nil {
+		v = viper.New()
+	}
+
+	v.SetConfigType(format)
+
+	setDefaults(v)
+	bindEnv(v)
+
+	if options.flags != nil {
+		if err := bindFlags(v, options.flags); err != nil {
+			return nil, errors.Wrap(err, "failed to bind flags").
+				WithCode(errors.CodeConfiguration)
+		}
+	}
+
+	if err := read(v); err != nil {
+		return nil, err
 	}
 
 	var config Config
-	if err := viper.Unmarshal(&config); err != nil {
+	if err := v.Unmarshal(&config, viper.DecodeHook(decodeHook())); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	if err := expandEnvReferences(&config, options.strictEnv); err != nil {
+		return nil, err
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
 	return &config, nil
 }
 
-func setDefaults() {
+// WithViper makes Load use a pre-built viper instance instead of a fresh one.
+// Defaults, environment bindings, and the config file are applied on top.
+func WithViper(v *viper.Viper) Option {
+	return func(o *loadOptions) {
+		o.viper = v
+	}
+}
+
+func setDefaults(v *viper.Viper) {
 	// Server defaults
-	viper.SetDefault("server.host", "0.0.0.0")
-	viper.SetDefault("server.port", 8080)
-	viper.SetDefault("server.read_timeout", "30s")
-	viper.SetDefault("server.write_timeout", "30s")
-	viper.SetDefault("server.shutdown_timeout", "10s")
+	v.SetDefault("server.host", "0.0.0.0")
+	v.SetDefault("server.port", 8080)
+	v.SetDefault("server.read_timeout", "30s")
+	v.SetDefault("server.write_timeout", "30s")
+	v.SetDefault("server.shutdown_timeout", "10s")
+	v.SetDefault("server.drain_delay", "5s")
+	v.SetDefault("server.docs", false)
+	v.SetDefault("server.tls_enabled", false)
+	v.SetDefault("server.tls_cert_file", "")
+	v.SetDefault("server.tls_key_file", "")
+	v.SetDefault("server.rate_limit.enabled", false)
+	v.SetDefault("server.rate_limit.backend", RateLimitMemory)
+	v.SetDefault("server.rate_limit.key_prefix", "task-queue:ratelimit")
+	v.SetDefault("server.rate_limit.requests_per_second", 10)
+	v.SetDefault("server.rate_limit.burst", 20)
+	v.SetDefault("server.batch.max_items", 1000)
+	v.SetDefault("server.batch.max_body_bytes", 32<<20)
+	v.SetDefault("server.health.check_timeout", "2s")
+	v.SetDefault("server.health.cache_ttl", "2s")
+
+	// gRPC defaults
+	v.SetDefault("grpc.enabled", false)
+	v.SetDefault("grpc.host", "0.0.0.0")
+	v.SetDefault("grpc.port", 50051)
+	v.SetDefault("grpc.shutdown_timeout", "10s")
+	v.SetDefault("grpc.tls_enabled", false)
+	v.SetDefault("grpc.tls_cert_file", "")
+	v.SetDefault("grpc.tls_key_file", "")
+
+	// Auth defaults
+	v.SetDefault("auth.enabled", false)
+	v.SetDefault("auth.database", false)
+	v.SetDefault("auth.cache_ttl", "1m")
 
 	// Database defaults
-	viper.SetDefault("database.host", "localhost")
-	viper.SetDefault("database.port", 5432)
-	viper.SetDefault("database.ssl_mode", "disable")
-	viper.SetDefault("database.max_connections", 25)
-	viper.SetDefault("database.max_idle_conns", 5)
-	viper.SetDefault("database.conn_max_lifetime", "5m")
+	v.SetDefault("database.host", "localhost")
+	v.SetDefault("database.port", 5432)
+	v.SetDefault("database.user", "taskqueue")
+	v.SetDefault("database.database", "taskqueue")
+	v.SetDefault("database.ssl_mode", "disable")
+	v.SetDefault("database.max_connections", 25)
+	v.SetDefault("database.max_idle_conns", 5)
+	v.SetDefault("database.conn_max_lifetime", "5m")
+	v.SetDefault("database.tls.enabled", false)
+	v.SetDefault("database.tls.insecure_skip_verify", false)
 
 	// Redis defaults
-	viper.SetDefault("redis.host", "localhost")
-	viper.SetDefault("redis.port", 6379)
-	viper.SetDefault("redis.db", 0)
-	viper.SetDefault("redis.pool_size", 10)
-	viper.SetDefault("redis.min_idle_conns", 5)
+	v.SetDefault("redis.mode", RedisModeStandalone)
+	v.SetDefault("redis.route_by_latency", false)
+	v.SetDefault("redis.host", "localhost")
+	v.SetDefault("redis.port", 6379)
+	v.SetDefault("redis.db", 0)
+	v.SetDefault("redis.pool_size", 10)
+	v.SetDefault("redis.min_idle_conns", 5)
+	v.SetDefault("redis.dial_timeout", "5s")
+	v.SetDefault("redis.read_timeout", "3s")
+	v.SetDefault("redis.write_timeout", "3s")
+	v.SetDefault("redis.tls.enabled", false)
+	v.SetDefault("redis.tls.insecure_skip_verify", false)
+
+	// Broker defaults
+	v.SetDefault("broker.type", BrokerRedis)
+	v.SetDefault("broker.rabbitmq.exchange", "task-queue")
+	v.SetDefault("broker.rabbitmq.prefetch", 10)
+	v.SetDefault("broker.rabbitmq.tls.enabled", false)
+	v.SetDefault("broker.rabbitmq.tls.insecure_skip_verify", false)
+	v.SetDefault("broker.nats.stream", "TASK_QUEUE")
 
 	// Queue defaults
-	viper.SetDefault("queue.max_queue_size", 10000)
-	viper.SetDefault("queue.poll_interval", "1s")
-	viper.SetDefault("queue.visibility_timeout", "30m")
-	viper.SetDefault("queue.retention_period", "7d")
+	v.SetDefault("queue.max_queue_size", 10000)
+	v.SetDefault("queue.poll_interval", "1s")
+	v.SetDefault("queue.visibility_timeout", "30m")
+	v.SetDefault("queue.retention_period", "7d")
+	v.SetDefault("queue.dead_letter_max_retries", 3)
+	v.SetDefault("queue.backoff.strategy", retry.StrategyExponential)
 
 	// Worker defaults
-	viper.SetDefault("worker.concurrency", 10)
-	viper.SetDefault("worker.batch_size", 10)
-	viper.SetDefault("worker.process_timeout", "5m")
+	v.SetDefault("worker.concurrency", 10)
+	v.SetDefault("worker.batch_size", 10)
+	v.SetDefault("worker.process_timeout", "5m")
+	v.SetDefault("worker.heartbeat_interval", "30s")
+	v.SetDefault("worker.unknown_type_action", UnknownTypeNack)
+	v.SetDefault("worker.default_type_concurrency", 0)
+	v.SetDefault("worker.throttle_policy", ThrottleWait)
+	v.SetDefault("worker.throttle_max_wait", "1s")
+	v.SetDefault("worker.autoscale.enabled", false)
+	v.SetDefault("worker.autoscale.min_concurrency", 2)
+	v.SetDefault("worker.autoscale.max_concurrency", 50)
+	v.SetDefault("worker.autoscale.interval", "10s")
+	v.SetDefault("worker.autoscale.target_backlog", 10)
+	v.SetDefault("worker.autoscale.target_latency", "1m")
+	v.SetDefault("worker.autoscale.scale_up_cooldown", "30s")
+	v.SetDefault("worker.autoscale.scale_down_cooldown", "2m")
+	v.SetDefault("worker.reserved_lane.workers", 0)
+	v.SetDefault("worker.reserved_lane.min_priority", "high")
+	v.SetDefault("worker.reserved_lane.idle_grace", "5s")
+	v.SetDefault("worker.deadlines.escalate", false)
+	v.SetDefault("worker.deadlines.escalate_interval", "15s")
+
+	// Events defaults
+	v.SetDefault("events.backend", EventsMemory)
+	v.SetDefault("events.channel", "task-queue:events")
+	v.SetDefault("events.buffer_size", 64)
+	v.SetDefault("events.heartbeat_interval", "15s")
 
 	// Metrics defaults
-	viper.SetDefault("metrics.enabled", true)
-	viper.SetDefault("metrics.path", "/metrics")
-	viper.SetDefault("metrics.port", 9090)
+	v.SetDefault("metrics.enabled", true)
+	v.SetDefault("metrics.path", "/metrics")
+	v.SetDefault("metrics.port", 9090)
+
+	// Tracing defaults
+	v.SetDefault("tracing.enabled", false)
+	v.SetDefault("tracing.service_name", "task-queue")
+	v.SetDefault("tracing.collector_url", "http://localhost:4318/v1/traces")
+	v.SetDefault("tracing.sample_rate", 0.1)
 
 	// Log defaults
-	viper.SetDefault("log.level", "info")
-	viper.SetDefault("log.format", "json")
+	v.SetDefault("log.level", "info")
+	v.SetDefault("log.format", "json")
+	v.SetDefault("log.output_path", "stdout")
+	v.SetDefault("log.audit.enabled", false)
+	v.SetDefault("log.audit.output_path", "stdout")
+
+	// Client defaults
+	v.SetDefault("client.url", "http://localhost:8080")
+	v.SetDefault("client.api_key", "")
+	v.SetDefault("client.timeout", "30s")
+}
+
+// configType is the reflected type of Config used to enumerate its keys
+var configType = reflect.TypeOf(Config{})
+
+// bindEnv enables TQ_-prefixed environment overrides for every config key,
+// including keys without a default
+func bindEnv(v *viper.Viper) {
+	v.SetEnvPrefix("TQ")
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	for _, key := range configKeys(configType, "") {
+		_ = v.BindEnv(key)
+	}
+}
+
+// configKeys walks a struct type and returns the dotted mapstructure keys of
+// every leaf field
+func configKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("mapstructure")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		key := tag
+		if prefix != "" {
+			key = prefix + "." + tag
+		}
+
+		if field.Type.Kind() == reflect.Struct &&
+			field.Type != reflect.TypeOf(time.Time{}) {
+			keys = append(keys, configKeys(field.Type, key)...)
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// formatFromPath returns the config format implied by the file extension
+func formatFromPath(path string) (string, error) {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	if ext == "" {
+		return "yaml", nil
+	}
+
+	return normalizeFormat(ext)
+}
+
+// normalizeFormat maps a format name or extension to a viper config type
+func normalizeFormat(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "yaml", "yml":
+		return "yaml", nil
+
+	case "json":
+		return "json", nil
+
+	case "toml":
+		return "toml", nil
+
+	default:
+		return "", errors.Newf("unsupported config format %q", format).
+			WithCode(errors.CodeConfiguration)
+	}
 }
@@ -0,0 +1,270 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"task-queue/pkg/errors"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigFile writes content to a file named name inside a temp dir
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestLoad_ParallelInstancesAreIsolated(t *testing.T) {
+	first := writeConfigFile(t, "first.yaml", `
+server:
+  port: 9001
+log:
+  level: debug
+queue:
+  retention_period: 24h
+`)
+
+	second := writeConfigFile(t, "second.yaml", `
+server:
+  host: 127.0.0.1
+redis:
+  pool_size: 42
+queue:
+  retention_period: 48h
+`)
+
+	t.Run("group", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			t.Run("first", func(t *testing.T) {
+				t.Parallel()
+
+				cfg, err := Load(first)
+				require.NoError(t, err)
+
+				assert.Equal(t, 9001, cfg.Server.Port)
+				assert.Equal(t, "0.0.0.0", cfg.Server.Host)
+				assert.Equal(t, "debug", cfg.Log.Level)
+				assert.Equal(t, 10, cfg.Redis.PoolSize)
+			})
+
+			t.Run("second", func(t *testing.T) {
+				t.Parallel()
+
+				cfg, err := Load(second)
+				require.NoError(t, err)
+
+				assert.Equal(t, 8080, cfg.Server.Port)
+				assert.Equal(t, "127.0.0.1", cfg.Server.Host)
+				assert.Equal(t, "info", cfg.Log.Level)
+				assert.Equal(t, 42, cfg.Redis.PoolSize)
+			})
+		}
+	})
+}
+
+func TestLoad_DoesNotTouchGlobalViper(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+server:
+  port: 9100
+queue:
+  retention_period: 24h
+`)
+
+	_, err := Load(path)
+	require.NoError(t, err)
+
+	assert.False(t, viper.IsSet("server.port"))
+	assert.Empty(t, viper.ConfigFileUsed())
+}
+
+func TestLoad_WithViper(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+queue:
+  retention_period: 24h
+`)
+
+	v := viper.New()
+	v.Set("server.port", 7000)
+
+	cfg, err := Load(path, WithViper(v))
+	require.NoError(t, err)
+
+	assert.Equal(t, 7000, cfg.Server.Port)
+	assert.Equal(t, path, v.ConfigFileUsed())
+}
+
+// sameConfigInEachFormat holds one logical config rendered in every
+// supported format
+var sameConfigInEachFormat = map[string]string{
+	"config.yaml": `
+server:
+  port: 9200
+  read_timeout: 45s
+redis:
+  host: cache.internal
+  tls:
+    enabled: false
+queue:
+  retention_period: 3d
+  poll_interval: 250ms
+worker:
+  concurrency: 4
+tracing:
+  sample_rate: 0.5
+`,
+	"config.json": `{
+  "server": {"port": 9200, "read_timeout": "45s"},
+  "redis": {"host": "cache.internal", "tls": {"enabled": false}},
+  "queue": {"retention_period": "3d", "poll_interval": "250ms"},
+  "worker": {"concurrency": 4},
+  "tracing": {"sample_rate": 0.5}
+}`,
+	"config.toml": `
+[server]
+port = 9200
+read_timeout = "45s"
+
+[redis]
+host = "cache.internal"
+
+[redis.tls]
+enabled = false
+
+[queue]
+retention_period = "3d"
+poll_interval = "250ms"
+
+[worker]
+concurrency = 4
+
+[tracing]
+sample_rate = 0.5
+`,
+}
+
+func TestLoad_FormatsProduceEqualConfigs(t *testing.T) {
+	var reference *Config
+	for name, content := range sameConfigInEachFormat {
+		cfg, err := Load(writeConfigFile(t, name, content))
+		require.NoError(t, err, name)
+
+		assert.Equal(t, 9200, cfg.Server.Port, name)
+		assert.Equal(t, 45*time.Second, cfg.Server.ReadTimeout, name)
+		assert.Equal(t, 72*time.Hour, cfg.Queue.RetentionPeriod, name)
+		assert.Equal(t, 250*time.Millisecond, cfg.Queue.PollInterval, name)
+
+		if reference == nil {
+			reference = cfg
+			continue
+		}
+
+		assert.Equal(t, reference, cfg, name)
+	}
+}
+
+func TestLoad_EnvPrecedenceAcrossFormats(t *testing.T) {
+	t.Setenv("TQ_SERVER_PORT", "9300")
+	t.Setenv("TQ_REDIS_PASSWORD", "from-env")
+	t.Setenv("TQ_QUEUE_RETENTION_PERIOD", "2d")
+
+	for name, content := range sameConfigInEachFormat {
+		cfg, err := Load(writeConfigFile(t, name, content))
+		require.NoError(t, err, name)
+
+		assert.Equal(t, 9300, cfg.Server.Port, name)
+		assert.Equal(t, "from-env", cfg.Redis.Password, name)
+		assert.Equal(t, 48*time.Hour, cfg.Queue.RetentionPeriod, name)
+		assert.Equal(t, "cache.internal", cfg.Redis.Host, name)
+	}
+}
+
+func TestLoadWithFormat_Extensionless(t *testing.T) {
+	path := writeConfigFile(t, "config", sameConfigInEachFormat["config.toml"])
+
+	_, err := Load(path)
+	assert.Error(t, err, "extensionless files default to yaml")
+
+	cfg, err := LoadWithFormat(path, "toml")
+	require.NoError(t, err)
+	assert.Equal(t, 9200, cfg.Server.Port)
+
+	_, err = LoadWithFormat(path, "ini")
+	require.Error(t, err)
+	assert.Equal(t, errors.CodeConfiguration, errors.GetCode(err))
+}
+
+func TestLoad_UnsupportedExtension(t *testing.T) {
+	_, err := Load(writeConfigFile(t, "config.ini", "[server]"))
+
+	require.Error(t, err)
+	assert.Equal(t, errors.CodeConfiguration, errors.GetCode(err))
+}
+
+func TestConfig_ValidateTracingSampleRate(t *testing.T) {
+	for _, rate := range []float64{0, 0.25, 1} {
+		cfg := &Config{Tracing: TracingConfig{SampleRate: rate}}
+		assert.NoError(t, cfg.Validate(), "rate %g", rate)
+	}
+
+	for _, rate := range []float64{-0.1, 1.5} {
+		cfg := &Config{Tracing: TracingConfig{SampleRate: rate}}
+		err := cfg.Validate()
+		require.Error(t, err, "rate %g", rate)
+		assert.Contains(t, err.Error(), "tracing sample_rate must be between 0 and 1")
+		assert.True(t, errors.HasCode(err, errors.CodeConfiguration))
+	}
+}
+
+func TestLoad_WorkerQueues(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+worker:
+  queues:
+    - name: default
+      weight: 3
+    - name: emails
+`)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []WorkerQueueConfig{{Name: "default", Weight: 3}, {Name: "emails"}}, cfg.Worker.Queues)
+}
+
+func TestConfig_ValidateWorkerQueues(t *testing.T) {
+	tests := []struct {
+		name    string
+		queues  []WorkerQueueConfig
+		wantErr string
+	}{
+		{name: "none", queues: nil},
+		{name: "weighted", queues: []WorkerQueueConfig{{Name: "default", Weight: 3}, {Name: "emails", Weight: 1}}},
+		{name: "unnamed", queues: []WorkerQueueConfig{{Weight: 1}}, wantErr: "worker queue 0 must set a name"},
+		{name: "duplicate", queues: []WorkerQueueConfig{{Name: "emails"}, {Name: "emails"}},
+			wantErr: `worker queue "emails" is listed twice`},
+		{name: "negative weight", queues: []WorkerQueueConfig{{Name: "emails", Weight: -1}},
+			wantErr: `worker queue "emails" weight must not be negative`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Worker: WorkerConfig{Queues: tt.queues}}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.True(t, errors.HasCode(err, errors.CodeConfiguration))
+		})
+	}
+}
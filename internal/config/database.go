@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DSN builds a lib/pq connection string from the configuration
+func (c DatabaseConfig) DSN() string {
+	params := []string{
+		fmt.Sprintf("host=%s", c.Host),
+		fmt.Sprintf("port=%d", c.Port),
+	}
+
+	if c.User != "" {
+		params = append(params, fmt.Sprintf("user=%s", quoteDSNValue(c.User)))
+	}
+
+	if c.Password != "" {
+		params = append(params,
+			fmt.Sprintf("password=%s", quoteDSNValue(c.Password)))
+	}
+
+	if c.Database != "" {
+		params = append(params,
+			fmt.Sprintf("dbname=%s", quoteDSNValue(c.Database)))
+	}
+
+	params = append(params, fmt.Sprintf("sslmode=%s", c.sslMode()))
+	if c.TLS.Enabled {
+		if c.TLS.CAFile != "" {
+			params = append(params,
+				fmt.Sprintf("sslrootcert=%s", quoteDSNValue(c.TLS.CAFile)))
+		}
+
+		if c.TLS.CertFile != "" {
+			params = append(params,
+				fmt.Sprintf("sslcert=%s", quoteDSNValue(c.TLS.CertFile)))
+		}
+
+		if c.TLS.KeyFile != "" {
+			params = append(params,
+				fmt.Sprintf("sslkey=%s", quoteDSNValue(c.TLS.KeyFile)))
+		}
+	}
+
+	return strings.Join(params, " ")
+}
+
+// sslMode returns the effective sslmode, upgrading "disable" when TLS is on
+func (c DatabaseConfig) sslMode() string {
+	if !c.TLS.Enabled {
+		if c.SSLMode == "" {
+			return "disable"
+		}
+
+		return c.SSLMode
+	}
+
+	if c.SSLMode != "" && c.SSLMode != "disable" {
+		return c.SSLMode
+	}
+
+	if c.TLS.InsecureSkipVerify {
+		return "require"
+	}
+
+	return "verify-full"
+}
+
+// quoteDSNValue quotes a connection string value when needed
+func quoteDSNValue(v string) string {
+	if v != "" && !strings.ContainsAny(v, ` '\`) {
+		return v
+	}
+
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `'`, `\'`)
+	return "'" + v + "'"
+}
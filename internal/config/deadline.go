@@ -0,0 +1,43 @@
+package config
+
+import (
+	"time"
+
+	"task-queue/internal/models"
+)
+
+// DefaultEscalateInterval is how often deadline escalation runs when
+// EscalateInterval is zero
+const DefaultEscalateInterval = 15 * time.Second
+
+// Deadline returns the longest a job of jobType and priority may wait
+// before it starts, or zero when it has none. A deadline set for the job
+// type wins over the one of its priority.
+func (c DeadlineConfig) Deadline(jobType string, priority models.JobPriority) time.Duration {
+	if d, ok := c.Types[jobType]; ok {
+		return d
+	}
+
+	for name, d := range c.Priorities {
+		if p, ok := models.ParseJobPriority(name); ok && p == priority {
+			return d
+		}
+	}
+
+	return 0
+}
+
+// Enabled reports whether any deadline is set
+func (c DeadlineConfig) Enabled() bool {
+	return len(c.Priorities) > 0 || len(c.Types) > 0
+}
+
+// Interval returns EscalateInterval, or DefaultEscalateInterval when it is
+// zero
+func (c DeadlineConfig) Interval() time.Duration {
+	if c.EscalateInterval > 0 {
+		return c.EscalateInterval
+	}
+
+	return DefaultEscalateInterval
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"task-queue/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadlineConfig_Deadline(t *testing.T) {
+	cfg := DeadlineConfig{
+		Priorities: map[string]time.Duration{"critical": 30 * time.Second, "normal": 10 * time.Minute},
+		Types:      map[string]time.Duration{"payment_capture": 5 * time.Second},
+	}
+
+	assert.Equal(t, 30*time.Second, cfg.Deadline("email_send", models.JobPriorityCritical))
+	assert.Equal(t, 10*time.Minute, cfg.Deadline("email_send", models.JobPriorityNormal))
+	assert.Zero(t, cfg.Deadline("email_send", models.JobPriorityLow), "priority without a deadline")
+	assert.Equal(t, 5*time.Second, cfg.Deadline("payment_capture", models.JobPriorityLow),
+		"the type's deadline wins")
+	assert.True(t, cfg.Enabled())
+	assert.False(t, DeadlineConfig{}.Enabled())
+}
+
+func TestDeadlineConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     DeadlineConfig
+		wantErr string
+	}{
+		{name: "empty", cfg: DeadlineConfig{}},
+		{name: "valid", cfg: DeadlineConfig{
+			Priorities: map[string]time.Duration{"critical": 30 * time.Second},
+			Types:      map[string]time.Duration{"report": time.Hour},
+			Escalate:   true,
+		}},
+		{name: "unknown priority", cfg: DeadlineConfig{Priorities: map[string]time.Duration{"urgent": time.Second}},
+			wantErr: `unsupported priority "urgent"`},
+		{name: "negative priority deadline", cfg: DeadlineConfig{Priorities: map[string]time.Duration{"high": -time.Second}},
+			wantErr: `priority "high" deadline must not be negative`},
+		{name: "negative type deadline", cfg: DeadlineConfig{Types: map[string]time.Duration{"report": -time.Second}},
+			wantErr: `type "report" deadline must not be negative`},
+		{name: "negative interval", cfg: DeadlineConfig{EscalateInterval: -time.Second},
+			wantErr: "escalate_interval must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoad_WorkerDeadlines(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+worker:
+  deadlines:
+    priorities:
+      critical: 30s
+      high: 2m
+    types:
+      payment_capture: 5s
+    escalate: true
+`)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	deadlines := cfg.Worker.Deadlines
+	assert.Equal(t, 30*time.Second, deadlines.Deadline("email_send", models.JobPriorityCritical))
+	assert.Equal(t, 2*time.Minute, deadlines.Deadline("email_send", models.JobPriorityHigh))
+	assert.Equal(t, 5*time.Second, deadlines.Deadline("payment_capture", models.JobPriorityNormal))
+	assert.True(t, deadlines.Escalate)
+	assert.Equal(t, DefaultEscalateInterval, deadlines.Interval())
+}
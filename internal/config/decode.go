@@ -0,0 +1,56 @@
+package config
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+// dayDurationPattern matches durations with a leading whole-day component
+var dayDurationPattern = regexp.MustCompile(`^(\d+)d(.*)$`)
+
+// ParseDuration parses a duration string like time.ParseDuration, and also
+// accepts a leading whole-day unit such as "7d" or "1d12h".
+func ParseDuration(s string) (time.Duration, error) {
+	matches := dayDurationPattern.FindStringSubmatch(s)
+	if matches == nil {
+		return time.ParseDuration(s)
+	}
+
+	days, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, err
+	}
+
+	total := time.Duration(days) * 24 * time.Hour
+	if matches[2] == "" {
+		return total, nil
+	}
+
+	rest, err := time.ParseDuration(matches[2])
+	if err != nil {
+		return 0, err
+	}
+
+	return total + rest, nil
+}
+
+// decodeHook returns the decode hooks used when unmarshalling configuration
+func decodeHook() mapstructure.DecodeHookFunc {
+	return mapstructure.ComposeDecodeHookFunc(
+		stringToDurationHook,
+		mapstructure.StringToSliceHookFunc(","),
+	)
+}
+
+// stringToDurationHook converts strings to time.Duration via ParseDuration
+func stringToDurationHook(from, to reflect.Type, data any) (any, error) {
+	if from.Kind() != reflect.String || to != reflect.TypeOf(time.Duration(0)) {
+		return data, nil
+	}
+
+	return ParseDuration(data.(string))
+}
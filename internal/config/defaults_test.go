@@ -0,0 +1,105 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// optionalKeys lists config keys that intentionally have no default
+var optionalKeys = map[string]bool{
+	"auth.keys":                         true,
+	"broker.nats.credentials_file":      true,
+	"broker.nats.servers":               true,
+	"broker.rabbitmq.tls.ca_file":       true,
+	"broker.rabbitmq.tls.cert_file":     true,
+	"broker.rabbitmq.tls.key_file":      true,
+	"broker.rabbitmq.tls.server_name":   true,
+	"broker.rabbitmq.url":               true,
+	"broker.sqs.profile":                true,
+	"broker.sqs.queue_urls":             true,
+	"broker.sqs.region":                 true,
+	"database.password":                 true,
+	"database.tls.ca_file":              true,
+	"database.tls.cert_file":            true,
+	"database.tls.key_file":             true,
+	"database.tls.server_name":          true,
+	"queue.backoff.params":              true,
+	"redis.addresses":                   true,
+	"redis.master_name":                 true,
+	"redis.password":                    true,
+	"redis.tls.ca_file":                 true,
+	"redis.tls.cert_file":               true,
+	"redis.tls.key_file":                true,
+	"redis.tls.server_name":             true,
+	"server.rate_limit.routes":          true,
+	"server.rate_limit.trusted_proxies": true,
+	"worker.deadlines.priorities":       true,
+	"worker.deadlines.types":            true,
+	"worker.queues":                     true,
+	"worker.types":                      true,
+}
+
+func TestSetDefaults_CoversEveryKey(t *testing.T) {
+	v := viper.New()
+	setDefaults(v)
+
+	for _, key := range configKeys(reflect.TypeOf(Config{}), "") {
+		if optionalKeys[key] {
+			assert.False(t, v.IsSet(key), "%s is allowlisted but has a default", key)
+			continue
+		}
+
+		assert.True(t, v.IsSet(key), "%s has no registered default", key)
+	}
+}
+
+func TestSetDefaults_DecodeIntoConfig(t *testing.T) {
+	v := viper.New()
+	setDefaults(v)
+
+	var cfg Config
+	require.NoError(t, v.Unmarshal(&cfg, viper.DecodeHook(decodeHook())))
+
+	assert.Equal(t, 7*24*time.Hour, cfg.Queue.RetentionPeriod)
+	assert.Equal(t, 30*time.Second, cfg.Worker.HeartbeatInterval)
+	assert.Equal(t, 5*time.Second, cfg.Redis.DialTimeout)
+	assert.Equal(t, 3, cfg.Queue.DeadLetterMaxRetries)
+	assert.True(t, cfg.Metrics.Enabled)
+	assert.False(t, cfg.Tracing.Enabled)
+	assert.Equal(t, 0.1, cfg.Tracing.SampleRate)
+	assert.Equal(t, "http://localhost:4318/v1/traces", cfg.Tracing.CollectorURL)
+	assert.Equal(t, "stdout", cfg.Log.OutputPath)
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"30s", 30 * time.Second, false},
+		{"7d", 7 * 24 * time.Hour, false},
+		{"1d12h", 36 * time.Hour, false},
+		{"0d", 0, false},
+		{"7days", 0, true},
+		{"d", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			d, err := ParseDuration(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, d)
+		})
+	}
+}
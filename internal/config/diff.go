@@ -0,0 +1,180 @@
+package config
+
+import (
+	"maps"
+	"net/url"
+	"reflect"
+	"slices"
+
+	"task-queue/pkg/logger"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// redactedValue replaces secret values in logs and diffs
+const redactedValue = "[REDACTED]"
+
+// secretKeys lists the config keys whose values must never be logged
+var secretKeys = map[string]bool{
+	"database.password":   true,
+	"redis.password":      true,
+	"broker.rabbitmq.url": true,
+	"client.api_key":      true,
+}
+
+// ReloadsTotal counts configuration reloads performed by Watch and
+// WatchRemote, labeled by result ("applied" or "rejected"). Register it with
+// a Prometheus registry to expose it.
+var ReloadsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "config_reloads_total",
+	Help: "Number of configuration reloads by result.",
+}, []string{"result"})
+
+// WithLogger sets the logger Watch and WatchRemote use to report reloads.
+// The global logger is used by default.
+func WithLogger(log logger.Logger) Option {
+	return func(o *loadOptions) {
+		o.logger = log
+	}
+}
+
+// Redacted returns a copy of the configuration with secrets masked, safe to
+// log or print
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	redacted.Broker.SQS.QueueURLs = maps.Clone(c.Broker.SQS.QueueURLs)
+	redacted.Broker.NATS.Servers = slices.Clone(c.Broker.NATS.Servers)
+
+	redacted.Database.Password = redact("database.password", c.Database.Password)
+	redacted.Redis.Password = redact("redis.password", c.Redis.Password)
+	redacted.Broker.RabbitMQ.URL = redact("broker.rabbitmq.url", c.Broker.RabbitMQ.URL)
+	redacted.Client.APIKey = redact("client.api_key", c.Client.APIKey)
+
+	return &redacted
+}
+
+// redact masks a secret value. URLs keep everything but their password.
+func redact(key, value string) string {
+	if value == "" || !secretKeys[key] {
+		return value
+	}
+
+	if u, err := url.Parse(value); err == nil && u.Scheme != "" && u.Host != "" {
+		return u.Redacted()
+	}
+
+	return redactedValue
+}
+
+// Diff returns the values that differ between old and new, keyed by their
+// dotted config key. A nil config is treated as the zero Config.
+func Diff(old, new *Config) []Change {
+	if old == nil {
+		old = &Config{}
+	}
+
+	if new == nil {
+		new = &Config{}
+	}
+
+	var changes []Change
+	diffValues(reflect.ValueOf(old).Elem(), reflect.ValueOf(new).Elem(), "", &changes)
+
+	return changes
+}
+
+// diffValues compares two values of the same type and appends their
+// differences to changes
+func diffValues(old, new reflect.Value, key string, changes *[]Change) {
+	switch old.Kind() {
+	case reflect.Struct:
+		t := old.Type()
+		for i := 0; i < t.NumField(); i++ {
+			tag := t.Field(i).Tag.Get("mapstructure")
+			if tag == "" || tag == "-" {
+				continue
+			}
+
+			diffValues(old.Field(i), new.Field(i), joinKey(key, tag), changes)
+		}
+
+	case reflect.Map:
+		names := make(map[string]bool)
+		for _, m := range []reflect.Value{old, new} {
+			for _, name := range m.MapKeys() {
+				names[name.String()] = true
+			}
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(names)) {
+			mapKey := reflect.ValueOf(name).Convert(old.Type().Key())
+			appendChange(changes, joinKey(key, name),
+				mapValue(old.MapIndex(mapKey)), mapValue(new.MapIndex(mapKey)))
+		}
+
+	default:
+		appendChange(changes, key, old.Interface(), new.Interface())
+	}
+}
+
+// appendChange records a change when the values differ
+func appendChange(changes *[]Change, key string, old, new any) {
+	if reflect.DeepEqual(old, new) {
+		return
+	}
+
+	if secretKeys[key] {
+		old = redact(key, old.(string))
+		new = redact(key, new.(string))
+	}
+
+	*changes = append(*changes, Change{Key: key, Old: old, New: new})
+}
+
+// mapValue returns the value of a map entry, or nil when it is absent
+func mapValue(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+
+	return v.Interface()
+}
+
+// joinKey appends name to a dotted key prefix
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "." + name
+}
+
+// LogEffectiveConfig logs every configuration value, with secrets redacted,
+// as a single Info entry keyed by dotted config key. Call it once at startup.
+func LogEffectiveConfig(log logger.Logger, cfg *Config) {
+	var keysAndValues []any
+	leafValues(reflect.ValueOf(cfg.Redacted()).Elem(), "",
+		func(key string, v reflect.Value) {
+			keysAndValues = append(keysAndValues, key, v.Interface())
+		})
+
+	log.Info("effective configuration", keysAndValues...)
+}
+
+// leafValues calls fn with every leaf value of a config struct
+func leafValues(v reflect.Value, key string, fn func(key string, v reflect.Value)) {
+	if v.Kind() != reflect.Struct {
+		fn(key, v)
+		return
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("mapstructure")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		leafValues(v.Field(i), joinKey(key, tag), fn)
+	}
+}
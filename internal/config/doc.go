@@ -1,6 +1,6 @@
 // Package config provides a centralized configuration management system for
 // the application. It supports configuration loading from multiple sources
-// including YAML files and environment variables, with sensible defaults and
+// including YAML, JSON, or TOML files and environment variables, with sensible defaults and
 // strong typing.
 //
 // The package uses viper for configuration management and provides structured
@@ -9,17 +9,29 @@
 // Configuration Hierarchy:
 //   - Default values are set for all configurable parameters
 //   - Values can be overridden by configuration file
-//   - Environment variables (with TQ_ prefix) take highest precedence
+//   - Environment variables (with TQ_ prefix) override the file
+//   - Command-line flags bound with BindFlags take highest precedence
 //
 // Configuration Structure:
-//   - Server: HTTP server settings including timeouts and TLS
-//   - Database: PostgreSQL connection parameters and pool settings
-//   - Redis: Redis connection and pooling configuration
+//   - Server: HTTP server settings including timeouts, TLS, per-client
+//     rate limits, bulk submission limits, health check timing, and
+//     whether the OpenAPI docs are served
+//   - GRPC: gRPC job service listen address, shutdown timeout, and TLS
+//   - Auth: API keys, configured by hash with their scopes, and key lookup
+//     in the database
+//   - Database: PostgreSQL connection parameters, pool settings, and client TLS
+//   - Redis: Redis standalone, sentinel, or cluster connection, pooling, and
+//     client TLS configuration
+//   - Broker: Queue backend selection (redis, rabbitmq, sqs, nats)
 //   - Queue: Job queue parameters including visibility and retention
-//   - Worker: Concurrency and processing settings
+//   - Worker: Concurrency, per-type limits, autoscaling, wait deadlines
+//     with escalation, weighted queues to consume, and processing settings
+//   - Events: Job event bus backend (memory or redis pub/sub) and stream
+//     heartbeats
 //   - Metrics: Prometheus metrics endpoint configuration
-//   - Tracing: Distributed tracing setup (e.g., Jaeger)
+//   - Tracing: OpenTelemetry span export over OTLP/HTTP and sample rate
 //   - Log: Logging format and level configuration
+//   - Client: API URL, API key, and request timeout of the taskqueue CLI
 //
 // Usage:
 //
@@ -29,7 +41,32 @@
 //	}
 //	fmt.Println("Server port:", cfg.Server.Port)
 //
+// The file format is detected from the extension. Use LoadWithFormat for
+// extensionless paths:
+//
+//	cfg, err := config.LoadWithFormat("/dev/stdin", "json")
+//
+// Remote configuration is read through viper's remote provider from Consul,
+// etcd, Firestore, or NATS, and both file and remote sources can be watched
+// for changes. Consul's ACL token comes from CONSUL_HTTP_TOKEN. Reloads are
+// logged as a redacted diff and counted in ReloadsTotal
+// (config_reloads_total):
+//
+//	opts := config.RemoteOptions{
+//	    Provider: "consul",
+//	    Endpoint: "127.0.0.1:8500",
+//	    Path:     "task-queue/config.yaml",
+//	}
+//	cfg, err := config.LoadRemote(opts)
+//	go config.WatchRemote(ctx, opts, func(cfg *config.Config, err error) {
+//	    // apply or reject the new configuration
+//	})
+//
 // Environment Variables:
 // All configuration can be overridden with environment variables using the pattern:
 // TQ_SERVER_PORT=8081 would override the server port setting.
+//
+// String values may reference environment variables as ${VAR} or
+// ${VAR:-default}; write $${VAR} for a literal ${VAR}. Undefined references
+// expand to an empty string unless WithStrictEnvExpansion is passed.
 package config
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"context"
+
+	"task-queue/internal/eventbus"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+)
+
+// BuildEventBus constructs the event bus selected by the events section.
+// The redis backend connects with the redis section.
+func (c *Config) BuildEventBus(ctx context.Context, log logger.Logger) (eventbus.Bus, error) {
+	opts := []eventbus.Option{eventbus.WithBufferSize(c.Events.BufferSize)}
+
+	switch c.Events.Backend {
+	case "", EventsMemory:
+		return eventbus.NewMemoryBus(opts...), nil
+
+	case EventsRedis:
+		client, err := c.Redis.NewClient()
+		if err != nil {
+			return nil, err
+		}
+
+		bus, err := eventbus.NewRedisBus(ctx, client, c.Events.Channel, log, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return bus, nil
+
+	default:
+		return nil, errors.Newf("unsupported events backend %q", c.Events.Backend).
+			WithCode(errors.CodeConfiguration).
+			WithMetadata("supported", []string{EventsMemory, EventsRedis})
+	}
+}
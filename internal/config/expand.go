@@ -0,0 +1,116 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+
+	"task-queue/pkg/errors"
+)
+
+// envReferencePattern matches $${escaped}, ${VAR}, and ${VAR:-default}
+var envReferencePattern = regexp.MustCompile(
+	`\$\$\{([^}]*)\}|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// WithStrictEnvExpansion makes loading fail when a ${VAR} reference without
+// a default names an undefined environment variable. By default such
+// references expand to an empty string.
+func WithStrictEnvExpansion() Option {
+	return func(o *loadOptions) {
+		o.strictEnv = true
+	}
+}
+
+// expandEnvReferences expands environment references in every string value of
+// the config tree
+func expandEnvReferences(cfg *Config, strict bool) error {
+	undefined := make(map[string]string)
+	expandValue(reflect.ValueOf(cfg).Elem(), "", undefined)
+
+	if !strict || len(undefined) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(undefined))
+	for key := range undefined {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return errors.Newf("undefined environment variable %s referenced by %s",
+		undefined[keys[0]], keys[0]).
+		WithCode(errors.CodeConfiguration).
+		WithMetadata("undefined", undefined)
+}
+
+// expandValue walks v and rewrites string values in place, recording the
+// dotted key of each undefined variable reference
+func expandValue(v reflect.Value, key string, undefined map[string]string) {
+	switch v.Kind() {
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(expandString(v.String(), key, undefined))
+		}
+
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			tag := t.Field(i).Tag.Get("mapstructure")
+			if tag == "" || tag == "-" {
+				continue
+			}
+
+			child := tag
+			if key != "" {
+				child = key + "." + tag
+			}
+
+			expandValue(v.Field(i), child, undefined)
+		}
+
+	case reflect.Pointer:
+		if !v.IsNil() {
+			expandValue(v.Elem(), key, undefined)
+		}
+
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			expandValue(v.Index(i), key, undefined)
+		}
+
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.String {
+			return
+		}
+
+		for _, mapKey := range v.MapKeys() {
+			expanded := expandString(v.MapIndex(mapKey).String(), key, undefined)
+			v.SetMapIndex(mapKey, reflect.ValueOf(expanded).Convert(v.Type().Elem()))
+		}
+	}
+}
+
+// expandString expands the references in s
+func expandString(s, key string, undefined map[string]string) string {
+	return envReferencePattern.ReplaceAllStringFunc(s, func(match string) string {
+		groups := envReferencePattern.FindStringSubmatch(match)
+		if groups[2] == "" {
+			return "${" + groups[1] + "}"
+		}
+
+		if value, ok := os.LookupEnv(groups[2]); ok && value != "" {
+			return value
+		}
+
+		if groups[3] != "" {
+			return groups[4]
+		}
+
+		if _, ok := os.LookupEnv(groups[2]); !ok {
+			undefined[key] = groups[2]
+		}
+
+		return ""
+	})
+}
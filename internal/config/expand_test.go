@@ -0,0 +1,115 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"task-queue/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandString(t *testing.T) {
+	t.Setenv("TQ_TEST_HOST", "db.internal")
+	t.Setenv("TQ_TEST_EMPTY", "")
+
+	tests := []struct {
+		input     string
+		expected  string
+		undefined bool
+	}{
+		{"${TQ_TEST_HOST}", "db.internal", false},
+		{"postgres://${TQ_TEST_HOST}:5432", "postgres://db.internal:5432", false},
+		{"${TQ_TEST_MISSING:-fallback}", "fallback", false},
+		{"${TQ_TEST_EMPTY:-fallback}", "fallback", false},
+		{"${TQ_TEST_HOST:-fallback}", "db.internal", false},
+		{"${TQ_TEST_MISSING:-}", "", false},
+		{"$${TQ_TEST_HOST}", "${TQ_TEST_HOST}", false},
+		{"${TQ_TEST_EMPTY}", "", false},
+		{"${TQ_TEST_MISSING}", "", true},
+		{"$TQ_TEST_HOST", "$TQ_TEST_HOST", false},
+		{"plain", "plain", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			undefined := make(map[string]string)
+
+			assert.Equal(t, tt.expected, expandString(tt.input, "key", undefined))
+			assert.Equal(t, tt.undefined, len(undefined) > 0)
+		})
+	}
+}
+
+func TestLoad_ExpandsEnvReferences(t *testing.T) {
+	t.Setenv("TQ_TEST_DB_PASSWORD", "s3cret")
+	t.Setenv("TQ_TEST_REDIS_HOST", "cache.internal")
+
+	path := writeConfigFile(t, "config.yaml", `
+server:
+  host: ${TQ_TEST_SERVER_HOST:-127.0.0.1}
+database:
+  password: ${TQ_TEST_DB_PASSWORD}
+redis:
+  host: ${TQ_TEST_REDIS_HOST}
+  tls:
+    server_name: ${TQ_TEST_REDIS_HOST}
+log:
+  output_path: $${HOME}/task-queue.log
+`)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "127.0.0.1", cfg.Server.Host)
+	assert.Equal(t, "s3cret", cfg.Database.Password)
+	assert.Equal(t, "cache.internal", cfg.Redis.Host)
+	assert.Equal(t, "cache.internal", cfg.Redis.TLS.ServerName)
+	assert.Equal(t, "${HOME}/task-queue.log", cfg.Log.OutputPath)
+}
+
+func TestLoad_UndefinedEnvReferences(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+database:
+  password: ${TQ_TEST_UNDEFINED_PASSWORD}
+`)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Database.Password)
+
+	_, err = Load(path, WithStrictEnvExpansion())
+	require.Error(t, err)
+	assert.Equal(t, errors.CodeConfiguration, errors.GetCode(err))
+	assert.Contains(t, err.Error(), "TQ_TEST_UNDEFINED_PASSWORD")
+	assert.Contains(t, err.Error(), "database.password")
+}
+
+func TestExpandEnvReferences_NestedCollections(t *testing.T) {
+	t.Setenv("TQ_TEST_REGION", "eu-west-1")
+
+	type nested struct {
+		Tags   []string          `mapstructure:"tags"`
+		Labels map[string]string `mapstructure:"labels"`
+		Inner  *struct {
+			Name string `mapstructure:"name"`
+		} `mapstructure:"inner"`
+	}
+
+	value := nested{
+		Tags:   []string{"region=${TQ_TEST_REGION}"},
+		Labels: map[string]string{"region": "${TQ_TEST_REGION}"},
+		Inner: &struct {
+			Name string `mapstructure:"name"`
+		}{Name: "${TQ_TEST_NOPE:-worker}"},
+	}
+
+	undefined := make(map[string]string)
+	expandValue(reflect.ValueOf(&value).Elem(), "", undefined)
+
+	assert.Equal(t, []string{"region=eu-west-1"}, value.Tags)
+	assert.Equal(t, "eu-west-1", value.Labels["region"])
+	assert.Equal(t, "worker", value.Inner.Name)
+	assert.Empty(t, undefined)
+}
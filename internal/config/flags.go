@@ -0,0 +1,85 @@
+package config
+
+import (
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// flagKeys lists the config keys exposed as command-line flags. Flag names
+// are the dotted config keys so documentation stays consistent.
+var flagKeys = []struct {
+	key   string
+	usage string
+}{
+	{"server.host", "HTTP server listen host"},
+	{"server.port", "HTTP server listen port"},
+	{"grpc.enabled", "serve the gRPC job service"},
+	{"grpc.port", "gRPC server listen port"},
+	{"log.level", "log level (debug, info, warn, error, fatal)"},
+	{"log.format", "log format (json, console)"},
+	{"database.host", "PostgreSQL host"},
+	{"database.port", "PostgreSQL port"},
+	{"database.database", "PostgreSQL database name"},
+	{"redis.host", "Redis host"},
+	{"redis.port", "Redis port"},
+	{"worker.concurrency", "number of concurrent workers"},
+	{"metrics.enabled", "serve Prometheus metrics"},
+}
+
+// BindFlags registers flags for the most commonly overridden config keys on
+// fs, e.g. --server.port=9000 or --log.level=debug
+func BindFlags(fs *pflag.FlagSet) {
+	defaults := viper.New()
+	setDefaults(defaults)
+
+	for _, f := range flagKeys {
+		if fs.Lookup(f.key) != nil {
+			continue
+		}
+
+		switch value := defaults.Get(f.key).(type) {
+		case int:
+			fs.Int(f.key, value, f.usage)
+
+		case bool:
+			fs.Bool(f.key, value, f.usage)
+
+		default:
+			fs.String(f.key, defaults.GetString(f.key), f.usage)
+		}
+	}
+}
+
+// LoadWithFlags loads configuration like Load with flags from fs layered on
+// top. Precedence is flags > env > file > defaults; only flags explicitly
+// set on the command line override other sources.
+func LoadWithFlags(configPath string, fs *pflag.FlagSet, opts ...Option) (*Config, error) {
+	return Load(configPath, append(opts, WithFlags(fs))...)
+}
+
+// WithFlags layers explicitly set flags from fs whose names match config keys
+// on top of every other source
+func WithFlags(fs *pflag.FlagSet) Option {
+	return func(o *loadOptions) {
+		o.flags = fs
+	}
+}
+
+// bindFlags binds every flag in fs named after a config key
+func bindFlags(v *viper.Viper, fs *pflag.FlagSet) error {
+	keys := make(map[string]bool)
+	for _, key := range configKeys(configType, "") {
+		keys[key] = true
+	}
+
+	var err error
+	fs.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || !keys[flag.Name] {
+			return
+		}
+
+		err = v.BindPFlag(flag.Name, flag)
+	})
+
+	return err
+}
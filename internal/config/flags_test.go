@@ -0,0 +1,66 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindFlags_RegistersDottedKeys(t *testing.T) {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	BindFlags(fs)
+
+	for _, f := range flagKeys {
+		assert.NotNil(t, fs.Lookup(f.key), f.key)
+	}
+
+	assert.Equal(t, "8080", fs.Lookup("server.port").DefValue)
+	assert.Equal(t, "true", fs.Lookup("metrics.enabled").DefValue)
+	assert.Equal(t, "info", fs.Lookup("log.level").DefValue)
+}
+
+func TestLoadWithFlags_Precedence(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+server:
+  host: file-host
+  port: 9001
+log:
+  level: warn
+  format: console
+worker:
+  concurrency: 3
+`)
+
+	t.Setenv("TQ_SERVER_PORT", "9002")
+	t.Setenv("TQ_LOG_LEVEL", "error")
+	t.Setenv("TQ_WORKER_CONCURRENCY", "6")
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	BindFlags(fs)
+	fs.String("unrelated", "", "not a config key")
+	require.NoError(t, fs.Parse([]string{
+		"--server.port=9003",
+		"--log.level=debug",
+		"--unrelated=x",
+	}))
+
+	cfg, err := LoadWithFlags(path, fs)
+	require.NoError(t, err)
+
+	// flags beat env, file, and defaults
+	assert.Equal(t, 9003, cfg.Server.Port)
+	assert.Equal(t, "debug", cfg.Log.Level)
+
+	// env beats file when no flag is set
+	assert.Equal(t, 6, cfg.Worker.Concurrency)
+
+	// file beats defaults
+	assert.Equal(t, "file-host", cfg.Server.Host)
+	assert.Equal(t, "console", cfg.Log.Format)
+
+	// defaults fill everything else, including unset flags
+	assert.Equal(t, 6379, cfg.Redis.Port)
+	assert.True(t, cfg.Metrics.Enabled)
+}
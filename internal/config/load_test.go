@@ -0,0 +1,54 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefault(t *testing.T) {
+	t.Setenv("TQ_SERVER_PORT", "9999")
+
+	cfg := Default()
+
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, 8080, cfg.Server.Port, "Default ignores the environment")
+	assert.Equal(t, 7*24*time.Hour, cfg.Queue.RetentionPeriod)
+	assert.Equal(t, BrokerRedis, cfg.Broker.Type)
+	assert.NotSame(t, Default(), cfg)
+}
+
+func TestLoadOrDefault(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		t.Setenv("TQ_SERVER_PORT", "9400")
+
+		cfg, err := LoadOrDefault(filepath.Join(t.TempDir(), "missing.yaml"))
+		require.NoError(t, err)
+
+		assert.Equal(t, 9400, cfg.Server.Port)
+		assert.Equal(t, 7*24*time.Hour, cfg.Queue.RetentionPeriod)
+	})
+
+	t.Run("existing file", func(t *testing.T) {
+		cfg, err := LoadOrDefault(writeConfigFile(t, "config.yaml", "server:\n  port: 9500\n"))
+		require.NoError(t, err)
+
+		assert.Equal(t, 9500, cfg.Server.Port)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		_, err := LoadOrDefault(writeConfigFile(t, "config.yaml", "broker:\n  type: kafka\n"))
+		assert.Error(t, err)
+	})
+}
+
+func TestMustLoad(t *testing.T) {
+	cfg := MustLoad(writeConfigFile(t, "config.yaml", "server:\n  port: 9600\n"))
+	assert.Equal(t, 9600, cfg.Server.Port)
+
+	missing := filepath.Join(t.TempDir(), "missing.yaml")
+	assert.Panics(t, func() { MustLoad(missing) })
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"task-queue/pkg/logger"
+)
+
+// LoggerConfig returns the logger configuration for the log section, on top
+// of logger.DefaultConfig
+func (c LogConfig) LoggerConfig() logger.Config {
+	cfg := logger.DefaultConfig()
+	if c.Level != "" {
+		cfg.Level = c.Level
+	}
+
+	if c.Format != "" {
+		cfg.Format = c.Format
+	}
+
+	if c.OutputPath != "" {
+		cfg.OutputPaths = []string{c.OutputPath}
+	}
+
+	return cfg
+}
+
+// AuditConfig returns the audit logger configuration for the log section
+func (c LogConfig) AuditConfig() logger.AuditConfig {
+	return logger.AuditConfig{
+		Enabled:    c.Audit.Enabled,
+		OutputPath: c.Audit.OutputPath,
+	}
+}
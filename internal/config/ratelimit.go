@@ -0,0 +1,74 @@
+package config
+
+import (
+	"net/netip"
+	"strings"
+
+	"task-queue/internal/ratelimit"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+)
+
+// TrustedPrefixes parses TrustedProxies, reading a bare IP as a single
+// address prefix. An invalid entry is reported in the error and skipped;
+// the valid prefixes are returned either way.
+func (c RateLimitConfig) TrustedPrefixes() ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(c.TrustedProxies))
+	var errs []error
+	for _, proxy := range c.TrustedProxies {
+		if strings.Contains(proxy, "/") {
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				errs = append(errs, errors.Wrapf(err, "invalid trusted proxy %q", proxy).
+					WithCode(errors.CodeConfiguration))
+				continue
+			}
+
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(proxy)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "invalid trusted proxy %q", proxy).
+				WithCode(errors.CodeConfiguration))
+			continue
+		}
+
+		prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return prefixes, err
+	}
+
+	return prefixes, nil
+}
+
+// BuildRateLimiter constructs the limiter selected by the server rate_limit
+// section. The redis backend connects with the redis section.
+func (c *Config) BuildRateLimiter(log logger.Logger) (ratelimit.Limiter, error) {
+	limits := c.Server.RateLimit
+	switch limits.Backend {
+	case "", RateLimitMemory:
+		return ratelimit.NewMemoryLimiter(), nil
+
+	case RateLimitRedis:
+		client, err := c.Redis.NewClient()
+		if err != nil {
+			return nil, err
+		}
+
+		limiter, err := ratelimit.NewRedisLimiter(client, limits.KeyPrefix, log)
+		if err != nil {
+			return nil, err
+		}
+
+		return limiter, nil
+
+	default:
+		return nil, errors.Newf("unsupported rate limit backend %q", limits.Backend).
+			WithCode(errors.CodeConfiguration).
+			WithMetadata("supported", []string{RateLimitMemory, RateLimitRedis})
+	}
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"net/netip"
+	"testing"
+
+	"task-queue/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitConfig_Validate(t *testing.T) {
+	valid := RateLimitConfig{
+		Enabled:           true,
+		Backend:           RateLimitRedis,
+		RequestsPerSecond: 10,
+		Burst:             20,
+		TrustedProxies:    []string{"10.0.0.0/8", "192.0.2.1"},
+		Routes: []RouteRateLimitConfig{
+			{Method: "POST", Path: "/v1/jobs", RequestsPerSecond: 2, Burst: 5},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*RateLimitConfig)
+		wantErr string
+	}{
+		{name: "valid", modify: func(*RateLimitConfig) {}},
+		{name: "disabled is not checked", modify: func(c *RateLimitConfig) { *c = RateLimitConfig{} }},
+		{name: "unknown backend", modify: func(c *RateLimitConfig) { c.Backend = "etcd" }, wantErr: "unsupported backend"},
+		{name: "zero rate", modify: func(c *RateLimitConfig) { c.RequestsPerSecond = 0 }, wantErr: "requests_per_second"},
+		{name: "zero burst", modify: func(c *RateLimitConfig) { c.Burst = 0 }, wantErr: "burst at least 1"},
+		{name: "bad proxy", modify: func(c *RateLimitConfig) { c.TrustedProxies = []string{"10.0.0.0/33"} }, wantErr: "invalid trusted proxy"},
+		{
+			name:    "route without path",
+			modify:  func(c *RateLimitConfig) { c.Routes[0].Path = "" },
+			wantErr: "route 0 must set a method",
+		},
+		{
+			name:    "route without burst",
+			modify:  func(c *RateLimitConfig) { c.Routes[0].Burst = 0 },
+			wantErr: "route POST /v1/jobs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			cfg.Routes = append([]RouteRateLimitConfig(nil), valid.Routes...)
+			tt.modify(&cfg)
+
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestRateLimitConfig_TrustedPrefixes(t *testing.T) {
+	prefixes, err := RateLimitConfig{TrustedProxies: []string{"10.1.2.3/8", "192.0.2.1", "2001:db8::/32"}}.TrustedPrefixes()
+	require.NoError(t, err)
+	assert.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("192.0.2.1/32"),
+		netip.MustParsePrefix("2001:db8::/32"),
+	}, prefixes)
+}
+
+func TestRateLimitConfig_TrustedPrefixesSkipsInvalid(t *testing.T) {
+	prefixes, err := RateLimitConfig{TrustedProxies: []string{"10.0.0.0/8", "nonsense", "192.0.2.0/33"}}.TrustedPrefixes()
+	require.Error(t, err)
+	assert.True(t, errors.HasCode(err, errors.CodeConfiguration))
+	assert.Contains(t, err.Error(), `"nonsense"`)
+	assert.Contains(t, err.Error(), `"192.0.2.0/33"`)
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}, prefixes)
+}
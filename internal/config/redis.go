@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+
+	"task-queue/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Addr returns the host:port address of the standalone Redis server
+func (c RedisConfig) Addr() string {
+	if len(c.Addresses) > 0 {
+		return c.Addresses[0]
+	}
+
+	return fmt.Sprintf("%s:%d", c.Host, c.Port)
+}
+
+// Addrs returns the addresses for the configured mode. Standalone mode falls
+// back to the deprecated Host and Port when Addresses is empty.
+func (c RedisConfig) Addrs() []string {
+	if len(c.Addresses) > 0 || c.mode() != RedisModeStandalone {
+		return c.Addresses
+	}
+
+	return []string{c.Addr()}
+}
+
+// mode returns the deployment mode, treating an empty mode as standalone
+func (c RedisConfig) mode() string {
+	if c.Mode == "" {
+		return RedisModeStandalone
+	}
+
+	return c.Mode
+}
+
+// Validate checks that the addresses match the deployment mode
+func (c RedisConfig) Validate() error {
+	switch c.mode() {
+	case RedisModeStandalone:
+		if len(c.Addresses) > 1 {
+			return errors.New("redis standalone mode accepts a single address").
+				WithCode(errors.CodeConfiguration).
+				WithMetadata("addresses", c.Addresses)
+		}
+
+	case RedisModeSentinel:
+		if c.MasterName == "" {
+			return errors.New("redis sentinel mode requires master_name").
+				WithCode(errors.CodeConfiguration)
+		}
+
+		if len(c.Addresses) == 0 {
+			return errors.New("redis sentinel mode requires at least one sentinel address").
+				WithCode(errors.CodeConfiguration)
+		}
+
+	case RedisModeCluster:
+		// Three or more seed addresses are recommended so the client can
+		// still discover the cluster when a node is down
+		if len(c.Addresses) == 0 {
+			return errors.New("redis cluster mode requires at least one address").
+				WithCode(errors.CodeConfiguration)
+		}
+
+		if c.DB != 0 {
+			return errors.New("redis cluster mode does not support db").
+				WithCode(errors.CodeConfiguration)
+		}
+
+	default:
+		return errors.Newf("unsupported redis mode %q", c.Mode).
+			WithCode(errors.CodeConfiguration).
+			WithMetadata("supported", []string{
+				RedisModeStandalone, RedisModeSentinel, RedisModeCluster,
+			})
+	}
+
+	if err := c.TLS.Validate(); err != nil {
+		return errors.Wrap(err, "invalid redis tls configuration")
+	}
+
+	return nil
+}
+
+// Options converts the configuration into go-redis client options for a
+// standalone server
+func (c RedisConfig) Options() (*redis.Options, error) {
+	opts, err := c.UniversalOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	return opts.Simple(), nil
+}
+
+// UniversalOptions converts the configuration into go-redis options for any
+// deployment mode
+func (c RedisConfig) UniversalOptions() (*redis.UniversalOptions, error) {
+	tlsConfig, err := c.TLS.BuildTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &redis.UniversalOptions{
+		Addrs:          c.Addrs(),
+		Password:       c.Password,
+		DB:             c.DB,
+		PoolSize:       c.PoolSize,
+		MinIdleConns:   c.MinIdleConns,
+		DialTimeout:    c.DialTimeout,
+		ReadTimeout:    c.ReadTimeout,
+		WriteTimeout:   c.WriteTimeout,
+		TLSConfig:      tlsConfig,
+		RouteByLatency: c.RouteByLatency,
+	}
+
+	switch c.mode() {
+	case RedisModeSentinel:
+		opts.MasterName = c.MasterName
+
+	case RedisModeCluster:
+		opts.IsClusterMode = true
+	}
+
+	return opts, nil
+}
+
+// NewClient creates a Redis client for the configured deployment mode
+func (c RedisConfig) NewClient() (redis.UniversalClient, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	opts, err := c.UniversalOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	return redis.NewUniversalClient(opts), nil
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"testing"
+
+	"task-queue/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     RedisConfig
+		wantErr string
+	}{
+		{name: "standalone host and port", cfg: RedisConfig{Host: "localhost", Port: 6379}},
+		{name: "empty mode is standalone", cfg: RedisConfig{Addresses: []string{"redis:6379"}}},
+		{
+			name: "standalone with several addresses",
+			cfg: RedisConfig{
+				Mode:      RedisModeStandalone,
+				Addresses: []string{"redis-1:6379", "redis-2:6379"},
+			},
+			wantErr: "single address",
+		},
+		{
+			name: "sentinel",
+			cfg: RedisConfig{
+				Mode:       RedisModeSentinel,
+				Addresses:  []string{"sentinel-1:26379"},
+				MasterName: "mymaster",
+			},
+		},
+		{
+			name:    "sentinel without master name",
+			cfg:     RedisConfig{Mode: RedisModeSentinel, Addresses: []string{"sentinel-1:26379"}},
+			wantErr: "master_name",
+		},
+		{
+			name:    "sentinel without addresses",
+			cfg:     RedisConfig{Mode: RedisModeSentinel, MasterName: "mymaster", Host: "localhost"},
+			wantErr: "at least one sentinel address",
+		},
+		{
+			name: "cluster with a single seed",
+			cfg:  RedisConfig{Mode: RedisModeCluster, Addresses: []string{"cluster:6379"}},
+		},
+		{
+			name:    "cluster without addresses",
+			cfg:     RedisConfig{Mode: RedisModeCluster, Host: "localhost", Port: 6379},
+			wantErr: "at least one address",
+		},
+		{
+			name:    "cluster with db",
+			cfg:     RedisConfig{Mode: RedisModeCluster, Addresses: []string{"cluster:6379"}, DB: 2},
+			wantErr: "does not support db",
+		},
+		{
+			name:    "unknown mode",
+			cfg:     RedisConfig{Mode: "ring"},
+			wantErr: "unsupported redis mode",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Equal(t, errors.CodeConfiguration, errors.GetCode(err))
+		})
+	}
+}
+
+func TestRedisConfig_UniversalOptions(t *testing.T) {
+	t.Run("standalone falls back to host and port", func(t *testing.T) {
+		opts, err := RedisConfig{Host: "cache", Port: 6380, DB: 3}.UniversalOptions()
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"cache:6380"}, opts.Addrs)
+		assert.Equal(t, 3, opts.DB)
+		assert.Empty(t, opts.MasterName)
+		assert.False(t, opts.IsClusterMode)
+	})
+
+	t.Run("standalone prefers addresses", func(t *testing.T) {
+		cfg := RedisConfig{Host: "cache", Port: 6380, Addresses: []string{"redis:6379"}}
+
+		opts, err := cfg.Options()
+		require.NoError(t, err)
+		assert.Equal(t, "redis:6379", opts.Addr)
+	})
+
+	t.Run("sentinel", func(t *testing.T) {
+		cfg := RedisConfig{
+			Mode:       RedisModeSentinel,
+			Addresses:  []string{"sentinel-1:26379", "sentinel-2:26379"},
+			MasterName: "mymaster",
+			Host:       "ignored",
+		}
+
+		opts, err := cfg.UniversalOptions()
+		require.NoError(t, err)
+
+		assert.Equal(t, cfg.Addresses, opts.Addrs)
+		assert.Equal(t, "mymaster", opts.MasterName)
+		assert.Equal(t, cfg.Addresses, opts.Failover().SentinelAddrs)
+	})
+
+	t.Run("cluster", func(t *testing.T) {
+		cfg := RedisConfig{
+			Mode:           RedisModeCluster,
+			Addresses:      []string{"node-1:6379", "node-2:6379", "node-3:6379"},
+			RouteByLatency: true,
+		}
+
+		opts, err := cfg.UniversalOptions()
+		require.NoError(t, err)
+
+		assert.True(t, opts.IsClusterMode)
+		assert.True(t, opts.Cluster().RouteByLatency)
+		assert.Equal(t, cfg.Addresses, opts.Cluster().Addrs)
+	})
+}
+
+func TestRedisConfig_NewClient(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      RedisConfig
+		expected any
+	}{
+		{
+			name:     "standalone",
+			cfg:      RedisConfig{Host: "localhost", Port: 6379},
+			expected: &redis.Client{},
+		},
+		{
+			name: "sentinel",
+			cfg: RedisConfig{
+				Mode:       RedisModeSentinel,
+				Addresses:  []string{"sentinel:26379"},
+				MasterName: "mymaster",
+			},
+			expected: &redis.Client{},
+		},
+		{
+			name:     "cluster with a single seed",
+			cfg:      RedisConfig{Mode: RedisModeCluster, Addresses: []string{"cluster:6379"}},
+			expected: &redis.ClusterClient{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := tt.cfg.NewClient()
+			require.NoError(t, err)
+			defer client.Close()
+
+			assert.IsType(t, tt.expected, client)
+		})
+	}
+
+	_, err := RedisConfig{Mode: RedisModeSentinel}.NewClient()
+	assert.Error(t, err)
+}
+
+func TestLoad_RedisAddressesFromEnv(t *testing.T) {
+	t.Setenv("TQ_REDIS_MODE", "cluster")
+	t.Setenv("TQ_REDIS_ADDRESSES", "node-1:6379,node-2:6379,node-3:6379")
+
+	cfg, err := Load(writeConfigFile(t, "config.yaml", "redis:\n  route_by_latency: true\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, RedisModeCluster, cfg.Redis.Mode)
+	assert.Equal(t, []string{"node-1:6379", "node-2:6379", "node-3:6379"}, cfg.Redis.Addresses)
+	assert.True(t, cfg.Redis.RouteByLatency)
+}
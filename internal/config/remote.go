@@ -0,0 +1,179 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"task-queue/pkg/errors"
+
+	"github.com/spf13/viper"
+	_ "github.com/spf13/viper/remote"
+)
+
+// defaultRemotePollInterval is how often WatchRemote polls when given no
+// PollInterval
+const defaultRemotePollInterval = 30 * time.Second
+
+// LoadRemote loads configuration from a key of a remote store, read through
+// viper's remote provider. Defaults, env overrides, decode hooks, and
+// validation behave exactly as in Load.
+func LoadRemote(opts RemoteOptions, loadOpts ...Option) (*Config, error) {
+	data, err := fetchRemote(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRemote(data, opts, loadOpts...)
+}
+
+// WatchRemote polls the remote key every PollInterval and invokes fn with the
+// reloaded configuration whenever the stored value changes, or with the error
+// when fetching or decoding fails. Reloads are logged and counted like those
+// of Watch. It returns the error of the initial load, and otherwise blocks
+// until ctx is canceled.
+func WatchRemote(ctx context.Context, opts RemoteOptions, fn WatchFunc,
+	loadOpts ...Option) error {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = defaultRemotePollInterval
+	}
+
+	last, err := fetchRemote(opts)
+	if err != nil {
+		return err
+	}
+
+	current, err := decodeRemote(last, opts, loadOpts...)
+	if err != nil {
+		return err
+	}
+
+	r := newReloader(current, fn, loadOpts)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-ticker.C:
+		}
+
+		data, err := fetchRemote(opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			fn(nil, err)
+			continue
+		}
+
+		if bytes.Equal(data, last) {
+			continue
+		}
+
+		last = data
+		r.reload(decodeRemote(data, opts, loadOpts...))
+	}
+}
+
+// decodeRemote decodes a fetched remote document
+func decodeRemote(data []byte, opts RemoteOptions, loadOpts ...Option) (*Config, error) {
+	format := opts.Format
+	if format == "" {
+		format = opts.Path
+		if i := strings.LastIndex(format, "."); i >= 0 && !strings.Contains(format[i:], "/") {
+			format = format[i+1:]
+		} else {
+			format = "yaml"
+		}
+	}
+
+	format, err := normalizeFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	return decode(format, func(v *viper.Viper) error {
+		if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+			return remoteError(err, opts, "failed to parse remote config")
+		}
+
+		return nil
+	}, loadOpts...)
+}
+
+// fetchRemote retrieves the raw (decrypted) value stored at opts.Path
+// through viper's remote provider
+func fetchRemote(opts RemoteOptions) ([]byte, error) {
+	if opts.Endpoint == "" || opts.Path == "" {
+		return nil, errors.New("remote config requires an endpoint and a path").
+			WithCode(errors.CodeConfiguration).
+			WithMetadata("provider", opts.Provider)
+	}
+
+	if !slices.Contains(viper.SupportedRemoteProviders, opts.Provider) {
+		return nil, errors.Newf("unsupported remote provider %q", opts.Provider).
+			WithCode(errors.CodeConfiguration).
+			WithMetadata("endpoint", opts.Endpoint)
+	}
+
+	reader, err := viper.RemoteConfig.Get(remoteProvider{opts: opts})
+	if err != nil {
+		return nil, remoteError(err, opts, "failed to read remote config")
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, remoteError(err, opts, "failed to read remote config")
+	}
+
+	return data, nil
+}
+
+// remoteProvider exposes RemoteOptions as a viper.RemoteProvider
+type remoteProvider struct {
+	opts RemoteOptions
+}
+
+func (p remoteProvider) Provider() string {
+	return p.opts.Provider
+}
+
+func (p remoteProvider) Endpoint() string {
+	return p.opts.Endpoint
+}
+
+func (p remoteProvider) Path() string {
+	if p.opts.Provider == "consul" {
+		return strings.TrimPrefix(p.opts.Path, "/")
+	}
+
+	return p.opts.Path
+}
+
+func (p remoteProvider) SecretKeyring() string {
+	return p.opts.SecretKeyring
+}
+
+// remoteError builds a CodeConfiguration error carrying the store location
+func remoteError(err error, opts RemoteOptions, message string) *errors.Error {
+	var e *errors.Error
+	if err != nil {
+		e = errors.Wrap(err, message)
+	} else {
+		e = errors.New(message)
+	}
+
+	return e.WithCode(errors.CodeConfiguration).
+		WithMetadata("provider", opts.Provider).
+		WithMetadata("endpoint", opts.Endpoint).
+		WithMetadata("path", opts.Path)
+}
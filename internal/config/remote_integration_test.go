@@ -0,0 +1,41 @@
+//go:build integration
+
+package config
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadRemote_Consul runs against a real Consul agent, e.g.
+//
+//	docker run -d -p 8500:8500 hashicorp/consul:1.19
+//	TQ_TEST_CONSUL_ADDR=127.0.0.1:8500 go test -tags=integration ./internal/config
+func TestLoadRemote_Consul(t *testing.T) {
+	addr := os.Getenv("TQ_TEST_CONSUL_ADDR")
+	if addr == "" {
+		t.Skip("TQ_TEST_CONSUL_ADDR not set")
+	}
+
+	req, err := http.NewRequest(http.MethodPut,
+		"http://"+addr+"/v1/kv/task-queue/it/config.yaml",
+		strings.NewReader(remoteYAML))
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	cfg, err := LoadRemote(RemoteOptions{
+		Provider: "consul",
+		Endpoint: addr,
+		Path:     "task-queue/it/config.yaml",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 9400, cfg.Server.Port)
+}
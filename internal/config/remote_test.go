@@ -0,0 +1,338 @@
+package config
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"task-queue/pkg/errors"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKVStore serves a single mutable value over the Consul and etcd v2 KV
+// APIs, recording the Consul ACL token of the last request
+type fakeKVStore struct {
+	mu    sync.Mutex
+	key   string
+	value []byte
+	token string
+}
+
+func (s *fakeKVStore) set(value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.value = []byte(value)
+}
+
+func (s *fakeKVStore) lastToken() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.token
+}
+
+func (s *fakeKVStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case "/v1/kv/" + s.key:
+		s.token = r.Header.Get("X-Consul-Token")
+		w.Header().Set("X-Consul-Index", "1")
+		_ = json.NewEncoder(w).Encode([]map[string]any{{
+			"Key":   s.key,
+			"Value": s.value,
+		}})
+
+	case "/v2/keys/" + s.key:
+		w.Header().Set("X-Etcd-Index", "1")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"action": "get",
+			"node": map[string]any{
+				"key":   "/" + s.key,
+				"value": string(s.value),
+			},
+		})
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		if strings.HasPrefix(r.URL.Path, "/v2/keys/") {
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"errorCode": 100,
+				"message":   "Key not found",
+				"cause":     strings.TrimPrefix(r.URL.Path, "/v2/keys"),
+				"index":     1,
+			})
+		}
+	}
+}
+
+func newFakeKVStore(t *testing.T, key, value string) (*fakeKVStore, *httptest.Server) {
+	t.Helper()
+
+	store := &fakeKVStore{key: key, value: []byte(value)}
+	server := httptest.NewServer(store)
+	t.Cleanup(server.Close)
+
+	return store, server
+}
+
+const remoteYAML = `
+server:
+  port: 9400
+queue:
+  retention_period: 2d
+`
+
+func TestLoadRemote_Providers(t *testing.T) {
+	_, server := newFakeKVStore(t, "task-queue/config.yaml", remoteYAML)
+
+	for _, provider := range []string{"consul", "etcd"} {
+		t.Run(provider, func(t *testing.T) {
+			cfg, err := LoadRemote(RemoteOptions{
+				Provider: provider,
+				Endpoint: server.URL,
+				Path:     "task-queue/config.yaml",
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, 9400, cfg.Server.Port)
+			assert.Equal(t, 48*time.Hour, cfg.Queue.RetentionPeriod)
+			assert.Equal(t, "info", cfg.Log.Level, "defaults still apply")
+		})
+	}
+}
+
+func TestLoadRemote_EnvOverridesRemote(t *testing.T) {
+	_, server := newFakeKVStore(t, "config", remoteYAML)
+	t.Setenv("TQ_SERVER_PORT", "9500")
+
+	cfg, err := LoadRemote(RemoteOptions{
+		Provider: "consul",
+		Endpoint: server.URL,
+		Path:     "config",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 9500, cfg.Server.Port)
+}
+
+func TestLoadRemote_ConsulTokenAndEscapedKey(t *testing.T) {
+	store, server := newFakeKVStore(t, "task queue/config?.yaml", remoteYAML)
+	t.Setenv("CONSUL_HTTP_TOKEN", "acl-token")
+
+	cfg, err := LoadRemote(RemoteOptions{
+		Provider: "consul",
+		Endpoint: server.URL,
+		Path:     "/task queue/config?.yaml",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 9400, cfg.Server.Port)
+	assert.Equal(t, "acl-token", store.lastToken())
+}
+
+func TestLoadRemote_Errors(t *testing.T) {
+	_, server := newFakeKVStore(t, "config", remoteYAML)
+
+	tests := []struct {
+		name string
+		opts RemoteOptions
+	}{
+		{
+			name: "connection refused",
+			opts: RemoteOptions{Provider: "consul", Endpoint: "127.0.0.1:1", Path: "config"},
+		},
+		{
+			name: "missing key",
+			opts: RemoteOptions{Provider: "etcd", Endpoint: server.URL, Path: "other"},
+		},
+		{
+			name: "unsupported provider",
+			opts: RemoteOptions{Provider: "zookeeper", Endpoint: server.URL, Path: "config"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadRemote(tt.opts)
+			require.Error(t, err)
+
+			var appErr *errors.Error
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, errors.CodeConfiguration, appErr.Code)
+			assert.Equal(t, tt.opts.Endpoint, appErr.Metadata["endpoint"])
+		})
+	}
+}
+
+func TestLoadRemote_SecretKeyring(t *testing.T) {
+	entity, err := openpgp.NewEntity("task-queue", "test", "tq@example.com", nil)
+	require.NoError(t, err)
+
+	keyringPath := filepath.Join(t.TempDir(), "secring.asc")
+	var keyring bytes.Buffer
+	armored, err := armor.Encode(&keyring, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.SerializePrivate(armored, nil))
+	require.NoError(t, armored.Close())
+	require.NoError(t, os.WriteFile(keyringPath, keyring.Bytes(), 0o600))
+
+	var encrypted bytes.Buffer
+	plain, err := openpgp.Encrypt(&encrypted, []*openpgp.Entity{entity}, nil, nil, nil)
+	require.NoError(t, err)
+
+	gz := gzip.NewWriter(plain)
+	_, err = gz.Write([]byte(remoteYAML))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	require.NoError(t, plain.Close())
+
+	value := base64.StdEncoding.EncodeToString(encrypted.Bytes())
+	_, server := newFakeKVStore(t, "secure", value)
+
+	cfg, err := LoadRemote(RemoteOptions{
+		Provider:      "consul",
+		Endpoint:      server.URL,
+		Path:          "secure",
+		SecretKeyring: keyringPath,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 9400, cfg.Server.Port)
+}
+
+func TestWatchRemote_InvokesCallbackOnChange(t *testing.T) {
+	store, server := newFakeKVStore(t, "config", remoteYAML)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan *Config, 1)
+	go func() {
+		_ = WatchRemote(ctx, RemoteOptions{
+			Provider:     "consul",
+			Endpoint:     server.URL,
+			Path:         "config",
+			PollInterval: 10 * time.Millisecond,
+		}, func(cfg *Config, err error) {
+			if err == nil {
+				changes <- cfg
+			}
+		})
+	}()
+
+	time.Sleep(30 * time.Millisecond)
+	store.set("server:\n  port: 9600\n")
+
+	select {
+	case cfg := <-changes:
+		assert.Equal(t, 9600, cfg.Server.Port)
+
+	case <-time.After(2 * time.Second):
+		t.Fatal("watch callback was not invoked")
+	}
+}
+
+func TestWatchRemote_ReturnsInitialDecodeError(t *testing.T) {
+	_, server := newFakeKVStore(t, "config", "server: [\n")
+
+	err := WatchRemote(context.Background(), RemoteOptions{
+		Provider: "consul",
+		Endpoint: server.URL,
+		Path:     "config",
+	}, func(*Config, error) {
+		t.Error("callback invoked for the initial load")
+	})
+
+	var appErr *errors.Error
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, errors.CodeConfiguration, appErr.Code)
+}
+
+func TestWatch_ReloadsFileOnWrite(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "server:\n  port: 9700\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan *Config, 4)
+	go func() {
+		_ = Watch(ctx, path, func(cfg *Config, err error) {
+			if err == nil {
+				changes <- cfg
+			}
+		})
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, os.WriteFile(path, []byte("server:\n  port: 9800\n"), 0o600))
+
+	select {
+	case cfg := <-changes:
+		assert.Equal(t, 9800, cfg.Server.Port)
+
+	case <-time.After(2 * time.Second):
+		t.Fatal("watch callback was not invoked")
+	}
+}
+
+func TestWatch_SkipsEmptyFile(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "server:\n  port: 9700\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan *Config, 4)
+	go func() {
+		_ = Watch(ctx, path, func(cfg *Config, err error) {
+			changes <- cfg
+		})
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+	select {
+	case cfg := <-changes:
+		t.Fatalf("an empty file was reloaded: %+v", cfg)
+
+	case <-time.After(4 * watchDebounce):
+	}
+
+	require.NoError(t, os.WriteFile(path, []byte("server:\n  port: 9900\n"), 0o600))
+
+	select {
+	case cfg := <-changes:
+		require.NotNil(t, cfg)
+		assert.Equal(t, 9900, cfg.Server.Port)
+
+	case <-time.After(2 * time.Second):
+		t.Fatal("watch callback was not invoked")
+	}
+}
+
+func TestWatch_ReturnsInitialLoadError(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "server: [\n")
+
+	err := Watch(context.Background(), path, func(*Config, error) {
+		t.Error("callback invoked without a change")
+	})
+
+	assert.ErrorContains(t, err, "failed to read config")
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+
+	"task-queue/pkg/errors"
+)
+
+// TLSClientConfig holds TLS settings for outbound client connections
+type TLSClientConfig struct {
+	Enabled            bool   `mapstructure:"enabled"`
+	CAFile             string `mapstructure:"ca_file"`
+	CertFile           string `mapstructure:"cert_file"`
+	KeyFile            string `mapstructure:"key_file"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
+	ServerName         string `mapstructure:"server_name"`
+}
+
+// BuildTLSConfig builds a tls.Config from the client settings. It returns
+// nil when TLS is disabled.
+func (c TLSClientConfig) BuildTLSConfig() (*tls.Config, error) {
+	if !c.Enabled {
+		return nil, nil
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify, //nolint:gosec // opt-in
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read CA file %s", c.CAFile).
+				WithCode(errors.CodeConfiguration)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.Newf("no certificates found in CA file %s", c.CAFile).
+				WithCode(errors.CodeConfiguration)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load client certificate").
+				WithCode(errors.CodeConfiguration).
+				WithMetadata("cert_file", c.CertFile).
+				WithMetadata("key_file", c.KeyFile)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// Validate checks that the referenced files are set consistently and exist
+func (c TLSClientConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return errors.New("tls cert_file and key_file must be set together").
+			WithCode(errors.CodeConfiguration)
+	}
+
+	files := map[string]string{
+		"ca_file":   c.CAFile,
+		"cert_file": c.CertFile,
+		"key_file":  c.KeyFile,
+	}
+
+	for key, path := range files {
+		if path == "" {
+			continue
+		}
+
+		if _, err := os.Stat(path); err != nil {
+			return errors.Wrapf(err, "tls %s %s is not readable", key, path).
+				WithCode(errors.CodeConfiguration)
+		}
+	}
+
+	return nil
+}
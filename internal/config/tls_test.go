@@ -0,0 +1,181 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"task-queue/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSelfSignedCert generates a self-signed certificate and key in dir
+func writeSelfSignedCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "task-queue-test"},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template,
+		&key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+
+	require.NoError(t, os.WriteFile(certFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile,
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certFile, keyFile
+}
+
+func TestBuildTLSConfig_Disabled(t *testing.T) {
+	tlsConfig, err := TLSClientConfig{}.BuildTLSConfig()
+
+	assert.NoError(t, err)
+	assert.Nil(t, tlsConfig)
+}
+
+func TestBuildTLSConfig_LoadsFiles(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t, t.TempDir())
+
+	cfg := TLSClientConfig{
+		Enabled:    true,
+		CAFile:     certFile,
+		CertFile:   certFile,
+		KeyFile:    keyFile,
+		ServerName: "localhost",
+	}
+
+	tlsConfig, err := cfg.BuildTLSConfig()
+	require.NoError(t, err)
+
+	assert.NotNil(t, tlsConfig.RootCAs)
+	assert.Len(t, tlsConfig.Certificates, 1)
+	assert.Equal(t, "localhost", tlsConfig.ServerName)
+	assert.False(t, tlsConfig.InsecureSkipVerify)
+}
+
+func TestBuildTLSConfig_Errors(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeSelfSignedCert(t, dir)
+
+	garbage := filepath.Join(dir, "garbage.pem")
+	require.NoError(t, os.WriteFile(garbage, []byte("not a cert"), 0o600))
+
+	tests := []struct {
+		name string
+		cfg  TLSClientConfig
+	}{
+		{
+			name: "missing ca file",
+			cfg:  TLSClientConfig{Enabled: true, CAFile: filepath.Join(dir, "nope.pem")},
+		},
+		{
+			name: "cert without key",
+			cfg:  TLSClientConfig{Enabled: true, CertFile: certFile},
+		},
+		{
+			name: "ca file without certificates",
+			cfg:  TLSClientConfig{Enabled: true, CAFile: garbage},
+		},
+		{
+			name: "mismatched key pair",
+			cfg:  TLSClientConfig{Enabled: true, CertFile: garbage, KeyFile: keyFile},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.cfg.BuildTLSConfig()
+
+			require.Error(t, err)
+			assert.Equal(t, errors.CodeConfiguration, errors.GetCode(err))
+		})
+	}
+}
+
+func TestValidate_RejectsMissingTLSFiles(t *testing.T) {
+	cfg := &Config{}
+	cfg.Redis.TLS = TLSClientConfig{Enabled: true, CAFile: "/does/not/exist.pem"}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "redis tls")
+
+	cfg.Redis.TLS.Enabled = false
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestRedisOptions_TLS(t *testing.T) {
+	certFile, _ := writeSelfSignedCert(t, t.TempDir())
+
+	cfg := RedisConfig{
+		Host: "redis.internal",
+		Port: 6380,
+		TLS:  TLSClientConfig{Enabled: true, CAFile: certFile},
+	}
+
+	opts, err := cfg.Options()
+	require.NoError(t, err)
+
+	assert.Equal(t, "redis.internal:6380", opts.Addr)
+	assert.NotNil(t, opts.TLSConfig)
+}
+
+func TestDatabaseDSN_TLS(t *testing.T) {
+	cfg := DatabaseConfig{
+		Host:     "db.internal",
+		Port:     5432,
+		User:     "tq",
+		Password: "p@ss word",
+		Database: "taskqueue",
+		SSLMode:  "disable",
+		TLS: TLSClientConfig{
+			Enabled:  true,
+			CAFile:   "/etc/ssl/ca.pem",
+			CertFile: "/etc/ssl/client.pem",
+			KeyFile:  "/etc/ssl/client.key",
+		},
+	}
+
+	dsn := cfg.DSN()
+
+	assert.Contains(t, dsn, "sslmode=verify-full")
+	assert.Contains(t, dsn, "sslrootcert=/etc/ssl/ca.pem")
+	assert.Contains(t, dsn, "sslcert=/etc/ssl/client.pem")
+	assert.Contains(t, dsn, "sslkey=/etc/ssl/client.key")
+	assert.Contains(t, dsn, "password='p@ss word'")
+
+	cfg.TLS.Enabled = false
+	assert.False(t, strings.Contains(cfg.DSN(), "sslrootcert"))
+	assert.Contains(t, cfg.DSN(), "sslmode=disable")
+}
@@ -1,25 +1,129 @@
 package config
 
-import "time"
+import (
+	"time"
+
+	"task-queue/pkg/logger"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
 
 // Config holds all configuration for the application
 type Config struct {
 	Server   ServerConfig   `mapstructure:"server"`
+	GRPC     GRPCConfig     `mapstructure:"grpc"`
+	Auth     AuthConfig     `mapstructure:"auth"`
 	Database DatabaseConfig `mapstructure:"database"`
 	Redis    RedisConfig    `mapstructure:"redis"`
+	Broker   BrokerConfig   `mapstructure:"broker"`
 	Queue    QueueConfig    `mapstructure:"queue"`
 	Worker   WorkerConfig   `mapstructure:"worker"`
+	Events   EventsConfig   `mapstructure:"events"`
 	Metrics  MetricsConfig  `mapstructure:"metrics"`
 	Tracing  TracingConfig  `mapstructure:"tracing"`
 	Log      LogConfig      `mapstructure:"log"`
+	Client   ClientConfig   `mapstructure:"client"`
+}
+
+// ClientConfig holds how the taskqueue CLI and other API clients reach the
+// HTTP API: its base URL, the API key to send, and the timeout of each
+// request
+type ClientConfig struct {
+	URL     string        `mapstructure:"url"`
+	APIKey  string        `mapstructure:"api_key"`
+	Timeout time.Duration `mapstructure:"timeout"`
 }
 
 // ServerConfig holds server-specific configuration
 type ServerConfig struct {
+	Host            string          `mapstructure:"host"`
+	Port            int             `mapstructure:"port"`
+	ReadTimeout     time.Duration   `mapstructure:"read_timeout"`
+	WriteTimeout    time.Duration   `mapstructure:"write_timeout"`
+	ShutdownTimeout time.Duration   `mapstructure:"shutdown_timeout"`
+	DrainDelay      time.Duration   `mapstructure:"drain_delay"`
+	Docs            bool            `mapstructure:"docs"`
+	TLSEnabled      bool            `mapstructure:"tls_enabled"`
+	TLSCertFile     string          `mapstructure:"tls_cert_file"`
+	TLSKeyFile      string          `mapstructure:"tls_key_file"`
+	RateLimit       RateLimitConfig `mapstructure:"rate_limit"`
+	Batch           BatchConfig     `mapstructure:"batch"`
+	Health          HealthConfig    `mapstructure:"health"`
+}
+
+// HealthConfig tunes the /readyz dependency checks: how long each check may
+// take and how long a report is reused before the checks run again
+type HealthConfig struct {
+	CheckTimeout time.Duration `mapstructure:"check_timeout"`
+	CacheTTL     time.Duration `mapstructure:"cache_ttl"`
+}
+
+// BatchConfig caps bulk job submissions to POST /v1/jobs/batch: the number
+// of jobs of one request and the size of its body. Zero selects the API's
+// default limit.
+type BatchConfig struct {
+	MaxItems     int   `mapstructure:"max_items"`
+	MaxBodyBytes int64 `mapstructure:"max_body_bytes"`
+}
+
+// RateLimitConfig holds the HTTP API rate limits. Each client, identified
+// by its API key or else its IP address, has a token bucket refilled at
+// RequestsPerSecond and holding up to Burst. Routes override the limit of
+// single endpoints, such as POST /v1/jobs. X-Forwarded-For is only trusted
+// from the addresses in TrustedProxies, given as IPs or CIDRs. The redis
+// backend shares buckets between replicas and falls back to in-process
+// buckets while Redis is unreachable.
+type RateLimitConfig struct {
+	Enabled           bool                   `mapstructure:"enabled"`
+	Backend           string                 `mapstructure:"backend"`
+	KeyPrefix         string                 `mapstructure:"key_prefix"`
+	RequestsPerSecond float64                `mapstructure:"requests_per_second"`
+	Burst             int                    `mapstructure:"burst"`
+	TrustedProxies    []string               `mapstructure:"trusted_proxies"`
+	Routes            []RouteRateLimitConfig `mapstructure:"routes"`
+}
+
+// RouteRateLimitConfig overrides the rate limit of the endpoint matching
+// Method and Path, given as registered, e.g. /v1/jobs/{id}
+type RouteRateLimitConfig struct {
+	Method            string  `mapstructure:"method"`
+	Path              string  `mapstructure:"path"`
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+	Burst             int     `mapstructure:"burst"`
+}
+
+// Rate limiter backends
+const (
+	RateLimitMemory = "memory"
+	RateLimitRedis  = "redis"
+)
+
+// AuthConfig holds the HTTP API authentication. Keys are configured by the
+// hex SHA-256 of the key, never the key itself. With Database set, keys are
+// also looked up in the api_keys table, each lookup cached for CacheTTL.
+type AuthConfig struct {
+	Enabled  bool           `mapstructure:"enabled"`
+	Keys     []APIKeyConfig `mapstructure:"keys"`
+	Database bool           `mapstructure:"database"`
+	CacheTTL time.Duration  `mapstructure:"cache_ttl"`
+}
+
+// APIKeyConfig is a configured API key: the principal Name, the hex
+// SHA-256 Hash of the key, and the Scopes it grants, such as jobs:write,
+// jobs:read, or admin
+type APIKeyConfig struct {
+	Name   string   `mapstructure:"name"`
+	Hash   string   `mapstructure:"hash"`
+	Scopes []string `mapstructure:"scopes"`
+}
+
+// GRPCConfig holds the gRPC job service configuration. The service is
+// disabled by default.
+type GRPCConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
 	Host            string        `mapstructure:"host"`
 	Port            int           `mapstructure:"port"`
-	ReadTimeout     time.Duration `mapstructure:"read_timeout"`
-	WriteTimeout    time.Duration `mapstructure:"write_timeout"`
 	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
 	TLSEnabled      bool          `mapstructure:"tls_enabled"`
 	TLSCertFile     string        `mapstructure:"tls_cert_file"`
@@ -28,28 +132,92 @@ type ServerConfig struct {
 
 // DatabaseConfig holds database configuration
 type DatabaseConfig struct {
-	Host            string        `mapstructure:"host"`
-	Port            int           `mapstructure:"port"`
-	User            string        `mapstructure:"user"`
-	Password        string        `mapstructure:"password"`
-	Database        string        `mapstructure:"database"`
-	SSLMode         string        `mapstructure:"ssl_mode"`
-	MaxConnections  int           `mapstructure:"max_connections"`
-	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
-	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+	Host            string          `mapstructure:"host"`
+	Port            int             `mapstructure:"port"`
+	User            string          `mapstructure:"user"`
+	Password        string          `mapstructure:"password"`
+	Database        string          `mapstructure:"database"`
+	SSLMode         string          `mapstructure:"ssl_mode"`
+	MaxConnections  int             `mapstructure:"max_connections"`
+	MaxIdleConns    int             `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime time.Duration   `mapstructure:"conn_max_lifetime"`
+	TLS             TLSClientConfig `mapstructure:"tls"`
 }
 
-// RedisConfig holds Redis configuration
+// Redis deployment modes
+const (
+	RedisModeStandalone = "standalone"
+	RedisModeSentinel   = "sentinel"
+	RedisModeCluster    = "cluster"
+)
+
+// RedisConfig holds Redis configuration. Addresses lists the server, sentinel,
+// or cluster seed addresses depending on Mode.
 type RedisConfig struct {
-	Host         string        `mapstructure:"host"`
-	Port         int           `mapstructure:"port"`
-	Password     string        `mapstructure:"password"`
-	DB           int           `mapstructure:"db"`
-	PoolSize     int           `mapstructure:"pool_size"`
-	MinIdleConns int           `mapstructure:"min_idle_conns"`
-	DialTimeout  time.Duration `mapstructure:"dial_timeout"`
-	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
-	WriteTimeout time.Duration `mapstructure:"write_timeout"`
+	Mode           string   `mapstructure:"mode"`
+	Addresses      []string `mapstructure:"addresses"`
+	MasterName     string   `mapstructure:"master_name"`
+	RouteByLatency bool     `mapstructure:"route_by_latency"`
+
+	// Host is the standalone server host, used when Addresses is empty.
+	//
+	// Deprecated: use Addresses.
+	Host string `mapstructure:"host"`
+
+	// Port is the standalone server port, used when Addresses is empty.
+	//
+	// Deprecated: use Addresses.
+	Port int `mapstructure:"port"`
+
+	Password     string          `mapstructure:"password"`
+	DB           int             `mapstructure:"db"`
+	PoolSize     int             `mapstructure:"pool_size"`
+	MinIdleConns int             `mapstructure:"min_idle_conns"`
+	DialTimeout  time.Duration   `mapstructure:"dial_timeout"`
+	ReadTimeout  time.Duration   `mapstructure:"read_timeout"`
+	WriteTimeout time.Duration   `mapstructure:"write_timeout"`
+	TLS          TLSClientConfig `mapstructure:"tls"`
+}
+
+// Broker types select the backend that stores queued jobs
+const (
+	BrokerRedis    = "redis"
+	BrokerRabbitMQ = "rabbitmq"
+	BrokerSQS      = "sqs"
+	BrokerNATS     = "nats"
+)
+
+// BrokerConfig selects the queue backend. Type picks the backend; only the
+// matching sub-section may be configured. The redis backend, also used when
+// Type is empty, reads the top-level Redis section.
+type BrokerConfig struct {
+	Type     string         `mapstructure:"type"`
+	RabbitMQ RabbitMQConfig `mapstructure:"rabbitmq"`
+	SQS      SQSConfig      `mapstructure:"sqs"`
+	NATS     NATSConfig     `mapstructure:"nats"`
+}
+
+// RabbitMQConfig holds RabbitMQ broker configuration
+type RabbitMQConfig struct {
+	URL      string          `mapstructure:"url"`
+	Exchange string          `mapstructure:"exchange"`
+	Prefetch int             `mapstructure:"prefetch"`
+	TLS      TLSClientConfig `mapstructure:"tls"`
+}
+
+// SQSConfig holds Amazon SQS broker configuration. QueueURLs maps queue
+// names to their SQS URLs.
+type SQSConfig struct {
+	Region    string            `mapstructure:"region"`
+	QueueURLs map[string]string `mapstructure:"queue_urls"`
+	Profile   string            `mapstructure:"profile"`
+}
+
+// NATSConfig holds NATS JetStream broker configuration
+type NATSConfig struct {
+	Servers         []string `mapstructure:"servers"`
+	Stream          string   `mapstructure:"stream"`
+	CredentialsFile string   `mapstructure:"credentials_file"`
 }
 
 // QueueConfig holds queue-specific configuration
@@ -59,6 +227,14 @@ type QueueConfig struct {
 	VisibilityTimeout    time.Duration `mapstructure:"visibility_timeout"`
 	RetentionPeriod      time.Duration `mapstructure:"retention_period"`
 	DeadLetterMaxRetries int           `mapstructure:"dead_letter_max_retries"`
+	Backoff              BackoffConfig `mapstructure:"backoff"`
+}
+
+// BackoffConfig names a retry backoff strategy registered with the retry
+// package, such as "exponential" or "fibonacci", and its parameters
+type BackoffConfig struct {
+	Strategy string         `mapstructure:"strategy"`
+	Params   map[string]any `mapstructure:"params"`
 }
 
 // WorkerConfig holds worker-specific configuration
@@ -67,16 +243,137 @@ type WorkerConfig struct {
 	BatchSize         int           `mapstructure:"batch_size"`
 	ProcessTimeout    time.Duration `mapstructure:"process_timeout"`
 	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval"`
+
+	// UnknownTypeAction is what a worker does with a job whose type has no
+	// handler: nack it for a later retry, or dead-letter it right away
+	UnknownTypeAction string `mapstructure:"unknown_type_action"`
+
+	// Types caps the concurrency and rate of individual job types within
+	// Concurrency. Types not listed, or listed without a concurrency, share
+	// one bucket of DefaultTypeConcurrency slots, which defaults to
+	// Concurrency when zero.
+	Types                  map[string]WorkerTypeConfig `mapstructure:"types"`
+	DefaultTypeConcurrency int                         `mapstructure:"default_type_concurrency"`
+
+	// ThrottlePolicy is what a worker does with a job whose type is over
+	// its rate limit: wait up to ThrottleMaxWait for the next token and
+	// requeue the job when that is longer, or requeue it right away
+	ThrottlePolicy  string        `mapstructure:"throttle_policy"`
+	ThrottleMaxWait time.Duration `mapstructure:"throttle_max_wait"`
+
+	// Autoscale replaces the fixed Concurrency with one that follows the
+	// queue depth when enabled
+	Autoscale AutoscaleConfig `mapstructure:"autoscale"`
+
+	// ReservedLane keeps some workers free for urgent jobs
+	ReservedLane ReservedLaneConfig `mapstructure:"reserved_lane"`
+
+	// Deadlines bounds how long jobs may wait before a worker starts them
+	Deadlines DeadlineConfig `mapstructure:"deadlines"`
+
+	// Queues lists the named queues the pool consumes, each dequeued from
+	// in proportion to its weight. Empty consumes the default queue alone.
+	Queues []WorkerQueueConfig `mapstructure:"queues"`
+}
+
+// WorkerQueueConfig names a queue consumed by the worker pool. Weight is its
+// share of dequeues relative to the other queues; zero counts as one.
+type WorkerQueueConfig struct {
+	Name   string `mapstructure:"name"`
+	Weight int    `mapstructure:"weight"`
+}
+
+// DeadlineConfig holds the wait objectives of jobs: the longest a job may
+// wait between becoming ready and starting, by priority name and,
+// overriding that, by job type. A job without a deadline is never in
+// breach. With Escalate, every EscalateInterval the pool promotes ready
+// jobs already past their deadline one priority up, so a job stuck behind
+// a backlog keeps climbing until it runs or reaches critical.
+type DeadlineConfig struct {
+	Priorities       map[string]time.Duration `mapstructure:"priorities"`
+	Types            map[string]time.Duration `mapstructure:"types"`
+	Escalate         bool                     `mapstructure:"escalate"`
+	EscalateInterval time.Duration            `mapstructure:"escalate_interval"`
+}
+
+// ReservedLaneConfig reserves Workers of the pool's workers for jobs of
+// MinPriority or higher, so a backlog of routine jobs cannot delay them. A
+// reserved worker that finds no such job for IdleGrace takes any job until
+// it runs out of work again; a zero IdleGrace keeps it reserved. With a fixed
+// Concurrency the lane is carved out of it, and with autoscaling it runs on
+// top of the scaled workers. Zero Workers disables the lane.
+type ReservedLaneConfig struct {
+	Workers     int           `mapstructure:"workers"`
+	MinPriority string        `mapstructure:"min_priority"`
+	IdleGrace   time.Duration `mapstructure:"idle_grace"`
+}
+
+// AutoscaleConfig holds the worker autoscaler settings. Every Interval the
+// pool sizes itself to keep TargetBacklog ready or running jobs per worker
+// and the oldest ready job younger than TargetLatency, within
+// MinConcurrency and MaxConcurrency. A zero target is ignored. Scaling up
+// waits ScaleUpCooldown after the last scale-up, and scaling down waits
+// ScaleDownCooldown after any scaling, so bursts do not make it flap.
+type AutoscaleConfig struct {
+	Enabled           bool          `mapstructure:"enabled"`
+	MinConcurrency    int           `mapstructure:"min_concurrency"`
+	MaxConcurrency    int           `mapstructure:"max_concurrency"`
+	Interval          time.Duration `mapstructure:"interval"`
+	TargetBacklog     int           `mapstructure:"target_backlog"`
+	TargetLatency     time.Duration `mapstructure:"target_latency"`
+	ScaleUpCooldown   time.Duration `mapstructure:"scale_up_cooldown"`
+	ScaleDownCooldown time.Duration `mapstructure:"scale_down_cooldown"`
+}
+
+// WorkerTypeConfig holds the worker settings of one job type. RateLimit
+// caps how many of its jobs start per second, allowing bursts of up to
+// RateBurst, which defaults to one. Zero values leave the type unlimited.
+type WorkerTypeConfig struct {
+	Concurrency int     `mapstructure:"concurrency"`
+	RateLimit   float64 `mapstructure:"rate_limit"`
+	RateBurst   int     `mapstructure:"rate_burst"`
+}
+
+// Unknown type actions select how workers treat jobs without a handler
+const (
+	UnknownTypeNack       = "nack"
+	UnknownTypeDeadLetter = "dead_letter"
+)
+
+// Throttle policies select how workers treat jobs over their type's rate
+// limit
+const (
+	ThrottleWait    = "wait"
+	ThrottleRequeue = "requeue"
+)
+
+// EventsConfig holds the job event bus configuration. The memory backend
+// serves a single instance; the redis backend shares events between
+// instances over pub/sub.
+type EventsConfig struct {
+	Backend           string        `mapstructure:"backend"`
+	Channel           string        `mapstructure:"channel"`
+	BufferSize        int           `mapstructure:"buffer_size"`
+	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval"`
 }
 
-// MetricsConfig holds metrics configuration
+// Event bus backends
+const (
+	EventsMemory = "memory"
+	EventsRedis  = "redis"
+)
+
+// MetricsConfig holds metrics configuration. Metrics are enabled by default
+// and served on a separate port.
 type MetricsConfig struct {
 	Enabled bool   `mapstructure:"enabled"`
 	Path    string `mapstructure:"path"`
 	Port    int    `mapstructure:"port"`
 }
 
-// TracingConfig holds tracing configuration
+// TracingConfig holds tracing configuration. Tracing is disabled by default
+// and samples 10% of traces once enabled. Spans are exported over OTLP/HTTP
+// to CollectorURL.
 type TracingConfig struct {
 	Enabled      bool    `mapstructure:"enabled"`
 	ServiceName  string  `mapstructure:"service_name"`
@@ -89,4 +386,64 @@ type LogConfig struct {
 	Level      string `mapstructure:"level"`
 	Format     string `mapstructure:"format"`
 	OutputPath string `mapstructure:"output_path"`
+
+	// Audit configures the job lifecycle audit stream, kept apart from the
+	// operational log
+	Audit AuditLogConfig `mapstructure:"audit"`
+}
+
+// AuditLogConfig holds audit log configuration
+type AuditLogConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	OutputPath string `mapstructure:"output_path"`
+}
+
+// Option customizes how configuration is loaded
+type Option func(*loadOptions)
+
+// loadOptions holds the settings applied by Option functions
+type loadOptions struct {
+	viper     *viper.Viper
+	flags     *pflag.FlagSet
+	strictEnv bool
+	logger    logger.Logger
+}
+
+// WatchFunc receives a freshly loaded configuration, or the error that
+// prevented it from loading
+type WatchFunc func(cfg *Config, err error)
+
+// Change describes one configuration value that differs between two configs.
+// Secret values are redacted.
+type Change struct {
+	Key string `json:"key"`
+	Old any    `json:"old"`
+	New any    `json:"new"`
+}
+
+// RemoteOptions describes a remote key/value store holding the configuration.
+// The Consul client takes its ACL token and TLS settings from the standard
+// CONSUL_HTTP_TOKEN, CONSUL_HTTP_AUTH, and CONSUL_CACERT environment
+// variables.
+type RemoteOptions struct {
+	// Provider is the store type: "consul", "etcd" (v2 API), "etcd3",
+	// "firestore", or "nats"
+	Provider string
+
+	// Endpoint is the store address, e.g. "127.0.0.1:8500" or
+	// "http://etcd:2379"; separate several etcd endpoints with ";"
+	Endpoint string
+
+	// Path is the key holding the configuration document
+	Path string
+
+	// Format is the document format; detected from Path when empty
+	Format string
+
+	// SecretKeyring is an optional armored OpenPGP keyring used to decrypt
+	// values written by crypt
+	SecretKeyring string
+
+	// PollInterval controls how often WatchRemote checks for changes
+	PollInterval time.Duration
 }
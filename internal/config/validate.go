@@ -0,0 +1,385 @@
+package config
+
+import (
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+)
+
+// Validate checks the configuration for inconsistent or missing values and
+// reports every problem found
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Server.TLSEnabled {
+		if c.Server.TLSCertFile == "" || c.Server.TLSKeyFile == "" {
+			errs = append(errs, errors.New("server tls requires tls_cert_file and tls_key_file").
+				WithCode(errors.CodeConfiguration))
+		}
+	}
+
+	if err := c.Server.RateLimit.Validate(); err != nil {
+		errs = append(errs, errors.Wrap(err, "invalid server rate_limit configuration"))
+	}
+
+	if err := c.Server.Batch.Validate(); err != nil {
+		errs = append(errs, errors.Wrap(err, "invalid server batch configuration"))
+	}
+
+	if c.Server.ShutdownTimeout < 0 || c.Server.DrainDelay < 0 {
+		errs = append(errs, errors.New("server shutdown_timeout and drain_delay must not be negative").
+			WithCode(errors.CodeConfiguration))
+	}
+
+	if c.Server.Health.CheckTimeout < 0 || c.Server.Health.CacheTTL < 0 {
+		errs = append(errs, errors.New("server health check_timeout and cache_ttl must not be negative").
+			WithCode(errors.CodeConfiguration))
+	}
+
+	if c.GRPC.TLSEnabled {
+		if c.GRPC.TLSCertFile == "" || c.GRPC.TLSKeyFile == "" {
+			errs = append(errs, errors.New("grpc tls requires tls_cert_file and tls_key_file").
+				WithCode(errors.CodeConfiguration))
+		}
+	}
+
+	if err := c.Auth.Validate(); err != nil {
+		errs = append(errs, errors.Wrap(err, "invalid auth configuration"))
+	}
+
+	if err := c.Database.TLS.Validate(); err != nil {
+		errs = append(errs, errors.Wrap(err, "invalid database tls configuration"))
+	}
+
+	if err := c.Redis.Validate(); err != nil {
+		errs = append(errs, errors.Wrap(err, "invalid redis configuration"))
+	}
+
+	if err := c.Broker.Validate(); err != nil {
+		errs = append(errs, errors.Wrap(err, "invalid broker configuration"))
+	}
+
+	switch c.Events.Backend {
+	case "", EventsMemory, EventsRedis:
+
+	default:
+		errs = append(errs, errors.Newf("unsupported events backend %q", c.Events.Backend).
+			WithCode(errors.CodeConfiguration).
+			WithMetadata("supported", []string{EventsMemory, EventsRedis}))
+	}
+
+	switch c.Worker.UnknownTypeAction {
+	case "", UnknownTypeNack, UnknownTypeDeadLetter:
+
+	default:
+		errs = append(errs, errors.Newf("unsupported worker unknown_type_action %q",
+			c.Worker.UnknownTypeAction).
+			WithCode(errors.CodeConfiguration).
+			WithMetadata("supported", []string{UnknownTypeNack, UnknownTypeDeadLetter}))
+	}
+
+	if c.Worker.DefaultTypeConcurrency < 0 {
+		errs = append(errs, errors.New("worker default_type_concurrency must not be negative").
+			WithCode(errors.CodeConfiguration))
+	}
+
+	jobTypes := make([]string, 0, len(c.Worker.Types))
+	for jobType := range c.Worker.Types {
+		jobTypes = append(jobTypes, jobType)
+	}
+
+	sort.Strings(jobTypes)
+	for _, jobType := range jobTypes {
+		typeCfg := c.Worker.Types[jobType]
+		if typeCfg.Concurrency < 0 || typeCfg.RateLimit < 0 || typeCfg.RateBurst < 0 {
+			errs = append(errs, errors.Newf("worker type %q concurrency, rate_limit, and rate_burst must not be negative",
+				jobType).
+				WithCode(errors.CodeConfiguration))
+		}
+
+		if typeCfg.Concurrency == 0 && typeCfg.RateLimit == 0 {
+			errs = append(errs, errors.Newf("worker type %q must set concurrency or rate_limit", jobType).
+				WithCode(errors.CodeConfiguration))
+		}
+	}
+
+	switch c.Worker.ThrottlePolicy {
+	case "", ThrottleWait, ThrottleRequeue:
+
+	default:
+		errs = append(errs, errors.Newf("unsupported worker throttle_policy %q",
+			c.Worker.ThrottlePolicy).
+			WithCode(errors.CodeConfiguration).
+			WithMetadata("supported", []string{ThrottleWait, ThrottleRequeue}))
+	}
+
+	if c.Worker.ThrottleMaxWait < 0 {
+		errs = append(errs, errors.New("worker throttle_max_wait must not be negative").
+			WithCode(errors.CodeConfiguration))
+	}
+
+	if err := c.Worker.Autoscale.Validate(); err != nil {
+		errs = append(errs, errors.Wrap(err, "invalid worker autoscale configuration"))
+	}
+
+	if err := c.Worker.ReservedLane.Validate(); err != nil {
+		errs = append(errs, errors.Wrap(err, "invalid worker reserved_lane configuration"))
+	}
+
+	seen := make(map[string]bool, len(c.Worker.Queues))
+	for i, q := range c.Worker.Queues {
+		switch {
+		case q.Name == "":
+			errs = append(errs, errors.Newf("worker queue %d must set a name", i).
+				WithCode(errors.CodeConfiguration))
+
+		case seen[q.Name]:
+			errs = append(errs, errors.Newf("worker queue %q is listed twice", q.Name).
+				WithCode(errors.CodeConfiguration))
+		}
+
+		if q.Weight < 0 {
+			errs = append(errs, errors.Newf("worker queue %q weight must not be negative", q.Name).
+				WithCode(errors.CodeConfiguration))
+		}
+
+		seen[q.Name] = true
+	}
+
+	if err := c.Worker.Deadlines.Validate(); err != nil {
+		errs = append(errs, errors.Wrap(err, "invalid worker deadlines configuration"))
+	}
+
+	if lane := c.Worker.ReservedLane; lane.Workers > 0 && !c.Worker.Autoscale.Enabled &&
+		lane.Workers >= c.Worker.Concurrency {
+		errs = append(errs, errors.Newf("worker reserved_lane workers (%d) must be fewer than concurrency (%d)",
+			lane.Workers, c.Worker.Concurrency).
+			WithCode(errors.CodeConfiguration))
+	}
+
+	if c.Tracing.SampleRate < 0 || c.Tracing.SampleRate > 1 {
+		errs = append(errs, errors.Newf("tracing sample_rate must be between 0 and 1, got %g",
+			c.Tracing.SampleRate).
+			WithCode(errors.CodeConfiguration))
+	}
+
+	if _, err := c.Queue.Backoff.Build(); err != nil {
+		errs = append(errs, errors.Wrap(err, "invalid queue backoff configuration"))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks the autoscaler bounds and targets when it is enabled
+func (c AutoscaleConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	var errs []error
+	if c.MinConcurrency < 1 || c.MaxConcurrency < c.MinConcurrency {
+		errs = append(errs, errors.Newf("concurrency bounds must satisfy 1 <= min (%d) <= max (%d)",
+			c.MinConcurrency, c.MaxConcurrency).
+			WithCode(errors.CodeConfiguration))
+	}
+
+	if c.Interval <= 0 {
+		errs = append(errs, errors.New("interval must be positive").
+			WithCode(errors.CodeConfiguration))
+	}
+
+	if c.TargetBacklog <= 0 && c.TargetLatency <= 0 {
+		errs = append(errs, errors.New("target_backlog or target_latency must be set").
+			WithCode(errors.CodeConfiguration))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks that enabled authentication has keys to check against,
+// each named, hashed, and scoped
+func (c AuthConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	var errs []error
+	if len(c.Keys) == 0 && !c.Database {
+		errs = append(errs, errors.New("keys or database must be set").
+			WithCode(errors.CodeConfiguration))
+	}
+
+	if c.CacheTTL < 0 {
+		errs = append(errs, errors.New("cache_ttl must not be negative").
+			WithCode(errors.CodeConfiguration))
+	}
+
+	names := make(map[string]bool, len(c.Keys))
+	for i, key := range c.Keys {
+		if key.Name == "" || names[key.Name] {
+			errs = append(errs, errors.Newf("key %d must have a unique name", i).
+				WithCode(errors.CodeConfiguration))
+		}
+
+		names[key.Name] = true
+		if _, err := hex.DecodeString(key.Hash); err != nil || len(key.Hash) != 64 {
+			errs = append(errs, errors.Newf("key %q hash must be a hex SHA-256", key.Name).
+				WithCode(errors.CodeConfiguration))
+		}
+
+		if len(key.Scopes) == 0 {
+			errs = append(errs, errors.Newf("key %q must grant at least one scope", key.Name).
+				WithCode(errors.CodeConfiguration))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks the rate limits, backend, and trusted proxies when rate
+// limiting is enabled
+func (c RateLimitConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	var errs []error
+	switch c.Backend {
+	case "", RateLimitMemory, RateLimitRedis:
+
+	default:
+		errs = append(errs, errors.Newf("unsupported backend %q", c.Backend).
+			WithCode(errors.CodeConfiguration).
+			WithMetadata("supported", []string{RateLimitMemory, RateLimitRedis}))
+	}
+
+	if c.RequestsPerSecond <= 0 || c.Burst < 1 {
+		errs = append(errs, errors.New("requests_per_second must be positive and burst at least 1").
+			WithCode(errors.CodeConfiguration))
+	}
+
+	if _, err := c.TrustedPrefixes(); err != nil {
+		errs = append(errs, err)
+	}
+
+	for i, route := range c.Routes {
+		if route.Method == "" || !strings.HasPrefix(route.Path, "/") {
+			errs = append(errs, errors.Newf("route %d must set a method and a path starting with /", i).
+				WithCode(errors.CodeConfiguration))
+		}
+
+		if route.RequestsPerSecond <= 0 || route.Burst < 1 {
+			errs = append(errs, errors.Newf("route %s %s requests_per_second must be positive and burst at least 1",
+				route.Method, route.Path).
+				WithCode(errors.CodeConfiguration))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks that neither batch limit is negative
+func (c BatchConfig) Validate() error {
+	if c.MaxItems < 0 || c.MaxBodyBytes < 0 {
+		return errors.New("max_items and max_body_bytes must not be negative").
+			WithCode(errors.CodeConfiguration)
+	}
+
+	return nil
+}
+
+// Validate checks that deadlines name known priorities and that no
+// duration is negative
+func (c DeadlineConfig) Validate() error {
+	var errs []error
+	for _, name := range sortedKeys(c.Priorities) {
+		if _, ok := models.ParseJobPriority(name); !ok {
+			errs = append(errs, errors.Newf("unsupported priority %q", name).
+				WithCode(errors.CodeConfiguration).
+				WithMetadata("supported", []string{"low", "normal", "high", "critical"}))
+		}
+
+		if c.Priorities[name] < 0 {
+			errs = append(errs, errors.Newf("priority %q deadline must not be negative", name).
+				WithCode(errors.CodeConfiguration))
+		}
+	}
+
+	for _, jobType := range sortedKeys(c.Types) {
+		if c.Types[jobType] < 0 {
+			errs = append(errs, errors.Newf("type %q deadline must not be negative", jobType).
+				WithCode(errors.CodeConfiguration))
+		}
+	}
+
+	if c.EscalateInterval < 0 {
+		errs = append(errs, errors.New("escalate_interval must not be negative").
+			WithCode(errors.CodeConfiguration))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// sortedKeys returns the keys of m in order, so errors are reported in a
+// stable order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// Validate checks the reserved lane's size and priority when it is enabled
+func (c ReservedLaneConfig) Validate() error {
+	if c.Workers < 0 {
+		return errors.New("workers must not be negative").
+			WithCode(errors.CodeConfiguration)
+	}
+
+	if c.Workers == 0 {
+		return nil
+	}
+
+	var errs []error
+	if _, ok := models.ParseJobPriority(c.MinPriority); !ok {
+		errs = append(errs, errors.Newf("unsupported min_priority %q", c.MinPriority).
+			WithCode(errors.CodeConfiguration).
+			WithMetadata("supported", []string{"low", "normal", "high", "critical"}))
+	}
+
+	if c.IdleGrace < 0 {
+		errs = append(errs, errors.New("idle_grace must not be negative").
+			WithCode(errors.CodeConfiguration))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	return nil
+}
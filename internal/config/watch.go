@@ -0,0 +1,158 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long Watch waits for the configuration file to stop
+// changing before reloading it, so a write in several steps is read once
+const watchDebounce = 100 * time.Millisecond
+
+// Watch reloads the configuration file whenever it changes on disk and passes
+// the result to fn. Each reload is logged with its diff against the previous
+// configuration and counted in ReloadsTotal, and a changed log section is
+// applied to the logger. The containing directory is watched so that editors and
+// Kubernetes ConfigMap updates that replace the file are picked up. A reload
+// waits for the file to settle, and an empty file, as seen mid-write, is
+// skipped. Watch returns the error of the initial load, and otherwise blocks
+// until ctx is canceled.
+func Watch(ctx context.Context, configPath string, fn WatchFunc,
+	opts ...Option) error {
+	current, err := Load(configPath, opts...)
+	if err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrap(err, "failed to create config watcher").
+			WithCode(errors.CodeConfiguration)
+	}
+	defer watcher.Close()
+
+	r := newReloader(current, fn, opts)
+	target := filepath.Clean(configPath)
+	if err := watcher.Add(filepath.Dir(target)); err != nil {
+		return errors.Wrapf(err, "failed to watch %s", configPath).
+			WithCode(errors.CodeConfiguration)
+	}
+
+	settled := time.NewTimer(watchDebounce)
+	settled.Stop()
+	defer settled.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			if filepath.Clean(event.Name) != target ||
+				!event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+				continue
+			}
+
+			settled.Reset(watchDebounce)
+
+		case <-settled.C:
+			if info, err := os.Stat(target); err == nil && info.Size() == 0 {
+				r.log.Debug("skipping reload of empty configuration file")
+				continue
+			}
+
+			r.reload(Load(configPath, opts...))
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			fn(nil, errors.Wrap(err, "config watcher failed").
+				WithCode(errors.CodeConfiguration))
+		}
+	}
+}
+
+// reloader logs and counts reloads and remembers the last applied
+// configuration so each reload can be diffed against it
+type reloader struct {
+	current *Config
+	log     logger.Logger
+	fn      WatchFunc
+}
+
+// newReloader creates a reloader starting from current, which may be nil
+func newReloader(current *Config, fn WatchFunc, opts []Option) *reloader {
+	options := loadOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	log := options.logger
+	if log == nil {
+		log = logger.Global()
+	}
+
+	return &reloader{current: current, log: log.Named("config"), fn: fn}
+}
+
+// reload handles the result of loading a changed configuration
+func (r *reloader) reload(cfg *Config, err error) {
+	if err != nil {
+		ReloadsTotal.WithLabelValues("rejected").Inc()
+		r.log.Warn("configuration reload rejected", "error", err)
+		r.fn(nil, err)
+		return
+	}
+
+	ReloadsTotal.WithLabelValues("applied").Inc()
+	r.log.Info("configuration reloaded", "changes", Diff(r.current, cfg))
+	r.applyLog(cfg)
+	r.current = cfg
+	r.fn(cfg, nil)
+}
+
+// applyLog applies a reloaded log section. A changed format or output path
+// replaces the global logger; when the new settings cannot be built the
+// current logger is kept. A changed level alone is applied in place.
+func (r *reloader) applyLog(cfg *Config) {
+	if r.current != nil && (r.current.Log.Format != cfg.Log.Format ||
+		r.current.Log.OutputPath != cfg.Log.OutputPath) {
+		log, err := logger.NewWithError(cfg.Log.LoggerConfig())
+		if err == nil {
+			logger.SetGlobal(log)
+			r.log = log.Named("config")
+			return
+		}
+
+		r.log.Warn("log settings not applied, keeping the current logger", "error", err)
+	}
+
+	r.applyLogLevel(cfg)
+}
+
+// applyLogLevel switches the logger to the reloaded log.level when it
+// changed. Loggers derived from the same logger.New share the level, so the
+// whole process follows.
+func (r *reloader) applyLogLevel(cfg *Config) {
+	level := cfg.Log.Level
+	if level == "" || r.current != nil && r.current.Log.Level == level {
+		return
+	}
+
+	if err := r.log.SetLevel(level); err != nil {
+		r.log.Warn("log level not applied", "level", level, "error", err)
+	}
+}
@@ -0,0 +1,19 @@
+// Package eventbus carries job status changes from the processes that make
+// them, workers and the job service, to the API's event streams.
+//
+// MemoryBus delivers events within one process. RedisBus shares them
+// between processes over Redis pub/sub, for deployments running several API
+// or worker instances. Publishing never waits for subscribers: each has a
+// bounded buffer, and one that falls a full buffer behind is dropped, with
+// its Events channel closed and Dropped reporting true.
+//
+// Subscribing to one job's events:
+//
+//	sub := bus.Subscribe(eventbus.Filter{JobID: id})
+//	defer sub.Close()
+//	for event := range sub.Events() {
+//	    if event.Status.Terminal() {
+//	        break
+//	    }
+//	}
+package eventbus
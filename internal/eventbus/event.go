@@ -0,0 +1,81 @@
+package eventbus
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"task-queue/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Event is a change in a job's status, named like the audit events, e.g.
+// job.completed or job.dead_lettered
+type Event struct {
+	Type       string           `json:"type"`
+	JobID      uuid.UUID        `json:"job_id"`
+	JobType    string           `json:"job_type"`
+	Status     models.JobStatus `json:"status"`
+	RetryCount int              `json:"retry_count"`
+	Error      string           `json:"error,omitempty"`
+	Time       time.Time        `json:"time"`
+}
+
+// NewEvent returns an event of eventType reporting job's current state
+func NewEvent(eventType string, job *models.Job) Event {
+	event := Event{
+		Type:       eventType,
+		JobID:      job.ID,
+		JobType:    job.Type,
+		Status:     job.Status,
+		RetryCount: job.RetryCount,
+		Time:       time.Now().UTC(),
+	}
+
+	if job.Error != nil {
+		event.Error = *job.Error
+	}
+
+	return event
+}
+
+// Filter selects the events of a subscription. Empty fields match every
+// event.
+type Filter struct {
+	JobID    uuid.UUID
+	JobTypes []string
+	Statuses []models.JobStatus
+}
+
+// Match reports whether event passes f
+func (f Filter) Match(event Event) bool {
+	if f.JobID != uuid.Nil && event.JobID != f.JobID {
+		return false
+	}
+
+	if len(f.JobTypes) > 0 && !slices.Contains(f.JobTypes, event.JobType) {
+		return false
+	}
+
+	return len(f.Statuses) == 0 || slices.Contains(f.Statuses, event.Status)
+}
+
+// Publisher publishes job events. Workers and the job service publish to
+// it; a failed publish must not fail the change it reports.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// Bus delivers published events to its subscribers. Publishing never
+// blocks on a subscriber: one that falls a full buffer behind is dropped.
+type Bus interface {
+	Publisher
+
+	// Subscribe returns a subscription receiving the events matching
+	// filter until it is closed
+	Subscribe(filter Filter) *Subscription
+
+	// Close ends every subscription and stops delivering events
+	Close() error
+}
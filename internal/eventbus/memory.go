@@ -0,0 +1,173 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+
+	"task-queue/pkg/errors"
+)
+
+// DefaultBufferSize is how many events a subscriber may fall behind before
+// it is dropped
+const DefaultBufferSize = 64
+
+// Option configures a bus
+type Option func(*options)
+
+// options are the settings shared by the bus implementations
+type options struct {
+	bufferSize int
+}
+
+// WithBufferSize sets how many events a subscriber may fall behind before
+// it is dropped
+func WithBufferSize(n int) Option {
+	return func(o *options) {
+		o.bufferSize = n
+	}
+}
+
+// newOptions applies opts over the defaults
+func newOptions(opts []Option) options {
+	o := options{bufferSize: DefaultBufferSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.bufferSize < 1 {
+		o.bufferSize = 1
+	}
+
+	return o
+}
+
+// Subscription receives the events of a bus matching its filter
+type Subscription struct {
+	events  chan Event
+	filter  Filter
+	bus     *MemoryBus
+	dropped bool
+}
+
+// Events returns the channel delivering events. It is closed when the
+// subscription is closed, the bus is closed, or the subscriber is dropped
+// for falling behind.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Dropped reports whether the subscription ended because its subscriber
+// fell behind. It is only meaningful once Events is closed.
+func (s *Subscription) Dropped() bool {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	return s.dropped
+}
+
+// Close ends the subscription. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	s.bus.remove(s)
+}
+
+// MemoryBus delivers events within the process
+type MemoryBus struct {
+	mu          sync.Mutex
+	options     options
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+var _ Bus = (*MemoryBus)(nil)
+
+// NewMemoryBus creates an in-process bus
+func NewMemoryBus(opts ...Option) *MemoryBus {
+	return &MemoryBus{
+		options:     newOptions(opts),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish delivers event to every matching subscriber without blocking.
+// A subscriber whose buffer is full is dropped.
+func (b *MemoryBus) Publish(ctx context.Context, event Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return errors.New("event bus is closed").
+			WithCode(errors.CodeInternal).
+			WithOp("eventbus.Publish")
+	}
+
+	for sub := range b.subscribers {
+		if !sub.filter.Match(event) {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+
+		default:
+			sub.dropped = true
+			b.remove(sub)
+		}
+	}
+
+	return nil
+}
+
+// Subscribe returns a subscription receiving the events matching filter.
+// On a closed bus the subscription's channel is already closed.
+func (b *MemoryBus) Subscribe(filter Filter) *Subscription {
+	sub := &Subscription{
+		events: make(chan Event, b.options.bufferSize),
+		filter: filter,
+		bus:    b,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		close(sub.events)
+		return sub
+	}
+
+	b.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// Subscribers returns the number of open subscriptions
+func (b *MemoryBus) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.subscribers)
+}
+
+// Close ends every subscription
+func (b *MemoryBus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subscribers {
+		b.remove(sub)
+	}
+
+	return nil
+}
+
+// remove ends sub if it is still subscribed. The caller holds b.mu.
+func (b *MemoryBus) remove(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+
+	delete(b.subscribers, sub)
+	close(sub.events)
+}
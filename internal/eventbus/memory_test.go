@@ -0,0 +1,93 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"task-queue/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// event returns an event for a new job of jobType with status
+func event(jobType string, status models.JobStatus) Event {
+	job := models.NewJob(jobType, json.RawMessage(`{}`), models.JobPriorityNormal)
+	job.Status = status
+	return NewEvent("job.test", job)
+}
+
+func TestMemoryBus_FiltersEvents(t *testing.T) {
+	bus := NewMemoryBus()
+	defer bus.Close()
+
+	target := event("email_send", models.JobStatusRunning)
+	byJob := bus.Subscribe(Filter{JobID: target.JobID})
+	byType := bus.Subscribe(Filter{JobTypes: []string{"email_send"}, Statuses: []models.JobStatus{
+		models.JobStatusFailed,
+	}})
+	all := bus.Subscribe(Filter{})
+
+	ctx := context.Background()
+	failed := event("email_send", models.JobStatusFailed)
+	other := event("report_build", models.JobStatusFailed)
+	for _, e := range []Event{target, failed, other} {
+		require.NoError(t, bus.Publish(ctx, e))
+	}
+
+	assert.Equal(t, target, <-byJob.Events())
+	assert.Empty(t, byJob.Events())
+	assert.Equal(t, failed, <-byType.Events())
+	assert.Empty(t, byType.Events())
+	assert.Len(t, all.Events(), 3)
+}
+
+func TestMemoryBus_DropsSlowSubscriber(t *testing.T) {
+	bus := NewMemoryBus(WithBufferSize(2))
+	defer bus.Close()
+
+	slow := bus.Subscribe(Filter{})
+	fast := bus.Subscribe(Filter{})
+
+	ctx := context.Background()
+	for range 3 {
+		require.NoError(t, bus.Publish(ctx, event("email_send", models.JobStatusRunning)))
+		<-fast.Events()
+	}
+
+	// The slow subscriber gets the events it buffered, then its channel
+	// closes
+	received := 0
+	for range slow.Events() {
+		received++
+	}
+
+	assert.Equal(t, 2, received)
+	assert.True(t, slow.Dropped())
+	assert.False(t, fast.Dropped())
+
+	require.NoError(t, bus.Publish(ctx, event("email_send", models.JobStatusRunning)))
+	assert.Len(t, fast.Events(), 1, "dropping a subscriber affected the others")
+}
+
+func TestMemoryBus_Close(t *testing.T) {
+	bus := NewMemoryBus()
+	sub := bus.Subscribe(Filter{})
+	sub.Close()
+	sub.Close()
+
+	_, open := <-sub.Events()
+	assert.False(t, open)
+	assert.False(t, sub.Dropped())
+
+	active := bus.Subscribe(Filter{})
+	require.NoError(t, bus.Close())
+	_, open = <-active.Events()
+	assert.False(t, open)
+
+	assert.Error(t, bus.Publish(context.Background(), event("email_send", models.JobStatusRunning)))
+
+	_, open = <-bus.Subscribe(Filter{}).Events()
+	assert.False(t, open, "subscribing to a closed bus")
+}
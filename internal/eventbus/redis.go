@@ -0,0 +1,123 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultChannel is the Redis pub/sub channel carrying job events
+const DefaultChannel = "task-queue:events"
+
+// RedisBus shares events between processes over Redis pub/sub. Each
+// process relays the events of the channel to its own subscribers through
+// a MemoryBus, so a slow subscriber never slows the relay.
+type RedisBus struct {
+	client  redis.UniversalClient
+	channel string
+	pubsub  *redis.PubSub
+	local   *MemoryBus
+	logger  logger.Logger
+	done    chan struct{}
+	once    sync.Once
+}
+
+var _ Bus = (*RedisBus)(nil)
+
+// NewRedisBus subscribes to channel and starts relaying its events. An
+// empty channel uses DefaultChannel.
+func NewRedisBus(ctx context.Context, client redis.UniversalClient, channel string,
+	log logger.Logger, opts ...Option) (*RedisBus, error) {
+	if client == nil {
+		return nil, errors.New("redis client is required").
+			WithCode(errors.CodeConfiguration).
+			WithOp("eventbus.NewRedisBus")
+	}
+
+	if channel == "" {
+		channel = DefaultChannel
+	}
+
+	pubsub := client.Subscribe(ctx, channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, errors.Wrapf(errors.FromRedis(err), "failed to subscribe to %s", channel).
+			WithOp("eventbus.NewRedisBus")
+	}
+
+	b := &RedisBus{
+		client:  client,
+		channel: channel,
+		pubsub:  pubsub,
+		local:   NewMemoryBus(opts...),
+		logger:  log.Named("eventbus"),
+		done:    make(chan struct{}),
+	}
+
+	go b.relay()
+	return b, nil
+}
+
+// relay delivers the channel's messages to the local subscribers until the
+// bus is closed
+func (b *RedisBus) relay() {
+	defer close(b.done)
+
+	for msg := range b.pubsub.Channel() {
+		var event Event
+		if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+			b.logger.Warn("dropping undecodable event", "error", err)
+			continue
+		}
+
+		if err := b.local.Publish(context.Background(), event); err != nil {
+			return
+		}
+	}
+}
+
+// Publish sends event to every process subscribed to the channel,
+// including this one
+func (b *RedisBus) Publish(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode event").
+			WithCode(errors.CodeSerialization).
+			WithOp("eventbus.Publish")
+	}
+
+	if err := b.client.Publish(ctx, b.channel, data).Err(); err != nil {
+		return errors.Wrap(errors.FromRedis(err), "failed to publish event").
+			WithOp("eventbus.Publish")
+	}
+
+	return nil
+}
+
+// Subscribe returns a subscription receiving the events matching filter
+func (b *RedisBus) Subscribe(filter Filter) *Subscription {
+	return b.local.Subscribe(filter)
+}
+
+// Close unsubscribes from the channel and ends every subscription. The
+// Redis client is managed by the caller.
+func (b *RedisBus) Close() error {
+	var err error
+	b.once.Do(func() {
+		err = b.pubsub.Close()
+		<-b.done
+		_ = b.local.Close()
+	})
+
+	if err != nil {
+		return errors.Wrap(errors.FromRedis(err), "failed to close event bus").
+			WithOp("eventbus.Close")
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package grpcserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	taskqueuev1 "task-queue/api/proto/taskqueue/v1"
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/validation"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// statuses maps model statuses to their protobuf values
+var statuses = map[models.JobStatus]taskqueuev1.JobStatus{
+	models.JobStatusPending:   taskqueuev1.JobStatus_JOB_STATUS_PENDING,
+	models.JobStatusRunning:   taskqueuev1.JobStatus_JOB_STATUS_RUNNING,
+	models.JobStatusCompleted: taskqueuev1.JobStatus_JOB_STATUS_COMPLETED,
+	models.JobStatusFailed:    taskqueuev1.JobStatus_JOB_STATUS_FAILED,
+	models.JobStatusRetrying:  taskqueuev1.JobStatus_JOB_STATUS_RETRYING,
+	models.JobStatusDead:      taskqueuev1.JobStatus_JOB_STATUS_DEAD,
+	models.JobStatusCancelled: taskqueuev1.JobStatus_JOB_STATUS_CANCELLED,
+}
+
+// parseID parses the job ID of a request
+func parseID(id string) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, errors.Validation("invalid job ID %q", id).
+			WithOp("grpcserver.parseID")
+	}
+
+	return parsed, nil
+}
+
+// fromPriority converts a protobuf priority to a model priority. The
+// protobuf values are one higher, leaving zero for unspecified, which
+// submits at low priority.
+func fromPriority(priority taskqueuev1.JobPriority) models.JobPriority {
+	if priority == taskqueuev1.JobPriority_JOB_PRIORITY_UNSPECIFIED {
+		return models.JobPriorityLow
+	}
+
+	return models.JobPriority(priority - 1)
+}
+
+// toPriority converts a model priority to its protobuf value
+func toPriority(priority models.JobPriority) taskqueuev1.JobPriority {
+	return taskqueuev1.JobPriority(priority + 1)
+}
+
+// fromStatus converts a protobuf status to a model status
+func fromStatus(status taskqueuev1.JobStatus) (models.JobStatus, bool) {
+	for model, value := range statuses {
+		if value == status {
+			return model, true
+		}
+	}
+
+	return "", false
+}
+
+// fromTime converts an optional timestamp
+func fromTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+
+	t := ts.AsTime()
+	return &t
+}
+
+// toTime converts an optional time
+func toTime(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+
+	return timestamppb.New(*t)
+}
+
+// fromString converts an optional string, treating empty as unset
+func fromString(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+// fromSubmitRequest converts a submit request to a job request
+func fromSubmitRequest(req *taskqueuev1.SubmitJobRequest) *models.JobRequest {
+	jobReq := &models.JobRequest{
+		Type:        req.GetType(),
+		Payload:     json.RawMessage(req.GetPayload()),
+		Priority:    fromPriority(req.GetPriority()),
+		ScheduledAt: fromTime(req.GetScheduledAt()),
+		Metadata:    req.GetMetadata().AsMap(),
+	}
+
+	if req.MaxRetries != nil {
+		maxRetries := int(req.GetMaxRetries())
+		jobReq.MaxRetries = &maxRetries
+	}
+
+	return jobReq
+}
+
+// toJob converts a job to its protobuf message. Metadata that cannot be
+// represented as a protobuf Struct fails with CodeSerialization.
+func toJob(job *models.Job) (*taskqueuev1.Job, error) {
+	msg := &taskqueuev1.Job{
+		Id:          job.ID.String(),
+		Type:        job.Type,
+		Payload:     job.Payload,
+		Status:      statuses[job.Status],
+		Priority:    toPriority(job.Priority),
+		MaxRetries:  int32(job.MaxRetries),
+		RetryCount:  int32(job.RetryCount),
+		CreatedAt:   timestamppb.New(job.CreatedAt),
+		UpdatedAt:   timestamppb.New(job.UpdatedAt),
+		ScheduledAt: toTime(job.ScheduledAt),
+		StartedAt:   toTime(job.StartedAt),
+		CompletedAt: toTime(job.CompletedAt),
+		Error:       fromString(job.Error),
+		Result:      job.Result,
+		WorkerId:    fromString(job.WorkerID),
+	}
+
+	if len(job.Metadata) > 0 {
+		metadata, err := structpb.NewStruct(job.Metadata)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to encode metadata of job %s", job.ID).
+				WithCode(errors.CodeSerialization).
+				WithOp("grpcserver.toJob")
+		}
+
+		msg.Metadata = metadata
+	}
+
+	return msg, nil
+}
+
+// fromListRequest converts a listing request to a job filter. Unknown
+// statuses and priorities fail with a validation error naming the entry.
+func fromListRequest(req *taskqueuev1.ListJobsRequest) (models.JobFilter, error) {
+	filter := models.JobFilter{
+		Types:         req.GetTypes(),
+		CreatedAfter:  fromTime(req.GetCreatedAfter()),
+		CreatedBefore: fromTime(req.GetCreatedBefore()),
+		Tags:          req.GetTags(),
+		Metadata:      req.GetMetadata(),
+		Search:        req.GetQuery(),
+		Limit:         int(req.GetLimit()),
+	}
+
+	fields := []*validation.Field{
+		validation.NewField("types", req.GetTypes(), validation.Each(validation.JobType())),
+	}
+
+	for i, value := range req.GetStatuses() {
+		status, ok := fromStatus(value)
+		if !ok {
+			fields = append(fields, invalid(fmt.Sprintf("statuses[%d]", i), value))
+			continue
+		}
+
+		filter.Statuses = append(filter.Statuses, status)
+	}
+
+	for i, value := range req.GetPriorities() {
+		if _, ok := taskqueuev1.JobPriority_name[int32(value)]; !ok ||
+			value == taskqueuev1.JobPriority_JOB_PRIORITY_UNSPECIFIED {
+			fields = append(fields, invalid(fmt.Sprintf("priorities[%d]", i), value))
+			continue
+		}
+
+		filter.Priorities = append(filter.Priorities, fromPriority(value))
+	}
+
+	if err := validation.ValidateAll(fields...); err != nil {
+		return models.JobFilter{}, err
+	}
+
+	return filter, nil
+}
+
+// invalid returns a field that always fails, reporting an unknown enum
+// value
+func invalid(name string, value fmt.Stringer) *validation.Field {
+	return validation.NewField(name, value, validation.ValidatorFunc(func(any) error {
+		return fmt.Errorf("unknown value %s", value)
+	}))
+}
@@ -0,0 +1,25 @@
+// Package grpcserver serves the task queue's gRPC job API, defined in
+// api/proto/taskqueue/v1/jobs.proto. It is backed by the same
+// service.JobService as the HTTP API, so both enforce the same validation
+// and cancellation rules.
+//
+// Errors are converted with errors.ToGRPCError: invalid requests are
+// InvalidArgument, unknown jobs NotFound, and cancelling a running or
+// finished job Aborted, each with an ErrorInfo detail carrying the task
+// queue error code. SubmitJobs reports each rejected job in its result
+// instead of failing the call.
+//
+// The interceptors store the caller's x-request-id metadata, or a generated
+// ID, under logger.RequestIDKey and echo it in the response header, and
+// store the trace ID of a W3C traceparent under logger.TraceIDKey. With
+// TracingOption every call is also a server span continuing the caller's
+// trace, so the jobs it submits join that trace. A panicking call fails
+// with codes.Internal instead of crashing the server.
+//
+// Serving the API with the configured address and TLS:
+//
+//	srv := grpcserver.New(svc, log)
+//	if err := grpcserver.Serve(ctx, cfg.GRPC, srv, grpcserver.TracingOption(tp)); err != nil {
+//	    return err
+//	}
+package grpcserver
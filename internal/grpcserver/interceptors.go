@@ -0,0 +1,127 @@
+package grpcserver
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Metadata keys read and written by the interceptors
+const (
+	RequestIDHeader   = "x-request-id"
+	TraceParentHeader = "traceparent"
+)
+
+// UnaryInterceptor propagates request and trace IDs into the context of
+// each call and converts its error to a gRPC status. A panicking handler
+// fails its call with CodeInternal instead of crashing the server.
+func (s *Server) UnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (any, error) {
+	ctx = s.withRequestContext(ctx)
+
+	resp, err := callUnary(ctx, req, handler)
+	if err != nil {
+		return nil, s.fail(ctx, info.FullMethod, err)
+	}
+
+	return resp, nil
+}
+
+// StreamInterceptor is UnaryInterceptor for streaming calls
+func (s *Server) StreamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	ctx := s.withRequestContext(stream.Context())
+
+	if err := callStream(srv, &contextStream{ServerStream: stream, ctx: ctx}, handler); err != nil {
+		return s.fail(ctx, info.FullMethod, err)
+	}
+
+	return nil
+}
+
+// callUnary runs handler, turning a panic into a CodeInternal error
+func callUnary(ctx context.Context, req any, handler grpc.UnaryHandler) (resp any, err error) {
+	defer errors.Recover(&err)
+	return handler(ctx, req)
+}
+
+// callStream runs handler, turning a panic into a CodeInternal error
+func callStream(srv any, stream grpc.ServerStream, handler grpc.StreamHandler) (err error) {
+	defer errors.Recover(&err)
+	return handler(srv, stream)
+}
+
+// contextStream is a server stream with a replaced context
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the stream's replaced context
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// withRequestContext stores the caller's request ID, or a new one, and the
+// trace ID of its traceparent in ctx. The request ID is echoed in the
+// response header.
+func (s *Server) withRequestContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	requestID := first(md, RequestIDHeader)
+	if requestID == "" {
+		requestID = uuid.NewString()
+	}
+
+	ctx = context.WithValue(ctx, logger.RequestIDKey, requestID)
+	if err := grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, requestID)); err != nil {
+		s.logger.WithContext(ctx).Debug("failed to set request ID header", "error", err)
+	}
+
+	if traceID, ok := parseTraceParent(first(md, TraceParentHeader)); ok {
+		ctx = context.WithValue(ctx, logger.TraceIDKey, traceID)
+	}
+
+	return ctx
+}
+
+// fail converts err to a gRPC status error, logging server errors
+func (s *Server) fail(ctx context.Context, method string, err error) error {
+	if errors.GetHTTPStatus(err) >= http.StatusInternalServerError {
+		s.logger.WithContext(ctx).Error("request failed", "method", method, "error", err)
+	}
+
+	return errors.ToGRPCError(err)
+}
+
+// first returns the first value of key in md
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// parseTraceParent returns the trace ID of a W3C traceparent header such
+// as 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func parseTraceParent(header string) (string, bool) {
+	parts := strings.Split(header, "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 {
+		return "", false
+	}
+
+	traceID := parts[1]
+	if strings.Trim(traceID, "0") == "" || strings.Trim(traceID, "0123456789abcdef") != "" {
+		return "", false
+	}
+
+	return traceID, true
+}
@@ -0,0 +1,109 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	taskqueuev1 "task-queue/api/proto/taskqueue/v1"
+	"task-queue/internal/config"
+	"task-queue/internal/tracing"
+	"task-queue/pkg/errors"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// NewGRPCServer returns a gRPC server with srv registered behind its
+// interceptors. opts are applied after the interceptors.
+func NewGRPCServer(srv *Server, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(srv.UnaryInterceptor),
+		grpc.ChainStreamInterceptor(srv.StreamInterceptor),
+	}, opts...)
+
+	gs := grpc.NewServer(opts...)
+	taskqueuev1.RegisterJobServiceServer(gs, srv)
+	return gs
+}
+
+// TracingOption traces every call on tp as a server span, continuing the
+// trace of the caller's traceparent metadata
+func TracingOption(tp trace.TracerProvider) grpc.ServerOption {
+	return grpc.StatsHandler(otelgrpc.NewServerHandler(
+		otelgrpc.WithTracerProvider(tp),
+		otelgrpc.WithPropagators(tracing.Propagator)))
+}
+
+// Serve answers calls to srv on cfg.Host and cfg.Port until ctx is
+// canceled, then waits up to cfg.ShutdownTimeout for in-flight calls before
+// closing the rest. Calls are served over TLS with cfg.TLSCertFile and
+// cfg.TLSKeyFile when cfg.TLSEnabled is set. opts are passed on to
+// NewGRPCServer.
+func Serve(ctx context.Context, cfg config.GRPCConfig, srv *Server, opts ...grpc.ServerOption) error {
+	if cfg.TLSEnabled {
+		creds, err := credentials.NewServerTLSFromFile(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return errors.Wrap(err, "failed to load grpc tls certificate").
+				WithCode(errors.CodeConfiguration).
+				WithOp("grpcserver.Serve")
+		}
+
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	addr := net.JoinHostPort(cfg.Host, fmt.Sprint(cfg.Port))
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on %s", addr).
+			WithCode(errors.CodeConfiguration).
+			WithOp("grpcserver.Serve")
+	}
+
+	return serve(ctx, ln, cfg.ShutdownTimeout, NewGRPCServer(srv, opts...))
+}
+
+// serve answers calls to gs on ln until ctx is canceled
+func serve(ctx context.Context, ln net.Listener, shutdownTimeout time.Duration, gs *grpc.Server) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- gs.Serve(ln)
+	}()
+
+	select {
+	case err := <-done:
+		return errors.Wrap(err, "grpc server failed").
+			WithCode(errors.CodeNetwork).
+			WithOp("grpcserver.Serve")
+
+	case <-ctx.Done():
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		gs.GracefulStop()
+		close(stopped)
+	}()
+
+	timer := time.NewTimer(shutdownTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-stopped:
+
+	case <-timer.C:
+		gs.Stop()
+		<-stopped
+	}
+
+	if err := <-done; err != nil {
+		return errors.Wrap(err, "grpc server failed").
+			WithCode(errors.CodeNetwork).
+			WithOp("grpcserver.Serve")
+	}
+
+	return nil
+}
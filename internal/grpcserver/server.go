@@ -0,0 +1,163 @@
+package grpcserver
+
+import (
+	"context"
+
+	taskqueuev1 "task-queue/api/proto/taskqueue/v1"
+	"task-queue/internal/models"
+	"task-queue/internal/service"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements the gRPC JobService over a service.JobService
+type Server struct {
+	taskqueuev1.UnimplementedJobServiceServer
+
+	jobs   *service.JobService
+	logger logger.Logger
+}
+
+var _ taskqueuev1.JobServiceServer = (*Server)(nil)
+
+// New creates the gRPC job service over jobs
+func New(jobs *service.JobService, log logger.Logger) *Server {
+	return &Server{
+		jobs:   jobs,
+		logger: log.Named("grpc"),
+	}
+}
+
+// SubmitJob validates, stores, and enqueues a job
+func (s *Server) SubmitJob(ctx context.Context, req *taskqueuev1.SubmitJobRequest) (*taskqueuev1.Job, error) {
+	job, err := s.jobs.Submit(ctx, fromSubmitRequest(req))
+	if err != nil {
+		return nil, err
+	}
+
+	return toJob(job)
+}
+
+// SubmitJobs submits the valid jobs together. An invalid job is reported in
+// its result rather than failing the call.
+func (s *Server) SubmitJobs(ctx context.Context,
+	req *taskqueuev1.SubmitJobsRequest) (*taskqueuev1.SubmitJobsResponse, error) {
+	reqs := make([]*models.JobRequest, len(req.GetJobs()))
+	for i, jobReq := range req.GetJobs() {
+		reqs[i] = fromSubmitRequest(jobReq)
+	}
+
+	results, err := s.jobs.SubmitBatch(ctx, reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &taskqueuev1.SubmitJobsResponse{
+		Results: make([]*taskqueuev1.SubmitJobResult, len(results)),
+	}
+
+	for i, result := range results {
+		resp.Results[i] = &taskqueuev1.SubmitJobResult{}
+		if result.Err != nil {
+			resp.Results[i].Error = statusProto(result.Err)
+			continue
+		}
+
+		if resp.Results[i].Job, err = toJob(result.Job); err != nil {
+			resp.Results[i].Error = statusProto(err)
+		}
+	}
+
+	return resp, nil
+}
+
+// GetJob returns a job and its status
+func (s *Server) GetJob(ctx context.Context, req *taskqueuev1.GetJobRequest) (*taskqueuev1.Job, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.jobs.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toJob(job)
+}
+
+// ListJobs returns a page of the jobs matching the request
+func (s *Server) ListJobs(ctx context.Context,
+	req *taskqueuev1.ListJobsRequest) (*taskqueuev1.ListJobsResponse, error) {
+	filter, err := fromListRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := s.jobs.List(ctx, service.ListQuery{
+		Filter: filter,
+		Sort:   req.GetSort(),
+		Cursor: req.GetCursor(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &taskqueuev1.ListJobsResponse{
+		Items:         make([]*taskqueuev1.Job, len(page.Items)),
+		NextCursor:    page.NextCursor,
+		TotalEstimate: page.TotalEstimate,
+	}
+
+	for i, job := range page.Items {
+		if resp.Items[i], err = toJob(job); err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// CancelJob cancels a job that has not started
+func (s *Server) CancelJob(ctx context.Context, req *taskqueuev1.CancelJobRequest) (*taskqueuev1.Job, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.jobs.Cancel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toJob(job)
+}
+
+// WatchJob streams the job, then the job again on every status change,
+// until it reaches a final status
+func (s *Server) WatchJob(req *taskqueuev1.WatchJobRequest,
+	stream grpc.ServerStreamingServer[taskqueuev1.Job]) error {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return err
+	}
+
+	return s.jobs.Watch(stream.Context(), id, func(job *models.Job) error {
+		msg, err := toJob(job)
+		if err != nil {
+			return err
+		}
+
+		return stream.Send(msg)
+	})
+}
+
+// statusProto converts err to the google.rpc.Status of a batch result
+func statusProto(err error) *rpcstatus.Status {
+	st, _ := status.FromError(errors.ToGRPCError(err))
+	return st.Proto()
+}
@@ -0,0 +1,200 @@
+package grpcserver
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	taskqueuev1 "task-queue/api/proto/taskqueue/v1"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/internal/service"
+	"task-queue/internal/storage"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testServer is a JobService over in-memory stores, served in process
+type testServer struct {
+	client taskqueuev1.JobServiceClient
+	store  *storage.MemoryJobStore
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	s := &testServer{store: storage.NewMemoryJobStore()}
+	log := logger.NewNop()
+	svc := service.New(s.store, storage.NewMemoryEventStore(), queue.NewMemoryQueue(queue.Config{}), log,
+		service.WithWatchInterval(10*time.Millisecond))
+
+	ln := bufconn.Listen(1 << 20)
+	gs := NewGRPCServer(New(svc, log))
+	go func() { _ = gs.Serve(ln) }()
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return ln.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	s.client = taskqueuev1.NewJobServiceClient(conn)
+	return s
+}
+
+func TestSubmitJob(t *testing.T) {
+	s := newTestServer(t)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), RequestIDHeader, "req-123")
+
+	var header metadata.MD
+	job, err := s.client.SubmitJob(ctx, &taskqueuev1.SubmitJobRequest{
+		Type:     "email_send",
+		Payload:  []byte(`{"to":"a@example.com"}`),
+		Priority: taskqueuev1.JobPriority_JOB_PRIORITY_HIGH,
+	}, grpc.Header(&header))
+	require.NoError(t, err)
+
+	assert.Equal(t, "email_send", job.GetType())
+	assert.Equal(t, taskqueuev1.JobStatus_JOB_STATUS_PENDING, job.GetStatus())
+	assert.Equal(t, taskqueuev1.JobPriority_JOB_PRIORITY_HIGH, job.GetPriority())
+	assert.JSONEq(t, `{"to":"a@example.com"}`, string(job.GetPayload()))
+	assert.Equal(t, []string{"req-123"}, header.Get(RequestIDHeader))
+
+	stored, err := s.store.Get(context.Background(), uuid.MustParse(job.GetId()))
+	require.NoError(t, err)
+	assert.Equal(t, models.JobPriorityHigh, stored.Priority)
+
+	got, err := s.client.GetJob(context.Background(), &taskqueuev1.GetJobRequest{Id: job.GetId()})
+	require.NoError(t, err)
+	assert.Equal(t, job.GetId(), got.GetId())
+}
+
+func TestSubmitJobs_ReportsEachResult(t *testing.T) {
+	s := newTestServer(t)
+
+	resp, err := s.client.SubmitJobs(context.Background(), &taskqueuev1.SubmitJobsRequest{
+		Jobs: []*taskqueuev1.SubmitJobRequest{
+			{Type: "email_send", Payload: []byte(`{}`)},
+			{Type: "", Payload: []byte(`{}`)},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.GetResults(), 2)
+
+	assert.NotEmpty(t, resp.GetResults()[0].GetJob().GetId())
+	assert.Nil(t, resp.GetResults()[0].GetError())
+	assert.Nil(t, resp.GetResults()[1].GetJob())
+	assert.Equal(t, int32(codes.InvalidArgument), resp.GetResults()[1].GetError().GetCode())
+}
+
+func TestGetJob_NotFound(t *testing.T) {
+	s := newTestServer(t)
+
+	_, err := s.client.GetJob(context.Background(), &taskqueuev1.GetJobRequest{Id: uuid.NewString()})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, errors.CodeNotFound, errors.GetCode(errors.FromGRPCStatus(status.Convert(err))))
+
+	_, err = s.client.GetJob(context.Background(), &taskqueuev1.GetJobRequest{Id: "not-a-uuid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestSubmitJob_ValidationFailure(t *testing.T) {
+	s := newTestServer(t)
+
+	_, err := s.client.SubmitJob(context.Background(), &taskqueuev1.SubmitJobRequest{
+		Type:    "bad type!",
+		Payload: []byte(`not json`),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	restored := errors.FromGRPCStatus(status.Convert(err))
+	assert.Equal(t, errors.CodeValidation, restored.Code)
+	assert.Contains(t, restored.Metadata[errors.FieldsKey], "type")
+	assert.Contains(t, restored.Metadata[errors.FieldsKey], "payload")
+
+	_, err = s.client.ListJobs(context.Background(), &taskqueuev1.ListJobsRequest{
+		Statuses: []taskqueuev1.JobStatus{taskqueuev1.JobStatus_JOB_STATUS_UNSPECIFIED},
+		Sort:     "priority",
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestWatchJob_StreamsStatusChanges(t *testing.T) {
+	s := newTestServer(t)
+
+	job, err := s.client.SubmitJob(context.Background(), &taskqueuev1.SubmitJobRequest{
+		Type:    "email_send",
+		Payload: []byte(`{}`),
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := s.client.WatchJob(ctx, &taskqueuev1.WatchJobRequest{Id: job.GetId()})
+	require.NoError(t, err)
+
+	first, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, taskqueuev1.JobStatus_JOB_STATUS_PENDING, first.GetStatus())
+
+	stored, err := s.store.Get(ctx, uuid.MustParse(job.GetId()))
+	require.NoError(t, err)
+	for _, next := range []models.JobStatus{models.JobStatusRunning, models.JobStatusCompleted} {
+		stored.Status = next
+		require.NoError(t, s.store.UpdateStatus(ctx, stored))
+
+		msg, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, statuses[next], msg.GetStatus())
+	}
+
+	// The stream ends once the job is finished
+	_, err = stream.Recv()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestParseTraceParent(t *testing.T) {
+	traceID, ok := parseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.True(t, ok)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+
+	for _, header := range []string{"", "garbage", "00-00000000000000000000000000000000-00f067aa0ba902b7-01"} {
+		_, ok := parseTraceParent(header)
+		assert.False(t, ok, header)
+	}
+}
+
+func TestInterceptors_RecoverPanics(t *testing.T) {
+	log := logger.NewNop()
+	svc := service.New(storage.NewMemoryJobStore(), storage.NewMemoryEventStore(),
+		queue.NewMemoryQueue(queue.Config{}), log)
+	srv := New(svc, log)
+	info := &grpc.UnaryServerInfo{FullMethod: "/taskqueue.v1.JobService/GetJob"}
+
+	_, err := srv.UnaryInterceptor(context.Background(), nil, info,
+		func(context.Context, any) (any, error) { panic("handler bug") })
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	err = srv.StreamInterceptor(nil, &contextStream{ctx: context.Background()},
+		&grpc.StreamServerInfo{FullMethod: "/taskqueue.v1.JobService/WatchJob"},
+		func(any, grpc.ServerStream) error { panic("stream bug") })
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
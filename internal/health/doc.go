@@ -0,0 +1,21 @@
+// Package health answers the liveness and readiness probes of the task
+// queue's processes.
+//
+// A Checker runs named dependency checks, each bounded by its own timeout,
+// and caches the report for a few seconds so frequent probes do not hammer
+// the dependencies. Applications register their own checks next to the
+// built-in ones:
+//
+//	checker := health.New(health.WithCacheTTL(cfg.Server.Health.CacheTTL),
+//	    health.WithTimeout(cfg.Server.Health.CheckTimeout))
+//	checker.Register("postgres", storage.HealthCheck(db))
+//	checker.Register("redis", redisQueue.Ping)
+//	checker.Register("worker", pool.HealthCheck, health.Timeout(time.Second))
+//
+// Register adds checks to Default for code without a checker at hand.
+//
+// Checker.Handler answers /readyz with 200 and the report of every check,
+// or 503 naming the failing ones, and any other path, such as /healthz,
+// with the process's liveness: 200 until Shutdown is called, 503 after.
+// The API serves both with api.WithHealth.
+package health
@@ -0,0 +1,303 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Checker defaults
+const (
+	DefaultTimeout  = 2 * time.Second
+	DefaultCacheTTL = 2 * time.Second
+)
+
+// Check states
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// CheckFunc checks one dependency, failing when it is unhealthy
+type CheckFunc func(ctx context.Context) error
+
+// Result is the outcome of one check
+type Result struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report is the outcome of every check of a Checker. Failing names the
+// checks that are down, in registration order.
+type Report struct {
+	Status    string            `json:"status"`
+	Checks    map[string]Result `json:"checks"`
+	Failing   []string          `json:"failing,omitempty"`
+	CheckedAt time.Time         `json:"checked_at"`
+}
+
+// Ready reports whether every check passed
+func (r Report) Ready() bool {
+	return len(r.Failing) == 0
+}
+
+// Option configures a Checker
+type Option func(*Checker)
+
+// WithTimeout sets the timeout of checks registered without their own,
+// DefaultTimeout by default
+func WithTimeout(d time.Duration) Option {
+	return func(c *Checker) {
+		if d > 0 {
+			c.timeout = d
+		}
+	}
+}
+
+// WithCacheTTL sets how long a report is reused before the checks run
+// again, DefaultCacheTTL by default. Zero runs them on every call.
+func WithCacheTTL(d time.Duration) Option {
+	return func(c *Checker) {
+		c.cacheTTL = d
+	}
+}
+
+// CheckOption configures one registered check
+type CheckOption func(*check)
+
+// Timeout bounds the check at d instead of the checker's timeout
+func Timeout(d time.Duration) CheckOption {
+	return func(c *check) {
+		c.timeout = d
+	}
+}
+
+// check is a registered check
+type check struct {
+	name    string
+	fn      CheckFunc
+	timeout time.Duration
+}
+
+// Checker runs the registered checks for readiness and tracks whether the
+// process is shutting down for liveness
+type Checker struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+	now      func() time.Time
+
+	mu     sync.RWMutex
+	checks []check
+
+	// run serializes check runs, so concurrent probes share one run
+	run      sync.Mutex
+	cached   *Report
+	cachedAt time.Time
+
+	shuttingDown atomic.Bool
+}
+
+// Default is the checker Register adds checks to
+var Default = New()
+
+// Register adds a check named name to Default
+func Register(name string, fn CheckFunc, opts ...CheckOption) {
+	Default.Register(name, fn, opts...)
+}
+
+// New creates a checker without checks
+func New(opts ...Option) *Checker {
+	c := &Checker{
+		timeout:  DefaultTimeout,
+		cacheTTL: DefaultCacheTTL,
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Register adds a check named name, replacing the check already registered
+// under that name. The cached report is discarded.
+func (c *Checker) Register(name string, fn CheckFunc, opts ...CheckOption) {
+	registered := check{name: name, fn: fn}
+	for _, opt := range opts {
+		opt(&registered)
+	}
+
+	c.mu.Lock()
+	replaced := false
+	for i := range c.checks {
+		if c.checks[i].name == name {
+			c.checks[i] = registered
+			replaced = true
+		}
+	}
+
+	if !replaced {
+		c.checks = append(c.checks, registered)
+	}
+
+	c.mu.Unlock()
+
+	c.run.Lock()
+	c.cached = nil
+	c.run.Unlock()
+}
+
+// Shutdown marks the process as shutting down: liveness and readiness fail
+// from then on, so load balancers stop routing to it
+func (c *Checker) Shutdown() {
+	c.shuttingDown.Store(true)
+}
+
+// ShuttingDown reports whether Shutdown was called
+func (c *Checker) ShuttingDown() bool {
+	return c.shuttingDown.Load()
+}
+
+// Check runs every check concurrently, each bounded by its timeout, and
+// returns their report. A report younger than the cache TTL is returned
+// without running the checks again.
+func (c *Checker) Check(ctx context.Context) Report {
+	c.run.Lock()
+	defer c.run.Unlock()
+
+	if c.cached != nil && c.now().Sub(c.cachedAt) < c.cacheTTL {
+		return *c.cached
+	}
+
+	c.mu.RLock()
+	checks := append([]check(nil), c.checks...)
+	c.mu.RUnlock()
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, chk := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.runCheck(ctx, chk)
+		}()
+	}
+
+	wg.Wait()
+
+	report := Report{
+		Status:    StatusUp,
+		Checks:    make(map[string]Result, len(checks)),
+		CheckedAt: c.now().UTC(),
+	}
+
+	for i, chk := range checks {
+		report.Checks[chk.name] = results[i]
+		if results[i].Status == StatusDown {
+			report.Failing = append(report.Failing, chk.name)
+			report.Status = StatusDown
+		}
+	}
+
+	c.cached = &report
+	c.cachedAt = c.now()
+	return report
+}
+
+// runCheck runs chk bounded by its timeout. A check that does not return
+// in time is reported down even if it ignores its context.
+func (c *Checker) runCheck(ctx context.Context, chk check) Result {
+	timeout := chk.timeout
+	if timeout <= 0 {
+		timeout = c.timeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- chk.fn(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := Result{
+		Status:    StatusUp,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// Handler serves the checker's probes as JSON: /readyz answers 200 with
+// the report when every check passes and 503 otherwise, and any other
+// path, such as /healthz, answers 200 until Shutdown is called and 503
+// after
+func (c *Checker) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/readyz" {
+			c.serveLiveness(w)
+			return
+		}
+
+		c.serveReadiness(w, r)
+	})
+}
+
+// serveLiveness answers a liveness probe
+func (c *Checker) serveLiveness(w http.ResponseWriter) {
+	if c.ShuttingDown() {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"status": StatusDown,
+			"reason": "shutting down",
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": StatusUp})
+}
+
+// serveReadiness answers a readiness probe with the checks' report
+func (c *Checker) serveReadiness(w http.ResponseWriter, r *http.Request) {
+	if c.ShuttingDown() {
+		writeJSON(w, http.StatusServiceUnavailable, Report{
+			Status:    StatusDown,
+			Checks:    map[string]Result{},
+			Failing:   []string{"shutdown"},
+			CheckedAt: c.now().UTC(),
+		})
+		return
+	}
+
+	report := c.Check(r.Context())
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+
+	writeJSON(w, status, report)
+}
+
+// writeJSON writes v as a JSON response with status
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,133 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"task-queue/internal/queue"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// probe sends a GET to path and decodes the JSON response into v
+func probe(t *testing.T, c *Checker, path string, v any) int {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	c.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(v))
+	return rec.Code
+}
+
+func up(context.Context) error { return nil }
+
+func TestReadiness_RedisDown(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+
+	q, err := queue.NewRedisQueue(client, queue.Config{}, logger.NewNop())
+	require.NoError(t, err)
+
+	c := New(WithCacheTTL(0))
+	c.Register("postgres", up)
+	c.Register("redis", q.Ping)
+
+	var report Report
+	require.Equal(t, http.StatusOK, probe(t, c, "/readyz", &report))
+	assert.Equal(t, StatusUp, report.Status)
+	assert.Equal(t, StatusUp, report.Checks["redis"].Status)
+
+	server.Close()
+
+	report = Report{}
+	require.Equal(t, http.StatusServiceUnavailable, probe(t, c, "/readyz", &report))
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, []string{"redis"}, report.Failing)
+	assert.Equal(t, StatusDown, report.Checks["redis"].Status)
+	assert.Contains(t, report.Checks["redis"].Error, "redis is unreachable")
+	assert.Equal(t, StatusUp, report.Checks["postgres"].Status)
+
+	var live map[string]string
+	assert.Equal(t, http.StatusOK, probe(t, c, "/healthz", &live),
+		"a down dependency does not fail liveness")
+}
+
+func TestCheck_CachesReport(t *testing.T) {
+	now := time.Now()
+	c := New(WithCacheTTL(5 * time.Second))
+	c.now = func() time.Time { return now }
+
+	var calls atomic.Int32
+	c.Register("counted", func(context.Context) error {
+		calls.Add(1)
+		return nil
+	})
+
+	for range 3 {
+		assert.True(t, c.Check(context.Background()).Ready())
+	}
+
+	assert.EqualValues(t, 1, calls.Load())
+
+	now = now.Add(6 * time.Second)
+	c.Check(context.Background())
+	assert.EqualValues(t, 2, calls.Load(), "an expired report is refreshed")
+
+	c.Register("other", up)
+	report := c.Check(context.Background())
+	assert.EqualValues(t, 3, calls.Load(), "registering discards the cached report")
+	assert.Len(t, report.Checks, 2)
+}
+
+func TestCheck_Timeout(t *testing.T) {
+	c := New(WithCacheTTL(0), WithTimeout(time.Hour))
+
+	block := make(chan struct{})
+	t.Cleanup(func() { close(block) })
+	c.Register("stuck", func(context.Context) error {
+		<-block
+		return nil
+	}, Timeout(20*time.Millisecond))
+	c.Register("failing", func(context.Context) error {
+		return errors.New("disk full")
+	})
+
+	start := time.Now()
+	report := c.Check(context.Background())
+	assert.Less(t, time.Since(start), time.Second, "a check ignoring its context is abandoned")
+
+	assert.Equal(t, []string{"stuck", "failing"}, report.Failing)
+	assert.Contains(t, report.Checks["stuck"].Error, "deadline exceeded")
+	assert.Equal(t, "disk full", report.Checks["failing"].Error)
+}
+
+func TestShutdown_FailsProbes(t *testing.T) {
+	c := New()
+	c.Register("postgres", up)
+
+	var live map[string]string
+	require.Equal(t, http.StatusOK, probe(t, c, "/healthz", &live))
+	assert.Equal(t, StatusUp, live["status"])
+
+	c.Shutdown()
+
+	live = nil
+	assert.Equal(t, http.StatusServiceUnavailable, probe(t, c, "/healthz", &live))
+	assert.Equal(t, "shutting down", live["reason"])
+
+	var report Report
+	assert.Equal(t, http.StatusServiceUnavailable, probe(t, c, "/readyz", &report))
+	assert.Equal(t, []string{"shutdown"}, report.Failing)
+}
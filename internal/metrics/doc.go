@@ -0,0 +1,91 @@
+// Package metrics exposes Prometheus metrics for the task queue system.
+//
+// It holds adapters that turn hooks offered by other packages into
+// Prometheus collectors. The dependency only goes one way: metrics imports
+// the packages it observes, and those packages never import metrics.
+//
+// Counting reported errors by code and component:
+//
+//	if _, err := metrics.InstallErrorObserver(prometheus.DefaultRegisterer); err != nil {
+//	    return err
+//	}
+//
+// Instrumenting every retry.Do call, labeled by its retry.WithName:
+//
+//	if _, err := metrics.InstallRetryMetrics(prometheus.DefaultRegisterer); err != nil {
+//	    return err
+//	}
+//
+// Counting log entries dropped by sampling:
+//
+//	if _, err := metrics.InstallLogDropObserver(prometheus.DefaultRegisterer); err != nil {
+//	    return err
+//	}
+//
+// Counting processed jobs and their durations by type and outcome:
+//
+//	jobMetrics, err := metrics.NewJobMetrics(prometheus.DefaultRegisterer)
+//	if err != nil {
+//	    return err
+//	}
+//	pool.Use(worker.Metrics(jobMetrics))
+//
+// Tracking the worker pool's size under the autoscaler:
+//
+//	scalingMetrics, err := metrics.NewScalingMetrics(prometheus.DefaultRegisterer)
+//	if err != nil {
+//	    return err
+//	}
+//	pool := worker.New(q, cfg.Worker, log, worker.WithScalingMetrics(scalingMetrics))
+//
+// Counting jobs held back by per-type rate limits:
+//
+//	throttleMetrics, err := metrics.NewThrottleMetrics(prometheus.DefaultRegisterer)
+//	if err != nil {
+//	    return err
+//	}
+//	pool := worker.New(q, cfg.Worker, log, worker.WithThrottleMetrics(throttleMetrics))
+//
+// Watching worker health: jobs in flight, queue wait, panics, failed
+// heartbeats, and nacks. Job types without a handler are labeled "unknown":
+//
+//	poolMetrics, err := metrics.NewPoolMetrics(prometheus.DefaultRegisterer)
+//	if err != nil {
+//	    return err
+//	}
+//	pool := worker.New(q, cfg.Worker, log, worker.WithPoolMetrics(poolMetrics))
+//
+// Tracking the lag and settled jobs of each queue a pool consumes:
+//
+//	queueMetrics, err := metrics.NewQueueMetrics(prometheus.DefaultRegisterer)
+//	if err != nil {
+//	    return err
+//	}
+//	queues, err := worker.QueuesFrom(manager, cfg.Worker.Queues)
+//	if err != nil {
+//	    return err
+//	}
+//	mq, err := worker.NewMultiQueue(queues, worker.WithQueueMetrics(queueMetrics))
+//
+// Counting jobs that missed their wait deadline and those escalated for it:
+//
+//	deadlineMetrics, err := metrics.NewDeadlineMetrics(prometheus.DefaultRegisterer)
+//	if err != nil {
+//	    return err
+//	}
+//	pool := worker.New(q, cfg.Worker, log, worker.WithDeadlineMetrics(deadlineMetrics))
+//
+// Counting jobs a Redis queue's reaper reclaims from crashed workers:
+//
+//	reaperMetrics, err := metrics.NewReaperMetrics(prometheus.DefaultRegisterer)
+//	if err != nil {
+//	    return err
+//	}
+//	cfg := c.QueueConfig()
+//	cfg.ReaperMetrics = reaperMetrics
+//
+// Serving everything registered on the configured metrics port, with the
+// pool's /healthz and /readyz checks:
+//
+//	go metrics.Serve(ctx, cfg.Metrics, prometheus.DefaultGatherer, pool.HealthHandler())
+package metrics
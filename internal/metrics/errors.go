@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"task-queue/pkg/errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrorObserver counts errors passed to errors.Report in
+// task_queue_errors_total, labeled by code and component
+type ErrorObserver struct {
+	total *prometheus.CounterVec
+}
+
+// NewErrorObserver creates an ErrorObserver and registers its counter with reg
+func NewErrorObserver(reg prometheus.Registerer) (*ErrorObserver, error) {
+	total := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "task_queue_errors_total",
+		Help: "Number of reported errors by code and component.",
+	}, []string{"code", "component"})
+
+	if err := reg.Register(total); err != nil {
+		return nil, errors.Wrap(err, "failed to register errors counter").
+			WithCode(errors.CodeConfiguration)
+	}
+
+	return &ErrorObserver{total: total}, nil
+}
+
+// InstallErrorObserver creates an ErrorObserver registered with reg and
+// installs it with errors.SetObserver
+func InstallErrorObserver(reg prometheus.Registerer) (*ErrorObserver, error) {
+	o, err := NewErrorObserver(reg)
+	if err != nil {
+		return nil, err
+	}
+
+	errors.SetObserver(o.Observe)
+	return o, nil
+}
+
+// Observe increments the counter for e. Errors without a component annotation
+// are counted with an empty component label.
+func (o *ErrorObserver) Observe(e *errors.Error) {
+	o.total.WithLabelValues(string(e.Code), errors.Component(e)).Inc()
+}
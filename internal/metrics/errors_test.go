@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"task-queue/pkg/errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorObserver_CountsByCodeAndComponent(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	observer, err := InstallErrorObserver(reg)
+	require.NoError(t, err)
+	t.Cleanup(func() { errors.SetObserver(nil) })
+
+	dbErr := errors.New("connection lost").
+		WithCode(errors.CodeDatabase).
+		WithComponent("storage")
+
+	_ = errors.Report(errors.Wrap(dbErr, "failed to save job"))
+	_ = errors.Report(dbErr)
+	_ = errors.Report(errors.Validation("bad payload"))
+	_ = errors.Wrap(dbErr, "created but not reported")
+
+	expected := `
+# HELP task_queue_errors_total Number of reported errors by code and component.
+# TYPE task_queue_errors_total counter
+task_queue_errors_total{code="DATABASE_ERROR",component="storage"} 2
+task_queue_errors_total{code="VALIDATION",component=""} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"task_queue_errors_total"))
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(
+		observer.total.WithLabelValues("DATABASE_ERROR", "storage")))
+}
+
+func TestNewErrorObserver_DuplicateRegistration(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	_, err := NewErrorObserver(reg)
+	require.NoError(t, err)
+
+	_, err = NewErrorObserver(reg)
+	require.Error(t, err)
+	assert.Equal(t, errors.CodeConfiguration, errors.GetCode(err))
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// LogDropObserver counts log entries dropped by sampling in
+// task_queue_log_entries_dropped_total, labeled by level
+type LogDropObserver struct {
+	dropped *prometheus.CounterVec
+}
+
+// NewLogDropObserver creates a LogDropObserver and registers its counter
+// with reg
+func NewLogDropObserver(reg prometheus.Registerer) (*LogDropObserver, error) {
+	dropped := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "task_queue_log_entries_dropped_total",
+		Help: "Number of log entries dropped by sampling by level.",
+	}, []string{"level"})
+
+	if err := reg.Register(dropped); err != nil {
+		return nil, errors.Wrap(err, "failed to register log drop counter").
+			WithCode(errors.CodeConfiguration)
+	}
+
+	return &LogDropObserver{dropped: dropped}, nil
+}
+
+// InstallLogDropObserver creates a LogDropObserver registered with reg and
+// installs it with logger.SetDropObserver
+func InstallLogDropObserver(reg prometheus.Registerer) (*LogDropObserver, error) {
+	o, err := NewLogDropObserver(reg)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.SetDropObserver(o.Observe)
+	return o, nil
+}
+
+// Observe increments the counter for level
+func (o *LogDropObserver) Observe(level string) {
+	o.dropped.WithLabelValues(level).Inc()
+}
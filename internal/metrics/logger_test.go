@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogDropObserver_CountsByLevel(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	_, err := InstallLogDropObserver(reg)
+	require.NoError(t, err)
+	t.Cleanup(func() { logger.SetDropObserver(nil) })
+
+	cfg := logger.DefaultConfig()
+	cfg.OutputPaths = []string{filepath.Join(t.TempDir(), "out.log")}
+	cfg.Sampling = logger.SamplingConfig{Enabled: true, Initial: 1, Tick: time.Minute}
+	log := logger.New(cfg)
+
+	for i := 0; i < 4; i++ {
+		log.Error("storm")
+		log.Warn("drizzle")
+	}
+
+	expected := `
+# HELP task_queue_log_entries_dropped_total Number of log entries dropped by sampling by level.
+# TYPE task_queue_log_entries_dropped_total counter
+task_queue_log_entries_dropped_total{level="error"} 3
+task_queue_log_entries_dropped_total{level="warn"} 3
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"task_queue_log_entries_dropped_total"))
+}
+
+func TestNewLogDropObserver_DuplicateRegistration(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	_, err := NewLogDropObserver(reg)
+	require.NoError(t, err)
+
+	_, err = NewLogDropObserver(reg)
+	require.Error(t, err)
+	assert.Equal(t, errors.CodeConfiguration, errors.GetCode(err))
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"task-queue/internal/queue"
+	"task-queue/pkg/errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ReaperMetrics implements queue.ReaperMetrics with a counter of jobs
+// reclaimed after their visibility timeout expired, labeled by queue and
+// outcome
+type ReaperMetrics struct {
+	reclaimed *prometheus.CounterVec
+}
+
+var _ queue.ReaperMetrics = (*ReaperMetrics)(nil)
+
+// NewReaperMetrics creates a ReaperMetrics and registers its collector with
+// reg. Set it as queue.Config.ReaperMetrics.
+func NewReaperMetrics(reg prometheus.Registerer) (*ReaperMetrics, error) {
+	m := &ReaperMetrics{
+		reclaimed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "task_queue_jobs_reclaimed_total",
+			Help: "Number of jobs in flight reclaimed after their visibility timeout expired.",
+		}, []string{"queue", "outcome"}),
+	}
+
+	if err := reg.Register(m.reclaimed); err != nil {
+		return nil, errors.Wrap(err, "failed to register reaper metrics").
+			WithCode(errors.CodeConfiguration)
+	}
+
+	return m, nil
+}
+
+// ObserveReclaimed counts a reclaimed job
+func (m *ReaperMetrics) ObserveReclaimed(queueName, outcome string) {
+	m.reclaimed.WithLabelValues(queueName, outcome).Inc()
+}
@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"task-queue/internal/queue"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReaperMetrics(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	m, err := NewReaperMetrics(reg)
+	require.NoError(t, err)
+
+	m.ObserveReclaimed("default", queue.ReclaimRequeued)
+	m.ObserveReclaimed("default", queue.ReclaimRequeued)
+	m.ObserveReclaimed("emails", queue.ReclaimDeadLettered)
+
+	expected := `
+# HELP task_queue_jobs_reclaimed_total Number of jobs in flight reclaimed after their visibility timeout expired.
+# TYPE task_queue_jobs_reclaimed_total counter
+task_queue_jobs_reclaimed_total{outcome="dead_lettered",queue="emails"} 1
+task_queue_jobs_reclaimed_total{outcome="requeued",queue="default"} 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected)))
+}
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"time"
+
+	"task-queue/pkg/errors"
+	"task-queue/pkg/retry"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unnamedOperation labels retried operations without a retry.WithName
+const unnamedOperation = "unnamed"
+
+// RetryMetrics implements retry.Metrics with counters of attempts,
+// successes, and exhausted operations and a histogram of retry delays, all
+// labeled by operation
+type RetryMetrics struct {
+	attempts  *prometheus.CounterVec
+	successes *prometheus.CounterVec
+	exhausted *prometheus.CounterVec
+	delays    *prometheus.HistogramVec
+}
+
+var _ retry.Metrics = (*RetryMetrics)(nil)
+
+// NewRetryMetrics creates a RetryMetrics and registers its collectors with
+// reg
+func NewRetryMetrics(reg prometheus.Registerer) (*RetryMetrics, error) {
+	m := &RetryMetrics{
+		attempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "task_queue_retry_attempts_total",
+			Help: "Number of attempts made by retried operations.",
+		}, []string{"operation"}),
+		successes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "task_queue_retry_successes_total",
+			Help: "Number of retried operations that eventually succeeded.",
+		}, []string{"operation"}),
+		exhausted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "task_queue_retry_exhausted_total",
+			Help: "Number of retried operations that ran out of attempts or retry budget.",
+		}, []string{"operation"}),
+		delays: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "task_queue_retry_delay_seconds",
+			Help:    "Delay slept before each retry.",
+			Buckets: prometheus.ExponentialBuckets(0.01, 4, 8),
+		}, []string{"operation"}),
+	}
+
+	collectors := []prometheus.Collector{m.attempts, m.successes, m.exhausted, m.delays}
+	for _, c := range collectors {
+		if err := reg.Register(c); err != nil {
+			return nil, errors.Wrap(err, "failed to register retry metrics").
+				WithCode(errors.CodeConfiguration)
+		}
+	}
+
+	return m, nil
+}
+
+// InstallRetryMetrics creates a RetryMetrics registered with reg and
+// installs it with retry.SetDefaultMetrics
+func InstallRetryMetrics(reg prometheus.Registerer) (*RetryMetrics, error) {
+	m, err := NewRetryMetrics(reg)
+	if err != nil {
+		return nil, err
+	}
+
+	retry.SetDefaultMetrics(m)
+	return m, nil
+}
+
+// IncAttempt counts one attempt of op
+func (m *RetryMetrics) IncAttempt(op string) {
+	m.attempts.WithLabelValues(operationLabel(op)).Inc()
+}
+
+// IncSuccess counts a successful operation
+func (m *RetryMetrics) IncSuccess(op string) {
+	m.successes.WithLabelValues(operationLabel(op)).Inc()
+}
+
+// IncExhausted counts an operation that gave up
+func (m *RetryMetrics) IncExhausted(op string) {
+	m.exhausted.WithLabelValues(operationLabel(op)).Inc()
+}
+
+// ObserveDelay records a retry delay in seconds
+func (m *RetryMetrics) ObserveDelay(op string, d time.Duration) {
+	m.delays.WithLabelValues(operationLabel(op)).Observe(d.Seconds())
+}
+
+// operationLabel returns the label value for op
+func operationLabel(op string) string {
+	if op == "" {
+		return unnamedOperation
+	}
+
+	return op
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"task-queue/pkg/errors"
+	"task-queue/pkg/retry"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryMetrics_CountsByOperation(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	_, err := InstallRetryMetrics(reg)
+	require.NoError(t, err)
+	t.Cleanup(func() { retry.SetDefaultMetrics(nil) })
+
+	backoff := retry.WithBackoffStrategy(retry.NewFixedBackoff(time.Millisecond))
+
+	calls := 0
+	require.NoError(t, retry.Do(func() error {
+		calls++
+		if calls < 2 {
+			return errors.New("temporary")
+		}
+
+		return nil
+	}, retry.WithName("storage.Create"), backoff))
+
+	require.Error(t, retry.Do(func() error { return errors.New("down") },
+		retry.WithMaxAttempts(2), backoff))
+
+	expected := `
+# HELP task_queue_retry_attempts_total Number of attempts made by retried operations.
+# TYPE task_queue_retry_attempts_total counter
+task_queue_retry_attempts_total{operation="storage.Create"} 2
+task_queue_retry_attempts_total{operation="unnamed"} 2
+# HELP task_queue_retry_exhausted_total Number of retried operations that ran out of attempts or retry budget.
+# TYPE task_queue_retry_exhausted_total counter
+task_queue_retry_exhausted_total{operation="unnamed"} 1
+# HELP task_queue_retry_successes_total Number of retried operations that eventually succeeded.
+# TYPE task_queue_retry_successes_total counter
+task_queue_retry_successes_total{operation="storage.Create"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"task_queue_retry_attempts_total",
+		"task_queue_retry_successes_total",
+		"task_queue_retry_exhausted_total",
+	))
+
+	count, err := testutil.GatherAndCount(reg, "task_queue_retry_delay_seconds")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count, "one delay series per operation")
+}
+
+func TestNewRetryMetrics_DuplicateRegistration(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	_, err := NewRetryMetrics(reg)
+	require.NoError(t, err)
+
+	_, err = NewRetryMetrics(reg)
+	require.Error(t, err)
+	assert.Equal(t, errors.CodeConfiguration, errors.GetCode(err))
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/pkg/errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// shutdownTimeout bounds how long Serve waits for in-flight scrapes on
+// shutdown
+const shutdownTimeout = 5 * time.Second
+
+// Serve exposes the metrics gathered by g on cfg.Port at cfg.Path until ctx
+// is canceled, along with health at /healthz and /readyz when it is not
+// nil. It returns nil right away when metrics are disabled.
+func Serve(ctx context.Context, cfg config.MetricsConfig, g prometheus.Gatherer,
+	health http.Handler) error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.Port))
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen for metrics on port %d", cfg.Port).
+			WithCode(errors.CodeConfiguration).
+			WithOp("metrics.Serve")
+	}
+
+	return serve(ctx, ln, cfg.Path, g, health)
+}
+
+// serve answers scrapes of path and health checks on ln until ctx is
+// canceled
+func serve(ctx context.Context, ln net.Listener, path string, g prometheus.Gatherer,
+	health http.Handler) error {
+	mux := http.NewServeMux()
+	mux.Handle(path, promhttp.HandlerFor(g, promhttp.HandlerOpts{}))
+	if health != nil {
+		mux.Handle("/healthz", health)
+		mux.Handle("/readyz", health)
+	}
+
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: shutdownTimeout}
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ln) }()
+
+	select {
+	case err := <-done:
+		return errors.Wrap(err, "metrics server failed").
+			WithCode(errors.CodeNetwork).
+			WithOp("metrics.Serve")
+
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return errors.Wrap(err, "failed to shut down metrics server").
+			WithOp("metrics.Serve")
+	}
+
+	if err := <-done; err != nil && !stderrors.Is(err, http.ErrServerClosed) {
+		return errors.Wrap(err, "metrics server failed").
+			WithCode(errors.CodeNetwork).
+			WithOp("metrics.Serve")
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServe_ExposesRegistry(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	m, err := NewPoolMetrics(reg)
+	require.NoError(t, err)
+	m.ObserveNack("timeout")
+
+	health := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(r.URL.Path))
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- serve(ctx, ln, "/metrics", reg, health) }()
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get("http://" + ln.Addr().String() + path)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, resp.Body.Close())
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	status, body := get("/metrics")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `task_queue_nacks_total{reason_class="timeout"} 1`)
+
+	status, body = get("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, "/readyz", body)
+
+	status, _ = get("/healthz")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
@@ -0,0 +1,288 @@
+package metrics
+
+import (
+	"time"
+
+	"task-queue/internal/worker"
+	"task-queue/pkg/errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// JobMetrics implements worker.JobMetrics with a counter of processed jobs
+// and a histogram of their durations, both labeled by job type and outcome
+type JobMetrics struct {
+	processed *prometheus.CounterVec
+	durations *prometheus.HistogramVec
+}
+
+var _ worker.JobMetrics = (*JobMetrics)(nil)
+
+// NewJobMetrics creates a JobMetrics and registers its collectors with reg.
+// Pass it to worker.Metrics to instrument a pool.
+func NewJobMetrics(reg prometheus.Registerer) (*JobMetrics, error) {
+	m := &JobMetrics{
+		processed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "task_queue_jobs_processed_total",
+			Help: "Number of jobs processed by workers.",
+		}, []string{"type", "outcome"}),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "task_queue_job_duration_seconds",
+			Help:    "Time spent processing a job.",
+			Buckets: prometheus.ExponentialBuckets(0.005, 4, 9),
+		}, []string{"type", "outcome"}),
+	}
+
+	for _, c := range []prometheus.Collector{m.processed, m.durations} {
+		if err := reg.Register(c); err != nil {
+			return nil, errors.Wrap(err, "failed to register job metrics").
+				WithCode(errors.CodeConfiguration)
+		}
+	}
+
+	return m, nil
+}
+
+// ObserveJob counts a processed job and records its duration
+func (m *JobMetrics) ObserveJob(jobType, outcome string, duration time.Duration) {
+	m.processed.WithLabelValues(jobType, outcome).Inc()
+	m.durations.WithLabelValues(jobType, outcome).Observe(duration.Seconds())
+}
+
+// ScalingMetrics implements worker.ScalingMetrics with a gauge of running
+// workers and a counter of scaling decisions labeled by direction
+type ScalingMetrics struct {
+	concurrency prometheus.Gauge
+	decisions   *prometheus.CounterVec
+}
+
+var _ worker.ScalingMetrics = (*ScalingMetrics)(nil)
+
+// NewScalingMetrics creates a ScalingMetrics and registers its collectors
+// with reg. Pass it to worker.WithScalingMetrics.
+func NewScalingMetrics(reg prometheus.Registerer) (*ScalingMetrics, error) {
+	m := &ScalingMetrics{
+		concurrency: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "task_queue_worker_concurrency",
+			Help: "Number of workers running in the pool.",
+		}),
+		decisions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "task_queue_worker_scaling_total",
+			Help: "Number of times the autoscaler resized the pool.",
+		}, []string{"direction"}),
+	}
+
+	for _, c := range []prometheus.Collector{m.concurrency, m.decisions} {
+		if err := reg.Register(c); err != nil {
+			return nil, errors.Wrap(err, "failed to register scaling metrics").
+				WithCode(errors.CodeConfiguration)
+		}
+	}
+
+	return m, nil
+}
+
+// SetConcurrency records the number of running workers
+func (m *ScalingMetrics) SetConcurrency(n int) {
+	m.concurrency.Set(float64(n))
+}
+
+// ObserveScaling counts a scaling decision
+func (m *ScalingMetrics) ObserveScaling(direction string) {
+	m.decisions.WithLabelValues(direction).Inc()
+}
+
+// ThrottleMetrics implements worker.ThrottleMetrics with a counter of
+// throttled jobs labeled by job type and action
+type ThrottleMetrics struct {
+	throttled *prometheus.CounterVec
+}
+
+var _ worker.ThrottleMetrics = (*ThrottleMetrics)(nil)
+
+// NewThrottleMetrics creates a ThrottleMetrics and registers its collector
+// with reg. Pass it to worker.WithThrottleMetrics.
+func NewThrottleMetrics(reg prometheus.Registerer) (*ThrottleMetrics, error) {
+	m := &ThrottleMetrics{
+		throttled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "task_queue_jobs_throttled_total",
+			Help: "Number of jobs held back by their type's rate limit.",
+		}, []string{"type", "action"}),
+	}
+
+	if err := reg.Register(m.throttled); err != nil {
+		return nil, errors.Wrap(err, "failed to register throttle metrics").
+			WithCode(errors.CodeConfiguration)
+	}
+
+	return m, nil
+}
+
+// ObserveThrottle counts a throttled job
+func (m *ThrottleMetrics) ObserveThrottle(jobType, action string) {
+	m.throttled.WithLabelValues(jobType, action).Inc()
+}
+
+// DeadlineMetrics implements worker.DeadlineMetrics with counters of jobs
+// that started after their deadline, labeled by job type and priority, and
+// of waiting jobs escalated for missing it, labeled by job type and the
+// priority they left
+type DeadlineMetrics struct {
+	breaches    *prometheus.CounterVec
+	escalations *prometheus.CounterVec
+}
+
+var _ worker.DeadlineMetrics = (*DeadlineMetrics)(nil)
+
+// NewDeadlineMetrics creates a DeadlineMetrics and registers its
+// collectors with reg. Pass it to worker.WithDeadlineMetrics.
+func NewDeadlineMetrics(reg prometheus.Registerer) (*DeadlineMetrics, error) {
+	m := &DeadlineMetrics{
+		breaches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "task_queue_slo_breaches_total",
+			Help: "Number of jobs that started after their wait deadline.",
+		}, []string{"type", "priority"}),
+		escalations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "task_queue_job_escalations_total",
+			Help: "Number of waiting jobs promoted a priority for missing their wait deadline.",
+		}, []string{"type", "from"}),
+	}
+
+	for _, c := range []prometheus.Collector{m.breaches, m.escalations} {
+		if err := reg.Register(c); err != nil {
+			return nil, errors.Wrap(err, "failed to register deadline metrics").
+				WithCode(errors.CodeConfiguration)
+		}
+	}
+
+	return m, nil
+}
+
+// ObserveDeadlineBreach counts a job that started after its deadline
+func (m *DeadlineMetrics) ObserveDeadlineBreach(jobType, priority string) {
+	m.breaches.WithLabelValues(jobType, priority).Inc()
+}
+
+// ObserveEscalation counts a job promoted from priority from
+func (m *DeadlineMetrics) ObserveEscalation(jobType, from string) {
+	m.escalations.WithLabelValues(jobType, from).Inc()
+}
+
+// QueueMetrics implements worker.QueueMetrics with a gauge of each queue's
+// lag, the time its latest dequeued job had been ready, and a counter of
+// its settled jobs labeled by outcome
+type QueueMetrics struct {
+	lag     *prometheus.GaugeVec
+	settled *prometheus.CounterVec
+}
+
+var _ worker.QueueMetrics = (*QueueMetrics)(nil)
+
+// NewQueueMetrics creates a QueueMetrics and registers its collectors with
+// reg. Pass it to worker.WithQueueMetrics.
+func NewQueueMetrics(reg prometheus.Registerer) (*QueueMetrics, error) {
+	m := &QueueMetrics{
+		lag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "task_queue_queue_lag_seconds",
+			Help: "Time the job last dequeued from a queue had been ready.",
+		}, []string{"queue"}),
+		settled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "task_queue_queue_jobs_processed_total",
+			Help: "Number of jobs dequeued from a queue and settled by workers.",
+		}, []string{"queue", "outcome"}),
+	}
+
+	for _, c := range []prometheus.Collector{m.lag, m.settled} {
+		if err := reg.Register(c); err != nil {
+			return nil, errors.Wrap(err, "failed to register queue metrics").
+				WithCode(errors.CodeConfiguration)
+		}
+	}
+
+	return m, nil
+}
+
+// ObserveQueueLag records the lag of queue
+func (m *QueueMetrics) ObserveQueueLag(queue string, lag time.Duration) {
+	m.lag.WithLabelValues(queue).Set(lag.Seconds())
+}
+
+// ObserveQueueSettled counts a settled job of queue
+func (m *QueueMetrics) ObserveQueueSettled(queue, outcome string) {
+	m.settled.WithLabelValues(queue, outcome).Inc()
+}
+
+// PoolMetrics implements worker.PoolMetrics with a gauge of jobs in flight,
+// a histogram of queue wait times by job type, and counters of handler
+// panics and failed heartbeats by job type and of nacks by reason class
+type PoolMetrics struct {
+	inFlight          prometheus.Gauge
+	wait              *prometheus.HistogramVec
+	panics            *prometheus.CounterVec
+	heartbeatFailures *prometheus.CounterVec
+	nacks             *prometheus.CounterVec
+}
+
+var _ worker.PoolMetrics = (*PoolMetrics)(nil)
+
+// NewPoolMetrics creates a PoolMetrics and registers its collectors with
+// reg. Pass it to worker.WithPoolMetrics.
+func NewPoolMetrics(reg prometheus.Registerer) (*PoolMetrics, error) {
+	m := &PoolMetrics{
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "task_queue_jobs_in_flight",
+			Help: "Number of jobs being handled by workers.",
+		}),
+		wait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "task_queue_job_wait_seconds",
+			Help:    "Time a job waited in the queue between becoming ready and being dequeued.",
+			Buckets: prometheus.ExponentialBuckets(0.01, 4, 9),
+		}, []string{"type"}),
+		panics: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "task_queue_handler_panics_total",
+			Help: "Number of job handlers that panicked.",
+		}, []string{"type"}),
+		heartbeatFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "task_queue_heartbeat_failures_total",
+			Help: "Number of failed job visibility extensions.",
+		}, []string{"type"}),
+		nacks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "task_queue_nacks_total",
+			Help: "Number of jobs negatively acknowledged by workers.",
+		}, []string{"reason_class"}),
+	}
+
+	for _, c := range []prometheus.Collector{m.inFlight, m.wait, m.panics, m.heartbeatFailures, m.nacks} {
+		if err := reg.Register(c); err != nil {
+			return nil, errors.Wrap(err, "failed to register pool metrics").
+				WithCode(errors.CodeConfiguration)
+		}
+	}
+
+	return m, nil
+}
+
+// AddInFlight adds delta to the number of jobs in flight
+func (m *PoolMetrics) AddInFlight(delta int) {
+	m.inFlight.Add(float64(delta))
+}
+
+// ObserveWait records how long a job waited in the queue
+func (m *PoolMetrics) ObserveWait(jobType string, wait time.Duration) {
+	m.wait.WithLabelValues(jobType).Observe(wait.Seconds())
+}
+
+// ObservePanic counts a handler panic
+func (m *PoolMetrics) ObservePanic(jobType string) {
+	m.panics.WithLabelValues(jobType).Inc()
+}
+
+// ObserveHeartbeatFailure counts a failed visibility extension
+func (m *PoolMetrics) ObserveHeartbeatFailure(jobType string) {
+	m.heartbeatFailures.WithLabelValues(jobType).Inc()
+}
+
+// ObserveNack counts a nack
+func (m *PoolMetrics) ObserveNack(reasonClass string) {
+	m.nacks.WithLabelValues(reasonClass).Inc()
+}
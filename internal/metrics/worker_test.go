@@ -0,0 +1,225 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/internal/worker"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobMetrics_CountsByTypeAndOutcome(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	m, err := NewJobMetrics(reg)
+	require.NoError(t, err)
+
+	h := worker.Metrics(m)(func(_ context.Context, job *models.Job) (json.RawMessage, error) {
+		if job.Type == "report.build" {
+			return nil, errors.New("no data")
+		}
+
+		return nil, nil
+	})
+
+	for _, jobType := range []string{"email.send", "email.send", "report.build"} {
+		_, _ = h(context.Background(), models.NewJob(jobType, nil, models.JobPriorityNormal))
+	}
+
+	expected := `
+# HELP task_queue_jobs_processed_total Number of jobs processed by workers.
+# TYPE task_queue_jobs_processed_total counter
+task_queue_jobs_processed_total{outcome="failure",type="report.build"} 1
+task_queue_jobs_processed_total{outcome="success",type="email.send"} 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"task_queue_jobs_processed_total"))
+
+	count, err := testutil.GatherAndCount(reg, "task_queue_job_duration_seconds")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestScalingMetrics(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	m, err := NewScalingMetrics(reg)
+	require.NoError(t, err)
+
+	m.SetConcurrency(2)
+	m.ObserveScaling(worker.ScaleUp)
+	m.SetConcurrency(8)
+	m.ObserveScaling(worker.ScaleUp)
+	m.ObserveScaling(worker.ScaleDown)
+	m.SetConcurrency(4)
+
+	expected := `
+# HELP task_queue_worker_concurrency Number of workers running in the pool.
+# TYPE task_queue_worker_concurrency gauge
+task_queue_worker_concurrency 4
+# HELP task_queue_worker_scaling_total Number of times the autoscaler resized the pool.
+# TYPE task_queue_worker_scaling_total counter
+task_queue_worker_scaling_total{direction="down"} 1
+task_queue_worker_scaling_total{direction="up"} 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected)))
+}
+
+func TestThrottleMetrics(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	m, err := NewThrottleMetrics(reg)
+	require.NoError(t, err)
+
+	m.ObserveThrottle("sms.send", worker.ThrottleWaited)
+	m.ObserveThrottle("sms.send", worker.ThrottleWaited)
+	m.ObserveThrottle("sms.send", worker.ThrottleRequeued)
+
+	expected := `
+# HELP task_queue_jobs_throttled_total Number of jobs held back by their type's rate limit.
+# TYPE task_queue_jobs_throttled_total counter
+task_queue_jobs_throttled_total{action="requeued",type="sms.send"} 1
+task_queue_jobs_throttled_total{action="waited",type="sms.send"} 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected)))
+}
+
+func TestDeadlineMetrics(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	m, err := NewDeadlineMetrics(reg)
+	require.NoError(t, err)
+
+	m.ObserveDeadlineBreach("payment.capture", "critical")
+	m.ObserveEscalation("report.build", "low")
+	m.ObserveEscalation("report.build", "low")
+
+	expected := `
+# HELP task_queue_job_escalations_total Number of waiting jobs promoted a priority for missing their wait deadline.
+# TYPE task_queue_job_escalations_total counter
+task_queue_job_escalations_total{from="low",type="report.build"} 2
+# HELP task_queue_slo_breaches_total Number of jobs that started after their wait deadline.
+# TYPE task_queue_slo_breaches_total counter
+task_queue_slo_breaches_total{priority="critical",type="payment.capture"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected)))
+}
+
+func TestQueueMetrics(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	m, err := NewQueueMetrics(reg)
+	require.NoError(t, err)
+
+	m.ObserveQueueLag("emails", 1500*time.Millisecond)
+	m.ObserveQueueSettled("emails", worker.QueueAcked)
+	m.ObserveQueueSettled("emails", worker.QueueAcked)
+	m.ObserveQueueSettled("default", worker.QueueNacked)
+
+	expected := `
+# HELP task_queue_queue_jobs_processed_total Number of jobs dequeued from a queue and settled by workers.
+# TYPE task_queue_queue_jobs_processed_total counter
+task_queue_queue_jobs_processed_total{outcome="acked",queue="emails"} 2
+task_queue_queue_jobs_processed_total{outcome="nacked",queue="default"} 1
+# HELP task_queue_queue_lag_seconds Time the job last dequeued from a queue had been ready.
+# TYPE task_queue_queue_lag_seconds gauge
+task_queue_queue_lag_seconds{queue="emails"} 1.5
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected)))
+}
+
+func TestPoolMetrics_ObservesDispatch(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	jobMetrics, err := NewJobMetrics(reg)
+	require.NoError(t, err)
+	poolMetrics, err := NewPoolMetrics(reg)
+	require.NoError(t, err)
+
+	q := queue.NewMemoryQueue(queue.Config{})
+	p := worker.New(q, config.WorkerConfig{Concurrency: 2}, logger.NewNop(),
+		worker.WithPoolMetrics(poolMetrics))
+	p.Use(worker.Metrics(jobMetrics), worker.Recover())
+
+	p.Register("email.send", func(context.Context, *models.Job) (json.RawMessage, error) {
+		return nil, nil
+	})
+	p.Register("report.build", func(context.Context, *models.Job) (json.RawMessage, error) {
+		return nil, errors.New("no data")
+	})
+	p.Register("image.resize", func(context.Context, *models.Job) (json.RawMessage, error) {
+		panic("corrupt image")
+	})
+
+	ctx := context.Background()
+	for _, jobType := range []string{"email.send", "email.send", "report.build", "image.resize", "f3a1c2"} {
+		require.NoError(t, q.Enqueue(ctx, models.NewJob(jobType, nil, models.JobPriorityNormal)))
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- p.Run(runCtx) }()
+
+	// Acked jobs leave the queue and nacked ones wait out their backoff
+	require.Eventually(t, func() bool {
+		stats, err := q.Stats(ctx)
+		require.NoError(t, err)
+		return stats.Processing == 0 && stats.Delayed == 3
+	}, 2*time.Second, 5*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+
+	expected := `
+# HELP task_queue_handler_panics_total Number of job handlers that panicked.
+# TYPE task_queue_handler_panics_total counter
+task_queue_handler_panics_total{type="image.resize"} 1
+# HELP task_queue_jobs_in_flight Number of jobs being handled by workers.
+# TYPE task_queue_jobs_in_flight gauge
+task_queue_jobs_in_flight 0
+# HELP task_queue_jobs_processed_total Number of jobs processed by workers.
+# TYPE task_queue_jobs_processed_total counter
+task_queue_jobs_processed_total{outcome="failure",type="report.build"} 1
+task_queue_jobs_processed_total{outcome="panic",type="image.resize"} 1
+task_queue_jobs_processed_total{outcome="success",type="email.send"} 2
+# HELP task_queue_nacks_total Number of jobs negatively acknowledged by workers.
+# TYPE task_queue_nacks_total counter
+task_queue_nacks_total{reason_class="error"} 1
+task_queue_nacks_total{reason_class="panic"} 1
+task_queue_nacks_total{reason_class="unknown_type"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"task_queue_handler_panics_total",
+		"task_queue_jobs_in_flight",
+		"task_queue_jobs_processed_total",
+		"task_queue_nacks_total",
+	))
+
+	// Wait times are labeled by registered type only
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	waits := make(map[string]uint64)
+	for _, family := range families {
+		if family.GetName() != "task_queue_job_wait_seconds" {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			waits[metric.GetLabel()[0].GetValue()] = metric.GetHistogram().GetSampleCount()
+		}
+	}
+
+	assert.Equal(t, map[string]uint64{
+		"email.send":   2,
+		"report.build": 1,
+		"image.resize": 1,
+		"unknown":      1,
+	}, waits)
+}
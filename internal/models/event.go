@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobEvent is an entry in a job's audit trail
+type JobEvent struct {
+	ID        uuid.UUID      `json:"id" db:"id"`
+	JobID     uuid.UUID      `json:"job_id" db:"job_id"`
+	EventType string         `json:"event_type" db:"event_type"`
+	EventData map[string]any `json:"event_data,omitempty" db:"event_data"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+	CreatedBy *string        `json:"created_by,omitempty" db:"created_by"`
+}
+
+// NewJobEvent creates an event of eventType for jobID
+func NewJobEvent(jobID uuid.UUID, eventType string, data map[string]any) *JobEvent {
+	return &JobEvent{
+		ID:        uuid.New(),
+		JobID:     jobID,
+		EventType: eventType,
+		EventData: data,
+		CreatedAt: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobSortField is the column jobs are listed by
+type JobSortField string
+
+const (
+	JobSortCreatedAt JobSortField = "created_at"
+	JobSortUpdatedAt JobSortField = "updated_at"
+)
+
+// JobCursor is the position after which a listing continues: the sort
+// value and ID of the last job already returned
+type JobCursor struct {
+	At time.Time `json:"at"`
+	ID uuid.UUID `json:"id"`
+}
+
+// JobFilter selects and orders the jobs returned by a listing. Empty
+// fields match every job; set fields must all match.
+type JobFilter struct {
+	Statuses   []JobStatus
+	Types      []string
+	Priorities []JobPriority
+
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+
+	// Tags must all appear in the job's "tags" metadata list
+	Tags []string
+
+	// Metadata maps metadata keys to the values they must hold
+	Metadata map[string]string
+
+	// Search matches a case-insensitive substring of the job's ID, type, or
+	// error
+	Search string
+
+	// SortBy defaults to JobSortCreatedAt. Jobs are listed newest first
+	// unless Ascending is set, with ties broken by ID.
+	SortBy    JobSortField
+	Ascending bool
+
+	// After continues a previous listing with the same filter
+	After *JobCursor
+	Limit int
+}
+
+// JobPage is one page of a job listing. Next is set when more jobs match,
+// and TotalEstimate counts the matching jobs across all pages, up to a
+// cap chosen by the store.
+type JobPage struct {
+	Items         []*Job
+	Next          *JobCursor
+	TotalEstimate int64
+}
+
+// SortKey returns the value of field on j that listings sort by
+func (j *Job) SortKey(field JobSortField) time.Time {
+	if field == JobSortUpdatedAt {
+		return j.UpdatedAt
+	}
+
+	return j.CreatedAt
+}
+
+// CursorFor returns the cursor that continues a listing sorted by field
+// after j
+func (j *Job) CursorFor(field JobSortField) *JobCursor {
+	return &JobCursor{At: j.SortKey(field), ID: j.ID}
+}
@@ -2,6 +2,10 @@ package models
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,8 +21,19 @@ const (
 	JobStatusFailed    JobStatus = "failed"
 	JobStatusRetrying  JobStatus = "retrying"
 	JobStatusDead      JobStatus = "dead"
+	JobStatusCancelled JobStatus = "cancelled"
 )
 
+// Terminal reports whether status is final: the job will not run again
+func (s JobStatus) Terminal() bool {
+	switch s {
+	case JobStatusCompleted, JobStatusFailed, JobStatusDead, JobStatusCancelled:
+		return true
+	}
+
+	return false
+}
+
 // JobPriority represents the priority level of a job
 type JobPriority int
 
@@ -29,9 +44,58 @@ const (
 	JobPriorityCritical JobPriority = 3
 )
 
+// jobPriorityNames maps priority names to levels
+var jobPriorityNames = map[string]JobPriority{
+	"low":      JobPriorityLow,
+	"normal":   JobPriorityNormal,
+	"high":     JobPriorityHigh,
+	"critical": JobPriorityCritical,
+}
+
+// ParseJobPriority returns the priority named low, normal, high, or
+// critical, in any case
+func ParseJobPriority(name string) (JobPriority, bool) {
+	priority, ok := jobPriorityNames[strings.ToLower(name)]
+	return priority, ok
+}
+
+// Name returns the name of the priority, or its number when it has none
+func (p JobPriority) Name() string {
+	for name, priority := range jobPriorityNames {
+		if priority == p {
+			return name
+		}
+	}
+
+	return strconv.Itoa(int(p))
+}
+
+// UnmarshalJSON accepts a priority level or its name, so clients can send
+// either. Priorities are always encoded as levels.
+func (p *JobPriority) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		var level int
+		if err := json.Unmarshal(data, &level); err != nil {
+			return fmt.Errorf("priority must be a level or name, got %s", data)
+		}
+
+		*p = JobPriority(level)
+		return nil
+	}
+
+	priority, ok := ParseJobPriority(name)
+	if !ok {
+		return fmt.Errorf("unknown priority %q", name)
+	}
+
+	*p = priority
+	return nil
+}
+
 // Job represents a task in the queue system
 type Job struct {
-	ID          uuid.UUID       `json:"id" db:"id"`
+	ID          uuid.UUID       `json:"id" db:"id" validate:"nonnil_uuid"`
 	Type        string          `json:"type" db:"type" validate:"required,min=1,max=100"`
 	Payload     json.RawMessage `json:"payload" db:"payload"`
 	Status      JobStatus       `json:"status" db:"status"`
@@ -47,6 +111,10 @@ type Job struct {
 	Result      json.RawMessage `json:"result,omitempty" db:"result"`
 	WorkerID    *string         `json:"worker_id,omitempty" db:"worker_id"`
 	Metadata    map[string]any  `json:"metadata,omitempty" db:"metadata"`
+
+	// Timeout overrides the worker's process timeout for this job when
+	// positive. It travels with the queued job and is not stored.
+	Timeout time.Duration `json:"timeout,omitempty" db:"-"`
 }
 
 // NewJob creates a new job with default values
@@ -66,16 +134,66 @@ func NewJob(jobType string, payload json.RawMessage, priority JobPriority) *Job
 	}
 }
 
+// PayloadKeys returns the sorted top-level keys of a JSON object payload,
+// which describe its shape without exposing values. It returns nil for
+// other payloads.
+func (j *Job) PayloadKeys() []string {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(j.Payload, &fields); err != nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
 // JobRequest represents a request to create a new job
 type JobRequest struct {
 	Type        string          `json:"type" validate:"required,min=1,max=100"`
-	Payload     json.RawMessage `json:"payload" validate:"required"`
+	Payload     json.RawMessage `json:"payload" validate:"required,json"`
 	Priority    JobPriority     `json:"priority,omitempty"`
 	MaxRetries  *int            `json:"max_retries,omitempty" validate:"omitempty,min=0,max=10"`
-	ScheduledAt *time.Time      `json:"scheduled_at,omitempty"`
+	ScheduledAt *time.Time      `json:"scheduled_at,omitempty" validate:"omitempty,future=1m"`
 	Metadata    map[string]any  `json:"metadata,omitempty"`
 }
 
+// NewJobFromRequest creates a pending job from req, keeping the defaults of
+// NewJob for the fields req leaves unset
+func NewJobFromRequest(req *JobRequest) *Job {
+	job := NewJob(req.Type, req.Payload, req.Priority)
+	if req.MaxRetries != nil {
+		job.MaxRetries = *req.MaxRetries
+	}
+
+	if req.ScheduledAt != nil {
+		scheduledAt := req.ScheduledAt.UTC()
+		job.ScheduledAt = &scheduledAt
+	}
+
+	for key, value := range req.Metadata {
+		job.Metadata[key] = value
+	}
+
+	return job
+}
+
+// ReplayMetadataKey is the metadata key of a replayed job holding the ID of
+// the job it replays
+const ReplayMetadataKey = "replay_of"
+
+// ReplayOverrides changes a replayed job. Nil fields keep the values of the
+// original job, and Metadata is added to its metadata.
+type ReplayOverrides struct {
+	Priority    *JobPriority   `json:"priority,omitempty"`
+	ScheduledAt *time.Time     `json:"scheduled_at,omitempty"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+}
+
 // JobResult represents the result of a completed job
 type JobResult struct {
 	JobID       uuid.UUID       `json:"job_id"`
@@ -0,0 +1,49 @@
+package queue
+
+import (
+	"context"
+
+	"task-queue/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Admin is implemented by queues that support operator controls: inspecting
+// and replaying dead-lettered jobs, pausing delivery, and draining waiting
+// jobs
+type Admin interface {
+	// DeadLetters returns up to limit dead-lettered jobs starting at offset,
+	// oldest first, and the number of dead-lettered jobs
+	DeadLetters(ctx context.Context, offset, limit int) ([]*models.Job, int64, error)
+
+	// RequeueDeadLetter moves a dead-lettered job back to the queue with its
+	// retry count and error cleared
+	RequeueDeadLetter(ctx context.Context, jobID uuid.UUID) (*models.Job, error)
+
+	// RequeueDeadLetters requeues every dead-lettered job like
+	// RequeueDeadLetter, returning how many were requeued
+	RequeueDeadLetters(ctx context.Context) (int64, error)
+
+	// PurgeDeadLetters deletes every dead-lettered job, returning how many
+	// were deleted
+	PurgeDeadLetters(ctx context.Context) (int64, error)
+
+	// Pause stops delivering jobs: Dequeue returns no job until Resume.
+	// Enqueueing and settling in-flight jobs still work.
+	Pause(ctx context.Context) error
+
+	// Resume undoes Pause
+	Resume(ctx context.Context) error
+
+	// Drain deletes every ready and delayed job, leaving in-flight and
+	// dead-lettered jobs, and returns how many were deleted
+	Drain(ctx context.Context) (int64, error)
+}
+
+// revive prepares a dead-lettered job for another run
+func revive(job *models.Job) {
+	job.Status = models.JobStatusPending
+	job.RetryCount = 0
+	job.Error = nil
+	job.ScheduledAt = nil
+}
@@ -0,0 +1,385 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryQueue_Conformance(t *testing.T) {
+	newQueue := func(t *testing.T) *MemoryQueue {
+		q := NewMemoryQueue(Config{VisibilityTimeout: time.Minute})
+		t.Cleanup(func() { _ = q.Close() })
+		return q
+	}
+
+	testConformance(t, func(t *testing.T) Queue {
+		return newQueue(t)
+	}, func(t *testing.T) (Queue, Queue, func()) {
+		q := newQueue(t)
+		return q, q.Peer(), func() {
+			q.mu.Lock()
+			defer q.mu.Unlock()
+
+			later := q.now().Add(2 * time.Minute)
+			q.now = func() time.Time { return later }
+		}
+	})
+}
+
+func TestRedisQueue_Conformance(t *testing.T) {
+	testConformance(t, func(t *testing.T) Queue {
+		q, _ := newRedisQueue(t)
+		return q
+	}, func(t *testing.T) (Queue, Queue, func()) {
+		q, server := newRedisQueue(t)
+		peer, err := NewRedisQueue(q.client, q.config, logger.NewNop())
+		require.NoError(t, err)
+
+		return q, peer, func() {
+			server.SetTime(time.Now().Add(2 * time.Minute))
+			_, err := q.Reap(context.Background())
+			require.NoError(t, err)
+		}
+	})
+}
+
+// testConformance checks the behaviour every Queue shares. newQueue returns
+// an empty queue whose visibility timeout outlasts each test. newExpiring
+// returns an empty queue, a peer consuming it as a second process would,
+// and a function running out the visibility timeout of the jobs in flight.
+func testConformance(t *testing.T, newQueue func(t *testing.T) Queue,
+	newExpiring func(t *testing.T) (q, peer Queue, expire func())) {
+	// conformanceJob returns a job of priority allowed three attempts
+	conformanceJob := func(priority models.JobPriority) *models.Job {
+		job := models.NewJob("email_send", nil, priority)
+		job.MaxRetries = 3
+		return job
+	}
+
+	// stats returns the statistics of q
+	stats := func(t *testing.T, q Queue) *QueueStats {
+		t.Helper()
+
+		s, err := q.Stats(context.Background())
+		require.NoError(t, err)
+		return s
+	}
+
+	// dequeue dequeues the next job of q, which must be want
+	dequeue := func(t *testing.T, q Queue, want *models.Job) *models.Job {
+		t.Helper()
+
+		got, err := q.Dequeue(context.Background())
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		require.Equal(t, want.ID, got.ID)
+		return got
+	}
+
+	t.Run("PriorityOrder", func(t *testing.T) {
+		ctx := context.Background()
+		q := newQueue(t)
+		low := conformanceJob(models.JobPriorityLow)
+		first := conformanceJob(models.JobPriorityNormal)
+		critical := conformanceJob(models.JobPriorityCritical)
+		second := conformanceJob(models.JobPriorityNormal)
+		require.NoError(t, q.EnqueueBatch(ctx, []*models.Job{low, first, critical, second}))
+
+		for _, want := range []*models.Job{critical, first, second, low} {
+			dequeue(t, q, want)
+		}
+
+		empty, err := q.Dequeue(ctx)
+		require.NoError(t, err)
+		assert.Nil(t, empty)
+	})
+
+	t.Run("DequeueMinPriority", func(t *testing.T) {
+		ctx := context.Background()
+		q := newQueue(t)
+		low := conformanceJob(models.JobPriorityLow)
+		high := conformanceJob(models.JobPriorityHigh)
+		require.NoError(t, q.EnqueueBatch(ctx, []*models.Job{low, high}))
+
+		got, err := q.DequeueMinPriority(ctx, models.JobPriorityHigh)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, high.ID, got.ID)
+
+		got, err = q.DequeueMinPriority(ctx, models.JobPriorityHigh)
+		require.NoError(t, err)
+		assert.Nil(t, got, "low priority job is left for other workers")
+
+		dequeue(t, q, low)
+	})
+
+	t.Run("DequeueBatch", func(t *testing.T) {
+		ctx := context.Background()
+		q := newQueue(t)
+		for range 3 {
+			require.NoError(t, q.Enqueue(ctx, conformanceJob(models.JobPriorityNormal)))
+		}
+
+		jobs, err := q.DequeueBatch(ctx, 2)
+		require.NoError(t, err)
+		assert.Len(t, jobs, 2)
+
+		jobs, err = q.DequeueBatch(ctx, 2)
+		require.NoError(t, err)
+		assert.Len(t, jobs, 1)
+		assert.Equal(t, int64(3), stats(t, q).Processing)
+	})
+
+	t.Run("RejectsNilJobs", func(t *testing.T) {
+		ctx := context.Background()
+		q := newQueue(t)
+
+		err := q.Enqueue(ctx, nil)
+		assert.True(t, errors.HasCode(err, errors.CodeValidation))
+
+		err = q.EnqueueBatch(ctx, []*models.Job{conformanceJob(models.JobPriorityNormal), nil})
+		assert.True(t, errors.HasCode(err, errors.CodeValidation))
+
+		size, err := q.Size(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, size, "an invalid batch enqueues nothing")
+	})
+
+	t.Run("AckSettlesOnce", func(t *testing.T) {
+		ctx := context.Background()
+		q := newQueue(t)
+		job := conformanceJob(models.JobPriorityNormal)
+		require.NoError(t, q.Enqueue(ctx, job))
+		dequeue(t, q, job)
+		assert.Equal(t, int64(1), stats(t, q).Processing)
+
+		require.NoError(t, q.Ack(ctx, job.ID))
+		assert.Zero(t, stats(t, q).Processing)
+		assert.True(t, errors.HasCode(q.Ack(ctx, job.ID), errors.CodeNotFound))
+		assert.True(t, errors.HasCode(q.Nack(ctx, job.ID, "late"), errors.CodeNotFound))
+	})
+
+	t.Run("NackRetriesThenDeadLetters", func(t *testing.T) {
+		ctx := context.Background()
+		q := newQueue(t)
+		job := conformanceJob(models.JobPriorityNormal)
+		job.MaxRetries = 2
+		require.NoError(t, q.Enqueue(ctx, job))
+
+		dequeue(t, q, job)
+		require.NoError(t, q.NackWithDelay(ctx, job.ID, "boom", 0))
+		got := dequeue(t, q, job)
+		assert.Equal(t, 1, got.RetryCount)
+		require.NotNil(t, got.Error)
+		assert.Equal(t, "boom", *got.Error)
+
+		require.NoError(t, q.Nack(ctx, job.ID, "boom again"))
+		s := stats(t, q)
+		assert.Zero(t, s.Processing)
+		assert.Zero(t, s.Size)
+		assert.Equal(t, int64(1), s.DeadLetter)
+		assert.True(t, errors.HasCode(q.Ack(ctx, job.ID), errors.CodeNotFound))
+	})
+
+	t.Run("NackErrorDeadLettersPermanentFailures", func(t *testing.T) {
+		ctx := context.Background()
+		q := newQueue(t)
+		job := conformanceJob(models.JobPriorityNormal)
+		require.NoError(t, q.Enqueue(ctx, job))
+		dequeue(t, q, job)
+
+		require.NoError(t, q.NackError(ctx, job.ID, errors.Validation("bad payload")))
+		s := stats(t, q)
+		assert.Zero(t, s.Size)
+		assert.Equal(t, int64(1), s.DeadLetter)
+	})
+
+	t.Run("NackWithDelayWaits", func(t *testing.T) {
+		ctx := context.Background()
+		q := newQueue(t)
+		job := conformanceJob(models.JobPriorityNormal)
+		require.NoError(t, q.Enqueue(ctx, job))
+		dequeue(t, q, job)
+
+		require.NoError(t, q.NackWithDelay(ctx, job.ID, "rate limited", time.Hour))
+		got, err := q.Dequeue(ctx)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+		assert.Equal(t, int64(1), stats(t, q).Delayed)
+	})
+
+	t.Run("ScheduledJobsWait", func(t *testing.T) {
+		ctx := context.Background()
+		q := newQueue(t)
+		later := conformanceJob(models.JobPriorityCritical)
+		later.ScheduledAt = ptr(time.Now().Add(time.Hour))
+		now := conformanceJob(models.JobPriorityLow)
+		require.NoError(t, q.EnqueueBatch(ctx, []*models.Job{later, now}))
+
+		dequeue(t, q, now)
+		got, err := q.Dequeue(ctx)
+		require.NoError(t, err)
+		assert.Nil(t, got, "not due yet")
+
+		s := stats(t, q)
+		assert.Equal(t, int64(1), s.Size)
+		assert.Equal(t, int64(1), s.Delayed)
+	})
+
+	t.Run("ReleaseIsNotARetry", func(t *testing.T) {
+		ctx := context.Background()
+		q := newQueue(t)
+		job := conformanceJob(models.JobPriorityNormal)
+		require.NoError(t, q.Enqueue(ctx, job))
+		dequeue(t, q, job)
+
+		require.NoError(t, q.Release(ctx, job.ID, 0))
+		got := dequeue(t, q, job)
+		assert.Zero(t, got.RetryCount)
+
+		require.NoError(t, q.Release(ctx, job.ID, time.Hour))
+		assert.Equal(t, int64(1), stats(t, q).Delayed)
+		assert.True(t, errors.HasCode(q.Release(ctx, job.ID, 0), errors.CodeNotFound))
+	})
+
+	t.Run("ExtendNeedsJobInFlight", func(t *testing.T) {
+		ctx := context.Background()
+		q := newQueue(t)
+		job := conformanceJob(models.JobPriorityNormal)
+		require.NoError(t, q.Enqueue(ctx, job))
+		dequeue(t, q, job)
+
+		require.NoError(t, q.Extend(ctx, job.ID, time.Hour))
+		require.NoError(t, q.Ack(ctx, job.ID))
+		assert.True(t, errors.HasCode(q.Extend(ctx, job.ID, time.Hour), errors.CodeNotFound))
+	})
+
+	t.Run("ExpiredJobCountsAnAttempt", func(t *testing.T) {
+		ctx := context.Background()
+		q, peer, expire := newExpiring(t)
+		job := conformanceJob(models.JobPriorityNormal)
+		require.NoError(t, q.Enqueue(ctx, job))
+		dequeue(t, q, job)
+
+		expire()
+		redelivered := dequeue(t, peer, job)
+		assert.Equal(t, 1, redelivered.RetryCount)
+		require.NotNil(t, redelivered.Error)
+		assert.Equal(t, reapReason, *redelivered.Error)
+
+		// The worker that lost its lease settles nothing of the redelivery
+		for name, settle := range map[string]func() error{
+			"Extend":  func() error { return q.Extend(ctx, job.ID, time.Hour) },
+			"Ack":     func() error { return q.Ack(ctx, job.ID) },
+			"Nack":    func() error { return q.Nack(ctx, job.ID, "stale") },
+			"Release": func() error { return q.Release(ctx, job.ID, 0) },
+		} {
+			assert.True(t, errors.HasCode(settle(), errors.CodeNotFound), "%s of the lost lease", name)
+		}
+
+		require.NoError(t, peer.Extend(ctx, job.ID, time.Hour))
+		require.NoError(t, peer.Ack(ctx, job.ID))
+		assert.Zero(t, stats(t, q).Processing)
+	})
+
+	t.Run("ExpiredJobDeadLettersOutOfRetries", func(t *testing.T) {
+		ctx := context.Background()
+		q, _, expire := newExpiring(t)
+		job := conformanceJob(models.JobPriorityNormal)
+		job.MaxRetries = 1
+		require.NoError(t, q.Enqueue(ctx, job))
+		dequeue(t, q, job)
+
+		expire()
+		s := stats(t, q)
+		assert.Zero(t, s.Processing)
+		assert.Zero(t, s.Size)
+		assert.Equal(t, int64(1), s.DeadLetter)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		ctx := context.Background()
+		q := newQueue(t)
+		inFlight := conformanceJob(models.JobPriorityNormal)
+		waiting := conformanceJob(models.JobPriorityNormal)
+		delayed := conformanceJob(models.JobPriorityNormal)
+		delayed.ScheduledAt = ptr(time.Now().Add(time.Hour))
+		require.NoError(t, q.Enqueue(ctx, inFlight))
+		dequeue(t, q, inFlight)
+		require.NoError(t, q.EnqueueBatch(ctx, []*models.Job{waiting, delayed}))
+
+		for _, job := range []*models.Job{inFlight, waiting, delayed} {
+			require.NoError(t, q.Delete(ctx, job.ID))
+		}
+
+		s := stats(t, q)
+		assert.Zero(t, s.Processing)
+		assert.Zero(t, s.Size)
+		assert.True(t, errors.HasCode(q.Delete(ctx, waiting.ID), errors.CodeNotFound))
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		ctx := context.Background()
+		q := newQueue(t)
+		inFlight := conformanceJob(models.JobPriorityNormal)
+		delayed := conformanceJob(models.JobPriorityNormal)
+		delayed.ScheduledAt = ptr(time.Now().Add(time.Hour))
+		require.NoError(t, q.Enqueue(ctx, inFlight))
+		dequeue(t, q, inFlight)
+		require.NoError(t, q.EnqueueBatch(ctx, []*models.Job{delayed,
+			conformanceJob(models.JobPriorityHigh)}))
+
+		require.NoError(t, q.Clear(ctx))
+		s := stats(t, q)
+		assert.Zero(t, s.Size)
+		assert.Zero(t, s.Delayed)
+		assert.Zero(t, s.Processing)
+	})
+
+	t.Run("ConcurrentDequeuesDeliverOnce", func(t *testing.T) {
+		ctx := context.Background()
+		q := newQueue(t)
+
+		const jobs = 200
+		for range jobs {
+			require.NoError(t, q.Enqueue(ctx, conformanceJob(models.JobPriorityNormal)))
+		}
+
+		var mu sync.Mutex
+		delivered := make(map[uuid.UUID]int, jobs)
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					job, err := q.Dequeue(ctx)
+					if !assert.NoError(t, err) || job == nil {
+						return
+					}
+
+					mu.Lock()
+					delivered[job.ID]++
+					mu.Unlock()
+					assert.NoError(t, q.Ack(ctx, job.ID))
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Len(t, delivered, jobs)
+		for id, n := range delivered {
+			assert.Equal(t, 1, n, "job %s delivered %d times", id, n)
+		}
+	})
+}
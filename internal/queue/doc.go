@@ -22,4 +22,57 @@
 //
 //	// Acknowledge successful processing
 //	err = q.Ack(ctx, job.ID)
+//
+// RedisQueue keeps the jobs being processed in a hash keyed by job ID, and
+// dequeues and settles them with Lua scripts: a job moves in one atomic
+// step, so an Ack racing a Nack for the same job settles it once, and
+// settling costs the same however many jobs are in flight. A job is only
+// settled as the queue handed it out, so a worker that lost its lease
+// cannot settle the job once it was delivered again.
+//
+// Every key of a RedisQueue is named queue:{name}:..., sharing the hash tag
+// of the queue's name, so the scripts run on Redis Cluster. Versions before
+// the hash tag stored jobs under queue:name:..., which are not read: run
+// MigrateLegacy once after upgrading, or StartReaper, which does. It moves
+// them to the new keys, and counts a failed attempt for jobs that were
+// being processed, since their workers can no longer settle them.
+//
+// A job whose worker crashed keeps its place in flight until its lease
+// expires. A reaper puts such jobs back, counting the lost attempt, or
+// dead-letters those out of retries:
+//
+//	q.StartReaper(ctx, time.Minute)
+//
+// MemoryQueue implements the same interface in process, for tests and local
+// development, and passes the same conformance tests as RedisQueue: an
+// expired job counts a failed attempt, and a worker that lost its lease
+// cannot settle the redelivery. Peer stands in for a second process
+// consuming the queue. It refuses new jobs with a CodeRateLimit error once
+// MaxSize are waiting:
+//
+//	q := queue.NewMemoryQueue(queue.Config{Name: "default", MaxSize: 1000})
+//	defer q.Close()
+//
+// Queues implementing Admin offer operator controls: listing, requeueing,
+// and purging dead-lettered jobs, pausing delivery, and draining waiting
+// jobs. A QueueManager holds a deployment's queues by name:
+//
+//	queues := queue.NewQueueManager()
+//	queues.Register("default", q)
+//	admin, err := queues.Admin("default")
+//	requeued, err := admin.RequeueDeadLetters(ctx)
+//
+// A Locker hands out named locks with fencing tokens, for work that must
+// not run twice at once across processes. RedisLocker shares them through
+// Redis and MemoryLocker within one process:
+//
+//	locker, err := queue.NewRedisLocker(redisClient, "queue:default")
+//	lock, err := locker.Acquire(ctx, "account:42", time.Minute)
+//	if errors.HasCode(err, errors.CodeConflict) {
+//	    // someone else holds it
+//	}
+//	defer lock.Release(ctx)
+//
+// The queuetest subpackage wraps a queue in a ChaosQueue injecting faults,
+// for testing consumers under at-least-once delivery.
 package queue
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"task-queue/internal/models"
+	"task-queue/pkg/logger"
 
 	"github.com/google/uuid"
 )
@@ -20,6 +21,10 @@ type Queue interface {
 	// Dequeue retrieves the next job from the queue
 	Dequeue(ctx context.Context) (*models.Job, error)
 
+	// DequeueMinPriority retrieves the next job whose priority is at least
+	// min
+	DequeueMinPriority(ctx context.Context, min models.JobPriority) (*models.Job, error)
+
 	// DequeueBatch retrieves multiple jobs from the queue
 	DequeueBatch(ctx context.Context, limit int) ([]*models.Job, error)
 
@@ -29,6 +34,18 @@ type Queue interface {
 	// Nack returns a job to the queue for reprocessing
 	Nack(ctx context.Context, jobID uuid.UUID, reason string) error
 
+	// NackError returns a failed job like Nack, but dead-letters it right
+	// away when errors.IsRetryable classifies the error as non-retryable
+	NackError(ctx context.Context, jobID uuid.UUID, err error) error
+
+	// NackWithDelay returns a failed job to the queue like Nack, but retries
+	// it after delay instead of the backoff
+	NackWithDelay(ctx context.Context, jobID uuid.UUID, reason string, delay time.Duration) error
+
+	// Release returns a job being processed to the queue, ready again after
+	// delay, without counting a retry
+	Release(ctx context.Context, jobID uuid.UUID, delay time.Duration) error
+
 	// Delete removes a job from the queue
 	Delete(ctx context.Context, jobID uuid.UUID) error
 
@@ -56,11 +73,13 @@ type QueueStats struct {
 	Delayed         int64         `json:"delayed"`
 	Failed          int64         `json:"failed"`
 	DeadLetter      int64         `json:"dead_letter"`
+	OldestAge       time.Duration `json:"oldest_age"`
 	EnqueueRate     float64       `json:"enqueue_rate"`
 	DequeueRate     float64       `json:"dequeue_rate"`
 	ProcessingTime  time.Duration `json:"avg_processing_time"`
 	LastEnqueueTime *time.Time    `json:"last_enqueue_time,omitempty"`
 	LastDequeueTime *time.Time    `json:"last_dequeue_time,omitempty"`
+	Paused          bool          `json:"paused"`
 }
 
 // Config represents queue configuration
@@ -73,6 +92,12 @@ type Config struct {
 	DeadLetterQueue   string        `json:"dead_letter_queue" yaml:"dead_letter_queue"`
 	PollInterval      time.Duration `json:"poll_interval" yaml:"poll_interval"`
 	BatchSize         int           `json:"batch_size" yaml:"batch_size"`
+
+	// Audit records job lifecycle transitions; nil disables auditing
+	Audit logger.AuditLogger `json:"-" yaml:"-"`
+
+	// ReaperMetrics counts the jobs the reaper reclaims; nil disables it
+	ReaperMetrics ReaperMetrics `json:"-" yaml:"-"`
 }
 
 // DefaultConfig returns default queue configuration
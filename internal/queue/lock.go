@@ -0,0 +1,255 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"task-queue/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Locker hands out named locks shared by every process using the same
+// backend, such as all workers of a Redis deployment
+type Locker interface {
+	// Acquire takes the lock on key for ttl. It fails with a CodeConflict
+	// error while another holder has it.
+	Acquire(ctx context.Context, key string, ttl time.Duration) (*Lock, error)
+}
+
+// lockBackend renews and releases locks handed out by a Locker
+type lockBackend interface {
+	refresh(ctx context.Context, key string, token int64, ttl time.Duration) error
+	release(ctx context.Context, key string, token int64) error
+}
+
+// Lock is a held lock. Token is a fencing token that grows with every
+// acquisition of Key, so a store can reject writes from a holder whose lock
+// expired and was taken by someone else.
+type Lock struct {
+	Key   string
+	Token int64
+
+	backend lockBackend
+}
+
+// Refresh extends the lock to ttl from now. It fails with a CodeNotFound
+// error once the lock expired or was released.
+func (l *Lock) Refresh(ctx context.Context, ttl time.Duration) error {
+	return l.backend.refresh(ctx, l.Key, l.Token, ttl)
+}
+
+// Release gives up the lock. It fails with a CodeNotFound error when the
+// lock already expired, in which case it may now belong to someone else and
+// is left alone.
+func (l *Lock) Release(ctx context.Context) error {
+	return l.backend.release(ctx, l.Key, l.Token)
+}
+
+// lockHeld is the error of acquiring a lock someone else holds
+func lockHeld(key string) error {
+	return errors.Newf("lock %q is held", key).
+		WithCode(errors.CodeConflict).
+		WithKey("lock.held", key).
+		WithOp("queue.Lock")
+}
+
+// lockLost is the error of renewing or releasing a lock no longer held
+func lockLost(key string) error {
+	return errors.NotFound("lock %q is no longer held", key).
+		WithKey("lock.lost", key).
+		WithOp("queue.Lock")
+}
+
+// Lock scripts. The locks of a RedisLocker and its fencing counter share
+// the hash tag of its prefix so they live in one Redis Cluster slot.
+var (
+	// acquireLockScript sets KEYS[1] to the next fencing token from KEYS[2]
+	// unless it exists, returning the token or 0
+	acquireLockScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return 0
+end
+local token = redis.call("INCR", KEYS[2])
+redis.call("SET", KEYS[1], token, "PX", ARGV[1])
+return token
+`)
+
+	// refreshLockScript extends KEYS[1] when it still holds token ARGV[1]
+	refreshLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+	// releaseLockScript deletes KEYS[1] when it still holds token ARGV[1]
+	releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+)
+
+// RedisLocker is a Locker backed by Redis. A lock is a key set with NX and
+// an expiry, holding its fencing token; only the holder of the token can
+// renew or delete it. Tokens come from one counter per locker, so locking
+// ever more distinct keys leaves no keys behind.
+type RedisLocker struct {
+	client    redis.UniversalClient
+	keyPrefix string
+}
+
+var _ Locker = (*RedisLocker)(nil)
+
+// NewRedisLocker creates a locker whose keys start with prefix, such as the
+// queue name
+func NewRedisLocker(client redis.UniversalClient, prefix string) (*RedisLocker, error) {
+	if client == nil {
+		return nil, errors.New("redis client is required").
+			WithCode(errors.CodeConfiguration)
+	}
+
+	return &RedisLocker{client: client, keyPrefix: prefix}, nil
+}
+
+// lockKeys returns the Redis keys of the lock on key and of the locker's
+// fencing counter
+func (l *RedisLocker) lockKeys(key string) []string {
+	return []string{
+		fmt.Sprintf("{%s}:lock:%s", l.keyPrefix, key),
+		fmt.Sprintf("{%s}:fence", l.keyPrefix),
+	}
+}
+
+// Acquire takes the lock on key for ttl
+func (l *RedisLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	token, err := acquireLockScript.Run(ctx, l.client, l.lockKeys(key), ttl.Milliseconds()).Int64()
+	if err != nil {
+		return nil, errors.Wrapf(errors.FromRedis(err), "failed to acquire lock %q", key).
+			WithOp("queue.Lock")
+	}
+
+	if token == 0 {
+		return nil, lockHeld(key)
+	}
+
+	return &Lock{Key: key, Token: token, backend: l}, nil
+}
+
+func (l *RedisLocker) refresh(ctx context.Context, key string, token int64, ttl time.Duration) error {
+	ok, err := refreshLockScript.Run(ctx, l.client, l.lockKeys(key)[:1],
+		strconv.FormatInt(token, 10), ttl.Milliseconds()).Int64()
+	if err != nil {
+		return errors.Wrapf(errors.FromRedis(err), "failed to refresh lock %q", key).
+			WithOp("queue.Lock")
+	}
+
+	if ok == 0 {
+		return lockLost(key)
+	}
+
+	return nil
+}
+
+func (l *RedisLocker) release(ctx context.Context, key string, token int64) error {
+	ok, err := releaseLockScript.Run(ctx, l.client, l.lockKeys(key)[:1],
+		strconv.FormatInt(token, 10)).Int64()
+	if err != nil {
+		return errors.Wrapf(errors.FromRedis(err), "failed to release lock %q", key).
+			WithOp("queue.Lock")
+	}
+
+	if ok == 0 {
+		return lockLost(key)
+	}
+
+	return nil
+}
+
+// memoryLock is a lock held in a MemoryLocker
+type memoryLock struct {
+	token   int64
+	expires time.Time
+}
+
+// MemoryLocker is an in-process Locker for tests and single-process
+// deployments. Locks are shared by everything using the same MemoryLocker.
+type MemoryLocker struct {
+	mu     sync.Mutex
+	locks  map[string]memoryLock
+	fences map[string]int64
+	now    func() time.Time
+}
+
+var _ Locker = (*MemoryLocker)(nil)
+
+// NewMemoryLocker creates an empty in-process locker
+func NewMemoryLocker() *MemoryLocker {
+	return &MemoryLocker{
+		locks:  make(map[string]memoryLock),
+		fences: make(map[string]int64),
+		now:    time.Now,
+	}
+}
+
+// held returns the unexpired lock on key. The caller holds l.mu.
+func (l *MemoryLocker) held(key string) (memoryLock, bool) {
+	lock, ok := l.locks[key]
+	if ok && !lock.expires.After(l.now()) {
+		delete(l.locks, key)
+		return memoryLock{}, false
+	}
+
+	return lock, ok
+}
+
+// Acquire takes the lock on key for ttl
+func (l *MemoryLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, errors.FromContext(err).WithOp("queue.Lock")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.held(key); ok {
+		return nil, lockHeld(key)
+	}
+
+	l.fences[key]++
+	token := l.fences[key]
+	l.locks[key] = memoryLock{token: token, expires: l.now().Add(ttl)}
+	return &Lock{Key: key, Token: token, backend: l}, nil
+}
+
+func (l *MemoryLocker) refresh(_ context.Context, key string, token int64, ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lock, ok := l.held(key)
+	if !ok || lock.token != token {
+		return lockLost(key)
+	}
+
+	lock.expires = l.now().Add(ttl)
+	l.locks[key] = lock
+	return nil
+}
+
+func (l *MemoryLocker) release(_ context.Context, key string, token int64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lock, ok := l.held(key)
+	if !ok || lock.token != token {
+		return lockLost(key)
+	}
+
+	delete(l.locks, key)
+	return nil
+}
@@ -0,0 +1,85 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"task-queue/pkg/errors"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLocker(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	l := NewMemoryLocker()
+	l.now = func() time.Time { return now }
+
+	first, err := l.Acquire(ctx, "account-1", time.Second)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, first.Token)
+
+	_, err = l.Acquire(ctx, "account-1", time.Second)
+	assert.True(t, errors.HasCode(err, errors.CodeConflict))
+
+	other, err := l.Acquire(ctx, "account-2", time.Second)
+	require.NoError(t, err)
+	require.NoError(t, other.Release(ctx))
+
+	// A refreshed lock outlives its first ttl
+	now = now.Add(900 * time.Millisecond)
+	require.NoError(t, first.Refresh(ctx, time.Second))
+	now = now.Add(900 * time.Millisecond)
+	_, err = l.Acquire(ctx, "account-1", time.Second)
+	assert.True(t, errors.HasCode(err, errors.CodeConflict))
+
+	// A holder that stops refreshing loses the lock to the next one, which
+	// gets a higher fencing token
+	now = now.Add(time.Second)
+	second, err := l.Acquire(ctx, "account-1", time.Second)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, second.Token)
+
+	assert.True(t, errors.HasCode(first.Refresh(ctx, time.Second), errors.CodeNotFound))
+	assert.True(t, errors.HasCode(first.Release(ctx), errors.CodeNotFound))
+
+	require.NoError(t, second.Release(ctx))
+	third, err := l.Acquire(ctx, "account-1", time.Second)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, third.Token)
+}
+
+func TestRedisLocker(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	l, err := NewRedisLocker(client, "queue:default")
+	require.NoError(t, err)
+
+	first, err := l.Acquire(ctx, "account-1", time.Second)
+	require.NoError(t, err)
+	_, err = l.Acquire(ctx, "account-1", time.Second)
+	assert.True(t, errors.HasCode(err, errors.CodeConflict))
+	require.NoError(t, first.Release(ctx))
+	assert.True(t, errors.HasCode(first.Release(ctx), errors.CodeNotFound))
+
+	// Locking many distinct keys leaves only the fencing counter, and
+	// tokens keep growing
+	last := first.Token
+	for i := range 100 {
+		lock, err := l.Acquire(ctx, fmt.Sprintf("account-%d", i), time.Second)
+		require.NoError(t, err)
+		assert.Greater(t, lock.Token, last)
+		last = lock.Token
+		require.NoError(t, lock.Release(ctx))
+	}
+
+	assert.Equal(t, []string{"{queue:default}:fence"}, server.Keys())
+}
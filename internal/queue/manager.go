@@ -0,0 +1,100 @@
+package queue
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"task-queue/pkg/errors"
+)
+
+// QueueManager holds the named queues of a deployment
+type QueueManager struct {
+	mu     sync.RWMutex
+	queues map[string]Queue
+}
+
+// NewQueueManager creates an empty queue manager
+func NewQueueManager() *QueueManager {
+	return &QueueManager{queues: make(map[string]Queue)}
+}
+
+// Register adds q under name, replacing any queue registered under it
+func (m *QueueManager) Register(name string, q Queue) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queues[name] = q
+}
+
+// Names returns the registered queue names in order
+func (m *QueueManager) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.queues))
+	for name := range m.queues {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+	return names
+}
+
+// Get returns the queue registered under name, or a CodeNotFound error
+func (m *QueueManager) Get(name string) (Queue, error) {
+	m.mu.RLock()
+	q, ok := m.queues[name]
+	m.mu.RUnlock()
+
+	if !ok {
+		return nil, errors.NotFound("queue %s not found", name).
+			WithKey("queue.not_found", name).
+			WithOp("queue.QueueManager.Get")
+	}
+
+	return q, nil
+}
+
+// Admin returns the operator controls of the queue registered under name.
+// A queue whose backend does not implement Admin fails with a
+// CodeValidation error.
+func (m *QueueManager) Admin(name string) (Admin, error) {
+	q, err := m.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	admin, ok := q.(Admin)
+	if !ok {
+		return nil, errors.Validation("queue %s does not support admin operations", name).
+			WithKey("queue.admin_unsupported", name).
+			WithOp("queue.QueueManager.Admin")
+	}
+
+	return admin, nil
+}
+
+// Stats returns the statistics of every queue, ordered by name. Each
+// carries its registered name.
+func (m *QueueManager) Stats(ctx context.Context) ([]*QueueStats, error) {
+	names := m.Names()
+	stats := make([]*QueueStats, 0, len(names))
+	for _, name := range names {
+		q, err := m.Get(name)
+		if err != nil {
+			continue
+		}
+
+		s, err := q.Stats(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get stats of queue %s", name).
+				WithOp("queue.QueueManager.Stats")
+		}
+
+		s.Name = name
+		stats = append(stats, s)
+	}
+
+	return stats, nil
+}
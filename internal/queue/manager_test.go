@@ -0,0 +1,43 @@
+package queue
+
+import (
+	"context"
+	"testing"
+
+	"task-queue/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statsOnly is a Queue without admin support
+type statsOnly struct {
+	Queue
+}
+
+func TestQueueManager(t *testing.T) {
+	m := NewQueueManager()
+	m.Register("emails", NewMemoryQueue(Config{Name: "emails"}))
+	m.Register("reports", NewMemoryQueue(Config{Name: "queue:reports"}))
+	m.Register("legacy", statsOnly{})
+
+	assert.Equal(t, []string{"emails", "legacy", "reports"}, m.Names())
+
+	_, err := m.Get("missing")
+	assert.True(t, errors.HasCode(err, errors.CodeNotFound))
+
+	_, err = m.Admin("legacy")
+	assert.True(t, errors.HasCode(err, errors.CodeValidation))
+
+	admin, err := m.Admin("emails")
+	require.NoError(t, err)
+	require.NoError(t, admin.Pause(context.Background()))
+
+	m.Register("legacy", NewMemoryQueue(Config{}))
+	stats, err := m.Stats(context.Background())
+	require.NoError(t, err)
+	require.Len(t, stats, 3)
+	assert.Equal(t, "emails", stats[0].Name)
+	assert.True(t, stats[0].Paused)
+	assert.Equal(t, "reports", stats[2].Name, "stats carry the registered name")
+}
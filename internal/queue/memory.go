@@ -0,0 +1,871 @@
+package queue
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// DefaultVisibilityTimeout is how long a dequeued job stays invisible when
+// Config.VisibilityTimeout is unset
+const DefaultVisibilityTimeout = 5 * time.Minute
+
+// MemoryQueue implements Queue with in-process data structures, for tests
+// and local development. It follows RedisQueue's semantics: jobs are served
+// by priority, scheduled jobs wait in a delayed heap, and a dequeued job
+// whose visibility timeout expires counts a failed attempt, then is
+// delivered again or dead-lettered once its retries are exhausted. A timer
+// reclaims such jobs as their timeouts expire.
+//
+// Like a RedisQueue, a MemoryQueue only settles or extends a job as it
+// handed it out: once the job was delivered again through a Peer, it
+// reports the earlier delivery with a CodeNotFound error. It is safe for
+// concurrent use.
+type MemoryQueue struct {
+	*memoryJobs
+
+	// held maps the jobs this queue handed out to their delivery. It is
+	// guarded by mu.
+	held map[uuid.UUID]uint64
+}
+
+// memoryJobs holds the jobs a MemoryQueue shares with its peers
+type memoryJobs struct {
+	mu         sync.Mutex
+	config     Config
+	ready      map[models.JobPriority][]*models.Job
+	delayed    delayedJobs
+	processing map[uuid.UUID]*inFlight
+	deadLetter []*models.Job
+	paused     bool
+	now        func() time.Time
+
+	// deliveries numbers the jobs handed out
+	deliveries uint64
+
+	// expiry fires when the earliest visibility timeout of the jobs in
+	// flight, wake, expires
+	expiry *time.Timer
+	wake   time.Time
+	closed bool
+}
+
+var (
+	_ Admin         = (*MemoryQueue)(nil)
+	_ Reprioritizer = (*MemoryQueue)(nil)
+)
+
+// inFlight is a dequeued job, the delivery it was handed out as, and the
+// time it becomes visible again
+type inFlight struct {
+	job      *models.Job
+	delivery uint64
+	visible  time.Time
+}
+
+// delayedJobs is a heap of scheduled jobs, the next due first
+type delayedJobs []*models.Job
+
+func (h delayedJobs) Len() int { return len(h) }
+
+func (h delayedJobs) Less(i, j int) bool {
+	return h[i].ScheduledAt.Before(*h[j].ScheduledAt)
+}
+
+func (h delayedJobs) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *delayedJobs) Push(x any) { *h = append(*h, x.(*models.Job)) }
+
+func (h *delayedJobs) Pop() any {
+	old := *h
+	job := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return job
+}
+
+// NewMemoryQueue creates an empty in-memory queue. Enqueueing fails with a
+// CodeRateLimit error once Config.MaxSize jobs are waiting, unless it is 0.
+func NewMemoryQueue(config Config) *MemoryQueue {
+	if config.Name == "" {
+		config.Name = "default"
+	}
+
+	if config.VisibilityTimeout <= 0 {
+		config.VisibilityTimeout = DefaultVisibilityTimeout
+	}
+
+	if config.Audit == nil {
+		config.Audit = logger.NopAudit()
+	}
+
+	return &MemoryQueue{
+		memoryJobs: &memoryJobs{
+			config:     config,
+			ready:      make(map[models.JobPriority][]*models.Job),
+			processing: make(map[uuid.UUID]*inFlight),
+			now:        time.Now,
+		},
+		held: make(map[uuid.UUID]uint64),
+	}
+}
+
+// Peer returns another queue over the same jobs that keeps its own record
+// of the jobs it handed out, as a second process sharing a RedisQueue does
+func (q *MemoryQueue) Peer() *MemoryQueue {
+	return &MemoryQueue{memoryJobs: q.memoryJobs, held: make(map[uuid.UUID]uint64)}
+}
+
+// Abandon forgets the job with jobID this queue handed out, once its worker
+// gave it up after losing its lease
+func (q *MemoryQueue) Abandon(jobID uuid.UUID) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.held, jobID)
+}
+
+// flight returns the job with jobID being processed, or nil when it is not
+// in flight or this queue handed out an earlier delivery of it. The caller
+// holds q.mu.
+func (q *MemoryQueue) flight(jobID uuid.UUID) *inFlight {
+	flight, ok := q.processing[jobID]
+	if !ok {
+		return nil
+	}
+
+	if delivery, held := q.held[jobID]; held && delivery != flight.delivery {
+		return nil
+	}
+
+	return flight
+}
+
+// take removes the job with jobID from processing, as flight finds it. The
+// caller holds q.mu.
+func (q *MemoryQueue) take(jobID uuid.UUID) *inFlight {
+	flight := q.flight(jobID)
+	if flight != nil {
+		delete(q.processing, jobID)
+		delete(q.held, jobID)
+	}
+
+	return flight
+}
+
+// Enqueue adds a job to the queue
+func (q *MemoryQueue) Enqueue(ctx context.Context, job *models.Job) error {
+	if job == nil {
+		return errors.Validation("job is nil").
+			WithKey("job.nil").
+			WithOp("queue.Enqueue")
+	}
+
+	q.mu.Lock()
+	if err := q.admit(1, "queue.Enqueue"); err != nil {
+		q.mu.Unlock()
+		return err
+	}
+
+	q.push(job)
+	q.mu.Unlock()
+
+	q.auditCreated(ctx, job)
+	return nil
+}
+
+// EnqueueBatch adds multiple jobs to the queue
+func (q *MemoryQueue) EnqueueBatch(ctx context.Context, jobs []*models.Job) error {
+	for i, job := range jobs {
+		if job == nil {
+			return errors.Validation("job at index %d is nil", i).
+				WithKey("job.nil_in_batch", i).
+				WithMetadata("index", i).
+				WithOp("queue.EnqueueBatch")
+		}
+	}
+
+	q.mu.Lock()
+	if err := q.admit(len(jobs), "queue.EnqueueBatch"); err != nil {
+		q.mu.Unlock()
+		return err
+	}
+
+	for _, job := range jobs {
+		q.push(job)
+	}
+	q.mu.Unlock()
+
+	for _, job := range jobs {
+		q.auditCreated(ctx, job)
+	}
+
+	return nil
+}
+
+// admit returns a CodeRateLimit error when incoming more jobs would leave
+// more than Config.MaxSize waiting. The caller holds q.mu.
+func (q *MemoryQueue) admit(incoming int, op string) error {
+	if q.config.MaxSize <= 0 || q.size()+int64(incoming) <= q.config.MaxSize {
+		return nil
+	}
+
+	return errors.RateLimited("queue %s is full", q.config.Name).
+		WithKey("queue.full", q.config.Name).
+		WithMetadata("max_size", q.config.MaxSize).
+		WithOp(op)
+}
+
+// auditCreated records a job entering the queue
+func (q *MemoryQueue) auditCreated(ctx context.Context, job *models.Job) {
+	q.config.Audit.JobEvent(ctx, logger.AuditJobCreated, job,
+		"priority", job.Priority,
+		"queue", q.config.Name,
+	)
+}
+
+// push stores a copy of job in its priority list, or in the delayed set when
+// it is scheduled for later. The caller holds q.mu.
+func (q *memoryJobs) push(job *models.Job) {
+	stored := *job
+	if stored.ScheduledAt != nil && stored.ScheduledAt.After(q.now()) {
+		heap.Push(&q.delayed, &stored)
+		return
+	}
+
+	q.ready[stored.Priority] = append(q.ready[stored.Priority], &stored)
+}
+
+// Dequeue retrieves the next job from the queue, or nil when none is ready.
+// Due delayed jobs and jobs whose visibility timeout expired are made ready
+// first.
+func (q *MemoryQueue) Dequeue(ctx context.Context) (*models.Job, error) {
+	return q.dequeue(ctx, models.JobPriorityLow)
+}
+
+// DequeueMinPriority retrieves the next job whose priority is at least min,
+// or nil when none is ready
+func (q *MemoryQueue) DequeueMinPriority(ctx context.Context,
+	min models.JobPriority) (*models.Job, error) {
+	return q.dequeue(ctx, min)
+}
+
+// dequeue retrieves the next ready job with a priority of at least min
+func (q *MemoryQueue) dequeue(ctx context.Context, min models.JobPriority) (*models.Job, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, errors.FromContext(err).WithOp("queue.Dequeue")
+	}
+
+	q.mu.Lock()
+	if q.paused {
+		q.mu.Unlock()
+		return nil, nil
+	}
+
+	reclaimed := q.promote()
+
+	var job *models.Job
+	for _, priority := range []models.JobPriority{
+		models.JobPriorityCritical,
+		models.JobPriorityHigh,
+		models.JobPriorityNormal,
+		models.JobPriorityLow,
+	} {
+		if priority < min {
+			break
+		}
+
+		if list := q.ready[priority]; len(list) > 0 {
+			job, q.ready[priority] = list[0], list[1:]
+			break
+		}
+	}
+
+	if job == nil {
+		q.mu.Unlock()
+		q.auditReclaimed(ctx, reclaimed)
+		return nil, nil
+	}
+
+	visible := q.now().Add(q.config.VisibilityTimeout)
+	q.deliveries++
+	q.processing[job.ID] = &inFlight{job: job, delivery: q.deliveries, visible: visible}
+	q.held[job.ID] = q.deliveries
+	q.expireAt(visible)
+	dequeued := *job
+	q.mu.Unlock()
+
+	q.auditReclaimed(ctx, reclaimed)
+	q.config.Audit.JobEvent(ctx, logger.AuditJobStarted, &dequeued,
+		"queue", q.config.Name,
+	)
+
+	return &dequeued, nil
+}
+
+// promote reclaims the jobs in flight whose visibility timeout expired and
+// moves due delayed jobs to the ready lists. It returns copies of the
+// reclaimed jobs, to audit once q.mu is released. The caller holds q.mu.
+func (q *memoryJobs) promote() []*models.Job {
+	now := q.now()
+	var reclaimed []*models.Job
+	for id, flight := range q.processing {
+		if !flight.visible.After(now) {
+			delete(q.processing, id)
+			reclaimed = append(reclaimed, q.fail(flight.job, reapReason, true, 0))
+		}
+	}
+
+	for len(q.delayed) > 0 && !q.delayed[0].ScheduledAt.After(now) {
+		job := heap.Pop(&q.delayed).(*models.Job)
+		q.ready[job.Priority] = append(q.ready[job.Priority], job)
+	}
+
+	return reclaimed
+}
+
+// lockPromoted locks q.mu and promotes jobs, returning the function that
+// unlocks it and audits the jobs reclaimed meanwhile
+func (q *memoryJobs) lockPromoted(ctx context.Context) func() {
+	q.mu.Lock()
+	reclaimed := q.promote()
+
+	return func() {
+		q.mu.Unlock()
+		q.auditReclaimed(ctx, reclaimed)
+	}
+}
+
+// auditReclaimed records the failed attempts of jobs reclaimed by promote
+func (q *memoryJobs) auditReclaimed(ctx context.Context, reclaimed []*models.Job) {
+	for _, job := range reclaimed {
+		q.auditFailed(ctx, job, reapReason, true)
+	}
+}
+
+// expireAt arms the expiry timer to fire by visible, unless it already
+// fires sooner. The caller holds q.mu.
+func (q *memoryJobs) expireAt(visible time.Time) {
+	if q.closed || (q.expiry != nil && !q.wake.After(visible)) {
+		return
+	}
+
+	if q.expiry != nil {
+		q.expiry.Stop()
+	}
+
+	q.wake = visible
+	q.expiry = time.AfterFunc(visible.Sub(q.now()), q.expire)
+}
+
+// expire reclaims the jobs whose visibility timeout expired, and arms the
+// expiry timer for the next one to expire
+func (q *memoryJobs) expire() {
+	q.mu.Lock()
+	q.expiry = nil
+	reclaimed := q.promote()
+
+	var next time.Time
+	for _, flight := range q.processing {
+		if next.IsZero() || flight.visible.Before(next) {
+			next = flight.visible
+		}
+	}
+
+	if !next.IsZero() {
+		q.expireAt(next)
+	}
+	q.mu.Unlock()
+
+	q.auditReclaimed(context.Background(), reclaimed)
+}
+
+// DequeueBatch retrieves up to limit ready jobs
+func (q *MemoryQueue) DequeueBatch(ctx context.Context, limit int) ([]*models.Job, error) {
+	if limit <= 0 {
+		limit = q.config.BatchSize
+	}
+
+	jobs := make([]*models.Job, 0, limit)
+	for i := 0; i < limit; i++ {
+		job, err := q.Dequeue(ctx)
+		if err != nil {
+			return jobs, err
+		}
+
+		if job == nil {
+			break
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// Ack acknowledges successful job processing
+func (q *MemoryQueue) Ack(ctx context.Context, jobID uuid.UUID) error {
+	q.mu.Lock()
+	flight := q.take(jobID)
+	q.mu.Unlock()
+
+	if flight == nil {
+		return errors.NotFound("job %s not found in processing queue", jobID).
+			WithKey("job.not_in_processing", jobID).
+			WithOp("queue.Ack")
+	}
+
+	q.config.Audit.JobEvent(ctx, logger.AuditJobCompleted, flight.job,
+		"queue", q.config.Name,
+	)
+
+	return nil
+}
+
+// Nack returns a job to the queue for reprocessing
+func (q *MemoryQueue) Nack(ctx context.Context, jobID uuid.UUID, reason string) error {
+	return q.nack(ctx, jobID, reason, true, backoffDelay)
+}
+
+// NackWithDelay returns a failed job to the queue like Nack, but retries it
+// after delay instead of the backoff
+func (q *MemoryQueue) NackWithDelay(ctx context.Context, jobID uuid.UUID, reason string,
+	delay time.Duration) error {
+	return q.nack(ctx, jobID, reason, true, max(delay, 0))
+}
+
+// NackError returns a failed job to the queue like Nack, but dead-letters it
+// immediately when the error is classified as non-retryable
+func (q *MemoryQueue) NackError(ctx context.Context, jobID uuid.UUID, jobErr error) error {
+	retryable, known := errors.IsRetryable(jobErr)
+
+	reason := "unknown error"
+	if jobErr != nil {
+		reason = jobErr.Error()
+	}
+
+	return q.nack(ctx, jobID, reason, retryable || !known, backoffDelay)
+}
+
+// nack removes a job from processing and either reschedules it after delay,
+// or the same backoff as RedisQueue for backoffDelay, or, when retry is
+// false or its retries are exhausted, dead-letters it
+func (q *MemoryQueue) nack(ctx context.Context, jobID uuid.UUID, reason string,
+	retry bool, delay time.Duration) error {
+	q.mu.Lock()
+	flight := q.take(jobID)
+	if flight == nil {
+		q.mu.Unlock()
+		return errors.NotFound("job %s not found in processing queue", jobID).
+			WithKey("job.not_in_processing", jobID).
+			WithOp("queue.Nack")
+	}
+
+	audited := q.fail(flight.job, reason, retry, delay)
+	q.mu.Unlock()
+
+	q.auditFailed(ctx, audited, reason, retry)
+	return nil
+}
+
+// fail counts a failed attempt of job, taken out of processing, and either
+// reschedules it after delay, or the same backoff as RedisQueue for
+// backoffDelay, or dead-letters it when retry is false or its retries are
+// exhausted. It returns a copy of the job. The caller holds q.mu.
+func (q *memoryJobs) fail(job *models.Job, reason string, retry bool,
+	delay time.Duration) *models.Job {
+	job.RetryCount++
+	job.Error = &reason
+	job.UpdatedAt = q.now()
+
+	if !retry || job.RetryCount >= job.MaxRetries {
+		job.Status = models.JobStatusDead
+		q.deadLetter = append(q.deadLetter, job)
+	} else {
+		if delay == backoffDelay {
+			delay = time.Duration(job.RetryCount) * time.Minute
+		}
+
+		job.ScheduledAt = ptr(q.now().Add(delay))
+		q.push(job)
+	}
+
+	failed := *job
+	return &failed
+}
+
+// auditFailed records a failed attempt of job, and its dead-lettering
+func (q *memoryJobs) auditFailed(ctx context.Context, job *models.Job, reason string, retry bool) {
+	q.config.Audit.JobEvent(ctx, logger.AuditJobFailed, job,
+		"queue", q.config.Name,
+		"retry_count", job.RetryCount,
+		"retryable", retry,
+		"reason", reason,
+	)
+
+	if job.Status == models.JobStatusDead {
+		q.config.Audit.JobEvent(ctx, logger.AuditJobDeadLettered, job,
+			"queue", q.config.Name,
+			"retry_count", job.RetryCount,
+		)
+	}
+}
+
+// Delete removes a job from the queue, wherever it is
+func (q *MemoryQueue) Delete(ctx context.Context, jobID uuid.UUID) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for priority, list := range q.ready {
+		if i := indexOf(list, jobID); i >= 0 {
+			q.ready[priority] = append(list[:i:i], list[i+1:]...)
+			return nil
+		}
+	}
+
+	if q.take(jobID) != nil {
+		return nil
+	}
+
+	if i := indexOf(q.delayed, jobID); i >= 0 {
+		heap.Remove(&q.delayed, i)
+		return nil
+	}
+
+	if i := indexOf(q.deadLetter, jobID); i >= 0 {
+		q.deadLetter = append(q.deadLetter[:i:i], q.deadLetter[i+1:]...)
+		return nil
+	}
+
+	return errors.NotFound("job %s not found", jobID).
+		WithKey("job.not_found", jobID).
+		WithOp("queue.Delete")
+}
+
+// indexOf returns the index of the job with id in jobs, or -1
+func indexOf(jobs []*models.Job, id uuid.UUID) int {
+	for i, job := range jobs {
+		if job.ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Release returns a job being processed to the queue, ready again after
+// delay, without counting a retry
+func (q *MemoryQueue) Release(ctx context.Context, jobID uuid.UUID,
+	delay time.Duration) error {
+	q.mu.Lock()
+	flight := q.take(jobID)
+	if flight == nil {
+		q.mu.Unlock()
+		return errors.NotFound("job %s not found in processing queue", jobID).
+			WithKey("job.not_in_processing", jobID).
+			WithOp("queue.Release")
+	}
+
+	job := flight.job
+	job.UpdatedAt = q.now()
+	job.ScheduledAt = nil
+	if delay > 0 {
+		job.ScheduledAt = ptr(q.now().Add(delay))
+	}
+
+	q.push(job)
+	audited := *job
+	q.mu.Unlock()
+
+	q.config.Audit.JobEvent(ctx, logger.AuditJobRequeued, &audited,
+		"queue", q.config.Name,
+		"delay", delay,
+	)
+
+	return nil
+}
+
+// Waiting returns up to limit ready jobs of priority, longest waiting
+// first. Due delayed jobs are made ready first.
+func (q *MemoryQueue) Waiting(ctx context.Context, priority models.JobPriority,
+	limit int) ([]*models.Job, error) {
+	defer q.lockPromoted(ctx)()
+
+	list := q.ready[priority]
+	jobs := make([]*models.Job, 0, min(limit, len(list)))
+	for _, job := range list[:min(limit, len(list))] {
+		waiting := *job
+		jobs = append(jobs, &waiting)
+	}
+
+	return jobs, nil
+}
+
+// UpdatePriority moves a ready or delayed job to priority and returns it. A
+// ready job joins the back of its new priority's list.
+func (q *MemoryQueue) UpdatePriority(ctx context.Context, jobID uuid.UUID,
+	priority models.JobPriority) (*models.Job, error) {
+	q.mu.Lock()
+	job, from := q.reprioritize(jobID, priority)
+	if job == nil {
+		q.mu.Unlock()
+		return nil, errors.NotFound("job %s is not waiting in the queue", jobID).
+			WithKey("job.not_waiting", jobID).
+			WithOp("queue.UpdatePriority")
+	}
+
+	updated := *job
+	q.mu.Unlock()
+
+	q.config.Audit.JobEvent(ctx, logger.AuditJobEscalated, &updated,
+		"queue", q.config.Name,
+		"from", from.Name(),
+	)
+
+	return &updated, nil
+}
+
+// reprioritize sets the priority of the waiting job with jobID, moving it
+// to the list of its new priority when it is ready, and returns the job and
+// its previous priority. It returns nil when the job is not waiting. The
+// caller holds q.mu.
+func (q *MemoryQueue) reprioritize(jobID uuid.UUID,
+	priority models.JobPriority) (*models.Job, models.JobPriority) {
+	if i := indexOf(q.delayed, jobID); i >= 0 {
+		job := q.delayed[i]
+		from := job.Priority
+		job.Priority = priority
+		job.UpdatedAt = q.now()
+		return job, from
+	}
+
+	for from, list := range q.ready {
+		i := indexOf(list, jobID)
+		if i < 0 {
+			continue
+		}
+
+		job := list[i]
+		q.ready[from] = append(list[:i:i], list[i+1:]...)
+		job.Priority = priority
+		job.UpdatedAt = q.now()
+		q.ready[priority] = append(q.ready[priority], job)
+		return job, from
+	}
+
+	return nil, 0
+}
+
+// Extend pushes back the visibility timeout of a job being processed. A job
+// that is no longer in flight, or was delivered again since this queue
+// handed it out, is reported with a CodeNotFound error.
+func (q *MemoryQueue) Extend(ctx context.Context, jobID uuid.UUID,
+	duration time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	flight := q.flight(jobID)
+	if flight == nil {
+		return errors.NotFound("job %s not found in processing queue", jobID).
+			WithKey("job.not_in_processing", jobID).
+			WithOp("queue.Extend")
+	}
+
+	flight.visible = q.now().Add(duration)
+	q.expireAt(flight.visible)
+	return nil
+}
+
+// Size returns the number of ready and delayed jobs
+func (q *MemoryQueue) Size(ctx context.Context) (int64, error) {
+	defer q.lockPromoted(ctx)()
+
+	return q.size(), nil
+}
+
+// size counts ready and delayed jobs. The caller holds q.mu.
+func (q *MemoryQueue) size() int64 {
+	total := int64(len(q.delayed))
+	for _, list := range q.ready {
+		total += int64(len(list))
+	}
+
+	return total
+}
+
+// Clear removes all ready, delayed, and in-flight jobs. Dead-lettered jobs
+// are kept, as in RedisQueue.
+func (q *MemoryQueue) Clear(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.ready = make(map[models.JobPriority][]*models.Job)
+	q.delayed = nil
+	q.processing = make(map[uuid.UUID]*inFlight)
+	return nil
+}
+
+// Close stops the expiry timer. Jobs in flight are then returned only as
+// the queue is used.
+func (q *MemoryQueue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.closed = true
+	if q.expiry != nil {
+		q.expiry.Stop()
+		q.expiry = nil
+	}
+
+	return nil
+}
+
+// Stats returns queue statistics
+func (q *MemoryQueue) Stats(ctx context.Context) (*QueueStats, error) {
+	defer q.lockPromoted(ctx)()
+
+	var oldest time.Duration
+	now := q.now()
+	for _, jobs := range q.ready {
+		for _, job := range jobs {
+			oldest = max(oldest, now.Sub(readySince(job)))
+		}
+	}
+
+	return &QueueStats{
+		Name:       q.config.Name,
+		Size:       q.size(),
+		Processing: int64(len(q.processing)),
+		Delayed:    int64(len(q.delayed)),
+		DeadLetter: int64(len(q.deadLetter)),
+		OldestAge:  oldest,
+		Paused:     q.paused,
+	}, nil
+}
+
+// DeadLetters returns up to limit dead-lettered jobs starting at offset,
+// oldest first, and the number of dead-lettered jobs
+func (q *MemoryQueue) DeadLetters(ctx context.Context, offset, limit int) ([]*models.Job, int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	total := len(q.deadLetter)
+	start := min(max(offset, 0), total)
+	end := min(start+max(limit, 0), total)
+
+	jobs := make([]*models.Job, 0, end-start)
+	for _, job := range q.deadLetter[start:end] {
+		copied := *job
+		jobs = append(jobs, &copied)
+	}
+
+	return jobs, int64(total), nil
+}
+
+// RequeueDeadLetter moves a dead-lettered job back to the queue with its
+// retry count and error cleared
+func (q *MemoryQueue) RequeueDeadLetter(ctx context.Context, jobID uuid.UUID) (*models.Job, error) {
+	q.mu.Lock()
+	i := indexOf(q.deadLetter, jobID)
+	if i < 0 {
+		q.mu.Unlock()
+		return nil, errors.NotFound("job %s not found in dead letter queue", jobID).
+			WithKey("job.not_in_dead_letter", jobID).
+			WithOp("queue.RequeueDeadLetter")
+	}
+
+	job := q.deadLetter[i]
+	q.deadLetter = append(q.deadLetter[:i:i], q.deadLetter[i+1:]...)
+	requeued := q.requeue(job)
+	q.mu.Unlock()
+
+	q.auditRequeued(ctx, requeued)
+	return requeued, nil
+}
+
+// RequeueDeadLetters requeues every dead-lettered job, returning how many
+// were requeued
+func (q *MemoryQueue) RequeueDeadLetters(ctx context.Context) (int64, error) {
+	q.mu.Lock()
+	jobs := q.deadLetter
+	q.deadLetter = nil
+
+	requeued := make([]*models.Job, len(jobs))
+	for i, job := range jobs {
+		requeued[i] = q.requeue(job)
+	}
+	q.mu.Unlock()
+
+	for _, job := range requeued {
+		q.auditRequeued(ctx, job)
+	}
+
+	return int64(len(requeued)), nil
+}
+
+// requeue revives a dead-lettered job and pushes it, returning a copy. The
+// caller holds q.mu.
+func (q *MemoryQueue) requeue(job *models.Job) *models.Job {
+	revive(job)
+	job.UpdatedAt = q.now()
+	q.push(job)
+
+	copied := *job
+	return &copied
+}
+
+// auditRequeued records a dead-lettered job returning to the queue
+func (q *MemoryQueue) auditRequeued(ctx context.Context, job *models.Job) {
+	q.config.Audit.JobEvent(ctx, logger.AuditJobRequeued, job,
+		"queue", q.config.Name,
+		"from", "dead_letter",
+	)
+}
+
+// PurgeDeadLetters deletes every dead-lettered job, returning how many were
+// deleted
+func (q *MemoryQueue) PurgeDeadLetters(ctx context.Context) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	purged := int64(len(q.deadLetter))
+	q.deadLetter = nil
+	return purged, nil
+}
+
+// Pause stops delivering jobs until Resume
+func (q *MemoryQueue) Pause(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.paused = true
+	return nil
+}
+
+// Resume delivers jobs again after Pause
+func (q *MemoryQueue) Resume(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.paused = false
+	return nil
+}
+
+// Drain deletes every ready and delayed job, returning how many were
+// deleted
+func (q *MemoryQueue) Drain(ctx context.Context) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	drained := q.size()
+	q.ready = make(map[models.JobPriority][]*models.Job)
+	q.delayed = nil
+	return drained, nil
+}
@@ -0,0 +1,246 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryQueue_DelayedAndRedelivery(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	q := NewMemoryQueue(Config{VisibilityTimeout: time.Minute})
+	q.now = func() time.Time { return now }
+
+	job := models.NewJob("a", nil, models.JobPriorityNormal)
+	job.ScheduledAt = ptr(now.Add(time.Second))
+	require.NoError(t, q.Enqueue(ctx, job))
+
+	got, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, got, "not due yet")
+
+	now = now.Add(time.Second)
+	got, err = q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+
+	// The visibility timeout expires without an Ack, so the job returns
+	now = now.Add(time.Minute)
+	again, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, again)
+	assert.Equal(t, job.ID, again.ID)
+
+	require.NoError(t, q.Extend(ctx, job.ID, time.Minute))
+	require.NoError(t, q.Ack(ctx, job.ID))
+	assert.True(t, errors.HasCode(q.Extend(ctx, job.ID, time.Minute), errors.CodeNotFound))
+}
+
+func TestMemoryQueue_ReleaseKeepsRetryCount(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	q := NewMemoryQueue(Config{})
+	q.now = func() time.Time { return now }
+
+	job := models.NewJob("a", nil, models.JobPriorityNormal)
+	require.NoError(t, q.Enqueue(ctx, job))
+
+	_, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NoError(t, q.Release(ctx, job.ID, time.Second))
+
+	got, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, got, "released job is not ready before its delay")
+
+	now = now.Add(time.Second)
+	got, err = q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, job.ID, got.ID)
+	assert.Zero(t, got.RetryCount)
+
+	require.NoError(t, q.Ack(ctx, job.ID))
+	assert.True(t, errors.HasCode(q.Release(ctx, job.ID, 0), errors.CodeNotFound))
+}
+
+func TestMemoryQueue_StatsOldestAge(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	q := NewMemoryQueue(Config{})
+	q.now = func() time.Time { return now }
+
+	older := models.NewJob("a", nil, models.JobPriorityLow)
+	older.CreatedAt = now.Add(-time.Minute)
+	newer := models.NewJob("b", nil, models.JobPriorityCritical)
+	newer.CreatedAt = now.Add(-time.Second)
+	require.NoError(t, q.EnqueueBatch(ctx, []*models.Job{older, newer}))
+
+	stats, err := q.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, stats.OldestAge)
+
+	_, err = q.Dequeue(ctx)
+	require.NoError(t, err)
+	_, err = q.Dequeue(ctx)
+	require.NoError(t, err)
+
+	stats, err = q.Stats(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, stats.OldestAge, "in-flight jobs are not waiting")
+}
+
+func TestMemoryQueue_NackWithDelay(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	q := NewMemoryQueue(Config{})
+	q.now = func() time.Time { return now }
+
+	job := models.NewJob("a", nil, models.JobPriorityNormal)
+	job.MaxRetries = 2
+	require.NoError(t, q.Enqueue(ctx, job))
+
+	_, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NoError(t, q.NackWithDelay(ctx, job.ID, "rate limited", 10*time.Second))
+
+	now = now.Add(9 * time.Second)
+	got, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, got, "retry waits for the explicit delay")
+
+	now = now.Add(time.Second)
+	got, err = q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, 1, got.RetryCount)
+
+	// The explicit delay still counts against MaxRetries
+	require.NoError(t, q.NackWithDelay(ctx, job.ID, "rate limited", time.Second))
+	stats, err := q.Stats(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, stats.DeadLetter)
+}
+
+func TestMemoryQueue_UpdatePriority(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	q := NewMemoryQueue(Config{})
+	q.now = func() time.Time { return now }
+
+	stuck := models.NewJob("a", nil, models.JobPriorityLow)
+	other := models.NewJob("b", nil, models.JobPriorityLow)
+	delayed := models.NewJob("c", nil, models.JobPriorityLow)
+	delayed.ScheduledAt = ptr(now.Add(time.Minute))
+	require.NoError(t, q.EnqueueBatch(ctx, []*models.Job{stuck, other, delayed}))
+
+	waiting, err := q.Waiting(ctx, models.JobPriorityLow, 10)
+	require.NoError(t, err)
+	require.Len(t, waiting, 2, "delayed jobs are not ready")
+	assert.Equal(t, stuck.ID, waiting[0].ID)
+
+	updated, err := q.UpdatePriority(ctx, stuck.ID, models.JobPriorityHigh)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobPriorityHigh, updated.Priority)
+
+	_, err = q.UpdatePriority(ctx, delayed.ID, models.JobPriorityNormal)
+	require.NoError(t, err)
+
+	got, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, stuck.ID, got.ID, "the promoted job overtakes the rest")
+	assert.Equal(t, models.JobPriorityHigh, got.Priority)
+
+	_, err = q.UpdatePriority(ctx, stuck.ID, models.JobPriorityCritical)
+	assert.True(t, errors.HasCode(err, errors.CodeNotFound), "a dequeued job is not waiting")
+
+	now = now.Add(time.Minute)
+	got, err = q.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, delayed.ID, got.ID, "the delayed job became ready at its new priority")
+}
+
+func TestMemoryQueue_DelayedJobsBecomeReadyInScheduleOrder(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	q := NewMemoryQueue(Config{})
+	q.now = func() time.Time { return now }
+
+	var jobs []*models.Job
+	for _, delay := range []time.Duration{3, 1, 4, 2} {
+		job := models.NewJob("a", nil, models.JobPriorityNormal)
+		job.ScheduledAt = ptr(now.Add(delay * time.Second))
+		jobs = append(jobs, job)
+	}
+	require.NoError(t, q.EnqueueBatch(ctx, jobs))
+	require.NoError(t, q.Delete(ctx, jobs[2].ID))
+
+	now = now.Add(time.Hour)
+	for _, want := range []*models.Job{jobs[1], jobs[3], jobs[0]} {
+		got, err := q.Dequeue(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, want.ID, got.ID)
+	}
+
+	got, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, got, "the deleted job left the heap")
+}
+
+func TestMemoryQueue_HonoursMaxSize(t *testing.T) {
+	ctx := context.Background()
+	q := NewMemoryQueue(Config{MaxSize: 2})
+
+	first := models.NewJob("a", nil, models.JobPriorityNormal)
+	delayed := models.NewJob("a", nil, models.JobPriorityNormal)
+	delayed.ScheduledAt = ptr(time.Now().Add(time.Hour))
+	require.NoError(t, q.EnqueueBatch(ctx, []*models.Job{first, delayed}))
+
+	err := q.Enqueue(ctx, models.NewJob("a", nil, models.JobPriorityNormal))
+	assert.True(t, errors.HasCode(err, errors.CodeRateLimit))
+
+	_, err = q.Dequeue(ctx)
+	require.NoError(t, err)
+	err = q.EnqueueBatch(ctx, []*models.Job{
+		models.NewJob("a", nil, models.JobPriorityNormal),
+		models.NewJob("a", nil, models.JobPriorityNormal),
+	})
+	assert.True(t, errors.HasCode(err, errors.CodeRateLimit), "a batch is admitted whole or not at all")
+
+	require.NoError(t, q.Nack(ctx, first.ID, "boom"), "a retry is never refused")
+	size, err := q.Size(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), size)
+}
+
+func TestMemoryQueue_TimerExpiresVisibility(t *testing.T) {
+	ctx := context.Background()
+	q := NewMemoryQueue(Config{VisibilityTimeout: 20 * time.Millisecond})
+	t.Cleanup(func() { _ = q.Close() })
+
+	job := models.NewJob("a", nil, models.JobPriorityNormal)
+	require.NoError(t, q.Enqueue(ctx, job))
+	_, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+
+	// Only the timer touches the queue until the job is ready again
+	require.Eventually(t, func() bool {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		return len(q.processing) == 0 && len(q.ready[job.Priority]) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	got, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, 1, got.RetryCount, "the expired attempt is counted")
+}
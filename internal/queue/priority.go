@@ -0,0 +1,22 @@
+package queue
+
+import (
+	"context"
+
+	"task-queue/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Reprioritizer is implemented by queues that can change the priority of a
+// job that is still waiting
+type Reprioritizer interface {
+	// Waiting returns up to limit ready jobs of priority, longest waiting
+	// first
+	Waiting(ctx context.Context, priority models.JobPriority, limit int) ([]*models.Job, error)
+
+	// UpdatePriority moves a ready or delayed job to priority and returns
+	// it. A job that is not waiting, such as one a worker has dequeued, is
+	// reported with a CodeNotFound error.
+	UpdatePriority(ctx context.Context, jobID uuid.UUID, priority models.JobPriority) (*models.Job, error)
+}
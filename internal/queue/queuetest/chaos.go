@@ -0,0 +1,375 @@
+package queuetest
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// Faults configures what a ChaosQueue injects. The zero value injects
+// nothing.
+type Faults struct {
+	// ErrorRate is the probability that a call fails with a retryable
+	// CodeNetwork error before it reaches the queue
+	ErrorRate float64
+
+	// MethodErrorRates overrides ErrorRate for the methods it names, e.g.
+	// "Ack" or "Extend"
+	MethodErrorRates map[string]float64
+
+	// DuplicateRate is the probability that a dequeued job is delivered a
+	// second time by a later dequeue, as when its visibility timeout expires
+	// while it still runs
+	DuplicateRate float64
+
+	// DropAckRate is the probability that an Ack reports success without
+	// reaching the queue, leaving the job to its visibility timeout
+	DropAckRate float64
+
+	// AckDelay is how long every Ack waits before reaching the queue
+	AckDelay time.Duration
+
+	// Latency bounds the random delay added to every call
+	Latency time.Duration
+
+	// Seed seeds the fault decisions, so a failing run can be reproduced
+	Seed int64
+}
+
+// Injected counts the faults a ChaosQueue has injected
+type Injected struct {
+	Errors      int64
+	Duplicates  int64
+	DroppedAcks int64
+	DelayedAcks int64
+}
+
+// ChaosQueue is a queue.Queue that injects Faults into the calls it passes
+// to another queue. Faults are drawn from a generator seeded with
+// Faults.Seed: a single caller sees the same faults on every run, while
+// concurrent callers share the sequence in the order they arrive.
+type ChaosQueue struct {
+	queue  queue.Queue
+	faults Faults
+
+	mu       sync.Mutex
+	rand     *rand.Rand
+	pending  []*models.Job
+	injected Injected
+}
+
+var _ queue.Queue = (*ChaosQueue)(nil)
+
+// NewChaosQueue returns q with faults injected into its calls
+func NewChaosQueue(q queue.Queue, faults Faults) *ChaosQueue {
+	return &ChaosQueue{
+		queue:  q,
+		faults: faults,
+		rand:   rand.New(rand.NewSource(faults.Seed)),
+	}
+}
+
+// Injected returns the faults injected so far
+func (c *ChaosQueue) Injected() Injected {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.injected
+}
+
+// Enqueue adds a job to the queue
+func (c *ChaosQueue) Enqueue(ctx context.Context, job *models.Job) error {
+	if err := c.inject(ctx, "Enqueue"); err != nil {
+		return err
+	}
+
+	return c.queue.Enqueue(ctx, job)
+}
+
+// EnqueueBatch adds multiple jobs to the queue
+func (c *ChaosQueue) EnqueueBatch(ctx context.Context, jobs []*models.Job) error {
+	if err := c.inject(ctx, "EnqueueBatch"); err != nil {
+		return err
+	}
+
+	return c.queue.EnqueueBatch(ctx, jobs)
+}
+
+// Dequeue retrieves a pending duplicate, or else the next job from the
+// queue
+func (c *ChaosQueue) Dequeue(ctx context.Context) (*models.Job, error) {
+	return c.dequeue(ctx, "Dequeue", models.JobPriorityLow, c.queue.Dequeue)
+}
+
+// DequeueMinPriority retrieves a pending duplicate, or else the next job
+// whose priority is at least min
+func (c *ChaosQueue) DequeueMinPriority(ctx context.Context,
+	min models.JobPriority) (*models.Job, error) {
+	return c.dequeue(ctx, "DequeueMinPriority", min, func(ctx context.Context) (*models.Job, error) {
+		return c.queue.DequeueMinPriority(ctx, min)
+	})
+}
+
+// dequeue injects the faults of method, then returns a pending duplicate
+// of at least min priority or the job next returns, which may be
+// duplicated in turn
+func (c *ChaosQueue) dequeue(ctx context.Context, method string, min models.JobPriority,
+	next func(context.Context) (*models.Job, error)) (*models.Job, error) {
+	if err := c.inject(ctx, method); err != nil {
+		return nil, err
+	}
+
+	if job := c.popDuplicate(min); job != nil {
+		return job, nil
+	}
+
+	job, err := next(ctx)
+	if err != nil || job == nil {
+		return job, err
+	}
+
+	c.maybeDuplicate(job)
+	return job, nil
+}
+
+// DequeueBatch retrieves up to limit jobs, pending duplicates first
+func (c *ChaosQueue) DequeueBatch(ctx context.Context, limit int) ([]*models.Job, error) {
+	if err := c.inject(ctx, "DequeueBatch"); err != nil {
+		return nil, err
+	}
+
+	var jobs []*models.Job
+	for limit <= 0 || len(jobs) < limit {
+		job := c.popDuplicate(models.JobPriorityLow)
+		if job == nil {
+			break
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	rest := limit
+	if limit > 0 {
+		if rest -= len(jobs); rest == 0 {
+			return jobs, nil
+		}
+	}
+
+	dequeued, err := c.queue.DequeueBatch(ctx, rest)
+	for _, job := range dequeued {
+		c.maybeDuplicate(job)
+	}
+
+	return append(jobs, dequeued...), err
+}
+
+// Ack acknowledges successful job processing, unless the ack is dropped.
+// It waits AckDelay first.
+func (c *ChaosQueue) Ack(ctx context.Context, jobID uuid.UUID) error {
+	if err := c.inject(ctx, "Ack"); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	drop := c.roll(c.faults.DropAckRate)
+	if drop {
+		c.injected.DroppedAcks++
+	} else if c.faults.AckDelay > 0 {
+		c.injected.DelayedAcks++
+	}
+	c.mu.Unlock()
+
+	if drop {
+		return nil
+	}
+
+	if err := sleep(ctx, c.faults.AckDelay); err != nil {
+		return err
+	}
+
+	return c.queue.Ack(ctx, jobID)
+}
+
+// Nack returns a job to the queue for reprocessing
+func (c *ChaosQueue) Nack(ctx context.Context, jobID uuid.UUID, reason string) error {
+	if err := c.inject(ctx, "Nack"); err != nil {
+		return err
+	}
+
+	return c.queue.Nack(ctx, jobID, reason)
+}
+
+// NackError returns a failed job like Nack, or dead-letters it when err is
+// not retryable
+func (c *ChaosQueue) NackError(ctx context.Context, jobID uuid.UUID, err error) error {
+	if err := c.inject(ctx, "NackError"); err != nil {
+		return err
+	}
+
+	return c.queue.NackError(ctx, jobID, err)
+}
+
+// NackWithDelay returns a failed job to the queue, retried after delay
+func (c *ChaosQueue) NackWithDelay(ctx context.Context, jobID uuid.UUID, reason string,
+	delay time.Duration) error {
+	if err := c.inject(ctx, "NackWithDelay"); err != nil {
+		return err
+	}
+
+	return c.queue.NackWithDelay(ctx, jobID, reason, delay)
+}
+
+// Release returns a job being processed to the queue without a retry
+func (c *ChaosQueue) Release(ctx context.Context, jobID uuid.UUID, delay time.Duration) error {
+	if err := c.inject(ctx, "Release"); err != nil {
+		return err
+	}
+
+	return c.queue.Release(ctx, jobID, delay)
+}
+
+// Delete removes a job from the queue
+func (c *ChaosQueue) Delete(ctx context.Context, jobID uuid.UUID) error {
+	if err := c.inject(ctx, "Delete"); err != nil {
+		return err
+	}
+
+	return c.queue.Delete(ctx, jobID)
+}
+
+// Extend extends the visibility timeout for a job
+func (c *ChaosQueue) Extend(ctx context.Context, jobID uuid.UUID, duration time.Duration) error {
+	if err := c.inject(ctx, "Extend"); err != nil {
+		return err
+	}
+
+	return c.queue.Extend(ctx, jobID, duration)
+}
+
+// Size returns the number of jobs in the queue
+func (c *ChaosQueue) Size(ctx context.Context) (int64, error) {
+	if err := c.inject(ctx, "Size"); err != nil {
+		return 0, err
+	}
+
+	return c.queue.Size(ctx)
+}
+
+// Clear removes all jobs from the queue and drops pending duplicates
+func (c *ChaosQueue) Clear(ctx context.Context) error {
+	if err := c.inject(ctx, "Clear"); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.pending = nil
+	c.mu.Unlock()
+
+	return c.queue.Clear(ctx)
+}
+
+// Close closes the wrapped queue. No fault is injected.
+func (c *ChaosQueue) Close() error {
+	return c.queue.Close()
+}
+
+// Stats returns queue statistics
+func (c *ChaosQueue) Stats(ctx context.Context) (*queue.QueueStats, error) {
+	if err := c.inject(ctx, "Stats"); err != nil {
+		return nil, err
+	}
+
+	return c.queue.Stats(ctx)
+}
+
+// inject waits a random latency and fails the call to method at its error
+// rate
+func (c *ChaosQueue) inject(ctx context.Context, method string) error {
+	c.mu.Lock()
+	var latency time.Duration
+	if c.faults.Latency > 0 {
+		latency = time.Duration(c.rand.Int63n(int64(c.faults.Latency)))
+	}
+
+	rate, ok := c.faults.MethodErrorRates[method]
+	if !ok {
+		rate = c.faults.ErrorRate
+	}
+
+	fail := c.roll(rate)
+	if fail {
+		c.injected.Errors++
+	}
+	c.mu.Unlock()
+
+	if err := sleep(ctx, latency); err != nil {
+		return err
+	}
+
+	if fail {
+		return errors.Newf("chaos: injected %s failure", method).
+			WithCode(errors.CodeNetwork).
+			WithRetryable(true).
+			WithOp("queuetest." + method)
+	}
+
+	return nil
+}
+
+// maybeDuplicate holds a copy of job for a later dequeue at DuplicateRate
+func (c *ChaosQueue) maybeDuplicate(job *models.Job) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.roll(c.faults.DuplicateRate) {
+		duplicate := *job
+		c.pending = append(c.pending, &duplicate)
+	}
+}
+
+// popDuplicate returns the oldest pending duplicate of at least min
+// priority, or nil
+func (c *ChaosQueue) popDuplicate(min models.JobPriority) *models.Job {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, job := range c.pending {
+		if job.Priority >= min {
+			c.pending = append(c.pending[:i], c.pending[i+1:]...)
+			c.injected.Duplicates++
+			return job
+		}
+	}
+
+	return nil
+}
+
+// roll reports true with probability rate. The caller holds c.mu.
+func (c *ChaosQueue) roll(rate float64) bool {
+	return rate > 0 && c.rand.Float64() < rate
+}
+
+// sleep waits for d or until ctx is canceled
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return errors.FromContext(ctx.Err()).WithOp("queuetest.sleep")
+
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,139 @@
+package queuetest
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newChaos returns a ChaosQueue with faults over a memory queue holding one
+// job, and the memory queue
+func newChaos(t *testing.T, faults Faults) (*ChaosQueue, *queue.MemoryQueue, *models.Job) {
+	t.Helper()
+
+	inner := queue.NewMemoryQueue(queue.Config{})
+	job := models.NewJob("email_send", json.RawMessage(`{}`), models.JobPriorityNormal)
+	require.NoError(t, inner.Enqueue(context.Background(), job))
+	return NewChaosQueue(inner, faults), inner, job
+}
+
+// processing returns how many jobs q has out for processing
+func processing(t *testing.T, q queue.Queue) int64 {
+	t.Helper()
+
+	stats, err := q.Stats(context.Background())
+	require.NoError(t, err)
+	return stats.Processing
+}
+
+func TestChaosQueue_NoFaultsPassesThrough(t *testing.T) {
+	ctx := context.Background()
+	q, inner, job := newChaos(t, Faults{})
+
+	got, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, job.ID, got.ID)
+
+	require.NoError(t, q.Ack(ctx, job.ID))
+	assert.Zero(t, processing(t, inner))
+	assert.Equal(t, Injected{}, q.Injected())
+}
+
+func TestChaosQueue_InjectsMethodErrors(t *testing.T) {
+	ctx := context.Background()
+	q, inner, job := newChaos(t, Faults{MethodErrorRates: map[string]float64{"Ack": 1}})
+
+	_, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+
+	err = q.Ack(ctx, job.ID)
+	require.Error(t, err)
+	assert.True(t, errors.HasCode(err, errors.CodeNetwork))
+	retryable, _ := errors.IsRetryable(err)
+	assert.True(t, retryable)
+	assert.Equal(t, int64(1), processing(t, inner), "a failed ack never reached the queue")
+	assert.Equal(t, int64(1), q.Injected().Errors)
+}
+
+func TestChaosQueue_DropsAcks(t *testing.T) {
+	ctx := context.Background()
+	q, inner, job := newChaos(t, Faults{DropAckRate: 1})
+
+	_, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NoError(t, q.Ack(ctx, job.ID))
+
+	assert.Equal(t, int64(1), processing(t, inner), "the job waits for its visibility timeout")
+	assert.Equal(t, int64(1), q.Injected().DroppedAcks)
+}
+
+func TestChaosQueue_DelaysAcks(t *testing.T) {
+	ctx := context.Background()
+	q, _, job := newChaos(t, Faults{AckDelay: 20 * time.Millisecond})
+
+	_, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+
+	start := time.Now()
+	require.NoError(t, q.Ack(ctx, job.ID))
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Equal(t, int64(1), q.Injected().DelayedAcks)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	err = q.Ack(cancelled, job.ID)
+	assert.True(t, errors.HasCode(err, errors.CodeCanceled))
+}
+
+func TestChaosQueue_DuplicatesDeliveries(t *testing.T) {
+	ctx := context.Background()
+	q, _, job := newChaos(t, Faults{DuplicateRate: 1})
+
+	first, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	second, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, second)
+	assert.Equal(t, job.ID, first.ID)
+	assert.Equal(t, job.ID, second.ID)
+	assert.NotSame(t, first, second)
+
+	none, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, none, "a duplicate is not duplicated again")
+	assert.Equal(t, int64(1), q.Injected().Duplicates)
+
+	require.NoError(t, q.Ack(ctx, first.ID))
+	err = q.Ack(ctx, second.ID)
+	assert.True(t, errors.HasCode(err, errors.CodeNotFound), "the duplicate's ack finds the job settled")
+}
+
+func TestChaosQueue_SeedReproducesFaults(t *testing.T) {
+	ctx := context.Background()
+	faults := Faults{ErrorRate: 0.5, Seed: 42}
+
+	pattern := func() []bool {
+		q, _, _ := newChaos(t, faults)
+		failed := make([]bool, 64)
+		for i := range failed {
+			_, err := q.Size(ctx)
+			failed[i] = err != nil
+		}
+
+		return failed
+	}
+
+	first := pattern()
+	assert.Equal(t, first, pattern())
+	assert.Contains(t, first, true)
+	assert.Contains(t, first, false)
+}
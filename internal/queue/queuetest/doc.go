@@ -0,0 +1,29 @@
+// Package queuetest provides tools for testing code built on the queue
+// package, in the spirit of net/http/httptest.
+//
+// ChaosQueue wraps any queue.Queue and injects the faults a production
+// backend shows now and then: failed calls, latency, delayed and dropped
+// acks, and duplicate deliveries. Run a worker pool, or a single handler,
+// against it to check that they hold up under at-least-once delivery:
+//
+//	q := queuetest.NewChaosQueue(queue.NewMemoryQueue(queue.Config{
+//	    VisibilityTimeout: time.Second,
+//	}), queuetest.Faults{
+//	    ErrorRate:     0.05,
+//	    DuplicateRate: 0.02,
+//	    DropAckRate:   0.02,
+//	    Latency:       2 * time.Millisecond,
+//	    Seed:          seed,
+//	})
+//	pool := worker.New(q, cfg, log)
+//
+// Log the seed of a failing run: the same seed replays the same fault
+// decisions, in the order the calls arrive. Injected reports how many
+// faults of each kind were injected, to bound the redeliveries a test
+// should expect.
+//
+// The worker package's soak tests run the pool against a ChaosQueue over
+// thousands of jobs. They sit behind the chaos build tag:
+//
+//	go test -tags=chaos ./internal/worker/
+package queuetest
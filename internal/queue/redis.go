@@ -4,75 +4,101 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"task-queue/internal/models"
 	"task-queue/pkg/errors"
 	"task-queue/pkg/logger"
+	"task-queue/pkg/retry"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
-// RedisQueue implements Queue interface using Redis
+// RedisQueue implements Queue interface using Redis. Its keys share the
+// hash tag of its name, so it works on cluster clients too.
 type RedisQueue struct {
-	client    *redis.Client
+	client    redis.UniversalClient
 	config    Config
 	logger    logger.Logger
 	keyPrefix string
+	retrier   *retry.Retrier
+
+	mu   sync.Mutex
+	held map[uuid.UUID]string
 }
 
 // NewRedisQueue creates a new Redis-based queue
-func NewRedisQueue(client *redis.Client, config Config, log logger.Logger) (*RedisQueue, error) {
+func NewRedisQueue(client redis.UniversalClient, config Config, log logger.Logger) (*RedisQueue, error) {
 	if client == nil {
 		return nil, errors.New("redis client is required").
-			WithCode(errors.CodeConfiguration)
+			WithCode(errors.CodeConfiguration).
+			WithOp("queue.NewRedisQueue")
 	}
 
 	if config.Name == "" {
 		config.Name = "default"
 	}
 
+	if config.Audit == nil {
+		config.Audit = logger.NopAudit()
+	}
+
 	return &RedisQueue{
 		client:    client,
 		config:    config,
 		logger:    log.Named("redis-queue"),
-		keyPrefix: fmt.Sprintf("queue:%s", config.Name),
+		keyPrefix: fmt.Sprintf("queue:{%s}", config.Name),
+		retrier:   retry.Network(),
+		held:      make(map[uuid.UUID]string),
 	}, nil
 }
 
 // Enqueue adds a job to the queue
 func (q *RedisQueue) Enqueue(ctx context.Context, job *models.Job) error {
+	if err := q.enqueue(ctx, job); err != nil {
+		return err
+	}
+
+	q.config.Audit.JobEvent(ctx, logger.AuditJobCreated, job,
+		"priority", job.Priority,
+		"queue", q.config.Name,
+	)
+
+	return nil
+}
+
+// enqueue stores a job in its priority list, or in the delayed set when it
+// is scheduled for later
+func (q *RedisQueue) enqueue(ctx context.Context, job *models.Job) error {
 	if job == nil {
-		return errors.New("job is nil").WithCode(errors.CodeValidation)
+		return errors.Validation("job is nil").
+			WithKey("job.nil").
+			WithOp("queue.Enqueue")
 	}
 
-	data, err := json.Marshal(job)
+	to, err := q.placement(job, "queue.Enqueue")
 	if err != nil {
-		return errors.Wrap(err, "failed to marshal job").
-			WithCode(errors.CodeSerialization)
+		return err
 	}
 
-	queueKey := q.getQueueKey(job.Priority)
-	if job.ScheduledAt != nil && job.ScheduledAt.After(time.Now()) {
-		score := float64(job.ScheduledAt.Unix())
-		err = q.client.ZAdd(ctx, q.getDelayedKey(), redis.Z{
-			Score:  score,
-			Member: data,
-		}).Err()
+	if to.kind == "zset" {
+		err = q.client.ZAdd(ctx, to.key, redis.Z{Score: to.score, Member: to.data}).Err()
 	} else {
-		err = q.client.RPush(ctx, queueKey, data).Err()
+		err = q.client.RPush(ctx, to.key, to.data).Err()
 	}
 
 	if err != nil {
-		return errors.Wrap(err, "failed to enqueue job").
-			WithCode(errors.CodeInternal)
+		return errors.Wrap(errors.FromRedis(err), "failed to enqueue job").
+			WithOp("queue.Enqueue")
 	}
 
 	q.updateEnqueueStats(ctx)
 	q.logger.Debug("job enqueued",
-		"job_id", job.ID,
-		"type", job.Type,
+		logger.UUID("job_id", job.ID),
+		logger.String("type", job.Type),
 		"priority", job.Priority,
+		"payload_keys", logger.Lazy(func() any { return job.PayloadKeys() }),
 	)
 
 	return nil
@@ -84,15 +110,38 @@ func (q *RedisQueue) EnqueueBatch(ctx context.Context, jobs []*models.Job) error
 		return nil
 	}
 
-	pipe := q.client.Pipeline()
+	encoded := make([][]byte, len(jobs))
+	var errs []error
+	for i, job := range jobs {
+		if job == nil {
+			errs = append(errs, errors.Validation("job at index %d is nil", i).
+				WithKey("job.nil_in_batch", i).
+				WithMetadata("index", i).
+				WithOp("queue.EnqueueBatch"))
+			continue
+		}
 
-	for _, job := range jobs {
 		data, err := json.Marshal(job)
 		if err != nil {
-			return errors.Wrapf(err, "failed to marshal job %s", job.ID).
-				WithCode(errors.CodeSerialization)
+			errs = append(errs, errors.Wrapf(err, "failed to marshal job %s", job.ID).
+				WithCode(errors.CodeSerialization).
+				WithMetadata("index", i).
+				WithOp("queue.EnqueueBatch"))
+			continue
 		}
 
+		encoded[i] = data
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return errors.Wrap(err, "invalid job batch").
+			WithOp("queue.EnqueueBatch")
+	}
+
+	pipe := q.client.Pipeline()
+
+	for i, job := range jobs {
+		data := encoded[i]
 		queueKey := q.getQueueKey(job.Priority)
 
 		if job.ScheduledAt != nil && job.ScheduledAt.After(time.Now()) {
@@ -108,71 +157,94 @@ func (q *RedisQueue) EnqueueBatch(ctx context.Context, jobs []*models.Job) error
 
 	_, err := pipe.Exec(ctx)
 	if err != nil {
-		return errors.Wrap(err, "failed to enqueue batch").WithCode(errors.CodeInternal)
+		return errors.Wrap(errors.FromRedis(err), "failed to enqueue batch").
+			WithOp("queue.EnqueueBatch")
 	}
 
 	q.updateEnqueueStats(ctx)
 	q.logger.Debug("batch enqueued", "count", len(jobs))
+	for _, job := range jobs {
+		q.config.Audit.JobEvent(ctx, logger.AuditJobCreated, job,
+			"priority", job.Priority,
+			"queue", q.config.Name,
+		)
+	}
 
 	return nil
 }
 
 // Dequeue retrieves the next job from the queue
 func (q *RedisQueue) Dequeue(ctx context.Context) (*models.Job, error) {
+	return q.dequeue(ctx, models.JobPriorityLow)
+}
+
+// DequeueMinPriority retrieves the next job whose priority is at least min
+func (q *RedisQueue) DequeueMinPriority(ctx context.Context,
+	min models.JobPriority) (*models.Job, error) {
+	return q.dequeue(ctx, min)
+}
+
+// dequeue moves the next job of the priority lists, from critical down to
+// min, to the in-flight hash in one atomic step. It does not wait for a job
+// when the lists are empty.
+func (q *RedisQueue) dequeue(ctx context.Context, min models.JobPriority) (*models.Job, error) {
+	paused, err := q.paused(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if paused {
+		return nil, nil
+	}
+
 	if err := q.processScheduledJobs(ctx); err != nil {
 		q.logger.Warn("failed to process scheduled jobs", "error", err)
 	}
 
-	priorities := []models.JobPriority{
+	keys := make([]string, 0, 5)
+	for _, priority := range []models.JobPriority{
 		models.JobPriorityCritical,
 		models.JobPriorityHigh,
 		models.JobPriorityNormal,
 		models.JobPriorityLow,
-	}
-
-	for _, priority := range priorities {
-		queueKey := q.getQueueKey(priority)
-		processingKey := q.getProcessingKey()
-		result, err := q.client.BLMove(ctx,
-			queueKey,
-			processingKey,
-			"LEFT",
-			"RIGHT",
-			100*time.Millisecond,
-		).Result()
-
-		if err == redis.Nil {
-			continue
-		}
-
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to dequeue job").
-				WithCode(errors.CodeInternal)
+	} {
+		if priority < min {
+			break
 		}
 
-		var job models.Job
-		if err := json.Unmarshal([]byte(result), &job); err != nil {
-			return nil, errors.Wrap(err, "failed to unmarshal job").
-				WithCode(errors.CodeSerialization)
-		}
+		keys = append(keys, q.getQueueKey(priority))
+	}
 
-		if err := q.setVisibilityTimeout(ctx, &job); err != nil {
-			q.logger.Warn("failed to set visibility timeout",
-				"job_id", job.ID,
-				"error", err,
-			)
-		}
+	result, err := dequeueScript.Run(ctx, q.client, append(keys, q.getInFlightKey(), q.getLeasesKey()),
+		q.config.VisibilityTimeout.Milliseconds()).Text()
+	if err == redis.Nil {
+		return nil, nil
+	}
 
-		q.updateDequeueStats(ctx)
-		q.logger.Debug("job dequeued",
-			"job_id", job.ID,
-			"type", job.Type,
-		)
+	if err != nil {
+		return nil, errors.Wrap(errors.FromRedis(err), "failed to dequeue job").
+			WithOp("queue.Dequeue")
+	}
 
-		return &job, nil
+	var job models.Job
+	if err := json.Unmarshal([]byte(result), &job); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal job").
+			WithCode(errors.CodeSerialization).
+			WithOp("queue.Dequeue")
 	}
 
-	return nil, nil
+	q.hold(job.ID, result)
+	q.updateDequeueStats(ctx)
+	q.logger.Debug("job dequeued",
+		logger.UUID("job_id", job.ID),
+		logger.String("type", job.Type),
+		"payload_keys", logger.Lazy(func() any { return job.PayloadKeys() }),
+	)
+	q.config.Audit.JobEvent(ctx, logger.AuditJobStarted, &job,
+		"queue", q.config.Name,
+	)
+
+	return &job, nil
 }
 
 // DequeueBatch retrieves multiple jobs from the queue
@@ -198,158 +270,234 @@ func (q *RedisQueue) DequeueBatch(ctx context.Context, limit int) ([]*models.Job
 	return jobs, nil
 }
 
-// Ack acknowledges successful job processing
+// Ack acknowledges successful job processing. It is one atomic round trip
+// whatever the number of jobs being processed. A job this queue handed out
+// is only acknowledged as it was handed out, not once it was redelivered.
 func (q *RedisQueue) Ack(ctx context.Context, jobID uuid.UUID) error {
-	processingKey := q.getProcessingKey()
-	jobs, err := q.client.LRange(ctx, processingKey, 0, -1).Result()
+	data, err := q.settle(ctx, jobID, claim{held: q.holding(jobID)}, destination{}, "queue.Ack")
 	if err != nil {
-		return errors.Wrap(err, "failed to get processing jobs").WithCode(errors.CodeInternal)
+		return err
 	}
 
-	for _, jobData := range jobs {
-		var job models.Job
-		if err := json.Unmarshal([]byte(jobData), &job); err != nil {
-			continue
-		}
+	var job models.Job
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		q.logger.Warn("acknowledged undecodable job", "job_id", jobID, "error", err)
+		job.ID = jobID
+	}
 
-		if job.ID == jobID {
-			count, err := q.client.LRem(ctx, processingKey, 1, jobData).Result()
-			if err != nil {
-				return errors.Wrap(err, "failed to remove job from processing").
-					WithCode(errors.CodeInternal)
-			}
+	q.logger.Debug("job acknowledged", "job_id", jobID)
+	q.config.Audit.JobEvent(ctx, logger.AuditJobCompleted, &job,
+		"queue", q.config.Name,
+	)
 
-			if count > 0 {
-				q.clearVisibilityTimeout(ctx, jobID)
-				q.logger.Debug("job acknowledged", "job_id", jobID)
+	return nil
+}
 
-				return nil
-			}
-		}
+// Nack returns a job to the queue for reprocessing
+func (q *RedisQueue) Nack(ctx context.Context, jobID uuid.UUID, reason string) error {
+	return q.nack(ctx, jobID, reason, true, backoffDelay)
+}
+
+// NackWithDelay returns a failed job to the queue like Nack, but retries it
+// after delay instead of the backoff
+func (q *RedisQueue) NackWithDelay(ctx context.Context, jobID uuid.UUID, reason string,
+	delay time.Duration) error {
+	return q.nack(ctx, jobID, reason, true, max(delay, 0))
+}
+
+// NackError returns a failed job to the queue like Nack, but dead-letters it
+// immediately when the error is classified as non-retryable
+func (q *RedisQueue) NackError(ctx context.Context, jobID uuid.UUID, jobErr error) error {
+	retryable, known := errors.IsRetryable(jobErr)
+
+	reason := "unknown error"
+	if jobErr != nil {
+		reason = jobErr.Error()
 	}
 
-	return errors.Newf("job %s not found in processing queue", jobID).
-		WithCode(errors.CodeNotFound)
+	return q.nack(ctx, jobID, reason, retryable || !known, backoffDelay)
 }
 
-// Nack returns a job to the queue for reprocessing
-func (q *RedisQueue) Nack(ctx context.Context, jobID uuid.UUID, reason string) error {
-	processingKey := q.getProcessingKey()
-	jobs, err := q.client.LRange(ctx, processingKey, 0, -1).Result()
+// nack fails the job with jobID being processed, as fail describes
+func (q *RedisQueue) nack(ctx context.Context, jobID uuid.UUID, reason string,
+	retry bool, delay time.Duration) error {
+	held, job, err := q.inFlight(ctx, jobID, "queue.Nack")
 	if err != nil {
-		return errors.Wrap(err, "failed to get processing jobs").
-			WithCode(errors.CodeInternal)
+		return err
 	}
 
-	for _, jobData := range jobs {
-		var job models.Job
-		if err := json.Unmarshal([]byte(jobData), &job); err != nil {
-			continue
+	if _, err := q.fail(ctx, claim{held: held}, job, reason, retry, delay, "queue.Nack"); err != nil {
+		return err
+	}
+
+	q.logger.Debug("job nacked",
+		"job_id", jobID,
+		"retry_count", job.RetryCount,
+		"retryable", retry,
+		"reason", reason,
+	)
+
+	return nil
+}
+
+// fail counts a failure of job, in processing as c requires, and settles
+// it: rescheduled after delay, or with backoff for backoffDelay, or, when
+// retry is false or its retries are exhausted, dead-lettered, which it
+// reports. The job moves in one atomic step, which fails when the job was
+// settled or redelivered since it was read.
+func (q *RedisQueue) fail(ctx context.Context, c claim, job *models.Job, reason string,
+	retry bool, delay time.Duration, op string) (bool, error) {
+	job.RetryCount++
+	job.Error = &reason
+	job.UpdatedAt = time.Now()
+
+	var to destination
+	deadLetter := !retry || job.RetryCount >= job.MaxRetries
+	if deadLetter {
+		job.Status = models.JobStatusDead
+		data, err := json.Marshal(job)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to marshal job").
+				WithCode(errors.CodeSerialization).
+				WithOp(op)
 		}
 
-		if job.ID == jobID {
-			job.RetryCount++
-			job.Error = &reason
-			job.UpdatedAt = time.Now()
-
-			if job.RetryCount >= job.MaxRetries {
-				if err := q.moveToDeadLetter(ctx, &job); err != nil {
-					return err
-				}
-			} else {
-				backoffDuration := time.Duration(job.RetryCount) * time.Minute
-				job.ScheduledAt = ptr(time.Now().Add(backoffDuration))
-
-				if err := q.Enqueue(ctx, &job); err != nil {
-					return err
-				}
-			}
-
-			_, err := q.client.LRem(ctx, processingKey, 1, jobData).Result()
-			if err != nil {
-				return errors.Wrap(err, "failed to remove job from processing").
-					WithCode(errors.CodeInternal)
-			}
-
-			q.logger.Debug("job nacked",
-				"job_id", jobID,
-				"retry_count", job.RetryCount,
-				"reason", reason,
-			)
-
-			return nil
+		to = destination{kind: "list", key: q.getDeadLetterKey(), data: data}
+	} else {
+		if delay == backoffDelay {
+			delay = time.Duration(job.RetryCount) * time.Minute
+		}
+
+		job.ScheduledAt = ptr(time.Now().Add(delay))
+		var err error
+		if to, err = q.placement(job, op); err != nil {
+			return false, err
 		}
 	}
 
-	return errors.Newf("job %s not found in processing queue", jobID).
-		WithCode(errors.CodeNotFound)
+	if _, err := q.settle(ctx, job.ID, c, to, op); err != nil {
+		return false, err
+	}
+
+	if !deadLetter {
+		q.updateEnqueueStats(ctx)
+	}
+
+	q.config.Audit.JobEvent(ctx, logger.AuditJobFailed, job,
+		"queue", q.config.Name,
+		"retry_count", job.RetryCount,
+		"retryable", retry,
+		"reason", reason,
+	)
+
+	if deadLetter {
+		q.config.Audit.JobEvent(ctx, logger.AuditJobDeadLettered, job,
+			"queue", q.config.Name,
+			"retry_count", job.RetryCount,
+		)
+	}
+
+	return deadLetter, nil
+}
+
+// Release returns a job being processed to the queue, ready again after
+// delay, without counting a retry
+func (q *RedisQueue) Release(ctx context.Context, jobID uuid.UUID,
+	delay time.Duration) error {
+	held, job, err := q.inFlight(ctx, jobID, "queue.Release")
+	if err != nil {
+		return err
+	}
+
+	job.UpdatedAt = time.Now()
+	job.ScheduledAt = nil
+	if delay > 0 {
+		job.ScheduledAt = ptr(time.Now().Add(delay))
+	}
+
+	to, err := q.placement(job, "queue.Release")
+	if err != nil {
+		return err
+	}
+
+	if _, err := q.settle(ctx, jobID, claim{held: held}, to, "queue.Release"); err != nil {
+		return err
+	}
+
+	q.updateEnqueueStats(ctx)
+	q.logger.Debug("job released", "job_id", jobID, "delay", delay)
+	q.config.Audit.JobEvent(ctx, logger.AuditJobRequeued, job,
+		"queue", q.config.Name,
+		"delay", delay,
+	)
+
+	return nil
 }
 
-// Delete removes a job from the queue
+// Delete removes a job from the queue, wherever it is. A job being
+// processed is found in one lookup, and is only deleted as this queue
+// handed it out, when it did; a waiting or dead-lettered one is searched
+// for by Redis, in one atomic round trip.
 func (q *RedisQueue) Delete(ctx context.Context, jobID uuid.UUID) error {
+	_, err := q.settle(ctx, jobID, claim{held: q.holding(jobID)}, destination{}, "queue.Delete")
+	if err == nil {
+		q.logger.Debug("job deleted", "job_id", jobID)
+		return nil
+	}
+
+	if !errors.HasCode(err, errors.CodeNotFound) {
+		return err
+	}
+
 	keys := []string{
 		q.getQueueKey(models.JobPriorityLow),
 		q.getQueueKey(models.JobPriorityNormal),
 		q.getQueueKey(models.JobPriorityHigh),
 		q.getQueueKey(models.JobPriorityCritical),
-		q.getProcessingKey(),
-		q.getDelayedKey(),
 		q.getDeadLetterKey(),
+		q.getDelayedKey(),
 	}
 
-	for _, key := range keys {
-		if key != q.getDelayedKey() {
-			jobs, err := q.client.LRange(ctx, key, 0, -1).Result()
-			if err != nil {
-				continue
-			}
-
-			for _, jobData := range jobs {
-				var job models.Job
-				if err := json.Unmarshal([]byte(jobData), &job); err != nil {
-					continue
-				}
-
-				if job.ID == jobID {
-					_, err := q.client.LRem(ctx, key, 1, jobData).Result()
-					if err == nil {
-						q.logger.Debug("job deleted", "job_id", jobID)
-						return nil
-					}
-				}
-			}
-		} else {
-			jobs, err := q.client.ZRange(ctx, key, 0, -1).Result()
-			if err != nil {
-				continue
-			}
-
-			for _, jobData := range jobs {
-				var job models.Job
-				if err := json.Unmarshal([]byte(jobData), &job); err != nil {
-					continue
-				}
-
-				if job.ID == jobID {
-					_, err := q.client.ZRem(ctx, key, jobData).Result()
-
-					if err == nil {
-						q.logger.Debug("job deleted", "job_id", jobID)
-						return nil
-					}
-				}
-			}
-		}
+	deleted, err := deleteWaitingScript.Run(ctx, q.client, keys, jobID.String()).Int()
+	if err != nil {
+		return errors.Wrap(errors.FromRedis(err), "failed to delete job").
+			WithOp("queue.Delete")
+	}
+
+	if deleted == 0 {
+		return errors.NotFound("job %s not found", jobID).
+			WithKey("job.not_found", jobID).
+			WithOp("queue.Delete")
 	}
 
-	return errors.Newf("job %s not found", jobID).WithCode(errors.CodeNotFound)
+	q.logger.Debug("job deleted", "job_id", jobID)
+	return nil
 }
 
-// Extend extends the visibility timeout for a job
+// Extend extends the visibility timeout for a job. The call is idempotent
+// and on the lease's critical path, so it gets a few quick retries. A job
+// whose lease has already expired or been released, or that was
+// redelivered since this queue handed it out, is reported with a
+// CodeNotFound error.
 func (q *RedisQueue) Extend(ctx context.Context, jobID uuid.UUID,
 	duration time.Duration) error {
-	key := q.getVisibilityKey(jobID)
-	return q.client.Expire(ctx, key, duration).Err()
+	keys := []string{q.getInFlightKey(), q.getLeasesKey()}
+	held := q.holding(jobID)
+	return retry.Quick().DoContext(ctx, func(ctx context.Context) error {
+		extended, err := redisResult(extendScript.Run(ctx, q.client, keys,
+			jobID.String(), held, duration.Milliseconds()).Int())
+		if err != nil {
+			return err
+		}
+
+		if extended == 0 {
+			return errors.NotFound("job %s has no visibility lease", jobID).
+				WithKey("job.not_in_processing", jobID).
+				WithOp("queue.Extend")
+		}
+
+		return nil
+	})
 }
 
 // Size returns the number of jobs in the queue
@@ -363,20 +511,20 @@ func (q *RedisQueue) Size(ctx context.Context) (int64, error) {
 	}
 
 	for _, priority := range priorities {
-		count, err := q.client.LLen(ctx, q.getQueueKey(priority)).Result()
+		count, err := q.count(ctx, q.client.LLen, q.getQueueKey(priority))
 		if err != nil {
-			return 0, errors.Wrap(err, "failed to get queue size").
-				WithCode(errors.CodeInternal)
+			return 0, errors.Wrap(errors.FromRedis(err), "failed to get queue size").
+				WithOp("queue.Size")
 		}
 
 		total += count
 	}
 
 	// Add delayed jobs
-	delayedCount, err := q.client.ZCard(ctx, q.getDelayedKey()).Result()
+	delayedCount, err := q.count(ctx, q.client.ZCard, q.getDelayedKey())
 	if err != nil {
-		return 0, errors.Wrap(err, "failed to get delayed queue size").
-			WithCode(errors.CodeInternal)
+		return 0, errors.Wrap(errors.FromRedis(err), "failed to get delayed queue size").
+			WithOp("queue.Size")
 	}
 
 	total += delayedCount
@@ -391,13 +539,15 @@ func (q *RedisQueue) Clear(ctx context.Context) error {
 		q.getQueueKey(models.JobPriorityNormal),
 		q.getQueueKey(models.JobPriorityHigh),
 		q.getQueueKey(models.JobPriorityCritical),
-		q.getProcessingKey(),
+		q.getInFlightKey(),
+		q.getLeasesKey(),
 		q.getDelayedKey(),
 	}
 
 	for _, key := range keys {
 		if err := q.client.Del(ctx, key).Err(); err != nil {
-			return errors.Wrapf(err, "failed to delete key %s", key).WithCode(errors.CodeInternal)
+			return errors.Wrapf(errors.FromRedis(err), "failed to delete key %s", key).
+				WithOp("queue.Clear")
 		}
 	}
 
@@ -405,6 +555,17 @@ func (q *RedisQueue) Clear(ctx context.Context) error {
 	return nil
 }
 
+// Ping checks that Redis answers, without retrying, so health checks see
+// an outage at once
+func (q *RedisQueue) Ping(ctx context.Context) error {
+	if err := q.client.Ping(ctx).Err(); err != nil {
+		return errors.Wrap(errors.FromRedis(err), "redis is unreachable").
+			WithOp("queue.Ping")
+	}
+
+	return nil
+}
+
 // Close closes the queue connection
 func (q *RedisQueue) Close() error {
 	// Redis client is managed externally
@@ -423,27 +584,36 @@ func (q *RedisQueue) Stats(ctx context.Context) (*QueueStats, error) {
 	}
 
 	stats.Size = size
-	processingCount, err := q.client.LLen(ctx, q.getProcessingKey()).Result()
+	stats.Processing, err = q.count(ctx, q.client.HLen, q.getInFlightKey())
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get processing count").
-			WithCode(errors.CodeInternal)
+		return nil, errors.Wrap(errors.FromRedis(err), "failed to get processing count").
+			WithOp("queue.Stats")
 	}
 
-	stats.Processing = processingCount
-	delayedCount, err := q.client.ZCard(ctx, q.getDelayedKey()).Result()
+	delayedCount, err := q.count(ctx, q.client.ZCard, q.getDelayedKey())
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get delayed count").
-			WithCode(errors.CodeInternal)
+		return nil, errors.Wrap(errors.FromRedis(err), "failed to get delayed count").
+			WithOp("queue.Stats")
 	}
 
 	stats.Delayed = delayedCount
-	deadLetterCount, err := q.client.LLen(ctx, q.getDeadLetterKey()).Result()
+	deadLetterCount, err := q.count(ctx, q.client.LLen, q.getDeadLetterKey())
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get dead letter count").
-			WithCode(errors.CodeInternal)
+		return nil, errors.Wrap(errors.FromRedis(err), "failed to get dead letter count").
+			WithOp("queue.Stats")
 	}
 
 	stats.DeadLetter = deadLetterCount
+	oldestAge, err := q.oldestAge(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.OldestAge = oldestAge
+	if stats.Paused, err = q.paused(ctx); err != nil {
+		return nil, err
+	}
+
 	statsKey := fmt.Sprintf("%s:stats", q.keyPrefix)
 	statsData, err := q.client.HGetAll(ctx, statsKey).Result()
 	if err == nil && len(statsData) > 0 {
@@ -461,12 +631,77 @@ func (q *RedisQueue) Stats(ctx context.Context) (*QueueStats, error) {
 
 // Helper methods
 
+// oldestAge returns how long the job at the head of the oldest priority
+// list has been ready
+func (q *RedisQueue) oldestAge(ctx context.Context) (time.Duration, error) {
+	var oldest time.Duration
+	for _, priority := range []models.JobPriority{
+		models.JobPriorityLow,
+		models.JobPriorityNormal,
+		models.JobPriorityHigh,
+		models.JobPriorityCritical,
+	} {
+		data, err := q.client.LIndex(ctx, q.getQueueKey(priority), 0).Result()
+		if err == redis.Nil {
+			continue
+		}
+
+		if err != nil {
+			return 0, errors.Wrap(errors.FromRedis(err), "failed to read queue head").
+				WithOp("queue.Stats")
+		}
+
+		var job models.Job
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			continue
+		}
+
+		oldest = max(oldest, time.Since(readySince(&job)))
+	}
+
+	return oldest, nil
+}
+
+// readySince returns when job became ready: its schedule time, or its
+// creation time when it was never delayed
+func readySince(job *models.Job) time.Time {
+	if job.ScheduledAt != nil {
+		return *job.ScheduledAt
+	}
+
+	return job.CreatedAt
+}
+
+// count reads a key's length with cmd, retrying transient failures
+func (q *RedisQueue) count(ctx context.Context,
+	cmd func(context.Context, string) *redis.IntCmd, key string) (int64, error) {
+	return retry.ResultContext(ctx, q.retrier, func(ctx context.Context) (int64, error) {
+		return redisResult(cmd(ctx, key).Result())
+	})
+}
+
+// redisResult translates a go-redis error so retries see its code
+func redisResult[T any](value T, err error) (T, error) {
+	if err != nil {
+		return value, errors.FromRedis(err)
+	}
+
+	return value, nil
+}
+
 func (q *RedisQueue) getQueueKey(priority models.JobPriority) string {
 	return fmt.Sprintf("%s:%s", q.keyPrefix, GetQueueName(priority))
 }
 
-func (q *RedisQueue) getProcessingKey() string {
-	return fmt.Sprintf("%s:processing", q.keyPrefix)
+// getInFlightKey returns the hash of the jobs being processed, by ID
+func (q *RedisQueue) getInFlightKey() string {
+	return fmt.Sprintf("%s:inflight", q.keyPrefix)
+}
+
+// getLeasesKey returns the zset of the leases of the jobs being processed,
+// scored by their deadline in milliseconds
+func (q *RedisQueue) getLeasesKey() string {
+	return fmt.Sprintf("%s:leases", q.keyPrefix)
 }
 
 func (q *RedisQueue) getDelayedKey() string {
@@ -477,8 +712,8 @@ func (q *RedisQueue) getDeadLetterKey() string {
 	return fmt.Sprintf("%s:dead_letter", q.keyPrefix)
 }
 
-func (q *RedisQueue) getVisibilityKey(jobID uuid.UUID) string {
-	return fmt.Sprintf("%s:visibility:%s", q.keyPrefix, jobID)
+func (q *RedisQueue) getPausedKey() string {
+	return fmt.Sprintf("%s:paused", q.keyPrefix)
 }
 
 func (q *RedisQueue) processScheduledJobs(ctx context.Context) error {
@@ -509,29 +744,6 @@ func (q *RedisQueue) processScheduledJobs(ctx context.Context) error {
 	return err
 }
 
-func (q *RedisQueue) setVisibilityTimeout(ctx context.Context, job *models.Job) error {
-	key := q.getVisibilityKey(job.ID)
-	return q.client.Set(ctx, key, "1", q.config.VisibilityTimeout).Err()
-}
-
-func (q *RedisQueue) clearVisibilityTimeout(ctx context.Context,
-	jobID uuid.UUID) {
-	key := q.getVisibilityKey(jobID)
-	q.client.Del(ctx, key)
-}
-
-func (q *RedisQueue) moveToDeadLetter(ctx context.Context, job *models.Job) error {
-	job.Status = models.JobStatusDead
-	job.UpdatedAt = time.Now()
-
-	data, err := json.Marshal(job)
-	if err != nil {
-		return errors.Wrap(err, "failed to marshal job").WithCode(errors.CodeSerialization)
-	}
-
-	return q.client.RPush(ctx, q.getDeadLetterKey(), data).Err()
-}
-
 func (q *RedisQueue) updateEnqueueStats(ctx context.Context) {
 	statsKey := fmt.Sprintf("%s:stats", q.keyPrefix)
 
@@ -546,6 +758,10 @@ func (q *RedisQueue) updateDequeueStats(ctx context.Context) {
 	q.client.HSet(ctx, statsKey, "last_dequeue_time", time.Now().Unix())
 }
 
+// backoffDelay asks nack to schedule the retry with the queue's backoff
+// instead of an explicit delay
+const backoffDelay time.Duration = -1
+
 // Helper function to create pointer
 func ptr[T any](v T) *T {
 	return &v
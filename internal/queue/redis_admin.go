@@ -0,0 +1,221 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+var _ Admin = (*RedisQueue)(nil)
+
+// DeadLetters returns up to limit dead-lettered jobs starting at offset,
+// oldest first, and the number of dead-lettered jobs
+func (q *RedisQueue) DeadLetters(ctx context.Context, offset, limit int) ([]*models.Job, int64, error) {
+	key := q.getDeadLetterKey()
+	total, err := q.count(ctx, q.client.LLen, key)
+	if err != nil {
+		return nil, 0, errors.Wrap(errors.FromRedis(err), "failed to count dead letters").
+			WithOp("queue.DeadLetters")
+	}
+
+	if limit <= 0 || int64(offset) >= total {
+		return []*models.Job{}, total, nil
+	}
+
+	start := int64(max(offset, 0))
+	entries, err := q.client.LRange(ctx, key, start, start+int64(limit)-1).Result()
+	if err != nil {
+		return nil, 0, errors.Wrap(errors.FromRedis(err), "failed to list dead letters").
+			WithOp("queue.DeadLetters")
+	}
+
+	jobs := make([]*models.Job, 0, len(entries))
+	for _, data := range entries {
+		var job models.Job
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			q.logger.Warn("skipping undecodable dead letter", "error", err)
+			continue
+		}
+
+		jobs = append(jobs, &job)
+	}
+
+	return jobs, total, nil
+}
+
+// RequeueDeadLetter moves a dead-lettered job back to the queue with its
+// retry count and error cleared
+func (q *RedisQueue) RequeueDeadLetter(ctx context.Context, jobID uuid.UUID) (*models.Job, error) {
+	key := q.getDeadLetterKey()
+	entries, err := q.client.LRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, errors.Wrap(errors.FromRedis(err), "failed to list dead letters").
+			WithOp("queue.RequeueDeadLetter")
+	}
+
+	for _, data := range entries {
+		var job models.Job
+		if err := json.Unmarshal([]byte(data), &job); err != nil || job.ID != jobID {
+			continue
+		}
+
+		// Only the caller that removes the entry requeues it
+		removed, err := q.client.LRem(ctx, key, 1, data).Result()
+		if err != nil {
+			return nil, errors.Wrap(errors.FromRedis(err), "failed to remove dead letter").
+				WithOp("queue.RequeueDeadLetter")
+		}
+
+		if removed == 0 {
+			break
+		}
+
+		if err := q.requeue(ctx, &job); err != nil {
+			return nil, err
+		}
+
+		return &job, nil
+	}
+
+	return nil, errors.NotFound("job %s not found in dead letter queue", jobID).
+		WithKey("job.not_in_dead_letter", jobID).
+		WithOp("queue.RequeueDeadLetter")
+}
+
+// RequeueDeadLetters requeues every dead-lettered job, returning how many
+// were requeued. Jobs dead-lettered while it runs are requeued too.
+func (q *RedisQueue) RequeueDeadLetters(ctx context.Context) (int64, error) {
+	var requeued int64
+	for {
+		data, err := q.client.LPop(ctx, q.getDeadLetterKey()).Result()
+		if err == redis.Nil {
+			return requeued, nil
+		}
+
+		if err != nil {
+			return requeued, errors.Wrap(errors.FromRedis(err), "failed to pop dead letter").
+				WithOp("queue.RequeueDeadLetters")
+		}
+
+		var job models.Job
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			q.logger.Warn("dropping undecodable dead letter", "error", err)
+			continue
+		}
+
+		if err := q.requeue(ctx, &job); err != nil {
+			return requeued, err
+		}
+
+		requeued++
+	}
+}
+
+// requeue revives a dead-lettered job and enqueues it
+func (q *RedisQueue) requeue(ctx context.Context, job *models.Job) error {
+	revive(job)
+	job.UpdatedAt = time.Now()
+	if err := q.enqueue(ctx, job); err != nil {
+		return errors.Wrapf(err, "failed to requeue job %s", job.ID).
+			WithOp("queue.requeue")
+	}
+
+	q.config.Audit.JobEvent(ctx, logger.AuditJobRequeued, job,
+		"queue", q.config.Name,
+		"from", "dead_letter",
+	)
+
+	return nil
+}
+
+// PurgeDeadLetters deletes every dead-lettered job, returning how many were
+// deleted
+func (q *RedisQueue) PurgeDeadLetters(ctx context.Context) (int64, error) {
+	key := q.getDeadLetterKey()
+	pipe := q.client.TxPipeline()
+	count := pipe.LLen(ctx, key)
+	pipe.Del(ctx, key)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, errors.Wrap(errors.FromRedis(err), "failed to purge dead letters").
+			WithOp("queue.PurgeDeadLetters")
+	}
+
+	return count.Val(), nil
+}
+
+// Pause stops delivering jobs until Resume. The flag is shared by every
+// process using the queue.
+func (q *RedisQueue) Pause(ctx context.Context) error {
+	if err := q.client.Set(ctx, q.getPausedKey(), "1", 0).Err(); err != nil {
+		return errors.Wrap(errors.FromRedis(err), "failed to pause queue").
+			WithOp("queue.Pause")
+	}
+
+	return nil
+}
+
+// Resume delivers jobs again after Pause
+func (q *RedisQueue) Resume(ctx context.Context) error {
+	if err := q.client.Del(ctx, q.getPausedKey()).Err(); err != nil {
+		return errors.Wrap(errors.FromRedis(err), "failed to resume queue").
+			WithOp("queue.Resume")
+	}
+
+	return nil
+}
+
+// paused reports whether the queue is paused
+func (q *RedisQueue) paused(ctx context.Context) (bool, error) {
+	n, err := q.client.Exists(ctx, q.getPausedKey()).Result()
+	if err != nil {
+		return false, errors.Wrap(errors.FromRedis(err), "failed to read pause flag").
+			WithOp("queue.paused")
+	}
+
+	return n > 0, nil
+}
+
+// Drain deletes every ready and delayed job, returning how many were
+// deleted
+func (q *RedisQueue) Drain(ctx context.Context) (int64, error) {
+	keys := []string{
+		q.getQueueKey(models.JobPriorityLow),
+		q.getQueueKey(models.JobPriorityNormal),
+		q.getQueueKey(models.JobPriorityHigh),
+		q.getQueueKey(models.JobPriorityCritical),
+		q.getDelayedKey(),
+	}
+
+	// The keys share the queue's hash tag, so one transaction drains them
+	counts := make([]*redis.IntCmd, len(keys))
+	_, err := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			if key == q.getDelayedKey() {
+				counts[i] = pipe.ZCard(ctx, key)
+			} else {
+				counts[i] = pipe.LLen(ctx, key)
+			}
+		}
+
+		return pipe.Del(ctx, keys...).Err()
+	})
+	if err != nil {
+		return 0, errors.Wrap(errors.FromRedis(err), "failed to drain queue").
+			WithOp("queue.Drain")
+	}
+
+	var drained int64
+	for _, count := range counts {
+		drained += count.Val()
+	}
+
+	return drained, nil
+}
@@ -0,0 +1,152 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// legacyKey returns the key a queue stored suffix under before its keys
+// shared the hash tag of its name
+func (q *RedisQueue) legacyKey(suffix string) string {
+	return fmt.Sprintf("queue:%s:%s", q.config.Name, suffix)
+}
+
+// MigrateLegacy moves the jobs stored by versions before the hash tag,
+// under queue:name:..., to the queue's keys, and returns how many it moved.
+// Ready and dead-lettered jobs go ahead of newer ones, in order, and delayed
+// jobs keep their schedule. A job that was being processed cannot be
+// settled by its worker any more, so it counts a failed attempt, as Reap
+// does. Concurrent calls move each job once.
+func (q *RedisQueue) MigrateLegacy(ctx context.Context) (int, error) {
+	moved := 0
+	lists := map[string]string{
+		"dead_letter": q.getDeadLetterKey(),
+	}
+	for _, priority := range []models.JobPriority{
+		models.JobPriorityCritical,
+		models.JobPriorityHigh,
+		models.JobPriorityNormal,
+		models.JobPriorityLow,
+	} {
+		lists[GetQueueName(priority)] = q.getQueueKey(priority)
+	}
+
+	for suffix, key := range lists {
+		n, err := q.migrateList(ctx, q.legacyKey(suffix), key)
+		moved += n
+		if err != nil {
+			return moved, err
+		}
+	}
+
+	n, err := q.migrateDelayed(ctx)
+	moved += n
+	if err != nil {
+		return moved, err
+	}
+
+	n, err = q.migrateProcessing(ctx)
+	moved += n
+
+	return moved, err
+}
+
+// migrateList moves the entries of the legacy list from to the head of the
+// list to, keeping their order
+func (q *RedisQueue) migrateList(ctx context.Context, from, to string) (int, error) {
+	moved := 0
+	for {
+		data, err := q.client.RPop(ctx, from).Result()
+		if err == redis.Nil {
+			return moved, nil
+		}
+
+		if err != nil {
+			return moved, errors.Wrap(errors.FromRedis(err), "failed to read legacy jobs").
+				WithMetadata("key", from).
+				WithOp("queue.MigrateLegacy")
+		}
+
+		if err := q.client.LPush(ctx, to, data).Err(); err != nil {
+			q.client.RPush(ctx, from, data)
+			return moved, errors.Wrap(errors.FromRedis(err), "failed to move legacy job").
+				WithMetadata("key", from).
+				WithOp("queue.MigrateLegacy")
+		}
+
+		moved++
+	}
+}
+
+// migrateDelayed moves the legacy delayed jobs, with their schedule
+func (q *RedisQueue) migrateDelayed(ctx context.Context) (int, error) {
+	from := q.legacyKey("delayed")
+	moved := 0
+	for {
+		popped, err := q.client.ZPopMin(ctx, from, 1).Result()
+		if err != nil {
+			return moved, errors.Wrap(errors.FromRedis(err), "failed to read legacy jobs").
+				WithMetadata("key", from).
+				WithOp("queue.MigrateLegacy")
+		}
+
+		if len(popped) == 0 {
+			return moved, nil
+		}
+
+		if err := q.client.ZAdd(ctx, q.getDelayedKey(), popped[0]).Err(); err != nil {
+			q.client.ZAdd(ctx, from, popped[0])
+			return moved, errors.Wrap(errors.FromRedis(err), "failed to move legacy job").
+				WithMetadata("key", from).
+				WithOp("queue.MigrateLegacy")
+		}
+
+		moved++
+	}
+}
+
+// migrateProcessing moves the legacy jobs being processed in flight with a
+// lease that has already run out, and reaps them
+func (q *RedisQueue) migrateProcessing(ctx context.Context) (int, error) {
+	from := q.legacyKey("processing")
+	var ids []string
+	for {
+		data, err := q.client.RPop(ctx, from).Result()
+		if err == redis.Nil {
+			break
+		}
+
+		if err != nil {
+			return 0, errors.Wrap(errors.FromRedis(err), "failed to read legacy jobs").
+				WithMetadata("key", from).
+				WithOp("queue.MigrateLegacy")
+		}
+
+		var job models.Job
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			q.logger.Warn("dead-lettering undecodable legacy job in flight", "error", err)
+			q.client.RPush(ctx, q.getDeadLetterKey(), data)
+			continue
+		}
+
+		pipe := q.client.TxPipeline()
+		pipe.HSet(ctx, q.getInFlightKey(), job.ID.String(), data)
+		pipe.ZAdd(ctx, q.getLeasesKey(), redis.Z{Score: 0, Member: job.ID.String()})
+		if _, err := pipe.Exec(ctx); err != nil {
+			q.client.RPush(ctx, from, data)
+			return 0, errors.Wrap(errors.FromRedis(err), "failed to move legacy job").
+				WithMetadata("key", from).
+				WithOp("queue.MigrateLegacy")
+		}
+
+		ids = append(ids, job.ID.String())
+	}
+
+	return q.reap(ctx, ids)
+}
@@ -0,0 +1,136 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+var _ Reprioritizer = (*RedisQueue)(nil)
+
+// Waiting returns up to limit ready jobs of priority, longest waiting first
+func (q *RedisQueue) Waiting(ctx context.Context, priority models.JobPriority,
+	limit int) ([]*models.Job, error) {
+	if limit <= 0 {
+		return []*models.Job{}, nil
+	}
+
+	entries, err := q.client.LRange(ctx, q.getQueueKey(priority), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, errors.Wrap(errors.FromRedis(err), "failed to read waiting jobs").
+			WithOp("queue.Waiting")
+	}
+
+	jobs := make([]*models.Job, 0, len(entries))
+	for _, data := range entries {
+		var job models.Job
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			q.logger.Warn("skipping undecodable job", "error", err)
+			continue
+		}
+
+		jobs = append(jobs, &job)
+	}
+
+	return jobs, nil
+}
+
+// UpdatePriority moves a ready or delayed job to priority and returns it. A
+// ready job joins the back of its new priority's list.
+func (q *RedisQueue) UpdatePriority(ctx context.Context, jobID uuid.UUID,
+	priority models.JobPriority) (*models.Job, error) {
+	for _, from := range []models.JobPriority{
+		models.JobPriorityLow,
+		models.JobPriorityNormal,
+		models.JobPriorityHigh,
+		models.JobPriorityCritical,
+	} {
+		key := q.getQueueKey(from)
+		entries, err := q.client.LRange(ctx, key, 0, -1).Result()
+		if err != nil {
+			return nil, errors.Wrap(errors.FromRedis(err), "failed to read waiting jobs").
+				WithOp("queue.UpdatePriority")
+		}
+
+		for _, data := range entries {
+			var job models.Job
+			if err := json.Unmarshal([]byte(data), &job); err != nil || job.ID != jobID {
+				continue
+			}
+
+			// Only the caller that removes the entry moves it, so a job
+			// dequeued meanwhile is not enqueued again
+			removed, err := q.client.LRem(ctx, key, 1, data).Result()
+			if err != nil {
+				return nil, errors.Wrap(errors.FromRedis(err), "failed to remove waiting job").
+					WithOp("queue.UpdatePriority")
+			}
+
+			if removed == 0 {
+				return nil, q.notWaiting(jobID)
+			}
+
+			return q.moveToPriority(ctx, &job, priority)
+		}
+	}
+
+	entries, err := q.client.ZRange(ctx, q.getDelayedKey(), 0, -1).Result()
+	if err != nil {
+		return nil, errors.Wrap(errors.FromRedis(err), "failed to read delayed jobs").
+			WithOp("queue.UpdatePriority")
+	}
+
+	for _, data := range entries {
+		var job models.Job
+		if err := json.Unmarshal([]byte(data), &job); err != nil || job.ID != jobID {
+			continue
+		}
+
+		removed, err := q.client.ZRem(ctx, q.getDelayedKey(), data).Result()
+		if err != nil {
+			return nil, errors.Wrap(errors.FromRedis(err), "failed to remove delayed job").
+				WithOp("queue.UpdatePriority")
+		}
+
+		if removed == 0 {
+			break
+		}
+
+		return q.moveToPriority(ctx, &job, priority)
+	}
+
+	return nil, q.notWaiting(jobID)
+}
+
+// moveToPriority enqueues job, just removed from where it waited, at
+// priority
+func (q *RedisQueue) moveToPriority(ctx context.Context, job *models.Job,
+	priority models.JobPriority) (*models.Job, error) {
+	from := job.Priority
+	job.Priority = priority
+	job.UpdatedAt = time.Now()
+	if err := q.enqueue(ctx, job); err != nil {
+		return nil, errors.Wrapf(err, "failed to move job %s to priority %s", job.ID, priority.Name()).
+			WithOp("queue.UpdatePriority")
+	}
+
+	q.config.Audit.JobEvent(ctx, logger.AuditJobEscalated, job,
+		"queue", q.config.Name,
+		"from", from.Name(),
+	)
+
+	return job, nil
+}
+
+// notWaiting reports a job that is neither ready nor delayed
+func (q *RedisQueue) notWaiting(jobID uuid.UUID) error {
+	return errors.NotFound("job %s is not waiting in the queue", jobID).
+		WithKey("job.not_waiting", jobID).
+		WithOp("queue.UpdatePriority")
+}
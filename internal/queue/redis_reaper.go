@@ -0,0 +1,154 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+)
+
+// Outcomes of a reclaimed job reported to ReaperMetrics
+const (
+	ReclaimRequeued     = "requeued"
+	ReclaimDeadLettered = "dead_lettered"
+)
+
+// DefaultReapInterval is how often StartReaper looks for expired jobs when
+// given no interval
+const DefaultReapInterval = 30 * time.Second
+
+// reapBatch is how many jobs in flight a reaper pass inspects per round trip
+const reapBatch = 100
+
+// reapReason is the failure recorded on a reclaimed job
+const reapReason = "visibility timeout expired"
+
+// ReaperMetrics counts the jobs reclaimed from workers that stopped
+// renewing their visibility timeout, by queue and outcome. internal/metrics
+// provides a Prometheus implementation.
+type ReaperMetrics interface {
+	ObserveReclaimed(queue, outcome string)
+}
+
+// StartReaper runs Reap every interval, or DefaultReapInterval when it is
+// not positive, until ctx is canceled. Every replica may run one: a job is
+// reclaimed once however many reapers find it. It first moves the jobs left
+// under legacy keys, as MigrateLegacy does.
+func (q *RedisQueue) StartReaper(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultReapInterval
+	}
+
+	go func() {
+		moved, err := q.MigrateLegacy(ctx)
+		if err != nil && ctx.Err() == nil {
+			q.logger.Warn("failed to migrate legacy jobs", "moved", moved, "error", err)
+		}
+
+		if moved > 0 {
+			q.logger.Warn("migrated jobs from legacy keys", "moved", moved)
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-ticker.C:
+			}
+
+			if _, err := q.Reap(ctx); err != nil && ctx.Err() == nil {
+				q.logger.Warn("failed to reap expired jobs", "error", err)
+			}
+		}
+	}()
+}
+
+// Reap reclaims the jobs in flight whose lease has expired, as it does when
+// their worker crashed, and returns how many it reclaimed. Each counts a
+// failed attempt: it is ready again at once, or dead-lettered when its
+// retries are exhausted. A job settled or extended meanwhile is left alone.
+func (q *RedisQueue) Reap(ctx context.Context) (int, error) {
+	reclaimed, skipped := 0, 0
+	for {
+		ids, err := expiredScript.Run(ctx, q.client, []string{q.getLeasesKey()},
+			skipped, reapBatch).StringSlice()
+		if err != nil {
+			return reclaimed, errors.Wrap(errors.FromRedis(err), "failed to find expired leases").
+				WithOp("queue.Reap")
+		}
+
+		n, err := q.reap(ctx, ids)
+		reclaimed += n
+		if err != nil {
+			return reclaimed, err
+		}
+
+		if len(ids) < reapBatch {
+			return reclaimed, nil
+		}
+
+		skipped += len(ids) - n
+	}
+}
+
+// reap reclaims the jobs with ids, whose lease had expired when they were
+// found
+func (q *RedisQueue) reap(ctx context.Context, ids []string) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	held, err := q.client.HMGet(ctx, q.getInFlightKey(), ids...).Result()
+	if err != nil {
+		return 0, errors.Wrap(errors.FromRedis(err), "failed to get jobs in flight").
+			WithOp("queue.Reap")
+	}
+
+	reclaimed := 0
+	for _, entry := range held {
+		data, ok := entry.(string)
+		if !ok {
+			continue
+		}
+
+		var job models.Job
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			q.logger.Warn("skipping undecodable job in flight", "error", err)
+			continue
+		}
+
+		deadLetter, err := q.fail(ctx, claim{held: data, expired: true}, &job, reapReason, true, 0, "queue.Reap")
+		if errors.HasCode(err, errors.CodeNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return reclaimed, err
+		}
+
+		reclaimed++
+		outcome := ReclaimRequeued
+		if deadLetter {
+			outcome = ReclaimDeadLettered
+		}
+
+		q.logger.Warn("reclaimed job whose visibility timeout expired",
+			"job_id", job.ID,
+			"type", job.Type,
+			"retry_count", job.RetryCount,
+			"outcome", outcome,
+		)
+
+		if q.config.ReaperMetrics != nil {
+			q.config.ReaperMetrics.ObserveReclaimed(q.config.Name, outcome)
+		}
+	}
+
+	return reclaimed, nil
+}
@@ -0,0 +1,138 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reclaimRecorder is a ReaperMetrics recording each reclaimed job's outcome
+type reclaimRecorder struct {
+	mu       sync.Mutex
+	outcomes []string
+}
+
+func (r *reclaimRecorder) ObserveReclaimed(queue, outcome string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.outcomes = append(r.outcomes, queue+"/"+outcome)
+}
+
+// crash dequeues job and lets its lease run out, as when its worker dies
+func crash(t *testing.T, q *RedisQueue, server *miniredis.Miniredis, job *models.Job) {
+	t.Helper()
+
+	got, err := q.Dequeue(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.Equal(t, job.ID, got.ID)
+	_, err = server.ZAdd(q.getLeasesKey(), 0, job.ID.String())
+	require.NoError(t, err)
+}
+
+func TestRedisQueue_ReapRequeuesCrashedJob(t *testing.T) {
+	ctx := context.Background()
+	q, server := newRedisQueue(t)
+	recorder := &reclaimRecorder{}
+	q.config.ReaperMetrics = recorder
+
+	crashed, leased := newRedisJob(), newRedisJob()
+	require.NoError(t, q.Enqueue(ctx, crashed))
+	crash(t, q, server, crashed)
+	require.NoError(t, q.Enqueue(ctx, leased))
+	_, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+
+	n, err := q.Reap(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{"default/" + ReclaimRequeued}, recorder.outcomes)
+	assert.Equal(t, int64(1), redisStats(t, q).Processing, "a job still leased is left alone")
+
+	got, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, got, "the crashed job is ready again")
+	assert.Equal(t, crashed.ID, got.ID)
+	assert.Equal(t, 1, got.RetryCount)
+	require.NotNil(t, got.Error)
+	assert.Equal(t, reapReason, *got.Error)
+	_, err = server.ZScore(q.getLeasesKey(), crashed.ID.String())
+	assert.NoError(t, err, "the redelivered job is leased again")
+
+	n, err = q.Reap(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestRedisQueue_ReapDeadLettersExhaustedJob(t *testing.T) {
+	ctx := context.Background()
+	q, server := newRedisQueue(t)
+	recorder := &reclaimRecorder{}
+	q.config.ReaperMetrics = recorder
+
+	job := newRedisJob()
+	job.MaxRetries = 1
+	require.NoError(t, q.Enqueue(ctx, job))
+	crash(t, q, server, job)
+
+	n, err := q.Reap(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{"default/" + ReclaimDeadLettered}, recorder.outcomes)
+
+	stats := redisStats(t, q)
+	assert.Zero(t, stats.Processing)
+	assert.Zero(t, stats.Size)
+	assert.Equal(t, int64(1), stats.DeadLetter)
+
+	err = q.Ack(ctx, job.ID)
+	assert.True(t, errors.HasCode(err, errors.CodeNotFound), "a late ack finds the job reclaimed")
+}
+
+func TestRedisQueue_ReapScansEveryBatch(t *testing.T) {
+	ctx := context.Background()
+	q, server := newRedisQueue(t)
+
+	const jobs = 3*reapBatch + 7
+	for range jobs {
+		job := newRedisJob()
+		require.NoError(t, q.Enqueue(ctx, job))
+		crash(t, q, server, job)
+	}
+
+	n, err := q.Reap(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, jobs, n)
+
+	stats := redisStats(t, q)
+	assert.Zero(t, stats.Processing)
+	assert.Equal(t, int64(jobs), stats.Size)
+}
+
+func TestRedisQueue_StartReaper(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	q, server := newRedisQueue(t)
+
+	job := newRedisJob()
+	require.NoError(t, q.Enqueue(ctx, job))
+	_, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+
+	q.StartReaper(ctx, 10*time.Millisecond)
+	server.SetTime(time.Now().Add(q.config.VisibilityTimeout + time.Second))
+
+	require.Eventually(t, func() bool {
+		got, err := q.Dequeue(ctx)
+		return err == nil && got != nil && got.ID == job.ID
+	}, time.Second, 10*time.Millisecond, "the expired job is never reclaimed")
+}
@@ -0,0 +1,263 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Settlement scripts. A dequeued job is held in the in-flight hash under its
+// ID, so finding it takes one lookup however many jobs are being processed,
+// and its lease is its deadline, in milliseconds of Redis time, in the
+// leases zset. Every key a script touches is passed in KEYS, and all of a
+// queue's keys share its hash tag, so the scripts run on cluster clients.
+var (
+	// dequeueScript pops the first job of the ready lists KEYS[1..n-2], in
+	// order, records it in the in-flight hash KEYS[n-1], and leases it in
+	// the zset KEYS[n] for ARGV[1] milliseconds, or for good when that is 0.
+	// It returns the job or false.
+	dequeueScript = redis.NewScript(`
+local inflight, leases = KEYS[#KEYS - 1], KEYS[#KEYS]
+for i = 1, #KEYS - 2 do
+	local data = redis.call("LPOP", KEYS[i])
+	if data then
+		local id = cjson.decode(data).id
+		redis.call("HSET", inflight, id, data)
+		if tonumber(ARGV[1]) > 0 then
+			local t = redis.call("TIME")
+			local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+			redis.call("ZADD", leases, now + tonumber(ARGV[1]), id)
+		end
+		return data
+	end
+end
+return false
+`)
+
+	// extendScript leases job ARGV[1], held in the in-flight hash KEYS[1]
+	// as ARGV[2] or as anything when ARGV[2] is empty, for ARGV[3] more
+	// milliseconds in the zset KEYS[2]. It returns 0, extending nothing,
+	// when the job is not held so or its lease has run out.
+	extendScript = redis.NewScript(`
+local data = redis.call("HGET", KEYS[1], ARGV[1])
+if not data or (ARGV[2] ~= "" and data ~= ARGV[2]) then
+	return 0
+end
+
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local deadline = redis.call("ZSCORE", KEYS[2], ARGV[1])
+if deadline and tonumber(deadline) <= now then
+	return 0
+end
+
+redis.call("ZADD", KEYS[2], now + tonumber(ARGV[3]), ARGV[1])
+return 1
+`)
+
+	// settleScript removes job ARGV[1] from the in-flight hash KEYS[1] and
+	// its lease from the zset KEYS[2], when it is held as ARGV[2] or ARGV[2]
+	// is empty, and, when ARGV[6] is "1", its lease has run out. When
+	// ARGV[3] is "list" or "zset" it then adds ARGV[4] to KEYS[3], scored
+	// ARGV[5] in a zset. It returns the removed job, or false when the job
+	// was not in flight so.
+	settleScript = redis.NewScript(`
+local data = redis.call("HGET", KEYS[1], ARGV[1])
+if not data or (ARGV[2] ~= "" and data ~= ARGV[2]) then
+	return false
+end
+
+if ARGV[6] == "1" then
+	local deadline = redis.call("ZSCORE", KEYS[2], ARGV[1])
+	local t = redis.call("TIME")
+	local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+	if not deadline or tonumber(deadline) > now then
+		return false
+	end
+end
+
+redis.call("HDEL", KEYS[1], ARGV[1])
+redis.call("ZREM", KEYS[2], ARGV[1])
+if ARGV[3] == "list" then
+	redis.call("RPUSH", KEYS[3], ARGV[4])
+elseif ARGV[3] == "zset" then
+	redis.call("ZADD", KEYS[3], ARGV[5], ARGV[4])
+end
+return data
+`)
+
+	// expiredScript returns up to ARGV[2] IDs, from offset ARGV[1], of the
+	// jobs whose lease in the zset KEYS[1] has run out
+	expiredScript = redis.NewScript(`
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+return redis.call("ZRANGEBYSCORE", KEYS[1], "-inf", now, "LIMIT", ARGV[1], ARGV[2])
+`)
+
+	// deleteWaitingScript removes job ARGV[1] from the lists KEYS[1..n-1]
+	// or the zset KEYS[n], returning 1 when it was found
+	deleteWaitingScript = redis.NewScript(`
+for i = 1, #KEYS do
+	local entries
+	if i == #KEYS then
+		entries = redis.call("ZRANGE", KEYS[i], 0, -1)
+	else
+		entries = redis.call("LRANGE", KEYS[i], 0, -1)
+	end
+
+	for _, entry in ipairs(entries) do
+		if cjson.decode(entry).id == ARGV[1] then
+			if i == #KEYS then
+				redis.call("ZREM", KEYS[i], entry)
+			else
+				redis.call("LREM", KEYS[i], 1, entry)
+			end
+			return 1
+		end
+	end
+end
+return 0
+`)
+)
+
+// destination is where settling a job puts it: a ready list, the delayed
+// set, or, when kind is empty, nowhere
+type destination struct {
+	kind  string
+	key   string
+	data  []byte
+	score float64
+}
+
+// placement returns where job waits: the delayed set when it is scheduled
+// for later, or else the ready list of its priority
+func (q *RedisQueue) placement(job *models.Job, op string) (destination, error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return destination{}, errors.Wrap(err, "failed to marshal job").
+			WithCode(errors.CodeSerialization).
+			WithOp(op)
+	}
+
+	if job.ScheduledAt != nil && job.ScheduledAt.After(time.Now()) {
+		return destination{
+			kind:  "zset",
+			key:   q.getDelayedKey(),
+			data:  data,
+			score: float64(job.ScheduledAt.Unix()),
+		}, nil
+	}
+
+	return destination{kind: "list", key: q.getQueueKey(job.Priority), data: data}, nil
+}
+
+// claim is what settling a job requires of it: that it is still held as
+// held, unless held is empty, and, when expired is set, that its lease ran
+// out
+type claim struct {
+	held    string
+	expired bool
+}
+
+// hold records data as the job with jobID this queue handed out, so that
+// settling it later fails once the job was redelivered
+func (q *RedisQueue) hold(jobID uuid.UUID, data string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.held[jobID] = data
+}
+
+// holding returns the job with jobID as this queue handed it out, or an
+// empty string when it did not
+func (q *RedisQueue) holding(jobID uuid.UUID) string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.held[jobID]
+}
+
+// Abandon forgets the job with jobID this queue handed out, once its worker
+// gave it up after losing its lease
+func (q *RedisQueue) Abandon(jobID uuid.UUID) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.held, jobID)
+}
+
+// inFlight returns the job with jobID being processed, as this queue handed
+// it out or else as stored, and decoded, or a CodeNotFound error
+func (q *RedisQueue) inFlight(ctx context.Context, jobID uuid.UUID,
+	op string) (string, *models.Job, error) {
+	data := q.holding(jobID)
+	if data == "" {
+		var err error
+		data, err = q.client.HGet(ctx, q.getInFlightKey(), jobID.String()).Result()
+		if err == redis.Nil {
+			return "", nil, notInFlight(jobID, op)
+		}
+
+		if err != nil {
+			return "", nil, errors.Wrap(errors.FromRedis(err), "failed to get processing job").
+				WithOp(op)
+		}
+	}
+
+	var job models.Job
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		return "", nil, errors.Wrap(err, "failed to unmarshal job").
+			WithCode(errors.CodeSerialization).
+			WithOp(op)
+	}
+
+	return data, &job, nil
+}
+
+// settle atomically takes the job with jobID out of processing, when it
+// meets c, and puts it at to. It returns the job as it was held. Of
+// concurrent settlements of one job only the first succeeds; the others get
+// a CodeNotFound error. Once it succeeds, unless the reaper settled it,
+// this queue forgets handing the job out.
+func (q *RedisQueue) settle(ctx context.Context, jobID uuid.UUID, c claim,
+	to destination, op string) (string, error) {
+	keys := []string{q.getInFlightKey(), q.getLeasesKey(), to.key}
+	if to.key == "" {
+		keys[2] = keys[0]
+	}
+
+	expired := ""
+	if c.expired {
+		expired = "1"
+	}
+
+	data, err := settleScript.Run(ctx, q.client, keys,
+		jobID.String(), c.held, to.kind, to.data, to.score, expired).Text()
+	if err != nil && err != redis.Nil {
+		return "", errors.Wrap(errors.FromRedis(err), "failed to settle job").
+			WithOp(op)
+	}
+
+	if err == redis.Nil {
+		return "", notInFlight(jobID, op)
+	}
+
+	if !c.expired {
+		q.Abandon(jobID)
+	}
+
+	return data, nil
+}
+
+// notInFlight reports a job that is not being processed
+func notInFlight(jobID uuid.UUID, op string) error {
+	return errors.NotFound("job %s not found in processing queue", jobID).
+		WithKey("job.not_in_processing", jobID).
+		WithOp(op)
+}
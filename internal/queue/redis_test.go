@@ -0,0 +1,369 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRedisQueue returns a queue named default on a fresh miniredis server
+func newRedisQueue(t *testing.T) (*RedisQueue, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+
+	q, err := NewRedisQueue(client, Config{Name: "default", VisibilityTimeout: time.Minute}, logger.NewNop())
+	require.NoError(t, err)
+	return q, server
+}
+
+// newRedisJob returns a normal priority job allowed three attempts
+func newRedisJob() *models.Job {
+	job := models.NewJob("email_send", json.RawMessage(`{"to":"a@example.com"}`), models.JobPriorityNormal)
+	job.MaxRetries = 3
+	return job
+}
+
+// redisStats returns the queue statistics
+func redisStats(t *testing.T, q *RedisQueue) *QueueStats {
+	t.Helper()
+
+	stats, err := q.Stats(context.Background())
+	require.NoError(t, err)
+	return stats
+}
+
+func TestRedisQueue_DequeueAndAck(t *testing.T) {
+	ctx := context.Background()
+	q, server := newRedisQueue(t)
+	low := newRedisJob()
+	low.Priority = models.JobPriorityLow
+	high := newRedisJob()
+	high.Priority = models.JobPriorityHigh
+	require.NoError(t, q.EnqueueBatch(ctx, []*models.Job{low, high}))
+
+	none, err := q.DequeueMinPriority(ctx, models.JobPriorityCritical)
+	require.NoError(t, err)
+	assert.Nil(t, none)
+
+	got, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, high.ID, got.ID, "higher priorities are served first")
+	_, err = server.ZScore(q.getLeasesKey(), high.ID.String())
+	assert.NoError(t, err, "the job is leased")
+	assert.Equal(t, int64(1), redisStats(t, q).Processing)
+
+	require.NoError(t, q.Ack(ctx, high.ID))
+	assert.False(t, server.Exists(q.getLeasesKey()))
+	assert.Zero(t, redisStats(t, q).Processing)
+
+	err = q.Ack(ctx, high.ID)
+	assert.True(t, errors.HasCode(err, errors.CodeNotFound))
+}
+
+func TestRedisQueue_AckCostIsIndependentOfInFlightJobs(t *testing.T) {
+	ctx := context.Background()
+	q, server := newRedisQueue(t)
+
+	// ackCost returns how many commands, counting those a script runs, an
+	// ack of a freshly dequeued job takes
+	ackCost := func() int {
+		job := newRedisJob()
+		require.NoError(t, q.Enqueue(ctx, job))
+		_, err := q.Dequeue(ctx)
+		require.NoError(t, err)
+
+		before := server.CommandCount()
+		require.NoError(t, q.Ack(ctx, job.ID))
+		return server.CommandCount() - before
+	}
+
+	ackCost() // loads the script
+	alone := ackCost()
+
+	for range 10000 {
+		other := newRedisJob()
+		data, err := json.Marshal(other)
+		require.NoError(t, err)
+		server.HSet(q.getInFlightKey(), other.ID.String(), string(data))
+	}
+
+	assert.Equal(t, alone, ackCost(), "an ack does not scan the jobs in flight")
+	assert.Equal(t, int64(10000), redisStats(t, q).Processing)
+}
+
+func TestRedisQueue_NackRetriesThenDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	q, _ := newRedisQueue(t)
+	job := newRedisJob()
+	job.MaxRetries = 2
+	require.NoError(t, q.Enqueue(ctx, job))
+
+	_, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NoError(t, q.NackWithDelay(ctx, job.ID, "boom", 0))
+
+	stats := redisStats(t, q)
+	assert.Zero(t, stats.Processing)
+	assert.Equal(t, int64(1), stats.Size)
+
+	got, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, 1, got.RetryCount)
+	require.NoError(t, q.Nack(ctx, job.ID, "boom again"))
+
+	stats = redisStats(t, q)
+	assert.Zero(t, stats.Processing)
+	assert.Zero(t, stats.Size)
+	assert.Equal(t, int64(1), stats.DeadLetter)
+
+	dead, _, err := q.DeadLetters(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.Equal(t, models.JobStatusDead, dead[0].Status)
+	assert.Equal(t, 2, dead[0].RetryCount)
+}
+
+func TestRedisQueue_ConcurrentAckAndNackSettleOnce(t *testing.T) {
+	ctx := context.Background()
+	q, _ := newRedisQueue(t)
+
+	const jobs = 100
+	for range jobs {
+		require.NoError(t, q.Enqueue(ctx, newRedisJob()))
+	}
+
+	var acked, nacked int
+	for range jobs {
+		job, err := q.Dequeue(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, job)
+
+		var wg sync.WaitGroup
+		var ackErr, nackErr error
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			ackErr = q.Ack(ctx, job.ID)
+		}()
+		go func() {
+			defer wg.Done()
+			nackErr = q.NackWithDelay(ctx, job.ID, "boom", time.Hour)
+		}()
+		wg.Wait()
+
+		require.True(t, (ackErr == nil) != (nackErr == nil),
+			"exactly one settlement wins: ack %v, nack %v", ackErr, nackErr)
+		if ackErr == nil {
+			acked++
+			assert.True(t, errors.HasCode(nackErr, errors.CodeNotFound))
+		} else {
+			nacked++
+			assert.True(t, errors.HasCode(ackErr, errors.CodeNotFound))
+		}
+	}
+
+	stats := redisStats(t, q)
+	assert.Zero(t, stats.Processing)
+	assert.Equal(t, int64(nacked), stats.Delayed, "only nacks that won rescheduled their job")
+	assert.Equal(t, jobs, acked+nacked)
+}
+
+func TestRedisQueue_NackLosesToRedelivery(t *testing.T) {
+	ctx := context.Background()
+	q, server := newRedisQueue(t)
+	job := newRedisJob()
+	require.NoError(t, q.Enqueue(ctx, job))
+	_, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+
+	// The job changes in flight between the nack's read and its
+	// settlement, as when it is reaped and delivered again
+	held, _, err := q.inFlight(ctx, job.ID, "test")
+	require.NoError(t, err)
+	server.HSet(q.getInFlightKey(), job.ID.String(), held+" ")
+
+	_, err = q.settle(ctx, job.ID, claim{held: held}, destination{}, "test")
+	assert.True(t, errors.HasCode(err, errors.CodeNotFound))
+	assert.Equal(t, int64(1), redisStats(t, q).Processing)
+}
+
+func TestRedisQueue_Release(t *testing.T) {
+	ctx := context.Background()
+	q, _ := newRedisQueue(t)
+	job := newRedisJob()
+	require.NoError(t, q.Enqueue(ctx, job))
+	_, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, q.Release(ctx, job.ID, 0))
+	got, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Zero(t, got.RetryCount, "a release is not a retry")
+
+	require.NoError(t, q.Release(ctx, job.ID, time.Hour))
+	stats := redisStats(t, q)
+	assert.Zero(t, stats.Processing)
+	assert.Equal(t, int64(1), stats.Delayed)
+}
+
+func TestRedisQueue_Delete(t *testing.T) {
+	ctx := context.Background()
+	q, _ := newRedisQueue(t)
+	inFlight, waiting, delayed := newRedisJob(), newRedisJob(), newRedisJob()
+	delayed.ScheduledAt = ptr(time.Now().Add(time.Hour))
+	require.NoError(t, q.Enqueue(ctx, inFlight))
+	_, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NoError(t, q.EnqueueBatch(ctx, []*models.Job{waiting, delayed}))
+
+	for _, job := range []*models.Job{inFlight, waiting, delayed} {
+		require.NoError(t, q.Delete(ctx, job.ID))
+	}
+
+	stats := redisStats(t, q)
+	assert.Zero(t, stats.Processing)
+	assert.Zero(t, stats.Size)
+
+	err = q.Delete(ctx, waiting.ID)
+	assert.True(t, errors.HasCode(err, errors.CodeNotFound))
+}
+
+func TestRedisQueue_StaleWorkerCannotSettleRedelivery(t *testing.T) {
+	ctx := context.Background()
+	stale, server := newRedisQueue(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+	current, err := NewRedisQueue(client, Config{Name: "default", VisibilityTimeout: time.Minute}, logger.NewNop())
+	require.NoError(t, err)
+
+	job := newRedisJob()
+	require.NoError(t, stale.Enqueue(ctx, job))
+	crash(t, stale, server, job)
+	n, err := current.Reap(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	redelivered, err := current.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, redelivered)
+
+	// Each attempt fails, however many came before it
+	for name, settle := range map[string]func() error{
+		"Extend":  func() error { return stale.Extend(ctx, job.ID, time.Minute) },
+		"Ack":     func() error { return stale.Ack(ctx, job.ID) },
+		"Nack":    func() error { return stale.Nack(ctx, job.ID, "stale") },
+		"Release": func() error { return stale.Release(ctx, job.ID, 0) },
+		"Delete":  func() error { return stale.Delete(ctx, job.ID) },
+	} {
+		err = settle()
+		assert.True(t, errors.HasCode(err, errors.CodeNotFound), "a stale worker's %s does not touch the redelivery", name)
+		assert.Equal(t, int64(1), redisStats(t, current).Processing)
+	}
+
+	stale.Abandon(job.ID)
+	assert.Empty(t, stale.held)
+	require.NoError(t, current.Ack(ctx, job.ID))
+	assert.Empty(t, current.held)
+}
+
+func TestRedisQueue_KeysShareHashTag(t *testing.T) {
+	ctx := context.Background()
+	q, server := newRedisQueue(t)
+
+	delayed := newRedisJob()
+	delayed.ScheduledAt = ptr(time.Now().Add(time.Hour))
+	leased, failed := newRedisJob(), newRedisJob()
+	failed.MaxRetries = 1
+	require.NoError(t, q.EnqueueBatch(ctx, []*models.Job{delayed, leased, failed}))
+	for range 2 {
+		_, err := q.Dequeue(ctx)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, q.Nack(ctx, failed.ID, "boom"))
+	require.NoError(t, q.Pause(ctx))
+
+	keys := server.Keys()
+	require.NotEmpty(t, keys)
+	for _, key := range keys {
+		assert.True(t, strings.HasPrefix(key, "queue:{default}:"), "key %s is outside the queue's hash slot", key)
+	}
+}
+
+func TestRedisQueue_MigrateLegacy(t *testing.T) {
+	ctx := context.Background()
+	q, server := newRedisQueue(t)
+
+	encode := func(job *models.Job) string {
+		data, err := json.Marshal(job)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	current, first, second := newRedisJob(), newRedisJob(), newRedisJob()
+	require.NoError(t, q.Enqueue(ctx, current))
+	_, err := server.RPush("queue:default:normal", encode(first), encode(second))
+	require.NoError(t, err)
+
+	delayed := newRedisJob()
+	delayed.ScheduledAt = ptr(time.Now().Add(time.Hour))
+	_, err = server.ZAdd("queue:default:delayed", float64(delayed.ScheduledAt.Unix()), encode(delayed))
+	require.NoError(t, err)
+
+	processing, exhausted := newRedisJob(), newRedisJob()
+	exhausted.MaxRetries = 1
+	_, err = server.RPush("queue:default:processing", encode(processing), encode(exhausted))
+	require.NoError(t, err)
+	_, err = server.RPush("queue:default:dead_letter", encode(newRedisJob()))
+	require.NoError(t, err)
+
+	moved, err := q.MigrateLegacy(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 6, moved)
+	for _, key := range server.Keys() {
+		assert.True(t, strings.HasPrefix(key, "queue:{default}:"), "legacy key %s is left", key)
+	}
+
+	stats := redisStats(t, q)
+	assert.Zero(t, stats.Processing)
+	assert.Equal(t, int64(2), stats.DeadLetter)
+
+	for _, want := range []*models.Job{first, second, current, processing} {
+		got, err := q.Dequeue(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, want.ID, got.ID, "legacy jobs go first, in order")
+		require.NoError(t, q.Ack(ctx, got.ID))
+		if want == processing {
+			assert.Equal(t, 1, got.RetryCount, "the interrupted attempt counts")
+		}
+	}
+
+	got, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, got, "the delayed job keeps its schedule")
+	_, err = server.ZScore(q.getDelayedKey(), encode(delayed))
+	assert.NoError(t, err)
+
+	moved, err = q.MigrateLegacy(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, moved)
+}
@@ -0,0 +1,15 @@
+// Package ratelimit limits request rates with a token bucket per key, such
+// as an API client. A bucket holds up to a rule's Burst tokens and refills
+// at its Rate a second; every request takes a token and is denied when none
+// is left.
+//
+// MemoryLimiter keeps buckets in process. RedisLimiter keeps them in Redis,
+// updated by a script so concurrent replicas share one bucket per key, and
+// falls back to in-process buckets while Redis is unreachable:
+//
+//	limiter, err := ratelimit.NewRedisLimiter(client, "task-queue:ratelimit", log)
+//	result, err := limiter.Allow(ctx, "key:ops", ratelimit.Rule{Rate: 10, Burst: 20})
+//	if !result.Allowed {
+//	    // retry after result.RetryAfter
+//	}
+package ratelimit
@@ -0,0 +1,31 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Rule is a token bucket: Rate tokens a second refill a bucket holding up
+// to Burst, and each request takes one
+type Rule struct {
+	Rate  float64
+	Burst int
+}
+
+// Result is the outcome of taking a token
+type Result struct {
+	// Allowed reports whether a token was taken
+	Allowed bool
+
+	// Remaining is how many whole tokens are left in the bucket
+	Remaining int
+
+	// RetryAfter is how long a denied caller must wait for a token
+	RetryAfter time.Duration
+}
+
+// Limiter holds a token bucket per key
+type Limiter interface {
+	// Allow takes a token from the bucket of key under rule
+	Allow(ctx context.Context, key string, rule Rule) (Result, error)
+}
@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// sweepInterval is how often a MemoryLimiter forgets buckets that have
+// refilled, so clients seen once do not accumulate
+const sweepInterval = time.Minute
+
+// bucket is one key's token bucket, with the time it is full again
+type bucket struct {
+	limiter *rate.Limiter
+	full    time.Time
+}
+
+// MemoryLimiter keeps token buckets in process, for single-instance
+// deployments and as the fallback of RedisLimiter
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+var _ Limiter = (*MemoryLimiter)(nil)
+
+// NewMemoryLimiter creates an in-process limiter
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow takes a token from the bucket of key under rule
+func (l *MemoryLimiter) Allow(_ context.Context, key string, rule Rule) (Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	limit := rate.Limit(rule.Rate)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(limit, rule.Burst)}
+		l.buckets[key] = b
+	}
+
+	b.limiter.SetLimitAt(now, limit)
+	b.limiter.SetBurstAt(now, rule.Burst)
+
+	result := Result{Allowed: b.limiter.AllowN(now, 1)}
+	if !result.Allowed {
+		r := b.limiter.ReserveN(now, 1)
+		result.RetryAfter = r.DelayFrom(now)
+		r.CancelAt(now)
+	}
+
+	tokens := b.limiter.TokensAt(now)
+	result.Remaining = int(tokens)
+	b.full = now.Add(time.Duration((float64(rule.Burst) - tokens) / rule.Rate * float64(time.Second)))
+	return result, nil
+}
+
+// sweep forgets the buckets that are full again, at most once every
+// sweepInterval. The caller holds l.mu.
+func (l *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if !b.full.After(now) {
+			delete(l.buckets, key)
+		}
+	}
+}
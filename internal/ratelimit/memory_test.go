@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a settable clock for MemoryLimiter
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newFakeLimiter() (*MemoryLimiter, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	l := NewMemoryLimiter()
+	l.now = clock.Now
+	return l, clock
+}
+
+func TestMemoryLimiter_BurstExhaustion(t *testing.T) {
+	l, clock := newFakeLimiter()
+	rule := Rule{Rate: 2, Burst: 3}
+	ctx := context.Background()
+
+	for want := 2; want >= 0; want-- {
+		result, err := l.Allow(ctx, "client", rule)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, want, result.Remaining)
+	}
+
+	result, err := l.Allow(ctx, "client", rule)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	result, err = l.Allow(ctx, "client", rule)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed, "a token refills after RetryAfter")
+}
+
+func TestMemoryLimiter_KeysAreIsolated(t *testing.T) {
+	l, _ := newFakeLimiter()
+	rule := Rule{Rate: 1, Burst: 1}
+	ctx := context.Background()
+
+	first, _ := l.Allow(ctx, "a", rule)
+	second, _ := l.Allow(ctx, "a", rule)
+	other, _ := l.Allow(ctx, "b", rule)
+
+	assert.True(t, first.Allowed)
+	assert.False(t, second.Allowed)
+	assert.True(t, other.Allowed)
+}
+
+func TestMemoryLimiter_SweepsRefilledBuckets(t *testing.T) {
+	l, clock := newFakeLimiter()
+	ctx := context.Background()
+
+	_, _ = l.Allow(ctx, "idle", Rule{Rate: 1, Burst: 5})
+	_, _ = l.Allow(ctx, "busy", Rule{Rate: 0.001, Burst: 5})
+
+	clock.now = clock.now.Add(2 * sweepInterval)
+	_, _ = l.Allow(ctx, "new", Rule{Rate: 1, Burst: 5})
+
+	assert.NotContains(t, l.buckets, "idle")
+	assert.Contains(t, l.buckets, "busy")
+}
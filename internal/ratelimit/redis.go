@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// allowScript takes a token from the bucket hash KEYS[1], refilling ARGV[1]
+// tokens a second up to ARGV[2]. Time comes from the Redis server so every
+// replica sees the same clock. It returns whether a token was taken, the
+// whole tokens left, and the milliseconds until the next token.
+var allowScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) / rate * 1000)
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", tostring(now))
+redis.call("PEXPIRE", KEYS[1], math.ceil((burst - tokens) / rate * 1000) + 1000)
+return {allowed, math.floor(tokens), wait}
+`)
+
+// RedisLimiter keeps token buckets in Redis, so a limit holds across every
+// replica sharing it. While Redis cannot be reached, buckets are kept in
+// process instead: each replica then enforces the limit on its own.
+type RedisLimiter struct {
+	client    redis.UniversalClient
+	keyPrefix string
+	fallback  *MemoryLimiter
+	degraded  atomic.Bool
+	logger    logger.Logger
+}
+
+var _ Limiter = (*RedisLimiter)(nil)
+
+// NewRedisLimiter creates a limiter whose bucket keys start with prefix
+func NewRedisLimiter(client redis.UniversalClient, prefix string, log logger.Logger) (*RedisLimiter, error) {
+	if client == nil {
+		return nil, errors.New("redis client is required").
+			WithCode(errors.CodeConfiguration)
+	}
+
+	return &RedisLimiter{
+		client:    client,
+		keyPrefix: prefix,
+		fallback:  NewMemoryLimiter(),
+		logger:    log.Named("ratelimit"),
+	}, nil
+}
+
+// Allow takes a token from the bucket of key under rule, from the
+// in-process fallback when Redis fails
+func (l *RedisLimiter) Allow(ctx context.Context, key string, rule Rule) (Result, error) {
+	values, err := allowScript.Run(ctx, l.client, []string{l.keyPrefix + ":" + key},
+		strconv.FormatFloat(rule.Rate, 'f', -1, 64), rule.Burst).Int64Slice()
+	if err != nil || len(values) != 3 {
+		if err == nil {
+			err = errors.Newf("unexpected rate limit reply %v", values)
+		}
+
+		if !l.degraded.Swap(true) {
+			l.logger.WithContext(ctx).Warn("redis rate limiter unavailable, limiting in process",
+				"error", errors.FromRedis(err))
+		}
+
+		return l.fallback.Allow(ctx, key, rule)
+	}
+
+	if l.degraded.Swap(false) {
+		l.logger.WithContext(ctx).Info("redis rate limiter recovered")
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+
+	"task-queue/pkg/logger"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRedisLimiter(t *testing.T, server *miniredis.Miniredis) *RedisLimiter {
+	t.Helper()
+
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+
+	l, err := NewRedisLimiter(client, "test:ratelimit", logger.NewNop())
+	require.NoError(t, err)
+	return l
+}
+
+func TestRedisLimiter_SharedAcrossReplicas(t *testing.T) {
+	server := miniredis.RunT(t)
+	first, second := newRedisLimiter(t, server), newRedisLimiter(t, server)
+	rule := Rule{Rate: 1, Burst: 2}
+	ctx := context.Background()
+
+	result, err := first.Allow(ctx, "client", rule)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+
+	result, err = second.Allow(ctx, "client", rule)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	result, err = first.Allow(ctx, "client", rule)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed, "the replicas share one bucket")
+	assert.Positive(t, result.RetryAfter)
+
+	result, err = second.Allow(ctx, "other", rule)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	assert.True(t, server.Exists("test:ratelimit:client"))
+	assert.Positive(t, server.TTL("test:ratelimit:client"))
+}
+
+func TestRedisLimiter_FallsBackWhenUnavailable(t *testing.T) {
+	server := miniredis.RunT(t)
+	l := newRedisLimiter(t, server)
+	server.Close()
+
+	rule := Rule{Rate: 1, Burst: 1}
+	ctx := context.Background()
+
+	result, err := l.Allow(ctx, "client", rule)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	result, err = l.Allow(ctx, "client", rule)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed, "the fallback still limits")
+	assert.True(t, l.degraded.Load())
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+
+	"task-queue/internal/models"
+	"task-queue/internal/tracing"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+	"task-queue/pkg/validation"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// MaxSubmitBatch caps the number of jobs of one SubmitBatch call unless
+// BatchLimit raises it
+const MaxSubmitBatch = 100
+
+// SubmitResult is the outcome of one job of SubmitBatch
+type SubmitResult struct {
+	Job *models.Job
+	Err error
+}
+
+// BatchOption customizes SubmitBatch
+type BatchOption func(*batchOptions)
+
+// batchOptions holds the settings of one SubmitBatch call
+type batchOptions struct {
+	limit  int
+	atomic bool
+}
+
+// BatchLimit caps the batch at n jobs instead of MaxSubmitBatch
+func BatchLimit(n int) BatchOption {
+	return func(o *batchOptions) {
+		o.limit = n
+	}
+}
+
+// Atomic makes the batch all or nothing when atomic is set: a single
+// invalid job rejects every job of the batch
+func Atomic(atomic bool) BatchOption {
+	return func(o *batchOptions) {
+		o.atomic = atomic
+	}
+}
+
+// SubmitBatch validates each of reqs, stores the valid jobs together, and
+// enqueues them, returning one result per request in order. An invalid job
+// is rejected in its result without failing the others, unless the batch
+// is Atomic. The jobs are stored in one transaction, so a storage or queue
+// failure fails the whole call and submits none of them; it also fails
+// when reqs is empty or over the batch limit. The stored jobs share one
+// job.enqueue span.
+func (s *JobService) SubmitBatch(ctx context.Context, reqs []*models.JobRequest,
+	opts ...BatchOption) (_ []SubmitResult, err error) {
+	cfg := batchOptions{limit: MaxSubmitBatch}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if len(reqs) == 0 || len(reqs) > cfg.limit {
+		return nil, errors.Validation("a batch must hold between 1 and %d jobs, got %d",
+			cfg.limit, len(reqs)).
+			WithMetadata("max_jobs", cfg.limit).
+			WithOp("service.SubmitBatch")
+	}
+
+	results := make([]SubmitResult, len(reqs))
+	jobs := make([]*models.Job, 0, len(reqs))
+	accepted := make([]int, 0, len(reqs))
+	for i, req := range reqs {
+		if err := validation.ValidateJobRequest(req, s.validation...); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		jobs = append(jobs, models.NewJobFromRequest(req))
+		accepted = append(accepted, i)
+	}
+
+	if cfg.atomic && len(jobs) < len(reqs) {
+		for _, i := range accepted {
+			results[i].Err = errors.Validation("not submitted: another job of the atomic batch is invalid").
+				WithKey("job.batch_rejected").
+				WithOp("service.SubmitBatch")
+		}
+
+		return results, nil
+	}
+
+	if len(jobs) == 0 {
+		return results, nil
+	}
+
+	ctx, span := s.startEnqueue(ctx, attribute.Int("job.count", len(jobs)))
+	defer func() { tracing.End(span, err) }()
+
+	for _, job := range jobs {
+		tracing.Inject(ctx, job)
+	}
+
+	if err := s.store.CreateBatch(ctx, jobs); err != nil {
+		return nil, errors.Wrap(err, "failed to store job batch").WithOp("service.SubmitBatch")
+	}
+
+	if err := s.queue.EnqueueBatch(ctx, jobs); err != nil {
+		s.abandonBatch(context.WithoutCancel(ctx), jobs, err)
+		return nil, errors.Wrap(err, "failed to enqueue job batch").WithOp("service.SubmitBatch")
+	}
+
+	for k, job := range jobs {
+		results[accepted[k]].Job = job
+		s.recordEvent(ctx, job, logger.AuditJobCreated, map[string]any{
+			"priority": job.Priority,
+			"batch":    len(jobs),
+		})
+	}
+
+	return results, nil
+}
+
+// abandonBatch takes the jobs of a batch that failed to enqueue back out of
+// the queue, since part of it may have been enqueued, and marks them failed
+func (s *JobService) abandonBatch(ctx context.Context, jobs []*models.Job, cause error) {
+	for _, job := range jobs {
+		if err := s.queue.Delete(ctx, job.ID); err != nil && !errors.HasCode(err, errors.CodeNotFound) {
+			s.logger.WithContext(ctx).Error("failed to remove unqueued job from queue",
+				"job_id", job.ID, "error", err)
+		}
+
+		s.abandon(ctx, job, cause)
+	}
+}
@@ -0,0 +1,23 @@
+// Package service implements the job operations shared by the HTTP and
+// gRPC APIs: submitting jobs alone or in batches, reading, listing,
+// watching, and cancelling them, and reading their audit trail.
+//
+// Errors are *errors.Error values that each transport maps to its own
+// status: problem details over HTTP, status codes over gRPC. Invalid
+// requests fail with CodeValidation, unknown jobs with CodeNotFound, and
+// cancelling a running or finished job with CodeConflict.
+//
+// WithPublisher publishes the jobs it creates and cancels to an event bus,
+// alongside the events workers publish as they process them.
+//
+// WithTracerProvider traces each submission as a job.enqueue span and
+// stores its context in the job's metadata, so the worker that processes
+// the job continues the submitter's trace. See package tracing.
+//
+// Creating the service once and sharing it between transports:
+//
+//	svc := service.New(jobStore, eventStore, q, log,
+//	    service.WithValidation(validation.WithSchemaLookup(schemas)))
+//	h := api.New(svc, log)
+//	srv := grpcserver.New(svc, log)
+package service
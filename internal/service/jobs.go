@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"task-queue/internal/eventbus"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/internal/storage"
+	"task-queue/internal/tracing"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+	"task-queue/pkg/validation"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultWatchInterval is how often Watch polls the store for changes
+const defaultWatchInterval = 500 * time.Millisecond
+
+// apiActor is the created_by of events recorded by the service for an
+// anonymous caller. An authenticated caller is named by the logger.UserIDKey
+// of the request context.
+const apiActor = "api"
+
+// Option configures a JobService
+type Option func(*JobService)
+
+// WithValidation passes opts to validation.ValidateJobRequest for every
+// submitted job
+func WithValidation(opts ...validation.JobValidationOption) Option {
+	return func(s *JobService) {
+		s.validation = opts
+	}
+}
+
+// WithWatchInterval sets how often Watch polls for status changes
+func WithWatchInterval(interval time.Duration) Option {
+	return func(s *JobService) {
+		s.watchInterval = interval
+	}
+}
+
+// WithPublisher publishes job creation and cancellation to p
+func WithPublisher(p eventbus.Publisher) Option {
+	return func(s *JobService) {
+		s.publisher = p
+	}
+}
+
+// WithTracerProvider starts the enqueue spans of submitted jobs on tp
+// instead of the global provider
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(s *JobService) {
+		s.tracer = tracing.Tracer(tp)
+	}
+}
+
+// JobService implements the job operations behind the HTTP and gRPC APIs:
+// submitting, reading, listing, watching, and cancelling jobs
+type JobService struct {
+	store         storage.JobStore
+	events        storage.EventStore
+	queue         queue.Queue
+	publisher     eventbus.Publisher
+	tracer        trace.Tracer
+	logger        logger.Logger
+	validation    []validation.JobValidationOption
+	watchInterval time.Duration
+}
+
+// New creates a job service over store, events, and q
+func New(store storage.JobStore, events storage.EventStore, q queue.Queue,
+	log logger.Logger, opts ...Option) *JobService {
+	s := &JobService{
+		store:         store,
+		events:        events,
+		queue:         q,
+		tracer:        tracing.Tracer(nil),
+		logger:        log.Named("jobs"),
+		watchInterval: defaultWatchInterval,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Submit validates req, stores the job, and enqueues it. A stored job that
+// cannot be enqueued is marked failed. The job carries the context of the
+// job.enqueue span in its metadata, for the worker that processes it.
+func (s *JobService) Submit(ctx context.Context, req *models.JobRequest) (_ *models.Job, err error) {
+	if err := validation.ValidateJobRequest(req, s.validation...); err != nil {
+		return nil, err
+	}
+
+	job := models.NewJobFromRequest(req)
+	ctx, span := s.startEnqueue(ctx, tracing.JobAttributes(job)...)
+	defer func() { tracing.End(span, err) }()
+
+	tracing.Inject(ctx, job)
+	if err := s.store.Create(ctx, job); err != nil {
+		return nil, errors.Wrap(err, "failed to store job").WithOp("service.Submit")
+	}
+
+	if err := s.queue.Enqueue(ctx, job); err != nil {
+		s.abandon(context.WithoutCancel(ctx), job, err)
+		return nil, errors.Wrap(err, "failed to enqueue job").WithOp("service.Submit")
+	}
+
+	s.recordEvent(ctx, job, logger.AuditJobCreated, map[string]any{
+		"priority": job.Priority,
+	})
+
+	return job, nil
+}
+
+// startEnqueue starts the producer span of a submission
+func (s *JobService) startEnqueue(ctx context.Context, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, "job.enqueue",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attrs...))
+}
+
+// abandon marks a stored job that could not be enqueued as failed, so its
+// row does not stay pending forever
+func (s *JobService) abandon(ctx context.Context, job *models.Job, cause error) {
+	now := time.Now().UTC()
+	reason := "enqueue failed: " + cause.Error()
+	job.Status = models.JobStatusFailed
+	job.CompletedAt = &now
+	job.Error = &reason
+
+	if err := s.store.UpdateStatus(ctx, job); err != nil {
+		s.logger.WithContext(ctx).Error("failed to mark unqueued job failed",
+			"job_id", job.ID, "error", err)
+	}
+}
+
+// Get returns the job with id
+func (s *JobService) Get(ctx context.Context, id uuid.UUID) (*models.Job, error) {
+	return s.store.Get(ctx, id)
+}
+
+// Cancel removes a job that has not started from the queue and marks it
+// cancelled. Running and finished jobs fail with a CodeConflict error. A
+// worker that dequeues the job at the same moment may still run it.
+func (s *JobService) Cancel(ctx context.Context, id uuid.UUID) (*models.Job, error) {
+	job, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case job.Status == models.JobStatusRunning:
+		return nil, errors.Conflict("job %s is running", id).
+			WithKey("job.running", id).
+			WithOp("service.Cancel")
+
+	case job.Status.Terminal():
+		return nil, errors.Conflict("job %s is already %s", id, job.Status).
+			WithKey("job.finished", id, job.Status).
+			WithOp("service.Cancel")
+	}
+
+	// A job missing from the queue was already settled or never enqueued;
+	// its stored state still needs cancelling
+	if err := s.queue.Delete(ctx, id); err != nil && !errors.HasCode(err, errors.CodeNotFound) {
+		return nil, errors.Wrap(err, "failed to remove job from queue").WithOp("service.Cancel")
+	}
+
+	previous := job.Status
+	now := time.Now().UTC()
+	job.Status = models.JobStatusCancelled
+	job.CompletedAt = &now
+
+	if err := s.store.UpdateStatus(ctx, job); err != nil {
+		return nil, errors.Wrap(err, "failed to cancel job").WithOp("service.Cancel")
+	}
+
+	s.recordEvent(ctx, job, logger.AuditJobCancelled, map[string]any{
+		"previous_status": previous,
+	})
+
+	return job, nil
+}
+
+// Events returns the audit trail of the job with id, oldest first
+func (s *JobService) Events(ctx context.Context, id uuid.UUID) ([]models.JobEvent, error) {
+	if _, err := s.store.Get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	events, err := s.events.ListEvents(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if events == nil {
+		events = []models.JobEvent{}
+	}
+
+	return events, nil
+}
+
+// recordEvent appends an event to job's audit trail and publishes it when
+// a publisher is configured. A failed write is logged, since the change it
+// records already happened.
+func (s *JobService) recordEvent(ctx context.Context, job *models.Job, eventType string,
+	data map[string]any) {
+	event := models.NewJobEvent(job.ID, eventType, data)
+	actor := apiActor
+	if user, ok := ctx.Value(logger.UserIDKey).(string); ok && user != "" {
+		actor = user
+	}
+
+	event.CreatedBy = &actor
+
+	if err := s.events.RecordEvent(ctx, event); err != nil {
+		s.logger.WithContext(ctx).Warn("failed to record job event",
+			"job_id", job.ID, "event", eventType, "error", err)
+	}
+
+	if s.publisher == nil {
+		return
+	}
+
+	if err := s.publisher.Publish(ctx, eventbus.NewEvent(eventType, job)); err != nil {
+		s.logger.WithContext(ctx).Warn("failed to publish job event",
+			"job_id", job.ID, "event", eventType, "error", err)
+	}
+}
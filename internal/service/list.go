@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/validation"
+)
+
+// Listing limits
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 200
+)
+
+// DefaultSort lists the newest jobs first
+const DefaultSort = "-created_at"
+
+// SortNames are the accepted sorts: a field, prefixed with - for descending
+// order
+var SortNames = []string{"-created_at", "created_at", "-updated_at", "updated_at"}
+
+// sorts maps each of SortNames to the order it selects
+var sorts = map[string]struct {
+	field     models.JobSortField
+	ascending bool
+}{
+	"created_at":  {models.JobSortCreatedAt, true},
+	"-created_at": {models.JobSortCreatedAt, false},
+	"updated_at":  {models.JobSortUpdatedAt, true},
+	"-updated_at": {models.JobSortUpdatedAt, false},
+}
+
+// ListQuery is a job listing request. Filter selects the jobs; its order
+// and position come from Sort, which defaults to DefaultSort, and Cursor,
+// the NextCursor of the previous page. Filter.Limit defaults to
+// DefaultListLimit and is capped at MaxListLimit.
+type ListQuery struct {
+	Filter models.JobFilter
+	Sort   string
+	Cursor string
+}
+
+// ListPage is one page of a job listing. NextCursor is empty on the last
+// page.
+type ListPage struct {
+	Items         []*models.Job
+	NextCursor    string
+	TotalEstimate int64
+}
+
+// listCursor is the decoded form of a cursor. It records the sort it was
+// issued for, so a cursor cannot continue a listing in another order.
+type listCursor struct {
+	Sort string `json:"s"`
+	models.JobCursor
+}
+
+// EncodeCursor returns the opaque cursor continuing a listing in sort order
+// after cursor
+func EncodeCursor(sort string, cursor *models.JobCursor) (string, error) {
+	data, err := json.Marshal(listCursor{Sort: sort, JobCursor: *cursor})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode cursor").
+			WithCode(errors.CodeSerialization).
+			WithOp("service.EncodeCursor")
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes a cursor issued by EncodeCursor for sort
+func DecodeCursor(sort, s string) (*models.JobCursor, error) {
+	var cursor listCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || json.Unmarshal(data, &cursor) != nil {
+		return nil, fmt.Errorf("is not a valid cursor")
+	}
+
+	if cursor.Sort != sort {
+		return nil, fmt.Errorf("was issued for sort %s", cursor.Sort)
+	}
+
+	return &cursor.JobCursor, nil
+}
+
+// List returns a page of the jobs matching q. An unknown sort or a cursor
+// issued for another sort fails with a validation error naming the sort or
+// cursor field.
+func (s *JobService) List(ctx context.Context, q ListQuery) (*ListPage, error) {
+	sort := q.Sort
+	if sort == "" {
+		sort = DefaultSort
+	}
+
+	filter := q.Filter
+	err := validation.ValidateAll(
+		validation.NewField("sort", sort, validation.OneOfStrings(SortNames...)),
+		validation.NewField("cursor", q.Cursor, validation.ValidatorFunc(func(any) error {
+			if q.Cursor == "" {
+				return nil
+			}
+
+			cursor, err := DecodeCursor(sort, q.Cursor)
+			filter.After = cursor
+			return err
+		})),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.SortBy = sorts[sort].field
+	filter.Ascending = sorts[sort].ascending
+	switch {
+	case filter.Limit <= 0:
+		filter.Limit = DefaultListLimit
+
+	case filter.Limit > MaxListLimit:
+		filter.Limit = MaxListLimit
+	}
+
+	page, err := s.store.List(ctx, &filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ListPage{Items: page.Items, TotalEstimate: page.TotalEstimate}
+	if page.Next != nil {
+		if result.NextCursor, err = EncodeCursor(sort, page.Next); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
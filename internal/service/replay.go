@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"maps"
+
+	"task-queue/internal/models"
+	"task-queue/internal/tracing"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+	"task-queue/pkg/validation"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ReplayJob runs a finished job again: it submits a new job with the type,
+// payload, priority, retry limit, and metadata of the original, changed by
+// overrides, and with models.ReplayMetadataKey naming the original. The new
+// job is validated like a submission. Both jobs record the replay in their
+// audit trails. A job that has not finished fails with a CodeConflict error.
+func (s *JobService) ReplayJob(ctx context.Context, id uuid.UUID,
+	overrides models.ReplayOverrides) (_ *models.Job, err error) {
+	original, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !original.Status.Terminal() {
+		return nil, errors.Conflict("job %s is still %s", id, original.Status).
+			WithKey("job.not_finished", id, original.Status).
+			WithOp("service.ReplayJob")
+	}
+
+	req := replayRequest(original, overrides)
+	if err := validation.ValidateJobRequest(req, s.validation...); err != nil {
+		return nil, err
+	}
+
+	job := models.NewJobFromRequest(req)
+	ctx, span := s.startEnqueue(ctx, append(tracing.JobAttributes(job),
+		attribute.String("job.replay_of", id.String()))...)
+	defer func() { tracing.End(span, err) }()
+
+	tracing.Inject(ctx, job)
+	if err := s.store.Create(ctx, job); err != nil {
+		return nil, errors.Wrap(err, "failed to store replayed job").WithOp("service.ReplayJob")
+	}
+
+	if err := s.queue.Enqueue(ctx, job); err != nil {
+		s.abandon(context.WithoutCancel(ctx), job, err)
+		return nil, errors.Wrap(err, "failed to enqueue replayed job").WithOp("service.ReplayJob")
+	}
+
+	s.recordEvent(ctx, job, logger.AuditJobCreated, map[string]any{
+		"priority":               job.Priority,
+		models.ReplayMetadataKey: id.String(),
+	})
+	s.recordEvent(ctx, original, logger.AuditJobReplayed, map[string]any{
+		"replay_id": job.ID.String(),
+	})
+
+	return job, nil
+}
+
+// replayRequest returns the request submitting original again with
+// overrides applied. The original's trace context is left out, so the
+// replay joins the trace of whoever asked for it.
+func replayRequest(original *models.Job, overrides models.ReplayOverrides) *models.JobRequest {
+	metadata := maps.Clone(original.Metadata)
+	if metadata == nil {
+		metadata = make(map[string]any)
+	}
+
+	delete(metadata, tracing.MetadataKey)
+	maps.Copy(metadata, overrides.Metadata)
+	metadata[models.ReplayMetadataKey] = original.ID.String()
+
+	req := &models.JobRequest{
+		Type:        original.Type,
+		Payload:     original.Payload,
+		Priority:    original.Priority,
+		MaxRetries:  &original.MaxRetries,
+		ScheduledAt: overrides.ScheduledAt,
+		Metadata:    metadata,
+	}
+
+	if overrides.Priority != nil {
+		req.Priority = *overrides.Priority
+	}
+
+	return req
+}
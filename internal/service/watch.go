@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// Watch calls send with the job with id, then again whenever its status or
+// retry count changes, until the job reaches a terminal status. It polls the
+// store every watch interval and returns the context's error once ctx is
+// done, or the first error of send.
+func (s *JobService) Watch(ctx context.Context, id uuid.UUID, send func(*models.Job) error) error {
+	job, err := s.store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := send(job); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(s.watchInterval)
+	defer ticker.Stop()
+
+	for !job.Status.Terminal() {
+		select {
+		case <-ctx.Done():
+			return errors.FromContext(ctx.Err())
+
+		case <-ticker.C:
+		}
+
+		current, err := s.store.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if current.Status == job.Status && current.RetryCount == job.RetryCount {
+			continue
+		}
+
+		job = current
+		if err := send(job); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"task-queue/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// IssueAPIKey creates and stores a key for name with scopes. The returned
+// key is the only copy of it; the store keeps its hash.
+func IssueAPIKey(ctx context.Context, store APIKeyStore, name string, scopes []string) (string, *models.APIKey, error) {
+	secret, key, err := models.NewAPIKey(name, scopes)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := store.CreateAPIKey(ctx, key); err != nil {
+		return "", nil, err
+	}
+
+	return secret, key, nil
+}
+
+// RotateAPIKey replaces the key with id by a new key of the same name and
+// scopes. The old key keeps working for grace, so callers can switch over,
+// unless it already expires sooner.
+func RotateAPIKey(ctx context.Context, store APIKeyStore, id uuid.UUID,
+	grace time.Duration) (string, *models.APIKey, error) {
+	old, err := store.GetAPIKey(ctx, id)
+	if err != nil {
+		return "", nil, err
+	}
+
+	secret, key, err := IssueAPIKey(ctx, store, old.Name, old.Scopes)
+	if err != nil {
+		return "", nil, err
+	}
+
+	expires := time.Now().UTC().Add(grace)
+	if old.ExpiresAt != nil && old.ExpiresAt.Before(expires) {
+		return secret, key, nil
+	}
+
+	if err := store.ExpireAPIKey(ctx, id, expires); err != nil {
+		return "", nil, err
+	}
+
+	return secret, key, nil
+}
+
+// RevokeAPIKey expires the key with id now
+func RevokeAPIKey(ctx context.Context, store APIKeyStore, id uuid.UUID) error {
+	return store.ExpireAPIKey(ctx, id, time.Now().UTC())
+}
@@ -13,9 +13,26 @@
 //	// Create a job
 //	err := repo.Create(ctx, job)
 //
-//	// Find jobs by status
-//	jobs, err := repo.FindByStatus(ctx, models.JobStatusPending)
+//	// Record the outcome of processing it
+//	job.Status = models.JobStatusCompleted
+//	err = repo.UpdateStatus(ctx, job)
 //
-//	// Update job status
-//	err := repo.UpdateStatus(ctx, jobID, models.JobStatusCompleted)
+// Passing WithAudit records each status UpdateStatus writes on the audit
+// stream, when log.audit.enabled is set:
+//
+//	repo := storage.NewJobRepository(db, logger,
+//	    storage.WithAudit(logger.NewAudit(cfg.Log.AuditConfig())))
+//
+// JobRepository implements JobStore and EventRepository implements
+// EventStore. MemoryJobStore and MemoryEventStore are in-process versions
+// for tests. CreateBatch stores a batch of jobs in one transaction, so
+// either all of them are created or none is.
+//
+// APIKeyRepository stores API keys by their SHA-256 hash, implementing
+// APIKeyStore like MemoryAPIKeyStore. IssueAPIKey creates a key, returning
+// it once; RotateAPIKey replaces one, keeping the old key valid for a grace
+// period; RevokeAPIKey expires one now:
+//
+//	secret, key, err := storage.IssueAPIKey(ctx, keys, "ci", []string{"jobs:write"})
+//	secret, key, err = storage.RotateAPIKey(ctx, keys, key.ID, 24*time.Hour)
 package storage
@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"context"
+
+	"task-queue/pkg/errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// HealthCheck returns a check that pings the database of db, for
+// registering with a health checker. It does not retry, so an outage is
+// reported at once.
+func HealthCheck(db *sqlx.DB) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if err := db.PingContext(ctx); err != nil {
+			return errors.Wrap(errors.FromPostgres(err), "postgres is unreachable").
+				WithOp("storage.HealthCheck")
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,231 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Listing limits
+const (
+	// DefaultListLimit is the page size of a JobFilter without a Limit
+	DefaultListLimit = 50
+
+	// totalEstimateCap bounds the count behind JobPage.TotalEstimate, so a
+	// broad filter does not scan the whole table
+	totalEstimateCap = 10000
+)
+
+// listOrder returns the sort field and page size of filter, rejecting sort
+// fields stores cannot order by
+func listOrder(filter *models.JobFilter) (models.JobSortField, int, error) {
+	field := filter.SortBy
+	switch field {
+	case "":
+		field = models.JobSortCreatedAt
+
+	case models.JobSortCreatedAt, models.JobSortUpdatedAt:
+
+	default:
+		return "", 0, errors.Validation("cannot sort jobs by %q", field).
+			WithOp("storage.List")
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+
+	return field, limit, nil
+}
+
+// listedBefore reports whether a comes before b in a listing sorted by
+// field
+func listedBefore(a, b *models.Job, field models.JobSortField, ascending bool) bool {
+	return compareListed(a.SortKey(field), a.ID, b.SortKey(field), b.ID, ascending) < 0
+}
+
+// pastCursor reports whether job comes after cursor in a listing sorted by
+// field
+func pastCursor(job *models.Job, cursor *models.JobCursor, field models.JobSortField,
+	ascending bool) bool {
+	return compareListed(job.SortKey(field), job.ID, cursor.At, cursor.ID, ascending) > 0
+}
+
+// compareListed compares the listing positions (at, id) and (otherAt,
+// otherID), ordered by time and then by ID
+func compareListed(at time.Time, id uuid.UUID, otherAt time.Time, otherID uuid.UUID,
+	ascending bool) int {
+	c := at.Compare(otherAt)
+	if c == 0 {
+		c = bytes.Compare(id[:], otherID[:])
+	}
+
+	if !ascending {
+		c = -c
+	}
+
+	return c
+}
+
+// matchesFilter reports whether job matches every set field of filter,
+// ignoring the cursor
+func matchesFilter(job *models.Job, filter *models.JobFilter) bool {
+	if len(filter.Statuses) > 0 && !contains(filter.Statuses, job.Status) {
+		return false
+	}
+
+	if len(filter.Types) > 0 && !contains(filter.Types, job.Type) {
+		return false
+	}
+
+	if len(filter.Priorities) > 0 && !contains(filter.Priorities, job.Priority) {
+		return false
+	}
+
+	if filter.CreatedAfter != nil && !job.CreatedAt.After(*filter.CreatedAfter) {
+		return false
+	}
+
+	if filter.CreatedBefore != nil && !job.CreatedAt.Before(*filter.CreatedBefore) {
+		return false
+	}
+
+	tags := metadataTags(job.Metadata)
+	for _, tag := range filter.Tags {
+		if !contains(tags, tag) {
+			return false
+		}
+	}
+
+	for key, value := range filter.Metadata {
+		actual, ok := job.Metadata[key]
+		if !ok || fmt.Sprint(actual) != value {
+			return false
+		}
+	}
+
+	if filter.Search != "" {
+		search := strings.ToLower(filter.Search)
+		text := job.ID.String() + "\x00" + strings.ToLower(job.Type)
+		if job.Error != nil {
+			text += "\x00" + strings.ToLower(*job.Error)
+		}
+
+		if !strings.Contains(text, search) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// metadataTags returns the strings in the "tags" list of metadata
+func metadataTags(metadata map[string]any) []string {
+	var tags []string
+	switch list := metadata["tags"].(type) {
+	case []string:
+		tags = list
+
+	case []any:
+		for _, tag := range list {
+			if s, ok := tag.(string); ok {
+				tags = append(tags, s)
+			}
+		}
+	}
+
+	return tags
+}
+
+// contains reports whether values holds v
+func contains[T comparable](values []T, v T) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// jobConditions returns the SQL conditions and arguments selecting the jobs
+// that match filter, ignoring the cursor. Arguments are numbered from $1.
+func jobConditions(filter *models.JobFilter) ([]string, []any) {
+	var (
+		conditions []string
+		args       []any
+	)
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			statuses[i] = string(status)
+		}
+
+		add("status = ANY($%d::job_status[])", pq.Array(statuses))
+	}
+
+	if len(filter.Types) > 0 {
+		add("type = ANY($%d)", pq.Array(filter.Types))
+	}
+
+	if len(filter.Priorities) > 0 {
+		priorities := make([]string, len(filter.Priorities))
+		for i, priority := range filter.Priorities {
+			priorities[i] = priority.Name()
+		}
+
+		add("priority = ANY($%d::job_priority[])", pq.Array(priorities))
+	}
+
+	if filter.CreatedAfter != nil {
+		add("created_at > $%d", *filter.CreatedAfter)
+	}
+
+	if filter.CreatedBefore != nil {
+		add("created_at < $%d", *filter.CreatedBefore)
+	}
+
+	if len(filter.Tags) > 0 {
+		add("metadata->'tags' ?& $%d", pq.Array(filter.Tags))
+	}
+
+	for key, value := range filter.Metadata {
+		args = append(args, key, value)
+		conditions = append(conditions,
+			fmt.Sprintf("metadata->>$%d = $%d", len(args)-1, len(args)))
+	}
+
+	if filter.Search != "" {
+		add("(id::text ILIKE $%[1]d OR type ILIKE $%[1]d OR error ILIKE $%[1]d)",
+			"%"+escapeLike(filter.Search)+"%")
+	}
+
+	return conditions, args
+}
+
+// escapeLike escapes the LIKE wildcards in s
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// whereClause joins conditions into a WHERE clause, or returns an empty
+// string when there are none
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND ")
+}
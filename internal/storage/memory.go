@@ -0,0 +1,308 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// MemoryJobStore is an in-process JobStore for tests and single-node
+// development setups. Jobs are copied in and out, so callers never share
+// state with the store.
+type MemoryJobStore struct {
+	mu   sync.RWMutex
+	jobs map[uuid.UUID]*models.Job
+}
+
+var _ JobStore = (*MemoryJobStore)(nil)
+
+// NewMemoryJobStore creates an empty MemoryJobStore
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{jobs: make(map[uuid.UUID]*models.Job)}
+}
+
+// Create inserts a new job
+func (s *MemoryJobStore) Create(ctx context.Context, job *models.Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[job.ID]; ok {
+		return errors.New("job already exists").
+			WithCode(errors.CodeAlreadyExists).
+			WithKey("job.already_exists", job.ID).
+			WithOp("storage.Create")
+	}
+
+	s.jobs[job.ID] = copyJob(job)
+	return nil
+}
+
+// CreateBatch inserts jobs atomically. When one of them already exists,
+// none is inserted.
+func (s *MemoryJobStore) CreateBatch(ctx context.Context, jobs []*models.Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := make(map[uuid.UUID]bool, len(jobs))
+	for i, job := range jobs {
+		if _, ok := s.jobs[job.ID]; ok || seen[job.ID] {
+			return errors.Newf("job with ID %s already exists", job.ID).
+				WithCode(errors.CodeAlreadyExists).
+				WithKey("job.already_exists", job.ID).
+				WithMetadata("index", i).
+				WithOp("storage.CreateBatch")
+		}
+
+		seen[job.ID] = true
+	}
+
+	for _, job := range jobs {
+		s.jobs[job.ID] = copyJob(job)
+	}
+
+	return nil
+}
+
+// Get retrieves a job by ID
+func (s *MemoryJobStore) Get(ctx context.Context, id uuid.UUID) (*models.Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, errors.NotFound("job %s not found", id).
+			WithKey("job.not_found", id).
+			WithOp("storage.Get")
+	}
+
+	return copyJob(job), nil
+}
+
+// UpdateStatus writes the processing state of job
+func (s *MemoryJobStore) UpdateStatus(ctx context.Context, job *models.Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.jobs[job.ID]
+	if !ok {
+		return errors.NotFound("job %s not found", job.ID).
+			WithKey("job.not_found", job.ID).
+			WithOp("storage.UpdateStatus")
+	}
+
+	update := copyJob(job)
+	stored.Status = update.Status
+	stored.RetryCount = update.RetryCount
+	stored.StartedAt = update.StartedAt
+	stored.CompletedAt = update.CompletedAt
+	stored.Error = update.Error
+	stored.Result = update.Result
+	stored.WorkerID = update.WorkerID
+	return nil
+}
+
+// List returns the page of jobs matching filter, continuing after
+// filter.After
+func (s *MemoryJobStore) List(ctx context.Context, filter *models.JobFilter) (*models.JobPage, error) {
+	field, limit, err := listOrder(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matched []*models.Job
+	for _, job := range s.jobs {
+		if matchesFilter(job, filter) {
+			matched = append(matched, job)
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return listedBefore(matched[i], matched[j], field, filter.Ascending)
+	})
+
+	page := &models.JobPage{TotalEstimate: int64(min(len(matched), totalEstimateCap))}
+	for _, job := range matched {
+		if filter.After != nil && !pastCursor(job, filter.After, field, filter.Ascending) {
+			continue
+		}
+
+		if len(page.Items) == limit {
+			page.Next = page.Items[limit-1].CursorFor(field)
+			break
+		}
+
+		page.Items = append(page.Items, copyJob(job))
+	}
+
+	return page, nil
+}
+
+// copyJob copies job deeply enough that no pointer or slice is shared
+func copyJob(job *models.Job) *models.Job {
+	c := *job
+	c.Payload = append(json.RawMessage(nil), job.Payload...)
+	c.Result = append(json.RawMessage(nil), job.Result...)
+	c.ScheduledAt = copyPtr(job.ScheduledAt)
+	c.StartedAt = copyPtr(job.StartedAt)
+	c.CompletedAt = copyPtr(job.CompletedAt)
+	c.Error = copyPtr(job.Error)
+	c.WorkerID = copyPtr(job.WorkerID)
+	if job.Metadata != nil {
+		c.Metadata = make(map[string]any, len(job.Metadata))
+		for k, v := range job.Metadata {
+			c.Metadata[k] = v
+		}
+	}
+
+	return &c
+}
+
+// copyPtr returns a pointer to a copy of *p, or nil
+func copyPtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+
+	v := *p
+	return &v
+}
+
+// MemoryEventStore is an in-process EventStore for tests
+type MemoryEventStore struct {
+	mu     sync.RWMutex
+	events []models.JobEvent
+}
+
+var _ EventStore = (*MemoryEventStore)(nil)
+
+// NewMemoryEventStore creates an empty MemoryEventStore
+func NewMemoryEventStore() *MemoryEventStore {
+	return &MemoryEventStore{}
+}
+
+// RecordEvent appends event to its job's audit trail
+func (s *MemoryEventStore) RecordEvent(ctx context.Context, event *models.JobEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, *event)
+	return nil
+}
+
+// ListEvents returns the events recorded for jobID, oldest first
+func (s *MemoryEventStore) ListEvents(ctx context.Context, jobID uuid.UUID) ([]models.JobEvent, error) {
+	return s.Events(jobID), nil
+}
+
+// Events returns the events recorded for jobID in the order they were
+// recorded
+func (s *MemoryEventStore) Events(jobID uuid.UUID) []models.JobEvent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var events []models.JobEvent
+	for _, event := range s.events {
+		if event.JobID == jobID {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+// MemoryAPIKeyStore is an in-process APIKeyStore for tests
+type MemoryAPIKeyStore struct {
+	mu   sync.RWMutex
+	keys map[uuid.UUID]models.APIKey
+}
+
+var _ APIKeyStore = (*MemoryAPIKeyStore)(nil)
+
+// NewMemoryAPIKeyStore creates an empty MemoryAPIKeyStore
+func NewMemoryAPIKeyStore() *MemoryAPIKeyStore {
+	return &MemoryAPIKeyStore{keys: make(map[uuid.UUID]models.APIKey)}
+}
+
+// CreateAPIKey inserts key
+func (s *MemoryAPIKeyStore) CreateAPIKey(ctx context.Context, key *models.APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, stored := range s.keys {
+		if stored.ID == key.ID || stored.KeyHash == key.KeyHash {
+			return errors.New("api key already exists").
+				WithCode(errors.CodeAlreadyExists).
+				WithKey("api_key.already_exists", key.ID).
+				WithOp("storage.CreateAPIKey")
+		}
+	}
+
+	s.keys[key.ID] = copyAPIKey(*key)
+	return nil
+}
+
+// GetAPIKey retrieves a key by ID
+func (s *MemoryAPIKeyStore) GetAPIKey(ctx context.Context, id uuid.UUID) (*models.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, errors.NotFound("api key %s not found", id).
+			WithKey("api_key.not_found", id).
+			WithOp("storage.GetAPIKey")
+	}
+
+	key = copyAPIKey(key)
+	return &key, nil
+}
+
+// FindAPIKey retrieves the key whose hash is hash
+func (s *MemoryAPIKeyStore) FindAPIKey(ctx context.Context, hash string) (*models.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, key := range s.keys {
+		if key.KeyHash == hash {
+			key = copyAPIKey(key)
+			return &key, nil
+		}
+	}
+
+	return nil, errors.NotFound("api key not found").
+		WithOp("storage.FindAPIKey")
+}
+
+// ExpireAPIKey sets the expiry of the key with id to at
+func (s *MemoryAPIKeyStore) ExpireAPIKey(ctx context.Context, id uuid.UUID, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[id]
+	if !ok {
+		return errors.NotFound("api key %s not found", id).
+			WithKey("api_key.not_found", id).
+			WithOp("storage.ExpireAPIKey")
+	}
+
+	key.ExpiresAt = &at
+	s.keys[id] = key
+	return nil
+}
+
+// copyAPIKey returns key with its own scopes and expiry
+func copyAPIKey(key models.APIKey) models.APIKey {
+	key.Scopes = append([]string(nil), key.Scopes...)
+	key.ExpiresAt = copyPtr(key.ExpiresAt)
+	return key
+}
@@ -2,10 +2,13 @@ package storage
 
 import (
 	"context"
-	"database/sql"
+	"encoding/json"
+	"fmt"
 	"task-queue/internal/models"
 	"task-queue/pkg/errors"
 	"task-queue/pkg/logger"
+	"task-queue/pkg/retry"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -14,63 +17,542 @@ import (
 
 // JobRepository handles job persistence
 type JobRepository struct {
-	db     *sqlx.DB
-	logger logger.Logger
+	db      *sqlx.DB
+	logger  logger.Logger
+	audit   logger.AuditLogger
+	retrier *retry.Retrier
+}
+
+// JobRepositoryOption configures a JobRepository
+type JobRepositoryOption func(*JobRepository)
+
+// WithAudit records every status change UpdateStatus writes on audit.
+// logger.NewAudit returns a no-op logger when log.audit.enabled is unset.
+func WithAudit(audit logger.AuditLogger) JobRepositoryOption {
+	return func(r *JobRepository) {
+		if audit != nil {
+			r.audit = audit
+		}
+	}
 }
 
 // NewJobRepository creates a new job repository
-func NewJobRepository(db *sqlx.DB, log logger.Logger) *JobRepository {
-	return &JobRepository{
-		db:     db,
-		logger: log.Named("job-repo"),
+func NewJobRepository(db *sqlx.DB, log logger.Logger, opts ...JobRepositoryOption) *JobRepository {
+	r := &JobRepository{
+		db:      db,
+		logger:  log.Named("job-repo"),
+		audit:   logger.NopAudit(),
+		retrier: retry.Database(),
 	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
 }
 
-// Create inserts a new job into the database
+// insertJobQuery inserts one job from its named fields
+const insertJobQuery = `
+	INSERT INTO jobs (
+		id, type, payload, status, priority, max_retries,
+		retry_count, created_at, updated_at, scheduled_at,
+		metadata
+	) VALUES (
+		:id, :type, :payload, :status, :priority, :max_retries,
+		:retry_count, :created_at, :updated_at, :scheduled_at,
+		:metadata
+	)`
+
+// Create inserts a new job into the database, retrying transient failures.
+// A retry after an insert that committed reports the job as already
+// existing.
 func (r *JobRepository) Create(ctx context.Context, job *models.Job) error {
-	query := `
-		INSERT INTO jobs (
-			id, type, payload, status, priority, max_retries,
-			retry_count, created_at, updated_at, scheduled_at,
-			metadata
-		) VALUES (
-			:id, :type, :payload, :status, :priority, :max_retries,
-			:retry_count, :created_at, :updated_at, :scheduled_at,
-			:metadata
-		)`
+	err := r.retrier.DoContext(ctx, func(ctx context.Context) error {
+		if _, err := r.db.NamedExecContext(ctx, insertJobQuery, job); err != nil {
+			return errors.FromPostgres(err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		dbErr := errors.FromPostgres(err)
+		if errors.HasCode(dbErr, errors.CodeAlreadyExists) {
+			return errors.Wrapf(dbErr, "job with ID %s already exists", job.ID).
+				WithKey("job.already_exists", job.ID).
+				WithOp("storage.Create")
+		}
+
+		return errors.Wrap(dbErr, "failed to create job").
+			WithOp("storage.Create")
+	}
+
+	r.logger.Debug("job created",
+		logger.UUID("job_id", job.ID),
+		logger.String("type", job.Type),
+		"payload_keys", logger.Lazy(func() any { return job.PayloadKeys() }),
+	)
+	return nil
+}
+
+// CreateBatch inserts jobs in one transaction, retrying transient
+// failures, so either every job is stored or none is. As with Create, a
+// retry after a commit that was not acknowledged reports the jobs as
+// already existing.
+func (r *JobRepository) CreateBatch(ctx context.Context, jobs []*models.Job) error {
+	if len(jobs) == 0 {
+		return nil
+	}
 
-	_, err := r.db.NamedExecContext(ctx, query, job)
+	err := r.retrier.DoContext(ctx, func(ctx context.Context) error {
+		return r.insertAll(ctx, jobs)
+	})
+
+	if err != nil {
+		dbErr := errors.FromPostgres(err)
+		if errors.HasCode(dbErr, errors.CodeAlreadyExists) {
+			return errors.Wrap(dbErr, "a job of the batch already exists").
+				WithOp("storage.CreateBatch")
+		}
+
+		return errors.Wrap(dbErr, "failed to create job batch").
+			WithOp("storage.CreateBatch")
+	}
+
+	r.logger.Debug("job batch created", "count", len(jobs))
+	return nil
+}
+
+// insertAll inserts jobs in a transaction of its own
+func (r *JobRepository) insertAll(ctx context.Context, jobs []*models.Job) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.FromPostgres(err)
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.PrepareNamedContext(ctx, insertJobQuery)
 	if err != nil {
-		if pgErr, ok := err.(*pq.Error); ok {
-			if pgErr.Code == "23505" {
-				return errors.Newf("job with ID %s already exists", job.ID).
-					WithCode(errors.CodeAlreadyExists)
-			}
+		return errors.FromPostgres(err)
+	}
+
+	defer func() { _ = stmt.Close() }()
+
+	for _, job := range jobs {
+		if _, err := stmt.ExecContext(ctx, job); err != nil {
+			return errors.FromPostgres(err)
 		}
+	}
 
-		return errors.Wrap(err, "failed to create job").
-			WithCode(errors.CodeDatabase)
+	if err := tx.Commit(); err != nil {
+		return errors.FromPostgres(err)
 	}
 
-	r.logger.Debug("job created", "job_id", job.ID, "type", job.Type)
 	return nil
 }
 
-// Get retrieves a job by ID
+// Get retrieves a job by ID, retrying transient failures
 func (r *JobRepository) Get(ctx context.Context, id uuid.UUID) (*models.Job, error) {
 	var job models.Job
 	query := `SELECT * FROM jobs WHERE id = $1`
-	err := r.db.GetContext(ctx, &job, query, id)
+	err := r.retrier.DoContext(ctx, func(ctx context.Context) error {
+		if err := r.db.GetContext(ctx, &job, query, id); err != nil {
+			return errors.FromPostgres(err)
+		}
+
+		return nil
+	})
 
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errors.Newf("job %s not found", id).
-				WithCode(errors.CodeNotFound)
+		dbErr := errors.FromPostgres(err)
+		if errors.HasCode(dbErr, errors.CodeNotFound) {
+			return nil, errors.Wrapf(dbErr, "job %s not found", id).
+				WithKey("job.not_found", id).
+				WithOp("storage.Get")
 		}
 
-		return nil, errors.Wrap(err, "failed to get job").
-			WithCode(errors.CodeDatabase)
+		return nil, errors.Wrap(dbErr, "failed to get job").
+			WithOp("storage.Get")
 	}
 
 	return &job, nil
 }
+
+// UpdateStatus writes the processing state of job, retrying transient
+// failures
+func (r *JobRepository) UpdateStatus(ctx context.Context, job *models.Job) error {
+	query := `
+		UPDATE jobs SET
+			status = :status,
+			retry_count = :retry_count,
+			started_at = :started_at,
+			completed_at = :completed_at,
+			error = :error,
+			result = :result,
+			worker_id = :worker_id
+		WHERE id = :id`
+
+	var updated int64
+	err := r.retrier.DoContext(ctx, func(ctx context.Context) error {
+		res, err := r.db.NamedExecContext(ctx, query, job)
+		if err != nil {
+			return errors.FromPostgres(err)
+		}
+
+		if updated, err = res.RowsAffected(); err != nil {
+			return errors.FromPostgres(err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return errors.Wrap(errors.FromPostgres(err), "failed to update job status").
+			WithOp("storage.UpdateStatus")
+	}
+
+	if updated == 0 {
+		return errors.NotFound("job %s not found", job.ID).
+			WithKey("job.not_found", job.ID).
+			WithOp("storage.UpdateStatus")
+	}
+
+	r.logger.Debug("job status updated",
+		logger.UUID("job_id", job.ID),
+		logger.String("status", string(job.Status)),
+	)
+
+	if event, ok := statusAuditEvents[job.Status]; ok {
+		r.audit.JobEvent(ctx, event, job,
+			"retry_count", job.RetryCount,
+		)
+	}
+
+	return nil
+}
+
+// statusAuditEvents maps the statuses UpdateStatus writes to the audit
+// events recording them
+var statusAuditEvents = map[models.JobStatus]string{
+	models.JobStatusPending:   logger.AuditJobRequeued,
+	models.JobStatusRunning:   logger.AuditJobStarted,
+	models.JobStatusCompleted: logger.AuditJobCompleted,
+	models.JobStatusFailed:    logger.AuditJobFailed,
+	models.JobStatusRetrying:  logger.AuditJobFailed,
+	models.JobStatusDead:      logger.AuditJobDeadLettered,
+	models.JobStatusCancelled: logger.AuditJobCancelled,
+}
+
+// List returns the page of jobs matching filter, continuing after
+// filter.After, retrying transient failures. The page is read with one
+// extra row to learn whether another page follows.
+func (r *JobRepository) List(ctx context.Context, filter *models.JobFilter) (*models.JobPage, error) {
+	field, limit, err := listOrder(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions, args := jobConditions(filter)
+	countQuery := fmt.Sprintf(`SELECT count(*) FROM (SELECT 1 FROM jobs %s LIMIT %d) AS matched`,
+		whereClause(conditions), totalEstimateCap)
+	countArgs := args
+
+	order, past := "DESC", "<"
+	if filter.Ascending {
+		order, past = "ASC", ">"
+	}
+
+	if filter.After != nil {
+		args = append(args, filter.After.At, filter.After.ID)
+		conditions = append(conditions,
+			fmt.Sprintf("(%s, id) %s ($%d, $%d)", field, past, len(args)-1, len(args)))
+	}
+
+	query := fmt.Sprintf(`SELECT * FROM jobs %s ORDER BY %s %s, id %s LIMIT %d`,
+		whereClause(conditions), field, order, order, limit+1)
+
+	var (
+		jobs  []*models.Job
+		total int64
+	)
+	err = r.retrier.DoContext(ctx, func(ctx context.Context) error {
+		jobs = jobs[:0]
+		if err := r.db.SelectContext(ctx, &jobs, query, args...); err != nil {
+			return errors.FromPostgres(err)
+		}
+
+		if err := r.db.GetContext(ctx, &total, countQuery, countArgs...); err != nil {
+			return errors.FromPostgres(err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(errors.FromPostgres(err), "failed to list jobs").
+			WithOp("storage.List")
+	}
+
+	page := &models.JobPage{Items: jobs, TotalEstimate: total}
+	if len(jobs) > limit {
+		page.Items = jobs[:limit]
+		page.Next = jobs[limit-1].CursorFor(field)
+	}
+
+	return page, nil
+}
+
+// EventRepository handles job event persistence
+type EventRepository struct {
+	db      *sqlx.DB
+	logger  logger.Logger
+	retrier *retry.Retrier
+}
+
+// NewEventRepository creates a new job event repository
+func NewEventRepository(db *sqlx.DB, log logger.Logger) *EventRepository {
+	return &EventRepository{
+		db:      db,
+		logger:  log.Named("event-repo"),
+		retrier: retry.Database(),
+	}
+}
+
+// RecordEvent inserts event into job_events, retrying transient failures
+func (r *EventRepository) RecordEvent(ctx context.Context, event *models.JobEvent) error {
+	data, err := json.Marshal(event.EventData)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal event data").
+			WithCode(errors.CodeSerialization).
+			WithOp("storage.RecordEvent")
+	}
+
+	query := `
+		INSERT INTO job_events (id, job_id, event_type, event_data, created_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	err = r.retrier.DoContext(ctx, func(ctx context.Context) error {
+		if _, err := r.db.ExecContext(ctx, query, event.ID, event.JobID, event.EventType,
+			data, event.CreatedAt, event.CreatedBy); err != nil {
+			return errors.FromPostgres(err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return errors.Wrap(errors.FromPostgres(err), "failed to record job event").
+			WithOp("storage.RecordEvent")
+	}
+
+	return nil
+}
+
+// eventRow is a job_events row, whose event_data is decoded separately
+type eventRow struct {
+	ID        uuid.UUID `db:"id"`
+	JobID     uuid.UUID `db:"job_id"`
+	EventType string    `db:"event_type"`
+	EventData []byte    `db:"event_data"`
+	CreatedAt time.Time `db:"created_at"`
+	CreatedBy *string   `db:"created_by"`
+}
+
+// ListEvents returns the audit trail of jobID, oldest first, retrying
+// transient failures
+func (r *EventRepository) ListEvents(ctx context.Context, jobID uuid.UUID) ([]models.JobEvent, error) {
+	query := `
+		SELECT id, job_id, event_type, event_data, created_at, created_by
+		FROM job_events
+		WHERE job_id = $1
+		ORDER BY created_at, id`
+
+	var rows []eventRow
+	err := r.retrier.DoContext(ctx, func(ctx context.Context) error {
+		rows = rows[:0]
+		if err := r.db.SelectContext(ctx, &rows, query, jobID); err != nil {
+			return errors.FromPostgres(err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(errors.FromPostgres(err), "failed to list job events").
+			WithOp("storage.ListEvents")
+	}
+
+	events := make([]models.JobEvent, len(rows))
+	for i, row := range rows {
+		events[i] = models.JobEvent{
+			ID:        row.ID,
+			JobID:     row.JobID,
+			EventType: row.EventType,
+			CreatedAt: row.CreatedAt,
+			CreatedBy: row.CreatedBy,
+		}
+
+		if len(row.EventData) == 0 {
+			continue
+		}
+
+		if err := json.Unmarshal(row.EventData, &events[i].EventData); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode data of event %s", row.ID).
+				WithCode(errors.CodeSerialization).
+				WithOp("storage.ListEvents")
+		}
+	}
+
+	return events, nil
+}
+
+// APIKeyRepository handles API key persistence
+type APIKeyRepository struct {
+	db      *sqlx.DB
+	logger  logger.Logger
+	retrier *retry.Retrier
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *sqlx.DB, log logger.Logger) *APIKeyRepository {
+	return &APIKeyRepository{
+		db:      db,
+		logger:  log.Named("api-key-repo"),
+		retrier: retry.Database(),
+	}
+}
+
+// apiKeyRow is an api_keys row, whose scopes are a Postgres array
+type apiKeyRow struct {
+	ID        uuid.UUID      `db:"id"`
+	Name      string         `db:"name"`
+	KeyHash   string         `db:"key_hash"`
+	Scopes    pq.StringArray `db:"scopes"`
+	CreatedAt time.Time      `db:"created_at"`
+	ExpiresAt *time.Time     `db:"expires_at"`
+}
+
+// CreateAPIKey inserts key into api_keys, retrying transient failures
+func (r *APIKeyRepository) CreateAPIKey(ctx context.Context, key *models.APIKey) error {
+	query := `
+		INSERT INTO api_keys (id, name, key_hash, scopes, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	err := r.retrier.DoContext(ctx, func(ctx context.Context) error {
+		if _, err := r.db.ExecContext(ctx, query, key.ID, key.Name, key.KeyHash,
+			pq.Array(key.Scopes), key.CreatedAt, key.ExpiresAt); err != nil {
+			return errors.FromPostgres(err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return errors.Wrap(errors.FromPostgres(err), "failed to create api key").
+			WithOp("storage.CreateAPIKey")
+	}
+
+	r.logger.Info("api key created",
+		logger.UUID("key_id", key.ID),
+		logger.String("name", key.Name),
+	)
+	return nil
+}
+
+// GetAPIKey retrieves a key by ID, retrying transient failures
+func (r *APIKeyRepository) GetAPIKey(ctx context.Context, id uuid.UUID) (*models.APIKey, error) {
+	key, err := r.get(ctx, `SELECT * FROM api_keys WHERE id = $1`, id)
+	if errors.HasCode(err, errors.CodeNotFound) {
+		return nil, errors.Wrapf(err, "api key %s not found", id).
+			WithKey("api_key.not_found", id).
+			WithOp("storage.GetAPIKey")
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get api key").
+			WithOp("storage.GetAPIKey")
+	}
+
+	return key, nil
+}
+
+// FindAPIKey retrieves the key whose hash is hash, retrying transient
+// failures
+func (r *APIKeyRepository) FindAPIKey(ctx context.Context, hash string) (*models.APIKey, error) {
+	key, err := r.get(ctx, `SELECT * FROM api_keys WHERE key_hash = $1`, hash)
+	if errors.HasCode(err, errors.CodeNotFound) {
+		return nil, errors.Wrap(err, "api key not found").
+			WithOp("storage.FindAPIKey")
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find api key").
+			WithOp("storage.FindAPIKey")
+	}
+
+	return key, nil
+}
+
+// get reads the single api_keys row selected by query
+func (r *APIKeyRepository) get(ctx context.Context, query string, arg any) (*models.APIKey, error) {
+	var row apiKeyRow
+	err := r.retrier.DoContext(ctx, func(ctx context.Context) error {
+		if err := r.db.GetContext(ctx, &row, query, arg); err != nil {
+			return errors.FromPostgres(err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, errors.FromPostgres(err)
+	}
+
+	return &models.APIKey{
+		ID:        row.ID,
+		Name:      row.Name,
+		KeyHash:   row.KeyHash,
+		Scopes:    row.Scopes,
+		CreatedAt: row.CreatedAt,
+		ExpiresAt: row.ExpiresAt,
+	}, nil
+}
+
+// ExpireAPIKey sets the expiry of the key with id to at, retrying
+// transient failures
+func (r *APIKeyRepository) ExpireAPIKey(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `UPDATE api_keys SET expires_at = $2 WHERE id = $1`
+
+	var updated int64
+	err := r.retrier.DoContext(ctx, func(ctx context.Context) error {
+		res, err := r.db.ExecContext(ctx, query, id, at)
+		if err != nil {
+			return errors.FromPostgres(err)
+		}
+
+		if updated, err = res.RowsAffected(); err != nil {
+			return errors.FromPostgres(err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return errors.Wrap(errors.FromPostgres(err), "failed to expire api key").
+			WithOp("storage.ExpireAPIKey")
+	}
+
+	if updated == 0 {
+		return errors.NotFound("api key %s not found", id).
+			WithKey("api_key.not_found", id).
+			WithOp("storage.ExpireAPIKey")
+	}
+
+	r.logger.Info("api key expiry set",
+		logger.UUID("key_id", id),
+		"expires_at", at,
+	)
+	return nil
+}
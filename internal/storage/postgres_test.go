@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDriver is a database/sql driver whose statements affect a set number
+// of rows, standing in for Postgres where only the outcome of a write
+// matters
+type fakeDriver struct {
+	rowsAffected atomic.Int64
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{driver: d}, nil
+}
+
+// fakeConn executes statements directly, without preparing them
+type fakeConn struct {
+	driver *fakeDriver
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *fakeConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(c.driver.rowsAffected.Load()), nil
+}
+
+func (c *fakeConn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+// fakeDrivers numbers the drivers registered by newFakeDB, as database/sql
+// refuses a name twice
+var fakeDrivers atomic.Int64
+
+// newFakeDB returns a database whose writes affect rowsAffected rows
+func newFakeDB(t *testing.T, rowsAffected int64) *sqlx.DB {
+	t.Helper()
+
+	drv := &fakeDriver{}
+	drv.rowsAffected.Store(rowsAffected)
+	name := fmt.Sprintf("storage-fake-%d", fakeDrivers.Add(1))
+	sql.Register(name, drv)
+
+	db, err := sql.Open(name, "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	return sqlx.NewDb(db, "postgres")
+}
+
+// auditEntries returns an enabled audit logger writing to a temp file, and
+// a function decoding the written lines
+func auditEntries(t *testing.T) (logger.AuditLogger, func() []map[string]any) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit := logger.NewAudit(logger.AuditConfig{Enabled: true, OutputPath: path})
+
+	return audit, func() []map[string]any {
+		require.NoError(t, audit.Sync())
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+
+		var entries []map[string]any
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+			entries = append(entries, entry)
+		}
+
+		require.NoError(t, scanner.Err())
+		return entries
+	}
+}
+
+func TestJobRepository_UpdateStatusAudits(t *testing.T) {
+	tests := []struct {
+		status models.JobStatus
+		event  string
+	}{
+		{models.JobStatusRunning, logger.AuditJobStarted},
+		{models.JobStatusCompleted, logger.AuditJobCompleted},
+		{models.JobStatusRetrying, logger.AuditJobFailed},
+		{models.JobStatusDead, logger.AuditJobDeadLettered},
+		{models.JobStatusPending, logger.AuditJobRequeued},
+		{models.JobStatusCancelled, logger.AuditJobCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			audit, entries := auditEntries(t)
+			repo := NewJobRepository(newFakeDB(t, 1), logger.NewNop(), WithAudit(audit))
+
+			job := models.NewJob("email.send", json.RawMessage(`{}`), models.JobPriorityNormal)
+			job.Status = tt.status
+			job.RetryCount = 2
+			require.NoError(t, repo.UpdateStatus(context.Background(), job))
+
+			got := entries()
+			require.Len(t, got, 1)
+			assert.Equal(t, tt.event, got[0]["event"])
+			assert.Equal(t, job.ID.String(), got[0]["job_id"])
+			assert.Equal(t, string(tt.status), got[0]["status"])
+			assert.EqualValues(t, 2, got[0]["retry_count"])
+		})
+	}
+}
+
+func TestJobRepository_UpdateStatusMissingJobNotAudited(t *testing.T) {
+	audit, entries := auditEntries(t)
+	repo := NewJobRepository(newFakeDB(t, 0), logger.NewNop(), WithAudit(audit))
+
+	job := models.NewJob("email.send", json.RawMessage(`{}`), models.JobPriorityNormal)
+	job.Status = models.JobStatusCompleted
+	err := repo.UpdateStatus(context.Background(), job)
+
+	assert.True(t, errors.HasCode(err, errors.CodeNotFound))
+	assert.Empty(t, entries())
+}
+
+func TestJobRepository_UpdateStatusAuditDisabled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit := logger.NewAudit(logger.AuditConfig{OutputPath: path})
+	repo := NewJobRepository(newFakeDB(t, 1), logger.NewNop(), WithAudit(audit))
+
+	job := models.NewJob("email.send", json.RawMessage(`{}`), models.JobPriorityNormal)
+	job.Status = models.JobStatusCompleted
+	require.NoError(t, repo.UpdateStatus(context.Background(), job))
+
+	assert.NoFileExists(t, path)
+}
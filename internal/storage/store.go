@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"task-queue/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// JobStore persists jobs and their processing state
+type JobStore interface {
+	// Create inserts a new job
+	Create(ctx context.Context, job *models.Job) error
+
+	// CreateBatch inserts jobs atomically: either all of them are stored or
+	// none is
+	CreateBatch(ctx context.Context, jobs []*models.Job) error
+
+	// Get retrieves a job by ID
+	Get(ctx context.Context, id uuid.UUID) (*models.Job, error)
+
+	// UpdateStatus writes the processing state of job: its status,
+	// retry_count, started_at, completed_at, error, result, and worker_id
+	UpdateStatus(ctx context.Context, job *models.Job) error
+
+	// List returns the page of jobs matching filter, continuing after
+	// filter.After
+	List(ctx context.Context, filter *models.JobFilter) (*models.JobPage, error)
+}
+
+// EventStore records job events
+type EventStore interface {
+	// RecordEvent appends event to its job's audit trail
+	RecordEvent(ctx context.Context, event *models.JobEvent) error
+
+	// ListEvents returns the audit trail of jobID, oldest first
+	ListEvents(ctx context.Context, jobID uuid.UUID) ([]models.JobEvent, error)
+}
+
+// APIKeyStore persists API keys by the hash of the key
+type APIKeyStore interface {
+	// CreateAPIKey inserts key. A key whose hash is already stored fails
+	// with CodeAlreadyExists.
+	CreateAPIKey(ctx context.Context, key *models.APIKey) error
+
+	// GetAPIKey retrieves a key by ID
+	GetAPIKey(ctx context.Context, id uuid.UUID) (*models.APIKey, error)
+
+	// FindAPIKey retrieves the key whose hash is hash, expired or not
+	FindAPIKey(ctx context.Context, hash string) (*models.APIKey, error)
+
+	// ExpireAPIKey sets the expiry of the key with id to at
+	ExpireAPIKey(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+var (
+	_ JobStore    = (*JobRepository)(nil)
+	_ EventStore  = (*EventRepository)(nil)
+	_ APIKeyStore = (*APIKeyRepository)(nil)
+)
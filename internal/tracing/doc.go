@@ -0,0 +1,29 @@
+// Package tracing connects the spans of a job's life into one trace: the
+// API request that submits it, the enqueue, and each attempt a worker
+// makes at processing it.
+//
+// The HTTP and gRPC servers extract the caller's W3C traceparent. The job
+// service starts a "job.enqueue" span and stores its context in the job's
+// metadata under MetadataKey, so the context travels with the job through
+// the queue and the store. A worker extracts it when it dequeues the job
+// and starts the "job.process" span as its child, and the handler runs
+// with that span in its context. Store calls made through JobStore and
+// EventStore nest under whichever span is current.
+//
+// Setup builds the tracer provider of the configuration, exporting spans
+// over OTLP/HTTP to TracingConfig.CollectorURL and sampling
+// TracingConfig.SampleRate of new traces. A trace started by a sampled
+// caller is always kept.
+//
+//	tp, shutdown, err := tracing.Setup(ctx, cfg.Tracing)
+//	if err != nil {
+//	    return err
+//	}
+//	defer shutdown(context.Background())
+//
+//	store := tracing.JobStore(storage.NewJobRepository(db, log), tp)
+//	svc := service.New(store, events, q, log, service.WithTracerProvider(tp))
+//	handler := api.New(svc, log, api.WithTracing(tp))
+//	pool := worker.New(q, cfg.Worker, log, worker.WithStore(store),
+//	    worker.WithTracerProvider(tp))
+package tracing
@@ -0,0 +1,96 @@
+package tracing
+
+import (
+	"context"
+
+	"task-queue/internal/models"
+	"task-queue/internal/storage"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedJobStore starts a client span around every call to a JobStore
+type tracedJobStore struct {
+	store  storage.JobStore
+	tracer trace.Tracer
+}
+
+// JobStore returns store with a span around every call, named after the
+// method, e.g. "JobStore.Create". tp may be nil for the global provider.
+func JobStore(store storage.JobStore, tp trace.TracerProvider) storage.JobStore {
+	return &tracedJobStore{store: store, tracer: Tracer(tp)}
+}
+
+func (s *tracedJobStore) Create(ctx context.Context, job *models.Job) (err error) {
+	ctx, span := startStoreSpan(ctx, s.tracer, "JobStore.Create", JobAttributes(job)...)
+	defer func() { End(span, err) }()
+
+	return s.store.Create(ctx, job)
+}
+
+func (s *tracedJobStore) CreateBatch(ctx context.Context, jobs []*models.Job) (err error) {
+	ctx, span := startStoreSpan(ctx, s.tracer, "JobStore.CreateBatch",
+		attribute.Int("job.count", len(jobs)))
+	defer func() { End(span, err) }()
+
+	return s.store.CreateBatch(ctx, jobs)
+}
+
+func (s *tracedJobStore) Get(ctx context.Context, id uuid.UUID) (_ *models.Job, err error) {
+	ctx, span := startStoreSpan(ctx, s.tracer, "JobStore.Get", AttrJobID.String(id.String()))
+	defer func() { End(span, err) }()
+
+	return s.store.Get(ctx, id)
+}
+
+func (s *tracedJobStore) UpdateStatus(ctx context.Context, job *models.Job) (err error) {
+	ctx, span := startStoreSpan(ctx, s.tracer, "JobStore.UpdateStatus",
+		append(JobAttributes(job), attribute.String("job.status", string(job.Status)))...)
+	defer func() { End(span, err) }()
+
+	return s.store.UpdateStatus(ctx, job)
+}
+
+func (s *tracedJobStore) List(ctx context.Context, filter *models.JobFilter) (_ *models.JobPage, err error) {
+	ctx, span := startStoreSpan(ctx, s.tracer, "JobStore.List")
+	defer func() { End(span, err) }()
+
+	return s.store.List(ctx, filter)
+}
+
+// tracedEventStore starts a client span around every call to an
+// EventStore
+type tracedEventStore struct {
+	store  storage.EventStore
+	tracer trace.Tracer
+}
+
+// EventStore returns store with a span around every call, like JobStore
+func EventStore(store storage.EventStore, tp trace.TracerProvider) storage.EventStore {
+	return &tracedEventStore{store: store, tracer: Tracer(tp)}
+}
+
+func (s *tracedEventStore) RecordEvent(ctx context.Context, event *models.JobEvent) (err error) {
+	ctx, span := startStoreSpan(ctx, s.tracer, "EventStore.RecordEvent",
+		AttrJobID.String(event.JobID.String()), attribute.String("event.type", event.EventType))
+	defer func() { End(span, err) }()
+
+	return s.store.RecordEvent(ctx, event)
+}
+
+func (s *tracedEventStore) ListEvents(ctx context.Context, jobID uuid.UUID) (_ []models.JobEvent, err error) {
+	ctx, span := startStoreSpan(ctx, s.tracer, "EventStore.ListEvents", AttrJobID.String(jobID.String()))
+	defer func() { End(span, err) }()
+
+	return s.store.ListEvents(ctx, jobID)
+}
+
+// startStoreSpan starts a client span for a store call
+func startStoreSpan(ctx context.Context, tracer trace.Tracer, name string,
+	attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+}
@@ -0,0 +1,163 @@
+package tracing
+
+import (
+	"context"
+
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// InstrumentationName names the tracer of every span the task queue starts
+const InstrumentationName = "task-queue"
+
+// MetadataKey is the job metadata key holding the trace context of the
+// span that enqueued the job
+const MetadataKey = "trace_context"
+
+// Attribute keys describing a job on its spans
+const (
+	AttrJobID      = attribute.Key("job.id")
+	AttrJobType    = attribute.Key("job.type")
+	AttrJobAttempt = attribute.Key("job.attempt")
+)
+
+// Propagator carries trace context in W3C traceparent, tracestate, and
+// baggage headers, on requests and in job metadata alike
+var Propagator propagation.TextMapPropagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{},
+	propagation.Baggage{},
+)
+
+// Setup builds the tracer provider of cfg and installs it, with
+// Propagator, as the global default. A disabled configuration yields a
+// provider whose spans are never recorded. shutdown flushes the spans not
+// yet exported.
+func Setup(ctx context.Context, cfg config.TracingConfig) (tp trace.TracerProvider,
+	shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(Propagator)
+	if !cfg.Enabled {
+		return noop.NewTracerProvider(), func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.CollectorURL))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create trace exporter").
+			WithCode(errors.CodeConfiguration).
+			WithOp("tracing.Setup")
+	}
+
+	provider := NewProvider(cfg, sdktrace.WithBatcher(exporter))
+	otel.SetTracerProvider(provider)
+	return provider, provider.Shutdown, nil
+}
+
+// NewProvider returns a tracer provider naming cfg.ServiceName and sampling
+// cfg.SampleRate of new traces. opts add its span processors, such as an
+// exporter.
+func NewProvider(cfg config.TracingConfig, opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	opts = append([]sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(Sampler(cfg.SampleRate)),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(cfg.ServiceName))),
+	}, opts...)
+
+	return sdktrace.NewTracerProvider(opts...)
+}
+
+// Sampler samples rate of new traces and follows the caller's decision for
+// traces started elsewhere, so a trace is never cut in half
+func Sampler(rate float64) sdktrace.Sampler {
+	return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(rate))
+}
+
+// Tracer returns the task queue's tracer of tp, or of the global provider
+// when tp is nil
+func Tracer(tp trace.TracerProvider) trace.Tracer {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+
+	return tp.Tracer(InstrumentationName)
+}
+
+// JobAttributes describes job on a span
+func JobAttributes(job *models.Job) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		AttrJobID.String(job.ID.String()),
+		AttrJobType.String(job.Type),
+	}
+}
+
+// Inject stores the trace context of ctx in job's metadata. A context
+// without a span leaves the metadata unchanged.
+func Inject(ctx context.Context, job *models.Job) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+
+	carrier := propagation.MapCarrier{}
+	Propagator.Inject(ctx, carrier)
+	if job.Metadata == nil {
+		job.Metadata = make(map[string]any)
+	}
+
+	job.Metadata[MetadataKey] = map[string]string(carrier)
+}
+
+// Extract returns ctx carrying the trace context stored in job's metadata
+// as its remote parent. Jobs decoded from JSON hold the context as a
+// map[string]any, which is accepted as well.
+func Extract(ctx context.Context, job *models.Job) context.Context {
+	carrier := propagation.MapCarrier{}
+	switch stored := job.Metadata[MetadataKey].(type) {
+	case map[string]string:
+		for key, value := range stored {
+			carrier[key] = value
+		}
+
+	case map[string]any:
+		for key, value := range stored {
+			if s, ok := value.(string); ok {
+				carrier[key] = s
+			}
+		}
+
+	default:
+		return ctx
+	}
+
+	return Propagator.Extract(ctx, carrier)
+}
+
+// LogContext stores the trace ID of the span of ctx under
+// logger.TraceIDKey, so log entries written with ctx name the trace
+func LogContext(ctx context.Context) context.Context {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return ctx
+	}
+
+	return context.WithValue(ctx, logger.TraceIDKey, sc.TraceID().String())
+}
+
+// End ends span, recording err as its failure when it is not nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,153 @@
+package tracing_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"task-queue/internal/api"
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/internal/service"
+	"task-queue/internal/storage"
+	"task-queue/internal/tracing"
+	"task-queue/internal/worker"
+	"task-queue/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newRecorder returns a provider sampling every trace and the exporter
+// recording its spans
+func newRecorder(t *testing.T) (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := tracing.NewProvider(config.TracingConfig{ServiceName: "test", SampleRate: 1},
+		sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	return tp, exporter
+}
+
+// spanNamed returns the first recorded span called name
+func spanNamed(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+
+	require.Failf(t, "span not recorded", "no span named %q", name)
+	return tracetest.SpanStub{}
+}
+
+func TestTrace_FollowsJobFromRequestToWorker(t *testing.T) {
+	tp, exporter := newRecorder(t)
+	log := logger.NewNop()
+	store := tracing.JobStore(storage.NewMemoryJobStore(), tp)
+	events := tracing.EventStore(storage.NewMemoryEventStore(), tp)
+	q := queue.NewMemoryQueue(queue.Config{})
+
+	svc := service.New(store, events, q, log, service.WithTracerProvider(tp))
+	handler := api.New(svc, log, api.WithTracing(tp))
+
+	pool := worker.New(q, config.WorkerConfig{}, log,
+		worker.WithStore(store), worker.WithTracerProvider(tp))
+	var handlerTraceID trace.TraceID
+	pool.Register("email_send", func(ctx context.Context, job *models.Job) (json.RawMessage, error) {
+		handlerTraceID = trace.SpanContextFromContext(ctx).TraceID()
+		return json.RawMessage(`{"sent":true}`), nil
+	})
+
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	req := httptest.NewRequest(http.MethodPost, "/v1/jobs",
+		strings.NewReader(`{"type":"email_send","payload":{"to":"a@example.com"}}`))
+	req.Header.Set("traceparent", traceparent)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pool.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	require.Eventually(t, func() bool {
+		for _, span := range exporter.GetSpans() {
+			if span.Name == "job.process" {
+				return true
+			}
+		}
+
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+
+	spans := exporter.GetSpans()
+	want := "4bf92f3577b34da6a3ce929d0e0e4736"
+	for _, span := range spans {
+		assert.Equal(t, want, span.SpanContext.TraceID().String(), "span %s", span.Name)
+	}
+
+	assert.Equal(t, want, handlerTraceID.String())
+
+	server := spanNamed(t, spans, "POST /v1/jobs")
+	enqueue := spanNamed(t, spans, "job.enqueue")
+	create := spanNamed(t, spans, "JobStore.Create")
+	process := spanNamed(t, spans, "job.process")
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID().String())
+	assert.Equal(t, server.SpanContext.SpanID(), enqueue.Parent.SpanID())
+	assert.Equal(t, enqueue.SpanContext.SpanID(), create.Parent.SpanID())
+	assert.Equal(t, enqueue.SpanContext.SpanID(), process.Parent.SpanID())
+	assert.Equal(t, trace.SpanKindConsumer, process.SpanKind)
+
+	var updates int
+	for _, span := range spans {
+		if span.Name == "JobStore.UpdateStatus" {
+			assert.Equal(t, process.SpanContext.SpanID(), span.Parent.SpanID())
+			updates++
+		}
+	}
+
+	assert.Positive(t, updates, "the worker's status updates are traced")
+}
+
+func TestExtract_AcceptsDecodedMetadata(t *testing.T) {
+	tp, _ := newRecorder(t)
+	ctx, span := tp.Tracer("test").Start(context.Background(), "enqueue")
+	defer span.End()
+
+	job := models.NewJob("email_send", json.RawMessage(`{}`), models.JobPriorityNormal)
+	tracing.Inject(ctx, job)
+
+	data, err := json.Marshal(job)
+	require.NoError(t, err)
+
+	var decoded models.Job
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	got := trace.SpanContextFromContext(tracing.Extract(context.Background(), &decoded))
+	assert.Equal(t, span.SpanContext().TraceID(), got.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), got.SpanID())
+	assert.True(t, got.IsRemote())
+}
+
+func TestInject_WithoutSpanLeavesMetadata(t *testing.T) {
+	job := models.NewJob("email_send", json.RawMessage(`{}`), models.JobPriorityNormal)
+	tracing.Inject(context.Background(), job)
+
+	assert.NotContains(t, job.Metadata, tracing.MetadataKey)
+}
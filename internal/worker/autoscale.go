@@ -0,0 +1,188 @@
+package worker
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/queue"
+)
+
+// Scaling directions reported to ScalingMetrics
+const (
+	ScaleUp   = "up"
+	ScaleDown = "down"
+)
+
+// ScalingMetrics records the pool's concurrency and the autoscaler's
+// decisions. internal/metrics provides a Prometheus implementation.
+type ScalingMetrics interface {
+	SetConcurrency(n int)
+	ObserveScaling(direction string)
+}
+
+// StatsSource provides the queue statistics the autoscaler sizes the pool
+// from
+type StatsSource interface {
+	Stats(ctx context.Context) (*queue.QueueStats, error)
+}
+
+// WithScalingMetrics reports the pool's concurrency and scaling decisions
+// to m
+func WithScalingMetrics(m ScalingMetrics) Option {
+	return func(p *Pool) {
+		p.scalingMetrics = m
+	}
+}
+
+// WithStatsSource makes the autoscaler read statistics from src instead of
+// the pool's queue
+func WithStatsSource(src StatsSource) Option {
+	return func(p *Pool) {
+		p.stats = src
+	}
+}
+
+// workerSet is the resizable set of running workers of a pool
+type workerSet struct {
+	mu    sync.Mutex
+	wg    sync.WaitGroup
+	stops []chan struct{}
+}
+
+// size returns the number of workers that have not been asked to stop
+func (s *workerSet) size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.stops)
+}
+
+// resize starts or stops workers until n are running. Stopped workers
+// finish their current job before they exit.
+func (s *workerSet) resize(n int, work func(stop <-chan struct{})) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for len(s.stops) < n {
+		stop := make(chan struct{})
+		s.stops = append(s.stops, stop)
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			work(stop)
+		}()
+	}
+
+	for len(s.stops) > n {
+		last := len(s.stops) - 1
+		close(s.stops[last])
+		s.stops = s.stops[:last]
+	}
+}
+
+// autoscaler decides the pool's concurrency from queue statistics
+type autoscaler struct {
+	config    config.AutoscaleConfig
+	now       func() time.Time
+	lastUp    time.Time
+	lastScale time.Time
+}
+
+// decide returns the concurrency to run with given stats and the current
+// concurrency, and the reason for it
+func (a *autoscaler) decide(stats *queue.QueueStats, current int) (int, string) {
+	desired, reason := a.config.MinConcurrency, "idle"
+
+	ready := max(stats.Size-stats.Delayed, 0)
+	if a.config.TargetBacklog > 0 {
+		backlog := ready + stats.Processing
+		if n := int(math.Ceil(float64(backlog) / float64(a.config.TargetBacklog))); n > desired {
+			desired, reason = n, "backlog"
+		}
+	}
+
+	// Grow in proportion to how far the oldest job is over its target
+	if a.config.TargetLatency > 0 && stats.OldestAge > a.config.TargetLatency {
+		over := float64(stats.OldestAge) / float64(a.config.TargetLatency)
+		if n := int(math.Ceil(float64(max(current, 1)) * over)); n > desired {
+			desired, reason = n, "latency"
+		}
+	}
+
+	desired = min(max(desired, a.config.MinConcurrency), a.config.MaxConcurrency)
+
+	now := a.now()
+	switch {
+	case desired > current:
+		if now.Sub(a.lastUp) < a.config.ScaleUpCooldown {
+			return current, "scale_up_cooldown"
+		}
+
+		a.lastUp, a.lastScale = now, now
+
+	case desired < current:
+		if now.Sub(a.lastScale) < a.config.ScaleDownCooldown {
+			return current, "scale_down_cooldown"
+		}
+
+		a.lastScale = now
+	}
+
+	return desired, reason
+}
+
+// autoscale resizes workers every Interval until ctx is canceled
+func (p *Pool) autoscale(ctx context.Context, workers *workerSet, work func(stop <-chan struct{})) {
+	scaler := &autoscaler{config: p.config.Autoscale, now: time.Now}
+
+	ticker := time.NewTicker(p.config.Autoscale.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+		}
+
+		stats, err := p.stats.Stats(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				p.logger.Warn("failed to read queue stats for autoscaling", "error", err)
+			}
+
+			continue
+		}
+
+		current := workers.size()
+		target, reason := scaler.decide(stats, current)
+		if target == current {
+			continue
+		}
+
+		direction := ScaleUp
+		if target < current {
+			direction = ScaleDown
+		}
+
+		workers.resize(target, work)
+		p.logger.Info("worker pool scaled",
+			"from", current,
+			"to", target,
+			"reason", reason,
+			"size", stats.Size,
+			"processing", stats.Processing,
+			"oldest_age", stats.OldestAge,
+		)
+
+		if p.scalingMetrics != nil {
+			p.scalingMetrics.ObserveScaling(direction)
+			p.scalingMetrics.SetConcurrency(target)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStats is a StatsSource whose statistics tests set directly
+type fakeStats struct {
+	mu    sync.Mutex
+	stats queue.QueueStats
+}
+
+func (f *fakeStats) set(stats queue.QueueStats) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.stats = stats
+}
+
+func (f *fakeStats) Stats(context.Context) (*queue.QueueStats, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stats := f.stats
+	return &stats, nil
+}
+
+func TestAutoscaler_Decide(t *testing.T) {
+	now := time.Now()
+	a := &autoscaler{
+		config: config.AutoscaleConfig{
+			MinConcurrency:    2,
+			MaxConcurrency:    50,
+			TargetBacklog:     10,
+			TargetLatency:     time.Minute,
+			ScaleUpCooldown:   30 * time.Second,
+			ScaleDownCooldown: 2 * time.Minute,
+		},
+		now: func() time.Time { return now },
+	}
+
+	// Off-peak traffic stays at the minimum
+	target, reason := a.decide(&queue.QueueStats{Size: 5}, 2)
+	assert.Equal(t, 2, target)
+	assert.Equal(t, "idle", reason)
+
+	// A spike scales up to one worker per ten jobs
+	target, reason = a.decide(&queue.QueueStats{Size: 200, Processing: 2}, 2)
+	assert.Equal(t, 21, target)
+	assert.Equal(t, "backlog", reason)
+
+	// A second spike within the cooldown is held
+	now = now.Add(10 * time.Second)
+	target, reason = a.decide(&queue.QueueStats{Size: 400, Processing: 21}, 21)
+	assert.Equal(t, 21, target)
+	assert.Equal(t, "scale_up_cooldown", reason)
+
+	// After the cooldown it scales up again, capped at the maximum
+	now = now.Add(30 * time.Second)
+	target, _ = a.decide(&queue.QueueStats{Size: 900, Processing: 21}, 21)
+	assert.Equal(t, 50, target)
+
+	// Delayed jobs are not backlog, and scale-down waits out its cooldown
+	now = now.Add(time.Minute)
+	target, reason = a.decide(&queue.QueueStats{Size: 500, Delayed: 500}, 50)
+	assert.Equal(t, 50, target)
+	assert.Equal(t, "scale_down_cooldown", reason)
+
+	now = now.Add(2 * time.Minute)
+	target, _ = a.decide(&queue.QueueStats{Size: 500, Delayed: 500}, 50)
+	assert.Equal(t, 2, target)
+
+	// Old jobs scale up in proportion to how late they are
+	now = now.Add(time.Minute)
+	target, reason = a.decide(&queue.QueueStats{Size: 3, OldestAge: 3 * time.Minute}, 2)
+	assert.Equal(t, 6, target)
+	assert.Equal(t, "latency", reason)
+}
+
+// scalingRecorder is a ScalingMetrics that records what it observes
+type scalingRecorder struct {
+	mu          sync.Mutex
+	concurrency int
+	decisions   []string
+}
+
+func (r *scalingRecorder) SetConcurrency(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.concurrency = n
+}
+
+func (r *scalingRecorder) ObserveScaling(direction string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.decisions = append(r.decisions, direction)
+}
+
+func TestPool_AutoscaleGracefulScaleDown(t *testing.T) {
+	q := newRecordingQueue()
+	stats := &fakeStats{}
+	recorder := &scalingRecorder{}
+	p := New(q, config.WorkerConfig{
+		Autoscale: config.AutoscaleConfig{
+			Enabled:        true,
+			MinConcurrency: 1,
+			MaxConcurrency: 3,
+			Interval:       5 * time.Millisecond,
+			TargetBacklog:  10,
+		},
+	}, logger.NewNop(), WithStatsSource(stats), WithScalingMetrics(recorder))
+
+	var running atomic.Int32
+	release := make(chan struct{})
+	p.Register("report.build", func(ctx context.Context, _ *models.Job) (json.RawMessage, error) {
+		running.Add(1)
+		defer running.Add(-1)
+
+		select {
+		case <-release:
+			return nil, nil
+
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+
+	stats.set(queue.QueueStats{Size: 30})
+	startPool(t, p)
+	require.Eventually(t, func() bool { return p.Concurrency() == 3 }, time.Second, time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		enqueue(t, q, "report.build")
+	}
+
+	require.Eventually(t, func() bool { return running.Load() == 3 }, time.Second, time.Millisecond)
+
+	// The load drops, but running jobs finish before their workers exit
+	stats.set(queue.QueueStats{})
+	require.Eventually(t, func() bool { return p.Concurrency() == 1 }, time.Second, time.Millisecond)
+	assert.EqualValues(t, 3, running.Load())
+
+	close(release)
+	settled := q.waitSettled(t, 3)
+	for _, s := range settled {
+		assert.True(t, s.acked)
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	assert.Equal(t, 1, recorder.concurrency)
+	assert.Equal(t, []string{ScaleUp, ScaleDown}, recorder.decisions)
+}
@@ -0,0 +1,309 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"sync"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+)
+
+// Batch defaults used when BatchOptions leaves a bound unset
+const (
+	defaultMaxBatch = 10
+	defaultMaxWait  = time.Second
+)
+
+// BatchHandler processes several jobs of one type in a single call. It
+// returns one Result per job, in the order of jobs. A returned error fails
+// every job of the batch.
+type BatchHandler func(ctx context.Context, jobs []*models.Job) ([]Result, error)
+
+// Result is the outcome of one job of a batch. A non-nil Err fails the job
+// as an error returned by a Handler would, so Permanent, RetryAfter, and
+// Reschedule apply to it.
+type Result struct {
+	Data json.RawMessage
+	Err  error
+}
+
+// BatchOptions bounds how long jobs are accumulated before their batch
+// runs
+type BatchOptions struct {
+	// MaxBatch is the most jobs in one batch. It defaults to 10.
+	MaxBatch int
+
+	// MaxWait is how long the first job of a batch waits for it to fill
+	// before the batch runs anyway. It defaults to one second.
+	MaxWait time.Duration
+}
+
+// pendingJob is a job waiting in a batch, with the heartbeat that keeps it
+// leased meanwhile
+type pendingJob struct {
+	job    *models.Job
+	log    logger.Logger
+	hb     *heartbeat
+	cancel context.CancelCauseFunc
+}
+
+// stop ends the job's heartbeat. It reports whether the job's lease was
+// lost.
+func (pj *pendingJob) stop() bool {
+	lost := pj.hb.stop()
+	pj.cancel(nil)
+	return lost
+}
+
+// batcher accumulates the jobs of one batch type
+type batcher struct {
+	jobType string
+	handler BatchHandler
+	opts    BatchOptions
+
+	mu      sync.Mutex
+	pending []*pendingJob
+	timer   *time.Timer
+	gen     int
+	timers  sync.WaitGroup
+}
+
+// RegisterBatch sets h as the handler for jobType, replacing any earlier
+// one. Dequeued jobs of jobType are held until MaxBatch of them have
+// accumulated or the first has waited MaxWait, then passed to h together.
+// A full batch runs on the worker that filled it, and one flushed by
+// MaxWait runs on its own goroutine. Each job is settled on its own from
+// its Result. A batch counts as one job against its type's concurrency
+// limit, and runs under the process timeout but not the pool middleware.
+func (p *Pool) RegisterBatch(jobType string, h BatchHandler, opts BatchOptions) {
+	if opts.MaxBatch < 1 {
+		opts.MaxBatch = defaultMaxBatch
+	}
+
+	if opts.MaxWait <= 0 {
+		opts.MaxWait = defaultMaxWait
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.handlers, jobType)
+	p.batchers[jobType] = &batcher{jobType: jobType, handler: h, opts: opts}
+}
+
+// batcher returns the batcher of jobType, if it has a batch handler
+func (p *Pool) batcher(jobType string) (*batcher, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	b, ok := p.batchers[jobType]
+	return b, ok
+}
+
+// batch adds job to its type's pending batch under a heartbeat. The job
+// that fills the batch runs it on the calling worker; the first job of a
+// batch starts the MaxWait timer.
+func (p *Pool) batch(ctx context.Context, log logger.Logger, b *batcher, job *models.Job) {
+	jobCtx, cancel := context.WithCancelCause(ctx)
+	pj := &pendingJob{
+		job:    job,
+		log:    log,
+		hb:     p.startHeartbeat(jobCtx, cancel, log, job),
+		cancel: cancel,
+	}
+
+	b.mu.Lock()
+	b.pending = append(b.pending, pj)
+
+	var full []*pendingJob
+	switch {
+	case len(b.pending) >= b.opts.MaxBatch:
+		full = b.take()
+
+	case len(b.pending) == 1:
+		gen := b.gen
+		b.timers.Add(1)
+		b.timer = time.AfterFunc(b.opts.MaxWait, func() {
+			defer b.timers.Done()
+			p.flushBatch(ctx, b, gen)
+		})
+	}
+	b.mu.Unlock()
+
+	if full != nil {
+		p.runBatch(ctx, b, full)
+	}
+}
+
+// take removes and returns the pending batch and stops its timer. The
+// caller holds b.mu.
+func (b *batcher) take() []*pendingJob {
+	if b.timer != nil && b.timer.Stop() {
+		b.timers.Done()
+	}
+
+	pending := b.pending
+	b.pending, b.timer = nil, nil
+	b.gen++
+	return pending
+}
+
+// flushBatch runs the batch whose MaxWait timer fired, unless it already
+// ran because it filled up. Once ctx is canceled the batch is released
+// instead.
+func (p *Pool) flushBatch(ctx context.Context, b *batcher, gen int) {
+	b.mu.Lock()
+	if b.gen != gen {
+		b.mu.Unlock()
+		return
+	}
+
+	pending := b.take()
+	b.mu.Unlock()
+
+	if ctx.Err() != nil {
+		p.releaseBatch(context.WithoutCancel(ctx), pending)
+		return
+	}
+
+	p.runBatch(ctx, b, pending)
+}
+
+// drainBatches releases every pending batch back to the queue and waits for
+// batches flushed by their timers to finish. Run calls it on shutdown.
+func (p *Pool) drainBatches(ctx context.Context) {
+	p.mu.RLock()
+	batchers := make([]*batcher, 0, len(p.batchers))
+	for _, b := range p.batchers {
+		batchers = append(batchers, b)
+	}
+	p.mu.RUnlock()
+
+	for _, b := range batchers {
+		b.mu.Lock()
+		pending := b.take()
+		b.mu.Unlock()
+
+		p.releaseBatch(ctx, pending)
+		b.timers.Wait()
+	}
+}
+
+// releaseBatch returns the jobs of a batch that never ran to the queue
+// without counting a retry
+func (p *Pool) releaseBatch(ctx context.Context, pending []*pendingJob) {
+	for _, pj := range pending {
+		if pj.stop() {
+			continue
+		}
+
+		if err := p.queue.Release(ctx, pj.job.ID, 0); err != nil {
+			pj.log.Error("failed to release batched job", "error", err)
+		}
+	}
+}
+
+// runBatch passes the jobs of a batch to its handler and settles each job
+// from its result. Jobs whose lease was lost while the batch filled are
+// dropped first. A whole-batch error, or a result count that does not match
+// the batch, fails every job.
+func (p *Pool) runBatch(ctx context.Context, b *batcher, pending []*pendingJob) {
+	settleCtx := context.WithoutCancel(ctx)
+
+	pending = dropLost(pending)
+	if len(pending) == 0 {
+		return
+	}
+
+	if !p.limiter.acquire(b.jobType) {
+		for _, pj := range pending {
+			if !pj.stop() {
+				p.releaseLimited(settleCtx, pj.log, pj.job)
+			}
+		}
+
+		return
+	}
+	defer p.limiter.release(b.jobType)
+
+	jobs := make([]*models.Job, len(pending))
+	for i, pj := range pending {
+		jobs[i] = pj.job
+		p.markRunning(settleCtx, pj.log, pj.job)
+	}
+
+	p.observeInFlight(len(jobs))
+	results, err := p.callBatch(ctx, b, jobs)
+	p.observeInFlight(-len(jobs))
+	if err == nil && len(results) != len(jobs) {
+		err = errors.Newf("batch handler returned %d results for %d jobs", len(results), len(jobs)).
+			WithMetadata("type", b.jobType).
+			WithOp("worker.runBatch")
+	}
+
+	for i, pj := range pending {
+		if pj.stop() {
+			pj.log.Warn("dropping outcome of job whose lease was lost")
+			continue
+		}
+
+		jobErr := err
+		if jobErr == nil {
+			jobErr = results[i].Err
+		}
+
+		if jobErr != nil && ctx.Err() != nil {
+			p.interrupt(settleCtx, pj.log, pj.job, jobErr)
+			continue
+		}
+
+		if jobErr != nil {
+			p.fail(settleCtx, pj.log, pj.job, jobErr)
+			continue
+		}
+
+		pj.job.Result = results[i].Data
+		p.complete(settleCtx, pj.log, pj.job)
+	}
+}
+
+// dropLost stops the jobs of pending whose lease was lost, which another
+// worker may hold now, and returns the others
+func dropLost(pending []*pendingJob) []*pendingJob {
+	kept := pending[:0]
+	for _, pj := range pending {
+		if pj.hb.lost.Load() {
+			pj.stop()
+			pj.log.Warn("dropping batched job whose lease was lost")
+			continue
+		}
+
+		kept = append(kept, pj)
+	}
+
+	return kept
+}
+
+// callBatch runs the batch handler under the process timeout. A handler
+// that fails because the deadline expired reports a CodeTimeout error.
+func (p *Pool) callBatch(ctx context.Context, b *batcher, jobs []*models.Job) ([]Result, error) {
+	if p.config.ProcessTimeout <= 0 {
+		return b.handler(ctx, jobs)
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, p.config.ProcessTimeout)
+	defer cancel()
+
+	results, err := b.handler(runCtx, jobs)
+	if err != nil && stderrors.Is(runCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return nil, errors.Wrapf(err, "batch timed out after %s", p.config.ProcessTimeout).
+			WithCode(errors.CodeTimeout).
+			WithOp("worker.runBatch")
+	}
+
+	return results, err
+}
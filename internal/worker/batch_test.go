@@ -0,0 +1,219 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchRecorder is a BatchHandler that records the batches it receives and
+// answers each job with result
+type batchRecorder struct {
+	mu      sync.Mutex
+	batches [][]uuid.UUID
+	result  func(i int, job *models.Job) Result
+}
+
+func (r *batchRecorder) handle(_ context.Context, jobs []*models.Job) ([]Result, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ids := make([]uuid.UUID, len(jobs))
+	results := make([]Result, len(jobs))
+	for i, job := range jobs {
+		ids[i] = job.ID
+		results[i] = Result{Data: json.RawMessage(`{"ok":true}`)}
+		if r.result != nil {
+			results[i] = r.result(i, job)
+		}
+	}
+
+	r.batches = append(r.batches, ids)
+	return results, nil
+}
+
+func (r *batchRecorder) sizes() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sizes := make([]int, len(r.batches))
+	for i, batch := range r.batches {
+		sizes[i] = len(batch)
+	}
+
+	return sizes
+}
+
+// byJob indexes settlements by job ID
+func byJob(settled []settlement) map[uuid.UUID]settlement {
+	m := make(map[uuid.UUID]settlement, len(settled))
+	for _, s := range settled {
+		m[s.jobID] = s
+	}
+
+	return m
+}
+
+func TestPool_BatchSettlesEachJob(t *testing.T) {
+	q := newRecordingQueue()
+	p := New(q, config.WorkerConfig{Concurrency: 2}, logger.NewNop())
+
+	rec := &batchRecorder{result: func(i int, job *models.Job) Result {
+		switch i {
+		case 1:
+			return Result{Err: errors.New("provider rejected row")}
+
+		case 2:
+			return Result{Err: Permanent(errors.New("malformed row"))}
+		}
+
+		return Result{Data: json.RawMessage(`{"upserted":1}`)}
+	}}
+	p.RegisterBatch("row.upsert", rec.handle, BatchOptions{MaxBatch: 3, MaxWait: time.Minute})
+
+	jobs := []*models.Job{
+		enqueue(t, q, "row.upsert"),
+		enqueue(t, q, "row.upsert"),
+		enqueue(t, q, "row.upsert"),
+	}
+	startPool(t, p)
+
+	settled := byJob(q.waitSettled(t, 3))
+	assert.Equal(t, []int{3}, rec.sizes())
+
+	assert.True(t, settled[jobs[0].ID].acked)
+
+	failed := settled[jobs[1].ID]
+	assert.False(t, failed.acked)
+	assert.Equal(t, "provider rejected row", failed.reason)
+
+	permanent := settled[jobs[2].ID]
+	assert.False(t, permanent.acked)
+	retryable, known := errors.IsRetryable(permanent.err)
+	assert.True(t, known)
+	assert.False(t, retryable)
+}
+
+func TestPool_BatchErrorNacksEveryJob(t *testing.T) {
+	q := newRecordingQueue()
+	p := New(q, config.WorkerConfig{Concurrency: 2}, logger.NewNop())
+
+	p.RegisterBatch("row.upsert", func(context.Context, []*models.Job) ([]Result, error) {
+		return nil, errors.New("bulk insert failed")
+	}, BatchOptions{MaxBatch: 2, MaxWait: time.Minute})
+
+	enqueue(t, q, "row.upsert")
+	enqueue(t, q, "row.upsert")
+	startPool(t, p)
+
+	for _, s := range q.waitSettled(t, 2) {
+		assert.False(t, s.acked)
+		assert.Equal(t, "bulk insert failed", s.reason)
+	}
+}
+
+func TestPool_BatchFlushesAfterMaxWait(t *testing.T) {
+	const maxWait = 50 * time.Millisecond
+
+	q := newRecordingQueue()
+	p := New(q, config.WorkerConfig{Concurrency: 2}, logger.NewNop())
+
+	rec := &batchRecorder{}
+	p.RegisterBatch("row.upsert", rec.handle, BatchOptions{MaxBatch: 10, MaxWait: maxWait})
+
+	enqueue(t, q, "row.upsert")
+	enqueue(t, q, "row.upsert")
+
+	start := time.Now()
+	startPool(t, p)
+
+	for _, s := range q.waitSettled(t, 2) {
+		assert.True(t, s.acked)
+	}
+
+	assert.GreaterOrEqual(t, time.Since(start), maxWait)
+	assert.Equal(t, []int{2}, rec.sizes())
+}
+
+func TestPool_BatchInterleavesWithOtherTypes(t *testing.T) {
+	q := newRecordingQueue()
+	p := New(q, config.WorkerConfig{Concurrency: 2}, logger.NewNop())
+
+	rec := &batchRecorder{}
+	p.RegisterBatch("row.upsert", rec.handle, BatchOptions{MaxBatch: 2, MaxWait: time.Minute})
+	p.Register("email.send", func(context.Context, *models.Job) (json.RawMessage, error) {
+		return nil, nil
+	})
+
+	for range 2 {
+		enqueue(t, q, "row.upsert")
+		enqueue(t, q, "email.send")
+		enqueue(t, q, "row.upsert")
+	}
+	startPool(t, p)
+
+	for _, s := range q.waitSettled(t, 6) {
+		assert.True(t, s.acked)
+	}
+
+	assert.Equal(t, []int{2, 2}, rec.sizes())
+}
+
+func TestPool_BatchHeartbeatCoversAccumulation(t *testing.T) {
+	const visibility = 40 * time.Millisecond
+
+	q := &recordingQueue{MemoryQueue: queue.NewMemoryQueue(queue.Config{VisibilityTimeout: visibility})}
+	p := New(q, config.WorkerConfig{Concurrency: 2, HeartbeatInterval: visibility / 4},
+		logger.NewNop(), WithVisibilityTimeout(visibility))
+
+	rec := &batchRecorder{}
+	p.RegisterBatch("row.upsert", rec.handle, BatchOptions{MaxBatch: 10, MaxWait: 4 * visibility})
+
+	job := enqueue(t, q, "row.upsert")
+	startPool(t, p)
+
+	settled := q.waitSettled(t, 1)
+	assert.Equal(t, job.ID, settled[0].jobID)
+	assert.True(t, settled[0].acked)
+	require.Equal(t, []int{1}, rec.sizes(), "job was redelivered while its batch filled")
+}
+
+func TestPool_BatchDropsLostLeases(t *testing.T) {
+	q := newRecordingQueue()
+	p := New(q, config.WorkerConfig{Concurrency: 2, HeartbeatInterval: 5 * time.Millisecond},
+		logger.NewNop())
+
+	rec := &batchRecorder{}
+	p.RegisterBatch("row.upsert", rec.handle, BatchOptions{MaxBatch: 2, MaxWait: 100 * time.Millisecond})
+
+	lost := enqueue(t, q, "row.upsert")
+	startPool(t, p)
+	require.Eventually(t, func() bool {
+		stats, err := q.Stats(context.Background())
+		return err == nil && stats.Processing == 1
+	}, time.Second, 5*time.Millisecond)
+	require.NoError(t, q.Delete(context.Background(), lost.ID), "the lease is lost")
+
+	time.Sleep(150 * time.Millisecond)
+	assert.Empty(t, rec.sizes(), "a batch of lost jobs never reaches the handler")
+
+	kept := enqueue(t, q, "row.upsert")
+	settled := q.waitSettled(t, 1)
+	assert.Equal(t, kept.ID, settled[0].jobID)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	assert.Equal(t, [][]uuid.UUID{{kept.ID}}, rec.batches)
+}
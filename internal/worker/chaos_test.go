@@ -0,0 +1,241 @@
+//go:build chaos
+
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/internal/queue/queuetest"
+	"task-queue/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// soakJobs is how many jobs each soak scenario submits
+const soakJobs = 3000
+
+// soakVisibility is the visibility timeout of the soak queues, short so
+// dropped acks are redelivered quickly
+const soakVisibility = 300 * time.Millisecond
+
+// soakMetrics is a PoolMetrics counting what the pool reports
+type soakMetrics struct {
+	mu       sync.Mutex
+	inFlight int
+	waits    int
+	nacks    map[string]int
+}
+
+func (m *soakMetrics) AddInFlight(delta int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inFlight += delta
+}
+
+func (m *soakMetrics) ObserveWait(string, time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.waits++
+}
+
+func (m *soakMetrics) ObservePanic(string)            {}
+func (m *soakMetrics) ObserveHeartbeatFailure(string) {}
+
+func (m *soakMetrics) ObserveNack(reasonClass string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nacks[reasonClass]++
+}
+
+// soakHandler counts the runs of each job and fails a share of them
+type soakHandler struct {
+	mu        sync.Mutex
+	rand      *rand.Rand
+	failRate  float64
+	runs      map[uuid.UUID]int
+	succeeded map[uuid.UUID]bool
+	failures  int
+}
+
+func (h *soakHandler) handle(_ context.Context, job *models.Job) (json.RawMessage, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.runs[job.ID]++
+	if h.rand.Float64() < h.failRate {
+		h.failures++
+		return nil, RetryAfter(5*time.Millisecond, fmt.Errorf("flaky handler"))
+	}
+
+	h.succeeded[job.ID] = true
+	return json.RawMessage(`{}`), nil
+}
+
+// totalRuns returns how many times the handler ran
+func (h *soakHandler) totalRuns() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	total := 0
+	for _, n := range h.runs {
+		total += n
+	}
+
+	return total
+}
+
+// chaosSeed returns TQ_CHAOS_SEED, or a fresh seed when it is unset
+func chaosSeed(t *testing.T) int64 {
+	t.Helper()
+
+	if env := os.Getenv("TQ_CHAOS_SEED"); env != "" {
+		seed, err := strconv.ParseInt(env, 10, 64)
+		require.NoError(t, err, "TQ_CHAOS_SEED")
+		return seed
+	}
+
+	return time.Now().UnixNano()
+}
+
+func TestChaos_PoolKeepsDeliveryContract(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		faults   queuetest.Faults
+		failRate float64
+	}{
+		{
+			name:     "flaky backend",
+			faults:   queuetest.Faults{ErrorRate: 0.05, Latency: time.Millisecond},
+			failRate: 0.1,
+		},
+		{
+			name: "duplicates and lost acks",
+			faults: queuetest.Faults{
+				DuplicateRate: 0.05,
+				DropAckRate:   0.03,
+				AckDelay:      2 * time.Millisecond,
+			},
+			failRate: 0.1,
+		},
+		{
+			name: "everything at once",
+			faults: queuetest.Faults{
+				ErrorRate:        0.03,
+				MethodErrorRates: map[string]float64{"Ack": 0.1, "NackWithDelay": 0.1},
+				DuplicateRate:    0.03,
+				DropAckRate:      0.03,
+				AckDelay:         time.Millisecond,
+				Latency:          time.Millisecond,
+			},
+			failRate: 0.2,
+		},
+	}
+
+	for _, sc := range scenarios {
+		t.Run(sc.name, func(t *testing.T) {
+			seed := chaosSeed(t)
+			t.Logf("seed %d, rerun with TQ_CHAOS_SEED=%d", seed, seed)
+			sc.faults.Seed = seed
+			soak(t, sc.faults, sc.failRate, seed)
+		})
+	}
+}
+
+// soak runs a pool over soakJobs jobs on a memory queue behind a
+// ChaosQueue injecting faults, and checks the at-least-once contract
+func soak(t *testing.T, faults queuetest.Faults, failRate float64, seed int64) {
+	ctx := context.Background()
+	inner := queue.NewMemoryQueue(queue.Config{VisibilityTimeout: soakVisibility})
+	chaos := queuetest.NewChaosQueue(inner, faults)
+
+	submitted := make(map[uuid.UUID]bool, soakJobs)
+	for range soakJobs {
+		job := models.NewJob("email_send", json.RawMessage(`{}`), models.JobPriorityNormal)
+		job.MaxRetries = 3
+		submitted[job.ID] = true
+		require.Eventually(t, func() bool { return chaos.Enqueue(ctx, job) == nil },
+			time.Second, time.Millisecond, "enqueue never got through")
+	}
+
+	handler := &soakHandler{
+		rand:      rand.New(rand.NewSource(seed)),
+		failRate:  failRate,
+		runs:      make(map[uuid.UUID]int),
+		succeeded: make(map[uuid.UUID]bool),
+	}
+
+	metrics := &soakMetrics{nacks: make(map[string]int)}
+	p := New(chaos, config.WorkerConfig{Concurrency: 16, HeartbeatInterval: soakVisibility / 4},
+		logger.NewNop(), WithVisibilityTimeout(soakVisibility), WithPoolMetrics(metrics))
+	p.Register("email_send", handler.handle)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- p.Run(runCtx) }()
+
+	require.Eventually(t, func() bool {
+		s, err := inner.Stats(ctx)
+		if err != nil {
+			return false
+		}
+
+		metrics.mu.Lock()
+		idle := metrics.inFlight == 0
+		metrics.mu.Unlock()
+
+		return idle && s.Size == 0 && s.Processing == 0 && s.Delayed == 0
+	}, 2*time.Minute, 50*time.Millisecond, "the queue never drained")
+
+	cancel()
+	require.NoError(t, <-done)
+
+	dead, total, err := inner.DeadLetters(ctx, 0, soakJobs)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(dead)), total)
+
+	settled := make(map[uuid.UUID]bool, soakJobs)
+	for id := range handler.succeeded {
+		settled[id] = true
+	}
+
+	for _, job := range dead {
+		settled[job.ID] = true
+	}
+
+	for id := range settled {
+		assert.True(t, submitted[id], "job %s was never submitted", id)
+	}
+
+	assert.Len(t, settled, soakJobs, "every job succeeded or was dead-lettered")
+
+	// Every delivery past a job's first is caused by a handler failure or an
+	// injected fault, each of which redelivers a job at most once
+	injected := chaos.Injected()
+	runs := handler.totalRuns()
+	bound := soakJobs + handler.failures +
+		int(injected.Duplicates+injected.DroppedAcks+injected.Errors)
+	assert.LessOrEqual(t, runs, bound, "redeliveries beyond the at-least-once contract")
+	t.Logf("%d runs, %d failures, %d dead, injected %+v", runs, handler.failures, len(dead), injected)
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	assert.Zero(t, metrics.inFlight)
+	assert.Equal(t, runs, metrics.waits, "every delivery was observed")
+	assert.Equal(t, handler.failures, metrics.nacks[NackRetryAfter], "every failure was nacked")
+}
@@ -0,0 +1,148 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+)
+
+// escalateScanLimit is how many of the longest waiting jobs of each
+// priority an escalation pass looks at
+const escalateScanLimit = 100
+
+// DeadlineMetrics counts jobs that started after their deadline and waiting
+// jobs promoted for missing it. internal/metrics provides a Prometheus
+// implementation.
+type DeadlineMetrics interface {
+	ObserveDeadlineBreach(jobType, priority string)
+	ObserveEscalation(jobType, from string)
+}
+
+// WithDeadlineMetrics reports every deadline breach and escalation to m
+func WithDeadlineMetrics(m DeadlineMetrics) Option {
+	return func(p *Pool) {
+		p.deadlineMetrics = m
+	}
+}
+
+// checkDeadline records a breach of job's deadline when it waited longer
+// than that before a worker picked it up: a slo_breached event in its
+// audit trail, a warning, and a metric
+func (p *Pool) checkDeadline(ctx context.Context, log logger.Logger, job *models.Job) {
+	deadline := p.config.Deadlines.Deadline(job.Type, job.Priority)
+	if deadline <= 0 {
+		return
+	}
+
+	wait := p.now().Sub(readyAt(job))
+	if wait <= deadline {
+		return
+	}
+
+	log.Warn("job started after its deadline",
+		"priority", job.Priority.Name(),
+		"wait", wait,
+		"deadline", deadline,
+	)
+	p.recordEvent(ctx, log, job, logger.AuditJobSLOBreached, map[string]any{
+		"priority":    job.Priority.Name(),
+		"wait_ms":     wait.Milliseconds(),
+		"deadline_ms": deadline.Milliseconds(),
+	})
+
+	if p.deadlineMetrics != nil {
+		p.deadlineMetrics.ObserveDeadlineBreach(p.typeLabel(job.Type), job.Priority.Name())
+	}
+}
+
+// escalate runs an escalation pass over q every escalation interval until
+// ctx is canceled
+func (p *Pool) escalate(ctx context.Context, q queue.Reprioritizer) {
+	ticker := time.NewTicker(p.config.Deadlines.Interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+		}
+
+		p.escalatePass(ctx, q)
+	}
+}
+
+// escalatePass promotes each ready job that is past its deadline one
+// priority up, and returns how many it promoted. Priorities are scanned
+// from high down, so a job climbs at most one level a pass.
+func (p *Pool) escalatePass(ctx context.Context, q queue.Reprioritizer) int {
+	now := p.now()
+	promoted := 0
+	for _, priority := range []models.JobPriority{
+		models.JobPriorityHigh,
+		models.JobPriorityNormal,
+		models.JobPriorityLow,
+	} {
+		jobs, err := q.Waiting(ctx, priority, escalateScanLimit)
+		if err != nil {
+			if ctx.Err() == nil {
+				p.logger.Warn("failed to read waiting jobs for escalation", "error", err)
+			}
+
+			return promoted
+		}
+
+		for _, job := range jobs {
+			deadline := p.config.Deadlines.Deadline(job.Type, priority)
+			wait := now.Sub(readyAt(job))
+			if deadline <= 0 || wait <= deadline {
+				continue
+			}
+
+			if p.promote(ctx, q, job, wait) {
+				promoted++
+			}
+		}
+	}
+
+	return promoted
+}
+
+// promote moves job, which has waited past its deadline, one priority up
+// and records it in the job's audit trail. A job dequeued in the meantime
+// is left alone.
+func (p *Pool) promote(ctx context.Context, q queue.Reprioritizer, job *models.Job, wait time.Duration) bool {
+	log := p.logger.With("job_id", job.ID, "type", job.Type)
+	from := job.Priority
+	updated, err := q.UpdatePriority(ctx, job.ID, from+1)
+	switch {
+	case errors.HasCode(err, errors.CodeNotFound):
+		return false
+
+	case err != nil:
+		log.Warn("failed to escalate job", "error", err)
+		return false
+	}
+
+	log.Info("escalated job past its deadline",
+		"from", from.Name(),
+		"to", updated.Priority.Name(),
+		"wait", wait,
+	)
+	p.recordEvent(ctx, log, updated, logger.AuditJobEscalated, map[string]any{
+		"from":    from.Name(),
+		"to":      updated.Priority.Name(),
+		"wait_ms": wait.Milliseconds(),
+	})
+
+	if p.deadlineMetrics != nil {
+		p.deadlineMetrics.ObserveEscalation(p.typeLabel(job.Type), from.Name())
+	}
+
+	return true
+}
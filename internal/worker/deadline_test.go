@@ -0,0 +1,168 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/internal/storage"
+	"task-queue/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deadlineRecorder is a DeadlineMetrics that records what it observes
+type deadlineRecorder struct {
+	mu          sync.Mutex
+	breaches    []string
+	escalations []string
+}
+
+func (r *deadlineRecorder) ObserveDeadlineBreach(jobType, priority string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.breaches = append(r.breaches, jobType+"/"+priority)
+}
+
+func (r *deadlineRecorder) ObserveEscalation(jobType, from string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.escalations = append(r.escalations, jobType+"/"+from)
+}
+
+// eventsOfType returns the events of job recorded in events with eventType
+func eventsOfType(t *testing.T, events storage.EventStore, job *models.Job, eventType string) []models.JobEvent {
+	t.Helper()
+
+	all, err := events.ListEvents(context.Background(), job.ID)
+	require.NoError(t, err)
+
+	var matched []models.JobEvent
+	for _, event := range all {
+		if event.EventType == eventType {
+			matched = append(matched, event)
+		}
+	}
+
+	return matched
+}
+
+func TestDeadline_RecordsBreachOnDequeue(t *testing.T) {
+	now := time.Now()
+	cfg := config.WorkerConfig{Deadlines: config.DeadlineConfig{
+		Priorities: map[string]time.Duration{"critical": 30 * time.Second},
+	}}
+
+	q := newRecordingQueue()
+	events := storage.NewMemoryEventStore()
+	metrics := &deadlineRecorder{}
+	p := New(q, cfg, logger.NewNop(), WithEventStore(events), WithDeadlineMetrics(metrics))
+	p.now = func() time.Time { return now }
+	p.Register("payment.capture", func(context.Context, *models.Job) (json.RawMessage, error) {
+		return nil, nil
+	})
+
+	late := models.NewJob("payment.capture", json.RawMessage(`{}`), models.JobPriorityCritical)
+	late.CreatedAt = now.Add(-time.Minute)
+	onTime := models.NewJob("payment.capture", json.RawMessage(`{}`), models.JobPriorityCritical)
+	onTime.CreatedAt = now.Add(-10 * time.Second)
+	noDeadline := models.NewJob("payment.capture", json.RawMessage(`{}`), models.JobPriorityLow)
+	noDeadline.CreatedAt = now.Add(-time.Hour)
+	require.NoError(t, q.EnqueueBatch(context.Background(), []*models.Job{late, onTime, noDeadline}))
+
+	startPool(t, p)
+	q.waitSettled(t, 3)
+
+	breaches := eventsOfType(t, events, late, logger.AuditJobSLOBreached)
+	require.Len(t, breaches, 1)
+	assert.Equal(t, "critical", breaches[0].EventData["priority"])
+	assert.Equal(t, time.Minute.Milliseconds(), breaches[0].EventData["wait_ms"])
+	assert.Equal(t, (30 * time.Second).Milliseconds(), breaches[0].EventData["deadline_ms"])
+
+	assert.Empty(t, eventsOfType(t, events, onTime, logger.AuditJobSLOBreached))
+	assert.Empty(t, eventsOfType(t, events, noDeadline, logger.AuditJobSLOBreached))
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	assert.Equal(t, []string{"payment.capture/critical"}, metrics.breaches)
+}
+
+func TestDeadline_EscalatesStuckJob(t *testing.T) {
+	ctx := context.Background()
+	start := time.Now()
+	now := start
+	cfg := config.WorkerConfig{Deadlines: config.DeadlineConfig{
+		Priorities: map[string]time.Duration{"low": time.Minute, "normal": 5 * time.Minute},
+		Escalate:   true,
+	}}
+
+	q := queue.NewMemoryQueue(queue.Config{})
+	events := storage.NewMemoryEventStore()
+	metrics := &deadlineRecorder{}
+	p := New(q, cfg, logger.NewNop(), WithEventStore(events), WithDeadlineMetrics(metrics))
+	p.now = func() time.Time { return now }
+
+	stuck := models.NewJob("report.build", json.RawMessage(`{}`), models.JobPriorityLow)
+	stuck.CreatedAt = start
+	fresh := models.NewJob("report.build", json.RawMessage(`{}`), models.JobPriorityLow)
+	fresh.CreatedAt = start.Add(90 * time.Second)
+	require.NoError(t, q.EnqueueBatch(ctx, []*models.Job{stuck, fresh}))
+
+	now = start.Add(2 * time.Minute)
+	assert.Equal(t, 1, p.escalatePass(ctx, q), "only the job past its deadline is promoted")
+	assert.Equal(t, 0, p.escalatePass(ctx, q), "within the normal deadline")
+
+	now = start.Add(6 * time.Minute)
+	assert.Equal(t, 2, p.escalatePass(ctx, q), "normal to high, and the fresh job to normal")
+
+	next, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, stuck.ID, next.ID)
+	assert.Equal(t, models.JobPriorityHigh, next.Priority)
+
+	escalations := eventsOfType(t, events, stuck, logger.AuditJobEscalated)
+	require.Len(t, escalations, 2)
+	assert.Equal(t, "low", escalations[0].EventData["from"])
+	assert.Equal(t, "normal", escalations[0].EventData["to"])
+	assert.Equal(t, "normal", escalations[1].EventData["from"])
+	assert.Equal(t, "high", escalations[1].EventData["to"])
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	assert.Equal(t, []string{"unknown/low", "unknown/normal", "unknown/low"}, metrics.escalations)
+}
+
+func TestDeadline_RunEscalatesInBackground(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.WorkerConfig{Deadlines: config.DeadlineConfig{
+		Priorities:       map[string]time.Duration{"low": time.Second, "normal": time.Second, "high": time.Second},
+		Escalate:         true,
+		EscalateInterval: 5 * time.Millisecond,
+	}}
+
+	q := queue.NewMemoryQueue(queue.Config{})
+	require.NoError(t, q.Pause(ctx))
+
+	p := New(q, cfg, logger.NewNop())
+	p.now = func() time.Time { return time.Now().Add(time.Hour) }
+	p.Register("report.build", func(context.Context, *models.Job) (json.RawMessage, error) {
+		return nil, nil
+	})
+
+	job := models.NewJob("report.build", json.RawMessage(`{}`), models.JobPriorityLow)
+	require.NoError(t, q.Enqueue(ctx, job))
+	startPool(t, p)
+
+	require.Eventually(t, func() bool {
+		waiting, err := q.Waiting(ctx, models.JobPriorityCritical, 1)
+		return err == nil && len(waiting) == 1 && waiting[0].ID == job.ID
+	}, 2*time.Second, 5*time.Millisecond, "the paused job climbs to critical")
+}
@@ -0,0 +1,126 @@
+// Package worker runs job handlers against a queue. A Pool dequeues jobs
+// with a fixed number of goroutines, dispatches each to the handler
+// registered for its type under the process timeout, and acknowledges or
+// negatively acknowledges it with the outcome.
+//
+// Basic usage:
+//
+//	pool := worker.New(q, cfg.Worker, log)
+//	pool.Register("email.send", func(ctx context.Context, job *models.Job) (json.RawMessage, error) {
+//	    return nil, sendEmail(ctx, job.Payload)
+//	})
+//
+//	// Run blocks until ctx is canceled and in-flight jobs finish
+//	err := pool.Run(ctx)
+//
+// Canceling ctx also cancels the handlers' contexts. A job whose handler
+// fails once the pool is shutting down is released back to the queue
+// without counting a retry.
+//
+// Handlers can override how a failure is retried:
+//
+//	return nil, worker.Permanent(err)                  // dead-letter now
+//	return nil, worker.RetryAfter(10*time.Minute, err) // retry after exactly 10m
+//	return nil, worker.Reschedule(t, "window closed")  // run again at t, no retry counted
+//
+// WithStore keeps the jobs table in step with processing: a job is marked
+// running when a worker picks it up, and its outcome is written before the
+// job is settled with the queue. A result that cannot be stored nacks the
+// job rather than acking it, so it is retried instead of lost.
+// WithEventStore also records started, completed, and failed events, and
+// WithPublisher publishes them, with dead-lettering, to an event bus.
+//
+// WithTracerProvider runs each attempt in a job.process span, a child of
+// the job.enqueue span whose context the job carries in its metadata, so a
+// handler's own spans and log entries join the submitter's trace.
+//
+// RegisterBatch registers a handler that receives several jobs of one type
+// at once, for work that is cheaper in bulk. Jobs are held under heartbeats
+// until MaxBatch accumulate or the first has waited MaxWait, and each is
+// settled from its own Result:
+//
+//	pool.RegisterBatch("row.upsert", upsertRows, worker.BatchOptions{
+//	    MaxBatch: 100,
+//	    MaxWait:  500 * time.Millisecond,
+//	})
+//
+// Middleware wraps handlers. Middleware added with Use applies to every
+// handler in the order it was added, the first outermost; middleware passed
+// to RegisterWith runs inside it for one job type only:
+//
+//	pool.Use(worker.Recover(), worker.Logging(log), worker.Metrics(jobMetrics))
+//	pool.RegisterWith("report.build", buildReport, worker.Timeout(30*time.Second))
+//
+// WithLock keeps jobs that share a key, such as an account, from running at
+// the same time on any worker sharing its Locker. Its lock is refreshed by
+// the job's heartbeat, and a job whose lock is held is rescheduled:
+//
+//	pool.RegisterWith("account.sync", syncAccount,
+//	    worker.WithLock(locker, func(job *models.Job) string { return accountOf(job) }, time.Minute))
+//
+// The process timeout always wraps the handler innermost. A job's own
+// Timeout takes precedence over it.
+//
+// While a handler runs, a heartbeat extends the job's visibility timeout
+// every HeartbeatInterval so the queue does not redeliver it. Pass the
+// queue's visibility timeout with WithVisibilityTimeout. If the queue
+// rejects an extension because the job was reaped or settled elsewhere, the
+// handler's context is canceled and the job is left unsettled.
+//
+// WorkerConfig.Types caps the concurrency of individual job types within the
+// pool's Concurrency, and unlisted types share DefaultTypeConcurrency slots.
+// A dequeued job whose type is at its limit is released back to the queue
+// for a short delay without counting a retry, so it never holds a worker.
+// A type's RateLimit caps how fast its jobs start: a job over the limit
+// waits up to ThrottleMaxWait for its turn, its lease extended by the
+// heartbeat meanwhile, or is released until then under the requeue policy.
+// Pool.Reload applies changed rate limits from a
+// reloaded configuration.
+//
+// With WorkerConfig.Autoscale enabled the pool starts MinConcurrency workers
+// and resizes itself every Interval from the queue's statistics, between
+// MinConcurrency and MaxConcurrency. Workers removed on scale-down finish
+// their current job first. Pool.Concurrency reports the current size and
+// WithScalingMetrics exports it with every scaling decision.
+//
+// Pool.Health reports whether the pool is live, meaning its queue answers
+// and idle workers keep polling, and ready, meaning it also runs handlers
+// and is not draining. HealthHandler serves the report on /healthz and
+// /readyz for Kubernetes probes, and HealthCheck plugs readiness into a
+// health.Checker.
+//
+// WorkerConfig.ReservedLane keeps Workers of the pool for jobs of
+// MinPriority or higher, so a flood of routine jobs cannot hold up urgent
+// ones. Reserved workers poll with Queue.DequeueMinPriority more often than
+// the general ones, and borrow ordinary jobs after IdleGrace without urgent
+// work so the slots are not wasted.
+//
+// WorkerConfig.Deadlines sets how long jobs of each priority, or of
+// particular types, may wait before they start. A job a worker picks up
+// after its deadline gets a job.slo_breached event in its audit trail,
+// readable through the job events API, and is counted by DeadlineMetrics.
+// With Escalate, and a queue that implements queue.Reprioritizer, the pool
+// also promotes ready jobs already past their deadline one priority up
+// every EscalateInterval, recording a job.escalated event for each.
+//
+// A MultiQueue lets one pool consume several named queues, such as those
+// listed in WorkerConfig.Queues, with a weighted round-robin over their
+// dequeues. A paused, drained, or empty queue gives its turn to the others,
+// and each job is settled on the queue it came from. A job whose lease was
+// lost is forgotten, as the pool never settles it:
+//
+//	queues, err := worker.QueuesFrom(manager, cfg.Worker.Queues)
+//	if err != nil {
+//	    return err
+//	}
+//	mq, err := worker.NewMultiQueue(queues, worker.WithQueueMetrics(queueMetrics))
+//	if err != nil {
+//	    return err
+//	}
+//	pool := worker.New(mq, cfg.Worker, log)
+//
+// Handlers must tolerate running a job more than once. To test them the
+// way production delivers jobs, run the pool over a queuetest.ChaosQueue,
+// which injects failed calls, duplicate deliveries, and lost acks. The
+// pool's own soak tests do so behind the chaos build tag (make test-chaos).
+package worker
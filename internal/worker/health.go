@@ -0,0 +1,170 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"task-queue/pkg/errors"
+)
+
+// Health check defaults
+const (
+	// livenessPolls is how many poll intervals a worker may go without
+	// completing a dequeue before the pool reports itself wedged
+	livenessPolls = 50
+
+	// healthCheckTimeout bounds the queue connectivity check
+	healthCheckTimeout = 2 * time.Second
+)
+
+// HealthReport describes whether a pool is alive and ready. Live fails when
+// the queue is unreachable or idle workers have stopped polling; Ready
+// also requires a running pool with handlers that is not draining.
+type HealthReport struct {
+	Live           bool       `json:"live"`
+	Ready          bool       `json:"ready"`
+	Running        bool       `json:"running"`
+	Draining       bool       `json:"draining"`
+	Handlers       int        `json:"handlers"`
+	InFlight       int        `json:"in_flight"`
+	Workers        int        `json:"workers"`
+	LastPoll       *time.Time `json:"last_poll,omitempty"`
+	LastDequeue    *time.Time `json:"last_dequeue,omitempty"`
+	QueueReachable bool       `json:"queue_reachable"`
+	QueueError     string     `json:"queue_error,omitempty"`
+	Problems       []string   `json:"problems,omitempty"`
+}
+
+// health tracks the dequeue loop for health reports
+type health struct {
+	running     atomic.Bool
+	draining    atomic.Bool
+	started     atomic.Int64
+	inFlight    atomic.Int64
+	lastPoll    atomic.Int64
+	lastDequeue atomic.Int64
+}
+
+// WithLivenessThreshold sets how long workers that are not handling a job
+// may go without completing a dequeue before the pool reports itself not
+// live. It defaults to 50 poll intervals.
+func WithLivenessThreshold(d time.Duration) Option {
+	return func(p *Pool) {
+		p.livenessThreshold = d
+	}
+}
+
+// polled records a completed dequeue, and whether it returned a job
+func (h *health) polled(dequeued bool) {
+	now := time.Now().UnixNano()
+	h.lastPoll.Store(now)
+	if dequeued {
+		h.lastDequeue.Store(now)
+	}
+}
+
+// Health reports whether the pool is alive and ready. It checks that the
+// queue answers within a short timeout, and that some worker completed a
+// dequeue recently unless every worker is busy with a job.
+func (p *Pool) Health(ctx context.Context) HealthReport {
+	p.mu.RLock()
+	handlers := len(p.handlers) + len(p.batchers)
+	p.mu.RUnlock()
+
+	report := HealthReport{
+		Running:  p.health.running.Load(),
+		Draining: p.health.draining.Load(),
+		Handlers: handlers,
+		InFlight: int(p.health.inFlight.Load()),
+		Workers:  p.Concurrency(),
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	if _, err := p.queue.Size(checkCtx); err != nil {
+		report.QueueError = err.Error()
+		report.Problems = append(report.Problems, "queue unreachable")
+	} else {
+		report.QueueReachable = true
+	}
+
+	if last := p.health.lastPoll.Load(); last != 0 {
+		at := time.Unix(0, last)
+		report.LastPoll = &at
+	}
+
+	if last := p.health.lastDequeue.Load(); last != 0 {
+		at := time.Unix(0, last)
+		report.LastDequeue = &at
+	}
+
+	if report.Running && !report.Draining && report.InFlight < report.Workers {
+		since := time.Unix(0, p.health.started.Load())
+		if report.LastPoll != nil && report.LastPoll.After(since) {
+			since = *report.LastPoll
+		}
+
+		if time.Since(since) > p.livenessThreshold {
+			report.Problems = append(report.Problems, "workers stopped polling")
+		}
+	}
+
+	report.Live = len(report.Problems) == 0
+	switch {
+	case handlers == 0:
+		report.Problems = append(report.Problems, "no handlers registered")
+
+	case !report.Running:
+		report.Problems = append(report.Problems, "not running")
+
+	case report.Draining:
+		report.Problems = append(report.Problems, "draining")
+	}
+
+	report.Ready = len(report.Problems) == 0
+	return report
+}
+
+// HealthCheck fails, naming the problems of the pool's health report,
+// unless the pool is ready. It fits a health checker's check signature.
+func (p *Pool) HealthCheck(ctx context.Context) error {
+	report := p.Health(ctx)
+	if report.Ready {
+		return nil
+	}
+
+	return errors.Newf("worker pool not ready: %s", strings.Join(report.Problems, ", ")).
+		WithCode(errors.CodeInternal).
+		WithMetadata("problems", report.Problems).
+		WithOp("worker.HealthCheck")
+}
+
+// HealthHandler serves the pool's health report as JSON: /readyz answers
+// 200 when it is ready and any other path, such as /healthz, answers 200
+// when it is live. Failing checks answer 503.
+func (p *Pool) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := p.Health(r.Context())
+
+		ok := report.Live
+		if r.URL.Path == "/readyz" {
+			ok = report.Ready
+		}
+
+		status := http.StatusOK
+		if !ok {
+			status = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			p.logger.Warn("failed to write health report", "error", err)
+		}
+	})
+}
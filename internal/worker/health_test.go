@@ -0,0 +1,160 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unreachableQueue fails every call once down is set, like a queue whose
+// Redis went away
+type unreachableQueue struct {
+	*recordingQueue
+	down atomic.Bool
+}
+
+func (q *unreachableQueue) err() error {
+	return errors.New("dial tcp 127.0.0.1:6379: connection refused").WithCode(errors.CodeNetwork)
+}
+
+func (q *unreachableQueue) Dequeue(ctx context.Context) (*models.Job, error) {
+	if q.down.Load() {
+		return nil, q.err()
+	}
+
+	return q.recordingQueue.Dequeue(ctx)
+}
+
+func (q *unreachableQueue) Size(ctx context.Context) (int64, error) {
+	if q.down.Load() {
+		return 0, q.err()
+	}
+
+	return q.recordingQueue.Size(ctx)
+}
+
+// probe requests path from p's health handler and decodes the report
+func probe(t *testing.T, p *Pool, path string) (int, HealthReport) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	p.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var report HealthReport
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+	return rec.Code, report
+}
+
+func TestPool_HealthHealthy(t *testing.T) {
+	q := newRecordingQueue()
+	p := New(q, config.WorkerConfig{Concurrency: 2}, logger.NewNop())
+	p.Register("email.send", func(context.Context, *models.Job) (json.RawMessage, error) {
+		return nil, nil
+	})
+
+	status, report := probe(t, p, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, []string{"not running"}, report.Problems)
+	assert.ErrorContains(t, p.HealthCheck(context.Background()), "not running")
+
+	enqueue(t, q, "email.send")
+	startPool(t, p)
+	q.waitSettled(t, 1)
+
+	status, report = probe(t, p, "/healthz")
+	assert.Equal(t, http.StatusOK, status)
+	assert.True(t, report.Live)
+	assert.True(t, report.QueueReachable)
+	assert.Equal(t, 1, report.Handlers)
+	assert.Equal(t, 2, report.Workers)
+	assert.NotNil(t, report.LastPoll)
+	assert.NotNil(t, report.LastDequeue)
+
+	status, report = probe(t, p, "/readyz")
+	assert.Equal(t, http.StatusOK, status)
+	assert.True(t, report.Ready)
+	assert.Empty(t, report.Problems)
+	assert.NoError(t, p.HealthCheck(context.Background()))
+}
+
+func TestPool_HealthQueueDown(t *testing.T) {
+	q := &unreachableQueue{recordingQueue: newRecordingQueue()}
+	p := New(q, config.WorkerConfig{Concurrency: 2}, logger.NewNop(),
+		WithLivenessThreshold(5*idleWait))
+	p.Register("email.send", func(context.Context, *models.Job) (json.RawMessage, error) {
+		return nil, nil
+	})
+
+	startPool(t, p)
+	require.Eventually(t, func() bool { return p.Health(context.Background()).Live },
+		time.Second, 5*time.Millisecond)
+
+	q.down.Store(true)
+	status, report := probe(t, p, "/healthz")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.False(t, report.QueueReachable)
+	assert.Contains(t, report.QueueError, "connection refused")
+	assert.Contains(t, report.Problems, "queue unreachable")
+
+	// Workers that keep failing to dequeue stop counting as polling
+	require.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"queue unreachable", "workers stopped polling"},
+			p.Health(context.Background()).Problems)
+	}, 2*time.Second, 10*time.Millisecond)
+
+	q.down.Store(false)
+	require.Eventually(t, func() bool { return p.Health(context.Background()).Ready },
+		time.Second, 5*time.Millisecond)
+}
+
+func TestPool_HealthDraining(t *testing.T) {
+	q := newRecordingQueue()
+	p := New(q, config.WorkerConfig{Concurrency: 1}, logger.NewNop())
+
+	// The handler finishes its job even after shutdown begins
+	var running atomic.Int32
+	release := make(chan struct{})
+	p.Register("report.build", func(context.Context, *models.Job) (json.RawMessage, error) {
+		running.Add(1)
+		<-release
+		return nil, nil
+	})
+
+	enqueue(t, q, "report.build")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- p.Run(ctx) }()
+
+	require.Eventually(t, func() bool { return running.Load() == 1 }, time.Second, time.Millisecond)
+	status, report := probe(t, p, "/healthz")
+	assert.Equal(t, http.StatusOK, status, "a busy worker is not wedged")
+	assert.Equal(t, 1, report.InFlight)
+
+	cancel()
+	require.Eventually(t, func() bool { return p.Health(context.Background()).Draining },
+		time.Second, time.Millisecond)
+
+	status, report = probe(t, p, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, []string{"draining"}, report.Problems)
+
+	status, _ = probe(t, p, "/healthz")
+	assert.Equal(t, http.StatusOK, status)
+
+	close(release)
+	require.NoError(t, <-done)
+	assert.False(t, p.Health(context.Background()).Draining)
+}
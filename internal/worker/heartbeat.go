@@ -0,0 +1,171 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// heartbeat extends the visibility timeout of a job while its handler runs
+type heartbeat struct {
+	done chan struct{}
+	wg   sync.WaitGroup
+	lost atomic.Bool
+
+	mu    sync.Mutex
+	hooks map[int]func(context.Context) error
+	next  int
+}
+
+// heartbeatKey carries the heartbeat of the running job in its handler's
+// context
+type heartbeatKey struct{}
+
+// onHeartbeat runs fn on every heartbeat of the job whose handler ctx
+// belongs to, until remove is called. It reports false when the job has no
+// heartbeat. An error from fn that says what it renews is gone cancels the
+// handler like a lost lease.
+func onHeartbeat(ctx context.Context, fn func(context.Context) error) (remove func(), ok bool) {
+	hb, ok := ctx.Value(heartbeatKey{}).(*heartbeat)
+	if !ok {
+		return nil, false
+	}
+
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+
+	id := hb.next
+	hb.next++
+	hb.hooks[id] = fn
+
+	return func() {
+		hb.mu.Lock()
+		defer hb.mu.Unlock()
+
+		delete(hb.hooks, id)
+	}, true
+}
+
+// runHooks runs the hooks registered with onHeartbeat and returns the first
+// error
+func (hb *heartbeat) runHooks(ctx context.Context) error {
+	hb.mu.Lock()
+	hooks := make([]func(context.Context) error, 0, len(hb.hooks))
+	for _, fn := range hb.hooks {
+		hooks = append(hooks, fn)
+	}
+	hb.mu.Unlock()
+
+	for _, fn := range hooks {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// context returns ctx carrying hb for onHeartbeat, unless hb never beats
+func (hb *heartbeat) context(ctx context.Context) context.Context {
+	if hb.hooks == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, heartbeatKey{}, hb)
+}
+
+// startHeartbeat extends job's visibility every HeartbeatInterval until
+// stop is called or ctx is canceled, then runs the hooks registered with
+// onHeartbeat. When the queue rejects an extension because the job was
+// reaped or settled elsewhere, cancel is called with the rejection so the
+// handler stops duplicating work, and the job is abandoned. A zero interval
+// starts nothing.
+func (p *Pool) startHeartbeat(ctx context.Context, cancel context.CancelCauseFunc,
+	log logger.Logger, job *models.Job) *heartbeat {
+	hb := &heartbeat{done: make(chan struct{})}
+	if p.config.HeartbeatInterval <= 0 {
+		return hb
+	}
+
+	hb.hooks = make(map[int]func(context.Context) error)
+
+	hb.wg.Add(1)
+	go func() {
+		defer hb.wg.Done()
+
+		ticker := time.NewTicker(p.config.HeartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-hb.done:
+				return
+
+			case <-ctx.Done():
+				return
+
+			case <-ticker.C:
+			}
+
+			err := p.queue.Extend(ctx, job.ID, p.visibility)
+			if err == nil {
+				if err := hb.runHooks(ctx); err != nil && ctx.Err() == nil {
+					log.Warn("heartbeat hook failed", "error", err)
+					if errors.HasCode(err, errors.CodeNotFound) || errors.HasCode(err, errors.CodeConflict) {
+						cancel(err)
+						return
+					}
+				}
+
+				continue
+			}
+
+			if ctx.Err() == nil {
+				p.observeHeartbeatFailure(job.Type)
+			}
+
+			if errors.HasCode(err, errors.CodeNotFound) || errors.HasCode(err, errors.CodeConflict) {
+				log.Warn("job lease lost, canceling handler", "error", err)
+				hb.lost.Store(true)
+				p.abandon(job.ID)
+				cancel(err)
+				return
+			}
+
+			if ctx.Err() == nil {
+				log.Warn("failed to extend job visibility", "error", err)
+			}
+		}
+	}()
+
+	return hb
+}
+
+// stop ends the heartbeat and waits for it to exit. It reports whether the
+// job's lease was lost.
+func (hb *heartbeat) stop() bool {
+	close(hb.done)
+	hb.wg.Wait()
+	return hb.lost.Load()
+}
+
+// abandoner is a queue tracking the jobs it hands out until they are
+// settled, such as MultiQueue. It is told of a job whose lease was lost, as
+// the pool never settles it.
+type abandoner interface {
+	Abandon(jobID uuid.UUID)
+}
+
+// abandon tells the queue the pool gave up jobID after losing its lease
+func (p *Pool) abandon(jobID uuid.UUID) {
+	if q, ok := p.queue.(abandoner); ok {
+		q.Abandon(jobID)
+	}
+}
@@ -0,0 +1,118 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rejectingQueue rejects every visibility extension
+type rejectingQueue struct {
+	*recordingQueue
+}
+
+func (q *rejectingQueue) Extend(context.Context, uuid.UUID, time.Duration) error {
+	return errors.New("job was acknowledged by another worker").WithCode(errors.CodeConflict)
+}
+
+func TestHeartbeat_PreventsRedelivery(t *testing.T) {
+	const visibility = 50 * time.Millisecond
+
+	q := &recordingQueue{MemoryQueue: queue.NewMemoryQueue(queue.Config{VisibilityTimeout: visibility})}
+	p := New(q, config.WorkerConfig{Concurrency: 2, HeartbeatInterval: visibility / 4},
+		logger.NewNop(), WithVisibilityTimeout(visibility))
+
+	var calls atomic.Int32
+	p.Register("report.build", func(ctx context.Context, _ *models.Job) (json.RawMessage, error) {
+		calls.Add(1)
+		return nil, wait3x(ctx, visibility)
+	})
+
+	job := enqueue(t, q, "report.build")
+	startPool(t, p)
+
+	settled := q.waitSettled(t, 1)
+	assert.Equal(t, job.ID, settled[0].jobID)
+	assert.True(t, settled[0].acked)
+	assert.EqualValues(t, 1, calls.Load(), "job was redelivered while its handler ran")
+}
+
+func TestHeartbeat_CancelsHandlerWhenRejected(t *testing.T) {
+	q := &rejectingQueue{recordingQueue: newRecordingQueue()}
+	p := New(q, config.WorkerConfig{HeartbeatInterval: 5 * time.Millisecond}, logger.NewNop())
+
+	causes := make(chan error, 1)
+	p.Register("report.build", func(ctx context.Context, _ *models.Job) (json.RawMessage, error) {
+		<-ctx.Done()
+		causes <- context.Cause(ctx)
+		return nil, ctx.Err()
+	})
+
+	enqueue(t, q, "report.build")
+	startPool(t, p)
+
+	select {
+	case cause := <-causes:
+		assert.True(t, errors.HasCode(cause, errors.CodeConflict))
+
+	case <-time.After(time.Second):
+		t.Fatal("handler was not canceled after the extension was rejected")
+	}
+
+	// The job belongs to someone else now, so it is neither acked nor nacked
+	time.Sleep(20 * time.Millisecond)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	assert.Empty(t, q.settled)
+}
+
+func TestHeartbeat_StopsOnShutdown(t *testing.T) {
+	q := newRecordingQueue()
+	p := New(q, config.WorkerConfig{HeartbeatInterval: time.Millisecond}, logger.NewNop())
+
+	started := make(chan struct{})
+	p.Register("report.build", func(ctx context.Context, _ *models.Job) (json.RawMessage, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	enqueue(t, q, "report.build")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- p.Run(ctx) }()
+
+	<-started
+	cancel()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
+
+// wait3x blocks for three times d, failing early when ctx is canceled
+func wait3x(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+
+	case <-time.After(3 * d):
+		return nil
+	}
+}
@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"task-queue/internal/models"
+)
+
+// laneWait is how often a reserved worker polls for urgent jobs while there
+// are none, kept under idleWait so urgent jobs start faster than any other
+// worker would pick them up
+const laneWait = idleWait / 2
+
+// dequeueFunc fetches the next job for a worker, or nil when there is none
+type dequeueFunc func(ctx context.Context) (*models.Job, error)
+
+// reservedWorker dequeues for one worker of the reserved lane. It takes only
+// jobs of min priority or higher, and borrows any job once it has found no
+// urgent job for grace.
+type reservedWorker struct {
+	pool      *Pool
+	min       models.JobPriority
+	grace     time.Duration
+	now       func() time.Time
+	idleSince time.Time
+}
+
+// newReservedWorker returns the dequeue state of one reserved worker. The
+// lane's priority was validated with the configuration, so an unknown one
+// falls back to critical.
+func (p *Pool) newReservedWorker() *reservedWorker {
+	min, ok := models.ParseJobPriority(p.config.ReservedLane.MinPriority)
+	if !ok {
+		min = models.JobPriorityCritical
+	}
+
+	return &reservedWorker{
+		pool:  p,
+		min:   min,
+		grace: p.config.ReservedLane.IdleGrace,
+		now:   time.Now,
+	}
+}
+
+// next returns the next urgent job or, once the worker has found none for
+// the idle grace, the next job of any priority. The grace starts over
+// whenever an urgent job turns up or the queue runs dry.
+func (w *reservedWorker) next(ctx context.Context) (*models.Job, error) {
+	job, err := w.pool.queue.DequeueMinPriority(ctx, w.min)
+	if err != nil {
+		return nil, err
+	}
+
+	now := w.now()
+	if job != nil || w.idleSince.IsZero() {
+		w.idleSince = now
+	}
+
+	if job != nil || w.grace <= 0 || now.Sub(w.idleSince) < w.grace {
+		return job, nil
+	}
+
+	job, err = w.pool.queue.Dequeue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if job == nil {
+		w.idleSince = now
+	}
+
+	return job, nil
+}
+
+// laneSizes returns the number of general and reserved workers to start
+// with. A fixed pool gives up part of its Concurrency to the reserved lane,
+// while an autoscaled one runs the lane on top of its scaled workers.
+func (p *Pool) laneSizes() (general, reserved int) {
+	reserved = p.config.ReservedLane.Workers
+	if p.config.Autoscale.Enabled {
+		return p.config.Autoscale.MinConcurrency, reserved
+	}
+
+	return max(p.config.Concurrency-reserved, 1), reserved
+}
@@ -0,0 +1,146 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingHandler counts running jobs and blocks them until release is
+// closed
+func blockingHandler(running *atomic.Int32, release <-chan struct{}) Handler {
+	return func(ctx context.Context, job *models.Job) (json.RawMessage, error) {
+		running.Add(1)
+		defer running.Add(-1)
+
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+
+		return nil, nil
+	}
+}
+
+// enqueueWithPriority adds a job of jobType and priority to q
+func enqueueWithPriority(t *testing.T, q queue.Queue, jobType string,
+	priority models.JobPriority) *models.Job {
+	t.Helper()
+
+	job := models.NewJob(jobType, json.RawMessage(`{}`), priority)
+	require.NoError(t, q.Enqueue(context.Background(), job))
+	return job
+}
+
+func TestPool_ReservedLaneStartsCriticalJobsUnderLoad(t *testing.T) {
+	q := queue.NewMemoryQueue(queue.Config{})
+	p := New(q, config.WorkerConfig{
+		Concurrency:  3,
+		ReservedLane: config.ReservedLaneConfig{Workers: 1, MinPriority: "critical"},
+	}, logger.NewNop())
+
+	var running atomic.Int32
+	release := make(chan struct{})
+	defer close(release)
+	p.Register("report.build", blockingHandler(&running, release))
+
+	started := make(chan time.Time, 1)
+	p.Register("alert.page", func(ctx context.Context, job *models.Job) (json.RawMessage, error) {
+		started <- time.Now()
+		return nil, nil
+	})
+
+	for range 10 {
+		enqueueWithPriority(t, q, "report.build", models.JobPriorityLow)
+	}
+
+	startPool(t, p)
+	require.Eventually(t, func() bool { return running.Load() == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, 3, p.Concurrency())
+
+	// The general workers are saturated, but the reserved one stays free
+	time.Sleep(2 * idleWait)
+	assert.EqualValues(t, 2, running.Load())
+
+	enqueued := time.Now()
+	enqueueWithPriority(t, q, "alert.page", models.JobPriorityCritical)
+
+	select {
+	case at := <-started:
+		assert.Less(t, at.Sub(enqueued), idleWait)
+
+	case <-time.After(time.Second):
+		t.Fatal("critical job did not start")
+	}
+}
+
+func TestPool_ReservedLaneBorrowsWorkAfterIdleGrace(t *testing.T) {
+	q := queue.NewMemoryQueue(queue.Config{})
+	p := New(q, config.WorkerConfig{
+		Concurrency: 2,
+		ReservedLane: config.ReservedLaneConfig{
+			Workers:     1,
+			MinPriority: "high",
+			IdleGrace:   150 * time.Millisecond,
+		},
+	}, logger.NewNop())
+
+	var running atomic.Int32
+	release := make(chan struct{})
+	defer close(release)
+	p.Register("report.build", blockingHandler(&running, release))
+
+	for range 3 {
+		enqueueWithPriority(t, q, "report.build", models.JobPriorityNormal)
+	}
+
+	startPool(t, p)
+	require.Eventually(t, func() bool { return running.Load() == 1 }, time.Second, time.Millisecond)
+	assert.EqualValues(t, 1, running.Load(), "reserved worker waits out its grace")
+
+	require.Eventually(t, func() bool { return running.Load() == 2 }, time.Second, time.Millisecond)
+}
+
+func TestReservedWorker_GraceStartsOverWhenIdle(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	q := queue.NewMemoryQueue(queue.Config{})
+	p := New(q, config.WorkerConfig{
+		Concurrency:  2,
+		ReservedLane: config.ReservedLaneConfig{Workers: 1, MinPriority: "high", IdleGrace: time.Minute},
+	}, logger.NewNop())
+
+	w := p.newReservedWorker()
+	w.now = func() time.Time { return now }
+
+	// An empty queue keeps restarting the grace
+	job, err := w.next(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, job)
+
+	now = now.Add(time.Minute)
+	job, err = w.next(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, job)
+
+	normal := enqueueWithPriority(t, q, "report.build", models.JobPriorityNormal)
+	job, err = w.next(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, job, "grace restarted when the queue ran dry")
+
+	now = now.Add(time.Minute)
+	job, err = w.next(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, normal.ID, job.ID)
+}
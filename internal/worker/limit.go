@@ -0,0 +1,77 @@
+package worker
+
+import (
+	"sync"
+	"time"
+
+	"task-queue/internal/config"
+)
+
+// limitedDelay is how long a job whose type is at its concurrency limit
+// waits in the queue before it can be dequeued again
+const limitedDelay = 250 * time.Millisecond
+
+// defaultBucket names the bucket shared by job types without their own limit
+const defaultBucket = ""
+
+// typeLimiter caps the number of jobs of each type running at once. Types
+// without their own limit share the default bucket.
+type typeLimiter struct {
+	mu      sync.Mutex
+	limits  map[string]int
+	running map[string]int
+}
+
+// newTypeLimiter builds the per-type limits of cfg. The default bucket
+// gets DefaultTypeConcurrency slots, or the most workers the pool may run
+// when that is zero.
+func newTypeLimiter(cfg config.WorkerConfig) *typeLimiter {
+	limits := make(map[string]int, len(cfg.Types)+1)
+	for jobType, typeCfg := range cfg.Types {
+		if typeCfg.Concurrency > 0 {
+			limits[jobType] = typeCfg.Concurrency
+		}
+	}
+
+	limits[defaultBucket] = cfg.Concurrency
+	if cfg.Autoscale.Enabled {
+		limits[defaultBucket] = max(cfg.Concurrency, cfg.Autoscale.MaxConcurrency) + cfg.ReservedLane.Workers
+	}
+
+	if cfg.DefaultTypeConcurrency > 0 {
+		limits[defaultBucket] = cfg.DefaultTypeConcurrency
+	}
+
+	return &typeLimiter{limits: limits, running: make(map[string]int)}
+}
+
+// bucket returns the bucket jobType counts against
+func (l *typeLimiter) bucket(jobType string) string {
+	if _, ok := l.limits[jobType]; ok && jobType != defaultBucket {
+		return jobType
+	}
+
+	return defaultBucket
+}
+
+// acquire takes a slot for jobType, reporting false when its bucket is full
+func (l *typeLimiter) acquire(jobType string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket := l.bucket(jobType)
+	if l.running[bucket] >= l.limits[bucket] {
+		return false
+	}
+
+	l.running[bucket]++
+	return true
+}
+
+// release returns the slot taken by acquire
+func (l *typeLimiter) release(jobType string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.running[l.bucket(jobType)]--
+}
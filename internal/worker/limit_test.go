@@ -0,0 +1,128 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// concurrencyTracker records the peak number of jobs running at once, per
+// bucket and in total
+type concurrencyTracker struct {
+	mu      sync.Mutex
+	running map[string]int
+	peak    map[string]int
+	total   int
+	maxAll  int
+}
+
+func newConcurrencyTracker() *concurrencyTracker {
+	return &concurrencyTracker{running: make(map[string]int), peak: make(map[string]int)}
+}
+
+// handler returns a handler that counts itself under bucket while it runs
+func (c *concurrencyTracker) handler(bucket string) Handler {
+	return func(ctx context.Context, _ *models.Job) (json.RawMessage, error) {
+		c.mu.Lock()
+		c.running[bucket]++
+		c.total++
+		c.peak[bucket] = max(c.peak[bucket], c.running[bucket])
+		c.maxAll = max(c.maxAll, c.total)
+		c.mu.Unlock()
+
+		wait(ctx, 10*time.Millisecond)
+
+		c.mu.Lock()
+		c.running[bucket]--
+		c.total--
+		c.mu.Unlock()
+		return nil, nil
+	}
+}
+
+func TestPool_PerTypeConcurrency(t *testing.T) {
+	q := newRecordingQueue()
+	p := New(q, config.WorkerConfig{
+		Concurrency: 6,
+		Types: map[string]config.WorkerTypeConfig{
+			"send_email":   {Concurrency: 3},
+			"render_video": {Concurrency: 1},
+		},
+		DefaultTypeConcurrency: 2,
+	}, logger.NewNop())
+
+	tracker := newConcurrencyTracker()
+	p.Register("send_email", tracker.handler("send_email"))
+	p.Register("render_video", tracker.handler("render_video"))
+	p.Register("webhook", tracker.handler("default"))
+	p.Register("thumbnail", tracker.handler("default"))
+
+	var jobs int
+	for i := 0; i < 8; i++ {
+		for _, jobType := range []string{"send_email", "webhook", "thumbnail"} {
+			enqueue(t, q, jobType)
+			jobs++
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		enqueue(t, q, "render_video")
+		jobs++
+	}
+
+	startPool(t, p)
+
+	require.Eventually(t, func() bool {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		acked := 0
+		for _, s := range q.settled {
+			if s.acked {
+				acked++
+			}
+		}
+
+		return acked == jobs
+	}, 5*time.Second, 10*time.Millisecond)
+
+	q.mu.Lock()
+	for _, s := range q.settled {
+		assert.True(t, s.acked || s.released, "limited jobs are released, never nacked")
+	}
+	q.mu.Unlock()
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	assert.LessOrEqual(t, tracker.peak["send_email"], 3)
+	assert.Equal(t, 1, tracker.peak["render_video"])
+	assert.LessOrEqual(t, tracker.peak["default"], 2)
+	assert.LessOrEqual(t, tracker.maxAll, 6)
+}
+
+func TestTypeLimiter_DefaultBucket(t *testing.T) {
+	l := newTypeLimiter(config.WorkerConfig{
+		Concurrency: 2,
+		Types:       map[string]config.WorkerTypeConfig{"render_video": {Concurrency: 1}},
+	})
+
+	assert.True(t, l.acquire("render_video"))
+	assert.False(t, l.acquire("render_video"))
+
+	// Unlisted types share Concurrency slots when no default is configured
+	assert.True(t, l.acquire("webhook"))
+	assert.True(t, l.acquire("send_email"))
+	assert.False(t, l.acquire("webhook"))
+
+	l.release("send_email")
+	assert.True(t, l.acquire("webhook"))
+}
@@ -0,0 +1,106 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/pkg/errors"
+)
+
+// lockHeldDelay is how long a job whose lock is held waits before it runs
+// again
+const lockHeldDelay = time.Second
+
+// lockKey carries the lock taken by WithLock in the handler's context
+type lockKey struct{}
+
+// LockFromContext returns the lock WithLock holds for the running handler,
+// whose fencing token can guard writes against a holder that outlived its
+// lock
+func LockFromContext(ctx context.Context) (*queue.Lock, bool) {
+	lock, ok := ctx.Value(lockKey{}).(*queue.Lock)
+	return lock, ok
+}
+
+// WithLock runs the handler only while holding the lock on keyFn(job) from
+// locker, so no two workers, in this pool or any other sharing locker,
+// handle jobs with the same key at once. A job whose lock is held is
+// rescheduled shortly without counting a retry. The lock is refreshed to
+// ttl on every heartbeat, or every third of ttl when heartbeats are off,
+// and a lock that cannot be refreshed cancels the handler. An empty key
+// runs the handler without a lock.
+func WithLock(locker queue.Locker, keyFn func(*models.Job) string, ttl time.Duration) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, job *models.Job) (json.RawMessage, error) {
+			key := keyFn(job)
+			if key == "" {
+				return next(ctx, job)
+			}
+
+			lock, err := locker.Acquire(ctx, key, ttl)
+			if errors.HasCode(err, errors.CodeConflict) {
+				return nil, Reschedule(time.Now().Add(lockHeldDelay), fmt.Sprintf("lock %q is held", key))
+			}
+
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to acquire lock %q", key).
+					WithOp("worker.WithLock")
+			}
+
+			// A lock that cannot be released expires with its ttl
+			defer func() { _ = lock.Release(context.WithoutCancel(ctx)) }()
+
+			refresh := func(ctx context.Context) error { return lock.Refresh(ctx, ttl) }
+			runCtx, cancel := context.WithCancelCause(context.WithValue(ctx, lockKey{}, lock))
+			defer cancel(nil)
+
+			if remove, ok := onHeartbeat(ctx, refresh); ok {
+				defer remove()
+			} else {
+				defer refreshLock(runCtx, cancel, refresh, max(ttl/3, time.Millisecond))()
+			}
+
+			return next(runCtx, job)
+		}
+	}
+}
+
+// refreshLock calls refresh every interval until the returned stop is
+// called, canceling the handler when the lock is lost
+func refreshLock(ctx context.Context, cancel context.CancelCauseFunc,
+	refresh func(context.Context) error, interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+
+			case <-ctx.Done():
+				return
+
+			case <-ticker.C:
+			}
+
+			if err := refresh(ctx); errors.HasCode(err, errors.CodeNotFound) {
+				cancel(err)
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-exited
+	}
+}
@@ -0,0 +1,168 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// accountKey locks jobs by the account in their payload
+func accountKey(job *models.Job) string {
+	var payload struct {
+		Account string `json:"account"`
+	}
+
+	_ = json.Unmarshal(job.Payload, &payload)
+	return payload.Account
+}
+
+// enqueueAccount adds an account.sync job for account to q
+func enqueueAccount(t *testing.T, q queue.Queue, account string) *models.Job {
+	t.Helper()
+
+	job := models.NewJob("account.sync", json.RawMessage(`{"account":"`+account+`"}`),
+		models.JobPriorityNormal)
+	require.NoError(t, q.Enqueue(context.Background(), job))
+	return job
+}
+
+// waitAcked waits until n jobs of q were acked
+func waitAcked(t *testing.T, q *recordingQueue, n int, timeout time.Duration) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		acked := 0
+		for _, s := range q.settled {
+			if s.acked {
+				acked++
+			}
+		}
+
+		return acked >= n
+	}, timeout, 5*time.Millisecond)
+}
+
+func TestWithLock_MutualExclusionAcrossPools(t *testing.T) {
+	q := newRecordingQueue()
+	locker := queue.NewMemoryLocker()
+
+	var (
+		running, peak atomic.Int32
+		runs          atomic.Int32
+	)
+	syncAccount := func(context.Context, *models.Job) (json.RawMessage, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+
+		runs.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return nil, nil
+	}
+
+	for range 2 {
+		p := New(q, config.WorkerConfig{Concurrency: 2}, logger.NewNop())
+		p.RegisterWith("account.sync", syncAccount, WithLock(locker, accountKey, time.Second))
+		startPool(t, p)
+	}
+
+	for range 3 {
+		enqueueAccount(t, q, "acct-1")
+	}
+
+	waitAcked(t, q, 3, 5*time.Second)
+	assert.EqualValues(t, 1, peak.Load(), "two workers held the same lock")
+	assert.EqualValues(t, 3, runs.Load())
+}
+
+func TestWithLock_ExpiresAfterCrash(t *testing.T) {
+	q := newRecordingQueue()
+	locker := queue.NewMemoryLocker()
+
+	// A worker that crashed while holding the lock never releases it
+	crashed, err := locker.Acquire(context.Background(), "acct-1", 50*time.Millisecond)
+	require.NoError(t, err)
+
+	tokens := make(chan int64, 1)
+	p := New(q, config.WorkerConfig{Concurrency: 1}, logger.NewNop())
+	p.RegisterWith("account.sync", func(ctx context.Context, _ *models.Job) (json.RawMessage, error) {
+		lock, ok := LockFromContext(ctx)
+		if !ok {
+			return nil, errors.New("no lock in context")
+		}
+
+		tokens <- lock.Token
+		return nil, nil
+	}, WithLock(locker, accountKey, time.Second))
+
+	job := enqueueAccount(t, q, "acct-1")
+	startPool(t, p)
+
+	settled := q.waitSettled(t, 1)
+	assert.Equal(t, job.ID, settled[0].jobID)
+	assert.True(t, settled[0].released, "job waits for the held lock without failing")
+
+	waitAcked(t, q, 1, 3*time.Second)
+	assert.Greater(t, <-tokens, crashed.Token)
+}
+
+func TestWithLock_HeartbeatRefreshesLock(t *testing.T) {
+	const ttl = 40 * time.Millisecond
+
+	q := newRecordingQueue()
+	locker := queue.NewMemoryLocker()
+	p := New(q, config.WorkerConfig{Concurrency: 1, HeartbeatInterval: ttl / 4}, logger.NewNop())
+
+	var (
+		mu        sync.Mutex
+		conflicts []bool
+	)
+	p.RegisterWith("account.sync", func(ctx context.Context, _ *models.Job) (json.RawMessage, error) {
+		for range 3 {
+			if err := wait3x(ctx, ttl/3); err != nil {
+				return nil, err
+			}
+
+			_, err := locker.Acquire(ctx, "acct-1", ttl)
+			mu.Lock()
+			conflicts = append(conflicts, errors.HasCode(err, errors.CodeConflict))
+			mu.Unlock()
+		}
+
+		return nil, nil
+	}, WithLock(locker, accountKey, ttl))
+
+	enqueueAccount(t, q, "acct-1")
+	startPool(t, p)
+
+	waitAcked(t, q, 1, 2*time.Second)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []bool{true, true, true}, conflicts, "lock expired while its handler ran")
+
+	lock, err := locker.Acquire(context.Background(), "acct-1", ttl)
+	require.NoError(t, err, "lock was not released")
+	assert.EqualValues(t, 2, lock.Token)
+}
@@ -0,0 +1,156 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+)
+
+// Middleware wraps a Handler with behavior that runs around it
+type Middleware func(Handler) Handler
+
+// Outcomes reported by Outcome and the built-in middleware
+const (
+	OutcomeSuccess     = "success"
+	OutcomeFailure     = "failure"
+	OutcomeTimeout     = "timeout"
+	OutcomePanic       = "panic"
+	OutcomeRescheduled = "rescheduled"
+)
+
+// JobMetrics records processed jobs. internal/metrics provides a Prometheus
+// implementation.
+type JobMetrics interface {
+	ObserveJob(jobType, outcome string, duration time.Duration)
+}
+
+// chain wraps h with mw so that mw[0] runs first
+func chain(h Handler, mw ...Middleware) Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+
+	return h
+}
+
+// Outcome classifies the error returned by a handler as one of the Outcome
+// constants
+func Outcome(err error) string {
+	if err == nil {
+		return OutcomeSuccess
+	}
+
+	if IsRescheduled(err) {
+		return OutcomeRescheduled
+	}
+
+	var e *errors.Error
+	if stderrors.As(err, &e) && e.Metadata["panic"] == true {
+		return OutcomePanic
+	}
+
+	if errors.IsTimeout(err) || stderrors.Is(err, context.DeadlineExceeded) {
+		return OutcomeTimeout
+	}
+
+	return OutcomeFailure
+}
+
+// Logging logs the start and finish of every job with its duration and
+// outcome. Entries go through log.WithContext, so request-scoped fields
+// carried by ctx are included.
+func Logging(log logger.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, job *models.Job) (json.RawMessage, error) {
+			jobLog := log.WithContext(ctx).With(
+				"job_id", job.ID,
+				"type", job.Type,
+				"attempt", job.RetryCount+1,
+			)
+
+			jobLog.Info("job started")
+			start := time.Now()
+			result, err := next(ctx, job)
+			duration := time.Since(start)
+
+			if err != nil {
+				jobLog.Warn("job finished",
+					"outcome", Outcome(err),
+					"duration", duration,
+					"error", err,
+				)
+
+				return result, err
+			}
+
+			jobLog.Info("job finished", "outcome", OutcomeSuccess, "duration", duration)
+			return result, nil
+		}
+	}
+}
+
+// Recover turns a panic in the handler into an error built by
+// errors.FromPanic, so the job is nacked instead of crashing the worker
+func Recover() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, job *models.Job) (result json.RawMessage, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					result = nil
+					err = errors.FromPanic(r).
+						WithMetadata("type", job.Type).
+						WithOp("worker.Recover")
+				}
+			}()
+
+			return next(ctx, job)
+		}
+	}
+}
+
+// Timeout bounds the handler with job.Timeout, or with d when the job sets
+// none. A handler that fails because the deadline expired reports a
+// CodeTimeout error. Zero durations leave the handler unbounded.
+func Timeout(d time.Duration) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, job *models.Job) (json.RawMessage, error) {
+			timeout := d
+			if job.Timeout > 0 {
+				timeout = job.Timeout
+			}
+
+			if timeout <= 0 {
+				return next(ctx, job)
+			}
+
+			runCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			result, err := next(runCtx, job)
+			if err != nil && stderrors.Is(runCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+				return nil, errors.Wrapf(err, "job timed out after %s", timeout).
+					WithCode(errors.CodeTimeout).
+					WithOp("worker.process")
+			}
+
+			return result, err
+		}
+	}
+}
+
+// Metrics reports every job to m with its type, outcome, and duration
+func Metrics(m JobMetrics) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, job *models.Job) (json.RawMessage, error) {
+			start := time.Now()
+			result, err := next(ctx, job)
+			m.ObserveJob(job.Type, Outcome(err), time.Since(start))
+			return result, err
+		}
+	}
+}
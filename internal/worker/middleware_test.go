@@ -0,0 +1,150 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// trace records the order in which middleware runs
+type trace struct {
+	mu    sync.Mutex
+	steps []string
+}
+
+// middleware returns a Middleware that records name before and after next
+func (tr *trace) middleware(name string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, job *models.Job) (json.RawMessage, error) {
+			tr.add(name)
+			result, err := next(ctx, job)
+			tr.add("/" + name)
+			return result, err
+		}
+	}
+}
+
+func (tr *trace) add(step string) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	tr.steps = append(tr.steps, step)
+}
+
+func (tr *trace) get() []string {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	return append([]string(nil), tr.steps...)
+}
+
+func TestPool_MiddlewareOrder(t *testing.T) {
+	q := newRecordingQueue()
+	p := New(q, config.WorkerConfig{}, logger.NewNop())
+
+	tr := &trace{}
+	p.Use(tr.middleware("first"), tr.middleware("second"))
+	p.RegisterWith("email.send", func(context.Context, *models.Job) (json.RawMessage, error) {
+		tr.add("handler")
+		return nil, nil
+	}, tr.middleware("typed"))
+
+	// Middleware added after registration still applies
+	p.Use(tr.middleware("third"))
+
+	enqueue(t, q, "email.send")
+	startPool(t, p)
+	q.waitSettled(t, 1)
+
+	assert.Equal(t, []string{
+		"first", "second", "third", "typed", "handler",
+		"/typed", "/third", "/second", "/first",
+	}, tr.get())
+}
+
+func TestPool_PerTypeMiddleware(t *testing.T) {
+	q := newRecordingQueue()
+	p := New(q, config.WorkerConfig{}, logger.NewNop())
+
+	tr := &trace{}
+	p.Use(tr.middleware("global"))
+	p.RegisterWith("email.send", func(context.Context, *models.Job) (json.RawMessage, error) {
+		return nil, nil
+	}, tr.middleware("email"))
+	p.Register("report.build", func(context.Context, *models.Job) (json.RawMessage, error) {
+		return nil, nil
+	})
+
+	enqueue(t, q, "report.build")
+	startPool(t, p)
+	q.waitSettled(t, 1)
+	assert.Equal(t, []string{"global", "/global"}, tr.get())
+
+	enqueue(t, q, "email.send")
+	q.waitSettled(t, 2)
+	assert.Equal(t, []string{"global", "/global", "global", "email", "/email", "/global"}, tr.get())
+}
+
+func TestRecover_NacksPanickingJob(t *testing.T) {
+	q := newRecordingQueue()
+	p := New(q, config.WorkerConfig{}, logger.NewNop())
+	p.Use(Recover())
+	p.Register("email.send", func(context.Context, *models.Job) (json.RawMessage, error) {
+		panic("template missing")
+	})
+
+	job := enqueue(t, q, "email.send")
+	startPool(t, p)
+
+	settled := q.waitSettled(t, 1)
+	assert.Equal(t, job.ID, settled[0].jobID)
+	assert.False(t, settled[0].acked)
+	assert.True(t, errors.HasCode(settled[0].err, errors.CodeInternal))
+	assert.Contains(t, settled[0].reason, "panic: template missing")
+	assert.Equal(t, OutcomePanic, Outcome(settled[0].err))
+}
+
+func TestTimeout_PrefersJobTimeout(t *testing.T) {
+	h := Timeout(time.Hour)(func(ctx context.Context, _ *models.Job) (json.RawMessage, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	job := models.NewJob("report.build", nil, models.JobPriorityNormal)
+	job.Timeout = 10 * time.Millisecond
+
+	_, err := h(context.Background(), job)
+	require.Error(t, err)
+	assert.True(t, errors.HasCode(err, errors.CodeTimeout))
+	assert.Contains(t, err.Error(), "timed out after 10ms")
+}
+
+func TestOutcome(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "nil", err: nil, want: OutcomeSuccess},
+		{name: "failure", err: errors.New("smtp unavailable"), want: OutcomeFailure},
+		{name: "timeout code", err: errors.New("slow").WithCode(errors.CodeTimeout), want: OutcomeTimeout},
+		{name: "deadline", err: context.DeadlineExceeded, want: OutcomeTimeout},
+		{name: "panic", err: errors.FromPanic("boom"), want: OutcomePanic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Outcome(tt.err))
+		})
+	}
+}
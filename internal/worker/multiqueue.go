@@ -0,0 +1,478 @@
+package worker
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// Settlement outcomes reported to QueueMetrics
+const (
+	QueueAcked    = "acked"
+	QueueNacked   = "nacked"
+	QueueReleased = "released"
+)
+
+// QueueMetrics records the consumption of each queue of a MultiQueue: the
+// lag of every job dequeued from it, meaning how long the job had been
+// ready, and the settlement of each of its jobs. internal/metrics provides
+// a Prometheus implementation.
+type QueueMetrics interface {
+	ObserveQueueLag(queue string, lag time.Duration)
+	ObserveQueueSettled(queue, outcome string)
+}
+
+// WeightedQueue is a named queue consumed by a MultiQueue with Weight as its
+// share of dequeues. A weight of zero counts as one.
+type WeightedQueue struct {
+	Name   string
+	Queue  queue.Queue
+	Weight int
+}
+
+// MultiQueueOption customizes a MultiQueue
+type MultiQueueOption func(*MultiQueue)
+
+// WithQueueMetrics reports the lag and settlements of each queue to m
+func WithQueueMetrics(m QueueMetrics) MultiQueueOption {
+	return func(q *MultiQueue) {
+		q.metrics = m
+	}
+}
+
+// MultiQueue consumes several queues as one, so a single pool can serve
+// them. Dequeues follow a smooth weighted round-robin over the queues, and a
+// queue with no job ready, because it is empty, paused, or drained, gives
+// its turn to the next one. Every dequeued job is settled on the queue it
+// came from. Enqueued jobs go to the first queue.
+type MultiQueue struct {
+	queues  []WeightedQueue
+	metrics QueueMetrics
+	now     func() time.Time
+
+	mu      sync.Mutex
+	current []int
+	total   int
+	owners  map[uuid.UUID]int
+}
+
+var (
+	_ queue.Queue         = (*MultiQueue)(nil)
+	_ queue.Reprioritizer = (*MultiQueue)(nil)
+)
+
+// NewMultiQueue returns a MultiQueue over queues, which must be non-empty
+// and uniquely named
+func NewMultiQueue(queues []WeightedQueue, opts ...MultiQueueOption) (*MultiQueue, error) {
+	if len(queues) == 0 {
+		return nil, errors.New("multi queue needs at least one queue").
+			WithCode(errors.CodeConfiguration).
+			WithOp("worker.NewMultiQueue")
+	}
+
+	q := &MultiQueue{
+		queues:  slices.Clone(queues),
+		now:     time.Now,
+		current: make([]int, len(queues)),
+		owners:  make(map[uuid.UUID]int),
+	}
+
+	seen := make(map[string]bool, len(queues))
+	for i, wq := range q.queues {
+		switch {
+		case wq.Queue == nil:
+			return nil, errors.Newf("queue %q is nil", wq.Name).
+				WithCode(errors.CodeConfiguration).
+				WithOp("worker.NewMultiQueue")
+
+		case seen[wq.Name]:
+			return nil, errors.Newf("queue %q is listed twice", wq.Name).
+				WithCode(errors.CodeConfiguration).
+				WithOp("worker.NewMultiQueue")
+
+		case wq.Weight < 0:
+			return nil, errors.Newf("queue %q weight must not be negative", wq.Name).
+				WithCode(errors.CodeConfiguration).
+				WithOp("worker.NewMultiQueue")
+
+		case wq.Weight == 0:
+			q.queues[i].Weight = 1
+		}
+
+		seen[wq.Name] = true
+		q.total += q.queues[i].Weight
+	}
+
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	return q, nil
+}
+
+// QueuesFrom looks up the queues of cfg, the worker.queues section, in m
+func QueuesFrom(m *queue.QueueManager, cfg []config.WorkerQueueConfig) ([]WeightedQueue, error) {
+	queues := make([]WeightedQueue, 0, len(cfg))
+	for _, c := range cfg {
+		q, err := m.Get(c.Name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "worker queue %q is not registered", c.Name).
+				WithCode(errors.CodeConfiguration).
+				WithOp("worker.QueuesFrom")
+		}
+
+		queues = append(queues, WeightedQueue{Name: c.Name, Queue: q, Weight: c.Weight})
+	}
+
+	return queues, nil
+}
+
+// order returns the indexes of the queues in the order a dequeue tries
+// them: the smooth weighted round-robin pick first, then the others by
+// their current weight
+func (q *MultiQueue) order() []int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, wq := range q.queues {
+		q.current[i] += wq.Weight
+	}
+
+	order := make([]int, len(q.queues))
+	for i := range order {
+		order[i] = i
+	}
+
+	slices.SortStableFunc(order, func(a, b int) int { return q.current[b] - q.current[a] })
+	q.current[order[0]] -= q.total
+	return order
+}
+
+// dequeue takes the next job with fetch, trying the queues in weighted
+// order until one has a job ready
+func (q *MultiQueue) dequeue(ctx context.Context,
+	fetch func(queue.Queue) (*models.Job, error)) (*models.Job, error) {
+	var errs []error
+	for _, i := range q.order() {
+		job, err := fetch(q.queues[i].Queue)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to dequeue from queue %s", q.queues[i].Name))
+			continue
+		}
+
+		if job == nil {
+			continue
+		}
+
+		q.mu.Lock()
+		q.owners[job.ID] = i
+		q.mu.Unlock()
+
+		if q.metrics != nil {
+			q.metrics.ObserveQueueLag(q.queues[i].Name, max(q.now().Sub(readyAt(job)), 0))
+		}
+
+		return job, nil
+	}
+
+	if err := errors.Join(errs...); err != nil && ctx.Err() == nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// Dequeue retrieves the next job from the queue whose turn it is, or from
+// the next one with a job ready
+func (q *MultiQueue) Dequeue(ctx context.Context) (*models.Job, error) {
+	return q.dequeue(ctx, func(wq queue.Queue) (*models.Job, error) {
+		return wq.Dequeue(ctx)
+	})
+}
+
+// DequeueMinPriority retrieves the next job whose priority is at least min,
+// taking the queues in turn like Dequeue
+func (q *MultiQueue) DequeueMinPriority(ctx context.Context, min models.JobPriority) (*models.Job, error) {
+	return q.dequeue(ctx, func(wq queue.Queue) (*models.Job, error) {
+		return wq.DequeueMinPriority(ctx, min)
+	})
+}
+
+// DequeueBatch retrieves up to limit jobs, one dequeue at a time
+func (q *MultiQueue) DequeueBatch(ctx context.Context, limit int) ([]*models.Job, error) {
+	jobs := make([]*models.Job, 0, limit)
+	for len(jobs) < limit {
+		job, err := q.Dequeue(ctx)
+		if err != nil {
+			return jobs, err
+		}
+
+		if job == nil {
+			break
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// Enqueue adds a job to the first queue
+func (q *MultiQueue) Enqueue(ctx context.Context, job *models.Job) error {
+	return q.queues[0].Queue.Enqueue(ctx, job)
+}
+
+// EnqueueBatch adds jobs to the first queue
+func (q *MultiQueue) EnqueueBatch(ctx context.Context, jobs []*models.Job) error {
+	return q.queues[0].Queue.EnqueueBatch(ctx, jobs)
+}
+
+// owner returns the queue jobID was dequeued from. settled forgets the job,
+// which is back in its queue or gone once settled.
+func (q *MultiQueue) owner(jobID uuid.UUID, op string, settled bool) (WeightedQueue, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i, ok := q.owners[jobID]
+	if !ok {
+		return WeightedQueue{}, errors.NotFound("job %s was not dequeued from any queue", jobID).
+			WithKey("job.not_in_processing", jobID).
+			WithOp(op)
+	}
+
+	if settled {
+		delete(q.owners, jobID)
+	}
+
+	return q.queues[i], nil
+}
+
+// settle runs fn on the queue jobID came from and reports the outcome
+func (q *MultiQueue) settle(jobID uuid.UUID, op, outcome string, fn func(queue.Queue) error) error {
+	wq, err := q.owner(jobID, op, true)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(wq.Queue); err != nil {
+		return err
+	}
+
+	if q.metrics != nil {
+		q.metrics.ObserveQueueSettled(wq.Name, outcome)
+	}
+
+	return nil
+}
+
+// Ack acknowledges a job on the queue it came from
+func (q *MultiQueue) Ack(ctx context.Context, jobID uuid.UUID) error {
+	return q.settle(jobID, "queue.Ack", QueueAcked, func(wq queue.Queue) error {
+		return wq.Ack(ctx, jobID)
+	})
+}
+
+// Nack returns a job to the queue it came from
+func (q *MultiQueue) Nack(ctx context.Context, jobID uuid.UUID, reason string) error {
+	return q.settle(jobID, "queue.Nack", QueueNacked, func(wq queue.Queue) error {
+		return wq.Nack(ctx, jobID, reason)
+	})
+}
+
+// NackError returns a failed job to the queue it came from, which
+// dead-letters it when err is not retryable
+func (q *MultiQueue) NackError(ctx context.Context, jobID uuid.UUID, err error) error {
+	return q.settle(jobID, "queue.NackError", QueueNacked, func(wq queue.Queue) error {
+		return wq.NackError(ctx, jobID, err)
+	})
+}
+
+// NackWithDelay returns a failed job to the queue it came from, retried
+// after delay
+func (q *MultiQueue) NackWithDelay(ctx context.Context, jobID uuid.UUID, reason string,
+	delay time.Duration) error {
+	return q.settle(jobID, "queue.NackWithDelay", QueueNacked, func(wq queue.Queue) error {
+		return wq.NackWithDelay(ctx, jobID, reason, delay)
+	})
+}
+
+// Release returns a job to the queue it came from without counting a retry
+func (q *MultiQueue) Release(ctx context.Context, jobID uuid.UUID, delay time.Duration) error {
+	return q.settle(jobID, "queue.Release", QueueReleased, func(wq queue.Queue) error {
+		return wq.Release(ctx, jobID, delay)
+	})
+}
+
+// Extend extends the visibility timeout of a job on the queue it came from.
+// A job whose lease was lost is forgotten, as its worker no longer settles
+// it.
+func (q *MultiQueue) Extend(ctx context.Context, jobID uuid.UUID, duration time.Duration) error {
+	wq, err := q.owner(jobID, "queue.Extend", false)
+	if err != nil {
+		return err
+	}
+
+	err = wq.Queue.Extend(ctx, jobID, duration)
+	if errors.HasCode(err, errors.CodeNotFound) || errors.HasCode(err, errors.CodeConflict) {
+		q.Abandon(jobID)
+	}
+
+	return err
+}
+
+// Abandon forgets the queue jobID came from, once its worker gave up the
+// job after losing its lease, and tells that queue when it tracks the job
+// too
+func (q *MultiQueue) Abandon(jobID uuid.UUID) {
+	q.mu.Lock()
+	i, ok := q.owners[jobID]
+	delete(q.owners, jobID)
+	q.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	if a, ok := q.queues[i].Queue.(abandoner); ok {
+		a.Abandon(jobID)
+	}
+}
+
+// Delete removes a job from whichever queue holds it
+func (q *MultiQueue) Delete(ctx context.Context, jobID uuid.UUID) error {
+	q.mu.Lock()
+	delete(q.owners, jobID)
+	q.mu.Unlock()
+
+	for _, wq := range q.queues {
+		err := wq.Queue.Delete(ctx, jobID)
+		if !errors.HasCode(err, errors.CodeNotFound) {
+			return err
+		}
+	}
+
+	return errors.NotFound("job %s not found", jobID).
+		WithKey("job.not_found", jobID).
+		WithOp("queue.Delete")
+}
+
+// Size returns the number of jobs in every queue
+func (q *MultiQueue) Size(ctx context.Context) (int64, error) {
+	var size int64
+	for _, wq := range q.queues {
+		n, err := wq.Queue.Size(ctx)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to get size of queue %s", wq.Name)
+		}
+
+		size += n
+	}
+
+	return size, nil
+}
+
+// Clear removes every job from every queue
+func (q *MultiQueue) Clear(ctx context.Context) error {
+	var errs []error
+	for _, wq := range q.queues {
+		if err := wq.Queue.Clear(ctx); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to clear queue %s", wq.Name))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Close closes every queue
+func (q *MultiQueue) Close() error {
+	var errs []error
+	for _, wq := range q.queues {
+		if err := wq.Queue.Close(); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to close queue %s", wq.Name))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Stats adds up the statistics of every queue. OldestAge is the oldest of
+// them, and the queues count as paused only when all of them are.
+func (q *MultiQueue) Stats(ctx context.Context) (*queue.QueueStats, error) {
+	total := &queue.QueueStats{Name: q.queues[0].Name, Paused: true}
+	for _, wq := range q.queues {
+		stats, err := wq.Queue.Stats(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get stats of queue %s", wq.Name)
+		}
+
+		total.Size += stats.Size
+		total.Processing += stats.Processing
+		total.Delayed += stats.Delayed
+		total.Failed += stats.Failed
+		total.DeadLetter += stats.DeadLetter
+		total.OldestAge = max(total.OldestAge, stats.OldestAge)
+		total.EnqueueRate += stats.EnqueueRate
+		total.DequeueRate += stats.DequeueRate
+		total.Paused = total.Paused && stats.Paused
+	}
+
+	return total, nil
+}
+
+// Waiting returns up to limit ready jobs of priority from each queue that
+// can change priorities
+func (q *MultiQueue) Waiting(ctx context.Context, priority models.JobPriority, limit int) ([]*models.Job, error) {
+	var jobs []*models.Job
+	for _, wq := range q.queues {
+		r, ok := wq.Queue.(queue.Reprioritizer)
+		if !ok {
+			continue
+		}
+
+		waiting, err := r.Waiting(ctx, priority, limit)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read waiting jobs of queue %s", wq.Name)
+		}
+
+		jobs = append(jobs, waiting...)
+	}
+
+	return jobs, nil
+}
+
+// UpdatePriority moves a waiting job to priority in whichever queue holds
+// it
+func (q *MultiQueue) UpdatePriority(ctx context.Context, jobID uuid.UUID,
+	priority models.JobPriority) (*models.Job, error) {
+	for _, wq := range q.queues {
+		r, ok := wq.Queue.(queue.Reprioritizer)
+		if !ok {
+			continue
+		}
+
+		job, err := r.UpdatePriority(ctx, jobID, priority)
+		if !errors.HasCode(err, errors.CodeNotFound) {
+			return job, err
+		}
+	}
+
+	return nil, errors.NotFound("job %s is not waiting in any queue", jobID).
+		WithKey("job.not_waiting", jobID).
+		WithOp("queue.UpdatePriority")
+}
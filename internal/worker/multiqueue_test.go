@@ -0,0 +1,280 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// queueRecorder is a QueueMetrics that records what it observes
+type queueRecorder struct {
+	mu      sync.Mutex
+	lagged  map[string]int
+	settled map[string]int
+}
+
+func newQueueRecorder() *queueRecorder {
+	return &queueRecorder{lagged: make(map[string]int), settled: make(map[string]int)}
+}
+
+func (r *queueRecorder) ObserveQueueLag(queue string, _ time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lagged[queue]++
+}
+
+func (r *queueRecorder) ObserveQueueSettled(queue, outcome string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.settled[queue+"/"+outcome]++
+}
+
+// fill enqueues n jobs of jobType on q
+func fill(t *testing.T, q queue.Queue, jobType string, n int) {
+	t.Helper()
+
+	for range n {
+		enqueue(t, q, jobType)
+	}
+}
+
+// twoQueues returns a MultiQueue over a default queue weighted 3 and an
+// emails queue weighted 1
+func twoQueues(t *testing.T, opts ...MultiQueueOption) (*MultiQueue, *queue.MemoryQueue, *queue.MemoryQueue) {
+	t.Helper()
+
+	defaults := queue.NewMemoryQueue(queue.Config{Name: "default"})
+	emails := queue.NewMemoryQueue(queue.Config{Name: "emails"})
+	mq, err := NewMultiQueue([]WeightedQueue{
+		{Name: "default", Queue: defaults, Weight: 3},
+		{Name: "emails", Queue: emails, Weight: 1},
+	}, opts...)
+	require.NoError(t, err)
+
+	return mq, defaults, emails
+}
+
+func TestMultiQueue_DequeuesByWeight(t *testing.T) {
+	ctx := context.Background()
+	mq, defaults, emails := twoQueues(t)
+	fill(t, defaults, "report.build", 400)
+	fill(t, emails, "email.send", 400)
+
+	counts := map[string]int{}
+	for range 400 {
+		job, err := mq.Dequeue(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		counts[job.Type]++
+	}
+
+	assert.InDelta(t, 300, counts["report.build"], 4)
+	assert.InDelta(t, 100, counts["email.send"], 4)
+}
+
+func TestMultiQueue_PausedQueueGivesWayToOthers(t *testing.T) {
+	ctx := context.Background()
+	mq, defaults, emails := twoQueues(t)
+	fill(t, defaults, "report.build", 50)
+	fill(t, emails, "email.send", 50)
+	require.NoError(t, defaults.Pause(ctx))
+
+	for range 40 {
+		job, err := mq.Dequeue(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		assert.Equal(t, "email.send", job.Type)
+	}
+
+	require.NoError(t, defaults.Resume(ctx))
+	counts := map[string]int{}
+	for range 8 {
+		job, err := mq.Dequeue(ctx)
+		require.NoError(t, err)
+		counts[job.Type]++
+	}
+
+	assert.Equal(t, 6, counts["report.build"], "the resumed queue gets its share back")
+}
+
+func TestMultiQueue_SettlesOnOriginQueue(t *testing.T) {
+	ctx := context.Background()
+	metrics := newQueueRecorder()
+	mq, defaults, emails := twoQueues(t, WithQueueMetrics(metrics))
+	report := enqueue(t, defaults, "report.build")
+	email := enqueue(t, emails, "email.send")
+
+	first, err := mq.Dequeue(ctx)
+	require.NoError(t, err)
+	second, err := mq.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []any{report.ID, email.ID}, []any{first.ID, second.ID})
+
+	require.NoError(t, mq.Extend(ctx, email.ID, time.Minute))
+	require.NoError(t, mq.Ack(ctx, email.ID))
+	require.NoError(t, mq.Nack(ctx, report.ID, "boom"))
+
+	err = mq.Ack(ctx, email.ID)
+	assert.True(t, errors.HasCode(err, errors.CodeNotFound), "a settled job is forgotten")
+
+	stats, err := defaults.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Delayed, "the nacked job waits for its retry in its own queue")
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	assert.Equal(t, map[string]int{"default": 1, "emails": 1}, metrics.lagged)
+	assert.Equal(t, map[string]int{"emails/acked": 1, "default/nacked": 1}, metrics.settled)
+}
+
+func TestMultiQueue_ForgetsLostLeases(t *testing.T) {
+	ctx := context.Background()
+	mq, defaults, emails := twoQueues(t)
+	report := enqueue(t, defaults, "report.build")
+	email := enqueue(t, emails, "email.send")
+
+	for range 2 {
+		_, err := mq.Dequeue(ctx)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, defaults.Ack(ctx, report.ID), "another worker settled the job")
+	err := mq.Extend(ctx, report.ID, time.Minute)
+	assert.True(t, errors.HasCode(err, errors.CodeNotFound))
+
+	mq.Abandon(email.ID)
+
+	mq.mu.Lock()
+	defer mq.mu.Unlock()
+	assert.Empty(t, mq.owners, "lost leases are forgotten")
+}
+
+func TestPool_AbandonsLostLease(t *testing.T) {
+	mq, defaults, _ := twoQueues(t)
+	job := enqueue(t, defaults, "report.build")
+
+	started := make(chan struct{})
+	p := New(mq, config.WorkerConfig{Concurrency: 1, HeartbeatInterval: 10 * time.Millisecond},
+		logger.NewNop())
+	p.Register("report.build", func(ctx context.Context, _ *models.Job) (json.RawMessage, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- p.Run(ctx) }()
+
+	<-started
+	require.NoError(t, defaults.Delete(context.Background(), job.ID), "the lease is lost")
+	require.Eventually(t, func() bool {
+		mq.mu.Lock()
+		defer mq.mu.Unlock()
+		return len(mq.owners) == 0
+	}, 2*time.Second, 5*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestMultiQueue_Validation(t *testing.T) {
+	q := queue.NewMemoryQueue(queue.Config{})
+	tests := []struct {
+		name    string
+		queues  []WeightedQueue
+		wantErr string
+	}{
+		{name: "empty", wantErr: "at least one queue"},
+		{name: "nil queue", queues: []WeightedQueue{{Name: "a"}}, wantErr: `queue "a" is nil`},
+		{name: "duplicate", queues: []WeightedQueue{{Name: "a", Queue: q}, {Name: "a", Queue: q}},
+			wantErr: `queue "a" is listed twice`},
+		{name: "negative weight", queues: []WeightedQueue{{Name: "a", Queue: q, Weight: -1}},
+			wantErr: "weight must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMultiQueue(tt.queues)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.True(t, errors.HasCode(err, errors.CodeConfiguration))
+		})
+	}
+}
+
+func TestMultiQueue_ClearAndClose(t *testing.T) {
+	ctx := context.Background()
+	mq, defaults, emails := twoQueues(t)
+	fill(t, defaults, "report", 2)
+	fill(t, emails, "email", 1)
+
+	require.NoError(t, mq.Clear(ctx))
+	size, err := mq.Size(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, size)
+	require.NoError(t, mq.Close())
+}
+
+func TestQueuesFrom(t *testing.T) {
+	m := queue.NewQueueManager()
+	m.Register("default", queue.NewMemoryQueue(queue.Config{}))
+	m.Register("emails", queue.NewMemoryQueue(queue.Config{}))
+
+	queues, err := QueuesFrom(m, []config.WorkerQueueConfig{{Name: "default", Weight: 3}, {Name: "emails"}})
+	require.NoError(t, err)
+	require.Len(t, queues, 2)
+	assert.Equal(t, "default", queues[0].Name)
+	assert.Equal(t, 3, queues[0].Weight)
+
+	_, err = QueuesFrom(m, []config.WorkerQueueConfig{{Name: "sms"}})
+	assert.True(t, errors.HasCode(err, errors.CodeConfiguration))
+	assert.Contains(t, err.Error(), `worker queue "sms" is not registered`)
+}
+
+func TestPool_ConsumesMultiQueueAndDrainsOnShutdown(t *testing.T) {
+	mq, defaults, emails := twoQueues(t)
+	fill(t, defaults, "report.build", 6)
+	fill(t, emails, "email.send", 2)
+
+	var handled atomic.Int32
+	release := make(chan struct{})
+	p := New(mq, config.WorkerConfig{Concurrency: 8}, logger.NewNop())
+	for _, jobType := range []string{"report.build", "email.send"} {
+		p.Register(jobType, func(ctx context.Context, _ *models.Job) (json.RawMessage, error) {
+			handled.Add(1)
+			<-release
+			return nil, nil
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- p.Run(ctx) }()
+
+	require.Eventually(t, func() bool { return handled.Load() == 8 }, 2*time.Second, 5*time.Millisecond)
+	cancel()
+	close(release)
+	require.NoError(t, <-done)
+
+	for _, q := range []*queue.MemoryQueue{defaults, emails} {
+		stats, err := q.Stats(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, stats.Size)
+		assert.Zero(t, stats.Processing, "every in-flight job was settled before Run returned")
+	}
+}
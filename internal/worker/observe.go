@@ -0,0 +1,117 @@
+package worker
+
+import (
+	"time"
+
+	"task-queue/internal/models"
+)
+
+// Nack reason classes reported to PoolMetrics
+const (
+	NackError       = "error"
+	NackTimeout     = "timeout"
+	NackPanic       = "panic"
+	NackPermanent   = "permanent"
+	NackRetryAfter  = "retry_after"
+	NackUnknownType = "unknown_type"
+	NackPersist     = "persist"
+)
+
+// unknownTypeLabel replaces job types without a handler in metric labels,
+// so producers cannot grow label cardinality with arbitrary types
+const unknownTypeLabel = "unknown"
+
+// PoolMetrics records what the pool's dispatch loop sees: jobs in flight,
+// how long jobs waited in the queue, handler panics, failed heartbeats, and
+// nacks by reason class. Job types are only ever those with a registered
+// handler. internal/metrics provides a Prometheus implementation.
+type PoolMetrics interface {
+	AddInFlight(delta int)
+	ObserveWait(jobType string, wait time.Duration)
+	ObservePanic(jobType string)
+	ObserveHeartbeatFailure(jobType string)
+	ObserveNack(reasonClass string)
+}
+
+// WithPoolMetrics reports the pool's dispatch loop to m
+func WithPoolMetrics(m PoolMetrics) Option {
+	return func(p *Pool) {
+		p.poolMetrics = m
+	}
+}
+
+// typeLabel returns jobType for metric labels, or unknownTypeLabel when no
+// handler is registered for it
+func (p *Pool) typeLabel(jobType string) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if _, ok := p.handlers[jobType]; ok {
+		return jobType
+	}
+
+	if _, ok := p.batchers[jobType]; ok {
+		return jobType
+	}
+
+	return unknownTypeLabel
+}
+
+// readyAt returns when job became ready to run: its scheduled time, or its
+// creation when it was not scheduled later
+func readyAt(job *models.Job) time.Time {
+	if job.ScheduledAt != nil && job.ScheduledAt.After(job.CreatedAt) {
+		return *job.ScheduledAt
+	}
+
+	return job.CreatedAt
+}
+
+// observeDequeued records how long job waited in the queue
+func (p *Pool) observeDequeued(job *models.Job) {
+	if p.poolMetrics != nil {
+		p.poolMetrics.ObserveWait(p.typeLabel(job.Type), max(time.Since(readyAt(job)), 0))
+	}
+}
+
+// observeInFlight adds delta to the number of jobs being handled
+func (p *Pool) observeInFlight(delta int) {
+	p.health.inFlight.Add(int64(delta))
+	if p.poolMetrics != nil {
+		p.poolMetrics.AddInFlight(delta)
+	}
+}
+
+// observeOutcome records a handler panic, recovered into err by Recover
+func (p *Pool) observeOutcome(jobType string, err error) {
+	if p.poolMetrics != nil && Outcome(err) == OutcomePanic {
+		p.poolMetrics.ObservePanic(p.typeLabel(jobType))
+	}
+}
+
+// observeHeartbeatFailure records a failed visibility extension
+func (p *Pool) observeHeartbeatFailure(jobType string) {
+	if p.poolMetrics != nil {
+		p.poolMetrics.ObserveHeartbeatFailure(p.typeLabel(jobType))
+	}
+}
+
+// observeNack records a nack of the given reason class
+func (p *Pool) observeNack(reasonClass string) {
+	if p.poolMetrics != nil {
+		p.poolMetrics.ObserveNack(reasonClass)
+	}
+}
+
+// nackClass returns the reason class of a job nacked with err
+func nackClass(err error) string {
+	switch Outcome(err) {
+	case OutcomeTimeout:
+		return NackTimeout
+
+	case OutcomePanic:
+		return NackPanic
+	}
+
+	return NackError
+}
@@ -0,0 +1,400 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/eventbus"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/internal/storage"
+	"task-queue/internal/tracing"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// idleWait is how long a worker waits before polling again after the queue
+// returned no job or failed
+const idleWait = 100 * time.Millisecond
+
+// Handler processes a job and returns its result. A returned error fails
+// the job, which is retried or dead-lettered as errors.IsRetryable decides.
+// Wrapping it with Permanent or RetryAfter, or returning Reschedule,
+// overrides that decision.
+type Handler func(ctx context.Context, job *models.Job) (json.RawMessage, error)
+
+// Pool dequeues jobs and dispatches them to the handlers registered for
+// their types
+type Pool struct {
+	queue      queue.Queue
+	config     config.WorkerConfig
+	logger     logger.Logger
+	visibility time.Duration
+	limiter    *typeLimiter
+	rates      *rateLimiter
+	store      storage.JobStore
+	events     storage.EventStore
+	publisher  eventbus.Publisher
+	workerID   string
+	workers    workerSet
+	reserved   workerSet
+	health     health
+	stats      StatsSource
+	mu         sync.RWMutex
+	handlers   map[string]registration
+	batchers   map[string]*batcher
+	middleware []Middleware
+	tracer     oteltrace.Tracer
+	now        func() time.Time
+
+	livenessThreshold time.Duration
+	scalingMetrics    ScalingMetrics
+	throttleMetrics   ThrottleMetrics
+	poolMetrics       PoolMetrics
+	deadlineMetrics   DeadlineMetrics
+}
+
+// registration is a handler with its per-type middleware
+type registration struct {
+	handler    Handler
+	middleware []Middleware
+}
+
+// Option customizes a Pool
+type Option func(*Pool)
+
+// WithVisibilityTimeout sets how far each heartbeat pushes back a job's
+// visibility timeout. It should match the queue's Config.VisibilityTimeout
+// and defaults to queue.DefaultConfig's.
+func WithVisibilityTimeout(d time.Duration) Option {
+	return func(p *Pool) {
+		p.visibility = d
+	}
+}
+
+// New creates a pool consuming q. Concurrency below one runs a single
+// worker.
+func New(q queue.Queue, cfg config.WorkerConfig, log logger.Logger, opts ...Option) *Pool {
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+
+	if cfg.UnknownTypeAction == "" {
+		cfg.UnknownTypeAction = config.UnknownTypeNack
+	}
+
+	p := &Pool{
+		queue:             q,
+		config:            cfg,
+		logger:            log.Named("worker"),
+		visibility:        queue.DefaultConfig().VisibilityTimeout,
+		limiter:           newTypeLimiter(cfg),
+		rates:             newRateLimiter(cfg),
+		stats:             q,
+		workerID:          defaultWorkerID(),
+		tracer:            tracing.Tracer(nil),
+		now:               time.Now,
+		livenessThreshold: livenessPolls * idleWait,
+		handlers:          make(map[string]registration),
+		batchers:          make(map[string]*batcher),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Register sets the handler for jobType, replacing any earlier one
+func (p *Pool) Register(jobType string, h Handler) {
+	p.RegisterWith(jobType, h)
+}
+
+// RegisterWith sets the handler for jobType wrapped in mw, replacing any
+// earlier one. Per-type middleware runs inside the middleware added with
+// Use, in the order given.
+func (p *Pool) RegisterWith(jobType string, h Handler, mw ...Middleware) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.batchers, jobType)
+	p.handlers[jobType] = registration{handler: h, middleware: mw}
+}
+
+// Use adds middleware that wraps every handler. Middleware runs in the
+// order it was added, the first outermost, and applies to handlers
+// registered before or after the call.
+func (p *Pool) Use(mw ...Middleware) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.middleware = append(p.middleware, mw...)
+}
+
+// handler returns the handler for jobType wrapped in the pool middleware,
+// its per-type middleware, and the process timeout, in that order
+func (p *Pool) handler(jobType string) (Handler, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	reg, ok := p.handlers[jobType]
+	if !ok {
+		return nil, false
+	}
+
+	mw := make([]Middleware, 0, len(p.middleware)+len(reg.middleware)+1)
+	mw = append(mw, p.middleware...)
+	mw = append(mw, reg.middleware...)
+	mw = append(mw, Timeout(p.config.ProcessTimeout))
+	return chain(reg.handler, mw...), true
+}
+
+// Run starts Concurrency workers, or MinConcurrency resized by the
+// autoscaler when it is enabled, along with the reserved lane, and blocks
+// until ctx is canceled and they have finished their current jobs. It fails
+// with a CodeConfiguration error when no handler is registered.
+func (p *Pool) Run(ctx context.Context) error {
+	p.mu.RLock()
+	registered := len(p.handlers) + len(p.batchers)
+	p.mu.RUnlock()
+
+	if registered == 0 {
+		return errors.New("worker pool has no registered handlers").
+			WithCode(errors.CodeConfiguration).
+			WithOp("worker.Run")
+	}
+
+	p.health.started.Store(time.Now().UnixNano())
+	p.health.running.Store(true)
+	defer p.health.running.Store(false)
+
+	initial, reserved := p.laneSizes()
+	p.logger.Info("worker pool started",
+		"concurrency", initial,
+		"reserved", reserved,
+		"autoscale", p.config.Autoscale.Enabled,
+		"handlers", registered,
+	)
+
+	work := func(stop <-chan struct{}) { p.work(ctx, stop, p.queue.Dequeue, idleWait) }
+	p.workers.resize(initial, work)
+	if p.scalingMetrics != nil {
+		p.scalingMetrics.SetConcurrency(initial)
+	}
+
+	workReserved := func(stop <-chan struct{}) {
+		p.work(ctx, stop, p.newReservedWorker().next, laneWait)
+	}
+	p.reserved.resize(reserved, workReserved)
+
+	if p.config.Autoscale.Enabled {
+		p.workers.wg.Add(1)
+		go func() {
+			defer p.workers.wg.Done()
+			p.autoscale(ctx, &p.workers, work)
+		}()
+	}
+
+	if p.config.Deadlines.Escalate && p.config.Deadlines.Enabled() {
+		if q, ok := p.queue.(queue.Reprioritizer); ok {
+			p.workers.wg.Add(1)
+			go func() {
+				defer p.workers.wg.Done()
+				p.escalate(ctx, q)
+			}()
+		} else {
+			p.logger.Warn("queue cannot change job priorities, deadline escalation is off")
+		}
+	}
+
+	<-ctx.Done()
+	p.health.draining.Store(true)
+	defer p.health.draining.Store(false)
+
+	p.workers.resize(0, work)
+	p.reserved.resize(0, workReserved)
+	p.workers.wg.Wait()
+	p.reserved.wg.Wait()
+	p.drainBatches(context.WithoutCancel(ctx))
+	p.logger.Info("worker pool stopped")
+	return nil
+}
+
+// Concurrency returns the number of workers currently running, including
+// the reserved lane, which the autoscaler changes over time
+func (p *Pool) Concurrency() int {
+	return p.workers.size() + p.reserved.size()
+}
+
+// work processes the jobs dequeue returns until ctx is canceled or stop is
+// closed, polling every poll while there are none
+func (p *Pool) work(ctx context.Context, stop <-chan struct{}, dequeue dequeueFunc,
+	poll time.Duration) {
+	for ctx.Err() == nil {
+		select {
+		case <-stop:
+			return
+
+		default:
+		}
+
+		job, err := dequeue(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				p.logger.Warn("failed to dequeue job", "error", err)
+			}
+
+			wait(ctx, poll)
+			continue
+		}
+
+		p.health.polled(job != nil)
+
+		if job == nil {
+			wait(ctx, poll)
+			continue
+		}
+
+		p.process(ctx, job)
+	}
+}
+
+// process runs the handler for job under a heartbeat and settles the job
+// with the queue, once its type's rate limit lets it start. A job of a
+// batch type joins its pending batch instead, and one whose type is at its
+// concurrency limit is released back to the queue. The settlement outlives
+// ctx so a shutdown does not strand a finished job. A job whose lease was
+// lost is left to whoever holds it now. The run and its settlement are
+// traced as a job.process span, a child of the span that enqueued the job.
+func (p *Pool) process(ctx context.Context, job *models.Job) {
+	settleCtx := context.WithoutCancel(ctx)
+	log := p.logger.With("job_id", job.ID, "type", job.Type)
+	p.observeDequeued(job)
+	p.checkDeadline(settleCtx, log, job)
+
+	if !p.throttle(ctx, log, job) {
+		return
+	}
+
+	if b, ok := p.batcher(job.Type); ok {
+		p.batch(ctx, log, b, job)
+		return
+	}
+
+	h, ok := p.handler(job.Type)
+	if !ok {
+		p.rejectUnknown(settleCtx, log, job)
+		return
+	}
+
+	if !p.limiter.acquire(job.Type) {
+		p.releaseLimited(settleCtx, log, job)
+		return
+	}
+	defer p.limiter.release(job.Type)
+
+	var err error
+	ctx, span := p.startProcess(ctx, job)
+	defer func() { tracing.End(span, err) }()
+
+	settleCtx = context.WithoutCancel(ctx)
+	log = log.WithContext(ctx)
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	p.markRunning(settleCtx, log, job)
+	hb := p.startHeartbeat(runCtx, cancel, log, job)
+	p.observeInFlight(1)
+	var result json.RawMessage
+	result, err = h(hb.context(runCtx), job)
+	p.observeInFlight(-1)
+	p.observeOutcome(job.Type, err)
+	if hb.stop() {
+		log.Warn("dropping outcome of job whose lease was lost", "error", err)
+		return
+	}
+
+	if err != nil && ctx.Err() != nil {
+		p.interrupt(settleCtx, log, job, err)
+		return
+	}
+
+	if err != nil {
+		p.fail(settleCtx, log, job, err)
+		return
+	}
+
+	job.Result = result
+	p.complete(settleCtx, log, job)
+}
+
+// complete stores the result of a successful job and acks it
+func (p *Pool) complete(ctx context.Context, log logger.Logger, job *models.Job) {
+	if err := p.markCompleted(ctx, log, job); err != nil {
+		// Nack rather than ack a job whose result could not be stored, so
+		// it runs again instead of being lost
+		log.Error("failed to persist job result, nacking", "error", err)
+		p.observeNack(NackPersist)
+		if nackErr := p.queue.NackError(ctx, job.ID, err); nackErr != nil {
+			log.Error("failed to nack job", "error", nackErr)
+		}
+
+		return
+	}
+
+	if err := p.queue.Ack(ctx, job.ID); err != nil {
+		log.Error("failed to ack job", "error", err)
+		return
+	}
+
+	log.Debug("job completed", "result_bytes", len(job.Result))
+}
+
+// releaseLimited returns a job whose type is at its concurrency limit to the
+// queue without counting a retry, freeing the worker for other types
+func (p *Pool) releaseLimited(ctx context.Context, log logger.Logger, job *models.Job) {
+	log.Debug("job type at its concurrency limit, releasing job", "delay", limitedDelay)
+	if err := p.queue.Release(ctx, job.ID, limitedDelay); err != nil {
+		log.Error("failed to release job", "error", err)
+	}
+}
+
+// rejectUnknown settles a job whose type has no handler, nacking it or
+// dead-lettering it as configured
+func (p *Pool) rejectUnknown(ctx context.Context, log logger.Logger, job *models.Job) {
+	err := errors.Newf("no handler registered for job type %q", job.Type).
+		WithKey("job.unknown_type", job.Type).
+		WithMetadata("type", job.Type).
+		WithOp("worker.process")
+
+	log.Warn("job has an unknown type", "action", p.config.UnknownTypeAction)
+	p.observeNack(NackUnknownType)
+
+	var settleErr error
+	if p.config.UnknownTypeAction == config.UnknownTypeDeadLetter {
+		settleErr = p.queue.NackError(ctx, job.ID, err.WithRetryable(false))
+	} else {
+		settleErr = p.queue.Nack(ctx, job.ID, err.Error())
+	}
+
+	if settleErr != nil {
+		log.Error("failed to settle job with unknown type", "error", settleErr)
+	}
+}
+
+// wait sleeps for d or until ctx is canceled
+func wait(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
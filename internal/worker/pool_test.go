@@ -0,0 +1,287 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// settlement is an Ack or Nack observed by recordingQueue
+type settlement struct {
+	jobID    uuid.UUID
+	acked    bool
+	released bool
+	reason   string
+	err      error
+	delay    time.Duration
+}
+
+// recordingQueue is a MemoryQueue that records how jobs are settled
+type recordingQueue struct {
+	*queue.MemoryQueue
+	mu      sync.Mutex
+	settled []settlement
+}
+
+func newRecordingQueue() *recordingQueue {
+	return &recordingQueue{MemoryQueue: queue.NewMemoryQueue(queue.Config{})}
+}
+
+func (q *recordingQueue) record(s settlement) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.settled = append(q.settled, s)
+}
+
+func (q *recordingQueue) Ack(ctx context.Context, jobID uuid.UUID) error {
+	q.record(settlement{jobID: jobID, acked: true})
+	return q.MemoryQueue.Ack(ctx, jobID)
+}
+
+func (q *recordingQueue) Nack(ctx context.Context, jobID uuid.UUID, reason string) error {
+	q.record(settlement{jobID: jobID, reason: reason})
+	return q.MemoryQueue.Nack(ctx, jobID, reason)
+}
+
+func (q *recordingQueue) NackError(ctx context.Context, jobID uuid.UUID, err error) error {
+	q.record(settlement{jobID: jobID, reason: err.Error(), err: err})
+	return q.MemoryQueue.NackError(ctx, jobID, err)
+}
+
+func (q *recordingQueue) NackWithDelay(ctx context.Context, jobID uuid.UUID, reason string,
+	delay time.Duration) error {
+	q.record(settlement{jobID: jobID, reason: reason, delay: delay})
+	return q.MemoryQueue.NackWithDelay(ctx, jobID, reason, delay)
+}
+
+func (q *recordingQueue) Release(ctx context.Context, jobID uuid.UUID, delay time.Duration) error {
+	q.record(settlement{jobID: jobID, released: true, delay: delay})
+	return q.MemoryQueue.Release(ctx, jobID, delay)
+}
+
+// waitSettled waits for n settlements and returns them
+func (q *recordingQueue) waitSettled(t *testing.T, n int) []settlement {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		return len(q.settled) >= n
+	}, 2*time.Second, 5*time.Millisecond)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]settlement(nil), q.settled...)
+}
+
+// startPool runs p until the test ends
+func startPool(t *testing.T, p *Pool) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- p.Run(ctx) }()
+
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+}
+
+// enqueue adds a job of jobType to q
+func enqueue(t *testing.T, q queue.Queue, jobType string) *models.Job {
+	t.Helper()
+
+	job := models.NewJob(jobType, json.RawMessage(`{"to":"a@example.com"}`), models.JobPriorityNormal)
+	require.NoError(t, q.Enqueue(context.Background(), job))
+	return job
+}
+
+// stats returns the queue statistics
+func stats(t *testing.T, q queue.Queue) *queue.QueueStats {
+	t.Helper()
+
+	s, err := q.Stats(context.Background())
+	require.NoError(t, err)
+	return s
+}
+
+func TestPool_Success(t *testing.T) {
+	q := newRecordingQueue()
+	p := New(q, config.WorkerConfig{Concurrency: 2}, logger.NewNop())
+
+	var handled sync.Map
+	p.Register("email.send", func(ctx context.Context, job *models.Job) (json.RawMessage, error) {
+		handled.Store(job.ID, true)
+		return json.RawMessage(`{"sent":true}`), nil
+	})
+
+	first, second := enqueue(t, q, "email.send"), enqueue(t, q, "email.send")
+	startPool(t, p)
+
+	settled := q.waitSettled(t, 2)
+	for _, s := range settled {
+		assert.True(t, s.acked)
+	}
+
+	for _, job := range []*models.Job{first, second} {
+		_, ok := handled.Load(job.ID)
+		assert.True(t, ok)
+	}
+
+	s := stats(t, q)
+	assert.Zero(t, s.Size)
+	assert.Zero(t, s.Processing)
+}
+
+func TestPool_Failure(t *testing.T) {
+	q := newRecordingQueue()
+	p := New(q, config.WorkerConfig{}, logger.NewNop())
+	p.Register("email.send", func(context.Context, *models.Job) (json.RawMessage, error) {
+		return nil, errors.New("smtp unavailable").WithCode(errors.CodeNetwork)
+	})
+
+	job := enqueue(t, q, "email.send")
+	startPool(t, p)
+
+	settled := q.waitSettled(t, 1)
+	assert.Equal(t, job.ID, settled[0].jobID)
+	assert.False(t, settled[0].acked)
+	assert.Equal(t, "smtp unavailable", settled[0].reason)
+
+	// A retryable failure is rescheduled rather than dead-lettered
+	s := stats(t, q)
+	assert.EqualValues(t, 1, s.Delayed)
+	assert.Zero(t, s.DeadLetter)
+}
+
+func TestPool_Timeout(t *testing.T) {
+	q := newRecordingQueue()
+	p := New(q, config.WorkerConfig{ProcessTimeout: 20 * time.Millisecond}, logger.NewNop())
+	p.Register("report.build", func(ctx context.Context, _ *models.Job) (json.RawMessage, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	enqueue(t, q, "report.build")
+	startPool(t, p)
+
+	settled := q.waitSettled(t, 1)
+	assert.False(t, settled[0].acked)
+	assert.True(t, errors.HasCode(settled[0].err, errors.CodeTimeout))
+	assert.Contains(t, settled[0].reason, "timed out after 20ms")
+	assert.EqualValues(t, 1, stats(t, q).Delayed)
+}
+
+func TestPool_UnknownType(t *testing.T) {
+	tests := []struct {
+		name           string
+		action         string
+		wantDelayed    int64
+		wantDeadLetter int64
+	}{
+		{name: "nack by default", action: "", wantDelayed: 1},
+		{name: "nack", action: config.UnknownTypeNack, wantDelayed: 1},
+		{name: "dead letter", action: config.UnknownTypeDeadLetter, wantDeadLetter: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newRecordingQueue()
+			p := New(q, config.WorkerConfig{UnknownTypeAction: tt.action}, logger.NewNop())
+			p.Register("email.send", func(context.Context, *models.Job) (json.RawMessage, error) {
+				return nil, nil
+			})
+
+			enqueue(t, q, "video.render")
+			startPool(t, p)
+
+			settled := q.waitSettled(t, 1)
+			assert.Contains(t, settled[0].reason, `no handler registered for job type "video.render"`)
+
+			s := stats(t, q)
+			assert.Equal(t, tt.wantDelayed, s.Delayed)
+			assert.Equal(t, tt.wantDeadLetter, s.DeadLetter)
+		})
+	}
+}
+
+func TestPool_RunWithoutHandlers(t *testing.T) {
+	p := New(newRecordingQueue(), config.WorkerConfig{}, logger.NewNop())
+
+	err := p.Run(context.Background())
+	require.Error(t, err)
+	assert.True(t, errors.HasCode(err, errors.CodeConfiguration))
+}
+
+func TestPool_StopsOnCancel(t *testing.T) {
+	q := newRecordingQueue()
+	p := New(q, config.WorkerConfig{Concurrency: 4}, logger.NewNop())
+	p.Register("email.send", func(context.Context, *models.Job) (json.RawMessage, error) {
+		return nil, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- p.Run(ctx) }()
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
+
+func TestPool_ShutdownReleasesJobsInFlight(t *testing.T) {
+	q := newRecordingQueue()
+	p := New(q, config.WorkerConfig{}, logger.NewNop())
+
+	started := make(chan struct{})
+	p.Register("report.build", func(ctx context.Context, _ *models.Job) (json.RawMessage, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	job := models.NewJob("report.build", json.RawMessage(`{}`), models.JobPriorityNormal)
+	job.MaxRetries = 3
+	require.NoError(t, q.Enqueue(context.Background(), job))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- p.Run(ctx) }()
+
+	<-started
+	cancel()
+	require.NoError(t, <-done)
+
+	settled := q.waitSettled(t, 1)
+	assert.Equal(t, job.ID, settled[0].jobID)
+	assert.True(t, settled[0].released, "the interrupted job is handed back, not failed")
+
+	s := stats(t, q)
+	assert.Zero(t, s.DeadLetter)
+	assert.Zero(t, s.Processing)
+	assert.EqualValues(t, 1, s.Size)
+
+	got, err := q.Dequeue(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Zero(t, got.RetryCount, "the shutdown does not count as a retry")
+}
@@ -0,0 +1,170 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/pkg/logger"
+
+	"golang.org/x/time/rate"
+)
+
+// Throttle actions reported to ThrottleMetrics
+const (
+	ThrottleWaited   = "waited"
+	ThrottleRequeued = "requeued"
+)
+
+// ThrottleMetrics counts jobs held back by their type's rate limit.
+// internal/metrics provides a Prometheus implementation.
+type ThrottleMetrics interface {
+	ObserveThrottle(jobType, action string)
+}
+
+// WithThrottleMetrics reports every throttled job to m
+func WithThrottleMetrics(m ThrottleMetrics) Option {
+	return func(p *Pool) {
+		p.throttleMetrics = m
+	}
+}
+
+// rateLimiter holds a token bucket for each rate-limited job type
+type rateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*rate.Limiter
+	policy  string
+	maxWait time.Duration
+	now     func() time.Time
+}
+
+// newRateLimiter builds the per-type rate limits of cfg
+func newRateLimiter(cfg config.WorkerConfig) *rateLimiter {
+	l := &rateLimiter{buckets: make(map[string]*rate.Limiter), now: time.Now}
+	l.update(cfg)
+	return l
+}
+
+// update applies the rate limits and throttle policy of cfg. Buckets of
+// types that stay limited keep their tokens, so a reload does not grant a
+// fresh burst.
+func (l *rateLimiter) update(cfg config.WorkerConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.policy, l.maxWait = cfg.ThrottlePolicy, cfg.ThrottleMaxWait
+	if l.policy == "" {
+		l.policy = config.ThrottleWait
+	}
+
+	now := l.now()
+	buckets := make(map[string]*rate.Limiter, len(cfg.Types))
+	for jobType, typeCfg := range cfg.Types {
+		if typeCfg.RateLimit <= 0 {
+			continue
+		}
+
+		limit, burst := rate.Limit(typeCfg.RateLimit), max(typeCfg.RateBurst, 1)
+		b, ok := l.buckets[jobType]
+		if !ok {
+			b = rate.NewLimiter(limit, burst)
+		}
+
+		b.SetLimitAt(now, limit)
+		b.SetBurstAt(now, burst)
+		buckets[jobType] = b
+	}
+
+	l.buckets = buckets
+}
+
+// reserve takes a token for jobType. It reports how long to wait for it,
+// or false with the time until the next token when the job should be
+// requeued instead, taking nothing.
+func (l *rateLimiter) reserve(jobType string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[jobType]
+	if !ok {
+		return 0, true
+	}
+
+	maxWait := l.maxWait
+	if l.policy == config.ThrottleRequeue {
+		maxWait = 0
+	}
+
+	now := l.now()
+	r := b.ReserveN(now, 1)
+	delay := r.DelayFrom(now)
+	if delay > maxWait {
+		r.CancelAt(now)
+		return delay, false
+	}
+
+	return delay, true
+}
+
+// throttle holds job until its type's rate limit lets it start, reporting
+// whether it may run. A job that would wait longer than the policy allows
+// is released back to the queue until its token is due, without counting
+// a retry. A heartbeat extends the job's lease while it waits; a job whose
+// lease is lost meanwhile is left to whoever holds it now.
+func (p *Pool) throttle(ctx context.Context, log logger.Logger, job *models.Job) bool {
+	delay, ok := p.rates.reserve(job.Type)
+	if delay <= 0 {
+		return true
+	}
+
+	if !ok {
+		log.Debug("job type over its rate limit, releasing job", "delay", delay)
+		p.observeThrottle(job.Type, ThrottleRequeued)
+		p.releaseThrottled(ctx, log, job, delay)
+		return false
+	}
+
+	p.observeThrottle(job.Type, ThrottleWaited)
+	waitCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	hb := p.startHeartbeat(waitCtx, cancel, log, job)
+	wait(waitCtx, delay)
+	if hb.stop() {
+		log.Warn("job lease lost while throttled")
+		return false
+	}
+
+	if ctx.Err() != nil {
+		// Shutting down: hand the job back rather than start it canceled
+		p.releaseThrottled(ctx, log, job, 0)
+		return false
+	}
+
+	return true
+}
+
+// releaseThrottled returns a throttled job to the queue, ready after delay
+func (p *Pool) releaseThrottled(ctx context.Context, log logger.Logger, job *models.Job,
+	delay time.Duration) {
+	if err := p.queue.Release(context.WithoutCancel(ctx), job.ID, delay); err != nil {
+		log.Error("failed to release job", "error", err)
+	}
+}
+
+// observeThrottle reports a throttled job when throttle metrics are set
+func (p *Pool) observeThrottle(jobType, action string) {
+	if p.throttleMetrics != nil {
+		p.throttleMetrics.ObserveThrottle(jobType, action)
+	}
+}
+
+// Reload applies the settings of cfg that can change while the pool runs:
+// the per-type rate limits and the throttle policy. Pass it reloaded
+// configurations from config.Watch. Other settings take effect on restart.
+func (p *Pool) Reload(cfg config.WorkerConfig) {
+	p.rates.update(cfg)
+	p.logger.Info("worker rate limits reloaded", "throttle_policy", cfg.ThrottlePolicy)
+}
@@ -0,0 +1,216 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// throttleRecorder is a ThrottleMetrics that records what it observes
+type throttleRecorder struct {
+	mu      sync.Mutex
+	actions []string
+}
+
+func (r *throttleRecorder) ObserveThrottle(_, action string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.actions = append(r.actions, action)
+}
+
+func TestRateLimiter_Reserve(t *testing.T) {
+	now := time.Now()
+	cfg := config.WorkerConfig{
+		ThrottleMaxWait: time.Second,
+		Types: map[string]config.WorkerTypeConfig{
+			"sms.send": {RateLimit: 2, RateBurst: 2},
+		},
+	}
+
+	l := &rateLimiter{now: func() time.Time { return now }}
+	l.update(cfg)
+
+	// The burst is available right away
+	for range 2 {
+		delay, ok := l.reserve("sms.send")
+		assert.True(t, ok)
+		assert.Zero(t, delay)
+	}
+
+	// Later callers wait their turn, up to ThrottleMaxWait
+	delay, ok := l.reserve("sms.send")
+	assert.True(t, ok)
+	assert.Equal(t, 500*time.Millisecond, delay)
+
+	delay, ok = l.reserve("sms.send")
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, delay)
+
+	delay, ok = l.reserve("sms.send")
+	assert.False(t, ok)
+	assert.Equal(t, 1500*time.Millisecond, delay)
+
+	// Unlimited types never wait
+	delay, ok = l.reserve("email.send")
+	assert.True(t, ok)
+	assert.Zero(t, delay)
+
+	// A reload keeps the debt but applies the new rate to it
+	cfg.Types["sms.send"] = config.WorkerTypeConfig{RateLimit: 20, RateBurst: 2}
+	l.update(cfg)
+	now = now.Add(100 * time.Millisecond)
+
+	delay, ok = l.reserve("sms.send")
+	assert.True(t, ok)
+	assert.Equal(t, 50*time.Millisecond, delay)
+
+	// The requeue policy never waits
+	cfg.ThrottlePolicy = config.ThrottleRequeue
+	l.update(cfg)
+
+	delay, ok = l.reserve("sms.send")
+	assert.False(t, ok)
+	assert.Equal(t, 100*time.Millisecond, delay)
+}
+
+func TestPool_RateLimitHoldsInvocationRate(t *testing.T) {
+	const (
+		rate = 40.0
+		jobs = 30
+	)
+
+	q := newRecordingQueue()
+	p := New(q, config.WorkerConfig{
+		Concurrency:     4,
+		ThrottleMaxWait: time.Second,
+		Types: map[string]config.WorkerTypeConfig{
+			"sms.send": {RateLimit: rate},
+		},
+	}, logger.NewNop())
+
+	var (
+		mu      sync.Mutex
+		started []time.Time
+	)
+	p.Register("sms.send", func(context.Context, *models.Job) (json.RawMessage, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		started = append(started, time.Now())
+		return nil, nil
+	})
+
+	for range jobs {
+		enqueue(t, q, "sms.send")
+	}
+	startPool(t, p)
+
+	for _, s := range q.waitSettled(t, jobs) {
+		assert.True(t, s.acked)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Len(t, started, jobs)
+	first, last := started[0], started[0]
+	for _, at := range started {
+		first, last = minTime(first, at), maxTime(last, at)
+	}
+
+	observed := float64(jobs-1) / last.Sub(first).Seconds()
+	assert.InEpsilon(t, rate, observed, 0.1)
+}
+
+func TestPool_RateLimitRequeuePolicy(t *testing.T) {
+	q := newRecordingQueue()
+	metrics := &throttleRecorder{}
+	p := New(q, config.WorkerConfig{
+		Concurrency:    1,
+		ThrottlePolicy: config.ThrottleRequeue,
+		Types: map[string]config.WorkerTypeConfig{
+			"sms.send": {RateLimit: 1},
+		},
+	}, logger.NewNop(), WithThrottleMetrics(metrics))
+
+	p.Register("sms.send", func(context.Context, *models.Job) (json.RawMessage, error) {
+		return nil, nil
+	})
+
+	enqueue(t, q, "sms.send")
+	enqueue(t, q, "sms.send")
+	startPool(t, p)
+
+	settled := q.waitSettled(t, 2)
+	assert.True(t, settled[0].acked)
+	assert.True(t, settled[1].released)
+	assert.Greater(t, settled[1].delay, 900*time.Millisecond)
+	assert.LessOrEqual(t, settled[1].delay, time.Second)
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	assert.Equal(t, []string{ThrottleRequeued}, metrics.actions)
+}
+
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+
+	return a
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+
+	return a
+}
+
+func TestPool_ThrottleKeepsLease(t *testing.T) {
+	const visibility = 50 * time.Millisecond
+
+	q := &recordingQueue{MemoryQueue: queue.NewMemoryQueue(queue.Config{VisibilityTimeout: visibility})}
+	p := New(q, config.WorkerConfig{
+		Concurrency:       2,
+		HeartbeatInterval: visibility / 4,
+		ThrottleMaxWait:   time.Second,
+		Types: map[string]config.WorkerTypeConfig{
+			"sms.send": {RateLimit: 5},
+		},
+	}, logger.NewNop(), WithVisibilityTimeout(visibility))
+
+	var calls atomic.Int32
+	p.Register("sms.send", func(context.Context, *models.Job) (json.RawMessage, error) {
+		calls.Add(1)
+		return nil, nil
+	})
+
+	enqueue(t, q, "sms.send")
+	enqueue(t, q, "sms.send")
+	startPool(t, p)
+
+	// The second job waits 200ms for its token, four visibility timeouts
+	for _, s := range q.waitSettled(t, 2) {
+		assert.True(t, s.acked)
+	}
+
+	assert.EqualValues(t, 2, calls.Load())
+
+	stats, err := q.Stats(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, stats.Processing+stats.Size, "a throttled job was redelivered while it waited")
+}
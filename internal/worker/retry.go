@@ -0,0 +1,119 @@
+package worker
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+)
+
+// permanentError is a handler failure that must not be retried
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// retryAfterError is a handler failure to retry after an explicit delay
+type retryAfterError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *retryAfterError) Error() string { return e.err.Error() }
+func (e *retryAfterError) Unwrap() error { return e.err }
+
+// rescheduleError asks for a job to run again later without failing it
+type rescheduleError struct {
+	at     time.Time
+	reason string
+}
+
+func (e *rescheduleError) Error() string {
+	return fmt.Sprintf("rescheduled to %s: %s", e.at.Format(time.RFC3339), e.reason)
+}
+
+// Permanent fails the job with err and dead-letters it right away, however
+// many retries it has left and whatever errors.IsRetryable says about err
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// RetryAfter fails the job with err and retries it after exactly d instead
+// of the queue's backoff, even when errors.IsRetryable classifies err as
+// permanent. The retry still counts against MaxRetries.
+func RetryAfter(d time.Duration, err error) error {
+	return &retryAfterError{err: err, delay: d}
+}
+
+// Reschedule returns the job to the queue untouched, ready again at t. It
+// does not count as a failure or a retry.
+func Reschedule(t time.Time, reason string) error {
+	return &rescheduleError{at: t, reason: reason}
+}
+
+// IsRescheduled reports whether err asks for the job to be rescheduled
+func IsRescheduled(err error) bool {
+	var reschedule *rescheduleError
+	return stderrors.As(err, &reschedule)
+}
+
+// fail settles a job whose handler returned err as the handler asked:
+// Permanent dead-letters it, RetryAfter retries it after its delay,
+// Reschedule puts it back without a retry, and any other error goes through
+// NackError. When several are nested, Permanent wins over RetryAfter, which
+// wins over Reschedule.
+func (p *Pool) fail(ctx context.Context, log logger.Logger, job *models.Job, err error) {
+	var (
+		permanent  *permanentError
+		retryAfter *retryAfterError
+		reschedule *rescheduleError
+		settleErr  error
+	)
+
+	switch {
+	case stderrors.As(err, &permanent):
+		log.Warn("job failed permanently", "error", err)
+		final := errors.Wrap(permanent.err, "permanent failure").WithRetryable(false)
+		p.markFailed(ctx, log, job, final.Error(), false, true)
+		p.observeNack(NackPermanent)
+		settleErr = p.queue.NackError(ctx, job.ID, final)
+
+	case stderrors.As(err, &retryAfter):
+		log.Warn("job failed, retrying after delay", "error", err, "delay", retryAfter.delay)
+		p.markFailed(ctx, log, job, err.Error(), true, true)
+		p.observeNack(NackRetryAfter)
+		settleErr = p.queue.NackWithDelay(ctx, job.ID, err.Error(), retryAfter.delay)
+
+	case stderrors.As(err, &reschedule):
+		log.Info("job rescheduled", "scheduled_at", reschedule.at, "reason", reschedule.reason)
+		p.markFailed(ctx, log, job, err.Error(), true, false)
+		settleErr = p.queue.Release(ctx, job.ID, max(time.Until(reschedule.at), 0))
+
+	default:
+		log.Warn("job failed", "error", err)
+		p.markFailed(ctx, log, job, err.Error(), failureRetried(err), true)
+		p.observeNack(nackClass(err))
+		settleErr = p.queue.NackError(ctx, job.ID, err)
+	}
+
+	if settleErr != nil {
+		log.Error("failed to settle failed job", "error", settleErr)
+	}
+}
+
+// interrupt returns a job whose handler failed while the pool shut down to
+// the queue without counting a retry. The handler's context was canceled by
+// the shutdown, so the failure says nothing about the job.
+func (p *Pool) interrupt(ctx context.Context, log logger.Logger, job *models.Job, err error) {
+	log.Info("job interrupted by shutdown, releasing it", "error", err)
+	p.markFailed(ctx, log, job, err.Error(), true, false)
+	if releaseErr := p.queue.Release(ctx, job.ID, 0); releaseErr != nil {
+		log.Error("failed to release interrupted job", "error", releaseErr)
+	}
+}
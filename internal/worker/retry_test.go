@@ -0,0 +1,126 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"task-queue/internal/config"
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runOnce registers h for "report.build", processes one job prepared by
+// setup, and returns its settlement
+func runOnce(t *testing.T, setup func(*models.Job), h Handler) (*recordingQueue, settlement) {
+	t.Helper()
+
+	q := newRecordingQueue()
+	p := New(q, config.WorkerConfig{}, logger.NewNop())
+	p.Register("report.build", h)
+
+	job := models.NewJob("report.build", json.RawMessage(`{}`), models.JobPriorityNormal)
+	if setup != nil {
+		setup(job)
+	}
+
+	require.NoError(t, q.Enqueue(context.Background(), job))
+	startPool(t, p)
+
+	settled := q.waitSettled(t, 1)
+	require.Equal(t, job.ID, settled[0].jobID)
+	return q, settled[0]
+}
+
+func TestPermanent(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "retryable code", err: errors.New("smtp unavailable").WithCode(errors.CodeNetwork)},
+		{name: "explicitly retryable", err: errors.New("try again").WithRetryable(true)},
+		{name: "plain error", err: errors.New("template missing")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, s := runOnce(t, func(job *models.Job) { job.MaxRetries = 5 },
+				func(context.Context, *models.Job) (json.RawMessage, error) {
+					return nil, Permanent(tt.err)
+				})
+
+			assert.Equal(t, "permanent failure: "+tt.err.Error(), s.reason)
+			retryable, known := errors.IsRetryable(s.err)
+			assert.True(t, known)
+			assert.False(t, retryable)
+
+			st := stats(t, q)
+			assert.EqualValues(t, 1, st.DeadLetter, "dead-lettered with retries left")
+			assert.Zero(t, st.Delayed)
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		retryCount     int
+		wantDelayed    int64
+		wantDeadLetter int64
+	}{
+		{name: "retryable code", err: errors.New("rate limited").WithCode(errors.CodeRateLimit), wantDelayed: 1},
+		{name: "non-retryable code is still retried", err: errors.Validation("bad input"), wantDelayed: 1},
+		{name: "exhausted retries dead-letter", err: errors.New("rate limited"), retryCount: 2, wantDeadLetter: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, s := runOnce(t, func(job *models.Job) {
+				job.MaxRetries = 3
+				job.RetryCount = tt.retryCount
+			}, func(context.Context, *models.Job) (json.RawMessage, error) {
+				return nil, RetryAfter(10*time.Minute, tt.err)
+			})
+
+			assert.False(t, s.acked)
+			assert.Equal(t, 10*time.Minute, s.delay)
+			assert.Equal(t, tt.err.Error(), s.reason)
+
+			st := stats(t, q)
+			assert.Equal(t, tt.wantDelayed, st.Delayed)
+			assert.Equal(t, tt.wantDeadLetter, st.DeadLetter)
+		})
+	}
+}
+
+func TestReschedule(t *testing.T) {
+	at := time.Now().Add(time.Hour)
+	q, s := runOnce(t, func(job *models.Job) {
+		// A failure here would exhaust the job's retries
+		job.MaxRetries = 1
+	}, func(context.Context, *models.Job) (json.RawMessage, error) {
+		return nil, Reschedule(at, "upstream maintenance window")
+	})
+
+	assert.True(t, s.released)
+	assert.InDelta(t, time.Hour, s.delay, float64(time.Minute))
+
+	st := stats(t, q)
+	assert.EqualValues(t, 1, st.Delayed)
+	assert.Zero(t, st.DeadLetter)
+}
+
+func TestRetryDecisions_SurviveWrapping(t *testing.T) {
+	_, s := runOnce(t, nil, func(context.Context, *models.Job) (json.RawMessage, error) {
+		return nil, errors.Wrap(Permanent(errors.New("corrupt payload")), "decode report")
+	})
+
+	assert.Contains(t, s.reason, "permanent failure")
+	assert.Equal(t, OutcomeRescheduled, Outcome(Reschedule(time.Now(), "later")))
+}
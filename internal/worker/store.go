@@ -0,0 +1,193 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"task-queue/internal/eventbus"
+	"task-queue/internal/models"
+	"task-queue/internal/storage"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+	"task-queue/pkg/retry"
+)
+
+// WithStore persists each job's processing state to store: running with
+// started_at and worker_id when a worker picks it up, then its outcome
+// before the job is settled with the queue
+func WithStore(store storage.JobStore) Option {
+	return func(p *Pool) {
+		p.store = store
+	}
+}
+
+// WithEventStore records a job event when a job starts, completes, or
+// fails
+func WithEventStore(events storage.EventStore) Option {
+	return func(p *Pool) {
+		p.events = events
+	}
+}
+
+// WithPublisher publishes each job's status changes to p: started,
+// completed, failed, and dead-lettered
+func WithPublisher(pub eventbus.Publisher) Option {
+	return func(p *Pool) {
+		p.publisher = pub
+	}
+}
+
+// WithWorkerID sets the ID written to the worker_id of processed jobs. It
+// defaults to the host name and process ID.
+func WithWorkerID(id string) Option {
+	return func(p *Pool) {
+		p.workerID = id
+	}
+}
+
+// defaultWorkerID identifies this process as hostname-pid
+func defaultWorkerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "worker"
+	}
+
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// markRunning records that job started on this worker. A failed write is
+// logged and does not stop the job.
+func (p *Pool) markRunning(ctx context.Context, log logger.Logger, job *models.Job) {
+	now := time.Now().UTC()
+	job.Status = models.JobStatusRunning
+	job.StartedAt = &now
+	job.WorkerID = &p.workerID
+
+	if err := p.saveState(ctx, job); err != nil {
+		log.Warn("failed to mark job running", "error", err)
+	}
+
+	p.recordEvent(ctx, log, job, logger.AuditJobStarted, nil)
+	p.publish(ctx, log, logger.AuditJobStarted, job)
+}
+
+// markCompleted records the result of a successful job. The write must
+// succeed before the job is acked, so its failure is returned as a
+// retryable error. A job without a stored row is only logged, since
+// retrying it would never succeed.
+func (p *Pool) markCompleted(ctx context.Context, log logger.Logger, job *models.Job) error {
+	state := *job
+	now := time.Now().UTC()
+	state.Status = models.JobStatusCompleted
+	state.CompletedAt = &now
+	state.Error = nil
+
+	err := p.saveState(ctx, &state)
+	switch {
+	case errors.HasCode(err, errors.CodeNotFound):
+		log.Warn("job has no stored row, result not persisted", "error", err)
+
+	case err != nil:
+		return errors.Wrap(err, "failed to persist job result").
+			WithRetryable(true).
+			WithOp("worker.process")
+	}
+
+	p.recordEvent(ctx, log, job, logger.AuditJobCompleted, map[string]any{
+		"result_bytes": len(job.Result),
+	})
+	p.publish(ctx, log, logger.AuditJobCompleted, &state)
+
+	return nil
+}
+
+// markFailed records the outcome of a failed job as the queue will settle
+// it: retry is whether the queue retries it, and counted whether the
+// failure uses up a retry. The queue is settled whether or not this
+// succeeds, so a failed write is only logged.
+func (p *Pool) markFailed(ctx context.Context, log logger.Logger, job *models.Job,
+	reason string, retry, counted bool) {
+	state := *job
+	state.Error = &reason
+	if counted {
+		state.RetryCount++
+	}
+
+	switch {
+	case !counted:
+		state.Status = models.JobStatusPending
+
+	case !retry || state.RetryCount >= state.MaxRetries:
+		now := time.Now().UTC()
+		state.Status = models.JobStatusDead
+		state.CompletedAt = &now
+
+	default:
+		state.Status = models.JobStatusRetrying
+	}
+
+	if err := p.saveState(ctx, &state); err != nil {
+		log.Error("failed to persist job failure", "error", err)
+	}
+
+	p.recordEvent(ctx, log, job, logger.AuditJobFailed, map[string]any{
+		"status":      state.Status,
+		"retry_count": state.RetryCount,
+		"error":       reason,
+	})
+
+	event := logger.AuditJobFailed
+	if state.Status == models.JobStatusDead {
+		event = logger.AuditJobDeadLettered
+	}
+
+	p.publish(ctx, log, event, &state)
+}
+
+// saveState writes job's processing state to the store, with a few quick
+// retries. It does nothing without a store.
+func (p *Pool) saveState(ctx context.Context, job *models.Job) error {
+	if p.store == nil {
+		return nil
+	}
+
+	return retry.Quick().DoContext(ctx, func(ctx context.Context) error {
+		return p.store.UpdateStatus(ctx, job)
+	})
+}
+
+// recordEvent appends an event to job's audit trail when an event store is
+// configured. A failed write is logged.
+func (p *Pool) recordEvent(ctx context.Context, log logger.Logger, job *models.Job,
+	eventType string, data map[string]any) {
+	if p.events == nil {
+		return
+	}
+
+	event := models.NewJobEvent(job.ID, eventType, data)
+	event.CreatedBy = &p.workerID
+	if err := p.events.RecordEvent(ctx, event); err != nil {
+		log.Warn("failed to record job event", "event", eventType, "error", err)
+	}
+}
+
+// publish sends a status change of job to the publisher when one is
+// configured. A failed publish is logged.
+func (p *Pool) publish(ctx context.Context, log logger.Logger, eventType string, job *models.Job) {
+	if p.publisher == nil {
+		return
+	}
+
+	if err := p.publisher.Publish(ctx, eventbus.NewEvent(eventType, job)); err != nil {
+		log.Warn("failed to publish job event", "event", eventType, "error", err)
+	}
+}
+
+// failureRetried reports whether the queue retries a job that failed with
+// err, mirroring Queue.NackError
+func failureRetried(err error) bool {
+	retryable, known := errors.IsRetryable(err)
+	return retryable || !known
+}
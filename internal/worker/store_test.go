@@ -0,0 +1,187 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+
+	"task-queue/internal/config"
+	"task-queue/internal/eventbus"
+	"task-queue/internal/models"
+	"task-queue/internal/storage"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyStore is a MemoryJobStore whose first failures completion writes
+// fail with a database error
+type flakyStore struct {
+	*storage.MemoryJobStore
+	failures atomic.Int32
+}
+
+func (s *flakyStore) UpdateStatus(ctx context.Context, job *models.Job) error {
+	if job.Status == models.JobStatusCompleted && s.failures.Add(-1) >= 0 {
+		return errors.New("connection reset").WithCode(errors.CodeDatabase)
+	}
+
+	return s.MemoryJobStore.UpdateStatus(ctx, job)
+}
+
+// runStored processes one job created in store by a pool using store and
+// events, and returns the job once the queue settled it n times
+func runStored(t *testing.T, store storage.JobStore, events storage.EventStore, n int,
+	setup func(*models.Job), h Handler) (*recordingQueue, *models.Job) {
+	t.Helper()
+
+	q := newRecordingQueue()
+	p := New(q, config.WorkerConfig{}, logger.NewNop(),
+		WithStore(store), WithEventStore(events), WithWorkerID("worker-1"))
+	p.Register("report.build", h)
+
+	job := models.NewJob("report.build", json.RawMessage(`{}`), models.JobPriorityNormal)
+	if setup != nil {
+		setup(job)
+	}
+
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, job))
+	require.NoError(t, q.Enqueue(ctx, job))
+	startPool(t, p)
+	q.waitSettled(t, n)
+
+	stored, err := store.Get(ctx, job.ID)
+	require.NoError(t, err)
+	return q, stored
+}
+
+func TestStore_Success(t *testing.T) {
+	events := storage.NewMemoryEventStore()
+	_, row := runStored(t, storage.NewMemoryJobStore(), events, 1, nil,
+		func(context.Context, *models.Job) (json.RawMessage, error) {
+			return json.RawMessage(`{"pages":3}`), nil
+		})
+
+	assert.Equal(t, models.JobStatusCompleted, row.Status)
+	assert.JSONEq(t, `{"pages":3}`, string(row.Result))
+	assert.Nil(t, row.Error)
+	assert.Zero(t, row.RetryCount)
+	require.NotNil(t, row.WorkerID)
+	assert.Equal(t, "worker-1", *row.WorkerID)
+	require.NotNil(t, row.StartedAt)
+	require.NotNil(t, row.CompletedAt)
+	assert.False(t, row.CompletedAt.Before(*row.StartedAt))
+
+	var types []string
+	for _, event := range events.Events(row.ID) {
+		types = append(types, event.EventType)
+		assert.Equal(t, "worker-1", *event.CreatedBy)
+	}
+
+	assert.Equal(t, []string{logger.AuditJobStarted, logger.AuditJobCompleted}, types)
+}
+
+func TestStore_Failure(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries int
+		err        error
+		wantStatus models.JobStatus
+	}{
+		{name: "retries left", maxRetries: 3, err: errors.New("smtp unavailable"), wantStatus: models.JobStatusRetrying},
+		{name: "retries exhausted", maxRetries: 1, err: errors.New("smtp unavailable"), wantStatus: models.JobStatusDead},
+		{name: "non-retryable", maxRetries: 3, err: errors.Validation("bad template"), wantStatus: models.JobStatusDead},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, row := runStored(t, storage.NewMemoryJobStore(), storage.NewMemoryEventStore(), 1,
+				func(job *models.Job) { job.MaxRetries = tt.maxRetries },
+				func(context.Context, *models.Job) (json.RawMessage, error) {
+					return nil, tt.err
+				})
+
+			assert.Equal(t, tt.wantStatus, row.Status)
+			assert.Equal(t, 1, row.RetryCount)
+			require.NotNil(t, row.Error)
+			assert.Equal(t, tt.err.Error(), *row.Error)
+		})
+	}
+}
+
+func TestStore_RetryThenSuccess(t *testing.T) {
+	var attempts atomic.Int32
+	_, row := runStored(t, storage.NewMemoryJobStore(), storage.NewMemoryEventStore(), 2, nil,
+		func(context.Context, *models.Job) (json.RawMessage, error) {
+			if attempts.Add(1) == 1 {
+				return nil, RetryAfter(0, errors.New("warming up"))
+			}
+
+			return json.RawMessage(`{}`), nil
+		})
+
+	assert.Equal(t, models.JobStatusCompleted, row.Status)
+	assert.Equal(t, 1, row.RetryCount)
+	assert.Nil(t, row.Error)
+}
+
+func TestStore_WriteFailure(t *testing.T) {
+	handler := func(context.Context, *models.Job) (json.RawMessage, error) {
+		return json.RawMessage(`{}`), nil
+	}
+
+	t.Run("transient failures are retried before the ack", func(t *testing.T) {
+		store := &flakyStore{MemoryJobStore: storage.NewMemoryJobStore()}
+		store.failures.Store(2)
+
+		q, row := runStored(t, store, storage.NewMemoryEventStore(), 1, nil, handler)
+		assert.Equal(t, models.JobStatusCompleted, row.Status)
+		assert.True(t, q.waitSettled(t, 1)[0].acked)
+	})
+
+	t.Run("persistent failures nack the job", func(t *testing.T) {
+		store := &flakyStore{MemoryJobStore: storage.NewMemoryJobStore()}
+		store.failures.Store(1 << 20)
+
+		q, row := runStored(t, store, storage.NewMemoryEventStore(), 1, nil, handler)
+		assert.Equal(t, models.JobStatusRunning, row.Status)
+
+		s := q.waitSettled(t, 1)[0]
+		assert.False(t, s.acked)
+		assert.Contains(t, s.reason, "failed to persist job result")
+		assert.EqualValues(t, 1, stats(t, q).Delayed, "the job is retried, not lost")
+	})
+}
+
+func TestPublisher_DeadLettered(t *testing.T) {
+	bus := eventbus.NewMemoryBus()
+	defer bus.Close()
+
+	sub := bus.Subscribe(eventbus.Filter{})
+	defer sub.Close()
+
+	q := newRecordingQueue()
+	p := New(q, config.WorkerConfig{}, logger.NewNop(), WithPublisher(bus))
+	p.Register("report.build", func(context.Context, *models.Job) (json.RawMessage, error) {
+		return nil, Permanent(errors.New("bad template"))
+	})
+
+	job := models.NewJob("report.build", json.RawMessage(`{}`), models.JobPriorityNormal)
+	require.NoError(t, q.Enqueue(context.Background(), job))
+	startPool(t, p)
+	q.waitSettled(t, 1)
+
+	started := <-sub.Events()
+	assert.Equal(t, logger.AuditJobStarted, started.Type)
+	assert.Equal(t, models.JobStatusRunning, started.Status)
+
+	dead := <-sub.Events()
+	assert.Equal(t, logger.AuditJobDeadLettered, dead.Type)
+	assert.Equal(t, job.ID, dead.JobID)
+	assert.Equal(t, models.JobStatusDead, dead.Status)
+	assert.Contains(t, dead.Error, "bad template")
+}
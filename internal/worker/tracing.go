@@ -0,0 +1,29 @@
+package worker
+
+import (
+	"context"
+
+	"task-queue/internal/models"
+	"task-queue/internal/tracing"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// WithTracerProvider starts the processing spans of jobs on tp instead of
+// the global provider
+func WithTracerProvider(tp oteltrace.TracerProvider) Option {
+	return func(p *Pool) {
+		p.tracer = tracing.Tracer(tp)
+	}
+}
+
+// startProcess starts the job.process span of job as a child of the span
+// that enqueued it, and names the trace in the log entries of ctx
+func (p *Pool) startProcess(ctx context.Context, job *models.Job) (context.Context, oteltrace.Span) {
+	attrs := append(tracing.JobAttributes(job), tracing.AttrJobAttempt.Int(job.RetryCount+1))
+	ctx, span := p.tracer.Start(tracing.Extract(ctx, job), "job.process",
+		oteltrace.WithSpanKind(oteltrace.SpanKindConsumer),
+		oteltrace.WithAttributes(attrs...))
+
+	return tracing.LogContext(ctx), span
+}
@@ -0,0 +1,2 @@
+-- Jobs cancelled through the API before they ran
+ALTER TYPE job_status ADD VALUE IF NOT EXISTS 'cancelled';
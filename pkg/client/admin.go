@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+
+	"github.com/google/uuid"
+)
+
+// Queues returns the statistics of every queue. It requires the admin
+// scope, as do the other queue methods.
+func (c *Client) Queues(ctx context.Context) ([]*queue.QueueStats, error) {
+	var resp struct {
+		Queues []*queue.QueueStats `json:"queues"`
+	}
+
+	if err := c.do(ctx, http.MethodGet, "/v1/admin/queues", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Queues, nil
+}
+
+// RequeueDeadLetter returns the dead-lettered job with id to queue name
+func (c *Client) RequeueDeadLetter(ctx context.Context, name string, id uuid.UUID) (*models.Job, error) {
+	var job models.Job
+	path := queuePath(name, "dead-letter", id.String(), "requeue")
+	if err := c.do(ctx, http.MethodPost, path, nil, nil, &job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// RequeueDeadLetters returns every dead-lettered job of queue name to it,
+// reporting how many were requeued
+func (c *Client) RequeueDeadLetters(ctx context.Context, name string) (int64, error) {
+	return c.count(ctx, http.MethodPost, queuePath(name, "dead-letter", "requeue-all"))
+}
+
+// Drain deletes the ready and delayed jobs of queue name, reporting how
+// many were deleted
+func (c *Client) Drain(ctx context.Context, name string) (int64, error) {
+	return c.count(ctx, http.MethodPost, queuePath(name, "drain"))
+}
+
+// Pause stops workers taking jobs from queue name, returning its
+// statistics
+func (c *Client) Pause(ctx context.Context, name string) (*queue.QueueStats, error) {
+	return c.toggle(ctx, queuePath(name, "pause"))
+}
+
+// Resume lets workers take jobs from queue name again
+func (c *Client) Resume(ctx context.Context, name string) (*queue.QueueStats, error) {
+	return c.toggle(ctx, queuePath(name, "resume"))
+}
+
+// count calls a bulk admin action, returning the number of jobs it
+// affected
+func (c *Client) count(ctx context.Context, method, path string) (int64, error) {
+	var resp struct {
+		Count int64 `json:"count"`
+	}
+
+	if err := c.do(ctx, method, path, nil, nil, &resp); err != nil {
+		return 0, err
+	}
+
+	return resp.Count, nil
+}
+
+// toggle calls a pause or resume action
+func (c *Client) toggle(ctx context.Context, path string) (*queue.QueueStats, error) {
+	var stats queue.QueueStats
+	if err := c.do(ctx, http.MethodPost, path, nil, nil, &stats); err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// queuePath is the admin path of queue name followed by elems
+func queuePath(name string, elems ...string) string {
+	path := "/v1/admin/queues/" + url.PathEscape(name)
+	for _, elem := range elems {
+		path += "/" + elem
+	}
+
+	return path
+}
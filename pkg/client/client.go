@@ -0,0 +1,200 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"task-queue/pkg/errors"
+)
+
+// DefaultTimeout bounds each request that is not a watch
+const DefaultTimeout = 30 * time.Second
+
+// apiKeyHeader carries the API key, as read by the server
+const apiKeyHeader = "X-API-Key"
+
+// maxErrorBody caps how much of an error response is read
+const maxErrorBody = 1 << 20
+
+// Client calls the task queue HTTP API. It is safe for concurrent use.
+type Client struct {
+	baseURL *url.URL
+	apiKey  string
+	http    *http.Client
+	timeout time.Duration
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithAPIKey authenticates every request with key
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithHTTPClient sends requests through hc instead of a default client
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.http = hc
+	}
+}
+
+// WithTimeout bounds each request other than a watch to d, DefaultTimeout
+// when d is zero. A negative d disables the bound.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// New creates a client for the API served at baseURL
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, errors.Newf("invalid API URL %q", baseURL).
+			WithCode(errors.CodeConfiguration).
+			WithOp("client.New")
+	}
+
+	c := &Client{baseURL: u, http: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.timeout == 0 {
+		c.timeout = DefaultTimeout
+	}
+
+	return c, nil
+}
+
+// do sends a request to path with in encoded as the JSON body, when not
+// nil, and decodes a successful response into out, when not nil
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out any) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	resp, err := c.send(ctx, method, path, query, in)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return errors.Wrapf(err, "failed to decode response of %s %s", method, path).
+			WithCode(errors.CodeSerialization).
+			WithOp("client.do")
+	}
+
+	return nil
+}
+
+// send sends a request and returns its response when successful. An
+// unsuccessful response is closed and returned as an error.
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, in any) (*http.Response, error) {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encode request").
+				WithCode(errors.CodeSerialization).
+				WithOp("client.send")
+		}
+
+		body = bytes.NewReader(data)
+	}
+
+	u := c.baseURL.JoinPath(path)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build request").
+			WithCode(errors.CodeInternal).
+			WithOp("client.send")
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.apiKey != "" {
+		req.Header.Set(apiKeyHeader, c.apiKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, transportError(ctx, err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		return nil, responseError(resp)
+	}
+
+	return resp, nil
+}
+
+// transportError describes a request that got no response. The cause
+// names the method and URL.
+func transportError(ctx context.Context, err error) error {
+	code := errors.CodeNetwork
+	if ctxErr := errors.FromContext(ctx.Err()); ctxErr != nil {
+		code = ctxErr.Code
+	}
+
+	return errors.Wrap(err, "API request failed").
+		WithCode(code).
+		WithOp("client.send")
+}
+
+// problem is the part of a problem details body the client reads back
+type problem struct {
+	Detail string              `json:"detail"`
+	Code   errors.Code         `json:"code"`
+	Errors []errors.FieldError `json:"errors"`
+}
+
+// responseError converts an unsuccessful response into an error. A problem
+// details body keeps the server's code, detail, and field failures; any
+// other body is described by the status alone.
+func responseError(resp *http.Response) error {
+	fallback := errors.FromHTTPResponse(resp).WithOp("client.send")
+
+	var p problem
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	if err != nil || json.Unmarshal(data, &p) != nil || p.Code == "" || p.Detail == "" {
+		return fallback
+	}
+
+	e := errors.New(p.Detail).
+		WithCode(p.Code).
+		WithStatusCode(resp.StatusCode).
+		WithOp("client.send")
+	if len(p.Errors) > 0 {
+		e = e.WithMetadata(errors.FieldsKey, p.Errors)
+	}
+
+	if delay, ok := errors.RetryAfter(fallback); ok {
+		e = e.WithRetryAfter(delay)
+	}
+
+	return e
+}
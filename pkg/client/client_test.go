@@ -0,0 +1,286 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-queue/internal/api"
+	"task-queue/internal/eventbus"
+	"task-queue/internal/models"
+	"task-queue/internal/queue"
+	"task-queue/internal/service"
+	"task-queue/internal/storage"
+	"task-queue/pkg/errors"
+	"task-queue/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Keys accepted by the test server
+const (
+	adminKey  = "admin-key"
+	readerKey = "reader-key"
+)
+
+// testServer is an API server over in-memory stores and one queue
+type testServer struct {
+	*httptest.Server
+	queue *queue.MemoryQueue
+	bus   *eventbus.MemoryBus
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	s := &testServer{
+		queue: queue.NewMemoryQueue(queue.Config{Name: "default"}),
+		bus:   eventbus.NewMemoryBus(),
+	}
+
+	manager := queue.NewQueueManager()
+	manager.Register("default", s.queue)
+
+	log := logger.NewNop()
+	svc := service.New(storage.NewMemoryJobStore(), storage.NewMemoryEventStore(), s.queue, log,
+		service.WithPublisher(s.bus))
+	s.Server = httptest.NewServer(api.New(svc, log,
+		api.WithQueueManager(manager),
+		api.WithEventBus(s.bus),
+		api.WithAuth(api.StaticKeys{
+			adminKey:  {Name: "ops", Scopes: []string{api.ScopeAdmin}},
+			readerKey: {Name: "dashboard", Scopes: []string{api.ScopeJobsRead}},
+		}),
+	))
+
+	t.Cleanup(func() {
+		s.Close()
+		_ = s.bus.Close()
+	})
+
+	return s
+}
+
+// client returns a client of s authenticating with key
+func (s *testServer) client(t *testing.T, key string) *Client {
+	t.Helper()
+
+	c, err := New(s.URL+"/", WithAPIKey(key), WithHTTPClient(s.Client()))
+	require.NoError(t, err)
+	return c
+}
+
+func emailRequest() *models.JobRequest {
+	return &models.JobRequest{
+		Type:     "email_send",
+		Payload:  json.RawMessage(`{"to":"a@example.com"}`),
+		Priority: models.JobPriorityHigh,
+	}
+}
+
+func TestNew_InvalidURL(t *testing.T) {
+	for _, raw := range []string{"", "localhost:8080", "://nope"} {
+		_, err := New(raw)
+		assert.True(t, errors.HasCode(err, errors.CodeConfiguration), raw)
+	}
+}
+
+func TestClient_Jobs(t *testing.T) {
+	ctx := context.Background()
+	c := newTestServer(t).client(t, adminKey)
+
+	job, err := c.Submit(ctx, emailRequest())
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusPending, job.Status)
+	assert.Equal(t, models.JobPriorityHigh, job.Priority)
+
+	got, err := c.Get(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, got.ID)
+
+	_, err = c.Submit(ctx, &models.JobRequest{Type: "report_build", Payload: json.RawMessage(`{}`)})
+	require.NoError(t, err)
+
+	page, err := c.List(ctx, ListOptions{Types: []string{"email_send"}, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, job.ID, page.Items[0].ID)
+	assert.Equal(t, models.JobPriorityHigh, page.Items[0].Priority, "listed priorities are named")
+	assert.Empty(t, page.NextCursor)
+
+	future := time.Now().Add(time.Hour)
+	page, err = c.List(ctx, ListOptions{CreatedAfter: &future})
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+
+	cancelled, err := c.Cancel(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusCancelled, cancelled.Status)
+
+	priority := models.JobPriorityLow
+	replay, err := c.Replay(ctx, job.ID, models.ReplayOverrides{Priority: &priority})
+	require.NoError(t, err)
+	assert.NotEqual(t, job.ID, replay.ID)
+	assert.Equal(t, models.JobPriorityLow, replay.Priority)
+	assert.Equal(t, job.ID.String(), replay.Metadata[models.ReplayMetadataKey])
+
+	events, err := c.Events(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Len(t, events, 3)
+}
+
+func TestClient_Errors(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t)
+	c := s.client(t, adminKey)
+
+	t.Run("validation failures keep their fields", func(t *testing.T) {
+		_, err := c.List(ctx, ListOptions{Statuses: []models.JobStatus{"sleeping"}})
+		require.Error(t, err)
+		assert.True(t, errors.HasCode(err, errors.CodeValidation))
+
+		var e *errors.Error
+		require.ErrorAs(t, err, &e)
+		assert.Equal(t, http.StatusBadRequest, e.HTTPStatus())
+		fields := e.Metadata[errors.FieldsKey].([]errors.FieldError)
+		require.Len(t, fields, 1)
+		assert.Equal(t, "status[0]", fields[0].Field)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := c.Get(ctx, uuid.New())
+		assert.True(t, errors.HasCode(err, errors.CodeNotFound))
+	})
+
+	t.Run("authentication", func(t *testing.T) {
+		_, err := s.client(t, "").Get(ctx, uuid.New())
+		assert.True(t, errors.HasCode(err, errors.CodeAuthentication))
+
+		_, err = s.client(t, readerKey).Queues(ctx)
+		assert.True(t, errors.HasCode(err, errors.CodePermission))
+	})
+
+	t.Run("responses that are not problems", func(t *testing.T) {
+		plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "3")
+			http.Error(w, "upstream unavailable", http.StatusBadGateway)
+		}))
+		defer plain.Close()
+
+		c, err := New(plain.URL)
+		require.NoError(t, err)
+
+		_, err = c.Queues(ctx)
+		assert.True(t, errors.HasCode(err, errors.CodeNetwork))
+		delay, ok := errors.RetryAfter(err)
+		assert.True(t, ok)
+		assert.Equal(t, 3*time.Second, delay)
+	})
+
+	t.Run("unreachable server", func(t *testing.T) {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+
+		c, err := New(closed.URL)
+		require.NoError(t, err)
+
+		_, err = c.Get(ctx, uuid.New())
+		assert.True(t, errors.HasCode(err, errors.CodeNetwork))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		defer slow.Close()
+
+		c, err := New(slow.URL, WithTimeout(10*time.Millisecond))
+		require.NoError(t, err)
+
+		_, err = c.Get(ctx, uuid.New())
+		assert.True(t, errors.HasCode(err, errors.CodeTimeout))
+	})
+}
+
+func TestClient_Admin(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t)
+	c := s.client(t, adminKey)
+
+	stats, err := c.Pause(ctx, "default")
+	require.NoError(t, err)
+	assert.True(t, stats.Paused)
+	assert.Equal(t, "default", stats.Name)
+
+	stats, err = c.Resume(ctx, "default")
+	require.NoError(t, err)
+	assert.False(t, stats.Paused)
+
+	_, err = c.Pause(ctx, "missing")
+	assert.True(t, errors.HasCode(err, errors.CodeNotFound))
+
+	// Dead-letter two jobs by failing them permanently
+	var dead []uuid.UUID
+	for range 2 {
+		job, err := c.Submit(ctx, emailRequest())
+		require.NoError(t, err)
+
+		taken, err := s.queue.Dequeue(ctx)
+		require.NoError(t, err)
+		require.NoError(t, s.queue.NackError(ctx, taken.ID, errors.Validation("bad address")))
+		dead = append(dead, job.ID)
+	}
+
+	requeued, err := c.RequeueDeadLetter(ctx, "default", dead[0])
+	require.NoError(t, err)
+	assert.Equal(t, dead[0], requeued.ID)
+
+	count, err := c.RequeueDeadLetters(ctx, "default")
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	queues, err := c.Queues(ctx)
+	require.NoError(t, err)
+	require.Len(t, queues, 1)
+	assert.EqualValues(t, 2, queues[0].Size)
+
+	count, err = c.Drain(ctx, "default")
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+}
+
+func TestClient_Watch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s := newTestServer(t)
+	c := s.client(t, readerKey)
+	job, err := s.client(t, adminKey).Submit(ctx, emailRequest())
+	require.NoError(t, err)
+
+	var seen []models.JobStatus
+	err = c.Watch(ctx, job.ID, func(event eventbus.Event) error {
+		seen = append(seen, event.Status)
+		if event.Status == models.JobStatusPending {
+			job.Status = models.JobStatusCompleted
+			return s.bus.Publish(ctx, eventbus.NewEvent(logger.AuditJobCompleted, job))
+		}
+
+		return nil
+	})
+
+	require.NoError(t, err, "the watch ends with the job")
+	assert.Equal(t, []models.JobStatus{models.JobStatusPending, models.JobStatusCompleted}, seen)
+
+	stop := errors.New("stop")
+	err = c.Watch(ctx, job.ID, func(eventbus.Event) error { return stop })
+	assert.ErrorIs(t, err, stop)
+
+	err = c.Watch(ctx, uuid.New(), func(eventbus.Event) error { return nil })
+	assert.True(t, errors.HasCode(err, errors.CodeNotFound))
+}
@@ -0,0 +1,30 @@
+// Package client is a Go SDK for the task queue HTTP API. It wraps the job
+// and admin endpoints in typed methods, and turns problem responses back
+// into *errors.Error values carrying the server's error code, so callers
+// can branch on errors.HasCode as they would in-process.
+//
+// Basic usage:
+//
+//	c, err := client.New("http://localhost:8080",
+//	    client.WithAPIKey(os.Getenv("TQ_CLIENT_API_KEY")),
+//	)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	job, err := c.Submit(ctx, &models.JobRequest{
+//	    Type:    "send_email",
+//	    Payload: json.RawMessage(`{"to":"a@example.com"}`),
+//	})
+//
+// Watching a job:
+//
+//	err = c.Watch(ctx, job.ID, func(event eventbus.Event) error {
+//	    fmt.Println(event.Type, event.Status)
+//	    return nil
+//	})
+//
+// Watch returns once the job reaches a terminal status, the context is
+// cancelled, or the callback returns an error. The client timeout does not
+// apply to watches.
+package client
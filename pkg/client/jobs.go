@@ -0,0 +1,203 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"task-queue/internal/eventbus"
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// ListOptions selects the jobs returned by List. Zero values are left to
+// the server's defaults.
+type ListOptions struct {
+	Statuses      []models.JobStatus
+	Types         []string
+	Priorities    []string
+	Tags          []string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Search        string
+	Sort          string
+	Limit         int
+	Cursor        string
+}
+
+// query encodes the options as listing parameters
+func (o ListOptions) query() url.Values {
+	query := url.Values{}
+	for _, status := range o.Statuses {
+		query.Add("status", string(status))
+	}
+
+	for name, values := range map[string][]string{"type": o.Types, "priority": o.Priorities, "tag": o.Tags} {
+		for _, value := range values {
+			query.Add(name, value)
+		}
+	}
+
+	if o.CreatedAfter != nil {
+		query.Set("created_after", o.CreatedAfter.Format(time.RFC3339Nano))
+	}
+
+	if o.CreatedBefore != nil {
+		query.Set("created_before", o.CreatedBefore.Format(time.RFC3339Nano))
+	}
+
+	if o.Limit > 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+
+	for name, value := range map[string]string{"q": o.Search, "sort": o.Sort, "cursor": o.Cursor} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+
+	return query
+}
+
+// JobPage is one page of a job listing. NextCursor is empty on the last
+// page.
+type JobPage struct {
+	Items         []*models.Job `json:"items"`
+	NextCursor    string        `json:"next_cursor"`
+	TotalEstimate int64         `json:"total_estimate"`
+}
+
+// Submit submits a job
+func (c *Client) Submit(ctx context.Context, req *models.JobRequest) (*models.Job, error) {
+	var job models.Job
+	if err := c.do(ctx, http.MethodPost, "/v1/jobs", nil, req, &job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// Get returns the job with id
+func (c *Client) Get(ctx context.Context, id uuid.UUID) (*models.Job, error) {
+	var job models.Job
+	if err := c.do(ctx, http.MethodGet, "/v1/jobs/"+id.String(), nil, nil, &job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// List returns a page of the jobs matching opts
+func (c *Client) List(ctx context.Context, opts ListOptions) (*JobPage, error) {
+	var page struct {
+		JobPage
+		NextCursor *string `json:"next_cursor"`
+	}
+
+	if err := c.do(ctx, http.MethodGet, "/v1/jobs", opts.query(), nil, &page); err != nil {
+		return nil, err
+	}
+
+	if page.NextCursor != nil {
+		page.JobPage.NextCursor = *page.NextCursor
+	}
+
+	return &page.JobPage, nil
+}
+
+// Cancel cancels the job with id, which must not have started
+func (c *Client) Cancel(ctx context.Context, id uuid.UUID) (*models.Job, error) {
+	var job models.Job
+	if err := c.do(ctx, http.MethodDelete, "/v1/jobs/"+id.String(), nil, nil, &job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// Replay submits the finished job with id again as a new job, changed by
+// overrides, and returns the new job
+func (c *Client) Replay(ctx context.Context, id uuid.UUID, overrides models.ReplayOverrides) (*models.Job, error) {
+	var job models.Job
+	if err := c.do(ctx, http.MethodPost, "/v1/jobs/"+id.String()+"/replay", nil, overrides, &job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// Events returns the recorded history of the job with id, oldest first
+func (c *Client) Events(ctx context.Context, id uuid.UUID) ([]models.JobEvent, error) {
+	var resp struct {
+		Events []models.JobEvent `json:"events"`
+	}
+
+	if err := c.do(ctx, http.MethodGet, "/v1/jobs/"+id.String()+"/events", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Events, nil
+}
+
+// Watch calls fn with a snapshot of the job with id, then with each of its
+// status changes, until the job reaches a terminal status. It stops early
+// when ctx is done or fn returns an error, which it returns.
+func (c *Client) Watch(ctx context.Context, id uuid.UUID, fn func(eventbus.Event) error) error {
+	resp, err := c.send(ctx, http.MethodGet, "/v1/jobs/"+id.String()+"/watch", nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	for {
+		data, err := nextFrame(reader)
+		switch {
+		case err == io.EOF:
+			return nil
+
+		case err != nil:
+			return transportError(ctx, err)
+		}
+
+		var event eventbus.Event
+		if err := json.Unmarshal(data, &event); err != nil {
+			return errors.Wrap(err, "failed to decode job event").
+				WithCode(errors.CodeSerialization).
+				WithOp("client.Watch")
+		}
+
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+}
+
+// nextFrame returns the data of the next server-sent event, skipping
+// comments such as heartbeats. It returns io.EOF once the stream ends.
+func nextFrame(reader *bufio.Reader) ([]byte, error) {
+	var data []byte
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "" && data != nil:
+			return data, nil
+
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimSpace(strings.TrimPrefix(line, "data:"))...)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"errors"
+)
+
+// Chain returns every error in err's unwrap tree, outermost first. The
+// children of joined errors follow their parent in order, each with its own
+// chain, so the result is a depth-first walk. It returns nil for nil.
+func Chain(err error) []error {
+	var chain []error
+	walkChain(err, func(e error) {
+		chain = append(chain, e)
+	})
+
+	return chain
+}
+
+// walkChain calls fn for err and every error it wraps, depth first
+func walkChain(err error, fn func(error)) {
+	for err != nil {
+		fn(err)
+
+		switch unwrapper := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, child := range unwrapper.Unwrap() {
+				walkChain(child, fn)
+			}
+
+			return
+
+		case interface{ Unwrap() error }:
+			err = unwrapper.Unwrap()
+
+		default:
+			return
+		}
+	}
+}
+
+// RootCause returns the innermost error of err's chain that is not an *Error,
+// typically the driver or stdlib error that started the failure. When the
+// chain consists only of *Error values, the innermost one is returned.
+// Joined errors end the walk, since they have no single root.
+func RootCause(err error) error {
+	root := err
+	var foreign error
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		root = current
+		if _, ok := current.(*Error); !ok {
+			foreign = current
+		}
+	}
+
+	if foreign != nil {
+		return foreign
+	}
+
+	return root
+}
+
+// HasCode reports whether any *Error in err's chain has code. Unlike GetCode,
+// which only reads the outermost *Error, it finds codes buried under wrappers
+// that changed the code.
+func HasCode(err error, code Code) bool {
+	found := false
+	walkChain(err, func(e error) {
+		if appErr, ok := e.(*Error); ok && appErr.Code == code {
+			found = true
+		}
+	})
+
+	return found
+}
@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mixedChain builds *Error -> fmt wrapper -> *Error -> io.ErrUnexpectedEOF
+func mixedChain() (outer *Error, middle error, inner *Error) {
+	inner = Wrap(io.ErrUnexpectedEOF, "payload truncated").WithCode(CodeValidation)
+	middle = fmt.Errorf("decode job: %w", inner)
+	outer = Wrap(middle, "dequeue failed").WithCode(CodeInternal)
+
+	return outer, middle, inner
+}
+
+func TestChain(t *testing.T) {
+	outer, middle, inner := mixedChain()
+
+	assert.Equal(t, []error{outer, middle, inner, io.ErrUnexpectedEOF}, Chain(outer))
+	assert.Equal(t, []error{io.EOF}, Chain(io.EOF))
+	assert.Nil(t, Chain(nil))
+}
+
+func TestChain_JoinedErrors(t *testing.T) {
+	first := Wrap(io.EOF, "read failed").WithCode(CodeNetwork)
+	second := NotFound("job missing")
+	joined := Join(first, second)
+
+	chain := Chain(joined)
+	require.Len(t, chain, 5)
+	assert.Same(t, joined, chain[0])
+	assert.Equal(t, []error{first, io.EOF, second}, chain[2:])
+}
+
+func TestRootCause(t *testing.T) {
+	outer, _, _ := mixedChain()
+	assert.Equal(t, io.ErrUnexpectedEOF, RootCause(outer))
+
+	onlyTyped := Wrap(NotFound("job missing"), "lookup failed")
+	assert.Equal(t, onlyTyped.Cause, RootCause(onlyTyped))
+
+	foreignOverTyped := fmt.Errorf("handler: %w", Internal("boom"))
+	assert.Equal(t, foreignOverTyped, RootCause(foreignOverTyped))
+
+	assert.Equal(t, io.EOF, RootCause(io.EOF))
+	assert.Nil(t, RootCause(nil))
+}
+
+func TestHasCode(t *testing.T) {
+	outer, _, _ := mixedChain()
+
+	assert.Equal(t, CodeInternal, GetCode(outer))
+	assert.True(t, HasCode(outer, CodeInternal))
+	assert.True(t, HasCode(outer, CodeValidation), "found below a wrapper that changed the code")
+	assert.False(t, HasCode(outer, CodeNotFound))
+	assert.False(t, HasCode(io.EOF, CodeUnknown))
+	assert.True(t, HasCode(Join(io.EOF, NotFound("missing")), CodeNotFound))
+}
+
+func TestIs_PlainSentinelInChain(t *testing.T) {
+	outer, _, _ := mixedChain()
+
+	assert.True(t, outer.Is(io.ErrUnexpectedEOF), "direct calls see the whole chain")
+	assert.False(t, outer.Is(io.EOF))
+	assert.False(t, outer.Is(nil))
+	assert.True(t, errors.Is(outer, io.ErrUnexpectedEOF))
+	assert.True(t, errors.Is(outer, ErrValidation))
+}
+
+func TestIsRetryable_PermanentCodeDeepInChain(t *testing.T) {
+	outer, _, _ := mixedChain()
+	outer.WithCode(CodeNetwork)
+
+	retryable, known := IsRetryable(outer)
+	assert.True(t, known)
+	assert.False(t, retryable, "a wrapped validation error stays permanent")
+
+	retryable, _ = IsRetryable(outer.WithRetryable(true))
+	assert.True(t, retryable, "an explicit flag still wins")
+}
@@ -0,0 +1,84 @@
+package errors
+
+import (
+	"fmt"
+)
+
+// Sentinel errors for matching a category with errors.Is. Any *Error with the
+// same code matches, whatever its message. They must not be modified.
+var (
+	ErrNotFound         = sentinel(CodeNotFound, "not found")
+	ErrValidation       = sentinel(CodeValidation, "validation failed")
+	ErrAlreadyExists    = sentinel(CodeAlreadyExists, "already exists")
+	ErrConflict         = sentinel(CodeConflict, "conflict")
+	ErrInternal         = sentinel(CodeInternal, "internal error")
+	ErrUnauthenticated  = sentinel(CodeAuthentication, "unauthenticated")
+	ErrPermissionDenied = sentinel(CodePermission, "permission denied")
+	ErrRateLimited      = sentinel(CodeRateLimit, "rate limited")
+	ErrTimeout          = sentinel(CodeTimeout, "timeout")
+)
+
+// NotFound creates a not found error with a formatted message
+func NotFound(format string, args ...any) *Error {
+	return newWithCode(CodeNotFound, format, args...)
+}
+
+// Validation creates a validation error with a formatted message
+func Validation(format string, args ...any) *Error {
+	return newWithCode(CodeValidation, format, args...)
+}
+
+// AlreadyExists creates an already exists error with a formatted message
+func AlreadyExists(format string, args ...any) *Error {
+	return newWithCode(CodeAlreadyExists, format, args...)
+}
+
+// Conflict creates a conflict error with a formatted message
+func Conflict(format string, args ...any) *Error {
+	return newWithCode(CodeConflict, format, args...)
+}
+
+// Internal creates an internal error with a formatted message
+func Internal(format string, args ...any) *Error {
+	return newWithCode(CodeInternal, format, args...)
+}
+
+// Unauthenticated creates an authentication error with a formatted message
+func Unauthenticated(format string, args ...any) *Error {
+	return newWithCode(CodeAuthentication, format, args...)
+}
+
+// PermissionDenied creates a permission error with a formatted message
+func PermissionDenied(format string, args ...any) *Error {
+	return newWithCode(CodePermission, format, args...)
+}
+
+// RateLimited creates a rate limit error with a formatted message
+func RateLimited(format string, args ...any) *Error {
+	return newWithCode(CodeRateLimit, format, args...)
+}
+
+// Timeout creates a timeout error with a formatted message
+func Timeout(format string, args ...any) *Error {
+	return newWithCode(CodeTimeout, format, args...)
+}
+
+// newWithCode creates an error with a code and a stack starting at the caller
+// of the exported constructor
+func newWithCode(code Code, format string, args ...any) *Error {
+	message := format
+	if len(args) > 0 {
+		message = fmt.Sprintf(format, args...)
+	}
+
+	return (&Error{
+		Message:  message,
+		Metadata: make(map[string]any),
+		stack:    captureStack(3),
+	}).WithCode(code)
+}
+
+// sentinel creates a category error without a stack trace
+func sentinel(code Code, message string) *Error {
+	return (&Error{Message: message}).WithCode(code)
+}
@@ -0,0 +1,60 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstructors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *Error
+		code     Code
+		status   int
+		sentinel *Error
+	}{
+		{"NotFound", NotFound("job %s not found", "42"), CodeNotFound, http.StatusNotFound, ErrNotFound},
+		{"Validation", Validation("job %s is invalid", "42"), CodeValidation, http.StatusBadRequest, ErrValidation},
+		{"AlreadyExists", AlreadyExists("job %s exists", "42"), CodeAlreadyExists, http.StatusConflict, ErrAlreadyExists},
+		{"Conflict", Conflict("job %s changed", "42"), CodeConflict, http.StatusConflict, ErrConflict},
+		{"Internal", Internal("job %s broke", "42"), CodeInternal, http.StatusInternalServerError, ErrInternal},
+		{"Unauthenticated", Unauthenticated("token for %s expired", "42"), CodeAuthentication, http.StatusUnauthorized, ErrUnauthenticated},
+		{"PermissionDenied", PermissionDenied("job %s is private", "42"), CodePermission, http.StatusForbidden, ErrPermissionDenied},
+		{"RateLimited", RateLimited("client %s is throttled", "42"), CodeRateLimit, http.StatusTooManyRequests, ErrRateLimited},
+		{"Timeout", Timeout("job %s timed out", "42"), CodeTimeout, http.StatusRequestTimeout, ErrTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.code, tt.err.Code)
+			assert.Equal(t, tt.status, tt.err.HTTPStatus())
+			assert.Contains(t, tt.err.Message, "42")
+			assert.NotNil(t, tt.err.Metadata)
+
+			assert.True(t, errors.Is(tt.err, tt.sentinel))
+			assert.True(t, errors.Is(fmt.Errorf("handler: %w", tt.err), tt.sentinel))
+			assert.True(t, errors.Is(Wrap(tt.err, "wrapped"), tt.sentinel))
+
+			assert.Equal(t, tt.code, tt.sentinel.Code)
+			assert.Equal(t, tt.status, tt.sentinel.HTTPStatus())
+		})
+	}
+}
+
+func TestConstructors_SentinelMismatch(t *testing.T) {
+	err := NotFound("job missing")
+
+	assert.False(t, errors.Is(err, ErrValidation))
+	assert.False(t, errors.Is(errors.New("plain"), ErrNotFound))
+}
+
+func TestConstructors_StackStartsAtCaller(t *testing.T) {
+	err := Validation("bad input")
+
+	assert.True(t, strings.HasSuffix(err.StackTrace()[0].Function, "TestConstructors_StackStartsAtCaller"))
+}
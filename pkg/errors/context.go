@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"context"
+	"errors"
+)
+
+// Timeout reports whether the error is a timeout. Together with Temporary it
+// lets code written against net.Error recognize errors from this package.
+func (e *Error) Timeout() bool {
+	return e != nil && e.Code == CodeTimeout
+}
+
+// Temporary reports whether retrying may succeed, following IsRetryable. An
+// error whose retry classification is unknown is not temporary.
+func (e *Error) Temporary() bool {
+	if e == nil {
+		return false
+	}
+
+	retryable, known := IsRetryable(e)
+	return known && retryable
+}
+
+// FromContext converts a context error into a typed error: deadline errors
+// become CodeTimeout and cancellation becomes CodeCanceled, with the context
+// error kept as the cause. It returns nil for nil and an *Error unchanged.
+//
+//	if err := ctx.Err(); err != nil {
+//	    return errors.FromContext(err)
+//	}
+func FromContext(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	if e, ok := err.(*Error); ok {
+		return e
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return translate(err, CodeTimeout, "deadline exceeded")
+
+	case errors.Is(err, context.Canceled):
+		return translate(err, CodeCanceled, "operation canceled")
+
+	default:
+		return translate(err, CodeUnknown, "context error")
+	}
+}
@@ -0,0 +1,92 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var _ net.Error = (*Error)(nil)
+
+func TestError_TimeoutAndTemporary(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       *Error
+		timeout   bool
+		temporary bool
+	}{
+		{"timeout", Timeout("query took too long"), true, true},
+		{"network", New("reset").WithCode(CodeNetwork), false, true},
+		{"validation", Validation("bad input"), false, false},
+		{"unknown code", New("boom"), false, false},
+		{"explicit override", Timeout("slow").WithRetryable(false), true, false},
+		{"nil", nil, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.timeout, tt.err.Timeout())
+			assert.Equal(t, tt.temporary, tt.err.Temporary())
+		})
+	}
+}
+
+func TestError_IsContextErrors(t *testing.T) {
+	timeout := Timeout("job exceeded %s", time.Second)
+	canceled := New("shutting down").WithCode(CodeCanceled)
+	wrapped := fmt.Errorf("worker: %w", Wrap(timeout, "process failed"))
+
+	assert.ErrorIs(t, timeout, context.DeadlineExceeded)
+	assert.ErrorIs(t, wrapped, context.DeadlineExceeded)
+	assert.NotErrorIs(t, timeout, context.Canceled)
+
+	assert.ErrorIs(t, canceled, context.Canceled)
+	assert.NotErrorIs(t, canceled, context.DeadlineExceeded)
+
+	assert.NotErrorIs(t, Internal("boom"), context.DeadlineExceeded)
+	assert.ErrorIs(t, timeout, ErrTimeout, "code matching still works")
+}
+
+func TestFromContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	deadline := FromContext(ctx.Err())
+	assert.Equal(t, CodeTimeout, deadline.Code)
+	assert.True(t, deadline.Timeout())
+	assert.Equal(t, ctx.Err(), deadline.Cause)
+
+	canceled := FromContext(context.Canceled)
+	assert.Equal(t, CodeCanceled, canceled.Code)
+	assert.False(t, canceled.Temporary())
+
+	assert.Equal(t, CodeTimeout, FromContext(fmt.Errorf("op: %w", context.DeadlineExceeded)).Code)
+	assert.Equal(t, CodeUnknown, FromContext(io.EOF).Code)
+	assert.Nil(t, FromContext(nil))
+
+	existing := NotFound("gone")
+	assert.Same(t, existing, FromContext(existing))
+}
+
+func TestIsRetryable_TimeoutInterface(t *testing.T) {
+	retryable, known := IsRetryable(&net.OpError{Op: "read", Err: timeoutError{}})
+	assert.True(t, known)
+	assert.True(t, retryable)
+
+	_, known = IsRetryable(errors.New("plain"))
+	assert.False(t, known)
+}
+
+// timeoutError is a foreign error reporting a timeout
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
@@ -10,7 +10,13 @@
 //   - Stack trace capture for debugging
 //   - Structured error metadata
 //   - Error categorization (validation, not found, conflict, etc.)
+//   - Aggregation of multiple errors with Join
+//   - JSON round-trips that keep the cause chain (ToJSON, FromJSON)
 //   - gRPC status code conversion
+//   - Redaction of sensitive metadata in serialized and logged errors
+//   - Translation of Postgres and Redis driver errors (FromPostgres, FromRedis)
+//   - RFC 7807 problem+json responses for the HTTP API
+//   - Localized messages from per-locale catalogs (NewKey, Localize)
 //
 // Basic usage:
 //
@@ -18,6 +24,14 @@
 //	    WithCode(errors.CodeInternal).
 //	    WithMetadata("host", "localhost:5432")
 //
+// Constructors set the code for common categories in one call, and sentinels
+// match a category with the standard errors.Is:
+//
+//	err := errors.NotFound("job %s not found", id)
+//	if stderrors.Is(err, errors.ErrNotFound) {
+//	    // Handle any not found error
+//	}
+//
 // Wrapping errors:
 //
 //	if err := db.Query(); err != nil {
@@ -30,4 +44,47 @@
 //	if errors.IsNotFound(err) {
 //	    // Handle not found error
 //	}
+//
+// Aggregating several errors, e.g. from a batch:
+//
+//	if err := errors.Join(errs...); err != nil {
+//	    return err
+//	}
+//
+// Printing the chain with stack traces:
+//
+//	fmt.Printf("%+v\n", err)
+//
+// Sensitive metadata is replaced by "[REDACTED]" in ToJSON, gRPC details, and
+// SafeMetadata. Keys such as "password" and "token" are redacted by default:
+//
+//	errors.SetRedactedKeys("password", "token", "email")
+//	err = err.WithSensitiveMetadata("payload", body)
+//
+// Messages identified by a catalog key can be rendered in other locales;
+// WriteProblem does so for requests with an Accept-Language header:
+//
+//	err := errors.NewKey("job.not_found", id).WithCode(errors.CodeNotFound)
+//	msg := errors.Localize(err, "es")
+//
+// Converting panics into errors:
+//
+//	func handle(job *models.Job) (err error) {
+//	    defer errors.Recover(&err)
+//	    ...
+//	}
+//
+// Counting errors where they leave the system, e.g. when a job fails, via the
+// observer installed with SetObserver:
+//
+//	return errors.Report(err)
+//
+// Writing an HTTP error response:
+//
+//	errors.WriteProblem(w, err, r)
+//
+// Converting to and from gRPC:
+//
+//	return nil, errors.ToGRPCError(err)
+//	appErr := errors.FromGRPCStatus(status.Convert(err))
 package errors
@@ -0,0 +1,189 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+)
+
+// Postgres SQLSTATE codes translated by FromPostgres
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgNotNullViolation     = "23502"
+	pgCheckViolation       = "23514"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+	pgQueryCanceled        = "57014"
+	pgAdminShutdown        = "57P01"
+	pgConnectionException  = "08"
+)
+
+// redisRetryablePrefixes are Redis reply prefixes for transient server states
+var redisRetryablePrefixes = []string{
+	"LOADING",
+	"READONLY",
+	"TRYAGAIN",
+	"CLUSTERDOWN",
+	"MASTERDOWN",
+}
+
+// FromPostgres translates an error returned by database/sql and lib/pq into
+// an *Error with the matching code. Unique violations become
+// CodeAlreadyExists, other integrity violations CodeValidation, serialization
+// failures and deadlocks retryable CodeDatabase, statement timeouts
+// CodeTimeout, and connection failures CodeNetwork. The SQLSTATE and the
+// constraint, table, and column names are kept in the metadata. The driver
+// error stays in the chain; an *Error is returned unchanged.
+func FromPostgres(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	if e, ok := err.(*Error); ok {
+		return e
+	}
+
+	var pgErr *pq.Error
+	if errors.As(err, &pgErr) {
+		return fromPQ(err, pgErr)
+	}
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return translate(err, CodeNotFound, "postgres: no rows")
+
+	case errors.Is(err, context.DeadlineExceeded):
+		return translate(err, CodeTimeout, "postgres: query timed out")
+
+	case errors.Is(err, context.Canceled):
+		return translate(err, CodeCanceled, "postgres: query canceled")
+
+	case isConnectionError(err):
+		return translate(err, CodeNetwork, "postgres: connection failed")
+
+	default:
+		return translate(err, CodeDatabase, "postgres: query failed")
+	}
+}
+
+// fromPQ translates a server-reported Postgres error
+func fromPQ(err error, pgErr *pq.Error) *Error {
+	var e *Error
+	switch code := string(pgErr.Code); {
+	case code == pgUniqueViolation:
+		e = translate(err, CodeAlreadyExists, "postgres: unique constraint violated")
+
+	case code == pgForeignKeyViolation || code == pgNotNullViolation ||
+		code == pgCheckViolation:
+		e = translate(err, CodeValidation, "postgres: integrity constraint violated")
+
+	case code == pgSerializationFailure || code == pgDeadlockDetected:
+		e = translate(err, CodeDatabase, "postgres: transaction conflict").
+			WithRetryable(true)
+
+	case code == pgQueryCanceled:
+		e = translate(err, CodeTimeout, "postgres: statement canceled")
+
+	case code == pgAdminShutdown ||
+		string(pgErr.Code.Class()) == pgConnectionException:
+		e = translate(err, CodeNetwork, "postgres: connection failed")
+
+	default:
+		e = translate(err, CodeDatabase, "postgres: query failed")
+	}
+
+	e.WithMetadata("sqlstate", string(pgErr.Code))
+	if pgErr.Constraint != "" {
+		e.WithMetadata("constraint", pgErr.Constraint)
+	}
+
+	if pgErr.Table != "" {
+		e.WithMetadata("table", pgErr.Table)
+	}
+
+	if pgErr.Column != "" {
+		e.WithMetadata("column", pgErr.Column)
+	}
+
+	return e
+}
+
+// FromRedis translates an error returned by go-redis into an *Error with the
+// matching code. redis.Nil becomes CodeNotFound, timeouts CodeTimeout, pool
+// exhaustion CodeRateLimit, transient server states and connection failures
+// CodeNetwork, and other server replies CodeInternal. The driver error stays
+// in the chain; an *Error is returned unchanged.
+func FromRedis(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	if e, ok := err.(*Error); ok {
+		return e
+	}
+
+	switch {
+	case errors.Is(err, redis.Nil):
+		return translate(err, CodeNotFound, "redis: key not found")
+
+	case errors.Is(err, redis.ErrPoolTimeout), errors.Is(err, redis.ErrPoolExhausted):
+		return translate(err, CodeRateLimit, "redis: connection pool exhausted")
+
+	case errors.Is(err, context.DeadlineExceeded), isTimeout(err):
+		return translate(err, CodeTimeout, "redis: command timed out")
+
+	case errors.Is(err, context.Canceled):
+		return translate(err, CodeCanceled, "redis: command canceled")
+
+	case errors.Is(err, redis.ErrClosed):
+		return translate(err, CodeInternal, "redis: client closed")
+	}
+
+	var replyErr redis.Error
+	if errors.As(err, &replyErr) {
+		for _, prefix := range redisRetryablePrefixes {
+			if redis.HasErrorPrefix(err, prefix) {
+				return translate(err, CodeNetwork, "redis: server unavailable")
+			}
+		}
+
+		return translate(err, CodeInternal, "redis: command failed")
+	}
+
+	return translate(err, CodeNetwork, "redis: connection failed")
+}
+
+// translate wraps a driver error with code. The stack starts at the caller of
+// the exported translator.
+func translate(err error, code Code, message string) *Error {
+	return &Error{
+		Code:       code,
+		Message:    message,
+		Cause:      err,
+		StatusCode: codeToHTTPStatus(code),
+		Metadata:   make(map[string]any),
+		stack:      captureStack(3),
+	}
+}
+
+// isConnectionError reports whether err means the connection itself failed
+func isConnectionError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isTimeout reports whether err is a network timeout
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
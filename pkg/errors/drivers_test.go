@@ -0,0 +1,123 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// redisReply is a Redis server error reply
+type redisReply string
+
+func (r redisReply) Error() string { return string(r) }
+
+func (redisReply) RedisError() {}
+
+// netTimeout is a network read that hit its deadline
+var netTimeout = &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded}
+
+func TestFromPostgres(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		code      Code
+		retryable bool
+	}{
+		{"unique violation", &pq.Error{Code: "23505", Constraint: "jobs_pkey"}, CodeAlreadyExists, false},
+		{"foreign key violation", &pq.Error{Code: "23503", Constraint: "jobs_queue_fk"}, CodeValidation, false},
+		{"not null violation", &pq.Error{Code: "23502", Column: "type"}, CodeValidation, false},
+		{"serialization failure", &pq.Error{Code: "40001"}, CodeDatabase, true},
+		{"deadlock", &pq.Error{Code: "40P01"}, CodeDatabase, true},
+		{"statement timeout", &pq.Error{Code: "57014"}, CodeTimeout, true},
+		{"connection exception", &pq.Error{Code: "08006"}, CodeNetwork, true},
+		{"admin shutdown", &pq.Error{Code: "57P01"}, CodeNetwork, true},
+		{"syntax error", &pq.Error{Code: "42601"}, CodeDatabase, true},
+		{"no rows", sql.ErrNoRows, CodeNotFound, false},
+		{"wrapped no rows", fmt.Errorf("scan: %w", sql.ErrNoRows), CodeNotFound, false},
+		{"bad connection", driver.ErrBadConn, CodeNetwork, true},
+		{"connection reset", io.ErrUnexpectedEOF, CodeNetwork, true},
+		{"dial failure", &net.OpError{Op: "dial", Net: "tcp", Err: os.ErrNotExist}, CodeNetwork, true},
+		{"context deadline", context.DeadlineExceeded, CodeTimeout, true},
+		{"context canceled", context.Canceled, CodeCanceled, false},
+		{"other", io.ErrShortWrite, CodeDatabase, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromPostgres(tt.err)
+			require.NotNil(t, err)
+
+			assert.Equal(t, tt.code, err.Code)
+			assert.ErrorIs(t, err, tt.err, "the driver error stays in the chain")
+
+			retryable, known := IsRetryable(err)
+			assert.True(t, known)
+			assert.Equal(t, tt.retryable, retryable)
+		})
+	}
+}
+
+func TestFromPostgres_Metadata(t *testing.T) {
+	err := FromPostgres(&pq.Error{
+		Code:       "23505",
+		Constraint: "jobs_pkey",
+		Table:      "jobs",
+	})
+
+	assert.Equal(t, "23505", err.Metadata["sqlstate"])
+	assert.Equal(t, "jobs_pkey", err.Metadata["constraint"])
+	assert.Equal(t, "jobs", err.Metadata["table"])
+	assert.NotContains(t, err.Metadata, "column")
+	assert.Equal(t, 409, err.HTTPStatus())
+}
+
+func TestFromRedis(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code Code
+	}{
+		{"nil reply", redis.Nil, CodeNotFound},
+		{"wrapped nil reply", fmt.Errorf("get: %w", redis.Nil), CodeNotFound},
+		{"pool timeout", redis.ErrPoolTimeout, CodeRateLimit},
+		{"pool exhausted", redis.ErrPoolExhausted, CodeRateLimit},
+		{"network timeout", netTimeout, CodeTimeout},
+		{"context deadline", context.DeadlineExceeded, CodeTimeout},
+		{"context canceled", context.Canceled, CodeCanceled},
+		{"client closed", redis.ErrClosed, CodeInternal},
+		{"loading", redisReply("LOADING Redis is loading the dataset in memory"), CodeNetwork},
+		{"readonly replica", redisReply("READONLY You can't write against a read only replica."), CodeNetwork},
+		{"wrong type", redisReply("WRONGTYPE Operation against a key holding the wrong kind of value"), CodeInternal},
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: os.ErrNotExist}, CodeNetwork},
+		{"connection reset", io.EOF, CodeNetwork},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromRedis(tt.err)
+			require.NotNil(t, err)
+
+			assert.Equal(t, tt.code, err.Code)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestFromDriver_PassThrough(t *testing.T) {
+	assert.Nil(t, FromPostgres(nil))
+	assert.Nil(t, FromRedis(nil))
+
+	existing := NotFound("job not found")
+	assert.Same(t, existing, FromPostgres(existing))
+	assert.Same(t, existing, FromRedis(existing))
+}
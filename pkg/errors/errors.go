@@ -1,11 +1,11 @@
 package errors
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
-	"runtime"
 )
 
 // New creates a new error with a message
@@ -14,7 +14,7 @@ func New(message string) *Error {
 		Code:     CodeUnknown,
 		Message:  message,
 		Metadata: make(map[string]any),
-		Stack:    captureStack(2),
+		stack:    captureStack(2),
 	}
 }
 
@@ -23,7 +23,9 @@ func Newf(format string, args ...any) *Error {
 	return New(fmt.Sprintf(format, args...))
 }
 
-// Wrap wraps an existing error with additional context
+// Wrap wraps an existing error with additional context. When err already
+// contains an *Error its stack is reused rather than captured again, so a
+// chain of wraps costs a single capture and traces point at the origin.
 func Wrap(err error, message string) *Error {
 	if err == nil {
 		return nil
@@ -37,7 +39,7 @@ func Wrap(err error, message string) *Error {
 			Cause:      err,
 			StatusCode: appErr.StatusCode,
 			Metadata:   copyMetadata(appErr.Metadata),
-			Stack:      captureStack(2),
+			stack:      appErr.stack,
 		}
 	}
 
@@ -46,7 +48,7 @@ func Wrap(err error, message string) *Error {
 		Message:  message,
 		Cause:    err,
 		Metadata: make(map[string]any),
-		Stack:    captureStack(2),
+		stack:    captureStack(2),
 	}
 }
 
@@ -107,13 +109,54 @@ func (e *Error) WithMetadataMap(metadata map[string]any) *Error {
 	return e
 }
 
-// Error implements the error interface
+// WithOp records the operation that produced the error, e.g. "queue.Enqueue"
+func (e *Error) WithOp(op string) *Error {
+	if e == nil {
+		return nil
+	}
+
+	e.Op = op
+	return e
+}
+
+// WithComponent records the subsystem that produced the error
+func (e *Error) WithComponent(name string) *Error {
+	if e == nil {
+		return nil
+	}
+
+	e.Component = name
+	return e
+}
+
+// Error implements the error interface. The operation label, if any,
+// prefixes the message, so wrapped errors read as an op chain such as
+// "queue.Enqueue: storage.Create: job already exists".
 func (e *Error) Error() string {
+	message := e.Message
+	if label := e.opLabel(); label != "" {
+		message = label + ": " + message
+	}
+
 	if e.Cause != nil {
-		return fmt.Sprintf("%s: %v", e.Message, e.Cause)
+		return fmt.Sprintf("%s: %v", message, e.Cause)
 	}
 
-	return e.Message
+	return message
+}
+
+// opLabel joins the component and operation, e.g. "queue.Enqueue"
+func (e *Error) opLabel() string {
+	switch {
+	case e.Component == "":
+		return e.Op
+
+	case e.Op == "":
+		return e.Component
+
+	default:
+		return e.Component + "." + e.Op
+	}
 }
 
 // Unwrap returns the underlying error
@@ -121,11 +164,28 @@ func (e *Error) Unwrap() error {
 	return e.Cause
 }
 
-// Is implements errors.Is interface
+// Is implements errors.Is interface. An *Error matches another *Error with
+// the same code, and a timeout or canceled error matches
+// context.DeadlineExceeded or context.Canceled respectively, so code written
+// against the context package recognizes typed errors. Any other target
+// matches when it is found anywhere in the cause chain, so calling Is
+// directly behaves like errors.Is.
 func (e *Error) Is(target error) bool {
+	switch target {
+	case context.DeadlineExceeded:
+		if e.Code == CodeTimeout {
+			return true
+		}
+
+	case context.Canceled:
+		if e.Code == CodeCanceled {
+			return true
+		}
+	}
+
 	t, ok := target.(*Error)
 	if !ok {
-		return false
+		return target != nil && errors.Is(e.Cause, target)
 	}
 
 	return e.Code == t.Code
@@ -140,35 +200,15 @@ func (e *Error) HTTPStatus() int {
 	return codeToHTTPStatus(e.Code)
 }
 
-// ToJSON converts the error to JSON
-func (e *Error) ToJSON() ([]byte, error) {
-	return json.Marshal(e)
-}
-
-// captureStack captures the current stack trace
-func captureStack(skip int) []Frame {
-	const maxStackDepth = 32
-	frames := make([]Frame, 0, maxStackDepth)
-	pcs := make([]uintptr, maxStackDepth)
-	n := runtime.Callers(skip+1, pcs)
-
-	for i := 0; i < n; i++ {
-		pc := pcs[i]
-		fn := runtime.FuncForPC(pc)
-
-		if fn == nil {
-			continue
-		}
-
-		file, line := fn.FileLine(pc)
-		frames = append(frames, Frame{
-			Function: fn.Name(),
-			File:     file,
-			Line:     line,
-		})
+// ToJSON converts the error to JSON. Stack frames are omitted unless
+// WithStackTrace is passed.
+func (e *Error) ToJSON(opts ...JSONOption) ([]byte, error) {
+	options := jsonOptions{}
+	for _, opt := range opts {
+		opt(&options)
 	}
 
-	return frames
+	return json.Marshal(e.toJSONError(options))
 }
 
 // copyMetadata creates a copy of metadata map
@@ -286,6 +326,22 @@ func IsConflict(err error) bool {
 	return false
 }
 
+// Ops returns the operation labels found along the error chain, outermost
+// first, e.g. ["queue.Enqueue", "storage.Create"]. Errors without an
+// operation are skipped.
+func Ops(err error) []string {
+	var ops []string
+	for ; err != nil; err = errors.Unwrap(err) {
+		if e, ok := err.(*Error); ok {
+			if label := e.opLabel(); label != "" {
+				ops = append(ops, label)
+			}
+		}
+	}
+
+	return ops
+}
+
 // GetCode returns the error code from an error
 func GetCode(err error) Code {
 	var e *Error
@@ -302,6 +358,6 @@ func GetHTTPStatus(err error) int {
 	if errors.As(err, &e) {
 		return e.HTTPStatus()
 	}
-	
+
 	return http.StatusInternalServerError
 }
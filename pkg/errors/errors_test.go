@@ -16,7 +16,7 @@ func TestNew(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, "test error", err.Message)
 	assert.Equal(t, CodeUnknown, err.Code)
-	assert.NotEmpty(t, err.Stack)
+	assert.NotEmpty(t, err.StackTrace())
 	assert.NotNil(t, err.Metadata)
 }
 
@@ -34,7 +34,7 @@ func TestWrap(t *testing.T) {
 	assert.NotNil(t, wrapped)
 	assert.Equal(t, "wrapped message", wrapped.Message)
 	assert.Equal(t, stdErr, wrapped.Cause)
-	assert.NotEmpty(t, wrapped.Stack)
+	assert.NotEmpty(t, wrapped.StackTrace())
 
 	// Test wrapping nil
 	assert.Nil(t, Wrap(nil, "message"))
@@ -0,0 +1,56 @@
+package errors
+
+import (
+	"errors"
+	"sort"
+)
+
+// Fields returns the error as key/value pairs for structured loggers such as
+// logger.Logger: error.code, error.message, error.op when the chain carries an
+// operation, and one error.metadata.<key> pair per metadata entry, redacted as
+// in SafeMetadata. With WithStackTrace it also adds error.stack. Errors from
+// outside this package produce only error.message. Fields returns nil for a
+// nil error.
+func Fields(err error, opts ...JSONOption) []any {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if !errors.As(err, &e) {
+		return []any{"error.message", err.Error()}
+	}
+
+	options := jsonOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	fields := []any{
+		"error.code", string(e.Code),
+		"error.message", err.Error(),
+	}
+
+	if ops := Ops(err); len(ops) > 0 {
+		fields = append(fields, "error.op", ops[0])
+	}
+
+	metadata := e.SafeMetadata()
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		fields = append(fields, "error.metadata."+key, metadata[key])
+	}
+
+	if options.stack {
+		if stack := e.StackString(); stack != "" {
+			fields = append(fields, "error.stack", stack)
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fieldMap converts key/value pairs into a map
+func fieldMap(t *testing.T, fields []any) map[string]any {
+	t.Helper()
+
+	require.Zero(t, len(fields)%2, "fields come in pairs")
+	m := make(map[string]any, len(fields)/2)
+	for i := 0; i < len(fields); i += 2 {
+		m[fields[i].(string)] = fields[i+1]
+	}
+
+	return m
+}
+
+func TestFields(t *testing.T) {
+	inner := NotFound("job not found").
+		WithOp("storage.Get").
+		WithMetadata("job_id", "42").
+		WithMetadata("token", "t1")
+	err := Wrap(inner, "dequeue failed").WithOp("queue.Dequeue")
+
+	fields := Fields(err)
+	assert.Equal(t, []any{
+		"error.code", "NOT_FOUND",
+		"error.message", err.Error(),
+		"error.op", "queue.Dequeue",
+		"error.metadata.job_id", "42",
+		"error.metadata.token", RedactedValue,
+	}, fields)
+}
+
+func TestFields_Stack(t *testing.T) {
+	err := Internal("boom")
+
+	assert.NotContains(t, fieldMap(t, Fields(err)), "error.stack")
+
+	stack := fieldMap(t, Fields(err, WithStackTrace()))["error.stack"]
+	assert.Contains(t, stack, "TestFields_Stack")
+}
+
+func TestFields_PlainAndNil(t *testing.T) {
+	assert.Nil(t, Fields(nil))
+	assert.Equal(t, []any{"error.message", "EOF"}, Fields(io.EOF))
+}
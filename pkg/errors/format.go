@@ -0,0 +1,108 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// packagePrefix is the function name prefix of frames inside this package
+const packagePrefix = "task-queue/pkg/errors."
+
+// Format implements fmt.Formatter. %s and %v print the message and cause,
+// %q prints them quoted, %+v prints every error in the chain with its code
+// and stack trace, and %#v prints the error as a Go struct literal.
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			e.writeChain(s)
+			return
+		}
+
+		if s.Flag('#') {
+			fmt.Fprintf(s, "&errors.Error{Code:%q, Message:%q, StatusCode:%d, Metadata:%#v, Cause:%#v}",
+				e.Code, e.Message, e.StatusCode, e.SafeMetadata(), e.Cause)
+			return
+		}
+
+		_, _ = io.WriteString(s, e.Error())
+
+	case 's':
+		_, _ = io.WriteString(s, e.Error())
+
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+
+	default:
+		fmt.Fprintf(s, "%%!%c(*errors.Error=%s)", verb, e.Error())
+	}
+}
+
+// StackString returns the captured stack trace, one frame per two lines in
+// the format of Go panics. Frames from inside this package are omitted.
+func (e *Error) StackString() string {
+	var b strings.Builder
+	for _, frame := range trimPackageFrames(e.StackTrace()) {
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+	}
+
+	return b.String()
+}
+
+// writeChain writes every error in the unwrap chain with its code and stack
+func (e *Error) writeChain(w io.Writer) {
+	var err error = e
+	for i := 0; err != nil; i++ {
+		if i > 0 {
+			_, _ = io.WriteString(w, "\ncaused by: ")
+		}
+
+		appErr, ok := err.(*Error)
+		if !ok {
+			_, _ = io.WriteString(w, err.Error())
+			err = errors.Unwrap(err)
+			continue
+		}
+
+		message := appErr.Message
+		if label := appErr.opLabel(); label != "" {
+			message = label + ": " + message
+		}
+
+		fmt.Fprintf(w, "%s [%s]\n", message, appErr.Code)
+		if !appErr.sharesStackWithCause() {
+			_, _ = io.WriteString(w, appErr.StackString())
+		}
+
+		err = appErr.Cause
+	}
+}
+
+// sharesStackWithCause reports whether the error reuses the stack of an
+// *Error further down the chain, which then prints it instead
+func (e *Error) sharesStackWithCause() bool {
+	var cause *Error
+	return e.stack != nil && errors.As(e.Cause, &cause) && cause.stack == e.stack
+}
+
+// trimPackageFrames drops the leading frames that belong to this package, so
+// traces start at the caller of New or Wrap
+func trimPackageFrames(frames []Frame) []Frame {
+	for len(frames) > 0 && isPackageFrame(frames[0]) {
+		frames = frames[1:]
+	}
+
+	return frames
+}
+
+// isPackageFrame reports whether a frame belongs to this package's non-test
+// code
+func isPackageFrame(frame Frame) bool {
+	if !strings.HasPrefix(frame.Function, packagePrefix) {
+		return false
+	}
+
+	return !strings.HasSuffix(frame.File, "_test.go")
+}
@@ -0,0 +1,85 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// formatFixture builds a two-level error chain with fixed stack frames
+func formatFixture() *Error {
+	inner := Wrap(io.ErrUnexpectedEOF, "failed to read job").WithCode(CodeSerialization)
+	inner.stack = resolvedStack([]Frame{
+		{Function: "task-queue/pkg/errors.Wrap", File: "/src/pkg/errors/errors.go", Line: 30},
+		{Function: "task-queue/internal/queue.(*RedisQueue).Dequeue", File: "/src/internal/queue/redis.go", Line: 175},
+	})
+
+	outer := Wrap(fmt.Errorf("dequeue: %w", inner), "worker failed").WithCode(CodeInternal)
+	outer.stack = resolvedStack([]Frame{
+		{Function: "task-queue/internal/worker.(*Worker).poll", File: "/src/internal/worker/worker.go", Line: 88},
+		{Function: "task-queue/internal/worker.(*Worker).Run", File: "/src/internal/worker/worker.go", Line: 51},
+	})
+
+	return outer
+}
+
+func TestFormat_Verbs(t *testing.T) {
+	err := formatFixture()
+	message := "worker failed: dequeue: failed to read job: unexpected EOF"
+
+	assert.Equal(t, message, fmt.Sprintf("%s", err))
+	assert.Equal(t, message, fmt.Sprintf("%v", err))
+	assert.Equal(t, `"`+message+`"`, fmt.Sprintf("%q", err))
+	assert.Equal(t, "%!d(*errors.Error="+message+")", fmt.Sprintf("%d", err))
+}
+
+func TestFormat_PlusV(t *testing.T) {
+	expected := `worker failed [INTERNAL]
+task-queue/internal/worker.(*Worker).poll
+	/src/internal/worker/worker.go:88
+task-queue/internal/worker.(*Worker).Run
+	/src/internal/worker/worker.go:51
+
+caused by: dequeue: failed to read job: unexpected EOF
+caused by: failed to read job [SERIALIZATION_ERROR]
+task-queue/internal/queue.(*RedisQueue).Dequeue
+	/src/internal/queue/redis.go:175
+
+caused by: unexpected EOF`
+
+	assert.Equal(t, expected, fmt.Sprintf("%+v", formatFixture()))
+}
+
+func TestFormat_SharpV(t *testing.T) {
+	err := New("bad priority").WithCode(CodeValidation).WithMetadata("field", "priority")
+
+	assert.Equal(t,
+		`&errors.Error{Code:"VALIDATION", Message:"bad priority", StatusCode:400, `+
+			`Metadata:map[string]interface {}{"field":"priority"}, Cause:<nil>}`,
+		fmt.Sprintf("%#v", err))
+}
+
+func TestStackString_CapturedStack(t *testing.T) {
+	err := Newf("job %d failed", 42)
+
+	stack := err.StackString()
+	require.NotEmpty(t, stack)
+
+	// Normalize absolute paths so the output does not depend on the checkout
+	normalized := regexp.MustCompile(`\t\S*/([^/\s]+):\d+`).ReplaceAllString(stack, "\t$1:N")
+
+	lines := strings.Split(normalized, "\n")
+	assert.Equal(t, "task-queue/pkg/errors.TestStackString_CapturedStack", lines[0],
+		"frames from Newf and New are trimmed")
+	assert.Equal(t, "\tformat_test.go:N", lines[1])
+	assert.NotContains(t, stack, "errors.Newf")
+}
+
+func TestStackString_Empty(t *testing.T) {
+	assert.Empty(t, (&Error{Message: "no stack"}).StackString())
+}
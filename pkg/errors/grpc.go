@@ -0,0 +1,207 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain identifies ErrorInfo details produced by this package
+const ErrorDomain = "task-queue"
+
+// GRPCStatus converts the error into a gRPC status. The error code and
+// metadata are attached as an ErrorInfo detail so FromGRPCStatus can restore
+// them; sensitive values are redacted as in SafeMetadata. Because the method
+// matches the interface gRPC looks for, an *Error returned from a handler is
+// sent with the right status code.
+func (e *Error) GRPCStatus() *status.Status {
+	if e == nil {
+		return status.New(codes.OK, "")
+	}
+
+	st := status.New(codeToGRPC(e.Code), e.Error())
+
+	info := &errdetails.ErrorInfo{
+		Reason:   string(e.Code),
+		Domain:   ErrorDomain,
+		Metadata: make(map[string]string, len(e.Metadata)),
+	}
+
+	for key, value := range e.SafeMetadata() {
+		info.Metadata[key] = metadataString(value)
+	}
+
+	withDetails, err := st.WithDetails(info)
+	if err != nil {
+		return st
+	}
+
+	return withDetails
+}
+
+// FromGRPCStatus reconstructs an *Error from a gRPC status. When the status
+// carries an ErrorInfo detail from this package the original code and
+// metadata are restored; metadata values come back as strings. It returns nil
+// for a nil or OK status.
+func FromGRPCStatus(st *status.Status) *Error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	e := &Error{
+		Code:     grpcToCode(st.Code()),
+		Message:  st.Message(),
+		Metadata: make(map[string]any),
+		stack:    captureStack(2),
+	}
+
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != ErrorDomain {
+			continue
+		}
+
+		e.Code = Code(info.GetReason())
+		for key, value := range info.GetMetadata() {
+			e.Metadata[key] = value
+		}
+	}
+
+	e.StatusCode = codeToHTTPStatus(e.Code)
+	return e
+}
+
+// ToGRPCError converts any error into one carrying a gRPC status. Errors from
+// this package keep their code and metadata, errors that already carry a
+// status are returned unchanged, context errors map to DeadlineExceeded and
+// Canceled, and anything else becomes Unknown.
+func ToGRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return e.GRPCStatus().Err()
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+
+	default:
+		return status.Error(codes.Unknown, err.Error())
+	}
+}
+
+// metadataString renders a metadata value for an ErrorInfo detail
+func metadataString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(data)
+}
+
+// codeToGRPC maps error codes to gRPC status codes
+func codeToGRPC(code Code) codes.Code {
+	switch code {
+	case CodeValidation:
+		return codes.InvalidArgument
+
+	case CodeNotFound:
+		return codes.NotFound
+
+	case CodeAlreadyExists:
+		return codes.AlreadyExists
+
+	case CodeConflict:
+		return codes.Aborted
+
+	case CodePermission:
+		return codes.PermissionDenied
+
+	case CodeAuthentication:
+		return codes.Unauthenticated
+
+	case CodeRateLimit:
+		return codes.ResourceExhausted
+
+	case CodeTimeout:
+		return codes.DeadlineExceeded
+
+	case CodeCanceled:
+		return codes.Canceled
+
+	case CodeNetwork:
+		return codes.Unavailable
+
+	case CodeInternal, CodeDatabase, CodeSerialization, CodeConfiguration:
+		return codes.Internal
+
+	default:
+		return codes.Unknown
+	}
+}
+
+// grpcToCode maps gRPC status codes to error codes
+func grpcToCode(code codes.Code) Code {
+	switch code {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return CodeValidation
+
+	case codes.NotFound:
+		return CodeNotFound
+
+	case codes.AlreadyExists:
+		return CodeAlreadyExists
+
+	case codes.Aborted:
+		return CodeConflict
+
+	case codes.PermissionDenied:
+		return CodePermission
+
+	case codes.Unauthenticated:
+		return CodeAuthentication
+
+	case codes.ResourceExhausted:
+		return CodeRateLimit
+
+	case codes.DeadlineExceeded:
+		return CodeTimeout
+
+	case codes.Canceled:
+		return CodeCanceled
+
+	case codes.Unavailable:
+		return CodeNetwork
+
+	case codes.Internal, codes.DataLoss, codes.Unimplemented:
+		return CodeInternal
+
+	default:
+		return CodeUnknown
+	}
+}
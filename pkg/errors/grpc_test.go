@@ -0,0 +1,132 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCStatus_RoundTrip(t *testing.T) {
+	tests := []struct {
+		code     Code
+		grpcCode codes.Code
+	}{
+		{CodeUnknown, codes.Unknown},
+		{CodeInternal, codes.Internal},
+		{CodeValidation, codes.InvalidArgument},
+		{CodeNotFound, codes.NotFound},
+		{CodeAlreadyExists, codes.AlreadyExists},
+		{CodePermission, codes.PermissionDenied},
+		{CodeAuthentication, codes.Unauthenticated},
+		{CodeRateLimit, codes.ResourceExhausted},
+		{CodeTimeout, codes.DeadlineExceeded},
+		{CodeCanceled, codes.Canceled},
+		{CodeConflict, codes.Aborted},
+		{CodeDatabase, codes.Internal},
+		{CodeNetwork, codes.Unavailable},
+		{CodeSerialization, codes.Internal},
+		{CodeConfiguration, codes.Internal},
+		{Code("CUSTOM_CODE"), codes.Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			original := New("job lookup failed").
+				WithCode(tt.code).
+				WithMetadata("job_id", "42").
+				WithMetadata("attempt", 3).
+				WithMetadata("queues", []string{"high", "low"})
+
+			st := original.GRPCStatus()
+			assert.Equal(t, tt.grpcCode, st.Code())
+			assert.Equal(t, "job lookup failed", st.Message())
+
+			restored := FromGRPCStatus(st)
+			require.NotNil(t, restored)
+			assert.Equal(t, tt.code, restored.Code)
+			assert.Equal(t, original.Message, restored.Message)
+			assert.Equal(t, original.HTTPStatus(), restored.HTTPStatus())
+			assert.Equal(t, map[string]any{
+				"job_id":  "42",
+				"attempt": "3",
+				"queues":  `["high","low"]`,
+			}, restored.Metadata)
+		})
+	}
+}
+
+func TestFromGRPCStatus_WithoutDetails(t *testing.T) {
+	tests := []struct {
+		grpcCode codes.Code
+		code     Code
+	}{
+		{codes.InvalidArgument, CodeValidation},
+		{codes.FailedPrecondition, CodeValidation},
+		{codes.NotFound, CodeNotFound},
+		{codes.Aborted, CodeConflict},
+		{codes.Unavailable, CodeNetwork},
+		{codes.DataLoss, CodeInternal},
+		{codes.Unknown, CodeUnknown},
+		{codes.Code(99), CodeUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.grpcCode.String(), func(t *testing.T) {
+			e := FromGRPCStatus(status.New(tt.grpcCode, "remote failure"))
+
+			require.NotNil(t, e)
+			assert.Equal(t, tt.code, e.Code)
+			assert.Equal(t, "remote failure", e.Message)
+			assert.Empty(t, e.Metadata)
+		})
+	}
+
+	assert.Nil(t, FromGRPCStatus(nil))
+	assert.Nil(t, FromGRPCStatus(status.New(codes.OK, "")))
+}
+
+func TestToGRPCError(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		assert.NoError(t, ToGRPCError(nil))
+	})
+
+	t.Run("wrapped app error", func(t *testing.T) {
+		appErr := New("job not found").WithCode(CodeNotFound)
+		err := ToGRPCError(fmt.Errorf("handler: %w", appErr))
+
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, codes.NotFound, st.Code())
+		assert.Equal(t, CodeNotFound, FromGRPCStatus(st).Code)
+	})
+
+	t.Run("existing status", func(t *testing.T) {
+		original := status.Error(codes.Unimplemented, "not here")
+		assert.Equal(t, original, ToGRPCError(original))
+	})
+
+	t.Run("context errors", func(t *testing.T) {
+		assert.Equal(t, codes.DeadlineExceeded,
+			status.Code(ToGRPCError(fmt.Errorf("query: %w", context.DeadlineExceeded))))
+		assert.Equal(t, codes.Canceled, status.Code(ToGRPCError(context.Canceled)))
+	})
+
+	t.Run("plain error", func(t *testing.T) {
+		err := ToGRPCError(errors.New("boom"))
+
+		assert.Equal(t, codes.Unknown, status.Code(err))
+		assert.Equal(t, "boom", status.Convert(err).Message())
+	})
+}
+
+func TestGRPCStatus_UsedByStatusPackage(t *testing.T) {
+	err := Wrap(New("duplicate job").WithCode(CodeAlreadyExists), "enqueue failed")
+
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+}
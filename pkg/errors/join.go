@@ -0,0 +1,120 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// severity ranks codes for aggregate errors, most severe first. Server-side
+// failures outrank client errors so that a batch containing both reports a
+// 5xx status. Codes not listed rank below all listed codes.
+var severity = []Code{
+	CodeInternal,
+	CodeUnknown,
+	CodeDatabase,
+	CodeConfiguration,
+	CodeSerialization,
+	CodeNetwork,
+	CodeTimeout,
+	CodeCanceled,
+	CodeRateLimit,
+	CodeAuthentication,
+	CodePermission,
+	CodeConflict,
+	CodeAlreadyExists,
+	CodeNotFound,
+	CodeValidation,
+}
+
+// joinedErrors is the cause of an aggregate error. It implements
+// Unwrap() []error so errors.Is and errors.As reach every child.
+type joinedErrors []error
+
+// Error joins the child messages
+func (j joinedErrors) Error() string {
+	messages := make([]string, len(j))
+	for i, err := range j {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the children
+func (j joinedErrors) Unwrap() []error {
+	return j
+}
+
+// Join aggregates several errors into one. Nil errors are dropped; Join
+// returns nil when none remain and a lone *Error unchanged. Otherwise the
+// aggregate takes the most severe child code, summarizes the count in its
+// message, and lists each child's code, message, and metadata under the
+// "errors" metadata key.
+func Join(errs ...error) *Error {
+	children := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			children = append(children, err)
+		}
+	}
+
+	if len(children) == 0 {
+		return nil
+	}
+
+	if len(children) == 1 {
+		if e, ok := children[0].(*Error); ok {
+			return e
+		}
+	}
+
+	details := make([]map[string]any, len(children))
+	code := Code("")
+	for i, child := range children {
+		childCode := GetCode(child)
+		if code == "" || moreSevere(childCode, code) {
+			code = childCode
+		}
+
+		detail := map[string]any{
+			"code":    childCode,
+			"message": child.Error(),
+		}
+
+		var e *Error
+		if errors.As(child, &e) && len(e.Metadata) > 0 {
+			detail["metadata"] = e.Metadata
+		}
+
+		details[i] = detail
+	}
+
+	message := "1 error occurred"
+	if len(children) > 1 {
+		message = fmt.Sprintf("%d errors occurred", len(children))
+	}
+
+	return (&Error{
+		Message:  message,
+		Cause:    joinedErrors(children),
+		Metadata: map[string]any{"errors": details},
+		stack:    captureStack(2),
+	}).WithCode(code)
+}
+
+// moreSevere reports whether a ranks above b in the severity ordering
+func moreSevere(a, b Code) bool {
+	return severityRank(a) < severityRank(b)
+}
+
+// severityRank returns the position of code in the severity ordering
+func severityRank(code Code) int {
+	for i, c := range severity {
+		if c == code {
+			return i
+		}
+	}
+
+	return len(severity)
+}
@@ -0,0 +1,129 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoin_NilFiltering(t *testing.T) {
+	assert.Nil(t, Join())
+	assert.Nil(t, Join(nil, nil))
+
+	joined := Join(nil, io.EOF, nil, io.ErrUnexpectedEOF)
+	require.NotNil(t, joined)
+	assert.Equal(t, "2 errors occurred", joined.Message)
+	assert.Len(t, joined.Metadata["errors"], 2)
+}
+
+func TestJoin_SingleChild(t *testing.T) {
+	child := New("bad priority").WithCode(CodeValidation)
+	assert.Same(t, child, Join(nil, child))
+
+	plain := Join(io.EOF)
+	require.NotNil(t, plain)
+	assert.Equal(t, "1 error occurred", plain.Message)
+	assert.Equal(t, CodeUnknown, plain.Code)
+	assert.ErrorIs(t, plain, io.EOF)
+}
+
+func TestJoin_IsAndAsFindChildren(t *testing.T) {
+	notFound := New("job missing").WithCode(CodeNotFound).WithMetadata("job_id", "42")
+	joined := Join(io.EOF, fmt.Errorf("lookup: %w", notFound))
+
+	assert.ErrorIs(t, joined, io.EOF)
+	assert.True(t, errors.Is(joined, notFound))
+
+	var target *Error
+	require.True(t, errors.As(joined.Cause, &target))
+	assert.Same(t, notFound, target)
+
+	assert.Equal(t, "2 errors occurred: EOF; lookup: job missing", joined.Error())
+}
+
+func TestJoin_CodeAndStatusSelection(t *testing.T) {
+	tests := []struct {
+		name   string
+		errs   []error
+		code   Code
+		status int
+	}{
+		{
+			name:   "all validation",
+			errs:   []error{New("a").WithCode(CodeValidation), New("b").WithCode(CodeValidation)},
+			code:   CodeValidation,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "not found outranks validation",
+			errs:   []error{New("a").WithCode(CodeValidation), New("b").WithCode(CodeNotFound)},
+			code:   CodeNotFound,
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "database outranks client errors",
+			errs:   []error{New("a").WithCode(CodeValidation), New("b").WithCode(CodeDatabase)},
+			code:   CodeDatabase,
+			status: http.StatusInternalServerError,
+		},
+		{
+			name:   "plain error counts as unknown",
+			errs:   []error{New("a").WithCode(CodeRateLimit), io.EOF},
+			code:   CodeUnknown,
+			status: http.StatusInternalServerError,
+		},
+		{
+			name:   "unlisted code ranks last",
+			errs:   []error{New("a").WithCode(Code("CUSTOM")), New("b").WithCode(CodeValidation)},
+			code:   CodeValidation,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			joined := Join(tt.errs...)
+
+			assert.Equal(t, tt.code, joined.Code)
+			assert.Equal(t, tt.status, joined.HTTPStatus())
+			assert.Equal(t, tt.status, GetHTTPStatus(joined))
+		})
+	}
+}
+
+func TestJoin_ToJSONRendersChildren(t *testing.T) {
+	joined := Join(
+		New("priority out of range").WithCode(CodeValidation).WithMetadata("field", "priority"),
+		io.EOF,
+	)
+
+	data, err := joined.ToJSON()
+	require.NoError(t, err)
+
+	var decoded struct {
+		Code     Code `json:"code"`
+		Message  string
+		Metadata struct {
+			Errors []struct {
+				Code     Code           `json:"code"`
+				Message  string         `json:"message"`
+				Metadata map[string]any `json:"metadata"`
+			} `json:"errors"`
+		} `json:"metadata"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	assert.Equal(t, CodeUnknown, decoded.Code)
+	require.Len(t, decoded.Metadata.Errors, 2)
+	assert.Equal(t, CodeValidation, decoded.Metadata.Errors[0].Code)
+	assert.Equal(t, "priority out of range", decoded.Metadata.Errors[0].Message)
+	assert.Equal(t, map[string]any{"field": "priority"}, decoded.Metadata.Errors[0].Metadata)
+	assert.Equal(t, CodeUnknown, decoded.Metadata.Errors[1].Code)
+	assert.Nil(t, decoded.Metadata.Errors[1].Metadata)
+}
@@ -0,0 +1,143 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// jsonError is the wire representation of an *Error. The cause is carried as
+// text: Cause holds the full cause message and CauseChain the message of each
+// link in the unwrap chain, outermost first.
+type jsonError struct {
+	Code       Code           `json:"code"`
+	Message    string         `json:"message"`
+	StatusCode int            `json:"status_code,omitempty"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+	Retryable  *bool          `json:"retryable,omitempty"`
+	Op         string         `json:"op,omitempty"`
+	Component  string         `json:"component,omitempty"`
+	Key        string         `json:"key,omitempty"`
+	Args       []any          `json:"args,omitempty"`
+	Cause      string         `json:"cause,omitempty"`
+	CauseChain []string       `json:"cause_chain,omitempty"`
+	Stack      []Frame        `json:"stack,omitempty"`
+}
+
+// remoteCause stands in for a cause decoded from JSON. It reproduces the
+// original messages and unwrap chain, but not the original error types.
+type remoteCause struct {
+	message string
+	cause   error
+}
+
+// Error returns the original cause message
+func (c *remoteCause) Error() string {
+	return c.message
+}
+
+// Unwrap returns the next decoded cause
+func (c *remoteCause) Unwrap() error {
+	return c.cause
+}
+
+// WithStackTrace includes stack frames in the encoded error or log fields.
+// They are omitted by default so responses don't leak file paths.
+func WithStackTrace() JSONOption {
+	return func(o *jsonOptions) {
+		o.stack = true
+	}
+}
+
+// MarshalJSON encodes the error with its cause chain and without stack frames
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.toJSONError(jsonOptions{}))
+}
+
+// UnmarshalJSON decodes an error produced by MarshalJSON or ToJSON. Unknown
+// fields are ignored.
+func (e *Error) UnmarshalJSON(data []byte) error {
+	var decoded jsonError
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*e = Error{
+		Code:       decoded.Code,
+		Message:    decoded.Message,
+		StatusCode: decoded.StatusCode,
+		Metadata:   decoded.Metadata,
+		stack:      resolvedStack(decoded.Stack),
+		Retryable:  decoded.Retryable,
+		Op:         decoded.Op,
+		Component:  decoded.Component,
+		Key:        decoded.Key,
+		Args:       decoded.Args,
+		Cause:      decodeCause(decoded),
+	}
+
+	if e.Code == "" {
+		e.Code = CodeUnknown
+	}
+
+	if e.Metadata == nil {
+		e.Metadata = make(map[string]any)
+	}
+
+	return nil
+}
+
+// FromJSON reconstructs an error encoded by ToJSON. The cause is rebuilt as a
+// chain of errors carrying the original messages.
+func FromJSON(data []byte) (*Error, error) {
+	var e Error
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, Wrap(err, "failed to decode error").
+			WithCode(CodeSerialization)
+	}
+
+	return &e, nil
+}
+
+// toJSONError builds the wire representation of the error. Sensitive
+// metadata is redacted.
+func (e *Error) toJSONError(options jsonOptions) jsonError {
+	encoded := jsonError{
+		Code:       e.Code,
+		Message:    e.Message,
+		StatusCode: e.StatusCode,
+		Metadata:   e.SafeMetadata(),
+		Retryable:  e.Retryable,
+		Op:         e.Op,
+		Component:  e.Component,
+		Key:        e.Key,
+		Args:       e.Args,
+	}
+
+	if options.stack {
+		encoded.Stack = e.StackTrace()
+	}
+
+	if e.Cause != nil {
+		encoded.Cause = e.Cause.Error()
+		for cause := e.Cause; cause != nil; cause = errors.Unwrap(cause) {
+			encoded.CauseChain = append(encoded.CauseChain, cause.Error())
+		}
+	}
+
+	return encoded
+}
+
+// decodeCause rebuilds the cause chain of a decoded error
+func decodeCause(decoded jsonError) error {
+	chain := decoded.CauseChain
+	if len(chain) == 0 && decoded.Cause != "" {
+		chain = []string{decoded.Cause}
+	}
+
+	var cause error
+	for i := len(chain) - 1; i >= 0; i-- {
+		cause = &remoteCause{message: chain[i], cause: cause}
+	}
+
+	return cause
+}
@@ -0,0 +1,105 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSON_RoundTrip(t *testing.T) {
+	original := New("job rejected").
+		WithCode(CodeValidation).
+		WithStatusCode(http.StatusUnprocessableEntity).
+		WithMetadata("job_id", "42").
+		WithRetryable(false)
+
+	data, err := original.ToJSON()
+	require.NoError(t, err)
+
+	decoded, err := FromJSON(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, original.Code, decoded.Code)
+	assert.Equal(t, original.Message, decoded.Message)
+	assert.Equal(t, original.StatusCode, decoded.StatusCode)
+	assert.Equal(t, original.Metadata, decoded.Metadata)
+	assert.Equal(t, original.Retryable, decoded.Retryable)
+	assert.Nil(t, decoded.Cause)
+	assert.Empty(t, decoded.StackTrace())
+}
+
+func TestJSON_NestedCauseChain(t *testing.T) {
+	root := io.ErrUnexpectedEOF
+	inner := Wrap(root, "failed to read job").WithCode(CodeSerialization)
+	middle := fmt.Errorf("dequeue: %w", inner)
+	outer := Wrap(middle, "worker failed").WithCode(CodeInternal)
+
+	data, err := json.Marshal(outer)
+	require.NoError(t, err)
+
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.Equal(t, "dequeue: failed to read job: unexpected EOF", raw["cause"])
+	assert.Equal(t, []any{
+		"dequeue: failed to read job: unexpected EOF",
+		"failed to read job: unexpected EOF",
+		"unexpected EOF",
+	}, raw["cause_chain"])
+
+	decoded, err := FromJSON(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, outer.Error(), decoded.Error())
+	assert.Equal(t, CodeInternal, decoded.Code)
+
+	var messages []string
+	for cause := decoded.Unwrap(); cause != nil; cause = errors.Unwrap(cause) {
+		messages = append(messages, cause.Error())
+	}
+	assert.Len(t, messages, 3)
+	assert.Equal(t, "unexpected EOF", messages[2])
+}
+
+func TestJSON_StackIsOptional(t *testing.T) {
+	e := New("boom")
+
+	data, err := e.ToJSON()
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"stack"`)
+
+	data, err = e.ToJSON(WithStackTrace())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"stack"`)
+
+	decoded, err := FromJSON(data)
+	require.NoError(t, err)
+	assert.Equal(t, e.StackTrace(), decoded.StackTrace())
+}
+
+func TestFromJSON_UnknownAndMissingFields(t *testing.T) {
+	decoded, err := FromJSON([]byte(`{
+		"message": "from a newer version",
+		"severity": "high",
+		"links": [{"href": "https://example.com"}],
+		"cause": "upstream failed"
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, CodeUnknown, decoded.Code)
+	assert.Equal(t, "from a newer version", decoded.Message)
+	assert.NotNil(t, decoded.Metadata)
+	assert.Equal(t, "from a newer version: upstream failed", decoded.Error())
+}
+
+func TestFromJSON_Invalid(t *testing.T) {
+	_, err := FromJSON([]byte(`{"code": 42`))
+
+	require.Error(t, err)
+	assert.Equal(t, CodeSerialization, GetCode(err))
+}
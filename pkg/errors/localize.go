@@ -0,0 +1,303 @@
+package errors
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultLocale is the locale NewKey renders the default message in
+const DefaultLocale = "en"
+
+// Translator renders the message for a key in a locale. It reports false
+// when it has no message for the key in that locale.
+type Translator interface {
+	Translate(locale, key string, args ...any) (string, bool)
+}
+
+// Catalog is a map-backed Translator holding one message per key and
+// locale. Messages are fmt format strings rendered with the error's args.
+type Catalog struct {
+	messages map[string]map[string]string
+}
+
+// NewCatalog returns an empty catalog
+func NewCatalog() *Catalog {
+	return &Catalog{messages: make(map[string]map[string]string)}
+}
+
+// Add registers messages for locale, replacing existing messages with the
+// same key
+func (c *Catalog) Add(locale string, messages map[string]string) {
+	locale = strings.ToLower(locale)
+	if c.messages[locale] == nil {
+		c.messages[locale] = make(map[string]string, len(messages))
+	}
+
+	for key, message := range messages {
+		c.messages[locale][key] = message
+	}
+}
+
+// Translate implements Translator. Locales match case-insensitively.
+func (c *Catalog) Translate(locale, key string, args ...any) (string, bool) {
+	message, ok := c.messages[strings.ToLower(locale)][key]
+	if !ok {
+		return "", false
+	}
+
+	if len(args) == 0 {
+		return message, true
+	}
+
+	return fmt.Sprintf(message, args...), true
+}
+
+// LoadCatalog reads one catalog file per locale from the root of fsys, named
+// after the locale: en.yaml, es.json, pt-br.yml. Nested maps are flattened
+// into dotted keys, so {"job": {"not_found": "..."}} defines job.not_found.
+func LoadCatalog(fsys fs.FS) (*Catalog, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, Wrap(err, "failed to read message catalogs").
+			WithCode(CodeConfiguration)
+	}
+
+	catalog := NewCatalog()
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := path.Ext(name)
+		if entry.IsDir() || (ext != ".json" && ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, Wrap(err, "failed to read message catalog").
+				WithCode(CodeConfiguration).
+				WithMetadata("file", name)
+		}
+
+		var raw map[string]any
+		if ext == ".json" {
+			err = json.Unmarshal(data, &raw)
+		} else {
+			err = yaml.Unmarshal(data, &raw)
+		}
+
+		if err != nil {
+			return nil, Wrap(err, "failed to decode message catalog").
+				WithCode(CodeConfiguration).
+				WithMetadata("file", name)
+		}
+
+		messages := make(map[string]string)
+		flattenMessages(raw, "", messages)
+		catalog.Add(strings.TrimSuffix(name, ext), messages)
+	}
+
+	return catalog, nil
+}
+
+// flattenMessages copies the leaves of raw into messages under dotted keys
+func flattenMessages(raw map[string]any, prefix string, messages map[string]string) {
+	for key, value := range raw {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch v := value.(type) {
+		case map[string]any:
+			flattenMessages(v, key, messages)
+
+		case string:
+			messages[key] = v
+
+		default:
+			messages[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+//go:embed locales/*.yaml
+var builtinLocales embed.FS
+
+// translator holds the Translator used by NewKey and Localize
+var translator = struct {
+	mu sync.RWMutex
+	t  Translator
+}{
+	t: builtinCatalog(),
+}
+
+// builtinCatalog loads the catalogs shipped with the package
+func builtinCatalog() *Catalog {
+	locales, err := fs.Sub(builtinLocales, "locales")
+	if err != nil {
+		panic(fmt.Sprintf("errors: invalid builtin locales: %v", err))
+	}
+
+	catalog, err := LoadCatalog(locales)
+	if err != nil {
+		panic(fmt.Sprintf("errors: invalid builtin locales: %v", err))
+	}
+
+	return catalog
+}
+
+// SetTranslator replaces the Translator used by NewKey and Localize. The
+// default serves the catalogs embedded in this package.
+func SetTranslator(t Translator) {
+	translator.mu.Lock()
+	defer translator.mu.Unlock()
+
+	translator.t = t
+}
+
+// translateKey renders key in locale with the installed Translator
+func translateKey(locale, key string, args ...any) (string, bool) {
+	translator.mu.RLock()
+	t := translator.t
+	translator.mu.RUnlock()
+
+	if t == nil {
+		return "", false
+	}
+
+	return t.Translate(locale, key, args...)
+}
+
+// NewKey creates an error identified by a message key, such as
+// "job.not_found". The message is the key rendered in DefaultLocale with args,
+// or the key itself when no catalog defines it. Localize renders the same key
+// in other locales.
+func NewKey(key string, args ...any) *Error {
+	message, ok := translateKey(DefaultLocale, key, args...)
+	if !ok {
+		message = key
+	}
+
+	return &Error{
+		Code:     CodeUnknown,
+		Message:  message,
+		Metadata: make(map[string]any),
+		Key:      key,
+		Args:     args,
+		stack:    captureStack(2),
+	}
+}
+
+// WithKey attaches a message key and its args to the error so Localize can
+// render it in other locales. The message itself is unchanged.
+func (e *Error) WithKey(key string, args ...any) *Error {
+	if e == nil {
+		return nil
+	}
+
+	e.Key = key
+	e.Args = args
+	return e
+}
+
+// Localize returns the message of err in locale, such as "es" or "pt-BR".
+// The outermost error in the chain that carries a message key is rendered;
+// a regional locale falls back to its language. When err has no key or the
+// key has no message in the locale, Localize returns err.Error().
+func Localize(err error, locale string) string {
+	if err == nil {
+		return ""
+	}
+
+	if message, ok := localize(err, locale); ok {
+		return message
+	}
+
+	return err.Error()
+}
+
+// localize renders the outermost keyed error of the chain in locale
+func localize(err error, locale string) (string, bool) {
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		e, ok := current.(*Error)
+		if !ok || e.Key == "" {
+			continue
+		}
+
+		for _, candidate := range localeCandidates(locale) {
+			if message, ok := translateKey(candidate, e.Key, e.Args...); ok {
+				return message, true
+			}
+		}
+
+		return "", false
+	}
+
+	return "", false
+}
+
+// localeCandidates returns locale followed by its language, e.g. "pt-BR"
+// then "pt"
+func localeCandidates(locale string) []string {
+	locale = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	if locale == "" {
+		return nil
+	}
+
+	if language, _, ok := strings.Cut(locale, "-"); ok {
+		return []string{locale, language}
+	}
+
+	return []string{locale}
+}
+
+// acceptedLanguages parses an Accept-Language header into language tags
+// ordered by preference. Wildcards and tags with q=0 are dropped.
+func acceptedLanguages(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+
+			q = parsed
+		}
+
+		if q > 0 {
+			tags = append(tags, weighted{tag: tag, q: q})
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+
+	languages := make([]string, len(tags))
+	for i, tag := range tags {
+		languages[i] = tag.tag
+	}
+
+	return languages
+}
@@ -0,0 +1,139 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCatalogs defines a JSON and a YAML catalog, the latter missing a key
+var testCatalogs = fstest.MapFS{
+	"en.json": {Data: []byte(`{
+		"job": {
+			"not_found": "job %s not found",
+			"retries": "job %[1]s failed after %[2]d attempts"
+		},
+		"queue.full": "queue is full"
+	}`)},
+	"de.yaml": {Data: []byte(`
+job:
+  not_found: "Auftrag %s nicht gefunden"
+  retries: "nach %[2]d Versuchen ist Auftrag %[1]s fehlgeschlagen"
+`)},
+	"README.md": {Data: []byte("not a catalog")},
+}
+
+// useCatalog installs the test catalogs for the duration of a test
+func useCatalog(t *testing.T) {
+	t.Helper()
+
+	catalog, err := LoadCatalog(testCatalogs)
+	require.NoError(t, err)
+
+	SetTranslator(catalog)
+	t.Cleanup(func() { SetTranslator(builtinCatalog()) })
+}
+
+func TestNewKey_DefaultMessage(t *testing.T) {
+	useCatalog(t)
+
+	err := NewKey("job.retries", "42", 3).WithCode(CodeInternal)
+
+	assert.Equal(t, "job 42 failed after 3 attempts", err.Message)
+	assert.Equal(t, "job.retries", err.Key)
+	assert.Equal(t, []any{"42", 3}, err.Args)
+	assert.Equal(t, "job.unknown_key", NewKey("job.unknown_key").Message)
+}
+
+func TestLocalize(t *testing.T) {
+	useCatalog(t)
+
+	err := NewKey("job.retries", "42", 3)
+	wrapped := Wrap(fmt.Errorf("worker: %w", err), "processing failed")
+
+	tests := []struct {
+		name     string
+		err      error
+		locale   string
+		expected string
+	}{
+		{"argument interpolation", err, "de", "nach 3 Versuchen ist Auftrag 42 fehlgeschlagen"},
+		{"region falls back to language", err, "de-AT", "nach 3 Versuchen ist Auftrag 42 fehlgeschlagen"},
+		{"case and separator insensitive", err, "DE_at", "nach 3 Versuchen ist Auftrag 42 fehlgeschlagen"},
+		{"key found deep in the chain", wrapped, "de", "nach 3 Versuchen ist Auftrag 42 fehlgeschlagen"},
+		{"unknown locale", err, "fr", "job 42 failed after 3 attempts"},
+		{"empty locale", err, "", "job 42 failed after 3 attempts"},
+		{"key missing from catalog", NewKey("queue.full"), "de", "queue is full"},
+		{"error without key", New("plain message"), "de", "plain message"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Localize(tt.err, tt.locale))
+		})
+	}
+
+	assert.Empty(t, Localize(nil, "de"))
+}
+
+func TestWithKey(t *testing.T) {
+	err := NotFound("job %s not found", "7").WithKey("job.not_found", "7")
+
+	assert.Equal(t, "job 7 not found", err.Message)
+	assert.Equal(t, "no se encontró el trabajo 7", Localize(err, "es"))
+}
+
+func TestLoadCatalog_Invalid(t *testing.T) {
+	_, err := LoadCatalog(fstest.MapFS{"en.yaml": {Data: []byte("job: [unterminated")}})
+	require.Error(t, err)
+	assert.Equal(t, CodeConfiguration, GetCode(err))
+	assert.Equal(t, "en.yaml", err.(*Error).Metadata["file"])
+}
+
+func TestBuiltinCatalogsDefineTheSameKeys(t *testing.T) {
+	catalog := builtinCatalog()
+	english := catalog.messages[DefaultLocale]
+	require.NotEmpty(t, english)
+
+	for locale, messages := range catalog.messages {
+		for key := range english {
+			assert.Contains(t, messages, key, "locale %s", locale)
+		}
+	}
+}
+
+func TestWriteProblem_AcceptLanguage(t *testing.T) {
+	useCatalog(t)
+
+	tests := []struct {
+		name     string
+		header   string
+		detail   string
+		language string
+	}{
+		{"preferred language", "de-CH, en;q=0.8", "Auftrag 9 nicht gefunden", "de-CH"},
+		{"quality ordering", "fr;q=0.9, en;q=0.5, de;q=0.7", "Auftrag 9 nicht gefunden", "de"},
+		{"no supported language", "fr, it;q=0.5", "job 9 not found", ""},
+		{"no header", "", "job 9 not found", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/jobs/9", nil)
+			if tt.header != "" {
+				req.Header.Set("Accept-Language", tt.header)
+			}
+
+			rec := httptest.NewRecorder()
+			WriteProblem(rec, NotFound("job 9 not found").WithKey("job.not_found", "9"), req)
+
+			assert.Contains(t, rec.Body.String(), `"detail":"`+tt.detail+`"`)
+			assert.Equal(t, tt.language, rec.Header().Get("Content-Language"))
+		})
+	}
+}
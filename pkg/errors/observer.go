@@ -0,0 +1,72 @@
+package errors
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// Observer is notified of errors passed to Report, e.g. to count them in
+// metrics. It must be cheap and safe for concurrent use.
+type Observer func(e *Error)
+
+// observer holds the installed Observer, if any
+var observer atomic.Pointer[Observer]
+
+// SetObserver installs fn as the observer notified by Report, replacing any
+// previous one. A nil fn removes it.
+func SetObserver(fn Observer) {
+	if fn == nil {
+		observer.Store(nil)
+		return
+	}
+
+	observer.Store(&fn)
+}
+
+// Report notifies the installed observer of err and returns err unchanged.
+// Errors are observed only when reported, not when created or wrapped, so
+// each failure is counted once: call Report where an error leaves the system,
+// such as when a job fails or a request is answered, not at every layer.
+// Errors from outside this package are reported as CodeUnknown. A panic in
+// the observer is swallowed rather than propagated to the caller.
+//
+//	return errors.Report(err)
+func Report(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	fn := observer.Load()
+	if fn == nil {
+		return err
+	}
+
+	var e *Error
+	if !errors.As(err, &e) {
+		e = &Error{Code: CodeUnknown, Message: err.Error(), Cause: err}
+	}
+
+	notify(*fn, e)
+	return err
+}
+
+// notify calls fn, recovering from any panic it raises
+func notify(fn Observer, e *Error) {
+	defer func() {
+		_ = recover()
+	}()
+
+	fn(e)
+}
+
+// Component returns the first component annotation found along the error
+// chain, or an empty string when there is none
+func Component(err error) string {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if e, ok := err.(*Error); ok && e.Component != "" {
+			return e.Component
+		}
+	}
+
+	return ""
+}
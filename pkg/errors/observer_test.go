@@ -0,0 +1,81 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingObserver collects reported errors
+type recordingObserver struct {
+	mu     sync.Mutex
+	errors []*Error
+}
+
+func (r *recordingObserver) observe(e *Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errors = append(r.errors, e)
+}
+
+func installObserver(t *testing.T, fn Observer) {
+	t.Helper()
+
+	SetObserver(fn)
+	t.Cleanup(func() { SetObserver(nil) })
+}
+
+func TestReport(t *testing.T) {
+	recorder := &recordingObserver{}
+	installObserver(t, recorder.observe)
+
+	err := Wrap(Internal("disk full").WithComponent("storage"), "save failed")
+	assert.Same(t, err, Report(err))
+
+	plain := fmt.Errorf("read: %w", io.EOF)
+	assert.Same(t, plain, Report(plain))
+
+	assert.Nil(t, Report(nil))
+
+	require.Len(t, recorder.errors, 2)
+	assert.Same(t, err, recorder.errors[0])
+	assert.Equal(t, CodeUnknown, recorder.errors[1].Code)
+	assert.ErrorIs(t, recorder.errors[1], io.EOF)
+}
+
+func TestReport_NotCalledOnCreateOrWrap(t *testing.T) {
+	recorder := &recordingObserver{}
+	installObserver(t, recorder.observe)
+
+	_ = Wrap(New("boom"), "outer")
+	assert.Empty(t, recorder.errors)
+}
+
+func TestReport_ObserverPanicIsContained(t *testing.T) {
+	installObserver(t, func(*Error) { panic("observer bug") })
+
+	err := New("boom")
+	assert.NotPanics(t, func() {
+		assert.Same(t, err, Report(err))
+	})
+}
+
+func TestReport_NoObserver(t *testing.T) {
+	err := New("boom")
+	assert.Same(t, err, Report(err))
+}
+
+func TestComponent(t *testing.T) {
+	inner := Internal("disk full").WithComponent("storage")
+	outer := Wrap(fmt.Errorf("flush: %w", inner), "save failed")
+
+	assert.Equal(t, "storage", Component(outer))
+	assert.Equal(t, "api", Component(Wrap(outer, "request failed").WithComponent("api")))
+	assert.Empty(t, Component(io.EOF))
+	assert.Empty(t, Component(nil))
+}
@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithOp_ErrorPrefixChain(t *testing.T) {
+	storage := AlreadyExists("job already exists").WithOp("storage.Create")
+	queue := Wrap(storage, "failed to enqueue").WithOp("queue.Enqueue")
+	handler := Wrap(fmt.Errorf("request 7: %w", queue), "create job failed").
+		WithComponent("api").
+		WithOp("CreateJob")
+
+	assert.Equal(t,
+		"api.CreateJob: create job failed: request 7: "+
+			"queue.Enqueue: failed to enqueue: storage.Create: job already exists",
+		handler.Error())
+
+	assert.Equal(t, []string{"api.CreateJob", "queue.Enqueue", "storage.Create"}, Ops(handler))
+	assert.Equal(t, CodeAlreadyExists, handler.Code, "codes still propagate through Wrap")
+}
+
+func TestWithOp_NotCopiedByWrap(t *testing.T) {
+	inner := New("boom").WithOp("storage.Get")
+	outer := Wrap(inner, "lookup failed")
+
+	assert.Empty(t, outer.Op)
+	assert.Equal(t, []string{"storage.Get"}, Ops(outer))
+	assert.Equal(t, "lookup failed: storage.Get: boom", outer.Error())
+}
+
+func TestWithComponent_Alone(t *testing.T) {
+	err := New("disk full").WithComponent("worker")
+
+	assert.Equal(t, "worker: disk full", err.Error())
+	assert.Equal(t, []string{"worker"}, Ops(err))
+}
+
+func TestOps_Empty(t *testing.T) {
+	assert.Nil(t, Ops(nil))
+	assert.Nil(t, Ops(io.EOF))
+	assert.Nil(t, Ops(New("no op")))
+}
+
+func TestWithOp_JSON(t *testing.T) {
+	err := NotFound("job missing").WithComponent("storage").WithOp("Get")
+
+	data, jsonErr := err.ToJSON()
+	require.NoError(t, jsonErr)
+	assert.Contains(t, string(data), `"op":"Get"`)
+	assert.Contains(t, string(data), `"component":"storage"`)
+
+	decoded, jsonErr := FromJSON(data)
+	require.NoError(t, jsonErr)
+	assert.Equal(t, "Get", decoded.Op)
+	assert.Equal(t, "storage", decoded.Component)
+	assert.Equal(t, err.Error(), decoded.Error())
+}
+
+func TestWithOp_NilError(t *testing.T) {
+	var e *Error
+
+	assert.Nil(t, e.WithOp("op"))
+	assert.Nil(t, e.WithComponent("component"))
+}
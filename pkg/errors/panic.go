@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// panicFrames are the runtime functions that start a panic. Frames up to and
+// including the last of them belong to the recovery machinery, not to the
+// code that panicked.
+var panicFrames = map[string]bool{
+	"runtime.gopanic":  true,
+	"runtime.sigpanic": true,
+	"runtime.panicmem": true,
+}
+
+// FromPanic converts a value returned by recover() into an *Error with
+// CodeInternal. The stack starts at the statement that panicked, and the
+// metadata carries panic=true and the stringified value under panic_value.
+// A recovered error is kept as the cause. It returns nil for a nil value.
+//
+//	defer func() {
+//	    if r := recover(); r != nil {
+//	        err = errors.FromPanic(r)
+//	    }
+//	}()
+func FromPanic(recovered any) *Error {
+	if recovered == nil {
+		return nil
+	}
+
+	e := &Error{
+		Code:     CodeInternal,
+		Message:  fmt.Sprintf("panic: %v", recovered),
+		Metadata: map[string]any{"panic": true, "panic_value": fmt.Sprint(recovered)},
+		stack:    capturePanicStack(),
+	}
+
+	if err, ok := recovered.(error); ok {
+		e.Message = "panic"
+		e.Cause = err
+	}
+
+	return e.WithCode(CodeInternal)
+}
+
+// Recover turns a panic in the calling function into an error stored in
+// *errp. It must be deferred directly:
+//
+//	func handle(job *models.Job) (err error) {
+//	    defer errors.Recover(&err)
+//	    ...
+//	}
+func Recover(errp *error) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	if err := FromPanic(recovered); errp != nil {
+		*errp = err
+	}
+}
+
+// capturePanicStack captures the stack of a panicking goroutine from inside a
+// deferred call and drops the frames of the recovery itself
+func capturePanicStack() *stackTrace {
+	if stackCaptureDisabled.Load() {
+		return nil
+	}
+
+	pcs := make([]uintptr, 2*maxStackDepth)
+	n := runtime.Callers(3, pcs)
+
+	frames := (&stackTrace{pcs: pcs[:n]}).Frames()
+	for i := len(frames) - 1; i >= 0; i-- {
+		if panicFrames[frames[i].Function] {
+			frames = frames[i+1:]
+			break
+		}
+	}
+
+	if len(frames) > maxStackDepth {
+		frames = frames[:maxStackDepth]
+	}
+
+	return resolvedStack(frames)
+}
@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// explode panics with value
+func explode(value any) {
+	panic(value)
+}
+
+// guarded runs fn and converts a panic into the returned error
+func guarded(fn func()) (err error) {
+	defer Recover(&err)
+
+	fn()
+	return nil
+}
+
+func TestRecover_StackPointsAtPanicSite(t *testing.T) {
+	err := guarded(func() { explode("boom") })
+	require.Error(t, err)
+
+	var e *Error
+	require.True(t, errors.As(err, &e))
+
+	frames := e.StackTrace()
+	require.NotEmpty(t, frames)
+	assert.Equal(t, "task-queue/pkg/errors.explode", frames[0].Function)
+	for _, frame := range frames {
+		assert.False(t, strings.HasPrefix(frame.Function, "runtime.gopanic"))
+		assert.NotEqual(t, "task-queue/pkg/errors.Recover", frame.Function)
+		assert.NotEqual(t, "task-queue/pkg/errors.FromPanic", frame.Function)
+	}
+}
+
+func TestRecover_RuntimeError(t *testing.T) {
+	err := guarded(func() {
+		var job *Error
+		_ = job.Message
+	})
+
+	var e *Error
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, CodeInternal, e.Code)
+	assert.Contains(t, e.StackTrace()[0].Function, "TestRecover_RuntimeError")
+
+	var runtimeErr interface{ RuntimeError() }
+	assert.True(t, errors.As(err, &runtimeErr), "the runtime error stays in the chain")
+}
+
+func TestFromPanic(t *testing.T) {
+	e := FromPanic("out of slots")
+
+	assert.Equal(t, CodeInternal, e.Code)
+	assert.Equal(t, 500, e.HTTPStatus())
+	assert.Equal(t, "panic: out of slots", e.Error())
+	assert.Equal(t, true, e.Metadata["panic"])
+	assert.Equal(t, "out of slots", e.Metadata["panic_value"])
+
+	wrapped := FromPanic(io.ErrClosedPipe)
+	assert.ErrorIs(t, wrapped, io.ErrClosedPipe)
+	assert.Equal(t, "panic: io: read/write on closed pipe", wrapped.Error())
+
+	assert.Nil(t, FromPanic(nil))
+}
+
+func TestRecover_NoPanic(t *testing.T) {
+	assert.NoError(t, guarded(func() {}))
+}
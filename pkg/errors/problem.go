@@ -0,0 +1,202 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypeBase prefixes the lower-cased error code to form the problem
+// type URI, e.g. "urn:task-queue:problem:not_found"
+var ProblemTypeBase = "urn:task-queue:problem:"
+
+// FieldsKey is the metadata key holding the per-field failures of a
+// validation error. Problem details expose them as the "errors" extension.
+const FieldsKey = "fields"
+
+// FieldError is the wire shape of a single field failure stored under
+// FieldsKey. Value is only set when the producer opts into exposing input
+// values.
+type FieldError struct {
+	Field   string         `json:"field"`
+	Message string         `json:"message"`
+	Tag     string         `json:"tag"`
+	Params  map[string]any `json:"params,omitempty"`
+	Detail  string         `json:"detail,omitempty"`
+	Value   any            `json:"value,omitempty"`
+}
+
+// FieldLocalizer translates field failures into locale. It reports false
+// when it has no messages for the locale.
+type FieldLocalizer func(fields []FieldError, locale string) ([]FieldError, bool)
+
+// fieldLocalizer translates the "errors" extension of problem responses
+var fieldLocalizer atomic.Pointer[FieldLocalizer]
+
+// SetFieldLocalizer installs the function WriteProblem uses to translate
+// field failures. The validation package installs its Translator; nil
+// disables translation.
+func SetFieldLocalizer(localizer FieldLocalizer) {
+	if localizer == nil {
+		fieldLocalizer.Store(nil)
+		return
+	}
+
+	fieldLocalizer.Store(&localizer)
+}
+
+// internalProblemDetail replaces the detail of 5xx problems outside debug mode
+const internalProblemDetail = "an internal error occurred"
+
+// problemDebug exposes the details of 5xx problems when set
+var problemDebug atomic.Bool
+
+// SetProblemDebug controls whether problem details for 5xx errors include
+// the error message and metadata. They are hidden by default so responses
+// don't leak internal state.
+func SetProblemDebug(enabled bool) {
+	problemDebug.Store(enabled)
+}
+
+// Problem is an RFC 7807 problem details object. Extensions are encoded as
+// top-level members next to the standard ones.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]any
+}
+
+// ProblemDetails builds the problem details for err. The status and type
+// come from the error code, and the extensions hold the code and the
+// redacted metadata, with the field failures of validation errors under
+// "errors". For 5xx statuses the detail is a generic message and the
+// metadata is omitted unless SetProblemDebug is enabled. Errors from outside
+// this package are reported as unknown 500 errors.
+func ProblemDetails(err error, instance string) Problem {
+	code := CodeUnknown
+	status := http.StatusInternalServerError
+	var metadata map[string]any
+
+	var e *Error
+	if errors.As(err, &e) {
+		code = e.Code
+		status = e.HTTPStatus()
+		metadata = e.SafeMetadata()
+	}
+
+	problem := Problem{
+		Type:       ProblemTypeBase + strings.ToLower(string(code)),
+		Title:      http.StatusText(status),
+		Status:     status,
+		Instance:   instance,
+		Extensions: map[string]any{"code": code},
+	}
+
+	if detailHidden(status) {
+		problem.Detail = internalProblemDetail
+		return problem
+	}
+
+	if err != nil {
+		problem.Detail = err.Error()
+	}
+
+	for key, value := range metadata {
+		if key == FieldsKey {
+			key = "errors"
+		}
+
+		if _, ok := problem.Extensions[key]; !ok {
+			problem.Extensions[key] = value
+		}
+	}
+
+	return problem
+}
+
+// WriteProblem writes err as an application/problem+json response with the
+// status mapped from its code. The request path is used as the instance.
+// When the request has an Accept-Language header and the error carries a
+// message key, the detail is localized into the most preferred language with
+// a translation. Field failures are localized likewise by the installed
+// FieldLocalizer.
+func WriteProblem(w http.ResponseWriter, err error, r *http.Request) {
+	instance := ""
+	if r != nil && r.URL != nil {
+		instance = r.URL.Path
+	}
+
+	problem := ProblemDetails(err, instance)
+	if r != nil && !detailHidden(problem.Status) {
+		languages := acceptedLanguages(r.Header.Get("Accept-Language"))
+		for _, language := range languages {
+			if message, ok := localize(err, language); ok {
+				problem.Detail = message
+				w.Header().Set("Content-Language", language)
+				break
+			}
+		}
+
+		localizeFields(w, problem, languages)
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+// localizeFields translates the field failures of problem into the most
+// preferred language the installed FieldLocalizer supports
+func localizeFields(w http.ResponseWriter, problem Problem, languages []string) {
+	fields, ok := problem.Extensions["errors"].([]FieldError)
+	localizer := fieldLocalizer.Load()
+	if !ok || localizer == nil {
+		return
+	}
+
+	for _, language := range languages {
+		if localized, ok := (*localizer)(fields, language); ok {
+			problem.Extensions["errors"] = localized
+			if w.Header().Get("Content-Language") == "" {
+				w.Header().Set("Content-Language", language)
+			}
+
+			return
+		}
+	}
+}
+
+// detailHidden reports whether problems with status hide their details
+func detailHidden(status int) bool {
+	return status >= http.StatusInternalServerError && !problemDebug.Load()
+}
+
+// MarshalJSON encodes the problem with its extensions as top-level members.
+// Extensions never override the standard members.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]any, len(p.Extensions)+5)
+	for key, value := range p.Extensions {
+		members[key] = value
+	}
+
+	members["type"] = p.Type
+	members["title"] = p.Title
+	members["status"] = p.Status
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+
+	return json.Marshal(members)
+}
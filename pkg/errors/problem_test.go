@@ -0,0 +1,171 @@
+package errors
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblemDetails_Codes(t *testing.T) {
+	tests := []struct {
+		code   Code
+		status int
+		hidden bool
+	}{
+		{CodeValidation, http.StatusBadRequest, false},
+		{CodeNotFound, http.StatusNotFound, false},
+		{CodeAlreadyExists, http.StatusConflict, false},
+		{CodeConflict, http.StatusConflict, false},
+		{CodePermission, http.StatusForbidden, false},
+		{CodeAuthentication, http.StatusUnauthorized, false},
+		{CodeRateLimit, http.StatusTooManyRequests, false},
+		{CodeTimeout, http.StatusRequestTimeout, false},
+		{CodeCanceled, http.StatusRequestTimeout, false},
+		{CodeInternal, http.StatusInternalServerError, true},
+		{CodeDatabase, http.StatusInternalServerError, true},
+		{CodeNetwork, http.StatusInternalServerError, true},
+		{CodeSerialization, http.StatusInternalServerError, true},
+		{CodeConfiguration, http.StatusInternalServerError, true},
+		{CodeUnknown, http.StatusInternalServerError, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			err := New("something went wrong").
+				WithCode(tt.code).
+				WithMetadata("job_id", "42")
+
+			problem := ProblemDetails(err, "/jobs/42")
+
+			assert.Equal(t, tt.status, problem.Status)
+			assert.Equal(t, http.StatusText(tt.status), problem.Title)
+			assert.Equal(t, "/jobs/42", problem.Instance)
+			assert.Equal(t, tt.code, problem.Extensions["code"])
+			assert.Contains(t, problem.Type, ProblemTypeBase)
+
+			if tt.hidden {
+				assert.Equal(t, internalProblemDetail, problem.Detail)
+				assert.NotContains(t, problem.Extensions, "job_id")
+				return
+			}
+
+			assert.Equal(t, "something went wrong", problem.Detail)
+			assert.Equal(t, "42", problem.Extensions["job_id"])
+		})
+	}
+}
+
+func TestProblemDetails_Type(t *testing.T) {
+	problem := ProblemDetails(NotFound("job not found"), "")
+	assert.Equal(t, "urn:task-queue:problem:not_found", problem.Type)
+}
+
+func TestProblemDetails_PlainError(t *testing.T) {
+	problem := ProblemDetails(io.ErrUnexpectedEOF, "/jobs")
+
+	assert.Equal(t, http.StatusInternalServerError, problem.Status)
+	assert.Equal(t, CodeUnknown, problem.Extensions["code"])
+	assert.Equal(t, internalProblemDetail, problem.Detail)
+}
+
+func TestProblemDetails_Debug(t *testing.T) {
+	SetProblemDebug(true)
+	t.Cleanup(func() { SetProblemDebug(false) })
+
+	err := Internal("connection pool exhausted").
+		WithMetadata("pool", "primary").
+		WithMetadata("password", "hunter2")
+
+	problem := ProblemDetails(err, "")
+	assert.Equal(t, "connection pool exhausted", problem.Detail)
+	assert.Equal(t, "primary", problem.Extensions["pool"])
+	assert.Equal(t, RedactedValue, problem.Extensions["password"], "debug mode still redacts")
+}
+
+func TestProblem_MarshalJSON(t *testing.T) {
+	problem := ProblemDetails(Validation("priority out of range").
+		WithMetadata("field", "priority").
+		WithMetadata("status", "ignored"), "/jobs")
+
+	data, err := json.Marshal(problem)
+	require.NoError(t, err)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, map[string]any{
+		"type":     "urn:task-queue:problem:validation",
+		"title":    "Bad Request",
+		"status":   float64(400),
+		"detail":   "priority out of range",
+		"instance": "/jobs",
+		"code":     "VALIDATION",
+		"field":    "priority",
+	}, decoded)
+}
+
+func TestProblem_FieldErrors(t *testing.T) {
+	err := Validation("validation failed").WithMetadata(FieldsKey, []FieldError{
+		{Field: "priority", Message: "must be at most 3", Tag: "max", Params: map[string]any{"max": 3}},
+	})
+
+	data, merr := json.Marshal(ProblemDetails(err, "/jobs"))
+	require.NoError(t, merr)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.NotContains(t, decoded, FieldsKey)
+	assert.Equal(t, []any{map[string]any{
+		"field":   "priority",
+		"message": "must be at most 3",
+		"tag":     "max",
+		"params":  map[string]any{"max": float64(3)},
+	}}, decoded["errors"])
+}
+
+func TestWriteProblem_FieldLocalizer(t *testing.T) {
+	SetFieldLocalizer(func(fields []FieldError, locale string) ([]FieldError, bool) {
+		if locale != "es" {
+			return nil, false
+		}
+
+		localized := append([]FieldError(nil), fields...)
+		localized[0].Message = "es obligatorio"
+		return localized, true
+	})
+	t.Cleanup(func() { SetFieldLocalizer(nil) })
+
+	req := httptest.NewRequest(http.MethodPost, "/jobs", nil)
+	req.Header.Set("Accept-Language", "fr, es;q=0.5")
+	rec := httptest.NewRecorder()
+
+	WriteProblem(rec, Validation("validation failed").WithMetadata(FieldsKey, []FieldError{
+		{Field: "type", Message: "is required", Tag: "required"},
+	}), req)
+
+	assert.Equal(t, "es", rec.Header().Get("Content-Language"))
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &decoded))
+	require.Len(t, decoded["errors"], 1)
+	assert.Equal(t, "es obligatorio", decoded["errors"].([]any)[0].(map[string]any)["message"])
+}
+
+func TestWriteProblem(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/jobs/42?verbose=1", nil)
+	rec := httptest.NewRecorder()
+
+	WriteProblem(rec, NotFound("job 42 not found"), req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, ProblemContentType, rec.Header().Get("Content-Type"))
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &decoded))
+	assert.Equal(t, "/jobs/42", decoded["instance"])
+	assert.Equal(t, "job 42 not found", decoded["detail"])
+}
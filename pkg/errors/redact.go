@@ -0,0 +1,160 @@
+package errors
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// RedactedValue replaces sensitive metadata values in serialized and logged
+// errors
+const RedactedValue = "[REDACTED]"
+
+// defaultRedactedKeys are the metadata keys redacted unless SetRedactedKeys
+// replaces them
+var defaultRedactedKeys = []string{
+	"password",
+	"secret",
+	"token",
+	"authorization",
+	"api_key",
+	"cookie",
+}
+
+// redaction holds the package-level redaction settings
+var redaction = struct {
+	mu      sync.RWMutex
+	keys    map[string]struct{}
+	pattern *regexp.Regexp
+}{
+	keys: keySet(defaultRedactedKeys),
+}
+
+// SetRedactedKeys replaces the set of metadata keys whose values are redacted.
+// Keys match case-insensitively at any nesting depth. Calling it with no keys
+// disables key-based redaction.
+func SetRedactedKeys(keys ...string) {
+	redaction.mu.Lock()
+	defer redaction.mu.Unlock()
+
+	redaction.keys = keySet(keys)
+}
+
+// SetRedactedKeyPattern redacts the values of metadata keys matching pattern,
+// in addition to the keys set by SetRedactedKeys. A nil pattern removes it.
+func SetRedactedKeyPattern(pattern *regexp.Regexp) {
+	redaction.mu.Lock()
+	defer redaction.mu.Unlock()
+
+	redaction.pattern = pattern
+}
+
+// sensitiveValue marks a metadata value that is redacted regardless of key.
+// It also renders redacted through fmt and encoding/json, so the raw value
+// does not leak when the metadata map is printed directly.
+type sensitiveValue struct {
+	value any
+}
+
+// String hides the wrapped value
+func (sensitiveValue) String() string {
+	return RedactedValue
+}
+
+// MarshalJSON hides the wrapped value
+func (sensitiveValue) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + RedactedValue + `"`), nil
+}
+
+// WithSensitiveMetadata adds a metadata entry that is always redacted when the
+// error is serialized or logged, whatever its key
+func (e *Error) WithSensitiveMetadata(key string, value any) *Error {
+	return e.WithMetadata(key, sensitiveValue{value: value})
+}
+
+// SafeMetadata returns a copy of the metadata with sensitive values replaced
+// by RedactedValue. Keys stay visible and nested maps and slices are walked.
+func (e *Error) SafeMetadata() map[string]any {
+	if e == nil || e.Metadata == nil {
+		return nil
+	}
+
+	redaction.mu.RLock()
+	defer redaction.mu.RUnlock()
+
+	return redactMap(e.Metadata)
+}
+
+// redactMap copies m, redacting sensitive entries. The caller holds the
+// redaction read lock.
+func redactMap(m map[string]any) map[string]any {
+	redacted := make(map[string]any, len(m))
+	for key, value := range m {
+		if isRedactedKey(key) {
+			redacted[key] = RedactedValue
+			continue
+		}
+
+		redacted[key] = redactValue(value)
+	}
+
+	return redacted
+}
+
+// redactValue redacts sensitive values nested inside value
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case sensitiveValue:
+		return RedactedValue
+
+	case map[string]any:
+		return redactMap(v)
+
+	case map[string]string:
+		redacted := make(map[string]any, len(v))
+		for key, value := range v {
+			redacted[key] = value
+		}
+
+		return redactMap(redacted)
+
+	case []map[string]any:
+		redacted := make([]any, len(v))
+		for i, item := range v {
+			redacted[i] = redactMap(item)
+		}
+
+		return redacted
+
+	case []any:
+		redacted := make([]any, len(v))
+		for i, item := range v {
+			redacted[i] = redactValue(item)
+		}
+
+		return redacted
+
+	default:
+		return value
+	}
+}
+
+// isRedactedKey reports whether values under key must be redacted. The
+// caller holds the redaction read lock.
+func isRedactedKey(key string) bool {
+	if _, ok := redaction.keys[strings.ToLower(key)]; ok {
+		return true
+	}
+
+	return redaction.pattern != nil && redaction.pattern.MatchString(key)
+}
+
+// keySet lower-cases keys into a lookup set
+func keySet(keys []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		set[strings.ToLower(key)] = struct{}{}
+	}
+
+	return set
+}
@@ -0,0 +1,156 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// restoreRedaction resets the package-level redaction settings after a test
+func restoreRedaction(t *testing.T) {
+	t.Helper()
+
+	t.Cleanup(func() {
+		SetRedactedKeys(defaultRedactedKeys...)
+		SetRedactedKeyPattern(nil)
+	})
+}
+
+func TestSafeMetadata_RedactsKeysCaseInsensitively(t *testing.T) {
+	err := New("login failed").
+		WithMetadata("user", "ada").
+		WithMetadata("Password", "hunter2").
+		WithMetadata("AUTHORIZATION", "Bearer abc")
+
+	assert.Equal(t, map[string]any{
+		"user":          "ada",
+		"Password":      RedactedValue,
+		"AUTHORIZATION": RedactedValue,
+	}, err.SafeMetadata())
+
+	assert.Equal(t, "hunter2", err.Metadata["Password"], "the original is untouched")
+}
+
+func TestSafeMetadata_WalksNestedStructures(t *testing.T) {
+	err := New("request failed").WithMetadata("request", map[string]any{
+		"path": "/jobs",
+		"headers": map[string]string{
+			"Authorization": "Bearer abc",
+			"Accept":        "application/json",
+		},
+		"attempts": []any{
+			map[string]any{"token": "t1", "status": 500},
+			"plain",
+		},
+	})
+
+	assert.Equal(t, map[string]any{
+		"request": map[string]any{
+			"path": "/jobs",
+			"headers": map[string]any{
+				"Authorization": RedactedValue,
+				"Accept":        "application/json",
+			},
+			"attempts": []any{
+				map[string]any{"token": RedactedValue, "status": 500},
+				"plain",
+			},
+		},
+	}, err.SafeMetadata())
+}
+
+func TestSafeMetadata_JoinedChildren(t *testing.T) {
+	err := Join(
+		Validation("bad input").WithMetadata("secret", "s3"),
+		Validation("bad input").WithMetadata("field", "name"),
+	)
+
+	details := err.SafeMetadata()["errors"].([]any)
+	require.Len(t, details, 2)
+	assert.Equal(t, RedactedValue, details[0].(map[string]any)["metadata"].(map[string]any)["secret"])
+	assert.Equal(t, "name", details[1].(map[string]any)["metadata"].(map[string]any)["field"])
+}
+
+func TestSetRedactedKeys(t *testing.T) {
+	restoreRedaction(t)
+
+	SetRedactedKeys("Email")
+	err := New("signup failed").
+		WithMetadata("email", "ada@example.com").
+		WithMetadata("password", "hunter2")
+
+	assert.Equal(t, map[string]any{
+		"email":    RedactedValue,
+		"password": "hunter2",
+	}, err.SafeMetadata())
+}
+
+func TestSetRedactedKeyPattern(t *testing.T) {
+	restoreRedaction(t)
+
+	SetRedactedKeyPattern(regexp.MustCompile(`(?i)_key$`))
+	err := New("upload failed").
+		WithMetadata("signing_key", "k1").
+		WithMetadata("key_id", "k2")
+
+	assert.Equal(t, map[string]any{
+		"signing_key": RedactedValue,
+		"key_id":      "k2",
+	}, err.SafeMetadata())
+}
+
+func TestWithSensitiveMetadata(t *testing.T) {
+	err := New("webhook failed").
+		WithSensitiveMetadata("payload", `{"card":"4242"}`).
+		WithMetadata("url", "https://example.com/hook")
+
+	assert.Equal(t, RedactedValue, err.SafeMetadata()["payload"])
+	assert.Equal(t, "https://example.com/hook", err.SafeMetadata()["url"])
+
+	assert.NotContains(t, fmt.Sprint(err.Metadata), "4242",
+		"printing the raw map does not reveal the value")
+
+	raw, jsonErr := json.Marshal(err.Metadata)
+	require.NoError(t, jsonErr)
+	assert.NotContains(t, string(raw), "4242")
+}
+
+func TestRedaction_SerializedForms(t *testing.T) {
+	err := Internal("connect failed").
+		WithMetadata("host", "db.internal").
+		WithMetadata("password", "hunter2").
+		WithSensitiveMetadata("dsn", "postgres://u:p@db")
+
+	data, jsonErr := err.ToJSON()
+	require.NoError(t, jsonErr)
+	assert.NotContains(t, string(data), "hunter2")
+	assert.NotContains(t, string(data), "u:p@db")
+	assert.Contains(t, string(data), `"password":"[REDACTED]"`)
+
+	var info *errdetails.ErrorInfo
+	for _, detail := range err.GRPCStatus().Details() {
+		if d, ok := detail.(*errdetails.ErrorInfo); ok {
+			info = d
+		}
+	}
+
+	require.NotNil(t, info)
+	assert.Equal(t, map[string]string{
+		"host":     "db.internal",
+		"password": RedactedValue,
+		"dsn":      RedactedValue,
+	}, info.GetMetadata())
+
+	assert.NotContains(t, fmt.Sprintf("%#v", err), "hunter2")
+}
+
+func TestSafeMetadata_Nil(t *testing.T) {
+	var err *Error
+	assert.Nil(t, err.SafeMetadata())
+	assert.Nil(t, (&Error{}).SafeMetadata())
+}
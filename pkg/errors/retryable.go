@@ -0,0 +1,98 @@
+package errors
+
+import (
+	"context"
+	"errors"
+)
+
+// WithRetryable explicitly marks the error as retryable or not, overriding
+// the default for its code
+func (e *Error) WithRetryable(retryable bool) *Error {
+	if e == nil {
+		return nil
+	}
+
+	e.Retryable = &retryable
+	return e
+}
+
+// IsRetryable reports whether retrying the operation that produced err may
+// succeed. known is false when nothing in the error chain determines the
+// answer, leaving the decision to the caller.
+//
+// An explicit WithRetryable flag anywhere in the chain wins, outermost first.
+// Next, a non-retryable code anywhere in the chain makes the error permanent,
+// so a validation error stays final however it is wrapped. Otherwise the
+// code of the outermost *Error decides: network, timeout,
+// database, and rate limit errors are retryable; validation, not found,
+// already exists, permission, authentication, canceled, serialization, and
+// configuration errors are not. Context deadline errors and errors whose
+// Timeout method reports true, such as net.Error timeouts, are retryable;
+// context cancellation is not.
+func IsRetryable(err error) (retryable bool, known bool) {
+	if err == nil {
+		return false, true
+	}
+
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		if e, ok := current.(*Error); ok && e.Retryable != nil {
+			return *e.Retryable, true
+		}
+	}
+
+	if hasPermanentCode(err) {
+		return false, true
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		if retryable, known := codeRetryable(e.Code); known {
+			return retryable, true
+		}
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return true, true
+
+	case errors.Is(err, context.Canceled):
+		return false, true
+	}
+
+	var timeout interface{ Timeout() bool }
+	if errors.As(err, &timeout) && timeout.Timeout() {
+		return true, true
+	}
+
+	return false, false
+}
+
+// codeRetryable returns the default retry classification of a code
+func codeRetryable(code Code) (retryable bool, known bool) {
+	switch code {
+	case CodeNetwork, CodeTimeout, CodeDatabase, CodeRateLimit:
+		return true, true
+
+	case CodeValidation, CodeNotFound, CodeAlreadyExists, CodePermission,
+		CodeAuthentication, CodeCanceled, CodeSerialization, CodeConfiguration:
+		return false, true
+
+	default:
+		return false, false
+	}
+}
+
+// hasPermanentCode reports whether any *Error in err's chain has a code for
+// which retrying never helps
+func hasPermanentCode(err error) bool {
+	permanent := false
+	walkChain(err, func(link error) {
+		if e, ok := link.(*Error); ok {
+			if retryable, known := codeRetryable(e.Code); known && !retryable {
+				permanent = true
+			}
+		}
+	})
+
+	return permanent
+}
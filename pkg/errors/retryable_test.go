@@ -0,0 +1,77 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+		known     bool
+	}{
+		{"nil", nil, false, true},
+		{"network", New("reset").WithCode(CodeNetwork), true, true},
+		{"timeout", New("slow").WithCode(CodeTimeout), true, true},
+		{"database", New("db down").WithCode(CodeDatabase), true, true},
+		{"rate limit", New("slow down").WithCode(CodeRateLimit), true, true},
+		{"validation", New("bad input").WithCode(CodeValidation), false, true},
+		{"not found", New("missing").WithCode(CodeNotFound), false, true},
+		{"permission", New("denied").WithCode(CodePermission), false, true},
+		{"internal", New("bug").WithCode(CodeInternal), false, false},
+		{"unknown", New("mystery"), false, false},
+		{
+			name:      "explicit flag beats code",
+			err:       New("bad input").WithCode(CodeValidation).WithRetryable(true),
+			retryable: true,
+			known:     true,
+		},
+		{
+			name:      "explicit false beats retryable code",
+			err:       New("quota gone").WithCode(CodeRateLimit).WithRetryable(false),
+			retryable: false,
+			known:     true,
+		},
+		{
+			name:      "inner flag beats outer code",
+			err:       Wrap(New("flaky").WithRetryable(true), "wrapped").WithCode(CodeValidation),
+			retryable: true,
+			known:     true,
+		},
+		{
+			name:      "outer flag beats inner flag",
+			err:       Wrap(New("flaky").WithRetryable(true), "give up").WithRetryable(false),
+			retryable: false,
+			known:     true,
+		},
+		{
+			name:      "flag through fmt wrapping",
+			err:       fmt.Errorf("handler: %w", New("flaky").WithRetryable(true)),
+			retryable: true,
+			known:     true,
+		},
+		{"plain error", errors.New("boom"), false, false},
+		{"deadline exceeded", fmt.Errorf("query: %w", context.DeadlineExceeded), true, true},
+		{"canceled", context.Canceled, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retryable, known := IsRetryable(tt.err)
+
+			assert.Equal(t, tt.retryable, retryable)
+			assert.Equal(t, tt.known, known)
+		})
+	}
+}
+
+func TestWithRetryable_NilError(t *testing.T) {
+	var e *Error
+	assert.Nil(t, e.WithRetryable(true))
+}
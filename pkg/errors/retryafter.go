@@ -0,0 +1,151 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryAfterKey is the metadata key holding a server-provided retry delay.
+// The value is a time.Duration relative to when the error was observed, or a
+// time.Time before which retrying is pointless.
+const RetryAfterKey = "retry_after"
+
+// WithRetryAfter records that the operation should not be retried for d.
+// retry.Do waits this long instead of its backoff delay.
+func (e *Error) WithRetryAfter(d time.Duration) *Error {
+	return e.WithMetadata(RetryAfterKey, d)
+}
+
+// WithRetryAt records that the operation should not be retried before t
+func (e *Error) WithRetryAt(t time.Time) *Error {
+	return e.WithMetadata(RetryAfterKey, t)
+}
+
+// RetryAfter returns the retry delay hinted by the outermost *Error in err's
+// chain that carries one. Absolute times are converted to the delay from
+// now, and times in the past yield zero. Hints decoded from JSON are
+// accepted as nanosecond counts, duration strings, or RFC 3339 timestamps.
+func RetryAfter(err error) (time.Duration, bool) {
+	var hint any
+	found := false
+	walkChain(err, func(link error) {
+		if e, ok := link.(*Error); ok && !found {
+			hint, found = e.Metadata[RetryAfterKey]
+		}
+	})
+
+	if !found {
+		return 0, false
+	}
+
+	switch v := hint.(type) {
+	case time.Duration:
+		return max(v, 0), true
+
+	case time.Time:
+		return max(time.Until(v), 0), true
+
+	case float64:
+		return max(time.Duration(v), 0), true
+
+	case int64:
+		return max(time.Duration(v), 0), true
+
+	case string:
+		if d, err := time.ParseDuration(v); err == nil {
+			return max(d, 0), true
+		}
+
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return max(time.Until(t), 0), true
+		}
+	}
+
+	return 0, false
+}
+
+// ParseRetryAfter parses an HTTP Retry-After header value, either a number
+// of seconds or an HTTP date, into a delay from now. Dates in the past yield
+// zero.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(t.Sub(now), 0), true
+}
+
+// FromHTTPResponse converts an unsuccessful HTTP response from a downstream
+// service into an *Error. The status maps to a code: 429 becomes
+// CodeRateLimit, 408 and 504 CodeTimeout, other 5xx statuses CodeNetwork,
+// and 4xx statuses the matching client error code. A Retry-After header is
+// kept as a retry hint. It returns nil for 1xx, 2xx, and 3xx responses. The
+// body is not read.
+func FromHTTPResponse(resp *http.Response) *Error {
+	if resp == nil || resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+
+	code := statusToCode(resp.StatusCode)
+	message := fmt.Sprintf("http: %s", resp.Status)
+	if resp.Request != nil && resp.Request.URL != nil {
+		message = fmt.Sprintf("http: %s %s: %s", resp.Request.Method,
+			resp.Request.URL.Redacted(), resp.Status)
+	}
+
+	e := newWithCode(code, "%s", message).WithMetadata("status", resp.StatusCode)
+	if delay, ok := ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		e = e.WithRetryAfter(delay)
+	}
+
+	return e
+}
+
+// statusToCode maps a downstream HTTP error status to an error code
+func statusToCode(status int) Code {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return CodeValidation
+
+	case http.StatusUnauthorized:
+		return CodeAuthentication
+
+	case http.StatusForbidden:
+		return CodePermission
+
+	case http.StatusNotFound, http.StatusGone:
+		return CodeNotFound
+
+	case http.StatusConflict:
+		return CodeConflict
+
+	case http.StatusTooManyRequests:
+		return CodeRateLimit
+
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return CodeTimeout
+	}
+
+	if status >= http.StatusInternalServerError {
+		return CodeNetwork
+	}
+
+	return CodeUnknown
+}
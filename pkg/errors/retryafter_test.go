@@ -0,0 +1,111 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want time.Duration
+		ok   bool
+	}{
+		{"duration", RateLimited("slow down").WithRetryAfter(2 * time.Second), 2 * time.Second, true},
+		{"past time", RateLimited("slow down").WithRetryAt(time.Now().Add(-time.Minute)), 0, true},
+		{"wrapped", Wrap(RateLimited("slow down").WithRetryAfter(time.Second), "enqueue"), time.Second, true},
+		{"fmt wrapped", fmt.Errorf("call: %w", RateLimited("x").WithRetryAfter(time.Second)), time.Second, true},
+		{"decoded nanoseconds", New("x").WithMetadata(RetryAfterKey, float64(time.Second)), time.Second, true},
+		{"decoded string", New("x").WithMetadata(RetryAfterKey, "1500ms"), 1500 * time.Millisecond, true},
+		{"no hint", RateLimited("slow down"), 0, false},
+		{"garbage", New("x").WithMetadata(RetryAfterKey, "soon"), 0, false},
+		{"plain error", fmt.Errorf("boom"), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := RetryAfter(tt.err)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	future, ok := RetryAfter(New("x").WithRetryAt(time.Now().Add(time.Minute)))
+	require.True(t, ok)
+	assert.InDelta(t, float64(time.Minute), float64(future), float64(time.Second))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"120", 2 * time.Minute, true},
+		{" 0 ", 0, true},
+		{"Sun, 01 Mar 2026 12:00:30 GMT", 30 * time.Second, true},
+		{"Sun, 01 Mar 2026 11:00:00 GMT", 0, true},
+		{"-5", 0, false},
+		{"", 0, false},
+		{"tomorrow", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := ParseRetryAfter(tt.value, now)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFromHTTPResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/limited":
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+
+		case "/down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	get := func(path string) *http.Response {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	limited := FromHTTPResponse(get("/limited"))
+	require.NotNil(t, limited)
+	assert.Equal(t, CodeRateLimit, limited.Code)
+	assert.Equal(t, http.StatusTooManyRequests, limited.Metadata["status"])
+	assert.Contains(t, limited.Error(), "GET")
+	delay, ok := RetryAfter(limited)
+	require.True(t, ok)
+	assert.Equal(t, 3*time.Second, delay)
+
+	down := FromHTTPResponse(get("/down"))
+	assert.Equal(t, CodeNetwork, down.Code)
+	_, ok = RetryAfter(down)
+	assert.False(t, ok)
+
+	assert.Equal(t, CodeNotFound, FromHTTPResponse(get("/missing")).Code)
+	assert.Nil(t, FromHTTPResponse(get("/ok")))
+	assert.Nil(t, FromHTTPResponse(nil))
+}
@@ -0,0 +1,107 @@
+package errors
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// maxStackDepth caps the number of frames recorded per stack
+const maxStackDepth = 32
+
+// stackCaptureDisabled turns off stack capture when set
+var stackCaptureDisabled atomic.Bool
+
+// SetStackCapture enables or disables stack capture for errors created
+// afterwards. Capture is on by default; turning it off removes its cost from
+// performance-critical paths at the price of traces in %+v and ToJSON.
+func SetStackCapture(enabled bool) {
+	stackCaptureDisabled.Store(!enabled)
+}
+
+// stackTrace holds the program counters of a captured stack. They are
+// resolved into frames on first use, so errors that are never formatted or
+// encoded skip symbolization entirely. A stackTrace is shared, never copied,
+// between an error and the errors wrapping it.
+type stackTrace struct {
+	pcs    []uintptr
+	once   sync.Once
+	frames []Frame
+}
+
+// captureStack records the program counters of the current goroutine's stack,
+// skipping skip frames. It returns nil when capture is disabled.
+func captureStack(skip int) *stackTrace {
+	if stackCaptureDisabled.Load() {
+		return nil
+	}
+
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip+1, pcs)
+
+	return &stackTrace{pcs: pcs[:n]}
+}
+
+// resolvedStack returns a stack made of already-resolved frames, such as
+// frames decoded from JSON
+func resolvedStack(frames []Frame) *stackTrace {
+	if len(frames) == 0 {
+		return nil
+	}
+
+	return &stackTrace{frames: frames}
+}
+
+// Frames resolves the recorded program counters into frames
+func (s *stackTrace) Frames() []Frame {
+	if s == nil {
+		return nil
+	}
+
+	s.once.Do(func() {
+		if len(s.pcs) == 0 {
+			return
+		}
+
+		s.frames = make([]Frame, 0, len(s.pcs))
+		callers := runtime.CallersFrames(s.pcs)
+		for {
+			frame, more := callers.Next()
+			if frame.Function != "" {
+				s.frames = append(s.frames, Frame{
+					Function: frame.Function,
+					File:     frame.File,
+					Line:     frame.Line,
+				})
+			}
+
+			if !more {
+				break
+			}
+		}
+	})
+
+	return s.frames
+}
+
+// StackTrace returns the frames of the stack captured when the error was
+// created. Errors wrapping another *Error share its stack, so the trace
+// points at the origin of the failure.
+func (e *Error) StackTrace() []Frame {
+	if e == nil {
+		return nil
+	}
+
+	return e.stack.Frames()
+}
+
+// WithoutStack drops the captured stack, e.g. for expected errors whose
+// traces would only add noise to logs and responses
+func (e *Error) WithoutStack() *Error {
+	if e == nil {
+		return nil
+	}
+
+	e.stack = nil
+	return e
+}
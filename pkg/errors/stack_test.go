@@ -0,0 +1,100 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wrapThrice builds the error chain of a typical database failure
+func wrapThrice() *Error {
+	err := Wrap(io.ErrUnexpectedEOF, "query failed")
+	err = Wrap(err, "failed to load job")
+	return Wrap(err, "failed to dequeue")
+}
+
+func TestWrap_ReusesInnerStack(t *testing.T) {
+	inner := Internal("disk full")
+	outer := Wrap(fmt.Errorf("flush: %w", Wrap(inner, "write failed")), "save failed")
+
+	assert.Same(t, inner.stack, outer.stack)
+	assert.Nil(t, inner.stack.frames, "frames are resolved lazily")
+
+	frames := outer.StackTrace()
+	require.NotEmpty(t, frames)
+	assert.Contains(t, frames[0].Function, "TestWrap_ReusesInnerStack")
+}
+
+func TestWrap_CapturesForForeignErrors(t *testing.T) {
+	err := Wrap(io.EOF, "read failed")
+
+	require.NotNil(t, err.stack)
+	assert.Contains(t, err.StackTrace()[0].Function, "TestWrap_CapturesForForeignErrors")
+}
+
+func TestFormat_OriginFrameAfterWraps(t *testing.T) {
+	err := wrapThrice()
+	output := fmt.Sprintf("%+v", err)
+
+	assert.Contains(t, output, "task-queue/pkg/errors.wrapThrice")
+	assert.Equal(t, 1, strings.Count(output, "task-queue/pkg/errors.wrapThrice"),
+		"a shared stack is printed once")
+
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	assert.Equal(t, "failed to dequeue [UNKNOWN]", lines[0])
+	assert.Equal(t, "caused by: failed to load job [UNKNOWN]", lines[1])
+	assert.Equal(t, "caused by: query failed [UNKNOWN]", lines[2])
+	assert.Equal(t, "task-queue/pkg/errors.wrapThrice", lines[3])
+}
+
+func TestWithoutStack(t *testing.T) {
+	err := NotFound("job not found").WithoutStack()
+
+	assert.Nil(t, err.StackTrace())
+	assert.Empty(t, err.StackString())
+	assert.Nil(t, Wrap(err, "lookup failed").StackTrace())
+}
+
+func TestSetStackCapture(t *testing.T) {
+	SetStackCapture(false)
+	t.Cleanup(func() { SetStackCapture(true) })
+
+	assert.Nil(t, New("boom").StackTrace())
+	assert.Nil(t, Wrap(io.EOF, "boom").StackTrace())
+	assert.Equal(t, "failed to dequeue: failed to load job: query failed: unexpected EOF",
+		wrapThrice().Error())
+}
+
+func BenchmarkWrapChain(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = wrapThrice()
+	}
+}
+
+func BenchmarkWrapChainFormatted(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = fmt.Sprintf("%+v", wrapThrice())
+	}
+}
+
+func BenchmarkWrapChainWithoutCapture(b *testing.B) {
+	SetStackCapture(false)
+	b.Cleanup(func() { SetStackCapture(true) })
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = wrapThrice()
+	}
+}
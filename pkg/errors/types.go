@@ -29,7 +29,21 @@ type Error struct {
 	Cause      error          `json:"-"`
 	StatusCode int            `json:"status_code,omitempty"`
 	Metadata   map[string]any `json:"metadata,omitempty"`
-	Stack      []Frame        `json:"stack,omitempty"`
+
+	// Retryable overrides the retry classification implied by Code when set
+	Retryable *bool `json:"retryable,omitempty"`
+
+	// Op and Component name the operation and subsystem that produced the
+	// error, e.g. "Enqueue" and "queue"
+	Op        string `json:"op,omitempty"`
+	Component string `json:"component,omitempty"`
+
+	// Key and Args identify the message in the catalogs used by Localize
+	Key  string `json:"key,omitempty"`
+	Args []any  `json:"args,omitempty"`
+
+	// stack is captured when the error is created and resolved on demand
+	stack *stackTrace
 }
 
 // Frame represents a stack frame
@@ -38,3 +52,11 @@ type Frame struct {
 	File     string `json:"file"`
 	Line     int    `json:"line"`
 }
+
+// JSONOption customizes how ToJSON and Fields encode an error
+type JSONOption func(*jsonOptions)
+
+// jsonOptions holds the settings applied by JSONOption functions
+type jsonOptions struct {
+	stack bool
+}
@@ -0,0 +1,217 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Job lifecycle events recorded by AuditLogger.JobEvent
+const (
+	AuditJobCreated      = "job.created"
+	AuditJobStarted      = "job.started"
+	AuditJobCompleted    = "job.completed"
+	AuditJobFailed       = "job.failed"
+	AuditJobDeadLettered = "job.dead_lettered"
+	AuditJobRequeued     = "job.requeued"
+	AuditJobCancelled    = "job.cancelled"
+	AuditJobSLOBreached  = "job.slo_breached"
+	AuditJobEscalated    = "job.escalated"
+	AuditJobReplayed     = "job.replayed"
+)
+
+// Operator actions recorded by AuditLogger.AdminEvent
+const (
+	AuditQueuePaused           = "queue.paused"
+	AuditQueueResumed          = "queue.resumed"
+	AuditQueueDrained          = "queue.drained"
+	AuditDeadLetterRequeued    = "dead_letter.requeued"
+	AuditDeadLetterRequeuedAll = "dead_letter.requeued_all"
+	AuditDeadLetterPurged      = "dead_letter.purged"
+)
+
+// AuditConfig configures the audit logger
+type AuditConfig struct {
+	// Enabled turns the audit stream on; a disabled audit logger discards
+	// every event
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// OutputPath receives the JSON lines: stdout, stderr, or a file path
+	OutputPath string `json:"output_path" yaml:"output_path"`
+}
+
+// AuditLogger records job lifecycle transitions and operator actions as an
+// append-only stream, separate from operational logs. Entries are never sampled or filtered by
+// level.
+type AuditLogger interface {
+	// JobEvent records event for job. Each entry carries timestamp, event,
+	// job_id, type, status, worker_id, and the request_id from ctx, followed
+	// by the extra key/value pairs.
+	JobEvent(ctx context.Context, event string, job *models.Job, extra ...any)
+
+	// AdminEvent records an operator action. Each entry carries timestamp,
+	// event, actor, and the request_id from ctx, followed by the extra
+	// key/value pairs.
+	AdminEvent(ctx context.Context, event, actor string, extra ...any)
+
+	// Sync flushes buffered entries
+	Sync() error
+}
+
+// NewAudit creates an audit logger. It panics when the output cannot be
+// opened; use NewAuditWithError to handle that.
+func NewAudit(cfg AuditConfig) AuditLogger {
+	audit, err := NewAuditWithError(cfg)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return audit
+}
+
+// NewAuditWithError creates an audit logger, returning a CodeConfiguration
+// error when the output cannot be opened. A disabled config yields NopAudit.
+func NewAuditWithError(cfg AuditConfig) (AuditLogger, error) {
+	if !cfg.Enabled {
+		return NopAudit(), nil
+	}
+
+	path := cfg.OutputPath
+	if path == "" {
+		path = "stdout"
+	}
+
+	if err := validateOutputPath(path); err != nil {
+		return nil, err
+	}
+
+	sink, _, err := zap.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open audit output %s", path).
+			WithCode(errors.CodeConfiguration).
+			WithMetadata("path", path)
+	}
+
+	encoder := zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		TimeKey:        "timestamp",
+		MessageKey:     "event",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+	})
+
+	redactor, err := newRedactor(Config{RedactKeys: DefaultRedactKeys})
+	if err != nil {
+		return nil, err
+	}
+
+	// Every level is enabled so nothing configured for operational logs can
+	// suppress an audit entry
+	core := zapcore.NewCore(encoder, sink, zap.LevelEnablerFunc(func(zapcore.Level) bool {
+		return true
+	}))
+
+	return &zapAudit{logger: zap.New(newRedactingCore(core, redactor))}, nil
+}
+
+// zapAudit is the zap-backed AuditLogger
+type zapAudit struct {
+	logger *zap.Logger
+}
+
+// JobEvent records event for job
+func (a *zapAudit) JobEvent(ctx context.Context, event string, job *models.Job, extra ...any) {
+	fields := make([]zap.Field, 0, 6+len(extra)/2)
+	fields = append(fields, auditJobFields(job)...)
+	fields = append(fields, zap.String("request_id", auditRequestID(ctx)))
+	a.logger.Info(event, auditExtraFields(fields, extra)...)
+}
+
+// AdminEvent records an operator action
+func (a *zapAudit) AdminEvent(ctx context.Context, event, actor string, extra ...any) {
+	fields := make([]zap.Field, 0, 2+len(extra)/2)
+	fields = append(fields,
+		zap.String("actor", actor),
+		zap.String("request_id", auditRequestID(ctx)),
+	)
+
+	a.logger.Info(event, auditExtraFields(fields, extra)...)
+}
+
+// auditExtraFields appends the key/value pairs of extra to fields, skipping
+// pairs whose key is not a string
+func auditExtraFields(fields []zap.Field, extra []any) []zap.Field {
+	for i := 0; i+1 < len(extra); i += 2 {
+		key, ok := extra[i].(string)
+		if !ok {
+			continue
+		}
+
+		fields = append(fields, zap.Any(key, extra[i+1]))
+	}
+
+	return fields
+}
+
+// Sync flushes buffered entries
+func (a *zapAudit) Sync() error {
+	return a.logger.Sync()
+}
+
+// auditJobFields returns the fixed job fields of an audit entry, empty when
+// job is nil
+func auditJobFields(job *models.Job) []zap.Field {
+	var id, jobType, status, workerID string
+	if job != nil {
+		id, jobType, status = job.ID.String(), job.Type, string(job.Status)
+		if job.WorkerID != nil {
+			workerID = *job.WorkerID
+		}
+	}
+
+	return []zap.Field{
+		zap.String("job_id", id),
+		zap.String("type", jobType),
+		zap.String("status", status),
+		zap.String("worker_id", workerID),
+	}
+}
+
+// auditRequestID returns the request ID carried by ctx, or an empty string
+func auditRequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	requestID := ctx.Value(RequestIDKey)
+	if requestID == nil {
+		return ""
+	}
+
+	return fmt.Sprint(requestID)
+}
+
+// nopAudit discards every event
+type nopAudit struct{}
+
+// NopAudit returns an audit logger that discards every event, used when
+// auditing is disabled
+func NopAudit() AuditLogger {
+	return nopAudit{}
+}
+
+// JobEvent discards the event
+func (nopAudit) JobEvent(context.Context, string, *models.Job, ...any) {}
+
+// AdminEvent discards the event
+func (nopAudit) AdminEvent(context.Context, string, string, ...any) {}
+
+// Sync does nothing
+func (nopAudit) Sync() error {
+	return nil
+}
@@ -0,0 +1,140 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// auditLogger returns an enabled audit logger writing to a temp file, and a
+// function decoding the written lines
+func auditLogger(t *testing.T) (AuditLogger, func() []map[string]any) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit := NewAudit(AuditConfig{Enabled: true, OutputPath: path})
+
+	return audit, func() []map[string]any {
+		require.NoError(t, audit.Sync())
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+
+		var entries []map[string]any
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+			entries = append(entries, entry)
+		}
+
+		require.NoError(t, scanner.Err())
+		return entries
+	}
+}
+
+func TestAudit_JobEventSchema(t *testing.T) {
+	audit, entries := auditLogger(t)
+
+	job := models.NewJob("email.send", json.RawMessage(`{}`), models.JobPriorityHigh)
+	job.Status = models.JobStatusRunning
+	job.WorkerID = ptrTo("worker-7")
+	ctx := context.WithValue(context.Background(), RequestIDKey, "req-123")
+
+	audit.JobEvent(ctx, AuditJobStarted, job, "queue", "default", "token", "abc")
+
+	got := entries()
+	require.Len(t, got, 1)
+	entry := got[0]
+
+	assert.Equal(t, AuditJobStarted, entry["event"])
+	assert.NotEmpty(t, entry["timestamp"])
+	assert.Equal(t, job.ID.String(), entry["job_id"])
+	assert.Equal(t, "email.send", entry["type"])
+	assert.Equal(t, "running", entry["status"])
+	assert.Equal(t, "worker-7", entry["worker_id"])
+	assert.Equal(t, "req-123", entry["request_id"])
+	assert.Equal(t, "default", entry["queue"])
+	assert.Equal(t, RedactedValue, entry["token"])
+	assert.NotContains(t, entry, "level")
+}
+
+func TestAudit_FixedSchemaWithoutJob(t *testing.T) {
+	audit, entries := auditLogger(t)
+
+	audit.JobEvent(context.Background(), AuditJobFailed, nil)
+
+	got := entries()
+	require.Len(t, got, 1)
+	for _, key := range []string{"timestamp", "event", "job_id", "type", "status", "worker_id", "request_id"} {
+		assert.Contains(t, got[0], key)
+	}
+}
+
+func TestAudit_AdminEventSchema(t *testing.T) {
+	audit, entries := auditLogger(t)
+	ctx := context.WithValue(context.Background(), RequestIDKey, "req-456")
+
+	audit.AdminEvent(ctx, AuditQueuePaused, "ops-console", "queue", "default")
+
+	got := entries()
+	require.Len(t, got, 1)
+	entry := got[0]
+
+	assert.Equal(t, AuditQueuePaused, entry["event"])
+	assert.NotEmpty(t, entry["timestamp"])
+	assert.Equal(t, "ops-console", entry["actor"])
+	assert.Equal(t, "req-456", entry["request_id"])
+	assert.Equal(t, "default", entry["queue"])
+	assert.NotContains(t, entry, "job_id")
+}
+
+func TestAudit_NeverSampledOrFiltered(t *testing.T) {
+	audit, entries := auditLogger(t)
+
+	// Settings that would suppress most operational entries have no effect
+	// on the audit stream
+	cfg := DefaultConfig()
+	cfg.Level = "fatal"
+	cfg.OutputPaths = []string{filepath.Join(t.TempDir(), "app.log")}
+	cfg.Sampling = SamplingConfig{Enabled: true, Initial: 1, Thereafter: 1000}
+	defer ReplaceGlobal(New(cfg))()
+
+	job := models.NewJob("report.build", nil, models.JobPriorityLow)
+	for i := 0; i < 500; i++ {
+		audit.JobEvent(context.Background(), AuditJobCompleted, job)
+	}
+
+	assert.Len(t, entries(), 500)
+}
+
+func TestNewAudit_Disabled(t *testing.T) {
+	audit := NewAudit(AuditConfig{OutputPath: filepath.Join(t.TempDir(), "audit.log")})
+	assert.Equal(t, NopAudit(), audit)
+
+	audit.JobEvent(context.Background(), AuditJobCreated, nil)
+	assert.NoError(t, audit.Sync())
+}
+
+func TestNewAuditWithError_InvalidOutput(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "not-a-dir")
+	require.NoError(t, os.WriteFile(file, nil, 0o600))
+
+	_, err := NewAuditWithError(AuditConfig{Enabled: true, OutputPath: filepath.Join(file, "audit.log")})
+	require.Error(t, err)
+	assert.True(t, errors.HasCode(err, errors.CodeConfiguration))
+}
+
+// ptrTo returns a pointer to v
+func ptrTo[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"task-queue/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// renderConsole writes one warning through a console encoder into a buffer
+func renderConsole(t *testing.T, format string, color bool) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	core := zapcore.NewCore(newEncoder(format, color), zapcore.AddSync(&buf), zap.DebugLevel)
+	zap.New(core).Warn("disk almost full")
+	return buf.String()
+}
+
+func TestColor_AutoWithoutTerminal(t *testing.T) {
+	// A buffer is never a terminal, so auto mode renders without escapes
+	out := renderConsole(t, FormatConsole, useColor(ColorAuto, false))
+
+	assert.Contains(t, out, "WARN")
+	assert.NotContains(t, out, "\x1b")
+}
+
+func TestColor_Modes(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		mode      string
+		terminal  bool
+		wantColor bool
+	}{
+		{name: "auto on terminal", format: FormatConsole, mode: ColorAuto, terminal: true, wantColor: true},
+		{name: "always when redirected", format: FormatConsole, mode: ColorAlways, wantColor: true},
+		{name: "never on terminal", format: FormatConsole, mode: ColorNever, terminal: true},
+		{name: "plain always", format: FormatConsolePlain, mode: ColorAlways, terminal: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := renderConsole(t, tt.format, useColor(tt.mode, tt.terminal))
+			assert.Equal(t, tt.wantColor, bytes.Contains([]byte(out), []byte("\x1b")))
+			assert.Contains(t, out, " | ", "console layout is kept")
+		})
+	}
+}
+
+func TestColor_FileOutputsArePlainInAutoMode(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.Format = FormatConsole
+	cfg.Outputs = []OutputConfig{
+		{Path: filepath.Join(dir, "auto.log")},
+		{Path: filepath.Join(dir, "always.log"), Color: ColorAlways},
+	}
+	log := New(cfg)
+
+	log.Warn("disk almost full")
+	require.NoError(t, log.Sync())
+
+	assert.False(t, isTerminal(filepath.Join(dir, "auto.log")))
+	assert.NotContains(t, readFile(t, filepath.Join(dir, "auto.log")), "\x1b")
+	assert.Contains(t, readFile(t, filepath.Join(dir, "always.log")), "\x1b")
+}
+
+func TestColor_InvalidMode(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Color = "rainbow"
+
+	_, err := NewWithError(cfg)
+	require.Error(t, err)
+	assert.True(t, errors.HasCode(err, errors.CodeConfiguration))
+}
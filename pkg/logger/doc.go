@@ -3,7 +3,9 @@
 // awareness, request ID tracking, and standardized log formatting.
 //
 // The logger supports multiple output formats (JSON, Console) and log levels,
-// with built-in support for distributed tracing correlation.
+// with built-in support for distributed tracing correlation. Console levels
+// are colored only when the output is a terminal unless Config.Color says
+// otherwise, and the console-plain format never colors them.
 //
 // Basic usage:
 //
@@ -21,4 +23,58 @@
 // With fields:
 //
 //	log.With("user_id", "123").Error("failed to process", "error", err)
+//
+// Typed fields can be mixed with loose pairs and cannot be mis-paired, and
+// Lazy defers expensive values until an entry is actually written.
+// Development loggers panic on mis-paired loose pairs (see CheckPairs):
+//
+//	log.Debug("job dequeued", logger.UUID("job_id", job.ID),
+//	    "payload_keys", logger.Lazy(func() any { return job.PayloadKeys() }))
+//
+// Errors from pkg/errors, attached with WithError or passed under the
+// "error" key, are logged as structured fields (error.code, error.message,
+// error.op, error.metadata.*), with the error's captured stack added at Error
+// level when Config.ErrorStacks and Config.Stacktrace are set:
+//
+//	log.WithError(err).Error("failed to enqueue job")
+//	log.Error("failed to enqueue job", "error", err)
+//
+// Outputs writes to several destinations, each with its own format, level,
+// and size-based rotation:
+//
+//	cfg.Outputs = []logger.OutputConfig{
+//	    {Path: "stdout", Format: "console", Level: "info"},
+//	    {Path: "/var/log/task-queue/app.log", Format: "json", Level: "debug",
+//	        Rotation: logger.RotationConfig{MaxSizeMB: 100, MaxBackups: 5}},
+//	}
+//
+// The level can be changed at runtime, and LevelHandler exposes it over HTTP
+// for the admin port:
+//
+//	mux.Handle("/admin/log/level", logger.LevelHandler(log))
+//	// curl -X PUT -d '{"level":"debug"}' localhost:9090/admin/log/level
+//
+// Sampling, off by default, keeps an error storm from making the logger the
+// bottleneck. Entries that must never be dropped carry NoSample:
+//
+//	cfg.Sampling = logger.SamplingConfig{Enabled: true, Initial: 100, Thereafter: 100}
+//	log.Error("worker panicked", logger.NoSample(), "panic", r)
+//
+// Values of sensitive keys such as password and authorization are written
+// as [REDACTED], also inside map fields one level deep, and long values are
+// truncated to Config.MaxFieldBytes. Secret masks a value whatever its key:
+//
+//	log.Info("connecting", "dsn", logger.Secret(dsn))
+//
+// Hooks forward entries to external systems. Each runs asynchronously
+// behind a bounded queue, so a slow sink drops entries rather than blocking
+// the caller:
+//
+//	cfg.Hooks = []logger.Hook{logger.NewWebhookHook(alertURL, "error")}
+//
+// Job lifecycle transitions go to a separate audit stream with a fixed
+// schema, which is never sampled or filtered by level:
+//
+//	audit := logger.NewAudit(logger.AuditConfig{Enabled: true, OutputPath: "/var/log/task-queue/audit.log"})
+//	audit.JobEvent(ctx, logger.AuditJobCompleted, job)
 package logger
@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Field is a typed key/value pair. Fields can be mixed with loose key/value
+// pairs in the variadic arguments of the logging methods and cannot be
+// mis-paired:
+//
+//	log.Info("job enqueued", logger.UUID("job_id", job.ID), "queue", name)
+type Field = zap.Field
+
+// String returns a string field
+func String(key, value string) Field {
+	return zap.String(key, value)
+}
+
+// Int returns an int field
+func Int(key string, value int) Field {
+	return zap.Int(key, value)
+}
+
+// Duration returns a duration field
+func Duration(key string, value time.Duration) Field {
+	return zap.Duration(key, value)
+}
+
+// Err returns an error field under the "error" key. An *errors.Error is
+// logged as structured fields, as with WithError.
+func Err(err error) Field {
+	return zap.Error(err)
+}
+
+// UUID returns a UUID field rendered as a string
+func UUID(key string, value uuid.UUID) Field {
+	return zap.Stringer(key, value)
+}
+
+// Any returns a field of any value, choosing the encoding from its type
+func Any(key string, value any) Field {
+	return zap.Any(key, value)
+}
+
+// LazyValue is a log value computed only when an entry holding it is
+// encoded. It is computed at most once per entry, however many outputs
+// encode it.
+type LazyValue struct {
+	once  sync.Once
+	fn    func() any
+	value any
+}
+
+// Lazy returns a value computed by fn only when the entry is encoded, so
+// expensive values cost nothing when the level filters the entry out:
+//
+//	log.Debug("job dequeued", "payload_keys", logger.Lazy(func() any {
+//	    return job.PayloadKeys()
+//	}))
+func Lazy(fn func() any) *LazyValue {
+	return &LazyValue{fn: fn}
+}
+
+// Value computes the value on first use
+func (v *LazyValue) Value() any {
+	v.once.Do(func() {
+		v.value = v.fn()
+		v.fn = nil
+	})
+
+	return v.value
+}
+
+// MarshalJSON encodes the computed value
+func (v *LazyValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.Value())
+}
+
+// CheckPairs reports malformed loose key/value pairs: a key without a value
+// and keys that are not strings, such as a UUID logged before its key.
+// Field values are skipped. Loggers built with Config.Development call it
+// on every entry and panic on failure, so tests catch mis-paired calls.
+func CheckPairs(keysAndValues ...any) error {
+	var problems []string
+	for i := 0; i < len(keysAndValues); i++ {
+		if _, ok := keysAndValues[i].(zapcore.Field); ok {
+			continue
+		}
+
+		if i+1 == len(keysAndValues) {
+			problems = append(problems, fmt.Sprintf("key %v at %d has no value", keysAndValues[i], i))
+			break
+		}
+
+		if _, ok := keysAndValues[i].(string); !ok {
+			problems = append(problems, fmt.Sprintf("key at %d is %T %v, not a string",
+				i, keysAndValues[i], keysAndValues[i]))
+		}
+
+		i++
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("malformed log fields: %s", strings.Join(problems, "; "))
+}
@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"task-queue/pkg/errors"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypedFields(t *testing.T) {
+	log, logs := observedLogger(false)
+	id := uuid.New()
+
+	log.Info("job enqueued",
+		UUID("job_id", id),
+		"queue", "default",
+		String("type", "email.send"),
+		Int("priority", 2),
+		Duration("delay", time.Second),
+		Any("tags", []string{"a"}),
+	)
+
+	require.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, id.String(), fields["job_id"])
+	assert.Equal(t, "default", fields["queue"])
+	assert.Equal(t, "email.send", fields["type"])
+	assert.EqualValues(t, 2, fields["priority"])
+	assert.Equal(t, time.Second, fields["delay"])
+	assert.Equal(t, []any{"a"}, fields["tags"])
+}
+
+func TestErrField_StructuredError(t *testing.T) {
+	log, logs := observedLogger(true)
+
+	log.Error("enqueue failed", Err(errors.Internal("disk full")), "queue", "default")
+
+	require.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, string(errors.CodeInternal), fields["error.code"])
+	assert.Equal(t, "default", fields["queue"])
+	assert.Contains(t, fields["error.stack"], "TestErrField_StructuredError")
+	assert.NotContains(t, fields, "error")
+}
+
+func TestLazy_NotEvaluatedWhenFiltered(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.OutputPaths = []string{filepath.Join(t.TempDir(), "out.log")}
+	log := New(cfg)
+
+	calls := 0
+	log.Debug("payload", "keys", Lazy(func() any {
+		calls++
+		return []string{"to"}
+	}))
+	require.NoError(t, log.Sync())
+
+	assert.Zero(t, calls)
+}
+
+func TestLazy_EvaluatedOnceAcrossOutputs(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.Outputs = []OutputConfig{
+		{Path: filepath.Join(dir, "a.log")},
+		{Path: filepath.Join(dir, "b.log")},
+	}
+	log := New(cfg)
+
+	calls := 0
+	log.Info("payload", "keys", Lazy(func() any {
+		calls++
+		return []string{"subject", "to"}
+	}))
+	require.NoError(t, log.Sync())
+
+	assert.Equal(t, 1, calls)
+	assert.Contains(t, readFile(t, filepath.Join(dir, "a.log")), `"keys":["subject","to"]`)
+	assert.Contains(t, readFile(t, filepath.Join(dir, "b.log")), `"keys":["subject","to"]`)
+}
+
+func TestCheckPairs(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name          string
+		keysAndValues []any
+		wantErr       string
+	}{
+		{
+			name:          "well formed",
+			keysAndValues: []any{"job_id", id, String("type", "x"), "queue", "default"},
+		},
+		{
+			name:          "odd length",
+			keysAndValues: []any{"job_id", id, "queue"},
+			wantErr:       "key queue at 2 has no value",
+		},
+		{
+			name:          "value before key",
+			keysAndValues: []any{id, "job_id"},
+			wantErr:       "not a string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckPairs(tt.keysAndValues...)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestDevelopment_PanicsOnMisPairedFields(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Development = true
+	cfg.OutputPaths = []string{filepath.Join(t.TempDir(), "out.log")}
+	log := New(cfg)
+
+	assert.Panics(t, func() { log.Debug("job", "job_id") })
+	assert.NotPanics(t, func() { log.Debug("job", "job_id", "1") })
+
+	cfg.Development = false
+	assert.NotPanics(t, func() { New(cfg).Info("job", "job_id") })
+}
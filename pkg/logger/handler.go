@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"task-queue/pkg/errors"
+)
+
+// levelBody is the JSON document read and written by LevelHandler
+type levelBody struct {
+	Level  string `json:"level"`
+	Output string `json:"output,omitempty"`
+}
+
+// LevelHandler returns an http.Handler that reports the level of l on GET
+// and changes it on PUT with a body such as {"level":"debug"}, or
+// {"level":"debug","output":"/var/log/app.log"} to change one output. Both
+// respond with the current level. Mount it on the admin port only.
+func LevelHandler(l Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+
+		case http.MethodPut:
+			var body levelBody
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				errors.WriteProblem(w, errors.Wrap(err, "invalid level request body").
+					WithCode(errors.CodeValidation), r)
+				return
+			}
+
+			var outputs []string
+			if body.Output != "" {
+				outputs = append(outputs, body.Output)
+			}
+
+			if err := l.SetLevel(body.Level, outputs...); err != nil {
+				errors.WriteProblem(w, err, r)
+				return
+			}
+
+			l.Info("log level changed", "level", body.Level, "output", body.Output)
+
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			errors.WriteProblem(w, errors.Newf("method %s not allowed", r.Method).
+				WithCode(errors.CodeValidation).
+				WithStatusCode(http.StatusMethodNotAllowed), r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(levelBody{Level: l.Level()})
+	})
+}
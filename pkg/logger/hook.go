@@ -0,0 +1,318 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultHookQueueSize is the number of entries buffered per hook when
+// Config.HookQueueSize is unset
+const DefaultHookQueueSize = 1024
+
+// Entry is a log entry delivered to a Hook
+type Entry struct {
+	Level   string         `json:"level"`
+	Message string         `json:"message"`
+	Logger  string         `json:"logger,omitempty"`
+	Caller  string         `json:"caller,omitempty"`
+	Time    time.Time      `json:"time"`
+	Fields  map[string]any `json:"fields,omitempty"`
+}
+
+// Hook receives log entries, e.g. to forward errors to an external system.
+// Hooks run on their own goroutine, one entry at a time.
+type Hook interface {
+	Fire(entry Entry) error
+}
+
+// LevelHook is a Hook that receives only entries at or above MinLevel.
+// Hooks that do not implement it receive error and fatal entries.
+type LevelHook interface {
+	Hook
+	MinLevel() string
+}
+
+// leveledHook restricts a Hook to a minimum level
+type leveledHook struct {
+	Hook
+	level string
+}
+
+// MinLevel returns the minimum level
+func (h leveledHook) MinLevel() string {
+	return h.level
+}
+
+// AtLevel returns h restricted to entries at or above level
+func AtLevel(level string, h Hook) LevelHook {
+	return leveledHook{Hook: h, level: level}
+}
+
+// hookStats counts hook outcomes across the process
+var hookStats struct {
+	failures atomic.Uint64
+	dropped  atomic.Uint64
+}
+
+// HookFailures returns the number of entries hooks failed to handle
+func HookFailures() uint64 {
+	return hookStats.failures.Load()
+}
+
+// HookDropped returns the number of entries dropped because a hook's queue
+// was full
+func HookDropped() uint64 {
+	return hookStats.dropped.Load()
+}
+
+// hookDispatcher feeds one hook from a bounded queue, so a slow hook never
+// blocks the logging call
+type hookDispatcher struct {
+	hook     Hook
+	minLevel zapcore.Level
+	queue    chan Entry
+	fallback zapcore.Core
+	failOnce sync.Once
+}
+
+// newHookDispatcher starts the goroutine delivering entries to hook.
+// Failures are reported once through fallback, which never reaches hooks.
+func newHookDispatcher(hook Hook, queueSize int, fallback zapcore.Core) (*hookDispatcher, error) {
+	minLevel := zap.ErrorLevel
+	if leveled, ok := hook.(LevelHook); ok {
+		parsed, known := levels[leveled.MinLevel()]
+		if !known {
+			return nil, fmt.Errorf("unknown hook level %q", leveled.MinLevel())
+		}
+
+		minLevel = parsed
+	}
+
+	d := &hookDispatcher{
+		hook:     hook,
+		minLevel: minLevel,
+		queue:    make(chan Entry, queueSize),
+		fallback: fallback,
+	}
+
+	go d.run()
+	return d, nil
+}
+
+// run delivers queued entries to the hook
+func (d *hookDispatcher) run() {
+	for entry := range d.queue {
+		if err := d.hook.Fire(entry); err != nil {
+			hookStats.failures.Add(1)
+			d.failOnce.Do(func() { d.reportFailure(err) })
+		}
+	}
+}
+
+// reportFailure logs the first failure of the hook directly to the
+// fallback core, so it cannot be fed back to hooks
+func (d *hookDispatcher) reportFailure(err error) {
+	entry := zapcore.Entry{
+		Level:      zap.WarnLevel,
+		Time:       time.Now(),
+		LoggerName: "logger",
+		Message:    "log hook failed, further failures are only counted",
+	}
+
+	_ = d.fallback.Write(entry, []zapcore.Field{
+		zap.String("hook", fmt.Sprintf("%T", d.hook)),
+		zap.Error(err),
+	})
+}
+
+// enqueue hands entry to the hook without blocking, dropping it when the
+// queue is full
+func (d *hookDispatcher) enqueue(entry Entry) {
+	select {
+	case d.queue <- entry:
+	default:
+		hookStats.dropped.Add(1)
+	}
+}
+
+// hookCore is a zapcore.Core that turns entries into Entry values for the
+// hooks whose level they reach
+type hookCore struct {
+	dispatchers []*hookDispatcher
+	minLevel    zapcore.Level
+	fields      []zapcore.Field
+}
+
+// newHookCore starts a dispatcher per hook and returns the core feeding them
+func newHookCore(hooks []Hook, queueSize int, fallback zapcore.Core) (*hookCore, error) {
+	if queueSize <= 0 {
+		queueSize = DefaultHookQueueSize
+	}
+
+	c := &hookCore{minLevel: zapcore.InvalidLevel}
+	for _, hook := range hooks {
+		d, err := newHookDispatcher(hook, queueSize, fallback)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(c.dispatchers) == 0 || d.minLevel < c.minLevel {
+			c.minLevel = d.minLevel
+		}
+
+		c.dispatchers = append(c.dispatchers, d)
+	}
+
+	return c, nil
+}
+
+// Enabled reports whether any hook wants entries at level
+func (c *hookCore) Enabled(level zapcore.Level) bool {
+	return len(c.dispatchers) > 0 && level >= c.minLevel
+}
+
+// With returns a core adding fields to every entry
+func (c *hookCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	return &clone
+}
+
+// Check adds c to the checked entry when a hook wants it
+func (c *hookCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+
+	return checked
+}
+
+// Write queues the entry for every hook whose level it reaches
+func (c *hookCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	e := Entry{
+		Level:   entry.Level.String(),
+		Message: entry.Message,
+		Logger:  entry.LoggerName,
+		Time:    entry.Time,
+		Fields:  enc.Fields,
+	}
+
+	if entry.Caller.Defined {
+		e.Caller = entry.Caller.TrimmedPath()
+	}
+
+	for _, d := range c.dispatchers {
+		if entry.Level >= d.minLevel {
+			d.enqueue(e)
+		}
+	}
+
+	return nil
+}
+
+// Sync is a no-op; hooks deliver asynchronously
+func (c *hookCore) Sync() error {
+	return nil
+}
+
+// ChannelHook sends entries to a channel, e.g. for tests or for an
+// in-process consumer
+type ChannelHook struct {
+	ch    chan<- Entry
+	level string
+}
+
+// NewChannelHook returns a hook sending entries at or above level to ch.
+// A consumer that stops reading fills the hook's queue, after which entries
+// are dropped; logging never blocks.
+func NewChannelHook(ch chan<- Entry, level string) *ChannelHook {
+	return &ChannelHook{ch: ch, level: level}
+}
+
+// Fire sends entry to the channel
+func (h *ChannelHook) Fire(entry Entry) error {
+	h.ch <- entry
+	return nil
+}
+
+// MinLevel returns the minimum level
+func (h *ChannelHook) MinLevel() string {
+	return h.level
+}
+
+// WebhookHook posts each entry as JSON to an HTTP endpoint, e.g. a Slack
+// incoming webhook relay or an alerting gateway
+type WebhookHook struct {
+	URL     string
+	Level   string
+	Headers http.Header
+	Client  *http.Client
+}
+
+// NewWebhookHook returns a hook posting entries at or above level to url
+// with a five second timeout
+func NewWebhookHook(url, level string) *WebhookHook {
+	return &WebhookHook{
+		URL:    url,
+		Level:  level,
+		Client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Fire posts entry and fails on transport errors and non-2xx responses
+func (h *WebhookHook) Fire(entry Entry) error {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encode log entry: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build webhook request: %w", err)
+	}
+
+	for key, values := range h.Headers {
+		req.Header[key] = values
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post log entry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("post log entry: unexpected status %s", resp.Status)
+	}
+
+	return nil
+}
+
+// MinLevel returns the minimum level
+func (h *WebhookHook) MinLevel() string {
+	return h.Level
+}
@@ -0,0 +1,182 @@
+package logger
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hookedLogger returns a logger writing to a temp file and feeding hooks
+func hookedLogger(t *testing.T, queueSize int, hooks ...Hook) Logger {
+	t.Helper()
+
+	cfg := DefaultConfig()
+	cfg.OutputPaths = []string{filepath.Join(t.TempDir(), "out.log")}
+	cfg.Hooks = hooks
+	cfg.HookQueueSize = queueSize
+	return New(cfg)
+}
+
+// receive waits for the next entry on ch
+func receive(t *testing.T, ch <-chan Entry) Entry {
+	t.Helper()
+
+	select {
+	case entry := <-ch:
+		return entry
+
+	case <-time.After(2 * time.Second):
+		t.Fatal("no entry delivered to hook")
+		return Entry{}
+	}
+}
+
+// hookFunc adapts a function to Hook
+type hookFunc func(Entry) error
+
+func (f hookFunc) Fire(entry Entry) error {
+	return f(entry)
+}
+
+func TestHook_LevelFiltering(t *testing.T) {
+	errors := make(chan Entry, 10)
+	warnings := make(chan Entry, 10)
+	log := hookedLogger(t, 0,
+		NewChannelHook(errors, "error"),
+		NewChannelHook(warnings, "warn"),
+	)
+
+	log.Info("started")
+	log.Warn("slow query")
+	log.Error("query failed")
+
+	assert.Equal(t, "query failed", receive(t, errors).Message)
+	assert.Equal(t, "slow query", receive(t, warnings).Message)
+	assert.Equal(t, "query failed", receive(t, warnings).Message)
+	assert.Empty(t, errors)
+	assert.Empty(t, warnings)
+}
+
+func TestHook_DefaultsToErrorLevel(t *testing.T) {
+	ch := make(chan Entry, 10)
+	log := hookedLogger(t, 0, hookFunc(func(e Entry) error {
+		ch <- e
+		return nil
+	}))
+
+	log.Warn("ignored")
+	log.Error("forwarded")
+
+	assert.Equal(t, "forwarded", receive(t, ch).Message)
+	assert.Empty(t, ch)
+}
+
+func TestHook_FieldPropagation(t *testing.T) {
+	ch := make(chan Entry, 1)
+	log := hookedLogger(t, 0, NewChannelHook(ch, "error"))
+
+	log.With("job_id", "job-1").Error("job failed", "attempt", 3, "password", "hunter2")
+
+	entry := receive(t, ch)
+	assert.Equal(t, "error", entry.Level)
+	assert.Equal(t, "job failed", entry.Message)
+	assert.False(t, entry.Time.IsZero())
+	assert.Equal(t, "job-1", entry.Fields["job_id"])
+	assert.EqualValues(t, 3, entry.Fields["attempt"])
+	assert.Equal(t, RedactedValue, entry.Fields["password"])
+}
+
+func TestHook_StalledHookDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	stalled := AtLevel("info", hookFunc(func(Entry) error {
+		<-release
+		return nil
+	}))
+
+	log := hookedLogger(t, 4, stalled)
+	dropped := HookDropped()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			log.Info("tick", "i", i)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("logging blocked on a stalled hook")
+	}
+
+	// One entry is held by the hook and at most four are queued
+	assert.GreaterOrEqual(t, HookDropped()-dropped, uint64(100-5))
+}
+
+func TestHook_FailuresCounted(t *testing.T) {
+	fired := make(chan struct{}, 3)
+	log := hookedLogger(t, 0, hookFunc(func(Entry) error {
+		defer func() { fired <- struct{}{} }()
+		return stderrors.New("sink unavailable")
+	}))
+
+	failures := HookFailures()
+	for i := 0; i < 3; i++ {
+		log.Error("boom")
+	}
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-fired:
+		case <-time.After(2 * time.Second):
+			t.Fatal("hook not fired")
+		}
+	}
+
+	assert.Eventually(t, func() bool {
+		return HookFailures()-failures == 3
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestNew_InvalidHookLevel(t *testing.T) {
+	assert.Panics(t, func() {
+		hookedLogger(t, 0, AtLevel("loud", hookFunc(func(Entry) error { return nil })))
+	})
+}
+
+func TestWebhookHook(t *testing.T) {
+	received := make(chan Entry, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+
+		var entry Entry
+		if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		received <- entry
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	hook := NewWebhookHook(server.URL, "error")
+	require.NoError(t, hook.Fire(Entry{Level: "error", Message: "disk full"}))
+	assert.Equal(t, "disk full", receive(t, received).Message)
+
+	hook.URL = server.URL + "/missing"
+	assert.Error(t, hook.Fire(Entry{Level: "error", Message: "disk full"}))
+}
@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"task-queue/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fileLogger returns a JSON logger at level writing to a temp file, and a
+// function returning what was written so far
+func fileLogger(t *testing.T, level string) (Logger, func() string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "out.log")
+	log := New(Config{Level: level, Format: "json", OutputPaths: []string{path}})
+
+	return log, func() string {
+		require.NoError(t, log.Sync())
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(data)
+	}
+}
+
+func TestLogger_SetLevel(t *testing.T) {
+	log, output := fileLogger(t, "info")
+	child := log.Named("worker").With("worker_id", 1)
+	assert.Equal(t, "info", child.Level())
+
+	child.Debug("hidden before the change")
+	require.NoError(t, log.SetLevel("debug"))
+	child.Debug("visible after the change")
+
+	out := output()
+	assert.NotContains(t, out, "hidden before the change")
+	assert.Contains(t, out, "visible after the change")
+	assert.Equal(t, "debug", child.Level(), "children share the parent's level")
+
+	require.NoError(t, child.SetLevel("error"))
+	assert.Equal(t, "error", log.Level())
+	log.Warn("suppressed warning")
+	assert.NotContains(t, output(), "suppressed warning")
+}
+
+func TestLogger_SetLevelInvalid(t *testing.T) {
+	log, _ := fileLogger(t, "warn")
+
+	err := log.SetLevel("verbose")
+	require.Error(t, err)
+	assert.True(t, errors.IsValidation(err))
+	assert.Equal(t, "warn", log.Level())
+}
+
+func TestNopLogger_SetLevel(t *testing.T) {
+	log := NewNop()
+
+	assert.NoError(t, log.SetLevel("debug"))
+	assert.Empty(t, log.Level())
+	assert.Error(t, log.SetLevel("loud"))
+}
+
+func TestLevelHandler(t *testing.T) {
+	log, output := fileLogger(t, "info")
+	handler := LevelHandler(log)
+
+	serve := func(method, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, "/admin/log/level", strings.NewReader(body)))
+		return rec
+	}
+
+	rec := serve(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"level":"info"}`, rec.Body.String())
+
+	log.Debug("before put")
+	rec = serve(http.MethodPut, `{"level":"debug"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"level":"debug"}`, rec.Body.String())
+	log.Debug("after put")
+
+	out := output()
+	assert.NotContains(t, out, "before put")
+	assert.Contains(t, out, "after put")
+
+	rec = serve(http.MethodPut, `{"level":"chatty"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "unknown log level")
+	assert.Equal(t, "debug", log.Level())
+
+	rec = serve(http.MethodPut, `not json`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serve(http.MethodPost, `{"level":"warn"}`)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, PUT", rec.Header().Get("Allow"))
+}
@@ -2,42 +2,145 @@ package logger
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"os"
+	"sync/atomic"
 	"time"
 
+	"task-queue/pkg/errors"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-// New creates a new logger instance with the given configuration
+// New creates a new logger instance with the given configuration. It panics
+// when the configuration is invalid; use NewWithError where that must not
+// take the process down, such as on configuration reload.
 func New(cfg Config) Logger {
-	zapConfig := zap.Config{
-		Level:             parseLevel(cfg.Level),
-		Development:       cfg.Development,
-		DisableCaller:     !cfg.Caller,
-		DisableStacktrace: !cfg.Stacktrace,
-		Encoding:          cfg.Format,
-		EncoderConfig:     getEncoderConfig(cfg.Format),
-		OutputPaths:       cfg.OutputPaths,
-		ErrorOutputPaths:  cfg.ErrorPaths,
-		InitialFields: map[string]any{
-			"app": "task-queue",
-			"pid": os.Getpid(),
-		},
-	}
-
-	logger, err := zapConfig.Build(
-		zap.AddCallerSkip(1),
-	)
-
+	l, err := NewWithError(cfg)
 	if err != nil {
 		panic(fmt.Sprintf("failed to initialize logger: %v", err))
 	}
 
-	return &zapLogger{
-		sugar: logger.Sugar(),
+	return l
+}
+
+// NewWithError creates a new logger instance with the given configuration,
+// returning a CodeConfiguration error when it is invalid or the outputs
+// cannot be opened
+func NewWithError(cfg Config) (Logger, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	core, outLevels, err := buildCores(cfg.outputs())
+	if err != nil {
+		return nil, err
+	}
+
+	errorSink, _, err := zap.Open(cfg.ErrorPaths...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open log error output").
+			WithCode(errors.CodeConfiguration)
+	}
+
+	redactor, err := newRedactor(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.Hooks) > 0 {
+		hooks, err := newHookCore(cfg.Hooks, cfg.HookQueueSize, core)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid log hook").
+				WithCode(errors.CodeConfiguration)
+		}
+
+		core = newLevelTee(core, hooks)
+	}
+
+	if cfg.ErrorStacks && cfg.Stacktrace {
+		core = &errorStackCore{Core: core}
+	}
+
+	if redactor != nil {
+		core = newRedactingCore(core, redactor)
+	}
+
+	opts := []zap.Option{
+		zap.ErrorOutput(errorSink),
+		zap.AddCallerSkip(1),
+		zap.Fields(zap.String("app", "task-queue"), zap.Int("pid", os.Getpid())),
+	}
+
+	if cfg.Development {
+		opts = append(opts, zap.Development())
+	}
+
+	if cfg.Caller {
+		opts = append(opts, zap.AddCaller())
+	}
+
+	if cfg.Stacktrace {
+		stackLevel := zap.ErrorLevel
+		if cfg.Development {
+			stackLevel = zap.WarnLevel
+		}
+
+		opts = append(opts, zap.AddStacktrace(stackLevel))
 	}
+
+	logger := zap.New(core, opts...)
+	l := &zapLogger{
+		sugar:       logger.Sugar(),
+		outputs:     outLevels,
+		errorStacks: cfg.ErrorStacks && cfg.Stacktrace,
+		development: cfg.Development,
+	}
+
+	if cfg.Sampling.Enabled {
+		l.unsampled = l.sugar
+		l.sugar = logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return sampledCore(core, cfg.Sampling)
+		})).Sugar()
+	}
+
+	return l, nil
+}
+
+// errorStackCore drops zap's stack of the logging call site from entries
+// that carry the stack captured by an *errors.Error, which is the one that
+// locates the failure
+type errorStackCore struct {
+	zapcore.Core
+}
+
+// With adds fields to the wrapped core
+func (c *errorStackCore) With(fields []zapcore.Field) zapcore.Core {
+	return &errorStackCore{Core: c.Core.With(fields)}
+}
+
+// Check adds c, rather than the wrapped core, to the checked entry so
+// Write sees the entry first
+func (c *errorStackCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+
+	return checked
+}
+
+// Write clears the call site stack when an error stack is among the fields
+func (c *errorStackCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	for _, f := range fields {
+		if f.Key == "error.stack" {
+			entry.Stack = ""
+			break
+		}
+	}
+
+	return c.Core.Write(entry, fields)
 }
 
 // NewNop returns a no-op logger for testing
@@ -47,8 +150,9 @@ func NewNop() Logger {
 	}
 }
 
-// getEncoderConfig returns the appropriate encoder config based on format
-func getEncoderConfig(format string) zapcore.EncoderConfig {
+// getEncoderConfig returns the appropriate encoder config based on format,
+// with colored levels for console output when color is set
+func getEncoderConfig(format string, color bool) zapcore.EncoderConfig {
 	base := zapcore.EncoderConfig{
 		TimeKey:        "timestamp",
 		LevelKey:       "level",
@@ -64,8 +168,12 @@ func getEncoderConfig(format string) zapcore.EncoderConfig {
 		EncodeCaller:   zapcore.ShortCallerEncoder,
 	}
 
-	if format == "console" {
-		base.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	if format == FormatConsole || format == FormatConsolePlain {
+		base.EncodeLevel = zapcore.CapitalLevelEncoder
+		if color && format == FormatConsole {
+			base.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		}
+
 		base.EncodeTime = localTimeEncoder
 		base.ConsoleSeparator = " | "
 	}
@@ -78,52 +186,221 @@ func localTimeEncoder(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
 	enc.AppendString(t.Format("2006-01-02 15:04:05.000"))
 }
 
-// parseLevel parses the log level string
+// levels maps the accepted level names to zap levels
+var levels = map[string]zapcore.Level{
+	"debug": zap.DebugLevel,
+	"info":  zap.InfoLevel,
+	"warn":  zap.WarnLevel,
+	"error": zap.ErrorLevel,
+	"fatal": zap.FatalLevel,
+}
+
+// parseLevel parses the log level string, defaulting to info
 func parseLevel(level string) zap.AtomicLevel {
-	switch level {
-	case "debug":
-		return zap.NewAtomicLevelAt(zap.DebugLevel)
+	if l, ok := levels[level]; ok {
+		return zap.NewAtomicLevelAt(l)
+	}
+
+	return zap.NewAtomicLevelAt(zap.InfoLevel)
+}
+
+// SetLevel changes the minimum level of the named outputs, or of every
+// output when none are named, for l and every logger sharing them. Unknown
+// levels and outputs are rejected with a CodeValidation error. It is a no-op
+// on loggers without a changeable level, such as NewNop.
+func (l *zapLogger) SetLevel(level string, outputs ...string) error {
+	parsed, ok := levels[level]
+	if !ok {
+		return errors.Newf("unknown log level %q, expected debug, info, warn, error, or fatal", level).
+			WithCode(errors.CodeValidation).
+			WithMetadata("level", level)
+	}
+
+	if len(outputs) == 0 {
+		for _, out := range l.outputs {
+			out.level.SetLevel(parsed)
+		}
+
+		return nil
+	}
+
+	targets := make([]zap.AtomicLevel, 0, len(outputs))
+	for _, path := range outputs {
+		out, ok := l.output(path)
+		if !ok {
+			return errors.Newf("unknown log output %q", path).
+				WithCode(errors.CodeValidation).
+				WithMetadata("output", path)
+		}
+
+		targets = append(targets, out.level)
+	}
 
-	case "info":
-		return zap.NewAtomicLevelAt(zap.InfoLevel)
+	for _, target := range targets {
+		target.SetLevel(parsed)
+	}
 
-	case "warn":
-		return zap.NewAtomicLevelAt(zap.WarnLevel)
+	return nil
+}
 
-	case "error":
-		return zap.NewAtomicLevelAt(zap.ErrorLevel)
+// output returns the output writing to path
+func (l *zapLogger) output(path string) (outputLevel, bool) {
+	for _, out := range l.outputs {
+		if out.path == path {
+			return out, true
+		}
+	}
 
-	case "fatal":
-		return zap.NewAtomicLevelAt(zap.FatalLevel)
+	return outputLevel{}, false
+}
+
+// Level returns the lowest level across the outputs, which is the level of
+// the most verbose one, or an empty string for loggers without a changeable
+// level
+func (l *zapLogger) Level() string {
+	if len(l.outputs) == 0 {
+		return ""
+	}
 
-	default:
-		return zap.NewAtomicLevelAt(zap.InfoLevel)
+	lowest := l.outputs[0].level.Level()
+	for _, out := range l.outputs[1:] {
+		if level := out.level.Level(); level < lowest {
+			lowest = level
+		}
 	}
+
+	return lowest.String()
 }
 
 // Debug logs a debug message
 func (l *zapLogger) Debug(msg string, keysAndValues ...any) {
-	l.sugar.Debugw(msg, keysAndValues...)
+	l.pick(keysAndValues).Debugw(msg, l.fields(keysAndValues, false)...)
 }
 
 // Info logs an info message
 func (l *zapLogger) Info(msg string, keysAndValues ...any) {
-	l.sugar.Infow(msg, keysAndValues...)
+	l.pick(keysAndValues).Infow(msg, l.fields(keysAndValues, false)...)
 }
 
 // Warn logs a warning message
 func (l *zapLogger) Warn(msg string, keysAndValues ...any) {
-	l.sugar.Warnw(msg, keysAndValues...)
+	l.pick(keysAndValues).Warnw(msg, l.fields(keysAndValues, false)...)
 }
 
 // Error logs an error message
 func (l *zapLogger) Error(msg string, keysAndValues ...any) {
-	l.sugar.Errorw(msg, keysAndValues...)
+	l.pick(keysAndValues).Errorw(msg, l.fields(keysAndValues, true)...)
 }
 
 // Fatal logs a fatal message and exits the program
 func (l *zapLogger) Fatal(msg string, keysAndValues ...any) {
-	l.sugar.Fatalw(msg, keysAndValues...)
+	l.pick(keysAndValues).Fatalw(msg, l.fields(keysAndValues, true)...)
+}
+
+// fields returns the arguments passed to zap for an entry. Development
+// loggers panic on mis-paired keys and values first, see CheckPairs.
+func (l *zapLogger) fields(keysAndValues []any, stack bool) []any {
+	if l.development {
+		if err := CheckPairs(keysAndValues...); err != nil {
+			panic(err.Error())
+		}
+	}
+
+	return l.errorFields(keysAndValues, stack)
+}
+
+// errorFields expands an *errors.Error logged under the "error" key, or as
+// an Err field, into structured fields, as WithError does. When stack is set
+// and error stacks are enabled it appends the stack of that error, or else
+// of the error attached by WithError.
+func (l *zapLogger) errorFields(keysAndValues []any, stack bool) []any {
+	stackErr := l.err
+	var expanded []any
+	for i := 0; i < len(keysAndValues); i++ {
+		start := i
+		var logged any
+		if f, ok := keysAndValues[i].(zap.Field); ok {
+			if f.Key == "error" && f.Type == zapcore.ErrorType {
+				logged = f.Interface
+			}
+		} else if i+1 < len(keysAndValues) {
+			if keysAndValues[i] == "error" {
+				logged = keysAndValues[i+1]
+			}
+
+			i++
+		}
+
+		var appErr *errors.Error
+		if err, ok := logged.(error); !ok || !stderrors.As(err, &appErr) {
+			if expanded != nil {
+				expanded = append(expanded, keysAndValues[start:i+1]...)
+			}
+
+			continue
+		}
+
+		if expanded == nil {
+			expanded = append(make([]any, 0, len(keysAndValues)+8), keysAndValues[:start]...)
+		}
+
+		expanded = append(expanded, errors.Fields(logged.(error))...)
+		stackErr = appErr
+	}
+
+	if expanded == nil {
+		expanded = keysAndValues
+	}
+
+	if !stack || !l.errorStacks || stackErr == nil {
+		return expanded
+	}
+
+	trace := stackErr.StackString()
+	if trace == "" {
+		return expanded
+	}
+
+	return append(expanded, "error.stack", trace)
+}
+
+// pick returns the sugared logger for an entry, bypassing sampling when the
+// entry carries NoSample
+func (l *zapLogger) pick(keysAndValues []any) *zap.SugaredLogger {
+	if l.unsampled != nil && hasNoSample(keysAndValues) {
+		return l.unsampled
+	}
+
+	return l.sugar
+}
+
+// derive returns a logger sharing l's settings, with fn applied to its
+// sugared loggers
+func (l *zapLogger) derive(fn func(*zap.SugaredLogger) *zap.SugaredLogger) *zapLogger {
+	derived := &zapLogger{
+		sugar:       fn(l.sugar),
+		outputs:     l.outputs,
+		errorStacks: l.errorStacks,
+		development: l.development,
+		err:         l.err,
+	}
+
+	if l.unsampled != nil {
+		derived.unsampled = fn(l.unsampled)
+	}
+
+	return derived
+}
+
+// withoutSampling returns l with sampling bypassed for every entry
+func (l *zapLogger) withoutSampling() *zapLogger {
+	if l.unsampled == nil {
+		return l
+	}
+
+	derived := *l
+	derived.sugar = l.unsampled
+	return &derived
 }
 
 // WithContext returns a logger with context values
@@ -145,38 +422,62 @@ func (l *zapLogger) WithContext(ctx context.Context) Logger {
 		fields = append(fields, "user_id", userID)
 	}
 
+	derived := l
 	if len(fields) > 0 {
-		return &zapLogger{
-			sugar: l.sugar.With(fields...),
-		}
+		derived = l.derive(func(s *zap.SugaredLogger) *zap.SugaredLogger {
+			return s.With(fields...)
+		})
 	}
 
-	return l
+	if noSample, _ := ctx.Value(noSampleContextKey).(bool); noSample {
+		return derived.withoutSampling()
+	}
+
+	return derived
 }
 
 // With returns a logger with additional fields
 func (l *zapLogger) With(keysAndValues ...interface{}) Logger {
-	return &zapLogger{
-		sugar: l.sugar.With(keysAndValues...),
+	derived := l.derive(func(s *zap.SugaredLogger) *zap.SugaredLogger {
+		return s.With(keysAndValues...)
+	})
+
+	if hasNoSample(keysAndValues) {
+		return derived.withoutSampling()
 	}
+
+	return derived
 }
 
-// WithError returns a logger with an error field
+// WithError returns a logger with an error field. An *errors.Error in the
+// chain is logged as structured fields (see errors.Fields), and its stack is
+// added to Error and Fatal entries when Config.ErrorStacks and
+// Config.Stacktrace are set.
 func (l *zapLogger) WithError(err error) Logger {
 	if err == nil {
 		return l
 	}
 
-	return &zapLogger{
-		sugar: l.sugar.With("error", err.Error()),
+	var appErr *errors.Error
+	if !stderrors.As(err, &appErr) {
+		return l.derive(func(s *zap.SugaredLogger) *zap.SugaredLogger {
+			return s.With("error", err.Error())
+		})
 	}
+
+	fields := errors.Fields(err)
+	derived := l.derive(func(s *zap.SugaredLogger) *zap.SugaredLogger {
+		return s.With(fields...)
+	})
+	derived.err = appErr
+	return derived
 }
 
 // Named returns a named logger
 func (l *zapLogger) Named(name string) Logger {
-	return &zapLogger{
-		sugar: l.sugar.Named(name),
-	}
+	return l.derive(func(s *zap.SugaredLogger) *zap.SugaredLogger {
+		return s.Named(name)
+	})
 }
 
 // Sync flushes any buffered log entries
@@ -184,46 +485,75 @@ func (l *zapLogger) Sync() error {
 	return l.sugar.Sync()
 }
 
-// Global logger instance
-var globalLogger Logger
+// globalLogger holds the global logger; nil until first use or SetGlobal
+var globalLogger atomic.Pointer[Logger]
+
+// SetGlobal sets the global logger instance. A nil l restores the default,
+// which is built from DefaultConfig on first use.
+func SetGlobal(l Logger) {
+	if l == nil {
+		globalLogger.Store(nil)
+		return
+	}
 
-func init() {
-	globalLogger = New(DefaultConfig())
+	globalLogger.Store(&l)
 }
 
-// SetGlobal sets the global logger instance
-func SetGlobal(l Logger) {
-	globalLogger = l
+// ReplaceGlobal sets the global logger and returns a function restoring the
+// previous one, mainly for tests:
+//
+//	defer logger.ReplaceGlobal(logger.NewNop())()
+func ReplaceGlobal(l Logger) (restore func()) {
+	var previous *Logger
+	if l == nil {
+		previous = globalLogger.Swap(nil)
+	} else {
+		previous = globalLogger.Swap(&l)
+	}
+
+	return func() {
+		globalLogger.Store(previous)
+	}
 }
 
-// Global returns the global logger instance
+// Global returns the global logger instance, building the default on first
+// use
 func Global() Logger {
-	return globalLogger
+	if l := globalLogger.Load(); l != nil {
+		return *l
+	}
+
+	def := New(DefaultConfig())
+	if globalLogger.CompareAndSwap(nil, &def) {
+		return def
+	}
+
+	return Global()
 }
 
 // Helper functions for global logger
 
 // Debug logs a debug message using the global logger
 func Debug(msg string, keysAndValues ...interface{}) {
-	globalLogger.Debug(msg, keysAndValues...)
+	Global().Debug(msg, keysAndValues...)
 }
 
 // Info logs an info message using the global logger
 func Info(msg string, keysAndValues ...interface{}) {
-	globalLogger.Info(msg, keysAndValues...)
+	Global().Info(msg, keysAndValues...)
 }
 
 // Warn logs a warning message using the global logger
 func Warn(msg string, keysAndValues ...interface{}) {
-	globalLogger.Warn(msg, keysAndValues...)
+	Global().Warn(msg, keysAndValues...)
 }
 
 // Error logs an error message using the global logger
 func Error(msg string, keysAndValues ...interface{}) {
-	globalLogger.Error(msg, keysAndValues...)
+	Global().Error(msg, keysAndValues...)
 }
 
 // Fatal logs a fatal message using the global logger and exits
 func Fatal(msg string, keysAndValues ...interface{}) {
-	globalLogger.Fatal(msg, keysAndValues...)
+	Global().Fatal(msg, keysAndValues...)
 }
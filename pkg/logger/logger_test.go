@@ -2,10 +2,16 @@ package logger
 
 import (
 	"context"
+	"io"
+	"sync"
 	"testing"
 
+	"task-queue/pkg/errors"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestNewLogger(t *testing.T) {
@@ -94,6 +100,7 @@ func TestGlobalLogger(t *testing.T) {
 	assert.NotNil(t, Global())
 
 	// Test SetGlobal
+	defer ReplaceGlobal(nil)()
 	newLogger := NewNop()
 	SetGlobal(newLogger)
 	assert.Equal(t, newLogger, Global())
@@ -106,3 +113,174 @@ func TestGlobalLogger(t *testing.T) {
 		Error("error")
 	})
 }
+
+func TestReplaceGlobal(t *testing.T) {
+	original := Global()
+
+	replacement := NewNop()
+	restore := ReplaceGlobal(replacement)
+	assert.Same(t, replacement, Global())
+
+	restore()
+	assert.Same(t, original, Global())
+}
+
+func TestGlobalLogger_ConcurrentSwap(t *testing.T) {
+	defer ReplaceGlobal(NewNop())()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				SetGlobal(NewNop())
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				Info("tick", "j", j)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+// observedLogger returns a logger writing to an in-memory zap core
+func observedLogger(errorStacks bool) (Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zap.DebugLevel)
+	return &zapLogger{
+		sugar:       zap.New(core).Sugar(),
+		errorStacks: errorStacks,
+	}, logs
+}
+
+func TestLoggerWithError_StructuredFields(t *testing.T) {
+	log, logs := observedLogger(false)
+
+	err := errors.Validation("invalid priority").
+		WithOp("queue.Enqueue").
+		WithMetadata("priority", 11).
+		WithMetadata("password", "hunter2")
+	log.WithError(err).Warn("rejected job")
+
+	require.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+
+	assert.Equal(t, "VALIDATION", fields["error.code"])
+	assert.Equal(t, "queue.Enqueue: invalid priority", fields["error.message"])
+	assert.Equal(t, "queue.Enqueue", fields["error.op"])
+	assert.EqualValues(t, 11, fields["error.metadata.priority"])
+	assert.Equal(t, errors.RedactedValue, fields["error.metadata.password"])
+	assert.NotContains(t, fields, "error")
+}
+
+func TestLoggerWithError_PlainError(t *testing.T) {
+	log, logs := observedLogger(true)
+
+	log.WithError(io.EOF).Error("read failed")
+
+	require.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, "EOF", fields["error"])
+	assert.NotContains(t, fields, "error.stack")
+}
+
+func TestLoggerWithError_StackAtErrorLevel(t *testing.T) {
+	err := errors.Internal("disk full")
+
+	tests := []struct {
+		name        string
+		errorStacks bool
+		log         func(Logger)
+		wantStack   bool
+	}{
+		{
+			name:        "error level with stacks",
+			errorStacks: true,
+			log:         func(l Logger) { l.Error("write failed") },
+			wantStack:   true,
+		},
+		{
+			name:        "warn level with stacks",
+			errorStacks: true,
+			log:         func(l Logger) { l.Warn("write failed") },
+			wantStack:   false,
+		},
+		{
+			name:        "error level without stacks",
+			errorStacks: false,
+			log:         func(l Logger) { l.Error("write failed") },
+			wantStack:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, logs := observedLogger(tt.errorStacks)
+			tt.log(log.WithError(err).Named("worker").With("job_id", "1"))
+
+			require.Equal(t, 1, logs.Len())
+			fields := logs.All()[0].ContextMap()
+			if tt.wantStack {
+				assert.Contains(t, fields["error.stack"], "TestLoggerWithError_StackAtErrorLevel")
+				return
+			}
+
+			assert.NotContains(t, fields, "error.stack")
+		})
+	}
+}
+
+func TestLogger_ErrorKeyStructuredFields(t *testing.T) {
+	log, logs := observedLogger(true)
+
+	err := errors.New("insert failed").WithCode(errors.CodeDatabase).
+		WithOp("storage.Create").
+		WithMetadata("table", "jobs").
+		WithMetadata("token", "secret")
+	log.Error("create job", "job_id", "job-1", "error", err, "attempt", 2)
+
+	require.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+
+	assert.Equal(t, "job-1", fields["job_id"])
+	assert.EqualValues(t, 2, fields["attempt"])
+	assert.Equal(t, string(errors.CodeDatabase), fields["error.code"])
+	assert.Equal(t, "storage.Create: insert failed", fields["error.message"])
+	assert.Equal(t, "jobs", fields["error.metadata.table"])
+	assert.Equal(t, errors.RedactedValue, fields["error.metadata.token"])
+	assert.Contains(t, fields["error.stack"], "TestLogger_ErrorKeyStructuredFields")
+	assert.NotContains(t, fields, "error")
+}
+
+func TestLogger_ErrorKeyPlainError(t *testing.T) {
+	log, logs := observedLogger(true)
+
+	log.Error("read failed", "error", io.EOF, NoSample())
+
+	require.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, "EOF", fields["error"])
+	assert.NotContains(t, fields, "error.code")
+	assert.NotContains(t, fields, "error.stack")
+}
+
+func TestLogger_ErrorStackReplacesCallSiteStack(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	log := &zapLogger{
+		sugar:       zap.New(&errorStackCore{Core: core}, zap.AddStacktrace(zap.ErrorLevel)).Sugar(),
+		errorStacks: true,
+	}
+
+	log.Error("plain failure")
+	log.Error("structured failure", "error", errors.Internal("disk full"))
+
+	require.Equal(t, 2, logs.Len())
+	assert.NotEmpty(t, logs.All()[0].Stack)
+	assert.Empty(t, logs.All()[1].Stack)
+	assert.Contains(t, logs.All()[1].ContextMap(), "error.stack")
+}
@@ -0,0 +1,335 @@
+package logger
+
+import (
+	stderrors "errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"task-queue/pkg/errors"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// OutputConfig describes one log destination with its own encoding and
+// level
+type OutputConfig struct {
+	// Path is stdout, stderr, a file path, or a URL registered with zap
+	Path string `json:"path" yaml:"path"`
+
+	// Format is json, console, or console-plain; empty uses Config.Format
+	Format string `json:"format" yaml:"format"`
+
+	// Color is auto, always, or never; empty uses Config.Color
+	Color string `json:"color" yaml:"color"`
+
+	// Level is the minimum level written to Path; empty uses Config.Level
+	Level string `json:"level" yaml:"level"`
+
+	// Rotation rotates a file output by size; ignored for other paths
+	Rotation RotationConfig `json:"rotation" yaml:"rotation"`
+}
+
+// RotationConfig configures size-based rotation of a file output. The
+// current file is renamed to path.1, shifting older backups up to
+// MaxBackups and removing the oldest.
+type RotationConfig struct {
+	// MaxSizeMB is the size at which the file is rotated; 0 disables
+	// rotation
+	MaxSizeMB int `json:"max_size_mb" yaml:"max_size_mb"`
+
+	// MaxBackups is the number of rotated files kept
+	MaxBackups int `json:"max_backups" yaml:"max_backups"`
+}
+
+// outputLevel is the changeable level of one output
+type outputLevel struct {
+	path  string
+	level zap.AtomicLevel
+}
+
+// outputs returns the configured outputs, or one per legacy OutputPaths
+// entry using the flat Format and Level. Empty formats and levels are
+// filled from the flat fields.
+func (c Config) outputs() []OutputConfig {
+	if len(c.Outputs) == 0 {
+		outputs := make([]OutputConfig, len(c.OutputPaths))
+		for i, path := range c.OutputPaths {
+			outputs[i] = OutputConfig{Path: path, Format: c.Format, Level: c.Level, Color: c.Color}
+		}
+
+		return outputs
+	}
+
+	outputs := make([]OutputConfig, len(c.Outputs))
+	for i, out := range c.Outputs {
+		if out.Format == "" {
+			out.Format = c.Format
+		}
+
+		if out.Level == "" {
+			out.Level = c.Level
+		}
+
+		if out.Color == "" {
+			out.Color = c.Color
+		}
+
+		outputs[i] = out
+	}
+
+	return outputs
+}
+
+// buildCores opens every output and returns the tee of their cores along
+// with their levels
+func buildCores(outputs []OutputConfig) (zapcore.Core, []outputLevel, error) {
+	cores := make([]zapcore.Core, 0, len(outputs))
+	outLevels := make([]outputLevel, 0, len(outputs))
+	for _, out := range outputs {
+		sink, err := openOutput(out)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		level := parseLevel(out.Level)
+		encoder := newEncoder(out.Format, useColor(out.Color, isTerminal(out.Path)))
+		cores = append(cores, zapcore.NewCore(encoder, sink, level))
+		outLevels = append(outLevels, outputLevel{path: out.Path, level: level})
+	}
+
+	return newLevelTee(cores...), outLevels, nil
+}
+
+// levelTee duplicates entries to several cores like zapcore.NewTee, but
+// writes each entry only to the cores enabled for its level. Wrapping cores
+// such as redactingCore call Write directly, skipping the Check of the tee's
+// members, so the tee must apply their levels itself.
+type levelTee []zapcore.Core
+
+// newLevelTee returns a tee of cores
+func newLevelTee(cores ...zapcore.Core) zapcore.Core {
+	if len(cores) == 1 {
+		return cores[0]
+	}
+
+	return levelTee(cores)
+}
+
+// Enabled reports whether any core is enabled for level
+func (t levelTee) Enabled(level zapcore.Level) bool {
+	for _, core := range t {
+		if core.Enabled(level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// With adds fields to every core
+func (t levelTee) With(fields []zapcore.Field) zapcore.Core {
+	cores := make(levelTee, len(t))
+	for i, core := range t {
+		cores[i] = core.With(fields)
+	}
+
+	return cores
+}
+
+// Check adds each core enabled for the entry to the checked entry
+func (t levelTee) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	for _, core := range t {
+		checked = core.Check(entry, checked)
+	}
+
+	return checked
+}
+
+// Write writes the entry to every core enabled for its level
+func (t levelTee) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	var errs []error
+	for _, core := range t {
+		if core.Enabled(entry.Level) {
+			if err := core.Write(entry, fields); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return stderrors.Join(errs...)
+}
+
+// Sync flushes every core
+func (t levelTee) Sync() error {
+	var errs []error
+	for _, core := range t {
+		if err := core.Sync(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return stderrors.Join(errs...)
+}
+
+// newEncoder returns the encoder for format, with colored levels when color
+// is set and the format is console
+func newEncoder(format string, color bool) zapcore.Encoder {
+	if format == FormatConsole || format == FormatConsolePlain {
+		return zapcore.NewConsoleEncoder(getEncoderConfig(format, color))
+	}
+
+	return zapcore.NewJSONEncoder(getEncoderConfig(format, color))
+}
+
+// useColor resolves a color mode for an output, coloring in auto mode only
+// when the output is a terminal
+func useColor(mode string, terminal bool) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+
+	case ColorNever:
+		return false
+
+	default:
+		return terminal
+	}
+}
+
+// isTerminal reports whether path resolves to a terminal: stdout or stderr
+// attached to one, or a character device such as /dev/tty
+func isTerminal(path string) bool {
+	var info os.FileInfo
+	var err error
+	switch path {
+	case "stdout":
+		info, err = os.Stdout.Stat()
+
+	case "stderr":
+		info, err = os.Stderr.Stat()
+
+	default:
+		if !isFilePath(path) {
+			return false
+		}
+
+		info, err = os.Stat(path)
+	}
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// openOutput opens the sink of out, rotating file outputs when configured
+func openOutput(out OutputConfig) (zapcore.WriteSyncer, error) {
+	if out.Rotation.MaxSizeMB > 0 && isFilePath(out.Path) {
+		file, err := newRotatingFile(out.Path, int64(out.Rotation.MaxSizeMB)<<20, out.Rotation.MaxBackups)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open log output %s", out.Path).
+				WithCode(errors.CodeConfiguration).
+				WithMetadata("path", out.Path)
+		}
+
+		return file, nil
+	}
+
+	sink, _, err := zap.Open(out.Path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open log output %s", out.Path).
+			WithCode(errors.CodeConfiguration).
+			WithMetadata("path", out.Path)
+	}
+
+	return sink, nil
+}
+
+// rotatingFile is a file sink that rotates when it reaches maxBytes
+type rotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxBytes   int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// newRotatingFile opens path for appending
+func newRotatingFile(path string, maxBytes int64, maxBackups int) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, maxBytes: maxBytes, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// open opens the current file and records its size
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	r.file, r.size = file, info.Size()
+	return nil
+}
+
+// Write appends p, rotating first when p would push the file past maxBytes
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size > 0 && r.size+int64(len(p)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate shifts the backups, moves the current file to path.1, and opens a
+// new current file
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+
+	if r.maxBackups <= 0 {
+		if err := os.Remove(r.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		return r.open()
+	}
+
+	for i := r.maxBackups - 1; i > 0; i-- {
+		from := fmt.Sprintf("%s.%d", r.path, i)
+		if err := os.Rename(from, fmt.Sprintf("%s.%d", r.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if err := os.Rename(r.path, r.path+".1"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return r.open()
+}
+
+// Sync flushes the current file
+func (r *rotatingFile) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.file.Sync()
+}
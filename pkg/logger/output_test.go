@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"task-queue/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// twoOutputLogger returns a logger with a console file at info and a JSON
+// file at debug, and their paths
+func twoOutputLogger(t *testing.T) (Logger, string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	console := filepath.Join(dir, "console.log")
+	jsonFile := filepath.Join(dir, "app.json")
+
+	cfg := DefaultConfig()
+	cfg.Outputs = []OutputConfig{
+		{Path: console, Format: "console", Level: "info"},
+		{Path: jsonFile, Format: "json", Level: "debug"},
+	}
+
+	return New(cfg), console, jsonFile
+}
+
+// readFile returns the content of path
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestOutputs_PerOutputLevelAndFormat(t *testing.T) {
+	log, console, jsonFile := twoOutputLogger(t)
+
+	log.Debug("cache miss", "key", "job:1")
+	log.Info("server started", "port", 8080)
+	require.NoError(t, log.Sync())
+
+	consoleOut := readFile(t, console)
+	assert.NotContains(t, consoleOut, "cache miss")
+	assert.Contains(t, consoleOut, "server started")
+	assert.False(t, strings.HasPrefix(consoleOut, "{"), "console output is not JSON")
+
+	jsonOut := readFile(t, jsonFile)
+	assert.Contains(t, jsonOut, `"message":"cache miss"`)
+	assert.Contains(t, jsonOut, `"message":"server started"`)
+	assert.Equal(t, "debug", log.Level())
+}
+
+func TestOutputs_SetLevelTargetsOneOutput(t *testing.T) {
+	log, console, jsonFile := twoOutputLogger(t)
+
+	require.NoError(t, log.SetLevel("debug", console))
+	require.NoError(t, log.SetLevel("error", jsonFile))
+	log.Debug("console only")
+	require.NoError(t, log.Sync())
+
+	assert.Contains(t, readFile(t, console), "console only")
+	assert.NotContains(t, readFile(t, jsonFile), "console only")
+
+	require.NoError(t, log.SetLevel("warn"))
+	log.Info("filtered everywhere")
+	require.NoError(t, log.Sync())
+
+	assert.NotContains(t, readFile(t, console), "filtered everywhere")
+	assert.NotContains(t, readFile(t, jsonFile), "filtered everywhere")
+	assert.Equal(t, "warn", log.Level())
+}
+
+func TestOutputs_SetLevelUnknownOutput(t *testing.T) {
+	log, _, _ := twoOutputLogger(t)
+
+	err := log.SetLevel("debug", "/nowhere.log")
+	require.Error(t, err)
+	assert.True(t, errors.HasCode(err, errors.CodeValidation))
+	assert.Equal(t, "debug", log.Level(), "levels are unchanged")
+}
+
+func TestOutputs_InheritFlatFormatAndLevel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	cfg := DefaultConfig()
+	cfg.Level = "warn"
+	cfg.Outputs = []OutputConfig{{Path: path}}
+	log := New(cfg)
+
+	log.Info("hidden")
+	log.Warn("shown")
+	require.NoError(t, log.Sync())
+
+	out := readFile(t, path)
+	assert.NotContains(t, out, "hidden")
+	assert.Contains(t, out, `"message":"shown"`)
+}
+
+func TestOutputs_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	file, err := newRotatingFile(path, 100, 2)
+	require.NoError(t, err)
+
+	line := []byte(strings.Repeat("x", 59) + "\n")
+	for i := 0; i < 5; i++ {
+		_, err := file.Write(line)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, file.Sync())
+	assert.Len(t, readFile(t, path), 60)
+	assert.Len(t, readFile(t, path+".1"), 60)
+	assert.Len(t, readFile(t, path+".2"), 60)
+	assert.NoFileExists(t, path+".3")
+}
+
+func TestOutputs_InvalidOutput(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Outputs = []OutputConfig{{Path: "stdout", Format: "yaml"}}
+
+	_, err := NewWithError(cfg)
+	require.Error(t, err)
+	assert.True(t, errors.HasCode(err, errors.CodeConfiguration))
+}
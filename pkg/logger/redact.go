@@ -0,0 +1,252 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"task-queue/pkg/errors"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RedactedValue replaces the values of sensitive fields. It matches the
+// marker used by pkg/errors.
+const RedactedValue = errors.RedactedValue
+
+// TruncatedSuffix ends values cut to Config.MaxFieldBytes
+const TruncatedSuffix = "...[TRUNCATED]"
+
+// DefaultRedactKeys are the field keys redacted by DefaultConfig
+var DefaultRedactKeys = []string{
+	"password",
+	"secret",
+	"token",
+	"authorization",
+	"api_key",
+	"cookie",
+}
+
+// secretValue is a value that is always written redacted
+type secretValue struct {
+	value string
+}
+
+// String hides the wrapped value
+func (secretValue) String() string {
+	return RedactedValue
+}
+
+// MarshalJSON hides the wrapped value
+func (secretValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(RedactedValue)
+}
+
+// Secret wraps v so it is written as RedactedValue whatever its key, also
+// inside map-valued fields
+//
+//	log.Info("connecting", "dsn", logger.Secret(dsn))
+func Secret(v string) fmt.Stringer {
+	return secretValue{value: v}
+}
+
+// redactor rewrites sensitive and oversized field values
+type redactor struct {
+	keys     map[string]struct{}
+	patterns []*regexp.Regexp
+	maxBytes int
+}
+
+// newRedactor builds the redactor for cfg, or returns nil when cfg asks for
+// no redaction
+func newRedactor(cfg Config) (*redactor, error) {
+	if len(cfg.RedactKeys) == 0 && len(cfg.RedactPatterns) == 0 && cfg.MaxFieldBytes <= 0 {
+		return nil, nil
+	}
+
+	r := &redactor{
+		keys:     make(map[string]struct{}, len(cfg.RedactKeys)),
+		maxBytes: cfg.MaxFieldBytes,
+	}
+
+	for _, key := range cfg.RedactKeys {
+		r.keys[strings.ToLower(key)] = struct{}{}
+	}
+
+	for _, pattern := range cfg.RedactPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid redact pattern %q", pattern).
+				WithCode(errors.CodeConfiguration)
+		}
+
+		r.patterns = append(r.patterns, re)
+	}
+
+	return r, nil
+}
+
+// sensitive reports whether key, or its last dot-separated segment, names a
+// sensitive field
+func (r *redactor) sensitive(key string) bool {
+	lower := strings.ToLower(key)
+	if _, ok := r.keys[lower]; ok {
+		return true
+	}
+
+	if i := strings.LastIndexByte(lower, '.'); i >= 0 {
+		if _, ok := r.keys[lower[i+1:]]; ok {
+			return true
+		}
+	}
+
+	for _, re := range r.patterns {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fields returns fields with sensitive values redacted and oversized ones
+// truncated. The input slice is never modified.
+func (r *redactor) fields(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		rewritten, changed := r.field(f)
+		if !changed {
+			if out != nil {
+				out = append(out, f)
+			}
+
+			continue
+		}
+
+		if out == nil {
+			out = make([]zapcore.Field, i, len(fields))
+			copy(out, fields[:i])
+		}
+
+		out = append(out, rewritten)
+	}
+
+	if out == nil {
+		return fields
+	}
+
+	return out
+}
+
+// field rewrites one field, reporting whether it changed
+func (r *redactor) field(f zapcore.Field) (zapcore.Field, bool) {
+	if f.Type == zapcore.SkipType {
+		return f, false
+	}
+
+	if r.sensitive(f.Key) {
+		return zap.String(f.Key, RedactedValue), true
+	}
+
+	switch f.Type {
+	case zapcore.StringType:
+		if s, ok := r.truncate(f.String); ok {
+			return zap.String(f.Key, s), true
+		}
+
+	case zapcore.ByteStringType, zapcore.BinaryType:
+		if b, ok := f.Interface.([]byte); ok {
+			if s, ok := r.truncate(string(b)); ok {
+				return zap.String(f.Key, s), true
+			}
+		}
+
+	case zapcore.ReflectType:
+		if m, ok := r.redactMap(f.Interface); ok {
+			return zap.Any(f.Key, m), true
+		}
+	}
+
+	return f, false
+}
+
+// redactMap redacts the sensitive entries and truncates the string values
+// of a map with string keys, one level deep. ok is false for other values.
+func (r *redactor) redactMap(value any) (map[string]any, bool) {
+	m := reflect.ValueOf(value)
+	if m.Kind() != reflect.Map || m.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+
+	out := make(map[string]any, m.Len())
+	iter := m.MapRange()
+	for iter.Next() {
+		key := iter.Key().String()
+		v := iter.Value().Interface()
+		if r.sensitive(key) {
+			out[key] = RedactedValue
+			continue
+		}
+
+		if s, ok := v.(string); ok {
+			if truncated, ok := r.truncate(s); ok {
+				v = truncated
+			}
+		}
+
+		out[key] = v
+	}
+
+	return out, true
+}
+
+// truncate cuts s to the byte limit on a rune boundary. ok is false when s
+// fits.
+func (r *redactor) truncate(s string) (string, bool) {
+	if r.maxBytes <= 0 || len(s) <= r.maxBytes {
+		return s, false
+	}
+
+	cut := r.maxBytes
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+
+	return s[:cut] + TruncatedSuffix, true
+}
+
+// redactingCore applies a redactor to the fields of every entry before the
+// wrapped core encodes them
+type redactingCore struct {
+	zapcore.Core
+	redactor *redactor
+}
+
+// newRedactingCore wraps core with r
+func newRedactingCore(core zapcore.Core, r *redactor) zapcore.Core {
+	return &redactingCore{Core: core, redactor: r}
+}
+
+// With redacts the fields before adding them to the wrapped core
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(c.redactor.fields(fields)), redactor: c.redactor}
+}
+
+// Check adds c, rather than the wrapped core, to the checked entry so
+// Write sees the fields first
+func (c *redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+
+	return checked
+}
+
+// Write redacts the fields and writes the entry to the wrapped core
+func (c *redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, c.redactor.fields(fields))
+}
@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// redactedLogger returns a logger redacting per cfg and writing to an
+// in-memory core
+func redactedLogger(t *testing.T, cfg Config) (Logger, *observer.ObservedLogs) {
+	t.Helper()
+
+	r, err := newRedactor(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, r)
+
+	core, logs := observer.New(zap.DebugLevel)
+	return &zapLogger{sugar: zap.New(newRedactingCore(core, r)).Sugar()}, logs
+}
+
+func TestRedaction_Keys(t *testing.T) {
+	log, logs := redactedLogger(t, Config{
+		RedactKeys:     DefaultRedactKeys,
+		RedactPatterns: []string{`(?i)^x-.*-key$`},
+	})
+
+	log.With("Authorization", "Bearer abc").Info("request",
+		"password", "hunter2",
+		"user.password", "hunter3",
+		"X-Upstream-Key", "k-123",
+		"user", "ada",
+		"attempt", 3,
+	)
+
+	require.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, RedactedValue, fields["Authorization"])
+	assert.Equal(t, RedactedValue, fields["password"])
+	assert.Equal(t, RedactedValue, fields["user.password"])
+	assert.Equal(t, RedactedValue, fields["X-Upstream-Key"])
+	assert.Equal(t, "ada", fields["user"])
+	assert.EqualValues(t, 3, fields["attempt"])
+}
+
+func TestRedaction_MapOneLevelDeep(t *testing.T) {
+	log, logs := redactedLogger(t, Config{RedactKeys: []string{"token"}, MaxFieldBytes: 8})
+
+	nested := map[string]any{"token": "deep"}
+	log.Info("job", "metadata", map[string]any{
+		"token":  "abc",
+		"tenant": "acme",
+		"note":   "a very long note",
+		"nested": nested,
+	})
+
+	fields := logs.All()[0].ContextMap()
+	metadata, ok := fields["metadata"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, RedactedValue, metadata["token"])
+	assert.Equal(t, "acme", metadata["tenant"])
+	assert.Equal(t, "a very l"+TruncatedSuffix, metadata["note"])
+	assert.Equal(t, nested, metadata["nested"], "only one level is inspected")
+}
+
+func TestRedaction_Truncation(t *testing.T) {
+	log, logs := redactedLogger(t, Config{MaxFieldBytes: 10})
+
+	log.Info("payload",
+		"body", strings.Repeat("x", 100),
+		"raw", []byte(strings.Repeat("y", 50)),
+		"short", "fits",
+		"accented", "ééééééé",
+	)
+
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, strings.Repeat("x", 10)+TruncatedSuffix, fields["body"])
+	assert.Equal(t, strings.Repeat("y", 10)+TruncatedSuffix, fields["raw"])
+	assert.Equal(t, "fits", fields["short"])
+	assert.Equal(t, "ééééé"+TruncatedSuffix, fields["accented"], "cuts on a rune boundary")
+}
+
+// encodeJSON encodes fields as a JSON object
+func encodeJSON(t *testing.T, fields []zapcore.Field) string {
+	t.Helper()
+
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	buf, err := enc.EncodeEntry(zapcore.Entry{}, fields)
+	require.NoError(t, err)
+	return buf.String()
+}
+
+func TestSecret(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	log := &zapLogger{sugar: zap.New(core).Sugar()}
+
+	log.Info("connecting", "dsn", Secret("postgres://user:pw@db"),
+		"headers", map[string]any{"x-custom": Secret("abc")})
+
+	entry := logs.All()[0]
+	fields := entry.ContextMap()
+	assert.Equal(t, RedactedValue, fields["dsn"], "masked even without redaction configured")
+	assert.NotContains(t, encodeJSON(t, entry.Context), "pw@db")
+	assert.NotContains(t, encodeJSON(t, entry.Context), "abc")
+}
+
+func TestNew_InvalidRedactPattern(t *testing.T) {
+	assert.PanicsWithValue(t,
+		"failed to initialize logger: invalid redact pattern \"(\": error parsing regexp: missing closing ): `(`",
+		func() { New(Config{Level: "info", Format: "json", RedactPatterns: []string{"("}}) })
+}
+
+func TestNewRedactor_Disabled(t *testing.T) {
+	r, err := newRedactor(Config{})
+	require.NoError(t, err)
+	assert.Nil(t, r)
+}
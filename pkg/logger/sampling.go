@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SamplingConfig limits how many identical entries are written. Within each
+// Tick, the first Initial entries with the same level and message are
+// written, then every Thereafter-th one; the rest are dropped.
+type SamplingConfig struct {
+	Enabled    bool          `json:"enabled" yaml:"enabled"`
+	Initial    int           `json:"initial" yaml:"initial"`
+	Thereafter int           `json:"thereafter" yaml:"thereafter"`
+	Tick       time.Duration `json:"tick" yaml:"tick"`
+}
+
+// noSampleKey names the field added by NoSample
+const noSampleKey = "logger.no_sample"
+
+// noSampleContextKey marks contexts created by NoSampleContext
+const noSampleContextKey ContextKey = "no_sample"
+
+// NoSample returns a field that exempts an entry from sampling, for messages
+// that must never be dropped such as panics and startup. Pass it among the
+// keys and values of a call, or to With for every entry of a logger. The
+// field itself is not written.
+//
+//	log.Error("worker panicked", logger.NoSample(), "panic", r)
+func NoSample() zap.Field {
+	return zap.Field{Key: noSampleKey, Type: zapcore.SkipType}
+}
+
+// NoSampleContext returns a context whose loggers, obtained with
+// WithContext, are exempt from sampling
+func NoSampleContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noSampleContextKey, true)
+}
+
+// hasNoSample reports whether keysAndValues carry the NoSample field
+func hasNoSample(keysAndValues []any) bool {
+	for _, kv := range keysAndValues {
+		if f, ok := kv.(zap.Field); ok && f.Key == noSampleKey && f.Type == zapcore.SkipType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sampledCore wraps core with a sampler configured by cfg, filling in the
+// defaults for unset values
+func sampledCore(core zapcore.Core, cfg SamplingConfig) zapcore.Core {
+	tick := cfg.Tick
+	if tick <= 0 {
+		tick = time.Second
+	}
+
+	initial := cfg.Initial
+	if initial <= 0 {
+		initial = 100
+	}
+
+	return zapcore.NewSamplerWithOptions(core, tick, initial, cfg.Thereafter,
+		zapcore.SamplerHook(recordSampling))
+}
+
+// droppedEntries counts the entries dropped by sampling in this process
+var droppedEntries atomic.Uint64
+
+// dropObserver is called for every entry dropped by sampling
+var dropObserver atomic.Pointer[func(level string)]
+
+// DroppedEntries returns the number of entries dropped by sampling since the
+// process started
+func DroppedEntries() uint64 {
+	return droppedEntries.Load()
+}
+
+// SetDropObserver installs fn to be called with the level of every entry
+// dropped by sampling, e.g. to count drops in metrics. fn must be fast and
+// must not log. A nil fn removes the observer.
+func SetDropObserver(fn func(level string)) {
+	if fn == nil {
+		dropObserver.Store(nil)
+		return
+	}
+
+	dropObserver.Store(&fn)
+}
+
+// recordSampling is the sampler hook counting dropped entries
+func recordSampling(entry zapcore.Entry, decision zapcore.SamplingDecision) {
+	if decision&zapcore.LogDropped == 0 {
+		return
+	}
+
+	droppedEntries.Add(1)
+	if fn := dropObserver.Load(); fn != nil {
+		(*fn)(entry.Level.String())
+	}
+}
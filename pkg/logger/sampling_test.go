@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sampledLogger returns a JSON logger with sampling writing to a temp file,
+// and a function counting the written lines containing msg
+func sampledLogger(t *testing.T, sampling SamplingConfig) (Logger, func(msg string) int) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "out.log")
+	cfg := DefaultConfig()
+	cfg.OutputPaths = []string{path}
+	cfg.Sampling = sampling
+	log := New(cfg)
+
+	return log, func(msg string) int {
+		require.NoError(t, log.Sync())
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return strings.Count(string(data), `"message":"`+msg+`"`)
+	}
+}
+
+func TestSampling_InitialThereafter(t *testing.T) {
+	log, count := sampledLogger(t, SamplingConfig{
+		Enabled:    true,
+		Initial:    10,
+		Thereafter: 100,
+		Tick:       time.Minute,
+	})
+
+	dropped := DroppedEntries()
+	for i := 0; i < 1000; i++ {
+		log.Error("dependency down", "attempt", i)
+	}
+
+	// The first 10, then every 100th of the remaining 990
+	assert.Equal(t, 10+990/100, count("dependency down"))
+	assert.Equal(t, uint64(1000-19), DroppedEntries()-dropped)
+}
+
+func TestSampling_DisabledByDefault(t *testing.T) {
+	log, count := sampledLogger(t, DefaultConfig().Sampling)
+
+	for i := 0; i < 300; i++ {
+		log.Info("busy")
+	}
+
+	assert.Equal(t, 300, count("busy"))
+}
+
+func TestSampling_NoSample(t *testing.T) {
+	log, count := sampledLogger(t, SamplingConfig{
+		Enabled:    true,
+		Initial:    1,
+		Thereafter: 0,
+		Tick:       time.Minute,
+	})
+
+	for i := 0; i < 50; i++ {
+		log.Error("panic recovered", NoSample(), "i", i)
+		log.Named("worker").Error("storm")
+	}
+
+	assert.Equal(t, 50, count("panic recovered"))
+	assert.Equal(t, 1, count("storm"))
+
+	pinned := log.With(NoSample()).Named("startup")
+	ctxLog := log.WithContext(NoSampleContext(context.Background()))
+	for i := 0; i < 20; i++ {
+		pinned.Info("pinned")
+		ctxLog.Info("from context")
+	}
+
+	assert.Equal(t, 20, count("pinned"))
+	assert.Equal(t, 20, count("from context"))
+}
+
+func TestSetDropObserver(t *testing.T) {
+	var levels []string
+	SetDropObserver(func(level string) { levels = append(levels, level) })
+	t.Cleanup(func() { SetDropObserver(nil) })
+
+	log, _ := sampledLogger(t, SamplingConfig{Enabled: true, Initial: 2, Tick: time.Minute})
+	for i := 0; i < 5; i++ {
+		log.Warn("slow")
+	}
+
+	assert.Equal(t, []string{"warn", "warn", "warn"}, levels)
+}
@@ -2,6 +2,9 @@ package logger
 
 import (
 	"context"
+	"time"
+
+	"task-queue/pkg/errors"
 
 	"go.uber.org/zap"
 )
@@ -39,15 +42,63 @@ type Logger interface {
 
 	// Sync flushes any buffered log entries
 	Sync() error
+
+	// SetLevel changes the minimum level, one of debug, info, warn, error,
+	// or fatal, of the outputs with the given paths, or of every output when
+	// none are given. Every logger derived from the same New shares the
+	// levels.
+	SetLevel(level string, outputs ...string) error
+
+	// Level returns the current minimum level across the outputs
+	Level() string
 }
 
 // zapLogger wraps zap.SugaredLogger to implement the Logger interface
 type zapLogger struct {
 	sugar *zap.SugaredLogger
+
+	// unsampled writes entries exempt from sampling; nil when sampling is
+	// off
+	unsampled *zap.SugaredLogger
+
+	// outputs holds the level of each output, shared by every logger derived
+	// from the same New; empty for loggers whose level cannot change, such
+	// as the nop logger
+	outputs []outputLevel
+
+	// errorStacks enables the stack of err on Error and Fatal entries
+	errorStacks bool
+
+	// development panics on mis-paired keys and values
+	development bool
+
+	// err is the error attached by WithError, if it is an *errors.Error
+	err *errors.Error
 }
 
+// Formats accepted by Config.Format and OutputConfig.Format
+const (
+	FormatJSON         = "json"
+	FormatConsole      = "console"
+	FormatConsolePlain = "console-plain"
+)
+
+// Color modes accepted by Config.Color and OutputConfig.Color
+const (
+	// ColorAuto colors console output only when it goes to a terminal
+	ColorAuto = "auto"
+
+	// ColorAlways colors console output even when it is redirected
+	ColorAlways = "always"
+
+	// ColorNever never colors console output
+	ColorNever = "never"
+)
+
 // Config holds logger configuration
 type Config struct {
+	// Level, Format, and OutputPaths describe a single output when Outputs
+	// is empty, and are the defaults for the level and format of Outputs
 	Level       string   `json:"level" yaml:"level"`
 	Format      string   `json:"format" yaml:"format"`
 	OutputPaths []string `json:"output_paths" yaml:"output_paths"`
@@ -55,6 +106,44 @@ type Config struct {
 	Development bool     `json:"development" yaml:"development"`
 	Caller      bool     `json:"caller" yaml:"caller"`
 	Stacktrace  bool     `json:"stacktrace" yaml:"stacktrace"`
+
+	// Color controls colored levels in console output: auto, the default,
+	// colors only outputs that are terminals; console-plain never colors
+	Color string `json:"color" yaml:"color"`
+
+	// Outputs configures destinations with their own format and level, such
+	// as console on stdout at info alongside JSON in a file at debug
+	Outputs []OutputConfig `json:"outputs" yaml:"outputs"`
+
+	// ErrorStacks adds the stack captured by an *errors.Error, attached with
+	// WithError or logged under the "error" key, to Error and Fatal entries
+	// in place of the call site stack. It has no effect unless Stacktrace is
+	// set.
+	ErrorStacks bool `json:"error_stacks" yaml:"error_stacks"`
+
+	// Sampling drops repeated entries under load; off by default
+	Sampling SamplingConfig `json:"sampling" yaml:"sampling"`
+
+	// RedactKeys are field keys, matched case-insensitively, whose values
+	// are written as RedactedValue. A key also matches the last segment of
+	// dotted keys, so "password" covers "user.password".
+	RedactKeys []string `json:"redact_keys" yaml:"redact_keys"`
+
+	// RedactPatterns are regular expressions matched against field keys,
+	// redacting the values of the keys they match
+	RedactPatterns []string `json:"redact_patterns" yaml:"redact_patterns"`
+
+	// MaxFieldBytes truncates longer string and byte values; 0 disables
+	// truncation
+	MaxFieldBytes int `json:"max_field_bytes" yaml:"max_field_bytes"`
+
+	// Hooks receive entries asynchronously after redaction, each filtered by
+	// its own minimum level
+	Hooks []Hook `json:"-" yaml:"-"`
+
+	// HookQueueSize bounds the entries buffered per hook; entries beyond it
+	// are dropped. Defaults to DefaultHookQueueSize.
+	HookQueueSize int `json:"hook_queue_size" yaml:"hook_queue_size"`
 }
 
 // DefaultConfig returns a default logger configuration
@@ -62,10 +151,19 @@ func DefaultConfig() Config {
 	return Config{
 		Level:       "info",
 		Format:      "json",
+		Color:       ColorAuto,
 		OutputPaths: []string{"stdout"},
 		ErrorPaths:  []string{"stderr"},
 		Development: false,
 		Caller:      true,
 		Stacktrace:  true,
+		ErrorStacks: true,
+		Sampling: SamplingConfig{
+			Initial:    100,
+			Thereafter: 100,
+			Tick:       time.Second,
+		},
+		RedactKeys:    append([]string(nil), DefaultRedactKeys...),
+		MaxFieldBytes: 8 << 10,
 	}
 }
@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"task-queue/pkg/errors"
+)
+
+// formats are the accepted Config.Format values
+var formats = []string{FormatJSON, FormatConsole, FormatConsolePlain}
+
+// colorModes are the accepted Config.Color values
+var colorModes = []string{ColorAuto, ColorAlways, ColorNever}
+
+// Validate checks the levels, formats, and paths of the outputs. An empty
+// level means info. File outputs must be in an existing directory; stdout,
+// stderr, and URL sinks are not checked.
+func (c Config) Validate() error {
+	if err := validateLevel(c.Level); err != nil {
+		return err
+	}
+
+	if len(c.Outputs) == 0 || c.Format != "" {
+		if err := validateFormat(c.Format); err != nil {
+			return err
+		}
+	}
+
+	if err := validateColor(c.Color); err != nil {
+		return err
+	}
+
+	for _, out := range c.outputs() {
+		if err := out.validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range c.ErrorPaths {
+		if err := validateOutputPath(path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validate checks the path, format, level, and rotation of an output
+func (o OutputConfig) validate() error {
+	if err := validateLevel(o.Level); err != nil {
+		return err
+	}
+
+	if err := validateFormat(o.Format); err != nil {
+		return err
+	}
+
+	if err := validateColor(o.Color); err != nil {
+		return err
+	}
+
+	if o.Rotation.MaxSizeMB < 0 || o.Rotation.MaxBackups < 0 {
+		return errors.Newf("log output %s has a negative rotation setting", o.Path).
+			WithCode(errors.CodeConfiguration).
+			WithMetadata("path", o.Path)
+	}
+
+	return validateOutputPath(o.Path)
+}
+
+// validateLevel checks level is empty or a known level
+func validateLevel(level string) error {
+	if _, ok := levels[level]; level != "" && !ok {
+		return errors.Newf("unknown log level %q", level).
+			WithCode(errors.CodeConfiguration).
+			WithMetadata("level", level).
+			WithMetadata("supported", []string{"debug", "info", "warn", "error", "fatal"})
+	}
+
+	return nil
+}
+
+// validateFormat checks format is json, console, or console-plain
+func validateFormat(format string) error {
+	if format != FormatJSON && format != FormatConsole && format != FormatConsolePlain {
+		return errors.Newf("unsupported log format %q", format).
+			WithCode(errors.CodeConfiguration).
+			WithMetadata("format", format).
+			WithMetadata("supported", formats)
+	}
+
+	return nil
+}
+
+// validateColor checks color is empty or a known color mode
+func validateColor(color string) error {
+	if color != "" && color != ColorAuto && color != ColorAlways && color != ColorNever {
+		return errors.Newf("unsupported log color mode %q", color).
+			WithCode(errors.CodeConfiguration).
+			WithMetadata("color", color).
+			WithMetadata("supported", colorModes)
+	}
+
+	return nil
+}
+
+// isFilePath reports whether path names a file rather than stdout, stderr,
+// or a URL sink
+func isFilePath(path string) bool {
+	return path != "stdout" && path != "stderr" && !strings.Contains(path, "://")
+}
+
+// validateOutputPath checks the directory of a file output exists
+func validateOutputPath(path string) error {
+	if !isFilePath(path) {
+		return nil
+	}
+
+	dir := filepath.Dir(path)
+	info, err := os.Stat(dir)
+	if err != nil {
+		return errors.Wrapf(err, "log output %s is not writable", path).
+			WithCode(errors.CodeConfiguration).
+			WithMetadata("path", path)
+	}
+
+	if !info.IsDir() {
+		return errors.Newf("log output %s is not writable: %s is not a directory", path, dir).
+			WithCode(errors.CodeConfiguration).
+			WithMetadata("path", path)
+	}
+
+	return nil
+}
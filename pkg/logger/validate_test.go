@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"task-queue/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWithError_InvalidConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "not-a-dir")
+	require.NoError(t, os.WriteFile(file, nil, 0o600))
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{
+			name:   "invalid format",
+			modify: func(c *Config) { c.Format = "xml" },
+		},
+		{
+			name:   "invalid level",
+			modify: func(c *Config) { c.Level = "verbose" },
+		},
+		{
+			name:   "missing output directory",
+			modify: func(c *Config) { c.OutputPaths = []string{filepath.Join(t.TempDir(), "missing", "out.log")} },
+		},
+		{
+			name:   "unwritable output directory",
+			modify: func(c *Config) { c.OutputPaths = []string{filepath.Join(file, "out.log")} },
+		},
+		{
+			name:   "unwritable error path",
+			modify: func(c *Config) { c.ErrorPaths = []string{filepath.Join(file, "err.log")} },
+		},
+		{
+			name:   "invalid redact pattern",
+			modify: func(c *Config) { c.RedactPatterns = []string{"("} },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.modify(&cfg)
+
+			log, err := NewWithError(cfg)
+			require.Error(t, err)
+			assert.Nil(t, log)
+			assert.True(t, errors.HasCode(err, errors.CodeConfiguration))
+		})
+	}
+}
+
+func TestNewWithError_Valid(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Level = ""
+	cfg.OutputPaths = []string{filepath.Join(t.TempDir(), "out.log")}
+
+	log, err := NewWithError(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "info", log.Level())
+}
+
+func TestNew_PanicsOnInvalidConfig(t *testing.T) {
+	assert.Panics(t, func() {
+		New(Config{Level: "info", Format: "xml"})
+	})
+}
@@ -0,0 +1,161 @@
+package retry
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// budgetSlots is how many counters a Budget splits its window into. Events
+// leave the window a slot at a time, so it slides in tenths of its length.
+const budgetSlots = 10
+
+// Budget caps retries across every operation sharing it to a ratio of the
+// initial attempts seen over a sliding window, so independent callers can't
+// multiply the load on a struggling dependency. First attempts are never
+// limited. A Budget is safe for concurrent use, and its memory does not
+// grow with the traffic it sees.
+type Budget struct {
+	ratio      float64
+	minRetries int
+	slot       time.Duration
+	now        func() time.Time
+
+	mu    sync.Mutex
+	slots [budgetSlots]budgetSlot
+
+	granted atomic.Uint64
+	denied  atomic.Uint64
+}
+
+// budgetSlot counts the events of one slice of a Budget's window
+type budgetSlot struct {
+	index    int64
+	attempts int
+	retries  int
+}
+
+// BudgetOption modifies budget configuration
+type BudgetOption func(*Budget)
+
+// BudgetStats counts the retries a Budget granted and denied
+type BudgetStats struct {
+	Granted uint64 `json:"granted"`
+	Denied  uint64 `json:"denied"`
+}
+
+// NewBudget creates a budget allowing at most retries retries for every
+// attempts initial attempts within window, e.g. NewBudget(1, 10, time.Minute)
+// allows one retry per ten operations started in the last minute
+func NewBudget(retries, attempts int, window time.Duration, opts ...BudgetOption) *Budget {
+	b := &Budget{
+		slot: max(window/budgetSlots, 1),
+		now:  time.Now,
+	}
+
+	if attempts > 0 {
+		b.ratio = float64(retries) / float64(attempts)
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// WithMinRetries allows n retries per window regardless of the ratio, so
+// low-traffic callers can still retry
+func WithMinRetries(n int) BudgetOption {
+	return func(b *Budget) {
+		b.minRetries = n
+	}
+}
+
+// WithBudgetClock replaces the clock used for the sliding window, mainly for
+// tests
+func WithBudgetClock(now func() time.Time) BudgetOption {
+	return func(b *Budget) {
+		b.now = now
+	}
+}
+
+// RecordAttempt records an initial attempt, raising the retry allowance
+func (b *Budget) RecordAttempt() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.current(b.now()).attempts++
+}
+
+// AllowRetry reports whether a retry fits in the budget, consuming a token
+// when it does
+func (b *Budget) AllowRetry() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	attempts, retries := b.totals(now)
+
+	allowed := float64(attempts) * b.ratio
+	if float64(retries+1) > allowed && retries >= b.minRetries {
+		b.denied.Add(1)
+		return false
+	}
+
+	b.current(now).retries++
+	b.granted.Add(1)
+	return true
+}
+
+// Stats returns the number of retries granted and denied so far
+func (b *Budget) Stats() BudgetStats {
+	return BudgetStats{
+		Granted: b.granted.Load(),
+		Denied:  b.denied.Load(),
+	}
+}
+
+// current returns the counter of the slot holding now, emptied first when
+// it still counts an older slot. The caller holds b.mu.
+func (b *Budget) current(now time.Time) *budgetSlot {
+	index := now.UnixNano() / int64(b.slot)
+	s := &b.slots[index%budgetSlots]
+	if s.index != index {
+		*s = budgetSlot{index: index}
+	}
+
+	return s
+}
+
+// totals returns the attempts and retries counted by the slots of the
+// window ending at now. The caller holds b.mu.
+func (b *Budget) totals(now time.Time) (attempts, retries int) {
+	index := now.UnixNano() / int64(b.slot)
+	for _, s := range b.slots {
+		if s.index > index-budgetSlots && s.index <= index {
+			attempts += s.attempts
+			retries += s.retries
+		}
+	}
+
+	return attempts, retries
+}
+
+// BudgetExhaustedError is returned when a Budget denies a retry. It wraps
+// the error of the last attempt.
+type BudgetExhaustedError struct {
+	Attempts int
+	Err      error
+}
+
+// Error implements the error interface
+func (e *BudgetExhaustedError) Error() string {
+	return fmt.Sprintf("retry budget exhausted after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the error of the last attempt
+func (e *BudgetExhaustedError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,158 @@
+package retry
+
+import (
+	stderrors "errors"
+	"sync"
+	"sync/atomic"
+	"task-queue/pkg/errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a manually advanced clock for budget tests
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func TestBudget_Ratio(t *testing.T) {
+	b := NewBudget(1, 4, time.Minute)
+
+	assert.False(t, b.AllowRetry(), "no attempts, no allowance")
+
+	for i := 0; i < 8; i++ {
+		b.RecordAttempt()
+	}
+
+	assert.True(t, b.AllowRetry())
+	assert.True(t, b.AllowRetry())
+	assert.False(t, b.AllowRetry())
+	assert.Equal(t, BudgetStats{Granted: 2, Denied: 2}, b.Stats())
+}
+
+func TestBudget_MinRetries(t *testing.T) {
+	b := NewBudget(1, 10, time.Minute, WithMinRetries(2))
+	b.RecordAttempt()
+
+	assert.True(t, b.AllowRetry())
+	assert.True(t, b.AllowRetry())
+	assert.False(t, b.AllowRetry())
+}
+
+func TestBudget_SlidingWindow(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	b := NewBudget(1, 1, 10*time.Second, WithBudgetClock(clock.Now))
+
+	b.RecordAttempt()
+	assert.True(t, b.AllowRetry())
+	assert.False(t, b.AllowRetry())
+
+	clock.Advance(5 * time.Second)
+	b.RecordAttempt()
+	assert.True(t, b.AllowRetry())
+	assert.False(t, b.AllowRetry())
+
+	clock.Advance(6 * time.Second)
+	assert.False(t, b.AllowRetry(), "the first attempt and retry expired together")
+
+	b.RecordAttempt()
+	assert.True(t, b.AllowRetry())
+
+	clock.Advance(time.Minute)
+	assert.False(t, b.AllowRetry(), "everything expired")
+}
+
+func TestBudget_ExpiresBySlot(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	b := NewBudget(2, 1, 10*time.Second, WithBudgetClock(clock.Now))
+
+	b.RecordAttempt()
+	clock.Advance(9*time.Second + 500*time.Millisecond)
+	assert.True(t, b.AllowRetry(), "the attempt's slot is still in the window")
+
+	clock.Advance(500 * time.Millisecond)
+	assert.False(t, b.AllowRetry(), "the attempt left the window with its slot")
+}
+
+func TestBudget_ConcurrentRatio(t *testing.T) {
+	b := NewBudget(1, 10, time.Minute)
+
+	const workers, perWorker = 50, 40
+	var calls atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perWorker; j++ {
+				_ = Do(func() error {
+					calls.Add(1)
+					return errors.New("unavailable").WithCode(errors.CodeDatabase)
+				}, WithMaxAttempts(4), WithBudget(b), WithBackoffStrategy(NewFixedBackoff(0)))
+			}
+		}()
+	}
+	wg.Wait()
+
+	operations := uint64(workers * perWorker)
+	stats := b.Stats()
+	assert.LessOrEqual(t, stats.Granted, operations/10, "retries stay within a tenth of the attempts")
+	assert.Greater(t, stats.Granted, uint64(0))
+	assert.Equal(t, int64(operations+stats.Granted), calls.Load(), "first attempts are never blocked")
+	assert.Greater(t, stats.Denied, stats.Granted)
+}
+
+func TestDo_BudgetExhausted(t *testing.T) {
+	b := NewBudget(0, 1, time.Minute)
+	calls := 0
+	cause := errors.New("connection reset").WithCode(errors.CodeNetwork)
+
+	err := Do(func() error {
+		calls++
+		return cause
+	}, WithMaxAttempts(5), WithBudget(b))
+
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+
+	var exhausted *BudgetExhaustedError
+	require.True(t, stderrors.As(err, &exhausted))
+	assert.Equal(t, 1, exhausted.Attempts)
+	assert.ErrorIs(t, err, cause)
+	assert.Contains(t, err.Error(), "retry budget exhausted")
+}
+
+func TestDo_BudgetGrantsRetries(t *testing.T) {
+	b := NewBudget(1, 1, time.Minute)
+	calls := 0
+
+	err := Do(func() error {
+		calls++
+		if calls < 2 {
+			return errors.New("temporary error")
+		}
+
+		return nil
+	}, WithBudget(b), WithBackoffStrategy(NewFixedBackoff(time.Millisecond)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, BudgetStats{Granted: 1}, b.Stats())
+}
@@ -0,0 +1,94 @@
+package retry
+
+import (
+	"fmt"
+	"task-queue/pkg/errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// attemptsFor runs an operation that always fails with err and returns the
+// number of attempts Do made
+func attemptsFor(err error, opts ...Option) int {
+	calls := 0
+	opts = append([]Option{
+		WithMaxAttempts(3),
+		WithBackoffStrategy(NewFixedBackoff(time.Millisecond)),
+	}, opts...)
+
+	_ = Do(func() error {
+		calls++
+		return err
+	}, opts...)
+
+	return calls
+}
+
+func TestWithRetryOnCodes(t *testing.T) {
+	only := WithRetryOnCodes(errors.CodeNetwork, errors.CodeTimeout)
+
+	assert.Equal(t, 3, attemptsFor(errors.New("reset").WithCode(errors.CodeNetwork), only))
+	assert.Equal(t, 3, attemptsFor(errors.Timeout("slow"), only))
+	assert.Equal(t, 1, attemptsFor(errors.New("db").WithCode(errors.CodeDatabase), only),
+		"retryable by default but not allowed")
+	assert.Equal(t, 1, attemptsFor(fmt.Errorf("plain"), only))
+
+	buried := errors.Wrap(fmt.Errorf("dial: %w", errors.New("reset").WithCode(errors.CodeNetwork)),
+		"enqueue").WithCode(errors.CodeInternal)
+	assert.Equal(t, 3, attemptsFor(buried, only), "codes anywhere in the chain count")
+}
+
+func TestWithAbortOnCodes(t *testing.T) {
+	abort := WithAbortOnCodes(errors.CodeConflict)
+
+	assert.Equal(t, 1, attemptsFor(errors.Conflict("version mismatch"), abort))
+	assert.Equal(t, 1, attemptsFor(errors.Wrap(errors.Conflict("stale"), "update"), abort))
+	assert.Equal(t, 3, attemptsFor(errors.New("reset").WithCode(errors.CodeNetwork), abort),
+		"other errors keep the default predicate")
+	assert.Equal(t, 1, attemptsFor(errors.Validation("bad"), abort),
+		"the default predicate still stops permanent errors")
+}
+
+func TestWithRetryIfAny(t *testing.T) {
+	isTimeout := func(err error) bool { return errors.IsTimeout(err) }
+	isConflict := func(err error) bool { return errors.HasCode(err, errors.CodeConflict) }
+	any := WithRetryIfAny(isTimeout, isConflict)
+
+	assert.Equal(t, 3, attemptsFor(errors.Timeout("slow"), any))
+	assert.Equal(t, 3, attemptsFor(errors.Conflict("stale"), any))
+	assert.Equal(t, 1, attemptsFor(errors.New("reset").WithCode(errors.CodeNetwork), any))
+	assert.Equal(t, 1, attemptsFor(errors.Timeout("slow"), WithRetryIfAny()), "no predicates never retry")
+}
+
+func TestRetryCodes_Precedence(t *testing.T) {
+	network := errors.New("reset").WithCode(errors.CodeNetwork)
+	conflict := errors.Conflict("stale")
+	wrappedBoth := errors.Wrap(network, "write").WithCode(errors.CodeConflict)
+
+	tests := []struct {
+		name  string
+		err   error
+		opts  []Option
+		calls int
+	}{
+		{"abort beats allow", network, []Option{
+			WithRetryOnCodes(errors.CodeNetwork), WithAbortOnCodes(errors.CodeNetwork)}, 1},
+		{"abort beats allow anywhere in the chain", wrappedBoth, []Option{
+			WithRetryOnCodes(errors.CodeNetwork), WithAbortOnCodes(errors.CodeConflict)}, 1},
+		{"allow beats default", conflict, []Option{WithRetryOnCodes(errors.CodeConflict)}, 3},
+		{"allow beats custom predicate", network, []Option{
+			WithRetryIf(func(error) bool { return false }), WithRetryOnCodes(errors.CodeNetwork)}, 3},
+		{"abort beats custom predicate", network, []Option{
+			WithRetryIfAny(func(error) bool { return true }), WithAbortOnCodes(errors.CodeNetwork)}, 1},
+		{"options accumulate", conflict, []Option{
+			WithRetryOnCodes(errors.CodeNetwork), WithRetryOnCodes(errors.CodeConflict)}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.calls, attemptsFor(tt.err, tt.opts...))
+		})
+	}
+}
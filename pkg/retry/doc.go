@@ -23,4 +23,61 @@
 //	    retry.WithMultiplier(2.0),
 //	)
 //	err := retry.Do(operation, retry.WithBackoffStrategy(backoff))
+//
+// Choosing a strategy: ExponentialBackoff with its small proportional jitter
+// suits a single caller. When many workers fail at once, such as after a
+// database failover, prefer FullJitterBackoff, which spreads retries the
+// widest, or DecorrelatedJitterBackoff, which spreads them almost as well
+// while keeping delays from collapsing towards zero. SetDefaultBackoff makes
+// either the default for every call without an explicit strategy:
+//
+//	retry.SetDefaultBackoff(func() retry.BackoffStrategy {
+//	    return retry.NewDecorrelatedJitterBackoff(100*time.Millisecond, 30*time.Second)
+//	})
+//
+// Strategies can also be built by name from configuration, with
+// StrategyFromConfig and the names fixed, linear, exponential, fibonacci,
+// polynomial, decorrelated, and full_jitter:
+//
+//	backoff, err := retry.StrategyFromConfig("fibonacci", map[string]any{
+//	    "base": "100ms",
+//	    "max":  "30s",
+//	})
+//
+// A Budget shared through WithBudget caps retries across callers to a ratio
+// of their first attempts, so a failing dependency sees at most a bounded
+// amount of extra load:
+//
+//	budget := retry.NewBudget(1, 10, time.Minute)
+//	err := retry.Do(operation, retry.WithBudget(budget))
+//
+// WithOnRetryInfo reports each retry with the delay about to be slept, and
+// DoWithReport returns a Report of every attempt:
+//
+//	report, err := retry.DoWithReport(operation,
+//	    retry.WithOnRetryInfo(func(info retry.RetryInfo) {
+//	        log.Printf("attempt %d/%d failed, retrying in %s: %v",
+//	            info.Attempt, info.MaxAttempts, info.NextDelay, info.Err)
+//	    }),
+//	)
+//
+// WithMaxElapsedTime bounds a call by total time instead of attempts, and
+// WithInfiniteAttempts drops the attempt limit for supervisory loops that
+// should retry until their context is done:
+//
+//	err := retry.DoWithContext(ctx, reconnect, retry.WithInfiniteAttempts())
+//
+// A Retrier freezes a policy once and is safe to share. Database, Network,
+// and Quick return the preconfigured ones used by the storage and queue
+// packages:
+//
+//	retrier := retry.NewRetrier(retry.WithMaxAttempts(5), retry.WithName("billing"))
+//	err := retrier.DoContext(ctx, charge)
+//	job, err := retry.ResultContext(ctx, retry.Database(), load)
+//
+// Operations that produce a value:
+//
+//	job, err := retry.DoWithResult(func() (*models.Job, error) {
+//	    return q.Dequeue(ctx)
+//	}, retry.WithMaxAttempts(5))
 package retry
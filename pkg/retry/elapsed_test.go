@@ -0,0 +1,144 @@
+package retry
+
+import (
+	"context"
+	"task-queue/pkg/errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMaxElapsedTime(t *testing.T) {
+	calls := 0
+	start := time.Now()
+	err := Do(func() error {
+		calls++
+		return errors.New("down")
+	},
+		WithMaxAttempts(1000),
+		WithMaxElapsedTime(50*time.Millisecond),
+		WithBackoffStrategy(NewFixedBackoff(10*time.Millisecond)),
+	)
+
+	elapsed := time.Since(start)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max elapsed time 50ms exceeded")
+	assert.Less(t, elapsed, 50*time.Millisecond, "no sleep crosses the bound")
+	assert.Greater(t, calls, 1)
+	assert.Less(t, calls, 6)
+
+	var retryErr *errors.Error
+	require.ErrorAs(t, err, &retryErr)
+	assert.Equal(t, BoundMaxElapsedTime, retryErr.Metadata["bound"])
+}
+
+func TestWithMaxElapsedTime_AttemptsHitFirst(t *testing.T) {
+	err := Do(func() error { return errors.New("down") },
+		WithMaxAttempts(3),
+		WithMaxElapsedTime(time.Minute),
+		WithBackoffStrategy(NewFixedBackoff(time.Millisecond)),
+	)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "operation failed after 3 attempts: max attempts reached")
+
+	var retryErr *errors.Error
+	require.ErrorAs(t, err, &retryErr)
+	assert.Equal(t, BoundMaxAttempts, retryErr.Metadata["bound"])
+}
+
+func TestWithMaxElapsedTime_SleepWouldCross(t *testing.T) {
+	calls := 0
+	err := Do(func() error {
+		calls++
+		return errors.New("down")
+	},
+		WithMaxElapsedTime(20*time.Millisecond),
+		WithBackoffStrategy(NewFixedBackoff(time.Second)),
+	)
+
+	require.Error(t, err)
+	assert.Equal(t, 1, calls, "a one second sleep never fits in 20ms")
+	assert.Contains(t, err.Error(), "operation failed after 1 attempts in")
+}
+
+func TestWithInfiniteAttempts_ElapsedBound(t *testing.T) {
+	calls := 0
+	err := Do(func() error {
+		calls++
+		return errors.New("down")
+	},
+		WithMaxAttempts(2),
+		WithInfiniteAttempts(),
+		WithMaxElapsedTime(30*time.Millisecond),
+		WithBackoffStrategy(NewFixedBackoff(time.Millisecond)),
+	)
+
+	require.Error(t, err)
+	assert.Greater(t, calls, 2, "MaxAttempts is ignored")
+	assert.Contains(t, err.Error(), "max elapsed time 30ms exceeded")
+}
+
+func TestWithInfiniteAttempts_ContextBound(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	var infos []RetryInfo
+	err := DoWithContext(ctx, func(context.Context) error {
+		calls++
+		return errors.New("down")
+	},
+		WithInfiniteAttempts(),
+		WithBackoffStrategy(NewFixedBackoff(time.Millisecond)),
+		WithOnRetryInfo(func(info RetryInfo) { infos = append(infos, info) }),
+	)
+
+	require.Error(t, err)
+	assert.True(t, errors.HasCode(err, errors.CodeTimeout))
+	assert.Greater(t, calls, 3)
+	require.NotEmpty(t, infos)
+	assert.Zero(t, infos[0].MaxAttempts)
+	assert.Equal(t, -1, infos[0].Remaining())
+}
+
+func TestWithInfiniteAttempts_SucceedsEventually(t *testing.T) {
+	calls := 0
+	err := Do(func() error {
+		calls++
+		if calls < 10 {
+			return errors.New("down")
+		}
+
+		return nil
+	},
+		WithInfiniteAttempts(),
+		WithMaxElapsedTime(time.Second),
+		WithBackoffStrategy(NewFixedBackoff(time.Microsecond)),
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 10, calls)
+}
+
+func TestWithInfiniteAttempts_RequiresABound(t *testing.T) {
+	calls := 0
+	err := Do(func() error {
+		calls++
+		return nil
+	}, WithInfiniteAttempts())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "require a max elapsed time or a cancellable context")
+	assert.Zero(t, calls)
+
+	r := NewRetrier(WithInfiniteAttempts())
+	assert.Zero(t, r.MaxAttempts())
+	assert.Error(t, r.Do(func() error { return nil }), "Do has no context to bound it")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, r.DoContext(ctx, func(context.Context) error { return nil }))
+}
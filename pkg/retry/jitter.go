@@ -0,0 +1,120 @@
+package retry
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DecorrelatedJitterBackoff implements the "decorrelated jitter" algorithm:
+// each delay is drawn uniformly between Base and three times the previous
+// delay, capped at Cap. Spreading delays this way keeps many workers that
+// failed together from retrying together.
+//
+// The strategy remembers the previous delay, and Do resets it at the start
+// of every call. It is safe for concurrent use, but concurrent calls sharing
+// an instance interleave their sequences; give each worker its own instance,
+// use SetDefaultBackoff, or use a Retrier, which clones it for every call, to
+// keep every call on its own sequence.
+type DecorrelatedJitterBackoff struct {
+	Base time.Duration
+	Cap  time.Duration
+
+	mu   sync.Mutex
+	prev time.Duration
+}
+
+// NewDecorrelatedJitterBackoff creates a decorrelated jitter strategy
+func NewDecorrelatedJitterBackoff(base, cap time.Duration) *DecorrelatedJitterBackoff {
+	return &DecorrelatedJitterBackoff{Base: base, Cap: cap}
+}
+
+// Next returns a delay between Base and three times the previous delay,
+// capped at Cap. The attempt number is not used.
+func (d *DecorrelatedJitterBackoff) Next(attempt int) time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	prev := d.prev
+	if prev < d.Base {
+		prev = d.Base
+	}
+
+	upper := float64(prev) * 3
+	delay := time.Duration(float64(d.Base) + rand.Float64()*(upper-float64(d.Base)))
+	if delay > d.Cap {
+		delay = d.Cap
+	}
+
+	d.prev = delay
+	return delay
+}
+
+// Clone returns a strategy with the same settings and a fresh sequence
+func (d *DecorrelatedJitterBackoff) Clone() BackoffStrategy {
+	return NewDecorrelatedJitterBackoff(d.Base, d.Cap)
+}
+
+// Reset restarts the sequence from Base
+func (d *DecorrelatedJitterBackoff) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.prev = 0
+}
+
+// FullJitterBackoff implements the "full jitter" algorithm: each delay is
+// drawn uniformly between zero and the exponential delay
+// min(Cap, Base*2^(attempt-1)). It is stateless and safe for concurrent use.
+type FullJitterBackoff struct {
+	Base time.Duration
+	Cap  time.Duration
+}
+
+// NewFullJitterBackoff creates a full jitter strategy
+func NewFullJitterBackoff(base, cap time.Duration) *FullJitterBackoff {
+	return &FullJitterBackoff{Base: base, Cap: cap}
+}
+
+// Next returns a delay between zero and the capped exponential delay for
+// attempt
+func (f *FullJitterBackoff) Next(attempt int) time.Duration {
+	if attempt <= 0 {
+		return 0
+	}
+
+	ceiling := math.Min(float64(f.Cap), float64(f.Base)*math.Pow(2, float64(attempt-1)))
+	return time.Duration(rand.Float64() * ceiling)
+}
+
+// Reset resets the backoff strategy
+func (f *FullJitterBackoff) Reset() {
+	// No state to reset for full jitter backoff
+}
+
+// defaultBackoff builds the strategy used by DefaultConfig when set
+var defaultBackoff atomic.Pointer[func() BackoffStrategy]
+
+// SetDefaultBackoff opts DefaultConfig, and so Do without
+// WithBackoffStrategy, into the strategies built by factory. The factory runs
+// once per call, so stateful strategies such as DecorrelatedJitterBackoff get
+// a fresh sequence each time. A nil factory restores ExponentialBackoff.
+func SetDefaultBackoff(factory func() BackoffStrategy) {
+	if factory == nil {
+		defaultBackoff.Store(nil)
+		return
+	}
+
+	defaultBackoff.Store(&factory)
+}
+
+// newDefaultBackoff builds the strategy for DefaultConfig
+func newDefaultBackoff() BackoffStrategy {
+	if factory := defaultBackoff.Load(); factory != nil {
+		return (*factory)()
+	}
+
+	return NewExponentialBackoff()
+}
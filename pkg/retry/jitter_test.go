@@ -0,0 +1,127 @@
+package retry
+
+import (
+	"sync"
+	"task-queue/pkg/errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const samples = 10000
+
+func TestFullJitterBackoff_Bounds(t *testing.T) {
+	b := NewFullJitterBackoff(100*time.Millisecond, time.Second)
+
+	tests := []struct {
+		attempt int
+		ceiling time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{20, time.Second},
+	}
+
+	for _, tt := range tests {
+		var sum time.Duration
+		for i := 0; i < samples; i++ {
+			delay := b.Next(tt.attempt)
+			require.GreaterOrEqual(t, delay, time.Duration(0))
+			require.Less(t, delay, tt.ceiling)
+			sum += delay
+		}
+
+		mean := sum / samples
+		assert.InDelta(t, float64(tt.ceiling/2), float64(mean), float64(tt.ceiling)/10,
+			"attempt %d mean is about half the ceiling", tt.attempt)
+	}
+
+	assert.Zero(t, b.Next(0))
+}
+
+func TestDecorrelatedJitterBackoff_Bounds(t *testing.T) {
+	base, cap := 50*time.Millisecond, 2*time.Second
+	b := NewDecorrelatedJitterBackoff(base, cap)
+
+	capped := 0
+	for run := 0; run < samples/10; run++ {
+		b.Reset()
+		prev := base
+		for attempt := 1; attempt <= 10; attempt++ {
+			delay := b.Next(attempt)
+			require.GreaterOrEqual(t, delay, base)
+			require.LessOrEqual(t, delay, cap)
+			require.LessOrEqual(t, delay, 3*prev)
+			if delay == cap {
+				capped++
+			}
+
+			prev = delay
+		}
+	}
+
+	assert.Greater(t, capped, 0, "long sequences reach the cap")
+}
+
+func TestDecorrelatedJitterBackoff_Reset(t *testing.T) {
+	base := 10 * time.Millisecond
+	b := NewDecorrelatedJitterBackoff(base, time.Hour)
+
+	for i := 0; i < 20; i++ {
+		b.Next(i + 1)
+	}
+
+	b.Reset()
+	for i := 0; i < samples; i++ {
+		delay := b.Next(1)
+		require.LessOrEqual(t, delay, 3*base, "first delay after reset stays within 3x base")
+		b.Reset()
+	}
+}
+
+func TestDecorrelatedJitterBackoff_Concurrent(t *testing.T) {
+	base, cap := time.Millisecond, 100*time.Millisecond
+	b := NewDecorrelatedJitterBackoff(base, cap)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				delay := b.Next(j)
+				assert.GreaterOrEqual(t, delay, base)
+				assert.LessOrEqual(t, delay, cap)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSetDefaultBackoff(t *testing.T) {
+	assert.IsType(t, &ExponentialBackoff{}, DefaultConfig().BackoffStrategy)
+
+	SetDefaultBackoff(func() BackoffStrategy {
+		return NewDecorrelatedJitterBackoff(time.Millisecond, 5*time.Millisecond)
+	})
+	t.Cleanup(func() { SetDefaultBackoff(nil) })
+
+	first, second := DefaultConfig().BackoffStrategy, DefaultConfig().BackoffStrategy
+	assert.IsType(t, &DecorrelatedJitterBackoff{}, first)
+	assert.NotSame(t, first, second, "every call gets its own strategy")
+
+	calls := 0
+	err := Do(func() error {
+		calls++
+		return errors.New("temporary error")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 3, calls)
+
+	SetDefaultBackoff(nil)
+	assert.IsType(t, &ExponentialBackoff{}, DefaultConfig().BackoffStrategy)
+}
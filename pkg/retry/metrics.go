@@ -0,0 +1,72 @@
+package retry
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Metrics receives the outcomes of retried operations, labeled by the name
+// set with WithName. Unnamed operations report an empty name.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// IncAttempt counts one run of the operation
+	IncAttempt(op string)
+
+	// IncSuccess counts an operation that eventually succeeded
+	IncSuccess(op string)
+
+	// IncExhausted counts an operation that ran out of attempts or was
+	// denied a retry by its budget
+	IncExhausted(op string)
+
+	// ObserveDelay records the sleep before a retry
+	ObserveDelay(op string, d time.Duration)
+}
+
+// WithMetrics reports the outcome of the operation to m, replacing the
+// package default
+func WithMetrics(m Metrics) Option {
+	return func(c *Config) {
+		c.Metrics = m
+	}
+}
+
+// WithName names the operation, e.g. "storage.Create", for the label used
+// by Metrics
+func WithName(name string) Option {
+	return func(c *Config) {
+		c.Name = name
+	}
+}
+
+// defaultMetrics holds the Metrics used by DefaultConfig when set
+var defaultMetrics atomic.Pointer[Metrics]
+
+// SetDefaultMetrics reports every call built from DefaultConfig, and so Do
+// without WithMetrics, to m. Set it once at startup; call sites then only
+// need WithName to get a useful label. A nil m turns the default off.
+func SetDefaultMetrics(m Metrics) {
+	if m == nil {
+		defaultMetrics.Store(nil)
+		return
+	}
+
+	defaultMetrics.Store(&m)
+}
+
+// loadDefaultMetrics returns the default Metrics, or nil when none is set
+func loadDefaultMetrics() Metrics {
+	if m := defaultMetrics.Load(); m != nil {
+		return *m
+	}
+
+	return nil
+}
+
+// noopMetrics discards every observation
+type noopMetrics struct{}
+
+func (noopMetrics) IncAttempt(string)                  {}
+func (noopMetrics) IncSuccess(string)                  {}
+func (noopMetrics) IncExhausted(string)                {}
+func (noopMetrics) ObserveDelay(string, time.Duration) {}
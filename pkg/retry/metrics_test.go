@@ -0,0 +1,133 @@
+package retry
+
+import (
+	"sync"
+	"task-queue/pkg/errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeMetrics counts every observation by operation
+type fakeMetrics struct {
+	mu        sync.Mutex
+	attempts  map[string]int
+	successes map[string]int
+	exhausted map[string]int
+	delays    map[string][]time.Duration
+}
+
+func newFakeMetrics() *fakeMetrics {
+	return &fakeMetrics{
+		attempts:  make(map[string]int),
+		successes: make(map[string]int),
+		exhausted: make(map[string]int),
+		delays:    make(map[string][]time.Duration),
+	}
+}
+
+func (f *fakeMetrics) IncAttempt(op string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attempts[op]++
+}
+
+func (f *fakeMetrics) IncSuccess(op string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.successes[op]++
+}
+
+func (f *fakeMetrics) IncExhausted(op string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.exhausted[op]++
+}
+
+func (f *fakeMetrics) ObserveDelay(op string, d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.delays[op] = append(f.delays[op], d)
+}
+
+func TestWithMetrics_SuccessAfterRetry(t *testing.T) {
+	m := newFakeMetrics()
+	calls := 0
+	err := Do(func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("temporary")
+		}
+
+		return nil
+	},
+		WithName("storage.Create"),
+		WithMetrics(m),
+		WithBackoffStrategy(NewFixedBackoff(time.Millisecond)),
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, m.attempts["storage.Create"])
+	assert.Equal(t, 1, m.successes["storage.Create"])
+	assert.Zero(t, m.exhausted["storage.Create"])
+	assert.Equal(t, []time.Duration{time.Millisecond, time.Millisecond}, m.delays["storage.Create"])
+}
+
+func TestWithMetrics_Exhausted(t *testing.T) {
+	m := newFakeMetrics()
+	err := Do(func() error { return errors.New("down") },
+		WithName("queue.Enqueue"),
+		WithMetrics(m),
+		WithMaxAttempts(4),
+		WithBackoffStrategy(NewFixedBackoff(time.Millisecond)),
+	)
+
+	assert.Error(t, err)
+	assert.Equal(t, 4, m.attempts["queue.Enqueue"])
+	assert.Zero(t, m.successes["queue.Enqueue"])
+	assert.Equal(t, 1, m.exhausted["queue.Enqueue"])
+	assert.Len(t, m.delays["queue.Enqueue"], 3)
+}
+
+func TestWithMetrics_BudgetDenied(t *testing.T) {
+	m := newFakeMetrics()
+	budget := NewBudget(0, 1, time.Minute)
+	err := Do(func() error { return errors.New("down") },
+		WithName("storage.Update"),
+		WithMetrics(m),
+		WithBudget(budget),
+		WithBackoffStrategy(NewFixedBackoff(time.Millisecond)),
+	)
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, m.attempts["storage.Update"])
+	assert.Equal(t, 1, m.exhausted["storage.Update"])
+}
+
+func TestWithMetrics_PermanentError(t *testing.T) {
+	m := newFakeMetrics()
+	err := Do(func() error { return errors.Validation("bad") }, WithMetrics(m))
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, m.attempts[""], "unnamed operations report an empty name")
+	assert.Zero(t, m.successes[""])
+	assert.Zero(t, m.exhausted[""])
+}
+
+func TestSetDefaultMetrics(t *testing.T) {
+	m := newFakeMetrics()
+	SetDefaultMetrics(m)
+	t.Cleanup(func() { SetDefaultMetrics(nil) })
+
+	assert.NoError(t, Do(func() error { return nil }, WithName("storage.Get")))
+	assert.Equal(t, 1, m.successes["storage.Get"])
+
+	override := newFakeMetrics()
+	assert.NoError(t, Do(func() error { return nil }, WithName("storage.Get"), WithMetrics(override)))
+	assert.Equal(t, 1, m.successes["storage.Get"], "WithMetrics replaces the default")
+	assert.Equal(t, 1, override.successes["storage.Get"])
+
+	SetDefaultMetrics(nil)
+	assert.Nil(t, DefaultConfig().Metrics)
+}
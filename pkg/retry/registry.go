@@ -0,0 +1,325 @@
+package retry
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"task-queue/pkg/errors"
+	"time"
+)
+
+// StrategyFactory builds a backoff strategy from configuration parameters
+type StrategyFactory func(params Params) (BackoffStrategy, error)
+
+// Params holds the parameters of a backoff strategy read from configuration.
+// Durations are strings such as "100ms" or time.Duration values; numbers
+// may be any numeric type or a numeric string.
+type Params map[string]any
+
+// Built-in strategy names accepted by StrategyFromConfig
+const (
+	StrategyFixed        = "fixed"
+	StrategyLinear       = "linear"
+	StrategyExponential  = "exponential"
+	StrategyFibonacci    = "fibonacci"
+	StrategyPolynomial   = "polynomial"
+	StrategyDecorrelated = "decorrelated"
+	StrategyFullJitter   = "full_jitter"
+)
+
+var (
+	strategiesMu sync.RWMutex
+	strategies   = map[string]StrategyFactory{
+		StrategyFixed:        fixedFromParams,
+		StrategyLinear:       linearFromParams,
+		StrategyExponential:  exponentialFromParams,
+		StrategyFibonacci:    fibonacciFromParams,
+		StrategyPolynomial:   polynomialFromParams,
+		StrategyDecorrelated: decorrelatedFromParams,
+		StrategyFullJitter:   fullJitterFromParams,
+	}
+)
+
+// RegisterStrategy makes a strategy available to StrategyFromConfig under
+// name, replacing any strategy already registered with it
+func RegisterStrategy(name string, factory StrategyFactory) {
+	strategiesMu.Lock()
+	defer strategiesMu.Unlock()
+
+	strategies[strings.ToLower(name)] = factory
+}
+
+// Strategies returns the registered strategy names in order
+func Strategies() []string {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// StrategyFromConfig builds the backoff strategy registered under name,
+// matched case-insensitively, from params. Parameters left out keep the
+// defaults of the strategy's constructor. Unknown names, unknown parameters,
+// and malformed values are reported as CodeConfiguration errors.
+func StrategyFromConfig(name string, params map[string]any) (BackoffStrategy, error) {
+	strategiesMu.RLock()
+	factory, ok := strategies[strings.ToLower(name)]
+	strategiesMu.RUnlock()
+
+	if !ok {
+		return nil, errors.Newf("unknown backoff strategy %q, expected one of %s",
+			name, strings.Join(Strategies(), ", ")).WithCode(errors.CodeConfiguration)
+	}
+
+	strategy, err := factory(params)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s backoff", strings.ToLower(name)).
+			WithCode(errors.CodeConfiguration)
+	}
+
+	return strategy, nil
+}
+
+// Duration returns the duration parameter key, or def when it is absent
+func (p Params) Duration(key string, def time.Duration) (time.Duration, error) {
+	value, ok := p[key]
+	if !ok || value == nil {
+		return def, nil
+	}
+
+	var (
+		d   time.Duration
+		err error
+	)
+
+	switch v := value.(type) {
+	case time.Duration:
+		d = v
+
+	case string:
+		d, err = time.ParseDuration(v)
+
+	default:
+		err = fmt.Errorf("got %T", value)
+	}
+
+	if err != nil {
+		return 0, errors.Newf("%s must be a duration such as 100ms: %v", key, err).
+			WithCode(errors.CodeConfiguration)
+	}
+
+	if d < 0 {
+		return 0, errors.Newf("%s must not be negative", key).WithCode(errors.CodeConfiguration)
+	}
+
+	return d, nil
+}
+
+// Float returns the numeric parameter key, or def when it is absent
+func (p Params) Float(key string, def float64) (float64, error) {
+	value, ok := p[key]
+	if !ok || value == nil {
+		return def, nil
+	}
+
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+
+	case float32:
+		return float64(v), nil
+
+	case int:
+		return float64(v), nil
+
+	case int64:
+		return float64(v), nil
+
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err == nil {
+			return f, nil
+		}
+	}
+
+	return 0, errors.Newf("%s must be a number, got %v", key, value).
+		WithCode(errors.CodeConfiguration)
+}
+
+// only reports parameters other than keys, catching misspelled names
+func (p Params) only(keys ...string) error {
+	var unknown []string
+	for key := range p {
+		found := false
+		for _, allowed := range keys {
+			if key == allowed {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			unknown = append(unknown, key)
+		}
+	}
+
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Strings(unknown)
+	return errors.Newf("unknown parameters %s, expected %s",
+		strings.Join(unknown, ", "), strings.Join(keys, ", ")).
+		WithCode(errors.CodeConfiguration)
+}
+
+// durations reads the duration parameters keys in order, with their defaults
+func (p Params) durations(keys []string, defaults ...time.Duration) ([]time.Duration, error) {
+	values := make([]time.Duration, len(keys))
+	for i, key := range keys {
+		d, err := p.Duration(key, defaults[i])
+		if err != nil {
+			return nil, err
+		}
+
+		values[i] = d
+	}
+
+	return values, nil
+}
+
+func fixedFromParams(params Params) (BackoffStrategy, error) {
+	if err := params.only("delay"); err != nil {
+		return nil, err
+	}
+
+	delay, err := params.Duration("delay", time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFixedBackoff(delay), nil
+}
+
+func linearFromParams(params Params) (BackoffStrategy, error) {
+	keys := []string{"initial", "increment", "max"}
+	if err := params.only(keys...); err != nil {
+		return nil, err
+	}
+
+	d, err := params.durations(keys, 100*time.Millisecond, 100*time.Millisecond, 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLinearBackoff(d[0], d[1], d[2]), nil
+}
+
+func exponentialFromParams(params Params) (BackoffStrategy, error) {
+	if err := params.only("initial", "max", "multiplier", "jitter"); err != nil {
+		return nil, err
+	}
+
+	b := NewExponentialBackoff()
+	d, err := params.durations([]string{"initial", "max"}, b.InitialDelay, b.MaxDelay)
+	if err != nil {
+		return nil, err
+	}
+
+	multiplier, err := params.Float("multiplier", b.Multiplier)
+	if err != nil {
+		return nil, err
+	}
+
+	jitter, err := params.Float("jitter", b.Jitter)
+	if err != nil {
+		return nil, err
+	}
+
+	if multiplier < 1 {
+		return nil, errors.New("multiplier must be at least 1").WithCode(errors.CodeConfiguration)
+	}
+
+	if jitter < 0 || jitter > 1 {
+		return nil, errors.New("jitter must be between 0 and 1").WithCode(errors.CodeConfiguration)
+	}
+
+	return NewExponentialBackoff(
+		WithInitialDelay(d[0]),
+		func(b *ExponentialBackoff) { b.MaxDelay = d[1] },
+		WithMultiplier(multiplier),
+		WithJitter(jitter),
+	), nil
+}
+
+func fibonacciFromParams(params Params) (BackoffStrategy, error) {
+	keys := []string{"base", "max"}
+	if err := params.only(keys...); err != nil {
+		return nil, err
+	}
+
+	d, err := params.durations(keys, 100*time.Millisecond, 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFibonacciBackoff(d[0], d[1]), nil
+}
+
+func polynomialFromParams(params Params) (BackoffStrategy, error) {
+	if err := params.only("base", "exponent", "max"); err != nil {
+		return nil, err
+	}
+
+	d, err := params.durations([]string{"base", "max"}, 100*time.Millisecond, 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	exponent, err := params.Float("exponent", 2)
+	if err != nil {
+		return nil, err
+	}
+
+	if exponent <= 0 {
+		return nil, errors.New("exponent must be positive").WithCode(errors.CodeConfiguration)
+	}
+
+	return NewPolynomialBackoff(d[0], exponent, d[1]), nil
+}
+
+func decorrelatedFromParams(params Params) (BackoffStrategy, error) {
+	keys := []string{"base", "cap"}
+	if err := params.only(keys...); err != nil {
+		return nil, err
+	}
+
+	d, err := params.durations(keys, 100*time.Millisecond, 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDecorrelatedJitterBackoff(d[0], d[1]), nil
+}
+
+func fullJitterFromParams(params Params) (BackoffStrategy, error) {
+	keys := []string{"base", "cap"}
+	if err := params.only(keys...); err != nil {
+		return nil, err
+	}
+
+	d, err := params.durations(keys, 100*time.Millisecond, 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFullJitterBackoff(d[0], d[1]), nil
+}
@@ -0,0 +1,148 @@
+package retry
+
+import (
+	"task-queue/pkg/errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// firstTen returns the delays of attempts one to ten
+func firstTen(b BackoffStrategy) []time.Duration {
+	delays := make([]time.Duration, 10)
+	for i := range delays {
+		delays[i] = b.Next(i + 1)
+	}
+
+	return delays
+}
+
+// ms converts whole milliseconds to durations
+func ms(values ...int) []time.Duration {
+	delays := make([]time.Duration, len(values))
+	for i, v := range values {
+		delays[i] = time.Duration(v) * time.Millisecond
+	}
+
+	return delays
+}
+
+func TestFibonacciBackoff_Sequence(t *testing.T) {
+	b := NewFibonacciBackoff(10*time.Millisecond, 500*time.Millisecond)
+
+	assert.Equal(t, ms(10, 10, 20, 30, 50, 80, 130, 210, 340, 500), firstTen(b))
+	assert.Zero(t, b.Next(0))
+	assert.Equal(t, 500*time.Millisecond, b.Next(1000), "large attempts do not overflow")
+}
+
+func TestPolynomialBackoff_Sequence(t *testing.T) {
+	quadratic := NewPolynomialBackoff(10*time.Millisecond, 2, 800*time.Millisecond)
+	assert.Equal(t, ms(10, 40, 90, 160, 250, 360, 490, 640, 800, 800), firstTen(quadratic))
+
+	linear := NewPolynomialBackoff(10*time.Millisecond, 1, time.Second)
+	assert.Equal(t, ms(10, 20, 30, 40, 50, 60, 70, 80, 90, 100), firstTen(linear))
+	assert.Zero(t, linear.Next(0))
+}
+
+func TestStrategyFromConfig_Sequences(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		expected []time.Duration
+	}{
+		{"fixed", map[string]any{"delay": "25ms"},
+			ms(25, 25, 25, 25, 25, 25, 25, 25, 25, 25)},
+		{"linear", map[string]any{"initial": "10ms", "increment": "5ms", "max": "50ms"},
+			ms(10, 15, 20, 25, 30, 35, 40, 45, 50, 50)},
+		{"exponential", map[string]any{"initial": "1ms", "max": "300ms", "multiplier": 2, "jitter": 0},
+			ms(1, 2, 4, 8, 16, 32, 64, 128, 256, 300)},
+		{"fibonacci", map[string]any{"base": "1ms", "max": "40ms"},
+			ms(1, 1, 2, 3, 5, 8, 13, 21, 34, 40)},
+		{"polynomial", map[string]any{"base": "1ms", "exponent": "3", "max": "600ms"},
+			ms(1, 8, 27, 64, 125, 216, 343, 512, 600, 600)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := StrategyFromConfig(tt.name, tt.params)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, firstTen(b))
+		})
+	}
+}
+
+func TestStrategyFromConfig_Jittered(t *testing.T) {
+	decorrelated, err := StrategyFromConfig("decorrelated", map[string]any{"base": "10ms", "cap": "200ms"})
+	require.NoError(t, err)
+	for _, delay := range firstTen(decorrelated) {
+		assert.GreaterOrEqual(t, delay, 10*time.Millisecond)
+		assert.LessOrEqual(t, delay, 200*time.Millisecond)
+	}
+
+	full, err := StrategyFromConfig("FULL_JITTER", map[string]any{"base": "10ms", "cap": "200ms"})
+	require.NoError(t, err)
+	for i, delay := range firstTen(full) {
+		ceiling := min(200*time.Millisecond, 10*time.Millisecond<<i)
+		assert.Less(t, delay, ceiling)
+	}
+}
+
+func TestStrategyFromConfig_Defaults(t *testing.T) {
+	b, err := StrategyFromConfig("exponential", nil)
+	require.NoError(t, err)
+	assert.Equal(t, NewExponentialBackoff(), b)
+
+	b, err = StrategyFromConfig("fixed", map[string]any{"delay": 2 * time.Second})
+	require.NoError(t, err)
+	assert.Equal(t, NewFixedBackoff(2*time.Second), b)
+}
+
+func TestStrategyFromConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		params   map[string]any
+		wantErr  string
+	}{
+		{"unknown strategy", "golden", nil, `unknown backoff strategy "golden"`},
+		{"unknown param", "fibonacci", map[string]any{"bsae": "1s"}, "unknown parameters bsae"},
+		{"duration as number", "fixed", map[string]any{"delay": 100}, "delay must be a duration"},
+		{"bad duration", "fixed", map[string]any{"delay": "soon"}, "delay must be a duration"},
+		{"negative duration", "fixed", map[string]any{"delay": "-1s"}, "delay must not be negative"},
+		{"bad number", "polynomial", map[string]any{"exponent": "two"}, "exponent must be a number"},
+		{"zero exponent", "polynomial", map[string]any{"exponent": 0}, "exponent must be positive"},
+		{"shrinking multiplier", "exponential", map[string]any{"multiplier": 0.5}, "multiplier must be at least 1"},
+		{"jitter out of range", "exponential", map[string]any{"jitter": 1.5}, "jitter must be between 0 and 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := StrategyFromConfig(tt.strategy, tt.params)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Equal(t, errors.CodeConfiguration, errors.GetCode(err))
+		})
+	}
+}
+
+func TestRegisterStrategy(t *testing.T) {
+	RegisterStrategy("Immediate", func(params Params) (BackoffStrategy, error) {
+		return NewFixedBackoff(0), params.only()
+	})
+	t.Cleanup(func() {
+		strategiesMu.Lock()
+		delete(strategies, "immediate")
+		strategiesMu.Unlock()
+	})
+
+	assert.Contains(t, Strategies(), "immediate")
+
+	b, err := StrategyFromConfig("immediate", nil)
+	require.NoError(t, err)
+	assert.Zero(t, b.Next(3))
+
+	_, err = StrategyFromConfig("immediate", map[string]any{"delay": "1s"})
+	assert.Error(t, err)
+}
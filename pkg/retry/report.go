@@ -0,0 +1,85 @@
+package retry
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReportKey is the metadata key under which the error returned after the
+// last attempt carries the report summary
+const ReportKey = "retry"
+
+// RetryInfo describes a failed attempt that is about to be retried
+type RetryInfo struct {
+	// Attempt is the number of the attempt that failed, starting at 1
+	Attempt int
+
+	// MaxAttempts is the configured maximum number of attempts, or 0 when
+	// attempts are unlimited
+	MaxAttempts int
+
+	// Err is the error returned by the failed attempt
+	Err error
+
+	// NextDelay is how long the retry sleeps before the next attempt
+	NextDelay time.Duration
+
+	// Elapsed is the time spent since the first attempt started
+	Elapsed time.Duration
+}
+
+// Remaining returns the number of attempts left after this one, or -1 when
+// attempts are unlimited
+func (i RetryInfo) Remaining() int {
+	if i.MaxAttempts == 0 {
+		return -1
+	}
+
+	return i.MaxAttempts - i.Attempt
+}
+
+// Report records the outcome of a retried operation
+type Report struct {
+	// Attempts is the number of times the operation ran
+	Attempts int
+
+	// Elapsed is the total time spent, sleeps included
+	Elapsed time.Duration
+
+	// Errors holds the error of each failed attempt, in order
+	Errors []error
+
+	// Delays holds the sleep before each retry, in order
+	Delays []time.Duration
+}
+
+// Succeeded reports whether the last attempt succeeded
+func (r Report) Succeeded() bool {
+	return r.Attempts > 0 && len(r.Errors) < r.Attempts
+}
+
+// Summary returns the report as metadata suitable for logs and error
+// responses, with errors as messages and durations as strings
+func (r Report) Summary() map[string]any {
+	errs := make([]string, len(r.Errors))
+	for i, err := range r.Errors {
+		errs[i] = err.Error()
+	}
+
+	delays := make([]string, len(r.Delays))
+	for i, delay := range r.Delays {
+		delays[i] = delay.String()
+	}
+
+	return map[string]any{
+		"attempts": r.Attempts,
+		"elapsed":  r.Elapsed.String(),
+		"errors":   errs,
+		"delays":   delays,
+	}
+}
+
+// String returns a one-line description of the report
+func (r Report) String() string {
+	return fmt.Sprintf("%d attempts, %d failed, in %s", r.Attempts, len(r.Errors), r.Elapsed)
+}
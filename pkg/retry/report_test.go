@@ -0,0 +1,159 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"task-queue/pkg/errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingBackoff remembers every delay its strategy returns
+type recordingBackoff struct {
+	BackoffStrategy
+	delays []time.Duration
+}
+
+func (r *recordingBackoff) Next(attempt int) time.Duration {
+	delay := r.BackoffStrategy.Next(attempt)
+	r.delays = append(r.delays, delay)
+	return delay
+}
+
+func TestWithOnRetryInfo(t *testing.T) {
+	backoff := &recordingBackoff{BackoffStrategy: NewFullJitterBackoff(time.Millisecond, 10*time.Millisecond)}
+
+	var infos []RetryInfo
+	calls := 0
+	err := Do(func() error {
+		calls++
+		return fmt.Errorf("attempt %d", calls)
+	},
+		WithMaxAttempts(4),
+		WithBackoffStrategy(backoff),
+		WithOnRetryInfo(func(info RetryInfo) { infos = append(infos, info) }),
+	)
+
+	require.Error(t, err)
+	require.Len(t, infos, 3, "no callback after the last attempt")
+	require.Len(t, backoff.delays, 3)
+
+	var lastElapsed time.Duration
+	for i, info := range infos {
+		assert.Equal(t, i+1, info.Attempt)
+		assert.Equal(t, 4, info.MaxAttempts)
+		assert.Equal(t, 3-i, info.Remaining())
+		assert.EqualError(t, info.Err, fmt.Sprintf("attempt %d", i+1))
+		assert.Equal(t, backoff.delays[i], info.NextDelay)
+		assert.GreaterOrEqual(t, info.Elapsed, lastElapsed)
+		lastElapsed = info.Elapsed
+	}
+}
+
+func TestWithOnRetryInfo_ReflectsHintAndCap(t *testing.T) {
+	var delays []time.Duration
+	calls := 0
+	err := Do(func() error {
+		calls++
+		if calls == 1 {
+			return errors.RateLimited("slow down").WithRetryAfter(5 * time.Millisecond)
+		}
+
+		if calls == 2 {
+			return errors.RateLimited("slow down").WithRetryAfter(time.Hour)
+		}
+
+		return nil
+	},
+		WithBackoffStrategy(NewFixedBackoff(time.Second)),
+		WithMaxDelay(10*time.Millisecond),
+		WithOnRetryInfo(func(info RetryInfo) { delays = append(delays, info.NextDelay) }),
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{5 * time.Millisecond, 10 * time.Millisecond}, delays)
+}
+
+func TestWithOnRetry_StillCalledAlongsideInfo(t *testing.T) {
+	var order []string
+	_ = Do(func() error { return errors.New("boom") },
+		WithMaxAttempts(2),
+		WithBackoffStrategy(NewFixedBackoff(time.Millisecond)),
+		WithOnRetry(func(attempt int, err error) { order = append(order, "retry") }),
+		WithOnRetryInfo(func(RetryInfo) { order = append(order, "info") }),
+	)
+
+	assert.Equal(t, []string{"retry", "info"}, order)
+}
+
+func TestDoWithReport_Success(t *testing.T) {
+	calls := 0
+	report, err := DoWithReport(func() error {
+		calls++
+		if calls < 3 {
+			return fmt.Errorf("attempt %d", calls)
+		}
+
+		return nil
+	}, WithBackoffStrategy(NewLinearBackoff(time.Millisecond, time.Millisecond, time.Second)))
+
+	require.NoError(t, err)
+	assert.True(t, report.Succeeded())
+	assert.Equal(t, 3, report.Attempts)
+	require.Len(t, report.Errors, 2)
+	assert.EqualError(t, report.Errors[0], "attempt 1")
+	assert.EqualError(t, report.Errors[1], "attempt 2")
+	assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond}, report.Delays)
+	assert.GreaterOrEqual(t, report.Elapsed, 3*time.Millisecond)
+	assert.Equal(t, "3 attempts, 2 failed, in "+report.Elapsed.String(), report.String())
+}
+
+func TestDoWithReport_Exhausted(t *testing.T) {
+	calls := 0
+	report, err := DoWithReport(func() error {
+		calls++
+		return fmt.Errorf("attempt %d", calls)
+	}, WithMaxAttempts(3), WithBackoffStrategy(NewFixedBackoff(time.Millisecond)))
+
+	require.Error(t, err)
+	assert.False(t, report.Succeeded())
+	assert.Equal(t, 3, report.Attempts)
+	require.Len(t, report.Errors, 3)
+	assert.EqualError(t, report.Errors[2], "attempt 3")
+	assert.Len(t, report.Delays, 2)
+
+	var retryErr *errors.Error
+	require.ErrorAs(t, err, &retryErr)
+	summary, ok := retryErr.Metadata[ReportKey].(map[string]any)
+	require.True(t, ok, "the final error carries the report summary")
+	assert.Equal(t, 3, summary["attempts"])
+	assert.Equal(t, []string{"attempt 1", "attempt 2", "attempt 3"}, summary["errors"])
+	assert.Equal(t, []string{"1ms", "1ms"}, summary["delays"])
+	assert.Equal(t, report.Elapsed.String(), summary["elapsed"])
+}
+
+func TestDoWithReport_PermanentError(t *testing.T) {
+	report, err := DoWithReport(func() error {
+		return errors.Validation("bad input")
+	})
+
+	require.Error(t, err)
+	assert.True(t, errors.IsValidation(err))
+	assert.Equal(t, 1, report.Attempts)
+	assert.Len(t, report.Errors, 1)
+	assert.Empty(t, report.Delays)
+}
+
+func TestDoWithReport_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	report, err := DoWithReport(func() error { return nil }, WithContext(ctx))
+
+	require.Error(t, err)
+	assert.Zero(t, report.Attempts)
+	assert.False(t, report.Succeeded())
+}
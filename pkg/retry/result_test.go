@@ -0,0 +1,122 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"task-queue/pkg/errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoWithResult_SuccessOnFirstAttempt(t *testing.T) {
+	calls := 0
+	result, err := DoWithResult(func() (int, error) {
+		calls++
+		return 42, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, result)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDoWithResult_RetryUntilSuccess(t *testing.T) {
+	calls := 0
+	result, err := DoWithResult(func() (string, error) {
+		calls++
+		if calls < 3 {
+			return "partial", errors.New("temporary error")
+		}
+
+		return "done", nil
+	}, WithMaxAttempts(3), WithBackoffStrategy(NewFixedBackoff(time.Millisecond)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "done", result)
+	assert.Equal(t, 3, calls)
+}
+
+func TestDoWithResult_MaxAttemptsExceeded(t *testing.T) {
+	calls := 0
+	result, err := DoWithResult(func() (int, error) {
+		calls++
+		return calls, errors.New("persistent error")
+	}, WithMaxAttempts(3), WithBackoffStrategy(NewFixedBackoff(time.Millisecond)))
+
+	assert.Error(t, err)
+	assert.Zero(t, result, "the zero value is returned on exhaustion")
+	assert.Equal(t, 3, calls)
+	assert.Contains(t, err.Error(), "operation failed after 3 attempts")
+}
+
+func TestDoWithResult_StopsOnNonRetryableError(t *testing.T) {
+	calls := 0
+	result, err := DoWithResult(func() (int, error) {
+		calls++
+		return 7, errors.Validation("bad input")
+	}, WithMaxAttempts(3))
+
+	assert.Error(t, err)
+	assert.Zero(t, result)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDoWithResult_Struct(t *testing.T) {
+	type lease struct {
+		ID    string
+		Until time.Time
+	}
+
+	until := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	calls := 0
+	result, err := DoWithResult(func() (lease, error) {
+		calls++
+		if calls == 1 {
+			return lease{}, errors.New("unavailable").WithCode(errors.CodeNetwork)
+		}
+
+		return lease{ID: "worker-1", Until: until}, nil
+	}, WithBackoffStrategy(NewFixedBackoff(time.Millisecond)))
+
+	require.NoError(t, err)
+	assert.Equal(t, lease{ID: "worker-1", Until: until}, result)
+}
+
+func TestDoWithResult_Interface(t *testing.T) {
+	result, err := DoWithResult(func() (fmt.Stringer, error) {
+		return time.Second, nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "1s", result.String())
+
+	result, err = DoWithResult(func() (fmt.Stringer, error) {
+		return time.Second, errors.Validation("rejected")
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, result, "a nil interface is returned on failure")
+}
+
+func TestDoWithResultContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	result, err := DoWithResultContext(ctx, func(context.Context) (int, error) {
+		return 1, errors.New("unavailable").WithCode(errors.CodeNetwork)
+	}, WithMaxAttempts(10), WithBackoffStrategy(NewFixedBackoff(time.Second)))
+
+	assert.Zero(t, result)
+	assert.Equal(t, errors.CodeTimeout, errors.GetCode(err))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	result, err = DoWithResultContext(context.Background(), func(ctx context.Context) (int, error) {
+		return 5, ctx.Err()
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, result)
+}
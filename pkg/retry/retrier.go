@@ -0,0 +1,198 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Cloner is implemented by stateful backoff strategies. A Retrier clones
+// such a strategy for every call, so concurrent calls never share a
+// sequence. Stateful strategies that do not implement Cloner are shared.
+type Cloner interface {
+	Clone() BackoffStrategy
+}
+
+// Retrier applies one retry policy to many operations. Its configuration is
+// validated and frozen by NewRetrier, so it is safe for concurrent use and
+// call sites cannot drift from the policy it encodes.
+type Retrier struct {
+	config Config
+	opts   []Option
+}
+
+// NewRetrier builds a Retrier from opts applied to DefaultConfig. Options
+// that set a context are ignored; pass the context to DoContext instead.
+// When no WithMetrics is given, each call reports to the Metrics set with
+// SetDefaultMetrics at the time of the call. NewRetrier panics on an
+// invalid configuration, which is a programming error.
+func NewRetrier(opts ...Option) *Retrier {
+	config := DefaultConfig()
+	config.Metrics = nil
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if err := config.validate(); err != nil {
+		panic(fmt.Sprintf("retry: invalid retrier: %v", err))
+	}
+
+	config.Context = nil
+	config.RetryOnCodes = append(config.RetryOnCodes[:0:0], config.RetryOnCodes...)
+	config.AbortOnCodes = append(config.AbortOnCodes[:0:0], config.AbortOnCodes...)
+
+	return &Retrier{
+		config: *config,
+		opts:   append(opts[:0:0], opts...),
+	}
+}
+
+// With returns a new Retrier with opts applied on top of this one's
+func (r *Retrier) With(opts ...Option) *Retrier {
+	return NewRetrier(append(append(r.opts[:0:0], r.opts...), opts...)...)
+}
+
+// Do executes the operation with the retrier's policy
+func (r *Retrier) Do(operation Operation) error {
+	_, _, err := doWithResult(func() (struct{}, error) {
+		return struct{}{}, operation()
+	}, r.callConfig(context.Background()))
+
+	return err
+}
+
+// DoContext executes a context-aware operation with the retrier's policy,
+// stopping when ctx is done
+func (r *Retrier) DoContext(ctx context.Context, operation ContextOperation) error {
+	_, _, err := doWithResult(func() (struct{}, error) {
+		return struct{}{}, operation(ctx)
+	}, r.callConfig(ctx))
+
+	return err
+}
+
+// DoWithReport executes the operation like Do and also returns a Report of
+// every attempt
+func (r *Retrier) DoWithReport(operation Operation) (Report, error) {
+	_, report, err := doWithResult(func() (struct{}, error) {
+		return struct{}{}, operation()
+	}, r.callConfig(context.Background()))
+
+	return report, err
+}
+
+// Result executes an operation that returns a value with r's policy. Go
+// methods cannot take type parameters, so it is a function.
+func Result[T any](r *Retrier, operation func() (T, error)) (T, error) {
+	result, _, err := doWithResult(operation, r.callConfig(context.Background()))
+	return result, err
+}
+
+// ResultContext executes a context-aware operation that returns a value
+// with r's policy, stopping when ctx is done
+func ResultContext[T any](ctx context.Context, r *Retrier,
+	operation func(context.Context) (T, error)) (T, error) {
+	result, _, err := doWithResult(func() (T, error) {
+		return operation(ctx)
+	}, r.callConfig(ctx))
+
+	return result, err
+}
+
+// MaxAttempts returns the maximum number of attempts per call, or 0 when
+// attempts are unlimited
+func (r *Retrier) MaxAttempts() int {
+	return r.config.attemptLimit()
+}
+
+// callConfig returns a copy of the frozen configuration for one call, with
+// its own backoff sequence when the strategy is stateful
+func (r *Retrier) callConfig(ctx context.Context) *Config {
+	config := r.config
+	config.Context = ctx
+	if cloner, ok := config.BackoffStrategy.(Cloner); ok {
+		config.BackoffStrategy = cloner.Clone()
+	}
+
+	if config.Metrics == nil {
+		config.Metrics = loadDefaultMetrics()
+	}
+
+	return &config
+}
+
+// validate reports configuration values the retry loop cannot run with
+func (c *Config) validate() error {
+	switch {
+	case c.MaxAttempts <= 0 && !c.InfiniteAttempts:
+		return fmt.Errorf("max attempts must be greater than 0")
+
+	case c.MaxDelay < 0:
+		return fmt.Errorf("max delay must not be negative")
+
+	case c.MaxElapsedTime < 0:
+		return fmt.Errorf("max elapsed time must not be negative")
+
+	case c.BackoffStrategy == nil:
+		return fmt.Errorf("a backoff strategy is required")
+
+	case c.RetryIf == nil:
+		return fmt.Errorf("a retry predicate is required")
+
+	default:
+		return nil
+	}
+}
+
+// Preconfigured retriers, built on first use
+var (
+	databaseRetrier = sync.OnceValue(func() *Retrier {
+		return NewRetrier(
+			WithName("database"),
+			WithMaxAttempts(4),
+			WithMaxDelay(2*time.Second),
+			WithBackoffStrategy(NewExponentialBackoff(
+				WithInitialDelay(50*time.Millisecond),
+				WithJitter(0.2),
+			)),
+		)
+	})
+
+	networkRetrier = sync.OnceValue(func() *Retrier {
+		return NewRetrier(
+			WithName("network"),
+			WithMaxAttempts(5),
+			WithMaxDelay(10*time.Second),
+			WithBackoffStrategy(NewDecorrelatedJitterBackoff(100*time.Millisecond, 10*time.Second)),
+		)
+	})
+
+	quickRetrier = sync.OnceValue(func() *Retrier {
+		return NewRetrier(
+			WithName("quick"),
+			WithMaxAttempts(3),
+			WithMaxDelay(100*time.Millisecond),
+			WithBackoffStrategy(NewFullJitterBackoff(10*time.Millisecond, 100*time.Millisecond)),
+		)
+	})
+)
+
+// Database returns the shared retrier for database calls: four attempts
+// with exponential backoff from 50ms, capped at 2s
+func Database() *Retrier {
+	return databaseRetrier()
+}
+
+// Network returns the shared retrier for calls to remote services such as
+// Redis or brokers: five attempts with decorrelated jitter from 100ms,
+// capped at 10s
+func Network() *Retrier {
+	return networkRetrier()
+}
+
+// Quick returns the shared retrier for cheap, latency-sensitive calls:
+// three attempts with full jitter from 10ms, capped at 100ms
+func Quick() *Retrier {
+	return quickRetrier()
+}
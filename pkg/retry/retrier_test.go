@@ -0,0 +1,161 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"task-queue/pkg/errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// steppingBackoff is a stateful strategy whose delays count up by one
+// nanosecond on every Next, whatever the attempt number
+type steppingBackoff struct {
+	mu   sync.Mutex
+	step time.Duration
+}
+
+func (s *steppingBackoff) Next(int) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.step++
+	return s.step
+}
+
+func (s *steppingBackoff) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.step = 0
+}
+
+func (s *steppingBackoff) Clone() BackoffStrategy {
+	return &steppingBackoff{}
+}
+
+func TestRetrier_ConcurrentCallsKeepTheirOwnSequence(t *testing.T) {
+	r := NewRetrier(WithMaxAttempts(4), WithBackoffStrategy(&steppingBackoff{}))
+
+	const calls = 50
+	reports := make([]Report, calls)
+
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reports[i], _ = r.DoWithReport(func() error { return errors.New("down") })
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i, report := range reports {
+		assert.Equal(t, []time.Duration{1, 2, 3}, report.Delays, "call %d", i)
+	}
+}
+
+func TestRetrier_DecorrelatedJitterIsolation(t *testing.T) {
+	base := time.Millisecond
+	r := NewRetrier(
+		WithMaxAttempts(2),
+		WithBackoffStrategy(NewDecorrelatedJitterBackoff(base, time.Second)),
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report, _ := r.DoWithReport(func() error { return errors.New("down") })
+			require.Len(t, report.Delays, 1)
+			assert.LessOrEqual(t, report.Delays[0], 3*base,
+				"the first delay is never grown by other calls")
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestRetrier_Variants(t *testing.T) {
+	r := NewRetrier(WithMaxAttempts(3), WithBackoffStrategy(NewFixedBackoff(time.Millisecond)))
+
+	calls := 0
+	assert.NoError(t, r.Do(func() error {
+		calls++
+		if calls < 2 {
+			return errors.New("temporary")
+		}
+
+		return nil
+	}))
+	assert.Equal(t, 2, calls)
+
+	value, err := Result(r, func() (int, error) { return 42, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 42, value)
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	got, err := ResultContext(ctx, r, func(ctx context.Context) (string, error) {
+		return ctx.Value(ctxKey{}).(string), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "request", got)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = r.DoContext(cancelled, func(context.Context) error { return nil })
+	assert.True(t, errors.HasCode(err, errors.CodeCanceled))
+}
+
+func TestRetrier_Frozen(t *testing.T) {
+	codes := []errors.Code{errors.CodeNetwork}
+	r := NewRetrier(
+		WithMaxAttempts(2),
+		WithBackoffStrategy(NewFixedBackoff(time.Millisecond)),
+		WithRetryOnCodes(codes...),
+	)
+	codes[0] = errors.CodeValidation
+
+	calls := 0
+	_ = r.Do(func() error {
+		calls++
+		return errors.New("reset").WithCode(errors.CodeNetwork)
+	})
+	assert.Equal(t, 2, calls)
+
+	derived := r.With(WithMaxAttempts(5))
+	assert.Equal(t, 5, derived.MaxAttempts())
+	assert.Equal(t, 2, r.MaxAttempts(), "With leaves the original untouched")
+}
+
+func TestRetrier_ResolvesDefaultMetricsPerCall(t *testing.T) {
+	r := NewRetrier(WithName("late"))
+
+	m := newFakeMetrics()
+	SetDefaultMetrics(m)
+	t.Cleanup(func() { SetDefaultMetrics(nil) })
+
+	assert.NoError(t, r.Do(func() error { return nil }))
+	assert.Equal(t, 1, m.successes["late"])
+}
+
+func TestNewRetrier_InvalidConfig(t *testing.T) {
+	assert.PanicsWithValue(t, "retry: invalid retrier: max attempts must be greater than 0",
+		func() { NewRetrier(WithMaxAttempts(0)) })
+	assert.Panics(t, func() { NewRetrier(WithBackoffStrategy(nil)) })
+	assert.Panics(t, func() { NewRetrier(WithRetryIf(nil)) })
+}
+
+func TestPresetRetriers(t *testing.T) {
+	for _, r := range []*Retrier{Database(), Network(), Quick()} {
+		assert.Greater(t, r.MaxAttempts(), 1)
+	}
+
+	assert.Same(t, Database(), Database())
+}
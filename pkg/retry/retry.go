@@ -9,22 +9,15 @@ import (
 	"time"
 )
 
-// defaultRetryIf determines if an error should trigger a retry
+// defaultRetryIf determines if an error should trigger a retry. An explicit
+// retryable flag or the error code decides; unclassified errors are retried.
 func defaultRetryIf(err error) bool {
 	if err == nil {
 		return false
 	}
 
-	if errors.IsValidation(err) {
-		return false
-	}
-
-	if errors.IsNotFound(err) {
-		return false
-	}
-
-	if errors.IsPermission(err) || errors.IsAuthentication(err) {
-		return false
+	if retryable, known := errors.IsRetryable(err); known {
+		return retryable
 	}
 
 	return true
@@ -37,6 +30,24 @@ func WithMaxAttempts(attempts int) Option {
 	}
 }
 
+// WithMaxElapsedTime stops retrying once the time spent, sleeps included,
+// would reach d. A retry whose sleep would cross the bound is not attempted.
+func WithMaxElapsedTime(d time.Duration) Option {
+	return func(c *Config) {
+		c.MaxElapsedTime = d
+	}
+}
+
+// WithInfiniteAttempts ignores MaxAttempts, retrying until the operation
+// succeeds, fails permanently, the context is done, or MaxElapsedTime is
+// reached. Do returns an error without running the operation when neither
+// a cancellable context nor a max elapsed time bounds the call.
+func WithInfiniteAttempts() Option {
+	return func(c *Config) {
+		c.InfiniteAttempts = true
+	}
+}
+
 // WithMaxDelay sets the maximum delay between retries
 func WithMaxDelay(delay time.Duration) Option {
 	return func(c *Config) {
@@ -58,6 +69,38 @@ func WithRetryIf(fn func(error) bool) Option {
 	}
 }
 
+// WithRetryIfAny retries when any of preds reports true for the error,
+// replacing the default predicate
+func WithRetryIfAny(preds ...func(error) bool) Option {
+	return func(c *Config) {
+		c.RetryIf = func(err error) bool {
+			for _, pred := range preds {
+				if pred(err) {
+					return true
+				}
+			}
+
+			return false
+		}
+	}
+}
+
+// WithRetryOnCodes retries only errors with one of codes anywhere in their
+// chain, overriding RetryIf. Codes in AbortOnCodes still stop.
+func WithRetryOnCodes(codes ...errors.Code) Option {
+	return func(c *Config) {
+		c.RetryOnCodes = append(c.RetryOnCodes, codes...)
+	}
+}
+
+// WithAbortOnCodes never retries errors with one of codes anywhere in their
+// chain, whatever RetryOnCodes or RetryIf say
+func WithAbortOnCodes(codes ...errors.Code) Option {
+	return func(c *Config) {
+		c.AbortOnCodes = append(c.AbortOnCodes, codes...)
+	}
+}
+
 // WithOnRetry sets a callback function called on each retry
 func WithOnRetry(fn func(int, error)) Option {
 	return func(c *Config) {
@@ -65,6 +108,15 @@ func WithOnRetry(fn func(int, error)) Option {
 	}
 }
 
+// WithOnRetryInfo sets a callback called before each retry sleep with the
+// attempt, the delay about to be slept, and the time spent so far. It runs
+// after any OnRetry callback.
+func WithOnRetryInfo(fn func(RetryInfo)) Option {
+	return func(c *Config) {
+		c.OnRetryInfo = fn
+	}
+}
+
 // WithContext sets the context for the retry operation
 func WithContext(ctx context.Context) Option {
 	return func(c *Config) {
@@ -72,7 +124,20 @@ func WithContext(ctx context.Context) Option {
 	}
 }
 
-// Do executes the operation with retry logic
+// WithBudget shares a retry budget with other operations. Each call records
+// its first attempt, and every retry must be granted by the budget; when one
+// is denied the call stops with a *BudgetExhaustedError.
+func WithBudget(budget *Budget) Option {
+	return func(c *Config) {
+		c.Budget = budget
+	}
+}
+
+// Do executes the operation with retry logic. When a failed attempt's error
+// carries a retry hint, set with errors.WithRetryAfter or parsed from a
+// Retry-After header by errors.FromHTTPResponse, the next sleep uses the hint
+// instead of the backoff strategy, still capped by MaxDelay and cut short by
+// the context.
 func Do(operation Operation, opts ...Option) error {
 	config := DefaultConfig()
 	for _, opt := range opts {
@@ -84,71 +149,240 @@ func Do(operation Operation, opts ...Option) error {
 
 // DoWithConfig executes the operation with the given configuration
 func DoWithConfig(operation Operation, config *Config) error {
-	if config.MaxAttempts <= 0 {
-		return fmt.Errorf("max attempts must be greater than 0")
+	_, _, err := doWithResult(func() (struct{}, error) {
+		return struct{}{}, operation()
+	}, config)
+
+	return err
+}
+
+// DoWithReport executes the operation like Do and also returns a Report of
+// every attempt, whether or not the operation eventually succeeded
+func DoWithReport(operation Operation, opts ...Option) (Report, error) {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	_, report, err := doWithResult(func() (struct{}, error) {
+		return struct{}{}, operation()
+	}, config)
+
+	return report, err
+}
+
+// DoWithContext executes a context-aware operation with retry logic
+func DoWithContext(ctx context.Context, operation ContextOperation,
+	opts ...Option) error {
+	op := func() error {
+		return operation(ctx)
+	}
+
+	opts = append(opts, WithContext(ctx))
+	return Do(op, opts...)
+}
+
+// DoWithResult executes an operation that returns a value with retry logic.
+// It returns the value of the first successful attempt, or the zero value
+// with the same error Do would return.
+func DoWithResult[T any](operation func() (T, error), opts ...Option) (T, error) {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	result, _, err := doWithResult(operation, config)
+	return result, err
+}
+
+// DoWithResultContext executes a context-aware operation that returns a
+// value with retry logic
+func DoWithResultContext[T any](ctx context.Context,
+	operation func(context.Context) (T, error), opts ...Option) (T, error) {
+	op := func() (T, error) {
+		return operation(ctx)
+	}
+
+	opts = append(opts, WithContext(ctx))
+	return DoWithResult(op, opts...)
+}
+
+// doWithResult runs the retry loop shared by every Do variant, recording
+// each attempt in the returned report
+func doWithResult[T any](operation func() (T, error), config *Config) (T, Report, error) {
+	var (
+		zero   T
+		report Report
+	)
+
+	if err := config.checkBounds(); err != nil {
+		return zero, report, err
+	}
+
+	if config.Budget != nil {
+		config.Budget.RecordAttempt()
+	}
+
+	if config.BackoffStrategy != nil {
+		config.BackoffStrategy.Reset()
+	}
+
+	metrics := config.Metrics
+	if metrics == nil {
+		metrics = noopMetrics{}
+	}
+
+	start := time.Now()
+	finish := func() Report {
+		report.Elapsed = time.Since(start)
+		return report
 	}
 
 	var lastErr error
-	for attempt := 1; attempt <= config.MaxAttempts; attempt++ {
+	bound := BoundMaxAttempts
+	for attempt := 1; config.InfiniteAttempts || attempt <= config.MaxAttempts; attempt++ {
 		select {
 		case <-config.Context.Done():
-			return errors.Wrap(config.Context.Err(), "retry cancelled").
-				WithCode(errors.CodeCanceled)
+			return zero, finish(), errors.Wrap(errors.FromContext(config.Context.Err()),
+				"retry cancelled")
 
 		default:
 		}
 
-		err := operation()
+		report.Attempts = attempt
+		result, err := operation()
+		metrics.IncAttempt(config.Name)
 		if err == nil {
-			return nil
+			metrics.IncSuccess(config.Name)
+			return result, finish(), nil
 		}
 
+		report.Errors = append(report.Errors, err)
 		lastErr = err
-		if !config.RetryIf(err) {
-			return err
+		if !config.shouldRetry(err) {
+			return zero, finish(), err
 		}
 
-		if attempt >= config.MaxAttempts {
+		if !config.InfiniteAttempts && attempt >= config.MaxAttempts {
 			break
 		}
 
-		if config.OnRetry != nil {
-			config.OnRetry(attempt, err)
+		delay := config.BackoffStrategy.Next(attempt)
+		if hint, ok := errors.RetryAfter(err); ok {
+			delay = hint
 		}
 
-		delay := config.BackoffStrategy.Next(attempt)
 		if delay > config.MaxDelay {
 			delay = config.MaxDelay
 		}
 
+		if config.MaxElapsedTime > 0 && time.Since(start)+delay >= config.MaxElapsedTime {
+			bound = BoundMaxElapsedTime
+			break
+		}
+
+		if config.Budget != nil && !config.Budget.AllowRetry() {
+			metrics.IncExhausted(config.Name)
+			return zero, finish(), &BudgetExhaustedError{Attempts: attempt, Err: err}
+		}
+
+		if config.OnRetry != nil {
+			config.OnRetry(attempt, err)
+		}
+
+		if config.OnRetryInfo != nil {
+			config.OnRetryInfo(RetryInfo{
+				Attempt:     attempt,
+				MaxAttempts: config.attemptLimit(),
+				Err:         err,
+				NextDelay:   delay,
+				Elapsed:     time.Since(start),
+			})
+		}
+
+		report.Delays = append(report.Delays, delay)
+		metrics.ObserveDelay(config.Name, delay)
 		timer := time.NewTimer(delay)
 		select {
 		case <-timer.C:
 		case <-config.Context.Done():
 			timer.Stop()
 
-			return errors.Wrap(
-				config.Context.Err(),
+			return zero, finish(), errors.Wrap(
+				errors.FromContext(config.Context.Err()),
 				"retry cancelled during backoff",
-			).WithCode(errors.CodeCanceled)
+			)
 		}
 	}
 
-	return errors.Wrapf(lastErr,
-		"operation failed after %d attempts", config.MaxAttempts,
-	).WithCode(errors.CodeInternal).
-		WithMetadata("attempts", config.MaxAttempts)
+	metrics.IncExhausted(config.Name)
+	finish()
+
+	var exhausted *errors.Error
+	if bound == BoundMaxElapsedTime {
+		exhausted = errors.Wrapf(lastErr,
+			"operation failed after %d attempts in %s: max elapsed time %s exceeded",
+			report.Attempts, report.Elapsed.Round(time.Millisecond), config.MaxElapsedTime)
+	} else {
+		exhausted = errors.Wrapf(lastErr,
+			"operation failed after %d attempts: max attempts reached", report.Attempts)
+	}
+
+	return zero, report, exhausted.WithCode(errors.CodeInternal).
+		WithMetadata("bound", bound).
+		WithMetadata(ReportKey, report.Summary())
 }
 
-// DoWithContext executes a context-aware operation with retry logic
-func DoWithContext(ctx context.Context, operation ContextOperation,
-	opts ...Option) error {
-	op := func() error {
-		return operation(ctx)
+// checkBounds reports a configuration that would retry forever or not at
+// all
+func (c *Config) checkBounds() error {
+	if !c.InfiniteAttempts {
+		if c.MaxAttempts <= 0 {
+			return fmt.Errorf("max attempts must be greater than 0")
+		}
+
+		return nil
 	}
 
-	opts = append(opts, WithContext(ctx))
-	return Do(op, opts...)
+	if c.MaxElapsedTime <= 0 && (c.Context == nil || c.Context.Done() == nil) {
+		return fmt.Errorf("infinite attempts require a max elapsed time or a cancellable context")
+	}
+
+	return nil
+}
+
+// attemptLimit returns MaxAttempts, or 0 when attempts are unlimited
+func (c *Config) attemptLimit() int {
+	if c.InfiniteAttempts {
+		return 0
+	}
+
+	return c.MaxAttempts
+}
+
+// shouldRetry applies the abort codes, the retry codes, and RetryIf in
+// order of precedence
+func (c *Config) shouldRetry(err error) bool {
+	if hasAnyCode(err, c.AbortOnCodes) {
+		return false
+	}
+
+	if len(c.RetryOnCodes) > 0 {
+		return hasAnyCode(err, c.RetryOnCodes)
+	}
+
+	return c.RetryIf(err)
+}
+
+// hasAnyCode reports whether err's chain carries any of codes
+func hasAnyCode(err error, codes []errors.Code) bool {
+	for _, code := range codes {
+		if errors.HasCode(err, code) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // WithInitialDelay sets the initial delay
@@ -1,8 +1,12 @@
 package retry
 
 import (
+	"context"
+	stderrors "errors"
+	"fmt"
 	"task-queue/pkg/errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -44,3 +48,77 @@ func TestDo_MaxAttemptsExceeded(t *testing.T) {
 	assert.Equal(t, 3, calls)
 	assert.Contains(t, err.Error(), "operation failed after 3 attempts")
 }
+
+func TestDefaultRetryIf(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"plain error", stderrors.New("boom"), true},
+		{"unclassified code", errors.New("bug").WithCode(errors.CodeInternal), true},
+		{"retryable code", errors.New("reset").WithCode(errors.CodeNetwork), true},
+		{"non-retryable code", errors.New("bad").WithCode(errors.CodeValidation), false},
+		{
+			name:     "explicit retryable overrides code",
+			err:      errors.New("bad").WithCode(errors.CodeValidation).WithRetryable(true),
+			expected: true,
+		},
+		{
+			name:     "explicit non-retryable overrides unknown",
+			err:      errors.New("bug").WithRetryable(false),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, defaultRetryIf(tt.err))
+		})
+	}
+}
+
+func TestDo_StopsOnNonRetryableError(t *testing.T) {
+	calls := 0
+	err := Do(func() error {
+		calls++
+		return errors.New("quota exhausted").
+			WithCode(errors.CodeRateLimit).
+			WithRetryable(false)
+	}, WithMaxAttempts(3))
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDo_ContextErrorsAreTyped(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := DoWithContext(ctx, func(context.Context) error {
+		return errors.New("unavailable").WithCode(errors.CodeNetwork)
+	}, WithMaxAttempts(10), WithBackoffStrategy(NewFixedBackoff(time.Second)))
+
+	assert.Equal(t, errors.CodeTimeout, errors.GetCode(err))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	err = DoWithContext(ctx, func(context.Context) error { return nil })
+	assert.Equal(t, errors.CodeCanceled, errors.GetCode(err))
+}
+
+func TestDo_StopsOnWrappedValidationError(t *testing.T) {
+	calls := 0
+	err := Do(func() error {
+		calls++
+		invalid := errors.Validation("payload is not valid JSON")
+		return errors.Wrap(fmt.Errorf("decode: %w", invalid), "handler failed").
+			WithCode(errors.CodeInternal)
+	}, WithMaxAttempts(3), WithBackoffStrategy(NewFixedBackoff(time.Millisecond)))
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,50 @@
+package retry
+
+import (
+	"task-queue/pkg/errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// timedRetry runs one failing attempt returning err and one success, and
+// returns how long Do slept between them
+func timedRetry(t *testing.T, err error, opts ...Option) time.Duration {
+	t.Helper()
+
+	calls := 0
+	start := time.Now()
+	opts = append([]Option{WithBackoffStrategy(NewFixedBackoff(time.Second))}, opts...)
+	assert.NoError(t, Do(func() error {
+		calls++
+		if calls == 1 {
+			return err
+		}
+
+		return nil
+	}, opts...))
+
+	return time.Since(start)
+}
+
+func TestDo_RetryAfterDuration(t *testing.T) {
+	elapsed := timedRetry(t, errors.RateLimited("slow down").WithRetryAfter(30*time.Millisecond))
+
+	assert.GreaterOrEqual(t, elapsed, 30*time.Millisecond)
+	assert.Less(t, elapsed, 500*time.Millisecond, "the hint replaces the 1s backoff")
+}
+
+func TestDo_RetryAfterPastTime(t *testing.T) {
+	elapsed := timedRetry(t, errors.RateLimited("slow down").WithRetryAt(time.Now().Add(-time.Hour)))
+
+	assert.Less(t, elapsed, 500*time.Millisecond, "a past hint retries immediately")
+}
+
+func TestDo_RetryAfterCappedByMaxDelay(t *testing.T) {
+	elapsed := timedRetry(t, errors.RateLimited("slow down").WithRetryAfter(time.Hour),
+		WithMaxDelay(20*time.Millisecond))
+
+	assert.GreaterOrEqual(t, elapsed, 20*time.Millisecond)
+	assert.Less(t, elapsed, 500*time.Millisecond)
+}
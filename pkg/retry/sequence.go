@@ -0,0 +1,78 @@
+package retry
+
+import (
+	"math"
+	"time"
+)
+
+// FibonacciBackoff grows delays along the Fibonacci sequence: Base, Base,
+// 2*Base, 3*Base, 5*Base, and so on, capped at Max. It grows more gently
+// than doubling. It is stateless and safe for concurrent use.
+type FibonacciBackoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// NewFibonacciBackoff creates a Fibonacci backoff strategy
+func NewFibonacciBackoff(base, max time.Duration) *FibonacciBackoff {
+	return &FibonacciBackoff{Base: base, Max: max}
+}
+
+// Next returns Base times the attempt-th Fibonacci number, capped at Max
+func (f *FibonacciBackoff) Next(attempt int) time.Duration {
+	if attempt <= 0 {
+		return 0
+	}
+
+	prev, delay := time.Duration(0), f.Base
+	for i := 1; i < attempt; i++ {
+		prev, delay = delay, prev+delay
+		if delay >= f.Max || delay < prev {
+			return f.Max
+		}
+	}
+
+	if delay > f.Max {
+		return f.Max
+	}
+
+	return delay
+}
+
+// Reset resets the backoff strategy
+func (f *FibonacciBackoff) Reset() {
+	// No state to reset for Fibonacci backoff
+}
+
+// PolynomialBackoff grows delays as Base*attempt^Exponent, capped at Max. An
+// exponent of 1 is linear and 2 quadratic. It is stateless and safe for
+// concurrent use.
+type PolynomialBackoff struct {
+	Base     time.Duration
+	Exponent float64
+	Max      time.Duration
+}
+
+// NewPolynomialBackoff creates a polynomial backoff strategy
+func NewPolynomialBackoff(base time.Duration, exponent float64, max time.Duration) *PolynomialBackoff {
+	return &PolynomialBackoff{Base: base, Exponent: exponent, Max: max}
+}
+
+// Next returns Base*attempt^Exponent, capped at Max
+func (p *PolynomialBackoff) Next(attempt int) time.Duration {
+	if attempt <= 0 {
+		return 0
+	}
+
+	delay := float64(p.Base) * math.Pow(float64(attempt), p.Exponent)
+	if delay > float64(p.Max) {
+		return p.Max
+	}
+
+	return time.Duration(delay)
+}
+
+// Reset resets the backoff strategy
+func (p *PolynomialBackoff) Reset() {
+	// No state to reset for polynomial backoff
+}
@@ -2,6 +2,7 @@ package retry
 
 import (
 	"context"
+	"task-queue/pkg/errors"
 	"time"
 )
 
@@ -20,28 +21,48 @@ type BackoffStrategy interface {
 	Reset()
 }
 
-// Config holds retry configuration
+// Config holds retry configuration. Whether a failed attempt is retried is
+// decided in order: a code in AbortOnCodes stops, then a non-empty
+// RetryOnCodes retries only its codes, and otherwise RetryIf decides.
 type Config struct {
-	MaxAttempts     int
-	MaxDelay        time.Duration
-	BackoffStrategy BackoffStrategy
-	RetryIf         func(error) bool
-	OnRetry         func(attempt int, err error)
-	Context         context.Context
+	MaxAttempts      int
+	InfiniteAttempts bool
+	MaxElapsedTime   time.Duration
+	MaxDelay         time.Duration
+	BackoffStrategy  BackoffStrategy
+	RetryIf          func(error) bool
+	RetryOnCodes     []errors.Code
+	AbortOnCodes     []errors.Code
+	OnRetry          func(attempt int, err error)
+	OnRetryInfo      func(RetryInfo)
+	Context          context.Context
+	Budget           *Budget
+	Metrics          Metrics
+	Name             string
 }
 
+// Bounds reported in the "bound" metadata of the error returned when a call
+// gives up
+const (
+	BoundMaxAttempts    = "max_attempts"
+	BoundMaxElapsedTime = "max_elapsed_time"
+)
+
 // Option is a function that modifies retry configuration
 type Option func(*Config)
 
-// DefaultConfig returns default retry configuration
+// DefaultConfig returns default retry configuration. The backoff strategy is
+// ExponentialBackoff unless SetDefaultBackoff selects another, and outcomes
+// go to the Metrics set with SetDefaultMetrics, if any.
 func DefaultConfig() *Config {
 	return &Config{
 		MaxAttempts:     3,
 		MaxDelay:        30 * time.Second,
-		BackoffStrategy: NewExponentialBackoff(),
+		BackoffStrategy: newDefaultBackoff(),
 		RetryIf:         defaultRetryIf,
 		OnRetry:         nil,
 		Context:         context.Background(),
+		Metrics:         loadDefaultMetrics(),
 	}
 }
 
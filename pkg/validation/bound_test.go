@@ -0,0 +1,110 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinMax_EveryNumericType(t *testing.T) {
+	type port uint16
+
+	tests := []struct {
+		name  string
+		below any
+		at    any
+		above any
+	}{
+		{"int", int(9), int(10), int(11)},
+		{"int8", int8(9), int8(10), int8(11)},
+		{"int16", int16(9), int16(10), int16(11)},
+		{"int32", int32(9), int32(10), int32(11)},
+		{"int64", int64(9), int64(10), int64(11)},
+		{"uint", uint(9), uint(10), uint(11)},
+		{"uint8", uint8(9), uint8(10), uint8(11)},
+		{"uint16", uint16(9), uint16(10), uint16(11)},
+		{"uint32", uint32(9), uint32(10), uint32(11)},
+		{"uint64", uint64(9), uint64(10), uint64(11)},
+		{"uintptr", uintptr(9), uintptr(10), uintptr(11)},
+		{"float32", float32(9.5), float32(10), float32(10.5)},
+		{"float64", 9.5, 10.0, 10.5},
+		{"named uint16", port(9), port(10), port(11)},
+		{"duration", time.Duration(9), time.Duration(10), time.Duration(11)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, Min(10).Validate(tt.below))
+			assert.NoError(t, Min(10).Validate(tt.at))
+			assert.NoError(t, Min(10).Validate(tt.above))
+
+			assert.NoError(t, Max(10).Validate(tt.below))
+			assert.NoError(t, Max(10).Validate(tt.at))
+			assert.Error(t, Max(10).Validate(tt.above))
+
+			assert.NoError(t, Between(10, 10).Validate(tt.at))
+		})
+	}
+}
+
+func TestMinMax_Messages(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator Validator
+		value     any
+		want      string
+	}{
+		{"number", Min(18), 17, "must be at least 18"},
+		{"duration", Min(float64(time.Second)), 500 * time.Millisecond, "must be at least 1s"},
+		{"max duration", Max(float64(time.Minute)), time.Hour, "must be at most 1m0s"},
+		{"string", Min(5), "test", "must be at least 5 characters"},
+		{"bytes", Max(2), []byte("abc"), "must be at most 2 bytes"},
+		{"slice", Min(2), []int{1}, "must be at least 2 items"},
+		{"map", Max(0), map[string]int{"a": 1}, "must be at most 0 items"},
+		{"pointer", Min(10), func() *int { n := 3; return &n }(), "must be at least 10"},
+		{"unsupported", Min(1), struct{}{}, "cannot apply min validation to type struct {}"},
+		{"nil", Max(1), nil, "cannot apply max validation to a nil value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualError(t, tt.validator.Validate(tt.value), tt.want)
+		})
+	}
+}
+
+func TestLength(t *testing.T) {
+	validator := Length(2, 4)
+
+	tests := []struct {
+		name    string
+		value   any
+		wantErr string
+	}{
+		{"string in range", "abc", ""},
+		{"multibyte counts characters", "héll", ""},
+		{"string too short", "a", "must be between 2 and 4 characters"},
+		{"bytes too long", []byte("abcde"), "must be between 2 and 4 bytes"},
+		{"slice in range", []string{"a", "b"}, ""},
+		{"map too small", map[string]int{}, "must be between 2 and 4 items"},
+		{"number rejected", 3, "cannot apply length validation to type int"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate(tt.value)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+
+	failures := validationFailures(t, NewField("name", "a", Length(2, 4)))
+	require.Len(t, failures, 1)
+	assert.Equal(t, "length", failures[0].Tag)
+	assert.Equal(t, map[string]any{"min": 2, "max": 4, "unit": "characters"}, failures[0].Params)
+}
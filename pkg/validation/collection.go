@@ -0,0 +1,182 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Each returns a validator that applies validators to every element of a
+// slice or array. Each element stops at its first failing validator, and the
+// failures are reported per element with an index path such as
+// "recipients[2]".
+func Each(validators ...Validator) Validator {
+	return ValidatorFunc(func(value any) error {
+		collection := indirect(reflect.ValueOf(value))
+		if !collection.IsValid() ||
+			(collection.Kind() != reflect.Slice && collection.Kind() != reflect.Array) {
+			return fmt.Errorf("must be a slice or array, got %T", value)
+		}
+
+		var failures ValidationErrors
+		for i := 0; i < collection.Len(); i++ {
+			elem := interfaceOf(collection.Index(i))
+			failures = appendFailure(failures, indexPath("", i), elem,
+				firstFailure(elem, validators))
+		}
+
+		return failuresOrNil(failures)
+	})
+}
+
+// Keys returns a validator that applies v to every key of a map. Failures are
+// reported under the key's path, e.g. "labels[Bad Key]".
+func Keys(v Validator) Validator {
+	return mapValidator(func(key, _ reflect.Value) (any, error) {
+		k := key.Interface()
+		if err := v.Validate(k); err != nil {
+			return k, fmt.Errorf("key %w", err)
+		}
+
+		return k, nil
+	})
+}
+
+// Values returns a validator that applies v to every value of a map.
+// Failures are reported under the key's path, e.g. "labels[env]".
+func Values(v Validator) Validator {
+	return mapValidator(func(_, value reflect.Value) (any, error) {
+		val := interfaceOf(value)
+		return val, v.Validate(val)
+	})
+}
+
+// mapValidator visits map entries in key order, collecting check failures
+func mapValidator(check func(key, value reflect.Value) (any, error)) Validator {
+	return ValidatorFunc(func(value any) error {
+		m := indirect(reflect.ValueOf(value))
+		if !m.IsValid() || m.Kind() != reflect.Map {
+			return fmt.Errorf("must be a map, got %T", value)
+		}
+
+		keys := m.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+
+		var failures ValidationErrors
+		for _, key := range keys {
+			reported, err := check(key, m.MapIndex(key))
+			failures = appendFailure(failures, keyPath("", key.Interface()), reported, err)
+		}
+
+		return failuresOrNil(failures)
+	})
+}
+
+// MinItems returns a validator that checks a slice, array, or map has at
+// least n items
+func MinItems(n int) Validator {
+	return ValidatorFunc(func(value any) error {
+		count, err := itemCount(value)
+		if err != nil {
+			return err
+		}
+
+		if count < n {
+			return paramError("min_items", n, "must contain at least %d items", n)
+		}
+
+		return nil
+	})
+}
+
+// MaxItems returns a validator that checks a slice, array, or map has at
+// most n items
+func MaxItems(n int) Validator {
+	return ValidatorFunc(func(value any) error {
+		count, err := itemCount(value)
+		if err != nil {
+			return err
+		}
+
+		if count > n {
+			return paramError("max_items", n, "must contain at most %d items", n)
+		}
+
+		return nil
+	})
+}
+
+// UniqueItems validates that a slice or array has no duplicate elements.
+// Comparable elements are compared with ==, others with reflect.DeepEqual.
+var UniqueItems = ValidatorFunc(func(value any) error {
+	collection := indirect(reflect.ValueOf(value))
+	if !collection.IsValid() ||
+		(collection.Kind() != reflect.Slice && collection.Kind() != reflect.Array) {
+		return fmt.Errorf("must be a slice or array, got %T", value)
+	}
+
+	seen := make(map[any]int)
+	for i := 0; i < collection.Len(); i++ {
+		elem := interfaceOf(collection.Index(i))
+		if elem != nil && reflect.TypeOf(elem).Comparable() {
+			if first, ok := seen[elem]; ok {
+				return duplicateError(i, first)
+			}
+
+			seen[elem] = i
+			continue
+		}
+
+		for j := 0; j < i; j++ {
+			if reflect.DeepEqual(elem, interfaceOf(collection.Index(j))) {
+				return duplicateError(i, j)
+			}
+		}
+	}
+
+	return nil
+})
+
+// duplicateError reports that element i repeats the earlier element first
+func duplicateError(i, first int) error {
+	return &ruleError{
+		tag:     "unique",
+		params:  map[string]any{"index": i, "duplicate": first},
+		message: fmt.Sprintf("must contain unique items, [%d] duplicates [%d]", i, first),
+	}
+}
+
+// itemCount returns the length of a slice, array, or map
+func itemCount(value any) (int, error) {
+	collection := indirect(reflect.ValueOf(value))
+	if collection.IsValid() {
+		switch collection.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return collection.Len(), nil
+		}
+	}
+
+	return 0, fmt.Errorf("must be a slice, array, or map, got %T", value)
+}
+
+// firstFailure returns the first error reported by validators for value
+func firstFailure(value any, validators []Validator) error {
+	for _, validator := range validators {
+		if err := validator.Validate(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// failuresOrNil returns failures as an error, or nil when there are none
+func failuresOrNil(failures ValidationErrors) error {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	return failures
+}
@@ -0,0 +1,141 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validationFailures runs Validate with values included and returns the
+// reported failures
+func validationFailures(t *testing.T, fields ...*Field) ValidationErrors {
+	t.Helper()
+
+	SetIncludeValues(true)
+	defer SetIncludeValues(false)
+
+	err := Validate(fields...)
+	require.Error(t, err)
+
+	failures, ok := ErrorsFrom(err)
+	require.True(t, ok)
+	return failures
+}
+
+func TestEach_Strings(t *testing.T) {
+	assert.NoError(t, Validate(NewField("recipients",
+		[]string{"a@example.com", "b@example.com"}, Each(Required, Email))))
+
+	failures := validationFailures(t, NewField("recipients",
+		[]string{"a@example.com", "", "nope"}, Each(Required, Email)))
+
+	require.Len(t, failures, 2)
+	assert.Equal(t, "recipients[1]", failures[0].Field)
+	assert.Equal(t, "is required", failures[0].Message)
+	assert.Equal(t, "recipients[2]", failures[1].Field)
+	assert.Equal(t, "must be a valid email address", failures[1].Message)
+	assert.Equal(t, "nope", failures[1].Value)
+}
+
+func TestEach_Ints(t *testing.T) {
+	failures := validationFailures(t, NewField("ports", []int{80, 70000}, Each(Max(65535))))
+
+	require.Len(t, failures, 1)
+	assert.Equal(t, "ports[1]", failures[0].Field)
+	assert.Equal(t, 70000, failures[0].Value)
+}
+
+func TestEach_Any(t *testing.T) {
+	failures := validationFailures(t, NewField("values", []any{"ok", 5, nil}, Each(Required)))
+
+	require.Len(t, failures, 1)
+	assert.Equal(t, "values[2]", failures[0].Field)
+}
+
+func TestEach_Nested(t *testing.T) {
+	matrix := [][]string{{"a", "b"}, {"c", ""}, {""}}
+	failures := validationFailures(t, NewField("matrix", matrix, Each(MinItems(2), Each(Required))))
+
+	require.Len(t, failures, 2)
+	assert.Equal(t, "matrix[1][1]", failures[0].Field)
+	assert.Equal(t, "is required", failures[0].Message)
+	assert.Equal(t, "matrix[2]", failures[1].Field)
+	assert.Equal(t, "must contain at least 2 items", failures[1].Message)
+}
+
+func TestEach_NotACollection(t *testing.T) {
+	err := Each(Required).Validate("text")
+	require.Error(t, err)
+	assert.Equal(t, "must be a slice or array, got string", err.Error())
+}
+
+func TestKeysAndValues(t *testing.T) {
+	labels := map[string]string{"env": "", "team": "core", "Bad Key": "x"}
+
+	failures := validationFailures(t,
+		NewField("labels", labels, Keys(Pattern(`^[a-z]+$`)), Values(Required)))
+	require.Len(t, failures, 1, "Validate stops at the first failing validator")
+	assert.Equal(t, "labels[Bad Key]", failures[0].Field)
+	assert.Equal(t, "key must match pattern ^[a-z]+$", failures[0].Message)
+
+	failures = validationFailures(t, NewField("labels", labels, Values(Required)))
+	require.Len(t, failures, 1)
+	assert.Equal(t, "labels[env]", failures[0].Field)
+	assert.Equal(t, "is required", failures[0].Message)
+
+	assert.EqualError(t, Keys(Required).Validate([]string{}), "must be a map, got []string")
+}
+
+func TestMinMaxItems(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator Validator
+		value     any
+		wantErr   bool
+	}{
+		{"min slice ok", MinItems(2), []int{1, 2}, false},
+		{"min slice short", MinItems(2), []int{1}, true},
+		{"min map ok", MinItems(1), map[string]int{"a": 1}, false},
+		{"max array ok", MaxItems(3), [3]int{}, false},
+		{"max slice long", MaxItems(1), []string{"a", "b"}, true},
+		{"max map long", MaxItems(0), map[string]int{"a": 1}, true},
+		{"not a collection", MinItems(1), 42, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validator.Validate(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUniqueItems(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		wantErr string
+	}{
+		{"unique strings", []string{"a", "b"}, ""},
+		{"duplicate strings", []string{"a", "b", "a"}, "must contain unique items, [2] duplicates [0]"},
+		{"duplicate any", []any{1, "1", 1}, "must contain unique items, [2] duplicates [0]"},
+		{"duplicate maps", []map[string]int{{"a": 1}, {"a": 1}}, "must contain unique items, [1] duplicates [0]"},
+		{"not a collection", "ab", "must be a slice or array, got string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := UniqueItems.Validate(tt.value)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
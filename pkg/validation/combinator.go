@@ -0,0 +1,61 @@
+package validation
+
+import (
+	"strings"
+)
+
+// Or returns a validator that passes when any of validators passes. When all
+// of them fail, the failure joins their messages with "or", e.g. "must be a
+// valid URL or must be a valid email address". Or with no validators passes.
+func Or(validators ...Validator) Validator {
+	return ValidatorFunc(func(value any) error {
+		if len(validators) == 0 {
+			return nil
+		}
+
+		messages := make([]string, 0, len(validators))
+		for _, validator := range validators {
+			err := validator.Validate(value)
+			if err == nil {
+				return nil
+			}
+
+			messages = append(messages, err.Error())
+		}
+
+		return &ruleError{tag: "or", message: strings.Join(messages, " or ")}
+	})
+}
+
+// And returns a validator that applies validators in order and reports the
+// first failure. It groups rules that must hold together inside Or.
+func And(validators ...Validator) Validator {
+	return ValidatorFunc(func(value any) error {
+		return firstFailure(value, validators)
+	})
+}
+
+// Not returns a validator that fails with message when v passes, e.g.
+// Not(In("admin", "root"), "is a reserved name")
+func Not(v Validator, message string) Validator {
+	return ValidatorFunc(func(value any) error {
+		if v.Validate(value) != nil {
+			return nil
+		}
+
+		return &ruleError{tag: "not", message: message}
+	})
+}
+
+// Optional returns a validator that applies v only when a value is present.
+// Values that Required rejects, such as nil, blank strings, and empty
+// collections, pass without running v.
+func Optional(v Validator) Validator {
+	return ValidatorFunc(func(value any) error {
+		if Required.Validate(value) != nil {
+			return nil
+		}
+
+		return v.Validate(value)
+	})
+}
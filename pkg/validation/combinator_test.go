@@ -0,0 +1,77 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOr(t *testing.T) {
+	contact := Or(URL, Email)
+
+	assert.NoError(t, contact.Validate("https://example.com"))
+	assert.NoError(t, contact.Validate("ada@example.com"))
+	assert.NoError(t, Or().Validate("anything"))
+
+	err := contact.Validate("nope")
+	require.Error(t, err)
+	assert.Equal(t, "must be a valid URL or must be a valid email address", err.Error())
+
+	failures := validationFailures(t, NewField("contact", "nope", contact))
+	assert.Equal(t, "or", failures[0].Tag)
+}
+
+func TestAnd(t *testing.T) {
+	short := And(Required, Max(5))
+
+	assert.NoError(t, short.Validate("abc"))
+	assert.EqualError(t, short.Validate(""), "is required", "stops at the first failure")
+	assert.EqualError(t, short.Validate("abcdef"), "must be at most 5 characters")
+
+	either := Or(And(Pattern(`^[a-z]+$`), Min(3)), In("x"))
+	assert.NoError(t, either.Validate("abc"))
+	assert.NoError(t, either.Validate("x"))
+	assert.Error(t, either.Validate("ab"))
+}
+
+func TestNot(t *testing.T) {
+	name := Not(In("admin", "root"), "is a reserved name")
+
+	assert.NoError(t, name.Validate("ada"))
+
+	failures := validationFailures(t, NewField("name", "root", name))
+	require.Len(t, failures, 1)
+	assert.Equal(t, "is a reserved name", failures[0].Message)
+	assert.Equal(t, "not", failures[0].Tag)
+}
+
+func TestOptional(t *testing.T) {
+	contact := Optional(Or(Email, URL))
+
+	var nilPointer *string
+	empty := []any{nil, "", "   ", []byte{}, nilPointer, map[string]string{}, []int{}}
+	for _, value := range empty {
+		assert.Error(t, Required.Validate(value), "Required rejects %#v", value)
+		assert.NoError(t, contact.Validate(value), "Optional skips %#v", value)
+	}
+
+	assert.NoError(t, contact.Validate("ada@example.com"))
+	assert.NoError(t, contact.Validate("https://example.com"))
+	assert.Error(t, contact.Validate("nope"))
+
+	assert.Error(t, Optional(Min(1)).Validate(0), "numbers are always present")
+}
+
+func TestOptional_Nested(t *testing.T) {
+	recipients := Each(Optional(Or(Email, Not(Pattern(`^\S+$`), "must not contain spaces"))))
+
+	assert.NoError(t, recipients.Validate([]string{"ada@example.com", "", "has space"}))
+
+	err := recipients.Validate([]string{"ada@example.com", "nospaces"})
+	failures, ok := ErrorsFrom(err)
+	require.True(t, ok)
+	require.Len(t, failures, 1)
+	assert.Equal(t, "[1]", failures[0].Field)
+	assert.Equal(t, "must be a valid email address or must not contain spaces", failures[0].Message)
+}
@@ -0,0 +1,227 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// CompareOp is the comparison applied by FieldCompare
+type CompareOp int
+
+// Comparison operators
+const (
+	EQ CompareOp = iota
+	NE
+	GT
+	GTE
+	LT
+	LTE
+)
+
+// compareTags are the rule tags reported for each operator, also accepted
+// by the struct tag validator
+var compareTags = map[CompareOp]string{
+	EQ:  "eqfield",
+	NE:  "nefield",
+	GT:  "gtfield",
+	GTE: "gtefield",
+	LT:  "ltfield",
+	LTE: "ltefield",
+}
+
+// String returns the operator name
+func (op CompareOp) String() string {
+	switch op {
+	case EQ:
+		return "eq"
+
+	case NE:
+		return "ne"
+
+	case GT:
+		return "gt"
+
+	case GTE:
+		return "gte"
+
+	case LT:
+		return "lt"
+
+	case LTE:
+		return "lte"
+
+	default:
+		return fmt.Sprintf("CompareOp(%d)", int(op))
+	}
+}
+
+// FieldCompare returns a validator that compares the value against the value
+// of other, which is read at validation time. Numbers of any width compare by
+// value, time.Time values chronologically, and strings lexically; EQ and NE
+// also accept any other pair of values of the same type. The check passes
+// when either side is nil, so pair it with Required when presence matters.
+func FieldCompare(op CompareOp, other *Field) Validator {
+	return ValidatorFunc(func(value any) error {
+		return compareField(op, reflect.ValueOf(value), reflect.ValueOf(other.Value), other.Name)
+	})
+}
+
+// AfterField returns a validator that checks a time is after other
+func AfterField(other *Field) Validator {
+	return FieldCompare(GT, other)
+}
+
+// BeforeField returns a validator that checks a time is before other
+func BeforeField(other *Field) Validator {
+	return FieldCompare(LT, other)
+}
+
+// compareField checks value against other, naming other in the failure
+func compareField(op CompareOp, value, other reflect.Value, otherName string) error {
+	value, other = indirect(value), indirect(other)
+	if !value.IsValid() || !other.IsValid() {
+		return nil
+	}
+
+	ok, err := compareValues(op, value, other)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		return nil
+	}
+
+	tag := compareTags[op]
+	return paramError(tag, otherName, "%s %s", compareMessage(op, isTime(value)), otherName)
+}
+
+// compareValues reports whether value op other holds
+func compareValues(op CompareOp, value, other reflect.Value) (bool, error) {
+	var cmp int
+	switch {
+	case isTime(value) && isTime(other):
+		cmp = value.Interface().(time.Time).Compare(other.Interface().(time.Time))
+
+	case isNumber(value) && isNumber(other):
+		a, b := toFloat(value), toFloat(other)
+		switch {
+		case a < b:
+			cmp = -1
+
+		case a > b:
+			cmp = 1
+		}
+
+	case value.Kind() == reflect.String && other.Kind() == reflect.String:
+		cmp = strings.Compare(value.String(), other.String())
+
+	case (op == EQ || op == NE) && value.Type() == other.Type() &&
+		value.CanInterface() && other.CanInterface():
+		equal := reflect.DeepEqual(value.Interface(), other.Interface())
+		return equal == (op == EQ), nil
+
+	default:
+		return false, fmt.Errorf("cannot compare %s with %s", value.Type(), other.Type())
+	}
+
+	switch op {
+	case EQ:
+		return cmp == 0, nil
+
+	case NE:
+		return cmp != 0, nil
+
+	case GT:
+		return cmp > 0, nil
+
+	case GTE:
+		return cmp >= 0, nil
+
+	case LT:
+		return cmp < 0, nil
+
+	case LTE:
+		return cmp <= 0, nil
+
+	default:
+		return false, fmt.Errorf("unknown comparison %s", op)
+	}
+}
+
+// compareMessage describes the expectation of op, in chronological terms for
+// times
+func compareMessage(op CompareOp, chronological bool) string {
+	switch op {
+	case EQ:
+		return "must equal"
+
+	case NE:
+		return "must not equal"
+
+	case GT:
+		if chronological {
+			return "must be after"
+		}
+
+		return "must be greater than"
+
+	case GTE:
+		if chronological {
+			return "must not be before"
+		}
+
+		return "must be greater than or equal to"
+
+	case LT:
+		if chronological {
+			return "must be before"
+		}
+
+		return "must be less than"
+
+	default:
+		if chronological {
+			return "must not be after"
+		}
+
+		return "must be less than or equal to"
+	}
+}
+
+// timeType is the reflect type of time.Time
+var timeType = reflect.TypeOf(time.Time{})
+
+// isTime reports whether value holds a time.Time
+func isTime(value reflect.Value) bool {
+	return value.Type() == timeType
+}
+
+// isNumber reports whether value has an integer or floating-point kind
+func isNumber(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+
+	default:
+		return false
+	}
+}
+
+// toFloat converts a numeric value to float64
+func toFloat(value reflect.Value) float64 {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint())
+
+	default:
+		return value.Float()
+	}
+}
@@ -0,0 +1,154 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldCompare_Times(t *testing.T) {
+	created := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	createdAt := NewField("created_at", created)
+
+	tests := []struct {
+		name      string
+		validator Validator
+		value     time.Time
+		wantErr   string
+	}{
+		{"after ok", AfterField(createdAt), created.Add(time.Minute), ""},
+		{"after equal", AfterField(createdAt), created, "must be after created_at"},
+		{"before ok", BeforeField(createdAt), created.Add(-time.Minute), ""},
+		{"before later", BeforeField(createdAt), created.Add(time.Minute), "must be before created_at"},
+		{"gte equal", FieldCompare(GTE, createdAt), created, ""},
+		{"gte earlier", FieldCompare(GTE, createdAt), created.Add(-time.Second), "must not be before created_at"},
+		{"lte later", FieldCompare(LTE, createdAt), created.Add(time.Second), "must not be after created_at"},
+		{"eq other zone", FieldCompare(EQ, createdAt), created.In(time.FixedZone("X", 3600)), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validator.Validate(tt.value)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFieldCompare_Numbers(t *testing.T) {
+	retryCount := NewField("retry_count", int64(3))
+
+	tests := []struct {
+		name    string
+		op      CompareOp
+		value   any
+		wantErr string
+	}{
+		{"gte equal", GTE, 3, ""},
+		{"gte less", GTE, 2, "must be greater than or equal to retry_count"},
+		{"gt uint", GT, uint8(4), ""},
+		{"lt float", LT, 2.5, ""},
+		{"lte more", LTE, 4, "must be less than or equal to retry_count"},
+		{"eq", EQ, int32(3), ""},
+		{"ne", NE, 3, "must not equal retry_count"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FieldCompare(tt.op, retryCount).Validate(tt.value)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFieldCompare_FieldFailure(t *testing.T) {
+	password := NewField("password", "hunter22", Required)
+	confirm := NewField("confirm", "hunter2", FieldCompare(EQ, password))
+
+	failures := validationFailures(t, password, confirm)
+	require.Len(t, failures, 1)
+	assert.Equal(t, "confirm", failures[0].Field)
+	assert.Equal(t, "must equal password", failures[0].Message)
+	assert.Equal(t, "eqfield", failures[0].Tag)
+	assert.Equal(t, map[string]any{"eqfield": "password"}, failures[0].Params)
+
+	confirm.Value = "hunter22"
+	assert.NoError(t, Validate(password, confirm), "the other value is read at validation time")
+}
+
+func TestFieldCompare_TypeMismatch(t *testing.T) {
+	createdAt := NewField("created_at", time.Now())
+
+	assert.EqualError(t, AfterField(createdAt).Validate(5), "cannot compare int with time.Time")
+	assert.EqualError(t, FieldCompare(GT, NewField("tags", []string{"a"})).Validate([]string{"b"}),
+		"cannot compare []string with []string")
+	assert.NoError(t, FieldCompare(EQ, NewField("tags", []string{"a"})).Validate([]string{"a"}))
+}
+
+func TestFieldCompare_NilPointers(t *testing.T) {
+	var missing *time.Time
+	now := time.Now()
+
+	assert.NoError(t, AfterField(NewField("created_at", missing)).Validate(now))
+	assert.NoError(t, AfterField(NewField("created_at", now)).Validate(missing))
+	assert.NoError(t, AfterField(NewField("created_at", nil)).Validate(now))
+
+	later := now.Add(time.Hour)
+	assert.NoError(t, AfterField(NewField("created_at", &now)).Validate(&later))
+	assert.Error(t, BeforeField(NewField("created_at", &now)).Validate(&later))
+}
+
+func TestStruct_CrossFieldTags(t *testing.T) {
+	type window struct {
+		CreatedAt   time.Time  `json:"created_at"`
+		ScheduledAt *time.Time `json:"scheduled_at" validate:"omitempty,gtfield=CreatedAt"`
+		RetryCount  int        `json:"retry_count"`
+		MaxRetries  int        `json:"max_retries" validate:"gtefield=RetryCount"`
+		Password    string     `json:"password"`
+		Confirm     string     `json:"confirm" validate:"eqfield=Password"`
+	}
+
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	earlier := created.Add(-time.Hour)
+
+	fields := byPath(fieldErrors(t, New().Struct(window{
+		CreatedAt:   created,
+		ScheduledAt: &earlier,
+		RetryCount:  4,
+		MaxRetries:  3,
+		Password:    "a",
+		Confirm:     "b",
+	})))
+
+	require.Len(t, fields, 3)
+	assert.Equal(t, "must be after created_at", fields["scheduled_at"].Message)
+	assert.Equal(t, map[string]any{"gtfield": "created_at"}, fields["scheduled_at"].Params)
+	assert.Equal(t, "must be greater than or equal to retry_count", fields["max_retries"].Message)
+	assert.Equal(t, "eqfield", fields["confirm"].Tag)
+
+	later := created.Add(time.Hour)
+	assert.NoError(t, New().Struct(window{
+		CreatedAt:   created,
+		ScheduledAt: &later,
+		RetryCount:  1,
+		MaxRetries:  3,
+	}))
+}
+
+func TestStruct_CrossFieldUnknownField(t *testing.T) {
+	err := New().Struct(struct {
+		A int `validate:"gtfield=Missing"`
+	}{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `references unknown field "Missing"`)
+}
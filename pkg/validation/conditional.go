@@ -0,0 +1,45 @@
+package validation
+
+// When returns a validator that applies validators only when cond reports
+// true. cond is evaluated at validation time, so it can read values that
+// change after the rule is built.
+func When(cond func() bool, validators ...Validator) Validator {
+	return ValidatorFunc(func(value any) error {
+		if !cond() {
+			return nil
+		}
+
+		return firstFailure(value, validators)
+	})
+}
+
+// Unless returns a validator that applies validators only when cond reports
+// false. cond is evaluated at validation time.
+func Unless(cond func() bool, validators ...Validator) Validator {
+	return When(func() bool { return !cond() }, validators...)
+}
+
+// RequiredIf returns a validator that requires the value when predicate
+// reports true for the value of other. other is read at validation time, so
+// it can be a sibling field passed to the same Validate call.
+func RequiredIf(other *Field, predicate func(any) bool) Validator {
+	return ValidatorFunc(func(value any) error {
+		if !predicate(other.Value) || Required.Validate(value) == nil {
+			return nil
+		}
+
+		return paramError("required_if", other.Name, "is required when %s is %v", other.Name, other.Value)
+	})
+}
+
+// RequiredWithout returns a validator that requires the value when other is
+// empty, using the same notion of empty as Required
+func RequiredWithout(other *Field) Validator {
+	return ValidatorFunc(func(value any) error {
+		if Required.Validate(other.Value) == nil || Required.Validate(value) == nil {
+			return nil
+		}
+
+		return paramError("required_without", other.Name, "is required when %s is missing", other.Name)
+	})
+}
@@ -0,0 +1,90 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhen(t *testing.T) {
+	enabled := false
+	validator := When(func() bool { return enabled }, Required, Min(3))
+
+	assert.NoError(t, validator.Validate(""), "skipped while the condition is false")
+
+	enabled = true
+	assert.EqualError(t, validator.Validate(""), "is required")
+	assert.EqualError(t, validator.Validate("ab"), "must be at least 3 characters")
+	assert.NoError(t, validator.Validate("abc"))
+}
+
+func TestUnless(t *testing.T) {
+	hasCron := true
+	validator := Unless(func() bool { return hasCron }, Required)
+
+	assert.NoError(t, validator.Validate(nil), "skipped while the condition is true")
+
+	hasCron = false
+	assert.EqualError(t, validator.Validate(nil), "is required")
+	assert.NoError(t, validator.Validate("2026-01-01T00:00:00Z"))
+}
+
+func TestRequiredIf(t *testing.T) {
+	isEmail := func(v any) bool { return v == "email" }
+
+	channel := NewField("channel", "sms", Required)
+	address := NewField("address", "", RequiredIf(channel, isEmail))
+	assert.NoError(t, Validate(channel, address), "not required for another channel")
+
+	channel.Value = "email"
+	failures := validationFailures(t, channel, address)
+	require.Len(t, failures, 1)
+	assert.Equal(t, "address", failures[0].Field)
+	assert.Equal(t, "is required when channel is email", failures[0].Message)
+	assert.Equal(t, "required_if", failures[0].Tag)
+	assert.Equal(t, map[string]any{"required_if": "channel"}, failures[0].Params)
+
+	address.Value = "ada@example.com"
+	assert.NoError(t, Validate(channel, address))
+}
+
+func TestRequiredIf_ChainAcrossThreeFields(t *testing.T) {
+	isSet := func(v any) bool { return Required.Validate(v) == nil }
+
+	schedule := NewField("schedule", "")
+	cron := NewField("cron", "", RequiredIf(schedule, isSet))
+	timezone := NewField("timezone", "", RequiredIf(cron, isSet))
+
+	assert.NoError(t, Validate(schedule, cron, timezone))
+
+	schedule.Value = "recurring"
+	failures := validationFailures(t, schedule, cron, timezone)
+	require.Len(t, failures, 1, "timezone is only required once cron is set")
+	assert.Equal(t, "cron", failures[0].Field)
+
+	cron.Value = "0 * * * *"
+	failures = validationFailures(t, schedule, cron, timezone)
+	require.Len(t, failures, 1)
+	assert.Equal(t, "timezone", failures[0].Field)
+
+	timezone.Value = "UTC"
+	assert.NoError(t, Validate(schedule, cron, timezone))
+}
+
+func TestRequiredWithout(t *testing.T) {
+	email := NewField("email", "")
+	phone := NewField("phone", "", RequiredWithout(email))
+
+	failures := validationFailures(t, email, phone)
+	require.Len(t, failures, 1)
+	assert.Equal(t, "phone", failures[0].Field)
+	assert.Equal(t, "is required when email is missing", failures[0].Message)
+
+	email.Value = "ada@example.com"
+	assert.NoError(t, Validate(email, phone), "not required when the other is present")
+
+	email.Value = ""
+	phone.Value = "+1 555 0100"
+	assert.NoError(t, Validate(email, phone))
+}
@@ -24,4 +24,72 @@
 //	    validation.Field("email", email, validation.Required, validation.Email),
 //	    validation.Field("age", age, validation.Min(18), validation.Max(100)),
 //	)
+//
+// Validate reports at most one failure per field, stopping at the first
+// failing validator. ValidateAll runs every validator and reports all
+// failures; ValidationErrors.ByField groups them for rendering.
+//
+// Both, like StructValidator.Struct, return an errors.Error with
+// CodeValidation whose failures are stored as []errors.FieldError under
+// errors.FieldsKey and rendered as the "errors" member of problem details.
+// ErrorsFrom recovers them through wrapping and JSON round trips. Input values
+// are left out unless SetIncludeValues is enabled.
+//
+// Collections are validated with Each for slice elements, Keys and Values for
+// maps, and MinItems, MaxItems, and UniqueItems for the collection itself.
+// Element failures carry index paths such as "recipients[2]".
+//
+// Nested groups fields under an object name. Failures from the fluent API and
+// the struct tag validator share one path syntax: members are joined with
+// dots and elements use brackets, e.g. "schedule.cron" or "jobs[3].type".
+//
+// WithMessage, or a Rule with a Message, replaces the default failure text.
+// The {value} and {param} placeholders are substituted and the original
+// message is kept in ValidationError.Detail. Struct fields use a companion
+// `validatemsg` tag.
+//
+// When and Unless apply validators conditionally, and RequiredIf and
+// RequiredWithout reference sibling fields. Conditions and sibling values are
+// read when Validate runs, not when the rules are built.
+//
+// ValidationErrors.Translate renders failures in Spanish, German, or any
+// locale added with RegisterLocale, looking messages up by Tag and
+// interpolating Params. Problem responses written by errors.WriteProblem are
+// translated the same way using the request's Accept-Language header.
+//
+// Sanitizers such as TrimSpace, ToLower, and Truncate normalize values before
+// they are validated. Field.WithSanitizers attaches them in the fluent API and
+// writes the result back to Field.Value; the `sanitize` struct tag does the
+// same for settable struct fields. Built-in sanitizers leave non-string
+// values unchanged.
+//
+// Or, And, and Not combine validators, and Optional applies one only when a
+// value is present by Required's definition:
+//
+//	validation.Optional(validation.Or(validation.Email, validation.URL))
+//
+// FieldCompare, AfterField, and BeforeField compare a value against a sibling
+// field, and the struct tags eqfield, nefield, gtfield, gtefield, ltfield,
+// and ltefield do the same for struct fields.
+//
+// Future, Past, Within, After, and Before check time.Time values, pointers,
+// and RFC 3339 strings; DurationBetween checks durations including the "7d"
+// form. Missing values pass unless Required is also applied. SetClock fixes
+// the current time in tests.
+//
+// Job payloads are checked with JSON, JSONObject, JSONArray, MaxBytes, and
+// JSONSchema. Schemas are compiled once per distinct source, and violations
+// report the JSON pointer of the offending value in the "path" param.
+// ValidateJobRequest applies every submission rule to a models.JobRequest in
+// one call, with options for the payload cap, allowed priorities, metadata
+// limits, and a per-type schema lookup.
+//
+// Domain rules registered with Register or RegisterFunc are available by name
+// through Named and as struct tags:
+//
+//	validation.Register("tenant", tenantValidator)
+//
+//	type Account struct {
+//	    Tenant string `validate:"required,tenant"`
+//	}
 package validation
@@ -0,0 +1,110 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"task-queue/internal/models"
+)
+
+// OneOfValidator checks that a string-kinded value is one of a fixed set of
+// strings. Named string types such as models.JobStatus are accepted.
+type OneOfValidator struct {
+	values []string
+	fold   bool
+}
+
+// OneOfStrings returns a validator that checks a value is one of values
+func OneOfStrings(values ...string) *OneOfValidator {
+	return &OneOfValidator{values: values}
+}
+
+// CaseInsensitive returns a copy of the validator that ignores case
+func (v *OneOfValidator) CaseInsensitive() *OneOfValidator {
+	return &OneOfValidator{values: v.values, fold: true}
+}
+
+// Validate implements the Validator interface
+func (v *OneOfValidator) Validate(value any) error {
+	str, ok := stringKind(value)
+	if !ok {
+		return fmt.Errorf("must be a string, got %T", value)
+	}
+
+	for _, allowed := range v.values {
+		if str == allowed || (v.fold && strings.EqualFold(str, allowed)) {
+			return nil
+		}
+	}
+
+	return paramError("oneof", v.values, "must be one of %s", strings.Join(v.values, ", "))
+}
+
+// jobStatuses are the values accepted by JobStatus
+var jobStatuses = []string{
+	string(models.JobStatusPending),
+	string(models.JobStatusRunning),
+	string(models.JobStatusCompleted),
+	string(models.JobStatusFailed),
+	string(models.JobStatusRetrying),
+	string(models.JobStatusDead),
+	string(models.JobStatusCancelled),
+}
+
+// JobStatus returns a validator that checks a value is one of the
+// models.JobStatus values
+func JobStatus() Validator {
+	return OneOfStrings(jobStatuses...)
+}
+
+// jobPriorityNames maps the priority names accepted by JobPriority to levels
+var jobPriorityNames = map[string]models.JobPriority{
+	"low":      models.JobPriorityLow,
+	"normal":   models.JobPriorityNormal,
+	"high":     models.JobPriorityHigh,
+	"critical": models.JobPriorityCritical,
+}
+
+// JobPriority returns a validator that checks a value is a job priority: an
+// integer of any width or a models.JobPriority from 0 to 3, or one of the
+// names low, normal, high, and critical in any case
+func JobPriority() Validator {
+	return ValidatorFunc(func(value any) error {
+		if str, ok := stringKind(value); ok {
+			if _, known := jobPriorityNames[strings.ToLower(str)]; known {
+				return nil
+			}
+
+			return priorityError()
+		}
+
+		v := indirect(reflect.ValueOf(value))
+		if !v.IsValid() || !isNumber(v) || v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64 {
+			return fmt.Errorf("must be a priority level or name, got %T", value)
+		}
+
+		level := toFloat(v)
+		if level < float64(models.JobPriorityLow) || level > float64(models.JobPriorityCritical) {
+			return priorityError()
+		}
+
+		return nil
+	})
+}
+
+// priorityError lists the accepted priority levels and names
+func priorityError() error {
+	return paramError("oneof", []string{"0", "1", "2", "3", "low", "normal", "high", "critical"},
+		"must be one of 0, 1, 2, 3, low, normal, high, critical")
+}
+
+// stringKind returns the string held by a string-kinded value or pointer
+func stringKind(value any) (string, bool) {
+	v := indirect(reflect.ValueOf(value))
+	if !v.IsValid() || v.Kind() != reflect.String {
+		return "", false
+	}
+
+	return v.String(), true
+}
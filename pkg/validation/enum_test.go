@@ -0,0 +1,99 @@
+package validation
+
+import (
+	"testing"
+
+	"task-queue/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type environment string
+
+func TestOneOfStrings(t *testing.T) {
+	strict := OneOfStrings("active", "inactive")
+	folded := strict.CaseInsensitive()
+
+	tests := []struct {
+		name      string
+		validator Validator
+		value     any
+		wantErr   string
+	}{
+		{"exact", strict, "active", ""},
+		{"alias type", strict, environment("inactive"), ""},
+		{"pointer", strict, func() *string { s := "active"; return &s }(), ""},
+		{"wrong case strict", strict, "Active", "must be one of active, inactive"},
+		{"wrong case folded", folded, "ACTIVE", ""},
+		{"alias folded", folded, environment("InActive"), ""},
+		{"unknown", folded, "paused", "must be one of active, inactive"},
+		{"not a string", strict, 1, "must be a string, got int"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validator.Validate(tt.value)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+
+	assert.Error(t, strict.Validate("ACTIVE"), "CaseInsensitive does not change the original")
+}
+
+func TestJobStatus(t *testing.T) {
+	for _, status := range []models.JobStatus{
+		models.JobStatusPending, models.JobStatusRunning, models.JobStatusCompleted,
+		models.JobStatusFailed, models.JobStatusRetrying, models.JobStatusDead,
+		models.JobStatusCancelled,
+	} {
+		assert.NoError(t, JobStatus().Validate(status), status)
+	}
+
+	assert.NoError(t, JobStatus().Validate("pending"))
+
+	failures := validationFailures(t, NewField("status", models.JobStatus("paused"), JobStatus()))
+	require.Len(t, failures, 1)
+	assert.Equal(t, "must be one of pending, running, completed, failed, retrying, dead, cancelled", failures[0].Message)
+	assert.Equal(t, "oneof", failures[0].Tag)
+}
+
+func TestJobPriority(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		wantErr bool
+	}{
+		{"int low", 0, false},
+		{"int critical", 3, false},
+		{"int8", int8(2), false},
+		{"uint", uint(1), false},
+		{"model type", models.JobPriorityHigh, false},
+		{"name", "normal", false},
+		{"mixed case name", "Critical", false},
+		{"negative", -1, true},
+		{"too high", 4, true},
+		{"model type out of range", models.JobPriority(7), true},
+		{"unknown name", "urgent", true},
+		{"float", 1.0, true},
+		{"nil", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := JobPriority().Validate(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	assert.EqualError(t, JobPriority().Validate("urgent"),
+		"must be one of 0, 1, 2, 3, low, normal, high, critical")
+}
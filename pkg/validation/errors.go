@@ -0,0 +1,99 @@
+package validation
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"sync/atomic"
+
+	"task-queue/pkg/errors"
+)
+
+// includeValues exposes the offending input values in error metadata
+var includeValues atomic.Bool
+
+// SetIncludeValues controls whether the errors returned by Validate,
+// ValidateAll, and StructValidator.Struct carry the offending input values.
+// They are omitted by default so responses and logs don't leak payload data.
+func SetIncludeValues(enabled bool) {
+	includeValues.Store(enabled)
+}
+
+// ErrorsFrom returns the field failures carried by err. It finds the
+// validation errors.Error anywhere in err's chain, including one decoded with
+// errors.FromJSON, and also accepts a bare ValidationErrors from Each, Keys,
+// or Values. It reports false when err carries no field failures.
+func ErrorsFrom(err error) (ValidationErrors, bool) {
+	var failures ValidationErrors
+	if stderrors.As(err, &failures) {
+		return failures, true
+	}
+
+	for _, e := range errors.Chain(err) {
+		appErr, ok := e.(*errors.Error)
+		if !ok || appErr.Code != errors.CodeValidation {
+			continue
+		}
+
+		if failures, ok := fromMetadata(appErr.Metadata[errors.FieldsKey]); ok {
+			return failures, true
+		}
+	}
+
+	return nil, false
+}
+
+// toFieldErrors converts failures to their wire shape, dropping the values
+// unless SetIncludeValues is enabled
+func toFieldErrors(failures ValidationErrors) []errors.FieldError {
+	withValues := includeValues.Load()
+	fields := make([]errors.FieldError, len(failures))
+	for i, failure := range failures {
+		fields[i] = errors.FieldError{
+			Field:   failure.Field,
+			Message: failure.Message,
+			Tag:     failure.Tag,
+			Params:  failure.Params,
+			Detail:  failure.Detail,
+		}
+
+		if withValues {
+			fields[i].Value = failure.Value
+		}
+	}
+
+	return fields
+}
+
+// fromMetadata converts the fields metadata back to failures. Metadata
+// decoded from JSON holds generic values, so it is re-decoded into the wire
+// shape.
+func fromMetadata(metadata any) (ValidationErrors, bool) {
+	var fields []errors.FieldError
+	switch m := metadata.(type) {
+	case nil:
+		return nil, false
+
+	case []errors.FieldError:
+		fields = m
+
+	default:
+		data, err := json.Marshal(m)
+		if err != nil || json.Unmarshal(data, &fields) != nil {
+			return nil, false
+		}
+	}
+
+	failures := make(ValidationErrors, len(fields))
+	for i, field := range fields {
+		failures[i] = ValidationError{
+			Field:   field.Field,
+			Message: field.Message,
+			Detail:  field.Detail,
+			Value:   field.Value,
+			Tag:     field.Tag,
+			Params:  field.Params,
+		}
+	}
+
+	return failures, true
+}
@@ -0,0 +1,116 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-queue/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// priorityFailure is the failure reported for a priority of 7
+var priorityFailure = ValidationError{
+	Field:   "priority",
+	Message: "must be at most 3",
+	Tag:     "max",
+	Params:  map[string]any{"max": float64(3)},
+}
+
+func TestErrorsFrom_Wrapped(t *testing.T) {
+	err := Validate(NewField("priority", 7, Max(3)))
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"direct", err},
+		{"errors.Wrap", errors.Wrap(err, "create job")},
+		{"fmt.Errorf", fmt.Errorf("handler: %w", err)},
+		{"joined", errors.Join(errors.NotFound("queue missing"), err)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures, ok := ErrorsFrom(tt.err)
+			require.True(t, ok)
+			assert.Equal(t, ValidationErrors{priorityFailure}, failures)
+		})
+	}
+}
+
+func TestErrorsFrom_NoFailures(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"nil", nil},
+		{"plain", fmt.Errorf("boom")},
+		{"other code", errors.NotFound("job not found").WithMetadata(errors.FieldsKey, "x")},
+		{"validation without fields", errors.Validation("bad input")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures, ok := ErrorsFrom(tt.err)
+			assert.False(t, ok)
+			assert.Nil(t, failures)
+		})
+	}
+}
+
+func TestErrorsFrom_Collection(t *testing.T) {
+	err := Each(Required).Validate([]string{"a", ""})
+
+	failures, ok := ErrorsFrom(err)
+	require.True(t, ok)
+	require.Len(t, failures, 1)
+	assert.Equal(t, "[1]", failures[0].Field)
+}
+
+func TestErrorsFrom_JSONRoundTrip(t *testing.T) {
+	err := errors.Wrap(Validate(NewField("priority", 7, Max(3))), "create job")
+
+	data, merr := json.Marshal(err)
+	require.NoError(t, merr)
+	assert.NotContains(t, string(data), "7", "values are omitted by default")
+
+	decoded, derr := errors.FromJSON(data)
+	require.NoError(t, derr)
+
+	failures, ok := ErrorsFrom(decoded)
+	require.True(t, ok)
+	assert.Equal(t, ValidationErrors{priorityFailure}, failures)
+}
+
+func TestErrorsFrom_IncludeValues(t *testing.T) {
+	SetIncludeValues(true)
+	t.Cleanup(func() { SetIncludeValues(false) })
+
+	failures, ok := ErrorsFrom(Validate(NewField("priority", 7, Max(3))))
+	require.True(t, ok)
+	require.Len(t, failures, 1)
+	assert.Equal(t, 7, failures[0].Value)
+}
+
+func TestErrorsFrom_Problem(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := errors.Wrap(New().Struct(struct {
+		Type string `json:"type" validate:"required"`
+	}{}), "create job")
+
+	errors.WriteProblem(rec, err, httptest.NewRequest(http.MethodPost, "/jobs", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &decoded))
+	assert.Equal(t, []any{map[string]any{
+		"field":   "type",
+		"message": "is required",
+		"tag":     "required",
+	}}, decoded["errors"])
+}
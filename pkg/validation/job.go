@@ -0,0 +1,159 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+)
+
+// Job request limits applied by ValidateJobRequest unless overridden
+const (
+	DefaultMaxPayloadBytes  = 1 << 20
+	DefaultMaxMetadataKeys  = 32
+	DefaultMaxMetadataBytes = 4 << 10
+	DefaultScheduleGrace    = time.Minute
+	MaxJobRetries           = 10
+)
+
+// SchemaLookup returns the JSON schema registered for a job type. ok is false
+// when the type has no schema.
+type SchemaLookup func(jobType string) (schema []byte, ok bool)
+
+// JobValidationOption customizes ValidateJobRequest
+type JobValidationOption func(*jobValidation)
+
+// jobValidation holds the limits applied to a job request
+type jobValidation struct {
+	maxPayloadBytes  int
+	priorities       []models.JobPriority
+	maxMetadataKeys  int
+	maxMetadataBytes int
+	scheduleGrace    time.Duration
+	schemas          SchemaLookup
+}
+
+// WithMaxPayloadBytes caps the size of the payload
+func WithMaxPayloadBytes(n int) JobValidationOption {
+	return func(v *jobValidation) {
+		v.maxPayloadBytes = n
+	}
+}
+
+// WithPriorities restricts the accepted priorities. By default every
+// models.JobPriority level is accepted.
+func WithPriorities(priorities ...models.JobPriority) JobValidationOption {
+	return func(v *jobValidation) {
+		v.priorities = priorities
+	}
+}
+
+// WithMetadataLimits caps the number of metadata keys and the size of the
+// encoded metadata
+func WithMetadataLimits(maxKeys, maxBytes int) JobValidationOption {
+	return func(v *jobValidation) {
+		v.maxMetadataKeys = maxKeys
+		v.maxMetadataBytes = maxBytes
+	}
+}
+
+// WithScheduleGrace sets how far in the past ScheduledAt may be, absorbing
+// clock skew between clients and the server
+func WithScheduleGrace(grace time.Duration) JobValidationOption {
+	return func(v *jobValidation) {
+		v.scheduleGrace = grace
+	}
+}
+
+// WithSchemaLookup validates payloads against the schema registered for the
+// job type, when there is one
+func WithSchemaLookup(lookup SchemaLookup) JobValidationOption {
+	return func(v *jobValidation) {
+		v.schemas = lookup
+	}
+}
+
+// metadataKeyPattern matches the accepted metadata keys
+const metadataKeyPattern = `^[a-zA-Z0-9_.-]{1,64}$`
+
+// ValidateJobRequest validates a job submission in collect-all mode: the
+// type, the payload's JSON, size, and optional schema, the priority,
+// MaxRetries, ScheduledAt, and the metadata keys and size. Every field is
+// checked and all failures are returned as one validation error, readable
+// with ErrorsFrom. Each field reports only its first failure, so an empty
+// payload is not also reported as invalid JSON.
+func ValidateJobRequest(req *models.JobRequest, opts ...JobValidationOption) error {
+	if req == nil {
+		return errors.New("validation: job request is nil").WithCode(errors.CodeValidation)
+	}
+
+	cfg := jobValidation{
+		maxPayloadBytes:  DefaultMaxPayloadBytes,
+		maxMetadataKeys:  DefaultMaxMetadataKeys,
+		maxMetadataBytes: DefaultMaxMetadataBytes,
+		scheduleGrace:    DefaultScheduleGrace,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return ValidateAll(
+		NewField("type", req.Type, And(Required, JobType())),
+		NewField("payload", req.Payload, And(Required, JSON,
+			MaxBytes(cfg.maxPayloadBytes), cfg.payloadSchema(req.Type))),
+		NewField("priority", req.Priority, cfg.priority()),
+		NewField("max_retries", req.MaxRetries, Optional(Between(0, MaxJobRetries))),
+		NewField("scheduled_at", req.ScheduledAt, Future(cfg.scheduleGrace)),
+		NewField("metadata", req.Metadata,
+			MaxItems(cfg.maxMetadataKeys),
+			Keys(Pattern(metadataKeyPattern)),
+			encodedSize(cfg.maxMetadataBytes),
+		),
+	)
+}
+
+// priority returns the validator for the configured priorities
+func (v jobValidation) priority() Validator {
+	if len(v.priorities) == 0 {
+		return JobPriority()
+	}
+
+	allowed := make([]any, len(v.priorities))
+	for i, priority := range v.priorities {
+		allowed[i] = priority
+	}
+
+	return In(allowed...)
+}
+
+// payloadSchema returns the schema validator for jobType, or a validator
+// that always passes when no schema is registered
+func (v jobValidation) payloadSchema(jobType string) Validator {
+	if v.schemas != nil {
+		if schema, ok := v.schemas(jobType); ok {
+			return JSONSchema(schema)
+		}
+	}
+
+	return ValidatorFunc(func(any) error { return nil })
+}
+
+// encodedSize returns a validator that checks a value encodes to at most n
+// bytes of JSON
+func encodedSize(n int) Validator {
+	return ValidatorFunc(func(value any) error {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("must be encodable as JSON")
+		}
+
+		if len(data) > n {
+			return paramError("max_bytes", n, "must be at most %d bytes when encoded", n)
+		}
+
+		return nil
+	})
+}
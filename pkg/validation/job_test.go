@@ -0,0 +1,120 @@
+package validation
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validJobRequest returns a request that passes every default rule
+func validJobRequest() *models.JobRequest {
+	retries := 3
+	scheduled := fixedNow.Add(time.Hour)
+	return &models.JobRequest{
+		Type:        "send_email",
+		Payload:     json.RawMessage(`{"to":"ada@example.com"}`),
+		Priority:    models.JobPriorityHigh,
+		MaxRetries:  &retries,
+		ScheduledAt: &scheduled,
+		Metadata:    map[string]any{"tenant": "acme"},
+	}
+}
+
+func TestValidateJobRequest_Valid(t *testing.T) {
+	useFakeClock(t)
+
+	assert.NoError(t, ValidateJobRequest(validJobRequest()))
+	assert.NoError(t, ValidateJobRequest(&models.JobRequest{
+		Type:    "cleanup",
+		Payload: json.RawMessage(`[]`),
+	}), "optional fields may be omitted")
+}
+
+func TestValidateJobRequest_EachRule(t *testing.T) {
+	useFakeClock(t)
+
+	tests := []struct {
+		name   string
+		mutate func(*models.JobRequest)
+		opts   []JobValidationOption
+		field  string
+		tag    string
+	}{
+		{"missing type", func(r *models.JobRequest) { r.Type = "" }, nil, "type", "required"},
+		{"bad type", func(r *models.JobRequest) { r.Type = "send email" }, nil, "type", "job_type"},
+		{"missing payload", func(r *models.JobRequest) { r.Payload = nil }, nil, "payload", "required"},
+		{"invalid payload", func(r *models.JobRequest) { r.Payload = json.RawMessage(`{`) }, nil, "payload", "json"},
+		{"oversized payload", func(r *models.JobRequest) {}, []JobValidationOption{WithMaxPayloadBytes(8)}, "payload", "max_bytes"},
+		{"schema violation", func(r *models.JobRequest) {}, []JobValidationOption{WithSchemaLookup(
+			func(jobType string) ([]byte, bool) {
+				return []byte(`{"required":["subject"]}`), jobType == "send_email"
+			})}, "payload", "json_schema"},
+		{"unknown priority", func(r *models.JobRequest) { r.Priority = 7 }, nil, "priority", "oneof"},
+		{"disallowed priority", func(r *models.JobRequest) {}, []JobValidationOption{
+			WithPriorities(models.JobPriorityLow, models.JobPriorityNormal)}, "priority", "oneof"},
+		{"negative retries", func(r *models.JobRequest) { n := -1; r.MaxRetries = &n }, nil, "max_retries", "min"},
+		{"too many retries", func(r *models.JobRequest) { n := 11; r.MaxRetries = &n }, nil, "max_retries", "max"},
+		{"past schedule", func(r *models.JobRequest) {
+			past := fixedNow.Add(-time.Hour)
+			r.ScheduledAt = &past
+		}, nil, "scheduled_at", "future"},
+		{"past schedule beyond grace", func(r *models.JobRequest) {
+			past := fixedNow.Add(-30 * time.Second)
+			r.ScheduledAt = &past
+		}, []JobValidationOption{WithScheduleGrace(10 * time.Second)}, "scheduled_at", "future"},
+		{"bad metadata key", func(r *models.JobRequest) { r.Metadata["bad key"] = 1 }, nil, "metadata[bad key]", "pattern"},
+		{"too many metadata keys", func(r *models.JobRequest) { r.Metadata["extra"] = 1 },
+			[]JobValidationOption{WithMetadataLimits(1, 1024)}, "metadata", "max_items"},
+		{"oversized metadata", func(r *models.JobRequest) { r.Metadata["blob"] = strings.Repeat("x", 64) },
+			[]JobValidationOption{WithMetadataLimits(10, 32)}, "metadata", "max_bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validJobRequest()
+			tt.mutate(req)
+
+			err := ValidateJobRequest(req, tt.opts...)
+			require.Error(t, err)
+			assert.True(t, errors.IsValidation(err))
+
+			failures, ok := ErrorsFrom(err)
+			require.True(t, ok)
+			require.Len(t, failures, 1, "only the broken rule fails: %v", failures)
+			assert.Equal(t, tt.field, failures[0].Field)
+			assert.Equal(t, tt.tag, failures[0].Tag)
+		})
+	}
+}
+
+func TestValidateJobRequest_CollectsAllFields(t *testing.T) {
+	useFakeClock(t)
+
+	retries := 20
+	failures, ok := ErrorsFrom(ValidateJobRequest(&models.JobRequest{
+		Payload:    json.RawMessage(`nope`),
+		Priority:   9,
+		MaxRetries: &retries,
+	}))
+	require.True(t, ok)
+
+	paths := make([]string, len(failures))
+	for i, failure := range failures {
+		paths[i] = failure.Field
+	}
+
+	assert.Equal(t, []string{"type", "payload", "priority", "max_retries"}, paths)
+}
+
+func TestValidateJobRequest_Nil(t *testing.T) {
+	err := ValidateJobRequest(nil)
+	require.Error(t, err)
+	assert.True(t, errors.IsValidation(err))
+}
@@ -0,0 +1,188 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// JSON validates that a json.RawMessage, []byte, or string holds a single
+// well-formed JSON value. Empty input passes, so presence is left to
+// Required.
+var JSON = ValidatorFunc(func(value any) error {
+	data, ok, err := jsonBytes(value)
+	if err != nil || !ok {
+		return err
+	}
+
+	if !json.Valid(data) {
+		return &ruleError{tag: "json", message: "must be valid JSON"}
+	}
+
+	return nil
+})
+
+// JSONObject validates that the input is well-formed JSON with an object at
+// the top level
+var JSONObject = jsonKindValidator('{', "json_object", "must be a JSON object")
+
+// JSONArray validates that the input is well-formed JSON with an array at the
+// top level
+var JSONArray = jsonKindValidator('[', "json_array", "must be a JSON array")
+
+// MaxBytes returns a validator that checks a json.RawMessage, []byte, or
+// string is at most n bytes long
+func MaxBytes(n int) Validator {
+	return ValidatorFunc(func(value any) error {
+		data, ok, err := jsonBytes(value)
+		if err != nil || !ok {
+			return err
+		}
+
+		if len(data) > n {
+			return paramError("max_bytes", n, "must be at most %d bytes", n)
+		}
+
+		return nil
+	})
+}
+
+// compiledSchemas caches compiled JSON schemas by their source
+var compiledSchemas sync.Map // string -> *compiledSchema
+
+// compiledSchema is the outcome of compiling a schema once
+type compiledSchema struct {
+	schema *jsonschema.Schema
+	err    error
+}
+
+// JSONSchema returns a validator that checks the input is well-formed JSON
+// matching schema. The schema is compiled once and cached by its content. On
+// a violation the JSON pointer of the offending value and the failing
+// keyword are reported in the "path" and "keyword" params. An invalid schema
+// fails every validation with a descriptive error.
+func JSONSchema(schema []byte) Validator {
+	return ValidatorFunc(func(value any) error {
+		data, ok, err := jsonBytes(value)
+		if err != nil || !ok {
+			return err
+		}
+
+		compiled := compileSchema(schema)
+		if compiled.err != nil {
+			return fmt.Errorf("cannot be checked against an invalid JSON schema: %v", compiled.err)
+		}
+
+		instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+		if err != nil {
+			return &ruleError{tag: "json", message: "must be valid JSON"}
+		}
+
+		err = compiled.schema.Validate(instance)
+		if err == nil {
+			return nil
+		}
+
+		var schemaErr *jsonschema.ValidationError
+		if !stderrors.As(err, &schemaErr) {
+			return err
+		}
+
+		return schemaViolation(schemaErr)
+	})
+}
+
+// compileSchema compiles schema, reusing an earlier compilation of the same
+// source
+func compileSchema(schema []byte) *compiledSchema {
+	key := string(schema)
+	if cached, ok := compiledSchemas.Load(key); ok {
+		return cached.(*compiledSchema)
+	}
+
+	compiled := &compiledSchema{}
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+	if err == nil {
+		compiler := jsonschema.NewCompiler()
+		if err = compiler.AddResource("schema.json", doc); err == nil {
+			compiled.schema, err = compiler.Compile("schema.json")
+		}
+	}
+
+	compiled.err = err
+	actual, _ := compiledSchemas.LoadOrStore(key, compiled)
+	return actual.(*compiledSchema)
+}
+
+// schemaViolation reports the first leaf failure of a schema validation
+func schemaViolation(err *jsonschema.ValidationError) error {
+	unit := err.BasicOutput()
+	path, keyword, reason := unit.InstanceLocation, unit.KeywordLocation, ""
+	for _, leaf := range unit.Errors {
+		if leaf.Error != nil {
+			path, keyword, reason = leaf.InstanceLocation, leaf.KeywordLocation, leaf.Error.String()
+			break
+		}
+	}
+
+	if path == "" {
+		path = "/"
+	}
+
+	message := fmt.Sprintf("does not match the JSON schema at %s", path)
+	if reason != "" {
+		message += ": " + reason
+	}
+
+	return &ruleError{
+		tag:     "json_schema",
+		params:  map[string]any{"path": path, "keyword": keyword},
+		message: message,
+	}
+}
+
+// jsonKindValidator checks the input is valid JSON whose first token starts
+// with open
+func jsonKindValidator(open byte, tag, message string) ValidatorFunc {
+	return func(value any) error {
+		data, ok, err := jsonBytes(value)
+		if err != nil || !ok {
+			return err
+		}
+
+		trimmed := bytes.TrimSpace(data)
+		if !json.Valid(trimmed) || trimmed[0] != open {
+			return &ruleError{tag: tag, message: message}
+		}
+
+		return nil
+	}
+}
+
+// jsonBytes returns the raw bytes of a JSON input. ok is false for nil and
+// empty input.
+func jsonBytes(value any) ([]byte, bool, error) {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		return nil, false, nil
+
+	case json.RawMessage:
+		data = v
+
+	case []byte:
+		data = v
+
+	case string:
+		data = []byte(v)
+
+	default:
+		return nil, false, fmt.Errorf("must be JSON bytes or a string, got %T", value)
+	}
+
+	return data, len(data) > 0, nil
+}
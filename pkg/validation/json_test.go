@@ -0,0 +1,139 @@
+package validation
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"task-queue/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// emailSchema describes the payload of an email job
+var emailSchema = []byte(`{
+	"type": "object",
+	"required": ["to", "subject"],
+	"properties": {
+		"to": {"type": "string", "format": "email"},
+		"subject": {"type": "string", "maxLength": 10},
+		"attachments": {"type": "array", "items": {"type": "string"}}
+	}
+}`)
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		wantErr string
+	}{
+		{"object", json.RawMessage(`{"a":1}`), ""},
+		{"scalar string", `"text"`, ""},
+		{"bytes", []byte(`[1,2]`), ""},
+		{"empty passes", json.RawMessage(nil), ""},
+		{"truncated", json.RawMessage(`{"a":`), "must be valid JSON"},
+		{"trailing data", `{} {}`, "must be valid JSON"},
+		{"wrong type", 42, "must be JSON bytes or a string, got int"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := JSON.Validate(tt.value)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJSONObjectAndArray(t *testing.T) {
+	assert.NoError(t, JSONObject.Validate(` {"a":1}`))
+	assert.EqualError(t, JSONObject.Validate(`[1]`), "must be a JSON object")
+	assert.EqualError(t, JSONObject.Validate(`{"a":`), "must be a JSON object")
+
+	assert.NoError(t, JSONArray.Validate(json.RawMessage(`[]`)))
+	assert.EqualError(t, JSONArray.Validate(`"[]"`), "must be a JSON array")
+}
+
+func TestMaxBytes(t *testing.T) {
+	assert.NoError(t, MaxBytes(8).Validate(`{"a":12}`))
+
+	failures := validationFailures(t,
+		NewField("payload", json.RawMessage(`{"a":123}`), MaxBytes(8)))
+	require.Len(t, failures, 1)
+	assert.Equal(t, "must be at most 8 bytes", failures[0].Message)
+	assert.Equal(t, map[string]any{"max_bytes": 8}, failures[0].Params)
+}
+
+func TestJSONSchema(t *testing.T) {
+	validator := JSONSchema(emailSchema)
+
+	assert.NoError(t, validator.Validate(`{"to":"ada@example.com","subject":"hi"}`))
+
+	tests := []struct {
+		name    string
+		payload string
+		path    string
+	}{
+		{"missing property", `{"to":"ada@example.com"}`, "/"},
+		{"wrong type", `{"to":"ada@example.com","subject":7}`, "/subject"},
+		{"too long", `{"to":"ada@example.com","subject":"a long subject"}`, "/subject"},
+		{"nested item", `{"to":"a@b.co","subject":"hi","attachments":["a",2]}`, "/attachments/1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := validationFailures(t,
+				NewField("payload", json.RawMessage(tt.payload), JSONSchema(emailSchema)))
+
+			require.Len(t, failures, 1)
+			assert.Equal(t, "json_schema", failures[0].Tag)
+			assert.Equal(t, tt.path, failures[0].Params["path"])
+			assert.NotEmpty(t, failures[0].Params["keyword"])
+			assert.True(t, strings.HasPrefix(failures[0].Message,
+				"does not match the JSON schema at "+tt.path+": "), failures[0].Message)
+		})
+	}
+
+	assert.EqualError(t, validator.Validate(`{"to":`), "must be valid JSON")
+}
+
+func TestJSONSchema_CachedAndInvalid(t *testing.T) {
+	first := compileSchema(emailSchema)
+	assert.Same(t, first, compileSchema(append([]byte(nil), emailSchema...)),
+		"schemas are cached by content")
+
+	err := JSONSchema([]byte(`{"type": 12}`)).Validate(`{}`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid JSON schema")
+}
+
+func TestStruct_JSONTags(t *testing.T) {
+	type envelope struct {
+		Body    json.RawMessage `json:"body" validate:"required,json_object,max_bytes=16"`
+		Items   json.RawMessage `json:"items" validate:"omitempty,json_array"`
+		Comment string          `json:"comment" validate:"omitempty,json"`
+	}
+
+	assert.NoError(t, New().Struct(envelope{Body: json.RawMessage(`{"a":1}`)}))
+
+	fields := byPath(fieldErrors(t, New().Struct(envelope{
+		Body:    json.RawMessage(`{"long":"payload!!"}`),
+		Items:   json.RawMessage(`{}`),
+		Comment: "{",
+	})))
+
+	require.Len(t, fields, 3)
+	assert.Equal(t, "max_bytes", fields["body"].Tag)
+	assert.Equal(t, "json_array", fields["items"].Tag)
+	assert.Equal(t, "json", fields["comment"].Tag)
+
+	fields = byPath(fieldErrors(t, New().Struct(models.JobRequest{
+		Type:    "email",
+		Payload: json.RawMessage(`{"to":`),
+	})))
+	assert.Equal(t, "json", fields["payload"].Tag, "JobRequest payloads must be valid JSON")
+}
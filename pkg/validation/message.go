@@ -0,0 +1,147 @@
+package validation
+
+import (
+	stderrors "errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ruleError is a failure reported by a built-in validator, carrying the rule
+// tag and parameters into ValidationError
+type ruleError struct {
+	tag     string
+	params  map[string]any
+	message string
+}
+
+// Error implements the error interface
+func (e *ruleError) Error() string {
+	return e.message
+}
+
+// paramError reports a failure of the rule tag with a single parameter
+func paramError(tag string, param any, format string, args ...any) error {
+	return &ruleError{
+		tag:     tag,
+		params:  map[string]any{tag: param},
+		message: fmt.Sprintf(format, args...),
+	}
+}
+
+// messageError replaces a validator's message with a custom one
+type messageError struct {
+	message string
+	detail  string
+	cause   error
+}
+
+// Error implements the error interface
+func (e *messageError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the original validator error
+func (e *messageError) Unwrap() error {
+	return e.cause
+}
+
+// WithMessage returns a validator that reports msg instead of the message of
+// v. The placeholder {value} is replaced by the validated value, {param} by
+// the rule parameter, and {name} by the parameter called name, e.g. {min}.
+// The original message is kept in ValidationError.Detail.
+func WithMessage(v Validator, msg string) Validator {
+	return Rule{Validator: v, Message: msg}
+}
+
+// Validate implements the Validator interface. An empty Message keeps the
+// validator's own message.
+func (r Rule) Validate(value any) error {
+	err := r.Validator.Validate(value)
+	if err == nil || r.Message == "" {
+		return err
+	}
+
+	if nested, ok := nestedFailures(err); ok {
+		replaced := make(ValidationErrors, len(nested))
+		for i, failure := range nested {
+			failure.Detail = failureDetail(failure)
+			failure.Message = renderMessage(r.Message, failure.Value, failure.Params)
+			replaced[i] = failure
+		}
+
+		return replaced
+	}
+
+	var params map[string]any
+	var re *ruleError
+	if stderrors.As(err, &re) {
+		params = re.params
+	}
+
+	detail := err.Error()
+	var me *messageError
+	if stderrors.As(err, &me) {
+		detail = me.detail
+	}
+
+	return &messageError{
+		message: renderMessage(r.Message, value, params),
+		detail:  detail,
+		cause:   err,
+	}
+}
+
+// newFailure builds the ValidationError for err at path, picking up the rule
+// tag, parameters, and original message when err carries them
+func newFailure(path string, value any, err error) ValidationError {
+	failure := ValidationError{Field: path, Message: err.Error(), Value: value}
+
+	var re *ruleError
+	if stderrors.As(err, &re) {
+		failure.Tag = re.tag
+		failure.Params = re.params
+	}
+
+	var me *messageError
+	if stderrors.As(err, &me) {
+		failure.Detail = me.detail
+	}
+
+	return failure
+}
+
+// failureDetail returns the original message of a failure
+func failureDetail(failure ValidationError) string {
+	if failure.Detail != "" {
+		return failure.Detail
+	}
+
+	return failure.Message
+}
+
+// renderMessage substitutes the {value}, {param}, and named parameter
+// placeholders in msg
+func renderMessage(msg string, value any, params map[string]any) string {
+	if !strings.Contains(msg, "{") {
+		return msg
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	pairs := []string{"{value}", fmt.Sprint(value)}
+	if len(names) > 0 {
+		pairs = append(pairs, "{param}", fmt.Sprint(params[names[0]]))
+	}
+
+	for _, name := range names {
+		pairs = append(pairs, "{"+name+"}", fmt.Sprint(params[name]))
+	}
+
+	return strings.NewReplacer(pairs...).Replace(msg)
+}
@@ -0,0 +1,98 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMessage_Placeholders(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator Validator
+		value     any
+		want      string
+	}{
+		{"plain", WithMessage(Required, "Name is required"), "", "Name is required"},
+		{"param", WithMessage(Min(8), "Password must contain at least {param} characters"), "abc",
+			"Password must contain at least 8 characters"},
+		{"named param", WithMessage(Max(3), "No more than {max} retries, got {value}"), 5,
+			"No more than 3 retries, got 5"},
+		{"value", WithMessage(Email, "{value} is not an email"), "nope", "nope is not an email"},
+		{"no params", WithMessage(Email, "bad {param}"), "nope", "bad {param}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validator.Validate(tt.value)
+			require.Error(t, err)
+			assert.Equal(t, tt.want, err.Error())
+		})
+	}
+}
+
+func TestWithMessage_PassesThrough(t *testing.T) {
+	assert.NoError(t, WithMessage(Min(3), "too short").Validate("abcd"))
+	assert.EqualError(t, Rule{Validator: Min(3)}.Validate("ab"), "must be at least 3 characters",
+		"an empty message keeps the default")
+}
+
+func TestWithMessage_FieldFailure(t *testing.T) {
+	failures := validationFailures(t,
+		NewField("password", "abc",
+			Required,
+			Rule{Validator: Min(8), Message: "Password must contain at least {min} characters"},
+		),
+		NewField("age", 10, Min(18)),
+	)
+
+	require.Len(t, failures, 2)
+	assert.Equal(t, "Password must contain at least 8 characters", failures[0].Message)
+	assert.Equal(t, "must be at least 8 characters", failures[0].Detail)
+	assert.Equal(t, "min", failures[0].Tag)
+	assert.Equal(t, map[string]any{"min": float64(8), "unit": "characters"}, failures[0].Params)
+
+	assert.Equal(t, "must be at least 18", failures[1].Message, "default message without a rule")
+	assert.Empty(t, failures[1].Detail)
+}
+
+func TestWithMessage_OuterTakesPrecedence(t *testing.T) {
+	inner := WithMessage(Min(8), "inner")
+	failures := validationFailures(t, NewField("password", "abc", WithMessage(inner, "outer")))
+
+	require.Len(t, failures, 1)
+	assert.Equal(t, "outer", failures[0].Message)
+	assert.Equal(t, "must be at least 8 characters", failures[0].Detail)
+}
+
+func TestWithMessage_Each(t *testing.T) {
+	failures := validationFailures(t, NewField("recipients", []string{"a@example.com", "bad"},
+		WithMessage(Each(Email), "{value} is not a valid recipient")))
+
+	require.Len(t, failures, 1)
+	assert.Equal(t, "recipients[1]", failures[0].Field)
+	assert.Equal(t, "bad is not a valid recipient", failures[0].Message)
+	assert.Equal(t, "must be a valid email address", failures[0].Detail)
+}
+
+func TestStruct_ValidateMsgTag(t *testing.T) {
+	type signup struct {
+		Password string   `json:"password" validate:"required,min=8" validatemsg:"Password must contain at least {param} characters"`
+		Tags     []string `json:"tags" validate:"dive,max=3" validatemsg:"tag {value} is too long"`
+		Email    string   `json:"email" validate:"email"`
+	}
+
+	fields := byPath(fieldErrors(t, New().Struct(signup{
+		Password: "abc",
+		Tags:     []string{"ok", "toolong"},
+		Email:    "nope",
+	})))
+
+	require.Len(t, fields, 3)
+	assert.Equal(t, "Password must contain at least 8 characters", fields["password"].Message)
+	assert.Equal(t, "must be at least 8 characters", fields["password"].Detail)
+	assert.Equal(t, "tag toolong is too long", fields["tags[1]"].Message)
+	assert.Equal(t, "must be a valid email address", fields["email"].Message)
+	assert.Empty(t, fields["email"].Detail)
+}
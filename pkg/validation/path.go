@@ -0,0 +1,73 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"task-queue/pkg/errors"
+)
+
+// Field paths are shared by the fluent API and the struct tag validator.
+// Object members are joined with dots and collection elements use brackets,
+// e.g. "schedule.cron", "jobs[3].type", or "labels[env]".
+
+// joinPath appends a member name or a bracketed element segment to prefix
+func joinPath(prefix, name string) string {
+	if prefix == "" || name == "" {
+		return prefix + name
+	}
+
+	if strings.HasPrefix(name, "[") {
+		return prefix + name
+	}
+
+	return prefix + "." + name
+}
+
+// indexPath appends the element index i to prefix
+func indexPath(prefix string, i int) string {
+	return fmt.Sprintf("%s[%d]", prefix, i)
+}
+
+// keyPath appends the map key to prefix
+func keyPath(prefix string, key any) string {
+	return fmt.Sprintf("%s[%v]", prefix, key)
+}
+
+// appendFailure records err under path. Failures nested inside err, either
+// ValidationErrors from Each or Keys or a validation errors.Error returned by
+// Validate, are flattened with path as their prefix.
+func appendFailure(failures ValidationErrors, path string, value any, err error) ValidationErrors {
+	if err == nil {
+		return failures
+	}
+
+	if nested, ok := nestedFailures(err); ok {
+		for _, failure := range nested {
+			failure.Field = joinPath(path, failure.Field)
+			failures = append(failures, failure)
+		}
+
+		return failures
+	}
+
+	return append(failures, newFailure(path, value, err))
+}
+
+// nestedFailures returns the field failures carried directly by err, if any
+func nestedFailures(err error) (ValidationErrors, bool) {
+	switch e := err.(type) {
+	case ValidationErrors:
+		return e, true
+
+	case *errors.Error:
+		if e.Code != errors.CodeValidation {
+			return nil, false
+		}
+
+		return fromMetadata(e.Metadata[errors.FieldsKey])
+
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,98 @@
+package validation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoinPath(t *testing.T) {
+	tests := []struct {
+		prefix, name, want string
+	}{
+		{"", "type", "type"},
+		{"job", "", "job"},
+		{"job", "type", "job.type"},
+		{"jobs", "[3]", "jobs[3]"},
+		{"jobs[3]", "type", "jobs[3].type"},
+		{"matrix[1]", "[2]", "matrix[1][2]"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, joinPath(tt.prefix, tt.name))
+	}
+}
+
+func TestNested_TwoLevelsWithEach(t *testing.T) {
+	// validateJob validates one element of the jobs batch as an object
+	validateJob := ValidatorFunc(func(value any) error {
+		job := value.(map[string]any)
+		return Validate(
+			NewField("type", job["type"], Required),
+			Nested("schedule",
+				NewField("cron", job["cron"], Required),
+			),
+		)
+	})
+
+	jobs := []map[string]any{
+		{"type": "email", "cron": "* * * * *"},
+		{"type": "", "cron": "* * * * *"},
+		{"type": "report", "cron": ""},
+	}
+
+	failures := validationFailures(t,
+		Nested("job",
+			NewField("type", "email", Required),
+			Nested("schedule",
+				NewField("cron", "", Required),
+				NewField("timezone", "UTC", Required),
+			),
+		),
+		NewField("jobs", jobs, Each(validateJob)),
+	)
+
+	paths := make([]string, len(failures))
+	for i, failure := range failures {
+		paths[i] = failure.Field
+	}
+
+	assert.Equal(t, []string{"job.schedule.cron", "jobs[1].type", "jobs[2].schedule.cron"}, paths)
+	assert.Equal(t, "", failures[1].Value, "element failures keep the member value")
+}
+
+func TestNested_SkippedWhenObjectFails(t *testing.T) {
+	schedule := Nested("schedule", NewField("cron", "", Required))
+	schedule.Validators = []Validator{Required}
+
+	failures := validationFailures(t, schedule)
+
+	require.Len(t, failures, 1)
+	assert.Equal(t, "schedule", failures[0].Field)
+}
+
+func TestNested_ValidateAll(t *testing.T) {
+	err := ValidateAll(Nested("schedule",
+		NewField("cron", "x", Min(9), Pattern(`^\S+ \S+`)),
+	))
+	require.Error(t, err)
+
+	assert.Equal(t,
+		"validation failed: schedule.cron: must be at least 9 characters; "+
+			"schedule.cron: must match pattern ^\\S+ \\S+",
+		err.Error())
+}
+
+func TestValidationErrors_JSONKeepsPaths(t *testing.T) {
+	failures := validationFailures(t, Nested("schedule", NewField("cron", "", Required)))
+
+	data, err := json.Marshal(failures)
+	require.NoError(t, err)
+
+	var decoded []map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Len(t, decoded, 1)
+	assert.Equal(t, "schedule.cron", decoded[0]["field"])
+}
@@ -0,0 +1,98 @@
+package validation
+
+import (
+	"sort"
+	"sync"
+
+	"task-queue/pkg/errors"
+)
+
+// registry holds the validators registered by name
+var registry = struct {
+	mu         sync.RWMutex
+	validators map[string]Validator
+}{
+	validators: make(map[string]Validator),
+}
+
+// builtinTags are the struct tag rules that registered names cannot shadow
+var builtinTags = map[string]struct{}{
+	"required": {}, "omitempty": {}, "dive": {}, "min": {}, "max": {},
+	"email": {}, "url": {}, "uuid": {}, "uuid4": {}, "nonnil_uuid": {}, "oneof": {}, "future": {}, "past": {},
+	"json": {}, "json_object": {}, "json_array": {}, "max_bytes": {},
+	"eqfield": {}, "nefield": {}, "gtfield": {}, "gtefield": {}, "ltfield": {}, "ltefield": {},
+}
+
+// Register makes v available under name, both to Named and as a struct tag.
+// Names must be non-empty, must not clash with a built-in tag, and can only
+// be registered once. Registration is safe to call concurrently with
+// validation.
+func Register(name string, v Validator) error {
+	if name == "" || v == nil {
+		return errors.New("validation: register requires a name and a validator").
+			WithCode(errors.CodeConfiguration)
+	}
+
+	if _, ok := builtinTags[name]; ok {
+		return errors.Newf("validation: %q is a built-in rule", name).
+			WithCode(errors.CodeConfiguration)
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if _, ok := registry.validators[name]; ok {
+		return errors.Newf("validation: validator %q already registered", name).
+			WithCode(errors.CodeAlreadyExists)
+	}
+
+	registry.validators[name] = v
+	return nil
+}
+
+// RegisterFunc registers fn under name, see Register
+func RegisterFunc(name string, fn func(any) error) error {
+	if fn == nil {
+		return Register(name, nil)
+	}
+
+	return Register(name, ValidatorFunc(fn))
+}
+
+// Named returns a validator that delegates to the one registered under name.
+// The lookup happens at validation time, so Named can be used before the
+// registration runs. An unknown name fails validation.
+func Named(name string) Validator {
+	return ValidatorFunc(func(value any) error {
+		v, ok := lookupRegistered(name)
+		if !ok {
+			return errors.Newf("validation: no validator registered as %q", name).
+				WithCode(errors.CodeConfiguration)
+		}
+
+		return v.Validate(value)
+	})
+}
+
+// ListRegistered returns the registered names in sorted order
+func ListRegistered() []string {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	names := make([]string, 0, len(registry.validators))
+	for name := range registry.validators {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// lookupRegistered returns the validator registered under name
+func lookupRegistered(name string) (Validator, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	v, ok := registry.validators[name]
+	return v, ok
+}
@@ -0,0 +1,160 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"task-queue/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// restoreRegistry removes validators registered during the test
+func restoreRegistry(t *testing.T) {
+	t.Helper()
+
+	t.Cleanup(func() {
+		registry.mu.Lock()
+		defer registry.mu.Unlock()
+
+		registry.validators = make(map[string]Validator)
+	})
+}
+
+// knownTenant accepts the tenants "acme" and "globex"
+var knownTenant = ValidatorFunc(func(value any) error {
+	if value == "acme" || value == "globex" {
+		return nil
+	}
+
+	return fmt.Errorf("must be a known tenant ID")
+})
+
+func TestRegister(t *testing.T) {
+	restoreRegistry(t)
+
+	require.NoError(t, Register("tenant", knownTenant))
+	require.NoError(t, RegisterFunc("webhook_host", func(value any) error {
+		if s, _ := value.(string); strings.HasSuffix(s, ".example.com") {
+			return nil
+		}
+
+		return fmt.Errorf("must be an allowed webhook host")
+	}))
+
+	assert.Equal(t, []string{"tenant", "webhook_host"}, ListRegistered())
+
+	err := Register("tenant", knownTenant)
+	require.Error(t, err)
+	assert.Equal(t, errors.CodeAlreadyExists, errors.GetCode(err))
+}
+
+func TestRegister_RejectsInvalid(t *testing.T) {
+	restoreRegistry(t)
+
+	tests := []struct {
+		name      string
+		validator Validator
+	}{
+		{"", knownTenant},
+		{"tenant", nil},
+		{"required", knownTenant},
+		{"gtfield", knownTenant},
+	}
+
+	for _, tt := range tests {
+		err := Register(tt.name, tt.validator)
+		require.Error(t, err, tt.name)
+		assert.Equal(t, errors.CodeConfiguration, errors.GetCode(err))
+	}
+
+	assert.Error(t, RegisterFunc("tenant", nil))
+	assert.Empty(t, ListRegistered())
+}
+
+func TestNamed(t *testing.T) {
+	restoreRegistry(t)
+
+	tenant := Named("tenant")
+	assert.EqualError(t, tenant.Validate("acme"), `validation: no validator registered as "tenant"`,
+		"unknown names fail")
+
+	require.NoError(t, Register("tenant", knownTenant))
+	assert.NoError(t, tenant.Validate("acme"), "looked up at validation time")
+
+	failures := validationFailures(t, NewField("tenant", "initech", Required, tenant))
+	require.Len(t, failures, 1)
+	assert.Equal(t, "must be a known tenant ID", failures[0].Message)
+}
+
+func TestStruct_RegisteredTag(t *testing.T) {
+	restoreRegistry(t)
+	require.NoError(t, Register("tenant", knownTenant))
+
+	type account struct {
+		Tenant  string   `json:"tenant" validate:"required,tenant"`
+		Parents []string `json:"parents" validate:"dive,tenant"`
+	}
+
+	v := New()
+	assert.NoError(t, v.Struct(account{Tenant: "acme", Parents: []string{"globex"}}))
+
+	fields := byPath(fieldErrors(t, v.Struct(account{Tenant: "initech", Parents: []string{"acme", "hooli"}})))
+	require.Len(t, fields, 2)
+	assert.Equal(t, "tenant", fields["tenant"].Tag)
+	assert.Equal(t, "must be a known tenant ID", fields["tenant"].Message)
+	assert.Equal(t, "tenant", fields["parents[1]"].Tag)
+}
+
+func TestStruct_UnknownAndParameterizedRegisteredTag(t *testing.T) {
+	restoreRegistry(t)
+
+	err := New().Struct(struct {
+		A string `validate:"tenant"`
+	}{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown rule "tenant"`)
+
+	require.NoError(t, Register("tenant", knownTenant))
+	err = New().Struct(struct {
+		A string `validate:"tenant=acme"`
+	}{})
+	require.Error(t, err)
+	assert.Equal(t, errors.CodeConfiguration, errors.GetCode(err))
+}
+
+func TestRegistry_ConcurrentReadsDuringValidation(t *testing.T) {
+	restoreRegistry(t)
+	require.NoError(t, Register("tenant", knownTenant))
+
+	type account struct {
+		Tenant string `validate:"tenant"`
+	}
+
+	v := New()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				assert.NoError(t, v.Struct(account{Tenant: "acme"}))
+				assert.NoError(t, Named("tenant").Validate("globex"))
+			}
+		}()
+
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				_ = Register(fmt.Sprintf("rule_%d_%d", i, j), knownTenant)
+				_ = ListRegistered()
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	assert.Len(t, ListRegistered(), 81)
+}
@@ -0,0 +1,170 @@
+package validation
+
+import (
+	"fmt"
+	"html"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Struct tag read by StructValidator for sanitizers
+const sanitizeTagName = "sanitize"
+
+// Sanitizer normalizes a value before it is validated. Sanitizers run in
+// order and the result replaces the value, so what is validated is what gets
+// stored.
+//
+// Sanitizers never run on non-string values unless they declare support:
+// StringSanitizer, which backs every built-in, returns other values
+// unchanged, while a custom implementation receives every value and decides
+// for itself.
+type Sanitizer interface {
+	Sanitize(value any) any
+}
+
+// StringSanitizer adapts a string function to Sanitizer. It applies to
+// strings and types whose underlying kind is string, and returns any other
+// value unchanged.
+type StringSanitizer func(string) string
+
+// Sanitize implements the Sanitizer interface
+func (f StringSanitizer) Sanitize(value any) any {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || v.Kind() != reflect.String {
+		return value
+	}
+
+	return reflect.ValueOf(f(v.String())).Convert(v.Type()).Interface()
+}
+
+// TrimSpace removes leading and trailing whitespace
+var TrimSpace = StringSanitizer(strings.TrimSpace)
+
+// ToLower converts a string to lower case
+var ToLower = StringSanitizer(strings.ToLower)
+
+// ToUpper converts a string to upper case
+var ToUpper = StringSanitizer(strings.ToUpper)
+
+// CollapseWhitespace trims a string and replaces every run of whitespace
+// inside it with a single space
+var CollapseWhitespace = StringSanitizer(func(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+})
+
+// htmlTagPattern matches HTML tags and comments
+var htmlTagPattern = regexp.MustCompile(`<!--[\s\S]*?-->|<[^>]*>`)
+
+// StripHTML removes HTML tags and comments and decodes entities, leaving
+// the text content
+var StripHTML = StringSanitizer(func(s string) string {
+	return html.UnescapeString(htmlTagPattern.ReplaceAllString(s, ""))
+})
+
+// Truncate returns a sanitizer that shortens a string to at most n
+// characters
+func Truncate(n int) Sanitizer {
+	return StringSanitizer(func(s string) string {
+		runes := []rune(s)
+		if len(runes) <= n {
+			return s
+		}
+
+		return string(runes[:n])
+	})
+}
+
+// WithSanitizers adds sanitizers that run, in order, before the field's
+// validators. The sanitized value replaces Value. It returns f for chaining.
+func (f *Field) WithSanitizers(sanitizers ...Sanitizer) *Field {
+	f.Sanitizers = append(f.Sanitizers, sanitizers...)
+	return f
+}
+
+// SanitizedValues returns the values of fields and their nested members by
+// path, as left by the sanitizers of the last Validate or ValidateAll call
+func SanitizedValues(fields ...*Field) map[string]any {
+	values := make(map[string]any)
+	collectValues("", fields, values)
+	return values
+}
+
+// collectValues records the values of fields under the path prefix
+func collectValues(prefix string, fields []*Field, values map[string]any) {
+	for _, field := range fields {
+		path := joinPath(prefix, field.Name)
+		values[path] = field.Value
+		collectValues(path, field.nested, values)
+	}
+}
+
+// sanitize applies sanitizers to value in order
+func sanitize(value any, sanitizers []Sanitizer) any {
+	for _, sanitizer := range sanitizers {
+		value = sanitizer.Sanitize(value)
+	}
+
+	return value
+}
+
+// sanitizeField applies sanitizers to a settable string or pointer to string
+// struct field, writing the result back. Other fields are left untouched.
+func sanitizeField(value reflect.Value, sanitizers []Sanitizer) {
+	value = indirect(value)
+	if !value.IsValid() || !value.CanSet() || value.Kind() != reflect.String {
+		return
+	}
+
+	sanitized := reflect.ValueOf(sanitize(value.Interface(), sanitizers))
+	if sanitized.IsValid() && sanitized.Kind() == reflect.String {
+		value.SetString(sanitized.String())
+	}
+}
+
+// parseSanitizeTag parses a comma-separated sanitize tag
+func parseSanitizeTag(tag string) ([]Sanitizer, error) {
+	var sanitizers []Sanitizer
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, param, hasParam := strings.Cut(part, "=")
+		switch name {
+		case "trim":
+			sanitizers = append(sanitizers, TrimSpace)
+
+		case "lower":
+			sanitizers = append(sanitizers, ToLower)
+
+		case "upper":
+			sanitizers = append(sanitizers, ToUpper)
+
+		case "collapse":
+			sanitizers = append(sanitizers, CollapseWhitespace)
+
+		case "strip_html":
+			sanitizers = append(sanitizers, StripHTML)
+
+		case "truncate":
+			if !hasParam {
+				return nil, fmt.Errorf("truncate requires a parameter")
+			}
+
+			n, err := strconv.Atoi(param)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("truncate parameter %q is not a length", param)
+			}
+
+			sanitizers = append(sanitizers, Truncate(n))
+
+		default:
+			return nil, fmt.Errorf("unknown sanitizer %q", name)
+		}
+	}
+
+	return sanitizers, nil
+}
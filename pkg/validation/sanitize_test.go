@@ -0,0 +1,125 @@
+package validation
+
+import (
+	"testing"
+
+	"task-queue/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizers(t *testing.T) {
+	type label string
+
+	tests := []struct {
+		name      string
+		sanitizer Sanitizer
+		input     any
+		want      any
+	}{
+		{"trim", TrimSpace, "  ada  ", "ada"},
+		{"lower", ToLower, "Ada", "ada"},
+		{"upper", ToUpper, "Ada", "ADA"},
+		{"collapse", CollapseWhitespace, " a \t b\n\nc ", "a b c"},
+		{"truncate", Truncate(3), "héllo", "hél"},
+		{"truncate short", Truncate(10), "hi", "hi"},
+		{"strip html", StripHTML, `<p>Fish &amp; <b>chips</b></p><!-- x -->`, "Fish & chips"},
+		{"named string", ToUpper, label("env"), label("ENV")},
+		{"non-string", TrimSpace, 42, 42},
+		{"nil", TrimSpace, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.sanitizer.Sanitize(tt.input))
+		})
+	}
+}
+
+func TestField_Sanitizers(t *testing.T) {
+	email := NewField("email", "  Ada@Example.COM ", Email, Max(15)).
+		WithSanitizers(TrimSpace, ToLower)
+	name := NewField("name", "  Ada   Lovelace ", Required).WithSanitizers(CollapseWhitespace)
+
+	require.NoError(t, Validate(email, name), "validators see the sanitized value")
+	assert.Equal(t, "ada@example.com", email.Value)
+	assert.Equal(t, map[string]any{
+		"email": "ada@example.com",
+		"name":  "Ada Lovelace",
+	}, SanitizedValues(email, name))
+}
+
+func TestField_SanitizerOrder(t *testing.T) {
+	truncateFirst := NewField("code", "  abcdef", Required).WithSanitizers(Truncate(4), TrimSpace)
+	trimFirst := NewField("code", "  abcdef", Required).WithSanitizers(TrimSpace, Truncate(4))
+
+	require.NoError(t, Validate(truncateFirst, trimFirst))
+	assert.Equal(t, "ab", truncateFirst.Value)
+	assert.Equal(t, "abcd", trimFirst.Value)
+}
+
+func TestField_SanitizedBeforeRequired(t *testing.T) {
+	blank := NewField("name", "   ", Required).WithSanitizers(TrimSpace)
+	object := Nested("job", NewField("type", " Email ", In("email")).WithSanitizers(TrimSpace, ToLower))
+
+	failures, ok := ErrorsFrom(Validate(blank, object))
+	require.True(t, ok)
+	require.Len(t, failures, 1)
+	assert.Equal(t, "name", failures[0].Field)
+	assert.Equal(t, "email", SanitizedValues(object)["job.type"])
+}
+
+type sanitizedSignup struct {
+	Email    string  `json:"email" sanitize:"trim,lower" validate:"email"`
+	Nickname *string `json:"nickname" sanitize:"collapse,truncate=8" validate:"omitempty,max=8"`
+	Bio      string  `json:"bio" sanitize:"strip_html"`
+	Age      int     `json:"age" sanitize:"trim"`
+}
+
+func TestStruct_SanitizeTag(t *testing.T) {
+	nickname := "  the   great  ada "
+	signup := sanitizedSignup{
+		Email:    " ADA@example.com ",
+		Nickname: &nickname,
+		Bio:      "<em>hi</em>",
+		Age:      36,
+	}
+
+	require.NoError(t, New().Struct(&signup))
+	assert.Equal(t, "ada@example.com", signup.Email)
+	assert.Equal(t, "the grea", *signup.Nickname)
+	assert.Equal(t, "hi", signup.Bio)
+	assert.Equal(t, 36, signup.Age, "non-string fields are left alone")
+}
+
+func TestStruct_SanitizeTagNeedsSettableFields(t *testing.T) {
+	signup := sanitizedSignup{Email: " ADA@example.com "}
+
+	fields := fieldErrors(t, New().Struct(signup))
+	require.Len(t, fields, 1)
+	assert.Equal(t, "email", fields[0].Field, "a struct passed by value is validated as is")
+}
+
+func TestStruct_MalformedSanitizeTag(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{"unknown sanitizer", &struct {
+			A string `sanitize:"shout"`
+		}{}},
+		{"missing truncate length", &struct {
+			A string `sanitize:"truncate"`
+		}{}},
+		{"negative truncate length", &struct {
+			A string `sanitize:"truncate=-1"`
+		}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, errors.CodeConfiguration, errors.GetCode(New().Struct(tt.value)))
+		})
+	}
+}
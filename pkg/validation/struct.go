@@ -0,0 +1,600 @@
+package validation
+
+import (
+	stderrors "errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"task-queue/pkg/errors"
+)
+
+// Struct tags read by StructValidator
+const (
+	tagName        = "validate"
+	messageTagName = "validatemsg"
+)
+
+// StructValidator validates structs against their `validate` tags. Parsed
+// tags are cached per type, so an instance should be created once and reused.
+//
+// Supported tags:
+//
+//	required    value must be present, with the same semantics as Required
+//	omitempty   skip the remaining rules when the value is empty
+//	min=N       numbers must be >= N; strings, slices, and maps need N items
+//	max=N       numbers must be <= N; strings, slices, and maps allow N items
+//	email       string must be a valid email address
+//	url         string must be a valid URL
+//	uuid        string or uuid.UUID must be a valid UUID; uuid4 requires
+//	            version 4 and nonnil_uuid rejects the nil UUID
+//	oneof=a b   value must be one of the space-separated options
+//	future=D    time must be in the future, with an optional grace period D
+//	past        time must be in the past
+//	json        bytes or string must be well-formed JSON, likewise
+//	            json_object and json_array for the top-level kind
+//	max_bytes=N bytes or string must be at most N bytes long
+//	dive        apply the remaining rules to every slice, array, or map element
+//	eqfield=F   value must equal the sibling field F, likewise nefield,
+//	            gtfield, gtefield, ltfield, and ltefield (see FieldCompare)
+//
+// Any other tag names a validator added with Register. Tags are resolved
+// when a type is first validated, so register custom rules at startup.
+//
+// A companion `validatemsg` tag replaces the message of every failure of the
+// field, with the placeholders described in WithMessage.
+//
+// A `sanitize` tag normalizes string fields before their rules run, e.g.
+// `sanitize:"trim,lower"`. It accepts trim, lower, upper, collapse,
+// strip_html, and truncate=N, and applies only to settable fields, so pass a
+// pointer to have the sanitized values written back and validated.
+//
+// Nested structs and pointers to structs are validated recursively, and the
+// fields of embedded structs are validated as if declared on the parent.
+// Field paths use the json name when one is set, e.g. "schedule.cron" or
+// "tags[2]".
+type StructValidator struct {
+	cache sync.Map // reflect.Type -> *structRules
+}
+
+// New creates a StructValidator
+func New() *StructValidator {
+	return &StructValidator{}
+}
+
+// structRules are the parsed tags of a struct type
+type structRules struct {
+	fields []fieldRules
+	err    error
+}
+
+// fieldRules are the parsed tags of a single struct field
+type fieldRules struct {
+	index      int
+	name       string
+	inline     bool
+	rules      []tagRule
+	message    string
+	sanitizers []Sanitizer
+}
+
+// tagRule is a single parsed rule from a validate tag
+type tagRule struct {
+	tag    string
+	params map[string]any
+	check  func(reflect.Value) error
+
+	// field names the sibling compared by a cross-field rule, resolved into
+	// crossCheck by parseStruct
+	field      string
+	op         CompareOp
+	crossCheck func(value, parent reflect.Value) error
+}
+
+// evaluate runs the rule against value, a field or element of parent
+func (r tagRule) evaluate(value, parent reflect.Value) error {
+	if r.crossCheck != nil {
+		return r.crossCheck(value, parent)
+	}
+
+	return r.check(value)
+}
+
+// Struct validates s, which must be a struct or a pointer to one. Every
+// field stops at its first failing rule. Failures are returned as an
+// errors.Error with CodeValidation, recoverable with ErrorsFrom. Malformed tags yield a CodeConfiguration error.
+func (v *StructValidator) Struct(s any) error {
+	value := indirect(reflect.ValueOf(s))
+	if !value.IsValid() || value.Kind() != reflect.Struct {
+		return errors.Newf("validation: Struct expects a struct, got %T", s).
+			WithCode(errors.CodeInternal)
+	}
+
+	var validationErrors ValidationErrors
+	if err := v.validateStruct(value, "", &validationErrors); err != nil {
+		return err
+	}
+
+	return newValidationError(validationErrors)
+}
+
+// validateStruct validates the fields of value, prefixing their paths
+func (v *StructValidator) validateStruct(value reflect.Value, prefix string, errs *ValidationErrors) error {
+	parsed := v.rulesFor(value.Type())
+	if parsed.err != nil {
+		return parsed.err
+	}
+
+	for _, field := range parsed.fields {
+		fieldValue := value.Field(field.index)
+		if field.inline {
+			embedded := indirect(fieldValue)
+			if !embedded.IsValid() {
+				continue
+			}
+
+			if err := v.validateStruct(embedded, prefix, errs); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if len(field.sanitizers) > 0 {
+			sanitizeField(fieldValue, field.sanitizers)
+		}
+
+		path := joinPath(prefix, field.name)
+		if err := v.validateValue(value, fieldValue, path, field.rules, field.message, errs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateValue applies rules to value, a field or element of the struct
+// parent, in order, descending into nested structs when every rule passes. A
+// non-empty message replaces the rule's.
+func (v *StructValidator) validateValue(parent, value reflect.Value, path string, rules []tagRule, message string, errs *ValidationErrors) error {
+	for i, rule := range rules {
+		switch rule.tag {
+		case "omitempty":
+			if isEmpty(value) {
+				return nil
+			}
+
+		case "dive":
+			return v.validateElements(parent, value, path, rules[i+1:], message, errs)
+
+		default:
+			if err := rule.evaluate(value, parent); err != nil {
+				failure := ValidationError{
+					Field:   path,
+					Message: err.Error(),
+					Value:   interfaceOf(value),
+					Tag:     rule.tag,
+					Params:  rule.params,
+				}
+
+				// Built-in checks may report more detail, such as the unit
+				var re *ruleError
+				if stderrors.As(err, &re) && re.tag == rule.tag && re.params != nil {
+					failure.Params = re.params
+				}
+
+				if message != "" {
+					failure.Detail = failure.Message
+					failure.Message = renderMessage(message, failure.Value, failure.Params)
+				}
+
+				*errs = append(*errs, failure)
+				return nil
+			}
+		}
+	}
+
+	nested := indirect(value)
+	if nested.IsValid() && nested.Kind() == reflect.Struct {
+		return v.validateStruct(nested, path, errs)
+	}
+
+	return nil
+}
+
+// validateElements applies rules to every element of a slice, array, or map
+func (v *StructValidator) validateElements(parent, value reflect.Value, path string, rules []tagRule, message string, errs *ValidationErrors) error {
+	value = indirect(value)
+	if !value.IsValid() {
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			elemPath := indexPath(path, i)
+			if err := v.validateValue(parent, value.Index(i), elemPath, rules, message, errs); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			elemPath := keyPath(path, iter.Key().Interface())
+			if err := v.validateValue(parent, iter.Value(), elemPath, rules, message, errs); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return errors.Newf("validation: dive on %s requires a slice, array, or map, got %s",
+			path, value.Type()).WithCode(errors.CodeConfiguration)
+	}
+
+	return nil
+}
+
+// rulesFor returns the parsed rules of t, parsing and caching them on first use
+func (v *StructValidator) rulesFor(t reflect.Type) *structRules {
+	if cached, ok := v.cache.Load(t); ok {
+		return cached.(*structRules)
+	}
+
+	parsed := parseStruct(t)
+	actual, _ := v.cache.LoadOrStore(t, parsed)
+	return actual.(*structRules)
+}
+
+// parseStruct parses the validate tags of every field of t
+func parseStruct(t reflect.Type) *structRules {
+	parsed := &structRules{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get(tagName)
+		if tag == "-" {
+			continue
+		}
+
+		jsonName := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if sf.Anonymous && tag == "" && jsonName == "" && isStructType(sf.Type) {
+			parsed.fields = append(parsed.fields, fieldRules{index: i, inline: true})
+			continue
+		}
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		rules, err := parseTag(tag)
+		if err == nil {
+			err = resolveCrossFields(t, rules)
+		}
+
+		var sanitizers []Sanitizer
+		if err == nil {
+			sanitizers, err = parseSanitizeTag(sf.Tag.Get(sanitizeTagName))
+		}
+
+		if err != nil {
+			parsed.err = errors.Wrapf(err, "validation: invalid tag on %s.%s", t.Name(), sf.Name).
+				WithCode(errors.CodeConfiguration)
+
+			return parsed
+		}
+
+		parsed.fields = append(parsed.fields, fieldRules{
+			index:      i,
+			name:       fieldName(sf),
+			rules:      rules,
+			message:    sf.Tag.Get(messageTagName),
+			sanitizers: sanitizers,
+		})
+	}
+
+	return parsed
+}
+
+// parseTag parses a comma-separated validate tag into rules
+func parseTag(tag string) ([]tagRule, error) {
+	var rules []tagRule
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, param, hasParam := strings.Cut(part, "=")
+		rule := tagRule{tag: name}
+
+		switch name {
+		case "omitempty", "dive":
+			// Markers interpreted by validateValue
+
+		case "required":
+			rule.check = checkRequired
+
+		case "min", "max":
+			if !hasParam {
+				return nil, fmt.Errorf("%s requires a parameter", name)
+			}
+
+			bound, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s parameter %q is not a number", name, param)
+			}
+
+			rule.params = map[string]any{name: bound}
+			rule.check = tagBound(bound, name == "min")
+
+		case "email":
+			rule.check = checkString(Email)
+
+		case "url":
+			rule.check = checkString(URL)
+
+		case "uuid":
+			rule.check = checkValidator(UUID)
+
+		case "uuid4":
+			rule.check = checkValidator(UUIDv4())
+
+		case "nonnil_uuid":
+			rule.check = checkValidator(NonNilUUID())
+
+		case "future":
+			var grace time.Duration
+			if hasParam {
+				d, err := parseDuration(param)
+				if err != nil {
+					return nil, fmt.Errorf("future parameter %q is not a duration", param)
+				}
+
+				grace = d
+				rule.params = map[string]any{name: grace}
+			}
+
+			rule.check = checkValidator(Future(grace))
+
+		case "past":
+			rule.check = checkValidator(Past())
+
+		case "json":
+			rule.check = checkValidator(JSON)
+
+		case "json_object":
+			rule.check = checkValidator(JSONObject)
+
+		case "json_array":
+			rule.check = checkValidator(JSONArray)
+
+		case "max_bytes":
+			n, err := strconv.Atoi(param)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("max_bytes parameter %q is not a byte count", param)
+			}
+
+			rule.params = map[string]any{name: n}
+			rule.check = checkValidator(MaxBytes(n))
+
+		case "oneof":
+			options := strings.Fields(param)
+			if len(options) == 0 {
+				return nil, fmt.Errorf("oneof requires at least one option")
+			}
+
+			rule.params = map[string]any{name: options}
+			rule.check = checkOneOf(options)
+
+		default:
+			if registered, ok := lookupRegistered(name); ok {
+				if hasParam {
+					return nil, fmt.Errorf("registered rule %q does not take a parameter", name)
+				}
+
+				rule.check = checkValidator(registered)
+				break
+			}
+
+			op, ok := compareOpForTag(name)
+			if !ok {
+				return nil, fmt.Errorf("unknown rule %q", name)
+			}
+
+			if param == "" {
+				return nil, fmt.Errorf("%s requires a field name", name)
+			}
+
+			rule.field = param
+			rule.op = op
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// resolveCrossFields binds the cross-field rules of a field of t to the
+// sibling fields they reference
+func resolveCrossFields(t reflect.Type, rules []tagRule) error {
+	for i := range rules {
+		if rules[i].field == "" {
+			continue
+		}
+
+		other, ok := t.FieldByName(rules[i].field)
+		if !ok {
+			return fmt.Errorf("%s references unknown field %q", rules[i].tag, rules[i].field)
+		}
+
+		name := fieldName(other)
+		op := rules[i].op
+		rules[i].params = map[string]any{rules[i].tag: name}
+		rules[i].crossCheck = func(value, parent reflect.Value) error {
+			sibling, err := parent.FieldByIndexErr(other.Index)
+			if err != nil {
+				return nil
+			}
+
+			return compareField(op, value, sibling, name)
+		}
+	}
+
+	return nil
+}
+
+// compareOpForTag returns the operator of a cross-field rule tag
+func compareOpForTag(tag string) (CompareOp, bool) {
+	for op, name := range compareTags {
+		if name == tag {
+			return op, true
+		}
+	}
+
+	return 0, false
+}
+
+// fieldName returns the path segment of a struct field: its json name when
+// set, otherwise its Go name
+func fieldName(sf reflect.StructField) string {
+	name := strings.Split(sf.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return sf.Name
+	}
+
+	return name
+}
+
+// checkRequired fails when value is empty
+func checkRequired(value reflect.Value) error {
+	if isEmpty(value) {
+		return errRequired
+	}
+
+	return nil
+}
+
+// tagBound applies a min or max bound to present values
+func tagBound(bound float64, isMin bool) func(reflect.Value) error {
+	return func(value reflect.Value) error {
+		value = indirect(value)
+		if !value.IsValid() {
+			return nil
+		}
+
+		return checkBound(value, bound, isMin)
+	}
+}
+
+// checkString applies a string validator to string-kinded values
+func checkString(validator Validator) func(reflect.Value) error {
+	return func(value reflect.Value) error {
+		value = indirect(value)
+		if !value.IsValid() {
+			return nil
+		}
+
+		if value.Kind() != reflect.String {
+			return fmt.Errorf("must be a string")
+		}
+
+		return validator.Validate(value.String())
+	}
+}
+
+// checkOneOf compares the formatted value against options
+func checkOneOf(options []string) func(reflect.Value) error {
+	return func(value reflect.Value) error {
+		value = indirect(value)
+		if !value.IsValid() {
+			return nil
+		}
+
+		var formatted string
+		switch value.Kind() {
+		case reflect.String:
+			formatted = value.String()
+
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			formatted = strconv.FormatInt(value.Int(), 10)
+
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			formatted = strconv.FormatUint(value.Uint(), 10)
+
+		case reflect.Float32, reflect.Float64:
+			formatted = strconv.FormatFloat(value.Float(), 'g', -1, 64)
+
+		default:
+			return fmt.Errorf("cannot apply oneof validation to type %s", value.Type())
+		}
+
+		for _, option := range options {
+			if formatted == option {
+				return nil
+			}
+		}
+
+		return paramError("oneof", options, "must be one of %v", options)
+	}
+}
+
+// isEmpty reports whether value is missing. Like Required, numbers and
+// booleans are always present and blank strings are empty.
+func isEmpty(value reflect.Value) bool {
+	value = indirect(value)
+	if !value.IsValid() {
+		return true
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(value.String()) == ""
+
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+
+	case reflect.Array, reflect.Struct:
+		return value.IsZero()
+
+	default:
+		return false
+	}
+}
+
+// indirect dereferences pointers and interfaces, returning the zero Value for
+// nil
+func indirect(value reflect.Value) reflect.Value {
+	for value.IsValid() && (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+
+		value = value.Elem()
+	}
+
+	return value
+}
+
+// interfaceOf returns the dereferenced value for reporting, or nil when it is
+// missing or unexported
+func interfaceOf(value reflect.Value) any {
+	value = indirect(value)
+	if !value.IsValid() || !value.CanInterface() {
+		return nil
+	}
+
+	return value.Interface()
+}
+
+// isStructType reports whether t is a struct or a pointer to one
+func isStructType(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct
+}
@@ -0,0 +1,240 @@
+package validation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"task-queue/internal/models"
+	"task-queue/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fieldErrors extracts the ValidationErrors carried by a Struct error
+func fieldErrors(t *testing.T, err error) ValidationErrors {
+	t.Helper()
+
+	require.Error(t, err)
+	assert.True(t, errors.IsValidation(err))
+
+	fields, ok := ErrorsFrom(err)
+	require.True(t, ok, "error carries field failures")
+	return fields
+}
+
+// byPath indexes validation errors by field path
+func byPath(fields ValidationErrors) map[string]ValidationError {
+	indexed := make(map[string]ValidationError, len(fields))
+	for _, field := range fields {
+		indexed[field.Field] = field
+	}
+
+	return indexed
+}
+
+func TestStruct_JobRequest(t *testing.T) {
+	v := New()
+	retries := 3
+
+	valid := models.JobRequest{
+		Type:       "email",
+		Payload:    json.RawMessage(`{"to":"ada@example.com"}`),
+		MaxRetries: &retries,
+	}
+	assert.NoError(t, v.Struct(valid))
+	assert.NoError(t, v.Struct(&valid))
+
+	valid.MaxRetries = nil
+	assert.NoError(t, v.Struct(valid), "omitempty skips a nil pointer")
+
+	tooMany := 11
+	fields := byPath(fieldErrors(t, v.Struct(models.JobRequest{
+		Type:       "   ",
+		MaxRetries: &tooMany,
+	})))
+
+	require.Len(t, fields, 3)
+	assert.Equal(t, "required", fields["type"].Tag)
+	assert.Equal(t, "required", fields["payload"].Tag)
+	assert.Equal(t, "max", fields["max_retries"].Tag)
+	assert.Equal(t, map[string]any{"max": float64(10)}, fields["max_retries"].Params)
+	assert.Nil(t, fields["max_retries"].Value, "values are omitted by default")
+	assert.Equal(t, "must be at most 10", fields["max_retries"].Message)
+}
+
+type brokenAddress struct {
+	City string `json:"city" validate:"required"`
+	Zip  string `json:"zip" validate:"min=5,max=5"`
+}
+
+type brokenBase struct {
+	Owner string `json:"owner" validate:"email"`
+}
+
+type brokenStruct struct {
+	brokenBase
+
+	Name     string            `json:"name" validate:"required"`
+	Short    string            `json:"short" validate:"min=3"`
+	Long     string            `json:"long" validate:"max=4"`
+	Count    uint16            `validate:"min=1"`
+	Ratio    float32           `json:"ratio" validate:"max=1"`
+	Note     *string           `json:"note" validate:"omitempty,min=10"`
+	Homepage string            `json:"homepage" validate:"url"`
+	ID       string            `json:"id" validate:"uuid"`
+	Level    string            `json:"level" validate:"oneof=debug info warn"`
+	Priority int               `json:"priority" validate:"oneof=0 1 2"`
+	Tags     []string          `json:"tags" validate:"min=1,dive,required,max=3"`
+	Labels   map[string]string `json:"labels" validate:"dive,oneof=a b"`
+	Address  brokenAddress     `json:"address"`
+	Billing  *brokenAddress    `json:"billing"`
+	Skipped  string            `json:"skipped" validate:"-"`
+	internal string            `validate:"required"`
+}
+
+func TestStruct_EveryTag(t *testing.T) {
+	note := "short"
+	s := brokenStruct{
+		brokenBase: brokenBase{Owner: "not-an-email"},
+		Short:      "ab",
+		Long:       "toolong",
+		Ratio:      1.5,
+		Note:       &note,
+		Homepage:   "example.com",
+		ID:         "1234",
+		Level:      "trace",
+		Priority:   5,
+		Tags:       []string{"ok", "", "four"},
+		Labels:     map[string]string{"k": "c"},
+		Address:    brokenAddress{Zip: "123"},
+		Billing:    &brokenAddress{City: "Lima", Zip: "123456"},
+	}
+
+	fields := byPath(fieldErrors(t, New().Struct(s)))
+
+	tests := []struct {
+		path    string
+		tag     string
+		message string
+	}{
+		{"owner", "email", "must be a valid email address"},
+		{"name", "required", "is required"},
+		{"short", "min", "must be at least 3 characters"},
+		{"long", "max", "must be at most 4 characters"},
+		{"Count", "min", "must be at least 1"},
+		{"ratio", "max", "must be at most 1"},
+		{"note", "min", "must be at least 10 characters"},
+		{"homepage", "url", "must be a valid URL"},
+		{"id", "uuid", "must be a valid UUID"},
+		{"level", "oneof", "must be one of [debug info warn]"},
+		{"priority", "oneof", "must be one of [0 1 2]"},
+		{"tags[1]", "required", "is required"},
+		{"tags[2]", "max", "must be at most 3 characters"},
+		{"labels[k]", "oneof", "must be one of [a b]"},
+		{"address.city", "required", "is required"},
+		{"address.zip", "min", "must be at least 5 characters"},
+		{"billing.zip", "max", "must be at most 5 characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			field, ok := fields[tt.path]
+			require.True(t, ok, "missing failure for %s", tt.path)
+			assert.Equal(t, tt.tag, field.Tag)
+			assert.Equal(t, tt.message, field.Message)
+		})
+	}
+
+	assert.Len(t, fields, len(tests), "no unexpected failures")
+	assert.Equal(t, map[string]any{"oneof": []string{"debug", "info", "warn"}}, fields["level"].Params)
+
+	SetIncludeValues(true)
+	t.Cleanup(func() { SetIncludeValues(false) })
+
+	fields = byPath(fieldErrors(t, New().Struct(s)))
+	assert.Equal(t, "ab", fields["short"].Value)
+}
+
+func TestStruct_StopsAtFirstFailurePerField(t *testing.T) {
+	fields := fieldErrors(t, New().Struct(struct {
+		Tags []string `json:"tags" validate:"min=2,dive,required"`
+	}{Tags: []string{""}}))
+
+	require.Len(t, fields, 1)
+	assert.Equal(t, "tags", fields[0].Field)
+	assert.Equal(t, "must be at least 2 items", fields[0].Message)
+}
+
+func TestStruct_NilEmbeddedPointer(t *testing.T) {
+	type withPointer struct {
+		*brokenBase
+		Name string `json:"name" validate:"required"`
+	}
+
+	assert.NoError(t, New().Struct(withPointer{Name: "ok"}))
+
+	fields := fieldErrors(t, New().Struct(withPointer{
+		brokenBase: &brokenBase{Owner: "nope"},
+		Name:       "ok",
+	}))
+	require.Len(t, fields, 1)
+	assert.Equal(t, "owner", fields[0].Field)
+}
+
+func TestStruct_InvalidInput(t *testing.T) {
+	v := New()
+
+	err := v.Struct("not a struct")
+	require.Error(t, err)
+	assert.Equal(t, errors.CodeInternal, errors.GetCode(err))
+
+	var nilRequest *models.JobRequest
+	assert.Equal(t, errors.CodeInternal, errors.GetCode(v.Struct(nilRequest)))
+}
+
+func TestStruct_MalformedTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{"unknown rule", struct {
+			A string `validate:"shiny"`
+		}{}},
+		{"missing param", struct {
+			A string `validate:"min"`
+		}{}},
+		{"non-numeric param", struct {
+			A string `validate:"max=ten"`
+		}{}},
+		{"empty oneof", struct {
+			A string `validate:"oneof="`
+		}{}},
+		{"dive on scalar", struct {
+			A string `validate:"dive,required"`
+		}{A: "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New().Struct(tt.value)
+			require.Error(t, err)
+			assert.Equal(t, errors.CodeConfiguration, errors.GetCode(err))
+		})
+	}
+}
+
+func TestStruct_CachesParsedTags(t *testing.T) {
+	v := New()
+	req := models.JobRequest{Type: "email", Payload: json.RawMessage(`{}`)}
+
+	require.NoError(t, v.Struct(req))
+	require.NoError(t, v.Struct(&req))
+
+	count := 0
+	v.cache.Range(func(_, _ any) bool {
+		count++
+		return true
+	})
+	assert.Equal(t, 1, count, "struct and pointer share one cache entry")
+}
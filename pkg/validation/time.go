@@ -0,0 +1,248 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// clock returns the current time for the relative time validators
+var clock atomic.Pointer[func() time.Time]
+
+// SetClock replaces the source of the current time used by Future, Past,
+// and Within, mainly for tests. A nil now restores time.Now.
+func SetClock(now func() time.Time) {
+	if now == nil {
+		clock.Store(nil)
+		return
+	}
+
+	clock.Store(&now)
+}
+
+// currentTime returns the time according to the configured clock
+func currentTime() time.Time {
+	if now := clock.Load(); now != nil {
+		return (*now)()
+	}
+
+	return time.Now()
+}
+
+// Future returns a validator that checks a time is in the future. An
+// optional grace period also accepts times up to that long ago, absorbing
+// clock skew between clients and the server.
+func Future(grace ...time.Duration) Validator {
+	var allowance time.Duration
+	if len(grace) > 0 {
+		allowance = grace[0]
+	}
+
+	return timeValidator(func(t time.Time) error {
+		if !t.After(currentTime().Add(-allowance)) {
+			return paramError("future", allowance, "must be in the future")
+		}
+
+		return nil
+	})
+}
+
+// Past returns a validator that checks a time is in the past
+func Past() Validator {
+	return timeValidator(func(t time.Time) error {
+		if !t.Before(currentTime()) {
+			return &ruleError{tag: "past", message: "must be in the past"}
+		}
+
+		return nil
+	})
+}
+
+// Within returns a validator that checks a time lies between now+min and
+// now+max, inclusive. Negative offsets reach into the past.
+func Within(min, max time.Duration) Validator {
+	return timeValidator(func(t time.Time) error {
+		now := currentTime()
+		if t.Before(now.Add(min)) || t.After(now.Add(max)) {
+			return &ruleError{
+				tag:     "within",
+				params:  map[string]any{"min": min, "max": max},
+				message: fmt.Sprintf("must be between %s and %s from now", min, max),
+			}
+		}
+
+		return nil
+	})
+}
+
+// After returns a validator that checks a time is after bound
+func After(bound time.Time) Validator {
+	return timeValidator(func(t time.Time) error {
+		if !t.After(bound) {
+			return paramError("after", bound, "must be after %s", bound.Format(time.RFC3339))
+		}
+
+		return nil
+	})
+}
+
+// Before returns a validator that checks a time is before bound
+func Before(bound time.Time) Validator {
+	return timeValidator(func(t time.Time) error {
+		if !t.Before(bound) {
+			return paramError("before", bound, "must be before %s", bound.Format(time.RFC3339))
+		}
+
+		return nil
+	})
+}
+
+// DurationBetween returns a validator that checks a duration lies between
+// min and max, inclusive. It accepts time.Duration values and strings such as
+// "90s" or "7d", with the whole-day extension supported by the config
+// package.
+func DurationBetween(min, max time.Duration) Validator {
+	return ValidatorFunc(func(value any) error {
+		d, ok, err := toDuration(value)
+		if err != nil || !ok {
+			return err
+		}
+
+		if d < min || d > max {
+			return &ruleError{
+				tag:     "duration",
+				params:  map[string]any{"min": min, "max": max},
+				message: fmt.Sprintf("must be between %s and %s", min, max),
+			}
+		}
+
+		return nil
+	})
+}
+
+// timeValidator adapts check to the accepted time inputs. Missing values
+// pass, so presence is left to Required.
+func timeValidator(check func(time.Time) error) Validator {
+	return ValidatorFunc(func(value any) error {
+		t, ok, err := toTime(value)
+		if err != nil || !ok {
+			return err
+		}
+
+		return check(t)
+	})
+}
+
+// toTime converts time.Time, *time.Time, and RFC 3339 strings. ok is false
+// for nil and empty values.
+func toTime(value any) (time.Time, bool, error) {
+	switch v := value.(type) {
+	case nil:
+		return time.Time{}, false, nil
+
+	case time.Time:
+		return v, true, nil
+
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, false, nil
+		}
+
+		return *v, true, nil
+
+	case string:
+		if v == "" {
+			return time.Time{}, false, nil
+		}
+
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("must be an RFC 3339 timestamp")
+		}
+
+		return t, true, nil
+
+	default:
+		return time.Time{}, false, fmt.Errorf("must be a time, got %T", value)
+	}
+}
+
+// toDuration converts time.Duration values, pointers to them, and duration
+// strings. ok is false for nil and empty values.
+func toDuration(value any) (time.Duration, bool, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, false, nil
+
+	case time.Duration:
+		return v, true, nil
+
+	case *time.Duration:
+		if v == nil {
+			return 0, false, nil
+		}
+
+		return *v, true, nil
+
+	case string:
+		if v == "" {
+			return 0, false, nil
+		}
+
+		d, err := parseDuration(v)
+		if err != nil {
+			return 0, false, fmt.Errorf("must be a duration such as 90s or 7d")
+		}
+
+		return d, true, nil
+
+	default:
+		return 0, false, fmt.Errorf("must be a duration, got %T", value)
+	}
+}
+
+// dayDurationPattern matches durations with a leading whole-day component
+var dayDurationPattern = regexp.MustCompile(`^(\d+)d(.*)$`)
+
+// parseDuration parses durations like time.ParseDuration plus a leading
+// whole-day unit such as "7d" or "1d12h", matching config.ParseDuration.
+// The config package sits above this one, so it is not imported here.
+func parseDuration(s string) (time.Duration, error) {
+	matches := dayDurationPattern.FindStringSubmatch(s)
+	if matches == nil {
+		return time.ParseDuration(s)
+	}
+
+	days, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, err
+	}
+
+	total := time.Duration(days) * 24 * time.Hour
+	if matches[2] == "" {
+		return total, nil
+	}
+
+	rest, err := time.ParseDuration(matches[2])
+	if err != nil {
+		return 0, err
+	}
+
+	return total + rest, nil
+}
+
+// checkValidator applies a fluent validator to the dereferenced field value,
+// letting missing values pass
+func checkValidator(validator Validator) func(reflect.Value) error {
+	return func(value reflect.Value) error {
+		v := interfaceOf(value)
+		if v == nil {
+			return nil
+		}
+
+		return validator.Validate(v)
+	}
+}
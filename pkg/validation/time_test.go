@@ -0,0 +1,164 @@
+package validation
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"task-queue/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedNow is the fake current time used by the time validator tests
+var fixedNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// useFakeClock pins the validation clock to fixedNow for the test
+func useFakeClock(t *testing.T) {
+	t.Helper()
+
+	SetClock(func() time.Time { return fixedNow })
+	t.Cleanup(func() { SetClock(nil) })
+}
+
+// timePtr returns a pointer to t
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestTimeValidators_Boundaries(t *testing.T) {
+	useFakeClock(t)
+
+	tests := []struct {
+		name      string
+		validator Validator
+		value     any
+		wantErr   string
+	}{
+		{"future after now", Future(), fixedNow.Add(time.Nanosecond), ""},
+		{"future at now", Future(), fixedNow, "must be in the future"},
+		{"future within grace", Future(time.Minute), fixedNow.Add(-59 * time.Second), ""},
+		{"future at grace edge", Future(time.Minute), fixedNow.Add(-time.Minute), "must be in the future"},
+		{"past before now", Past(), fixedNow.Add(-time.Nanosecond), ""},
+		{"past at now", Past(), fixedNow, "must be in the past"},
+		{"within lower edge", Within(time.Hour, 2*time.Hour), fixedNow.Add(time.Hour), ""},
+		{"within upper edge", Within(time.Hour, 2*time.Hour), fixedNow.Add(2 * time.Hour), ""},
+		{"within too soon", Within(time.Hour, 2*time.Hour), fixedNow.Add(59 * time.Minute),
+			"must be between 1h0m0s and 2h0m0s from now"},
+		{"within too late", Within(time.Hour, 2*time.Hour), fixedNow.Add(2*time.Hour + time.Second),
+			"must be between 1h0m0s and 2h0m0s from now"},
+		{"within past window", Within(-time.Hour, 0), fixedNow.Add(-30 * time.Minute), ""},
+		{"after strict", After(fixedNow), fixedNow, "must be after 2026-03-01T12:00:00Z"},
+		{"after ok", After(fixedNow), fixedNow.Add(time.Second), ""},
+		{"before strict", Before(fixedNow), fixedNow, "must be before 2026-03-01T12:00:00Z"},
+		{"before ok", Before(fixedNow), fixedNow.Add(-time.Second), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validator.Validate(tt.value)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTimeValidators_Inputs(t *testing.T) {
+	useFakeClock(t)
+
+	var missing *time.Time
+	tests := []struct {
+		name    string
+		value   any
+		wantErr string
+	}{
+		{"pointer", timePtr(fixedNow.Add(time.Hour)), ""},
+		{"nil pointer passes", missing, ""},
+		{"nil passes", nil, ""},
+		{"empty string passes", "", ""},
+		{"rfc3339 string", "2026-03-01T13:00:00Z", ""},
+		{"rfc3339 string in past", "2026-03-01T11:00:00+00:00", "must be in the future"},
+		{"malformed string", "tomorrow", "must be an RFC 3339 timestamp"},
+		{"wrong type", 42, "must be a time, got int"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Future().Validate(tt.value)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+
+	var nilPointer *time.Time
+	assert.Error(t, Validate(NewField("scheduled_at", nilPointer, Required, Future())),
+		"nil fails when Required")
+}
+
+func TestDurationBetween(t *testing.T) {
+	validator := DurationBetween(time.Hour, 7*24*time.Hour)
+	tests := []struct {
+		name    string
+		value   any
+		wantErr string
+	}{
+		{"lower edge", time.Hour, ""},
+		{"upper edge string", "7d", ""},
+		{"days and hours", "1d12h", ""},
+		{"too short", "59m", "must be between 1h0m0s and 168h0m0s"},
+		{"too long", "7d1s", "must be between 1h0m0s and 168h0m0s"},
+		{"pointer", func() *time.Duration { d := 2 * time.Hour; return &d }(), ""},
+		{"empty passes", "", ""},
+		{"malformed", "7days", "must be a duration such as 90s or 7d"},
+		{"wrong type", 3600, "must be a duration, got int"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate(tt.value)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStruct_JobRequestScheduledAt(t *testing.T) {
+	useFakeClock(t)
+
+	req := models.JobRequest{
+		Type:        "email",
+		Payload:     json.RawMessage(`{}`),
+		ScheduledAt: timePtr(fixedNow.Add(-30 * time.Second)),
+	}
+	assert.NoError(t, New().Struct(req), "a minute of grace absorbs clock skew")
+
+	req.ScheduledAt = timePtr(fixedNow.Add(-2 * time.Minute))
+	fields := fieldErrors(t, New().Struct(req))
+	require.Len(t, fields, 1)
+	assert.Equal(t, "scheduled_at", fields[0].Field)
+	assert.Equal(t, "future", fields[0].Tag)
+	assert.Equal(t, map[string]any{"future": time.Minute}, fields[0].Params)
+}
+
+func TestStruct_PastTag(t *testing.T) {
+	useFakeClock(t)
+
+	type event struct {
+		OccurredAt time.Time `json:"occurred_at" validate:"past"`
+	}
+
+	assert.NoError(t, New().Struct(event{OccurredAt: fixedNow.Add(-time.Hour)}))
+	fields := fieldErrors(t, New().Struct(event{OccurredAt: fixedNow.Add(time.Hour)}))
+	require.Len(t, fields, 1)
+	assert.Equal(t, "must be in the past", fields[0].Message)
+}
@@ -0,0 +1,212 @@
+package validation
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+	"time"
+
+	"task-queue/pkg/errors"
+)
+
+// fallbackLocale is the locale used for keys missing from the requested one
+const fallbackLocale = "en"
+
+// Translator renders validation failures in other languages. Messages are
+// looked up by the failure's Tag, or by Tag and unit such as
+// "min.characters" when the failure has a "unit" param, and their {name}
+// placeholders are replaced by the failure's params. A regional locale such
+// as "es-MX" falls back to its language, and keys missing from a locale fall
+// back to English.
+type Translator struct {
+	mu      sync.RWMutex
+	catalog *errors.Catalog
+	locales map[string]bool
+}
+
+//go:embed locales/*.yaml
+var builtinLocales embed.FS
+
+// defaultTranslator serves ValidationErrors.Translate and problem responses
+var defaultTranslator = NewTranslator()
+
+func init() {
+	errors.SetFieldLocalizer(defaultTranslator.localizeFields)
+}
+
+// NewTranslator returns a Translator with the built-in English, Spanish, and
+// German catalogs
+func NewTranslator() *Translator {
+	locales, err := fs.Sub(builtinLocales, "locales")
+	if err != nil {
+		panic(fmt.Sprintf("validation: invalid builtin locales: %v", err))
+	}
+
+	catalog, err := errors.LoadCatalog(locales)
+	if err != nil {
+		panic(fmt.Sprintf("validation: invalid builtin locales: %v", err))
+	}
+
+	entries, err := fs.ReadDir(locales, ".")
+	if err != nil {
+		panic(fmt.Sprintf("validation: invalid builtin locales: %v", err))
+	}
+
+	t := &Translator{catalog: catalog, locales: make(map[string]bool, len(entries))}
+	for _, entry := range entries {
+		t.locales[strings.TrimSuffix(entry.Name(), ".yaml")] = true
+	}
+
+	return t
+}
+
+// Register adds messages for locale, replacing existing messages with the
+// same key. Keys are rule tags, optionally followed by a unit, e.g.
+// "required" or "max.characters".
+func (t *Translator) Register(locale string, messages map[string]string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.catalog.Add(locale, messages)
+	t.locales[strings.ToLower(locale)] = true
+}
+
+// Translate returns a copy of failures with their messages in locale.
+// Failures with a custom message, and failures whose key has no message in
+// locale or in English, keep their message.
+func (t *Translator) Translate(failures ValidationErrors, locale string) ValidationErrors {
+	if failures == nil {
+		return nil
+	}
+
+	translated := make(ValidationErrors, len(failures))
+	for i, failure := range failures {
+		if failure.Detail == "" {
+			if message, ok := t.message(locale, failure.Tag, failure.Params); ok {
+				failure.Message = message
+			}
+		}
+
+		translated[i] = failure
+	}
+
+	return translated
+}
+
+// RegisterLocale adds messages for locale to the default Translator used by
+// ValidationErrors.Translate and problem responses
+func RegisterLocale(locale string, messages map[string]string) {
+	defaultTranslator.Register(locale, messages)
+}
+
+// Translate returns a copy of ve with its messages in locale, using the
+// default Translator
+func (ve ValidationErrors) Translate(locale string) ValidationErrors {
+	return defaultTranslator.Translate(ve, locale)
+}
+
+// supports reports whether locale or its language has a catalog
+func (t *Translator) supports(locale string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, candidate := range localeCandidates(locale) {
+		if t.locales[candidate] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// localizeFields translates the field failures of a problem response. It
+// reports false for locales without a catalog, so the caller can try the
+// next preferred language.
+func (t *Translator) localizeFields(fields []errors.FieldError, locale string) ([]errors.FieldError, bool) {
+	if !t.supports(locale) {
+		return nil, false
+	}
+
+	localized := make([]errors.FieldError, len(fields))
+	for i, field := range fields {
+		if field.Detail == "" {
+			if message, ok := t.message(locale, field.Tag, field.Params); ok {
+				field.Message = message
+			}
+		}
+
+		localized[i] = field
+	}
+
+	return localized, true
+}
+
+// message renders the message for tag in locale, falling back to English
+func (t *Translator) message(locale, tag string, params map[string]any) (string, bool) {
+	if tag == "" {
+		return "", false
+	}
+
+	keys := []string{tag}
+	if unit, ok := params["unit"].(string); ok && unit != "" {
+		keys = []string{tag + "." + unit, tag}
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, candidate := range append(localeCandidates(locale), fallbackLocale) {
+		for _, key := range keys {
+			if template, ok := t.catalog.Translate(candidate, key); ok {
+				return renderMessage(template, nil, formatParams(params)), true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// formatParams renders params for interpolation: lists are joined with
+// commas and times use RFC 3339
+func formatParams(params map[string]any) map[string]any {
+	formatted := make(map[string]any, len(params))
+	for name, param := range params {
+		switch p := param.(type) {
+		case []string:
+			formatted[name] = strings.Join(p, ", ")
+
+		case []any:
+			items := make([]string, len(p))
+			for i, item := range p {
+				items[i] = fmt.Sprint(item)
+			}
+
+			formatted[name] = strings.Join(items, ", ")
+
+		case time.Time:
+			formatted[name] = p.Format(time.RFC3339)
+
+		default:
+			formatted[name] = param
+		}
+	}
+
+	return formatted
+}
+
+// localeCandidates returns locale followed by its language, e.g. "pt-br"
+// then "pt"
+func localeCandidates(locale string) []string {
+	locale = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	if locale == "" {
+		return nil
+	}
+
+	if language, _, ok := strings.Cut(locale, "-"); ok {
+		return []string{locale, language}
+	}
+
+	return []string{locale}
+}
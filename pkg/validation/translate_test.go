@@ -0,0 +1,143 @@
+package validation
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-queue/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// representativeFailures covers required, unit-specific bounds, list params,
+// and a rule without params
+func representativeFailures(t *testing.T) ValidationErrors {
+	t.Helper()
+
+	failures, ok := ErrorsFrom(ValidateAll(
+		NewField("name", "", Required),
+		NewField("password", "abc", Min(8)),
+		NewField("tags", []string{"a", "b", "c"}, MaxItems(2)),
+		NewField("level", "trace", OneOfStrings("debug", "info")),
+		NewField("email", "nope", Email),
+		NewField("retries", 20, Max(10)),
+	))
+	require.True(t, ok)
+	require.Len(t, failures, 6)
+	return failures
+}
+
+func TestTranslate_Locales(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   []string
+	}{
+		{"en", []string{
+			"is required",
+			"must be at least 8 characters",
+			"must contain at most 2 items",
+			"must be one of debug, info",
+			"must be a valid email address",
+			"must be at most 10",
+		}},
+		{"es", []string{
+			"es obligatorio",
+			"debe tener al menos 8 caracteres",
+			"debe contener como máximo 2 elementos",
+			"debe ser uno de debug, info",
+			"debe ser una dirección de correo electrónico válida",
+			"debe ser como máximo 10",
+		}},
+		{"de", []string{
+			"ist erforderlich",
+			"muss mindestens 8 Zeichen lang sein",
+			"darf höchstens 2 Einträge enthalten",
+			"muss einer der Werte debug, info sein",
+			"muss eine gültige E-Mail-Adresse sein",
+			"darf höchstens 10 sein",
+		}},
+	}
+
+	failures := representativeFailures(t)
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			translated := failures.Translate(tt.locale)
+			require.Len(t, translated, len(tt.want))
+			for i, message := range tt.want {
+				assert.Equal(t, message, translated[i].Message)
+				assert.Equal(t, failures[i].Field, translated[i].Field)
+				assert.Equal(t, failures[i].Tag, translated[i].Tag)
+			}
+		})
+	}
+
+	assert.Equal(t, "is required", failures[0].Message, "the original is not modified")
+}
+
+func TestTranslate_ParamSubstitution(t *testing.T) {
+	failures, ok := ErrorsFrom(ValidateAll(
+		NewField("code", "abcdef", Length(2, 4)),
+		NewField("items", []int{1, 1}, UniqueItems),
+		NewField("id", "f47ac10b-58cc-3372-a567-0e02b2c3d479", UUIDVersion(4)),
+	))
+	require.True(t, ok)
+
+	translated := failures.Translate("es")
+	assert.Equal(t, "debe tener entre 2 y 4 caracteres", translated[0].Message)
+	assert.Equal(t, "debe contener elementos únicos, [1] repite [0]", translated[1].Message)
+	assert.Equal(t, "debe ser un UUID de la versión 4", translated[2].Message)
+}
+
+func TestTranslate_Fallbacks(t *testing.T) {
+	failures := ValidationErrors{
+		{Field: "name", Message: "is required", Tag: "required"},
+		{Field: "custom", Message: "Pick a name", Detail: "is required", Tag: "required"},
+		{Field: "tenant", Message: "must be a known tenant", Tag: "tenant"},
+		{Field: "raw", Message: "must be a string"},
+	}
+
+	assert.Equal(t, "es obligatorio", failures.Translate("es-MX")[0].Message, "region falls back to language")
+	assert.Equal(t, "is required", failures.Translate("fr")[0].Message, "unknown locale falls back to English")
+
+	translated := failures.Translate("de")
+	assert.Equal(t, "Pick a name", translated[1].Message, "custom messages are kept")
+	assert.Equal(t, "must be a known tenant", translated[2].Message, "unknown keys are kept")
+	assert.Equal(t, "must be a string", translated[3].Message)
+}
+
+func TestTranslator_Register(t *testing.T) {
+	translator := NewTranslator()
+	translator.Register("fr", map[string]string{
+		"required":       "est obligatoire",
+		"min.characters": "doit contenir au moins {min} caractères",
+	})
+
+	failures := representativeFailures(t)
+	translated := translator.Translate(failures, "fr")
+	assert.Equal(t, "est obligatoire", translated[0].Message)
+	assert.Equal(t, "doit contenir au moins 8 caractères", translated[1].Message)
+	assert.Equal(t, "must contain at most 2 items", translated[2].Message, "missing keys fall back to English")
+
+	assert.Equal(t, "is required", failures.Translate("fr")[0].Message, "the default translator is unchanged")
+}
+
+func TestTranslate_Problem(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/jobs", nil)
+	req.Header.Set("Accept-Language", "fr;q=0.9, de-CH")
+	rec := httptest.NewRecorder()
+
+	errors.WriteProblem(rec, Validate(NewField("type", "", Required)), req)
+
+	assert.Equal(t, "de-CH", rec.Header().Get("Content-Language"))
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &decoded))
+	assert.Equal(t, []any{map[string]any{
+		"field":   "type",
+		"message": "ist erforderlich",
+		"tag":     "required",
+	}}, decoded["errors"])
+}
@@ -13,23 +13,30 @@ func (f ValidatorFunc) Validate(value any) error {
 	return f(value)
 }
 
-// Rule represents a validation rule with a custom message
+// Rule represents a validation rule with a custom message. It implements
+// Validator, so rules and bare validators can be mixed in a Field.
 type Rule struct {
 	Validator Validator
 	Message   string
 }
 
-// Field represents a field to be validated
+// Field represents a field to be validated. Sanitizers run before the
+// validators and replace Value with their result.
 type Field struct {
 	Name       string
 	Value      any
 	Validators []Validator
+	Sanitizers []Sanitizer
+
+	// nested holds the member fields of an object created by Nested
+	nested []*Field
 }
 
 // ValidationError represents a validation error with field details
 type ValidationError struct {
 	Field   string         `json:"field"`
 	Message string         `json:"message"`
+	Detail  string         `json:"detail,omitempty"`
 	Value   any            `json:"value,omitempty"`
 	Tag     string         `json:"tag,omitempty"`
 	Params  map[string]any `json:"params,omitempty"`
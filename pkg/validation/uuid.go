@@ -0,0 +1,86 @@
+package validation
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// UUID validates that a value is a UUID: a uuid.UUID, a [16]byte, or a
+// string in any form accepted by uuid.Parse, including upper case and the
+// 32-digit form without hyphens
+var UUID = ValidatorFunc(func(value any) error {
+	_, err := toUUID(value)
+	return err
+})
+
+// UUIDVersion returns a validator that checks a value is a UUID of version n
+func UUIDVersion(n int) Validator {
+	return ValidatorFunc(func(value any) error {
+		id, err := toUUID(value)
+		if err != nil {
+			return err
+		}
+
+		if int(id.Version()) != n {
+			return paramError("uuid_version", n, "must be a version %d UUID", n)
+		}
+
+		return nil
+	})
+}
+
+// UUIDv4 returns a validator that checks a value is a random, version 4 UUID
+func UUIDv4() Validator {
+	return UUIDVersion(4)
+}
+
+// NonNilUUID returns a validator that checks a value is a UUID other than
+// uuid.Nil. Like Required, the nil UUID counts as missing.
+func NonNilUUID() Validator {
+	return ValidatorFunc(func(value any) error {
+		id, err := toUUID(value)
+		if err != nil {
+			return err
+		}
+
+		if id == uuid.Nil {
+			return errRequired
+		}
+
+		return nil
+	})
+}
+
+// errInvalidUUID is the failure reported for malformed UUIDs
+var errInvalidUUID = &ruleError{tag: "uuid", message: "must be a valid UUID"}
+
+// toUUID converts the accepted UUID representations
+func toUUID(value any) (uuid.UUID, error) {
+	switch v := value.(type) {
+	case uuid.UUID:
+		return v, nil
+
+	case *uuid.UUID:
+		if v == nil {
+			return uuid.Nil, errInvalidUUID
+		}
+
+		return *v, nil
+
+	case [16]byte:
+		return uuid.UUID(v), nil
+	}
+
+	str, ok := stringKind(value)
+	if !ok {
+		return uuid.Nil, fmt.Errorf("must be a UUID, got %T", value)
+	}
+
+	id, err := uuid.Parse(str)
+	if err != nil {
+		return uuid.Nil, errInvalidUUID
+	}
+
+	return id, nil
+}
@@ -0,0 +1,91 @@
+package validation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"task-queue/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUUID_Representations(t *testing.T) {
+	id := uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
+
+	tests := []struct {
+		name    string
+		value   any
+		wantErr string
+	}{
+		{"uuid value", id, ""},
+		{"uuid pointer", &id, ""},
+		{"nil uuid value", uuid.Nil, ""},
+		{"byte array", [16]byte(id), ""},
+		{"hyphenated string", id.String(), ""},
+		{"upper case string", "550E8400-E29B-41D4-A716-446655440000", ""},
+		{"without hyphens", "550e8400e29b41d4a716446655440000", ""},
+		{"truncated", "550e8400-e29b-41d4-a716", "must be a valid UUID"},
+		{"nil pointer", (*uuid.UUID)(nil), "must be a valid UUID"},
+		{"wrong type", 42, "must be a UUID, got int"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := UUID.Validate(tt.value)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUUIDVersion(t *testing.T) {
+	assert.NoError(t, UUIDv4().Validate(uuid.New()))
+	assert.NoError(t, UUIDv4().Validate("550E8400E29B41D4A716446655440000"))
+
+	v1 := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	assert.EqualError(t, UUIDv4().Validate(v1), "must be a version 4 UUID")
+	assert.NoError(t, UUIDVersion(1).Validate(v1))
+	assert.EqualError(t, UUIDv4().Validate(uuid.Nil), "must be a version 4 UUID")
+}
+
+func TestNonNilUUID(t *testing.T) {
+	assert.NoError(t, NonNilUUID().Validate(uuid.New()))
+	assert.EqualError(t, NonNilUUID().Validate(uuid.Nil), "is required")
+	assert.EqualError(t, NonNilUUID().Validate("00000000-0000-0000-0000-000000000000"), "is required")
+	assert.EqualError(t, NonNilUUID().Validate("nope"), "must be a valid UUID")
+
+	assert.Error(t, Required.Validate(uuid.Nil), "Required agrees that the nil UUID is missing")
+}
+
+func TestStruct_JobID(t *testing.T) {
+	job := models.NewJob("email", json.RawMessage(`{}`), models.JobPriorityNormal)
+	assert.NoError(t, New().Struct(job))
+
+	job.ID = uuid.Nil
+	fields := fieldErrors(t, New().Struct(job))
+	require.Len(t, fields, 1)
+	assert.Equal(t, "id", fields[0].Field)
+	assert.Equal(t, "nonnil_uuid", fields[0].Tag)
+	assert.Equal(t, "is required", fields[0].Message)
+}
+
+func TestStruct_UUIDTags(t *testing.T) {
+	type ref struct {
+		Parent uuid.UUID `json:"parent" validate:"uuid"`
+		Token  string    `json:"token" validate:"uuid4"`
+	}
+
+	assert.NoError(t, New().Struct(ref{Parent: uuid.New(), Token: uuid.NewString()}))
+
+	fields := byPath(fieldErrors(t, New().Struct(ref{
+		Parent: uuid.New(),
+		Token:  "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+	})))
+	require.Len(t, fields, 1)
+	assert.Equal(t, "must be a version 4 UUID", fields["token"].Message)
+}
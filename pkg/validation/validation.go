@@ -2,8 +2,12 @@ package validation
 
 import (
 	"fmt"
+	"reflect"
 	"regexp"
 	"strings"
+	"time"
+	"unicode/utf8"
+
 	"task-queue/pkg/errors"
 )
 
@@ -21,30 +25,73 @@ func (ve ValidationErrors) Error() string {
 	return fmt.Sprintf("validation failed: %s", strings.Join(msgs, "; "))
 }
 
-// Validate validates multiple fields
+// ByField groups the errors by field name, keeping their order within each
+// field
+func (ve ValidationErrors) ByField() map[string][]ValidationError {
+	grouped := make(map[string][]ValidationError)
+	for _, e := range ve {
+		grouped[e.Field] = append(grouped[e.Field], e)
+	}
+
+	return grouped
+}
+
+// Validate validates multiple fields. Each field stops at its first failing
+// validator, so it reports at most one error per field; use ValidateAll to
+// collect every failure.
 func Validate(fields ...*Field) error {
 	var validationErrors ValidationErrors
+	validateFields("", fields, true, &validationErrors)
+	return newValidationError(validationErrors)
+}
+
+// ValidateAll validates multiple fields, running every validator of every
+// field and reporting all of their failures
+func ValidateAll(fields ...*Field) error {
+	var validationErrors ValidationErrors
+	validateFields("", fields, false, &validationErrors)
+	return newValidationError(validationErrors)
+}
+
+// validateFields sanitizes fields and runs their validators under the path
+// prefix, stopping at the first failure of each field when failFast is set.
+// Members of nested objects are validated after the object's own validators
+// pass.
+func validateFields(prefix string, fields []*Field, failFast bool, errs *ValidationErrors) {
 	for _, field := range fields {
+		path := joinPath(prefix, field.Name)
+		if len(field.Sanitizers) > 0 {
+			field.Value = sanitize(field.Value, field.Sanitizers)
+		}
+
+		failed := false
 		for _, validator := range field.Validators {
 			if err := validator.Validate(field.Value); err != nil {
-				validationErrors = append(validationErrors, ValidationError{
-					Field:   field.Name,
-					Message: err.Error(),
-					Value:   field.Value,
-				})
-
-				break
+				*errs = appendFailure(*errs, path, field.Value, err)
+				failed = true
+				if failFast {
+					break
+				}
 			}
 		}
+
+		if !failed {
+			validateFields(path, field.nested, failFast, errs)
+		}
 	}
+}
 
-	if len(validationErrors) > 0 {
-		return errors.New(validationErrors.Error()).
-			WithCode(errors.CodeValidation).
-			WithMetadata("fields", validationErrors)
+// newValidationError converts validation failures into an errors.Error with
+// CodeValidation, carrying them in their wire shape under errors.FieldsKey,
+// or returns nil when there are none
+func newValidationError(validationErrors ValidationErrors) error {
+	if len(validationErrors) == 0 {
+		return nil
 	}
 
-	return nil
+	return errors.New(validationErrors.Error()).
+		WithCode(errors.CodeValidation).
+		WithMetadata(errors.FieldsKey, toFieldErrors(validationErrors))
 }
 
 // NewField creates a new field for validation
@@ -56,23 +103,36 @@ func NewField(name string, value any, validators ...Validator) *Field {
 	}
 }
 
+// Nested groups fields under an object named name, so their failures are
+// reported with dotted paths such as "schedule.cron". Nested objects can be
+// nested again, and an empty name merges the members into the parent.
+func Nested(name string, fields ...*Field) *Field {
+	return &Field{
+		Name:   name,
+		nested: fields,
+	}
+}
+
 // Common validators
 
+// errRequired is the failure reported by Required
+var errRequired = &ruleError{tag: "required", message: "is required"}
+
 // Required validates that a value is not empty
 var Required = ValidatorFunc(func(value any) error {
 	if value == nil {
-		return fmt.Errorf("is required")
+		return errRequired
 	}
 
 	switch v := value.(type) {
 	case string:
 		if strings.TrimSpace(v) == "" {
-			return fmt.Errorf("is required")
+			return errRequired
 		}
 
 	case []byte:
 		if len(v) == 0 {
-			return fmt.Errorf("is required")
+			return errRequired
 		}
 
 	// Numeric and boolean types are always considered present
@@ -80,83 +140,133 @@ var Required = ValidatorFunc(func(value any) error {
 	case uint, uint8, uint16, uint32, uint64:
 	case float32, float64:
 	case bool:
+	// Nil pointers, empty collections, and zero structs are missing
 	default:
-		if fmt.Sprintf("%v", v) == "" {
-			return fmt.Errorf("is required")
+		if isEmpty(reflect.ValueOf(v)) || fmt.Sprintf("%v", v) == "" {
+			return errRequired
 		}
 	}
 
 	return nil
 })
 
-// Min returns a validator that checks if a value is at least min
+// Min returns a validator that checks if a value is at least min. Numbers of
+// every integer and float width compare by value, and time.Duration values
+// compare in nanoseconds, so Min(float64(time.Second)) requires at least one
+// second. Strings, byte slices, and collections compare by length in bytes or
+// items.
 func Min(min float64) Validator {
 	return ValidatorFunc(func(value any) error {
-		switch v := value.(type) {
-		case int:
-			if float64(v) < min {
-				return fmt.Errorf("must be at least %v", min)
-			}
+		return checkBound(indirect(reflect.ValueOf(value)), min, true)
+	})
+}
 
-		case int64:
-			if float64(v) < min {
-				return fmt.Errorf("must be at least %v", min)
-			}
+// Max returns a validator that checks if a value is at most max, with the
+// same type handling as Min
+func Max(max float64) Validator {
+	return ValidatorFunc(func(value any) error {
+		return checkBound(indirect(reflect.ValueOf(value)), max, false)
+	})
+}
 
-		case float64:
-			if v < min {
-				return fmt.Errorf("must be at least %v", min)
-			}
+// durationType is the reflect type of time.Duration
+var durationType = reflect.TypeOf(time.Duration(0))
 
-		case string:
-			if float64(len(v)) < min {
-				return fmt.Errorf("must be at least %v characters", min)
-			}
+// checkBound compares numbers by value and strings, slices, arrays, and maps
+// by length against a min or max bound
+func checkBound(value reflect.Value, bound float64, isMin bool) error {
+	tag := "max"
+	if isMin {
+		tag = "min"
+	}
 
-		case []byte:
-			if float64(len(v)) < min {
-				return fmt.Errorf("must be at least %v bytes", min)
-			}
+	if !value.IsValid() {
+		return fmt.Errorf("cannot apply %s validation to a nil value", tag)
+	}
 
-		default:
-			return fmt.Errorf("cannot apply min validation to type %T", value)
+	var (
+		actual float64
+		unit   string
+		shown  any = bound
+	)
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		actual = toFloat(value)
+		if value.Type() == durationType {
+			shown = time.Duration(bound)
 		}
 
+	case reflect.String:
+		actual, unit = float64(value.Len()), "characters"
+
+	case reflect.Slice, reflect.Array, reflect.Map:
+		actual, unit = float64(value.Len()), collectionUnit(value)
+
+	default:
+		return fmt.Errorf("cannot apply %s validation to type %s", tag, value.Type())
+	}
+
+	if (isMin && actual >= bound) || (!isMin && actual <= bound) {
 		return nil
-	})
+	}
+
+	message := fmt.Sprintf("must be at most %v", shown)
+	if isMin {
+		message = fmt.Sprintf("must be at least %v", shown)
+	}
+
+	params := map[string]any{tag: shown}
+	if unit != "" {
+		message += " " + unit
+		params["unit"] = unit
+	}
+
+	return &ruleError{tag: tag, params: params, message: message}
 }
 
-// Max returns a validator that checks if a value is at most max
-func Max(max float64) Validator {
-	return ValidatorFunc(func(value any) error {
-		switch v := value.(type) {
-		case int:
-			if float64(v) > max {
-				return fmt.Errorf("must be at most %v", max)
-			}
+// collectionUnit names what the length of a slice, array, or map counts:
+// bytes for byte slices and arrays, items otherwise
+func collectionUnit(value reflect.Value) string {
+	if value.Kind() != reflect.Map && value.Type().Elem().Kind() == reflect.Uint8 {
+		return "bytes"
+	}
 
-		case int64:
-			if float64(v) > max {
-				return fmt.Errorf("must be at most %v", max)
-			}
+	return "items"
+}
 
-		case float64:
-			if v > max {
-				return fmt.Errorf("must be at most %v", max)
-			}
+// Length returns a validator that checks a string has between min and max
+// characters, or a byte slice or collection between min and max bytes or
+// items. Unlike Min and Max it never treats the value as a number, and it
+// counts strings in characters rather than bytes.
+func Length(min, max int) Validator {
+	return ValidatorFunc(func(value any) error {
+		v := indirect(reflect.ValueOf(value))
+		if !v.IsValid() {
+			return fmt.Errorf("cannot apply length validation to a nil value")
+		}
 
-		case string:
-			if float64(len(v)) > max {
-				return fmt.Errorf("must be at most %v characters", max)
-			}
+		var length int
+		var unit string
+		switch v.Kind() {
+		case reflect.String:
+			length, unit = utf8.RuneCountInString(v.String()), "characters"
 
-		case []byte:
-			if float64(len(v)) > max {
-				return fmt.Errorf("must be at most %v bytes", max)
-			}
+		case reflect.Slice, reflect.Array, reflect.Map:
+			length, unit = v.Len(), collectionUnit(v)
 
 		default:
-			return fmt.Errorf("cannot apply max validation to type %T", value)
+			return fmt.Errorf("cannot apply length validation to type %s", v.Type())
+		}
+
+		if length < min || length > max {
+			return &ruleError{
+				tag:     "length",
+				params:  map[string]any{"min": min, "max": max, "unit": unit},
+				message: fmt.Sprintf("must be between %d and %d %s", min, max, unit),
+			}
 		}
 
 		return nil
@@ -184,7 +294,7 @@ var Email = ValidatorFunc(func(value any) error {
 	emailRegex := regexp.MustCompile(
 		`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
 	if !emailRegex.MatchString(str) {
-		return fmt.Errorf("must be a valid email address")
+		return &ruleError{tag: "email", message: "must be a valid email address"}
 	}
 
 	return nil
@@ -199,23 +309,7 @@ var URL = ValidatorFunc(func(value any) error {
 
 	urlRegex := regexp.MustCompile(`^(https?|ftp)://[^\s/$.?#].[^\s]*$`)
 	if !urlRegex.MatchString(str) {
-		return fmt.Errorf("must be a valid URL")
-	}
-
-	return nil
-})
-
-// UUID validates that a string is a valid UUID
-var UUID = ValidatorFunc(func(value any) error {
-	str, ok := value.(string)
-	if !ok {
-		return fmt.Errorf("must be a string")
-	}
-
-	uuidRegex := regexp.MustCompile(
-		`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
-	if !uuidRegex.MatchString(strings.ToLower(str)) {
-		return fmt.Errorf("must be a valid UUID")
+		return &ruleError{tag: "url", message: "must be a valid URL"}
 	}
 
 	return nil
@@ -230,7 +324,7 @@ func In(values ...any) Validator {
 			}
 		}
 
-		return fmt.Errorf("must be one of %v", values)
+		return paramError("oneof", values, "must be one of %v", values)
 	})
 }
 
@@ -244,7 +338,7 @@ func Pattern(pattern string) Validator {
 		}
 
 		if !regex.MatchString(str) {
-			return fmt.Errorf("must match pattern %s", pattern)
+			return paramError("pattern", pattern, "must match pattern %s", pattern)
 		}
 
 		return nil
@@ -260,20 +354,21 @@ func JobType() Validator {
 		}
 
 		if len(str) < 1 || len(str) > 100 {
-			return fmt.Errorf("must be between 1 and 100 characters")
+			return &ruleError{
+				tag:     "length",
+				params:  map[string]any{"min": 1, "max": 100, "unit": "characters"},
+				message: "must be between 1 and 100 characters",
+			}
 		}
 
 		validPattern := regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 		if !validPattern.MatchString(str) {
-			return fmt.Errorf(
-				"can only contain letters, numbers, underscore, and hyphen")
+			return &ruleError{
+				tag:     "job_type",
+				message: "can only contain letters, numbers, underscore, and hyphen",
+			}
 		}
 
 		return nil
 	})
 }
-
-// Custom validator for job priority
-func JobPriority() Validator {
-	return In(0, 1, 2, 3)
-}
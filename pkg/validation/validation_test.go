@@ -4,7 +4,10 @@ import (
 	"strings"
 	"testing"
 
+	"task-queue/pkg/errors"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRequired(t *testing.T) {
@@ -23,6 +26,8 @@ func TestRequired(t *testing.T) {
 		{"non-empty slice", []byte{1, 2}, false},
 		{"bool false", false, false},
 		{"bool true", true, false},
+		{"nil pointer", (*string)(nil), true},
+		{"empty map", map[string]any{}, true},
 	}
 
 	for _, tt := range tests {
@@ -251,3 +256,60 @@ func TestValidate(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "validation failed")
 }
+
+func TestValidate_StopsAtFirstFailurePerField(t *testing.T) {
+	err := Validate(
+		NewField("name", "a!", Min(3), Pattern(`^[a-z]+$`)),
+		NewField("age", 150, Max(100)),
+	)
+	require.Error(t, err)
+
+	fields, ok := ErrorsFrom(err)
+	require.True(t, ok)
+	require.Len(t, fields, 2)
+	assert.Equal(t, "name", fields[0].Field)
+	assert.Equal(t, "must be at least 3 characters", fields[0].Message)
+	assert.Equal(t, "age", fields[1].Field)
+}
+
+func TestValidateAll(t *testing.T) {
+	assert.NoError(t, ValidateAll(NewField("name", "ada", Min(3), Pattern(`^[a-z]+$`))))
+
+	err := ValidateAll(
+		NewField("name", "a!", Min(3), Pattern(`^[a-z]+$`)),
+		NewField("age", 150, Min(18), Max(100)),
+	)
+	require.Error(t, err)
+	assert.True(t, errors.IsValidation(err))
+
+	fields, ok := ErrorsFrom(err)
+	require.True(t, ok, "error carries the structured slice")
+	require.Len(t, fields, 3)
+
+	grouped := fields.ByField()
+	require.Len(t, grouped["name"], 2)
+	assert.Equal(t, "must be at least 3 characters", grouped["name"][0].Message)
+	assert.Equal(t, "must match pattern ^[a-z]+$", grouped["name"][1].Message)
+	require.Len(t, grouped["age"], 1)
+	assert.Equal(t, "must be at most 100", grouped["age"][0].Message)
+
+	assert.Equal(t,
+		"validation failed: name: must be at least 3 characters; "+
+			"name: must match pattern ^[a-z]+$; age: must be at most 100",
+		err.Error())
+}
+
+func TestValidationErrors_ByField(t *testing.T) {
+	assert.Empty(t, ValidationErrors(nil).ByField())
+
+	grouped := ValidationErrors{
+		{Field: "a", Message: "first"},
+		{Field: "b", Message: "only"},
+		{Field: "a", Message: "second"},
+	}.ByField()
+
+	assert.Equal(t, map[string][]ValidationError{
+		"a": {{Field: "a", Message: "first"}, {Field: "a", Message: "second"}},
+		"b": {{Field: "b", Message: "only"}},
+	}, grouped)
+}